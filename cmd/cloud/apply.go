@@ -0,0 +1,90 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package main
+
+import (
+	"os"
+
+	"github.com/mattermost/mattermost-cloud/cmd/cloud/manifest"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func newCmdApply() *cobra.Command {
+	var flags applyFlags
+
+	cmd := &cobra.Command{
+		Use:   "apply",
+		Short: "Apply manifests of installations, groups, webhooks, subscriptions and cluster annotations.",
+		RunE: func(command *cobra.Command, args []string) error {
+			command.SilenceUsage = true
+			client := createClient(command.Context(), flags.clusterFlags)
+
+			plan, err := planManifests(client, flags.manifestFlags)
+			if err != nil {
+				return err
+			}
+			if plan.IsEmpty() {
+				plan.Print(os.Stdout)
+				return nil
+			}
+
+			return plan.Apply(os.Stdout)
+		},
+		PreRun: func(cmd *cobra.Command, args []string) {
+			flags.clusterFlags.addFlags(cmd)
+		},
+	}
+
+	flags.addFlags(cmd)
+
+	return cmd
+}
+
+func newCmdDiff() *cobra.Command {
+	var flags diffFlags
+
+	cmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Show changes that applying the manifests would make.",
+		RunE: func(command *cobra.Command, args []string) error {
+			command.SilenceUsage = true
+			client := createClient(command.Context(), flags.clusterFlags)
+
+			plan, err := planManifests(client, flags.manifestFlags)
+			if err != nil {
+				return err
+			}
+			plan.Print(os.Stdout)
+
+			if flags.exitCode && !plan.IsEmpty() {
+				return errors.New("server differs from the manifests")
+			}
+			return nil
+		},
+		PreRun: func(cmd *cobra.Command, args []string) {
+			flags.clusterFlags.addFlags(cmd)
+		},
+	}
+
+	flags.addFlags(cmd)
+
+	return cmd
+}
+
+func planManifests(client *model.Client, flags manifestFlags) (*manifest.Plan, error) {
+	manifests, err := manifest.Load(flags.files)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load manifests")
+	}
+
+	plan, err := manifest.NewPlan(client, manifests, manifest.Options{Prune: flags.prune})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to compare manifests with the server")
+	}
+
+	return plan, nil
+}
@@ -0,0 +1,40 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+type manifestFlags struct {
+	files []string
+	prune bool
+}
+
+func (flags *manifestFlags) addFlags(command *cobra.Command) {
+	command.Flags().StringSliceVarP(&flags.files, "filename", "f", nil, "Manifest files or directories with manifests to apply. Directories are read recursively.")
+	command.Flags().BoolVar(&flags.prune, "prune", false, "Delete resources which are not declared in the manifests. Only kinds present in the manifests are pruned, and installations, webhooks and subscriptions only of the owners present in the manifests.")
+	_ = command.MarkFlagRequired("filename")
+}
+
+type applyFlags struct {
+	clusterFlags
+	manifestFlags
+}
+
+func (flags *applyFlags) addFlags(command *cobra.Command) {
+	flags.manifestFlags.addFlags(command)
+}
+
+type diffFlags struct {
+	clusterFlags
+	manifestFlags
+	exitCode bool
+}
+
+func (flags *diffFlags) addFlags(command *cobra.Command) {
+	flags.manifestFlags.addFlags(command)
+	command.Flags().BoolVar(&flags.exitCode, "exit-code", false, "Exit with an error when the server differs from the manifests.")
+}
@@ -6,6 +6,8 @@ package main
 
 import (
 	"context"
+	"io"
+	"os"
 
 	"github.com/mattermost/mattermost-cloud/model"
 	"github.com/pkg/errors"
@@ -79,39 +81,24 @@ func executeInstallationBackupListCmd(ctx context.Context, flags installationBac
 
 	paging := getPaging(flags.pagingFlags)
 
-	request := &model.GetInstallationBackupsRequest{
-		InstallationID: flags.installationID,
-		State:          flags.state,
-		Paging:         paging,
-	}
-
-	backups, err := client.GetInstallationBackups(request)
-	if err != nil {
-		return errors.Wrap(err, "failed to get backup")
-	}
-
-	if enabled, customCols := getTableOutputOption(flags.tableOptions); enabled {
-		var keys []string
-		var vals [][]string
+	return watchOutput(ctx, os.Stdout, flags.watchOptions, func(w io.Writer) error {
+		request := &model.GetInstallationBackupsRequest{
+			InstallationID: flags.installationID,
+			State:          flags.state,
+			Paging:         paging,
+		}
 
-		if len(customCols) > 0 {
-			data := make([]interface{}, 0, len(backups))
-			for _, elem := range backups {
-				data = append(data, elem)
-			}
-			keys, vals, err = prepareTableData(customCols, data)
-			if err != nil {
-				return errors.Wrap(err, "failed to prepare table output")
-			}
-		} else {
-			keys, vals = defaultBackupTableData(backups)
+		backups, err := client.GetInstallationBackups(request)
+		if err != nil {
+			return errors.Wrap(err, "failed to get backup")
 		}
 
-		printTable(keys, vals)
-		return nil
-	}
+		return backupPrinter.printList(w, flags.tableOptions, backups)
+	})
+}
 
-	return printJSON(backups)
+var backupPrinter = resourcePrinter[*model.InstallationBackup]{
+	defaultTable: defaultBackupTableData,
 }
 
 func defaultBackupTableData(backups []*model.InstallationBackup) ([]string, [][]string) {
@@ -142,12 +129,14 @@ func newCmdInstallationBackupGet() *cobra.Command {
 
 			client := createClient(command.Context(), flags.clusterFlags)
 
-			backup, err := client.GetInstallationBackup(flags.backupID)
-			if err != nil {
-				return errors.Wrap(err, "failed to get backup")
-			}
+			return watchOutput(command.Context(), os.Stdout, flags.watchOptions, func(w io.Writer) error {
+				backup, err := client.GetInstallationBackup(flags.backupID)
+				if err != nil {
+					return errors.Wrap(err, "failed to get backup")
+				}
 
-			return printJSON(backup)
+				return backupPrinter.printObject(w, flags.tableOptions, backup)
+			})
 		},
 		PreRun: func(cmd *cobra.Command, args []string) {
 			flags.clusterFlags.addFlags(cmd)
@@ -20,6 +20,7 @@ type installationBackupListFlags struct {
 	clusterFlags
 	pagingFlags
 	tableOptions
+	watchOptions
 	installationID string
 	state          string
 }
@@ -29,14 +30,19 @@ func (flags *installationBackupListFlags) addFlags(command *cobra.Command) {
 	command.Flags().StringVar(&flags.state, "state", "", "The state to filter backups by.")
 	flags.pagingFlags.addFlags(command)
 	flags.tableOptions.addFlags(command)
+	flags.watchOptions.addFlags(command)
 }
 
 type installationBackupGetFlags struct {
 	clusterFlags
+	tableOptions
+	watchOptions
 	backupID string
 }
 
 func (flags *installationBackupGetFlags) addFlags(command *cobra.Command) {
+	flags.tableOptions.addFlags(command)
+	flags.watchOptions.addFlags(command)
 	command.Flags().StringVar(&flags.backupID, "backup", "", "The id of the backup to get.")
 	_ = command.MarkFlagRequired("backup")
 }
@@ -6,8 +6,8 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 
@@ -41,12 +41,14 @@ func newCmdCluster() *cobra.Command {
 	cmd.AddCommand(newCmdClusterProvision())
 	cmd.AddCommand(newCmdClusterUpdate())
 	cmd.AddCommand(newCmdClusterUpgrade())
+	cmd.AddCommand(newCmdClusterUpgrades())
 	cmd.AddCommand(newCmdClusterResize())
 	cmd.AddCommand(newCmdClusterNodegroup())
 	cmd.AddCommand(newCmdClusterDelete())
 	cmd.AddCommand(newCmdClusterGet())
 	cmd.AddCommand(newCmdClusterList())
 	cmd.AddCommand(newCmdClusterUtilities())
+	cmd.AddCommand(newCmdClusterTemplate())
 
 	cmd.AddCommand(newCmdClusterSizeDictionary())
 	cmd.AddCommand(newCmdClusterShowStateReport())
@@ -58,9 +60,7 @@ func newCmdCluster() *cobra.Command {
 }
 
 func printJSON(data interface{}) error {
-	encoder := json.NewEncoder(os.Stdout)
-	encoder.SetIndent("", "    ")
-	return encoder.Encode(data)
+	return writeJSON(os.Stdout, data)
 }
 
 // getRotatorConfigFromFlags creates a new RotatorConfig with the flags provided to the command
@@ -88,6 +88,7 @@ func newCmdClusterCreate() *cobra.Command {
 		},
 		PreRun: func(cmd *cobra.Command, args []string) {
 			flags.clusterFlags.addFlags(cmd)
+			flags.createRequestChanges.addFlags(cmd)
 			flags.pgBouncerConfigChanges.addFlags(cmd)
 		},
 	}
@@ -120,6 +121,19 @@ func executeClusterCreateCmd(ctx context.Context, flags clusterCreateFlags) erro
 		Provisioner:            model.ProvisionerKops,
 		ArgocdClusterRegister:  flags.argocdRegister,
 		PgBouncerConfig:        flags.GetPgBouncerConfig(),
+		Template:               flags.template,
+	}
+
+	if len(flags.template) != 0 {
+		template, err := client.GetClusterTemplate(flags.template)
+		if err != nil {
+			return errors.Wrap(err, "failed to get cluster template")
+		}
+		if template == nil {
+			return errors.Errorf("cluster template %s not found", flags.template)
+		}
+		// Flag defaults would otherwise mask the values of the template.
+		applyTemplateFlagDefaults(request, flags, template)
 	}
 
 	if flags.useEKS {
@@ -154,12 +168,14 @@ func executeClusterCreateCmd(ctx context.Context, flags clusterCreateFlags) erro
 		}
 	}
 
-	err := clusterdictionary.ApplyToCreateClusterRequest(flags.size, request)
-	if err != nil {
-		return errors.Wrap(err, "failed to apply size values")
+	if len(flags.template) == 0 || flags.sizeChanged {
+		err := clusterdictionary.ApplyToCreateClusterRequest(flags.size, request)
+		if err != nil {
+			return errors.Wrap(err, "failed to apply size values")
+		}
 	}
 
-	err = clusterdictionary.AddToCreateClusterRequest(flags.additionalNodegroups, request)
+	err := clusterdictionary.AddToCreateClusterRequest(flags.additionalNodegroups, request)
 	if err != nil {
 		return errors.Wrap(err, "failed to apply size values for additional nodegroups")
 	}
@@ -203,6 +219,66 @@ func executeClusterCreateCmd(ctx context.Context, flags clusterCreateFlags) erro
 	return nil
 }
 
+// applyTemplateFlagDefaults clears the values of the request that were set
+// from flag defaults rather than explicitly, so that they are taken from the
+// cluster template instead. Flags explicitly set to false are listed as
+// template overrides. Nested values are sent in full, so the values of the
+// template are filled in for the nested fields that were not set.
+func applyTemplateFlagDefaults(request *model.CreateClusterRequest, flags clusterCreateFlags, template *model.ClusterTemplate) {
+	spec := template.Spec
+	if spec == nil {
+		spec = &model.ClusterTemplateSpec{}
+	}
+
+	if !flags.providerChanged {
+		request.Provider = ""
+	}
+	if !flags.versionChanged {
+		request.Version = ""
+	}
+	if !flags.zonesChanged {
+		request.Zones = nil
+	}
+	if !flags.allowInstallationsChanged {
+		request.AllowInstallations = false
+	} else if !request.AllowInstallations {
+		request.TemplateOverrides = append(request.TemplateOverrides, "allow-installations")
+	}
+	if !flags.networkingChanged {
+		request.Networking = ""
+	}
+	if !flags.useEKS {
+		request.Provisioner = ""
+	}
+
+	request.PgBouncerConfig = nil
+	patch := flags.GetPatchPgBouncerConfig()
+	if *patch != (model.PatchPgBouncerConfig{}) {
+		request.PgBouncerConfig = &model.PgBouncerConfig{}
+		if spec.PgBouncerConfig != nil {
+			*request.PgBouncerConfig = *spec.PgBouncerConfig
+		}
+		request.PgBouncerConfig.ApplyPatch(patch)
+	}
+
+	for utility, version := range request.DesiredUtilityVersions {
+		if version == nil || (version.Chart == "" && version.ValuesPath == "") {
+			delete(request.DesiredUtilityVersions, utility)
+			continue
+		}
+		templateVersion := spec.DesiredUtilityVersions[utility]
+		if templateVersion == nil {
+			continue
+		}
+		if version.Chart == "" {
+			version.Chart = templateVersion.Chart
+		}
+		if version.ValuesPath == "" {
+			version.ValuesPath = templateVersion.ValuesPath
+		}
+	}
+}
+
 func newCmdClusterImport() *cobra.Command {
 	var flags clusterImportFlags
 
@@ -364,6 +440,7 @@ func executeClusterUpgradeCmd(ctx context.Context, flags clusterUpgradeFlags) er
 
 	request := &model.PatchUpgradeClusterRequest{
 		RotatorConfig: &rotatorConfig,
+		SkipPreflight: flags.skipPreflight,
 	}
 
 	if flags.isVersionChanged {
@@ -516,18 +593,20 @@ func newCmdClusterGet() *cobra.Command {
 func executeClusterGetCmd(ctx context.Context, flags clusterGetFlags) error {
 	client := createClient(ctx, flags.clusterFlags)
 
-	cluster, err := client.GetCluster(flags.cluster)
-	if err != nil {
-		return errors.Wrap(err, "failed to query cluster")
-	}
-	if cluster == nil {
-		return nil
-	}
+	return watchOutput(ctx, os.Stdout, flags.watchOptions, func(w io.Writer) error {
+		cluster, err := client.GetCluster(flags.cluster)
+		if err != nil {
+			return errors.Wrap(err, "failed to query cluster")
+		}
+		if cluster == nil {
+			return nil
+		}
 
-	if err = printJSON(cluster); err != nil {
-		return errors.Wrap(err, "failed to print cluster response")
-	}
-	return nil
+		if err = newClusterPrinter(false).printObject(w, flags.tableOptions, cluster); err != nil {
+			return errors.Wrap(err, "failed to print cluster response")
+		}
+		return nil
+	})
 }
 
 func newCmdClusterList() *cobra.Command {
@@ -554,43 +633,34 @@ func executeClusterListCmd(ctx context.Context, flags clusterListFlags) error {
 
 	paging := getPaging(flags.pagingFlags)
 
-	clusters, err := client.GetClusters(&model.GetClustersRequest{
-		Paging: paging,
-	})
-	if err != nil {
-		return errors.Wrap(err, "failed to query clusters")
-	}
-
-	if enabled, customCols := getTableOutputOption(flags.tableOptions); enabled {
-		var keys []string
-		var vals [][]string
-
-		if len(customCols) > 0 {
-			data := make([]interface{}, 0, len(clusters))
-			for _, cluster := range clusters {
-				data = append(data, cluster)
-			}
-			keys, vals, err = prepareTableData(customCols, data)
-			if err != nil {
-				return errors.Wrap(err, "failed to prepare table output")
-			}
-		} else {
-			keys, vals = defaultClustersTableData(clusters)
+	return watchOutput(ctx, os.Stdout, flags.watchOptions, func(w io.Writer) error {
+		clusters, err := client.GetClusters(&model.GetClustersRequest{
+			Paging: paging,
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to query clusters")
 		}
 
-		if flags.showTags {
-			keys, vals = enhanceTableWithAnnotations(clusters, keys, vals)
+		if err = newClusterPrinter(flags.showTags).printList(w, flags.tableOptions, clusters); err != nil {
+			return errors.Wrap(err, "failed to print cluster response")
 		}
-
-		printTable(keys, vals)
 		return nil
-	}
+	})
+}
 
-	if err = printJSON(clusters); err != nil {
-		return errors.Wrap(err, "failed to print cluster response")
+func newClusterPrinter(showTags bool) resourcePrinter[*model.ClusterDTO] {
+	printer := resourcePrinter[*model.ClusterDTO]{
+		defaultTable: defaultClustersTableData,
+		wideTable:    wideClustersTableData,
+	}
+	if showTags {
+		printer.defaultTable = func(clusters []*model.ClusterDTO) ([]string, [][]string) {
+			keys, vals := defaultClustersTableData(clusters)
+			return enhanceTableWithAnnotations(clusters, keys, vals)
+		}
 	}
 
-	return nil
+	return printer
 }
 
 func defaultClustersTableData(clusters []*model.ClusterDTO) ([]string, [][]string) {
@@ -642,6 +712,16 @@ func defaultClustersTableData(clusters []*model.ClusterDTO) ([]string, [][]strin
 	return keys, values
 }
 
+func wideClustersTableData(clusters []*model.ClusterDTO) ([]string, [][]string) {
+	keys, vals := defaultClustersTableData(clusters)
+	keys = append(keys, "PROVISIONER", "PROVIDER", "CREATED")
+	for i, cluster := range clusters {
+		vals[i] = append(vals[i], cluster.Provisioner, cluster.Provider, model.DateStringFromMillis(cluster.CreateAt))
+	}
+
+	return enhanceTableWithAnnotations(clusters, keys, vals)
+}
+
 func enhanceTableWithAnnotations(clusters []*model.ClusterDTO, keys []string, vals [][]string) ([]string, [][]string) {
 	var tags [][]string
 	for _, cluster := range clusters {
@@ -683,6 +763,12 @@ func newCmdClusterUtilities() *cobra.Command {
 	}
 	flags.addFlags(cmd)
 
+	cmd.AddCommand(newCmdClusterUtilityRegistry())
+	cmd.AddCommand(newCmdClusterUtilityAdd())
+	cmd.AddCommand(newCmdClusterUtilityRemove())
+	cmd.AddCommand(newCmdClusterUtilityDrift())
+	cmd.AddCommand(newCmdClusterUtilityRollout())
+
 	return cmd
 }
 
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"time"
+
 	"github.com/mattermost/mattermost-cloud/model"
 	"github.com/spf13/cobra"
 )
@@ -36,6 +38,7 @@ type createRequestOptions struct {
 	additionalNodegroups        map[string]string
 	nodegroupsWithPublicSubnet  []string
 	nodegroupsWithSecurityGroup []string
+	template                    string
 }
 
 func (flags *createRequestOptions) addFlags(command *cobra.Command) {
@@ -54,6 +57,25 @@ func (flags *createRequestOptions) addFlags(command *cobra.Command) {
 	command.Flags().StringToStringVar(&flags.additionalNodegroups, "additional-nodegroups", nil, "Additional nodegroups to create. The key is the name of the nodegroup and the value is the size constant.")
 	command.Flags().StringSliceVar(&flags.nodegroupsWithPublicSubnet, "nodegroups-with-public-subnet", nil, "Nodegroups to create with public subnet. The value is the name of the nodegroup.")
 	command.Flags().StringSliceVar(&flags.nodegroupsWithSecurityGroup, "nodegroups-with-sg", nil, "Nodegroups to create with dedicated security group. The value is the name of the nodegroup.")
+	command.Flags().StringVar(&flags.template, "template", "", "The name of the cluster template to create the cluster from. Only explicitly set flags override the template values.")
+}
+
+type createRequestChanges struct {
+	providerChanged           bool
+	versionChanged            bool
+	zonesChanged              bool
+	allowInstallationsChanged bool
+	networkingChanged         bool
+	sizeChanged               bool
+}
+
+func (flags *createRequestChanges) addFlags(command *cobra.Command) {
+	flags.providerChanged = command.Flags().Changed("provider")
+	flags.versionChanged = command.Flags().Changed("version")
+	flags.zonesChanged = command.Flags().Changed("zones")
+	flags.allowInstallationsChanged = command.Flags().Changed("allow-installations")
+	flags.networkingChanged = command.Flags().Changed("networking")
+	flags.sizeChanged = command.Flags().Changed("size")
 }
 
 type utilityFlags struct {
@@ -137,6 +159,7 @@ func (flags *sizeOptions) addFlags(command *cobra.Command) {
 type clusterCreateFlags struct {
 	clusterFlags
 	createRequestOptions
+	createRequestChanges
 	utilityFlags
 	sizeOptions
 	pgBouncerConfigOptions
@@ -366,6 +389,7 @@ type clusterUpgradeFlags struct {
 	ami            string
 	maxPodsPerNode int64
 	kmsKeyId       string
+	skipPreflight  bool
 }
 
 func (flags *clusterUpgradeFlags) addFlags(command *cobra.Command) {
@@ -376,6 +400,7 @@ func (flags *clusterUpgradeFlags) addFlags(command *cobra.Command) {
 	command.Flags().StringVar(&flags.ami, "ami", "", "The AMI Name to use for the cluster hosts. Note: AMI ID is still supported for backwards compatibility, but fails in cases you have ARM nodegroups.")
 	command.Flags().Int64Var(&flags.maxPodsPerNode, "max-pods-per-node", 0, "The maximum number of pods that can run on a single worker node.")
 	command.Flags().StringVar(&flags.kmsKeyId, "kms-key-id", "", "Custom KMS key for enterprise customers.")
+	command.Flags().BoolVar(&flags.skipPreflight, "skip-preflight", false, "Skip the checks for deprecated APIs, blocking pod disruption budgets, utility compatibility and node capacity run before the upgrade.")
 
 	_ = command.MarkFlagRequired("cluster")
 }
@@ -418,10 +443,14 @@ func (flags *clusterDeleteFlags) addFlags(command *cobra.Command) {
 
 type clusterGetFlags struct {
 	clusterFlags
+	tableOptions
+	watchOptions
 	cluster string
 }
 
 func (flags *clusterGetFlags) addFlags(command *cobra.Command) {
+	flags.tableOptions.addFlags(command)
+	flags.watchOptions.addFlags(command)
 	command.Flags().StringVar(&flags.cluster, "cluster", "", "The id of the cluster to be fetched.")
 	_ = command.MarkFlagRequired("cluster")
 }
@@ -441,23 +470,37 @@ func (flags *pagingFlags) addFlags(command *cobra.Command) {
 type tableOptions struct {
 	outputToTable bool
 	customCols    []string
+	output        string
 }
 
 func (flags *tableOptions) addFlags(command *cobra.Command) {
 	command.Flags().BoolVar(&flags.outputToTable, "table", false, "Whether to display the returned output list as a table or not.")
 	command.Flags().StringSliceVar(&flags.customCols, "custom-columns", []string{}, "Custom columns for table output specified with jsonpath in form <column_name>:<jsonpath>. Example: --custom-columns=ID:.ID,State:.State,VPC:.ProvisionerMetadataKops.VPC")
+	command.Flags().StringVarP(&flags.output, "output", "o", "", "Output format. One of: json, yaml, csv, table, wide, name (IDs only), custom-columns=<spec>, go-template=<template> or jsonpath=<expression>.")
+}
+
+type watchOptions struct {
+	watch         bool
+	watchInterval time.Duration
+}
+
+func (flags *watchOptions) addFlags(command *cobra.Command) {
+	command.Flags().BoolVarP(&flags.watch, "watch", "w", false, "Whether to keep polling and print the output again whenever it changes.")
+	command.Flags().DurationVar(&flags.watchInterval, "watch-interval", 5*time.Second, "The polling interval when watching.")
 }
 
 type clusterListFlags struct {
 	clusterFlags
 	pagingFlags
 	tableOptions
+	watchOptions
 	showTags bool
 }
 
 func (flags *clusterListFlags) addFlags(command *cobra.Command) {
 	flags.pagingFlags.addFlags(command)
 	flags.tableOptions.addFlags(command)
+	flags.watchOptions.addFlags(command)
 	command.Flags().BoolVar(&flags.showTags, "show-tags", false, "When printing, show all tags as the last column")
 }
 
@@ -7,6 +7,7 @@ package main
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"time"
@@ -45,15 +46,17 @@ func newCmdClusterInstallationGet() *cobra.Command {
 
 			client := createClient(command.Context(), flags.clusterFlags)
 
-			clusterInstallation, err := client.GetClusterInstallation(flags.clusterInstallationID)
-			if err != nil {
-				return errors.Wrap(err, "failed to query cluster installation")
-			}
-			if clusterInstallation == nil {
-				return nil
-			}
+			return watchOutput(command.Context(), os.Stdout, flags.watchOptions, func(w io.Writer) error {
+				clusterInstallation, err := client.GetClusterInstallation(flags.clusterInstallationID)
+				if err != nil {
+					return errors.Wrap(err, "failed to query cluster installation")
+				}
+				if clusterInstallation == nil {
+					return nil
+				}
 
-			return printJSON(clusterInstallation)
+				return clusterInstallationPrinter.printObject(w, flags.tableOptions, clusterInstallation)
+			})
 		},
 		PreRun: func(cmd *cobra.Command, args []string) {
 			flags.clusterFlags.addFlags(cmd)
@@ -89,37 +92,22 @@ func executeClusterInstallationListCmd(ctx context.Context, flags clusterInstall
 
 	paging := getPaging(flags.pagingFlags)
 
-	clusterInstallations, err := client.GetClusterInstallations(&model.GetClusterInstallationsRequest{
-		ClusterID:      flags.cluster,
-		InstallationID: flags.installation,
-		Paging:         paging,
-	})
-	if err != nil {
-		return errors.Wrap(err, "failed to query cluster installations")
-	}
-
-	if enabled, customCols := getTableOutputOption(flags.tableOptions); enabled {
-		var keys []string
-		var vals [][]string
-
-		if len(customCols) > 0 {
-			data := make([]interface{}, 0, len(clusterInstallations))
-			for _, inst := range clusterInstallations {
-				data = append(data, inst)
-			}
-			keys, vals, err = prepareTableData(customCols, data)
-			if err != nil {
-				return errors.Wrap(err, "failed to prepare table output")
-			}
-		} else {
-			keys, vals = defaultClusterInstallationTableData(clusterInstallations)
+	return watchOutput(ctx, os.Stdout, flags.watchOptions, func(w io.Writer) error {
+		clusterInstallations, err := client.GetClusterInstallations(&model.GetClusterInstallationsRequest{
+			ClusterID:      flags.cluster,
+			InstallationID: flags.installation,
+			Paging:         paging,
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to query cluster installations")
 		}
 
-		printTable(keys, vals)
-		return nil
-	}
+		return clusterInstallationPrinter.printList(w, flags.tableOptions, clusterInstallations)
+	})
+}
 
-	return printJSON(clusterInstallations)
+var clusterInstallationPrinter = resourcePrinter[*model.ClusterInstallation]{
+	defaultTable: defaultClusterInstallationTableData,
 }
 
 func defaultClusterInstallationTableData(cis []*model.ClusterInstallation) ([]string, [][]string) {
@@ -8,10 +8,14 @@ import "github.com/spf13/cobra"
 
 type clusterInstallationGetFlags struct {
 	clusterFlags
+	tableOptions
+	watchOptions
 	clusterInstallationID string
 }
 
 func (flags *clusterInstallationGetFlags) addFlags(command *cobra.Command) {
+	flags.tableOptions.addFlags(command)
+	flags.watchOptions.addFlags(command)
 	command.Flags().StringVar(&flags.clusterInstallationID, "cluster-installation", "", "The id of the cluster installation to be fetched.")
 	_ = command.MarkFlagRequired("cluster-installation")
 }
@@ -20,6 +24,7 @@ type clusterInstallationListFlags struct {
 	clusterFlags
 	pagingFlags
 	tableOptions
+	watchOptions
 	cluster      string
 	installation string
 }
@@ -27,6 +32,7 @@ type clusterInstallationListFlags struct {
 func (flags *clusterInstallationListFlags) addFlags(command *cobra.Command) {
 	flags.pagingFlags.addFlags(command)
 	flags.tableOptions.addFlags(command)
+	flags.watchOptions.addFlags(command)
 
 	command.Flags().StringVar(&flags.cluster, "cluster", "", "The cluster by which to filter cluster installations.")
 	command.Flags().StringVar(&flags.installation, "installation", "", "The installation by which to filter cluster installations.")
@@ -0,0 +1,284 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func newCmdClusterTemplate() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "template",
+		Short: "Manage cluster templates used for repeatable cluster creation.",
+	}
+
+	cmd.AddCommand(newCmdClusterTemplateCreate())
+	cmd.AddCommand(newCmdClusterTemplateGet())
+	cmd.AddCommand(newCmdClusterTemplateList())
+	cmd.AddCommand(newCmdClusterTemplateUpdate())
+	cmd.AddCommand(newCmdClusterTemplateDelete())
+	cmd.AddCommand(newCmdClusterTemplateClusters())
+
+	return cmd
+}
+
+func newCmdClusterTemplateCreate() *cobra.Command {
+	var flags clusterTemplateCreateFlags
+
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a cluster template.",
+		RunE: func(command *cobra.Command, args []string) error {
+			command.SilenceUsage = true
+			client := createClient(command.Context(), flags.clusterFlags)
+
+			spec, err := readClusterTemplateSpec(flags.specFile)
+			if err != nil {
+				return err
+			}
+
+			request := &model.CreateClusterTemplateRequest{
+				Name:        flags.name,
+				Description: flags.description,
+				Spec:        spec,
+			}
+
+			if flags.dryRun {
+				return runDryRun(request)
+			}
+
+			template, err := client.CreateClusterTemplate(request)
+			if err != nil {
+				return errors.Wrap(err, "failed to create cluster template")
+			}
+
+			return printJSON(template)
+		},
+		PreRun: func(cmd *cobra.Command, args []string) {
+			flags.clusterFlags.addFlags(cmd)
+		},
+	}
+	flags.addFlags(cmd)
+
+	return cmd
+}
+
+func newCmdClusterTemplateGet() *cobra.Command {
+	var flags clusterTemplateFlags
+
+	cmd := &cobra.Command{
+		Use:   "get",
+		Short: "Get a particular cluster template.",
+		RunE: func(command *cobra.Command, args []string) error {
+			command.SilenceUsage = true
+			client := createClient(command.Context(), flags.clusterFlags)
+
+			template, err := client.GetClusterTemplate(flags.name)
+			if err != nil {
+				return errors.Wrap(err, "failed to query cluster template")
+			}
+			if template == nil {
+				return nil
+			}
+
+			return printJSON(template)
+		},
+		PreRun: func(cmd *cobra.Command, args []string) {
+			flags.clusterFlags.addFlags(cmd)
+		},
+	}
+	flags.addFlags(cmd)
+
+	return cmd
+}
+
+func newCmdClusterTemplateList() *cobra.Command {
+	var flags clusterTemplateListFlags
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List cluster templates.",
+		RunE: func(command *cobra.Command, args []string) error {
+			command.SilenceUsage = true
+			client := createClient(command.Context(), flags.clusterFlags)
+
+			paging := getPaging(flags.pagingFlags)
+
+			return watchOutput(command.Context(), os.Stdout, flags.watchOptions, func(w io.Writer) error {
+				templates, err := client.GetClusterTemplates(&model.GetClusterTemplatesRequest{
+					Paging: paging,
+				})
+				if err != nil {
+					return errors.Wrap(err, "failed to query cluster templates")
+				}
+
+				return clusterTemplatePrinter.printList(w, flags.tableOptions, templates)
+			})
+		},
+		PreRun: func(cmd *cobra.Command, args []string) {
+			flags.clusterFlags.addFlags(cmd)
+		},
+	}
+	flags.addFlags(cmd)
+
+	return cmd
+}
+
+func newCmdClusterTemplateUpdate() *cobra.Command {
+	var flags clusterTemplateUpdateFlags
+
+	cmd := &cobra.Command{
+		Use:   "update",
+		Short: "Update a cluster template. Changing the spec increments the template version.",
+		RunE: func(command *cobra.Command, args []string) error {
+			command.SilenceUsage = true
+			client := createClient(command.Context(), flags.clusterFlags)
+
+			request := &model.PatchClusterTemplateRequest{}
+			if flags.descriptionChanged {
+				request.Description = &flags.description
+			}
+			if len(flags.specFile) != 0 {
+				spec, err := readClusterTemplateSpec(flags.specFile)
+				if err != nil {
+					return err
+				}
+				request.Spec = spec
+			}
+
+			if flags.dryRun {
+				return runDryRun(request)
+			}
+
+			template, err := client.UpdateClusterTemplate(flags.name, request)
+			if err != nil {
+				return errors.Wrap(err, "failed to update cluster template")
+			}
+
+			return printJSON(template)
+		},
+		PreRun: func(cmd *cobra.Command, args []string) {
+			flags.clusterFlags.addFlags(cmd)
+			flags.clusterTemplateUpdateChanges.addFlags(cmd)
+		},
+	}
+	flags.addFlags(cmd)
+
+	return cmd
+}
+
+func newCmdClusterTemplateDelete() *cobra.Command {
+	var flags clusterTemplateFlags
+
+	cmd := &cobra.Command{
+		Use:   "delete",
+		Short: "Delete a cluster template. Clusters created from it are not affected.",
+		RunE: func(command *cobra.Command, args []string) error {
+			command.SilenceUsage = true
+			client := createClient(command.Context(), flags.clusterFlags)
+
+			err := client.DeleteClusterTemplate(flags.name)
+			if err != nil {
+				return errors.Wrap(err, "failed to delete cluster template")
+			}
+
+			return nil
+		},
+		PreRun: func(cmd *cobra.Command, args []string) {
+			flags.clusterFlags.addFlags(cmd)
+		},
+	}
+	flags.addFlags(cmd)
+
+	return cmd
+}
+
+func newCmdClusterTemplateClusters() *cobra.Command {
+	var flags clusterTemplateClustersFlags
+
+	cmd := &cobra.Command{
+		Use:   "clusters",
+		Short: "List the clusters created from a template and how they differ from its current version.",
+		RunE: func(command *cobra.Command, args []string) error {
+			command.SilenceUsage = true
+			client := createClient(command.Context(), flags.clusterFlags)
+
+			divergences, err := client.GetClusterTemplateDivergence(flags.name, &model.GetClusterTemplateDivergenceRequest{
+				OnlyDiverged: flags.onlyDiverged,
+			})
+			if err != nil {
+				return errors.Wrap(err, "failed to query cluster template divergence")
+			}
+
+			return clusterTemplateDivergencePrinter.printList(os.Stdout, flags.tableOptions, divergences)
+		},
+		PreRun: func(cmd *cobra.Command, args []string) {
+			flags.clusterFlags.addFlags(cmd)
+		},
+	}
+	flags.addFlags(cmd)
+
+	return cmd
+}
+
+func readClusterTemplateSpec(path string) (*model.ClusterTemplateSpec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read cluster template spec file")
+	}
+
+	var spec model.ClusterTemplateSpec
+	err = json.Unmarshal(data, &spec)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse cluster template spec file")
+	}
+
+	return &spec, nil
+}
+
+var clusterTemplatePrinter = resourcePrinter[*model.ClusterTemplate]{
+	defaultTable: defaultClusterTemplateTableData,
+}
+
+func defaultClusterTemplateTableData(templates []*model.ClusterTemplate) ([]string, [][]string) {
+	keys := []string{"NAME", "VERSION", "DESCRIPTION", "UPDATE AT"}
+	vals := make([][]string, 0, len(templates))
+	for _, template := range templates {
+		vals = append(vals, []string{
+			template.Name,
+			fmt.Sprintf("%d", template.Version),
+			template.Description,
+			model.DateStringFromMillis(template.UpdateAt),
+		})
+	}
+	return keys, vals
+}
+
+var clusterTemplateDivergencePrinter = resourcePrinter[*model.ClusterTemplateDivergence]{
+	defaultTable: defaultClusterTemplateDivergenceTableData,
+}
+
+func defaultClusterTemplateDivergenceTableData(divergences []*model.ClusterTemplateDivergence) ([]string, [][]string) {
+	keys := []string{"CLUSTER", "NAME", "STATE", "TEMPLATE VERSION", "DIFFERENCES"}
+	vals := make([][]string, 0, len(divergences))
+	for _, divergence := range divergences {
+		vals = append(vals, []string{
+			divergence.ClusterID,
+			divergence.ClusterName,
+			divergence.State,
+			fmt.Sprintf("%d/%d", divergence.TemplateVersion, divergence.CurrentVersion),
+			strings.Join(divergence.Differences, "; "),
+		})
+	}
+	return keys, vals
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+type clusterTemplateFlags struct {
+	clusterFlags
+	name string
+}
+
+func (flags *clusterTemplateFlags) addFlags(command *cobra.Command) {
+	command.Flags().StringVar(&flags.name, "name", "", "The name of the cluster template.")
+	_ = command.MarkFlagRequired("name")
+}
+
+type clusterTemplateCreateFlags struct {
+	clusterTemplateFlags
+	description string
+	specFile    string
+}
+
+func (flags *clusterTemplateCreateFlags) addFlags(command *cobra.Command) {
+	flags.clusterTemplateFlags.addFlags(command)
+	command.Flags().StringVar(&flags.description, "description", "", "A description of the cluster template.")
+	command.Flags().StringVar(&flags.specFile, "spec-file", "", "Path to a JSON file with the cluster create request values of the template.")
+	_ = command.MarkFlagRequired("spec-file")
+}
+
+type clusterTemplateUpdateChanges struct {
+	descriptionChanged bool
+}
+
+func (flags *clusterTemplateUpdateChanges) addFlags(command *cobra.Command) {
+	flags.descriptionChanged = command.Flags().Changed("description")
+}
+
+type clusterTemplateUpdateFlags struct {
+	clusterTemplateFlags
+	clusterTemplateUpdateChanges
+	description string
+	specFile    string
+}
+
+func (flags *clusterTemplateUpdateFlags) addFlags(command *cobra.Command) {
+	flags.clusterTemplateFlags.addFlags(command)
+	command.Flags().StringVar(&flags.description, "description", "", "A new description of the cluster template.")
+	command.Flags().StringVar(&flags.specFile, "spec-file", "", "Path to a JSON file with the new cluster create request values of the template.")
+}
+
+type clusterTemplateListFlags struct {
+	clusterFlags
+	pagingFlags
+	tableOptions
+	watchOptions
+}
+
+func (flags *clusterTemplateListFlags) addFlags(command *cobra.Command) {
+	flags.pagingFlags.addFlags(command)
+	flags.tableOptions.addFlags(command)
+	flags.watchOptions.addFlags(command)
+}
+
+type clusterTemplateClustersFlags struct {
+	clusterTemplateFlags
+	tableOptions
+	onlyDiverged bool
+}
+
+func (flags *clusterTemplateClustersFlags) addFlags(command *cobra.Command) {
+	flags.clusterTemplateFlags.addFlags(command)
+	flags.tableOptions.addFlags(command)
+	command.Flags().BoolVar(&flags.onlyDiverged, "only-diverged", false, "Only list clusters that differ from the current version of the template.")
+}
@@ -0,0 +1,126 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func newCmdClusterUpgrades() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "upgrades",
+		Short: "Inspect and resume the Kubernetes upgrades of a cluster.",
+	}
+
+	cmd.AddCommand(newCmdClusterUpgradesList())
+	cmd.AddCommand(newCmdClusterUpgradesResume())
+
+	return cmd
+}
+
+func newCmdClusterUpgradesList() *cobra.Command {
+	var flags clusterUpgradesListFlags
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the upgrades of a cluster, most recent first.",
+		RunE: func(command *cobra.Command, args []string) error {
+			command.SilenceUsage = true
+			client := createClient(command.Context(), flags.clusterFlags)
+
+			paging := getPaging(flags.pagingFlags)
+
+			return watchOutput(command.Context(), os.Stdout, flags.watchOptions, func(w io.Writer) error {
+				upgrades, err := client.GetClusterUpgrades(flags.cluster, &model.GetClusterUpgradesRequest{
+					State:  flags.state,
+					Paging: paging,
+				})
+				if err != nil {
+					return errors.Wrap(err, "failed to query cluster upgrades")
+				}
+
+				return clusterUpgradePrinter.printList(w, flags.tableOptions, upgrades)
+			})
+		},
+		PreRun: func(cmd *cobra.Command, args []string) {
+			flags.clusterFlags.addFlags(cmd)
+		},
+	}
+	flags.addFlags(cmd)
+
+	return cmd
+}
+
+func newCmdClusterUpgradesResume() *cobra.Command {
+	var flags clusterUpgradesResumeFlags
+
+	cmd := &cobra.Command{
+		Use:   "resume",
+		Short: "Resume the failed upgrade of a cluster from the phase it failed in.",
+		RunE: func(command *cobra.Command, args []string) error {
+			command.SilenceUsage = true
+			client := createClient(command.Context(), flags.clusterFlags)
+
+			upgrade, err := client.ResumeClusterUpgrade(flags.cluster)
+			if err != nil {
+				return errors.Wrap(err, "failed to resume cluster upgrade")
+			}
+
+			return printJSON(upgrade)
+		},
+		PreRun: func(cmd *cobra.Command, args []string) {
+			flags.clusterFlags.addFlags(cmd)
+		},
+	}
+	flags.addFlags(cmd)
+
+	return cmd
+}
+
+var clusterUpgradePrinter = resourcePrinter[*model.ClusterUpgrade]{
+	defaultTable: defaultClusterUpgradeTableData,
+}
+
+func defaultClusterUpgradeTableData(upgrades []*model.ClusterUpgrade) ([]string, [][]string) {
+	keys := []string{"ID", "VERSION", "STATE", "PHASE", "NODEGROUPS", "CREATE AT", "MESSAGE"}
+	vals := make([][]string, 0, len(upgrades))
+	for _, upgrade := range upgrades {
+		vals = append(vals, []string{
+			upgrade.ID,
+			upgrade.Version,
+			string(upgrade.State),
+			string(upgrade.Phase),
+			clusterUpgradeNodeGroupsToString(upgrade.NodeGroups),
+			model.DateStringFromMillis(upgrade.CreateAt),
+			upgrade.Message,
+		})
+	}
+	return keys, vals
+}
+
+func clusterUpgradeNodeGroupsToString(nodeGroups model.ClusterUpgradeNodeGroups) string {
+	var succeeded int
+	var failed []string
+	for _, nodeGroup := range nodeGroups {
+		switch nodeGroup.State {
+		case model.ClusterUpgradeNodeGroupSucceeded:
+			succeeded++
+		case model.ClusterUpgradeNodeGroupFailed:
+			failed = append(failed, nodeGroup.Name)
+		}
+	}
+	progress := fmt.Sprintf("%d/%d", succeeded, len(nodeGroups))
+	if len(failed) > 0 {
+		progress += fmt.Sprintf(" (failed: %s)", strings.Join(failed, ", "))
+	}
+	return progress
+}
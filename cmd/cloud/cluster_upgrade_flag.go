@@ -0,0 +1,33 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+type clusterUpgradesListFlags struct {
+	clusterFlags
+	pagingFlags
+	tableOptions
+	watchOptions
+	cluster string
+	state   string
+}
+
+func (flags *clusterUpgradesListFlags) addFlags(command *cobra.Command) {
+	flags.pagingFlags.addFlags(command)
+	flags.tableOptions.addFlags(command)
+	flags.watchOptions.addFlags(command)
+	command.Flags().StringVar(&flags.cluster, "cluster", "", "The id of the cluster whose upgrades are listed.")
+	command.Flags().StringVar(&flags.state, "state", "", "The state by which to filter cluster upgrades.")
+	_ = command.MarkFlagRequired("cluster")
+}
+
+type clusterUpgradesResumeFlags struct {
+	clusterFlags
+	cluster string
+}
+
+func (flags *clusterUpgradesResumeFlags) addFlags(command *cobra.Command) {
+	command.Flags().StringVar(&flags.cluster, "cluster", "", "The id of the cluster whose failed upgrade is resumed.")
+	_ = command.MarkFlagRequired("cluster")
+}
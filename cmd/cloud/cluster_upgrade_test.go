@@ -0,0 +1,38 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClusterUpgradePrinter(t *testing.T) {
+	upgrades := []*model.ClusterUpgrade{
+		{ID: "upgrade1", Version: "1.29"},
+		{ID: "upgrade2", Version: "1.30"},
+	}
+
+	printList := func(t *testing.T, output string) string {
+		buffer := &bytes.Buffer{}
+		err := clusterUpgradePrinter.printList(buffer, tableOptions{output: output}, upgrades)
+		require.NoError(t, err)
+		return buffer.String()
+	}
+
+	t.Run("name", func(t *testing.T) {
+		assert.Equal(t, "upgrade1\nupgrade2\n", printList(t, "name"))
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		out := printList(t, "csv")
+		assert.Contains(t, out, "ID,VERSION,STATE,PHASE")
+		assert.Contains(t, out, "upgrade2,1.30")
+	})
+}
@@ -0,0 +1,250 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func newCmdClusterUtilityRegistry() *cobra.Command {
+	var flags clusterUtilityRegistryFlags
+
+	cmd := &cobra.Command{
+		Use:   "registry",
+		Short: "Show the utility registry of a cluster with the utility versions.",
+		RunE: func(command *cobra.Command, args []string) error {
+			command.SilenceUsage = true
+			client := createClient(command.Context(), flags.clusterFlags)
+
+			utilities, err := client.GetClusterUtilityRegistry(flags.cluster)
+			if err != nil {
+				return errors.Wrap(err, "failed to get cluster utility registry")
+			}
+
+			return clusterUtilityPrinter.printList(os.Stdout, flags.tableOptions, utilities)
+		},
+		PreRun: func(cmd *cobra.Command, args []string) {
+			flags.clusterFlags.addFlags(cmd)
+		},
+	}
+	flags.addFlags(cmd)
+
+	return cmd
+}
+
+func newCmdClusterUtilityAdd() *cobra.Command {
+	var flags clusterUtilityAddFlags
+
+	cmd := &cobra.Command{
+		Use:   "add",
+		Short: "Add a Helm chart utility to the registry of a cluster and provision it.",
+		RunE: func(command *cobra.Command, args []string) error {
+			command.SilenceUsage = true
+
+			return executeClusterUtilityAddCmd(command.Context(), flags)
+		},
+		PreRun: func(cmd *cobra.Command, args []string) {
+			flags.clusterFlags.addFlags(cmd)
+		},
+	}
+	flags.addFlags(cmd)
+
+	return cmd
+}
+
+func executeClusterUtilityAddCmd(ctx context.Context, flags clusterUtilityAddFlags) error {
+	client := createClient(ctx, flags.clusterFlags)
+
+	request := &model.AddClusterUtilityRequest{
+		Name:       flags.name,
+		Repo:       flags.repo,
+		RepoURL:    flags.repoURL,
+		Chart:      flags.chart,
+		Release:    flags.release,
+		Namespace:  flags.namespace,
+		Version:    flags.version,
+		ValuesPath: flags.valuesPath,
+		DependsOn:  flags.dependsOn,
+	}
+
+	if flags.dryRun {
+		return runDryRun(request)
+	}
+
+	cluster, err := client.AddClusterUtility(flags.cluster, request)
+	if err != nil {
+		return errors.Wrap(err, "failed to add cluster utility")
+	}
+
+	return printJSON(cluster)
+}
+
+func newCmdClusterUtilityRemove() *cobra.Command {
+	var flags clusterUtilityRemoveFlags
+
+	cmd := &cobra.Command{
+		Use:   "remove",
+		Short: "Remove a utility from the registry of a cluster and uninstall it.",
+		RunE: func(command *cobra.Command, args []string) error {
+			command.SilenceUsage = true
+			client := createClient(command.Context(), flags.clusterFlags)
+
+			cluster, err := client.RemoveClusterUtility(flags.cluster, flags.name)
+			if err != nil {
+				return errors.Wrap(err, "failed to remove cluster utility")
+			}
+
+			return printJSON(cluster)
+		},
+		PreRun: func(cmd *cobra.Command, args []string) {
+			flags.clusterFlags.addFlags(cmd)
+		},
+	}
+	flags.addFlags(cmd)
+
+	return cmd
+}
+
+func newCmdClusterUtilityDrift() *cobra.Command {
+	var flags clusterUtilityDriftFlags
+
+	cmd := &cobra.Command{
+		Use:   "drift",
+		Short: "Compare the desired, actual and default utility versions across clusters.",
+		RunE: func(command *cobra.Command, args []string) error {
+			command.SilenceUsage = true
+			client := createClient(command.Context(), flags.clusterFlags)
+
+			return watchOutput(command.Context(), os.Stdout, flags.watchOptions, func(w io.Writer) error {
+				report, err := client.GetUtilityDrift(&model.GetUtilityDriftRequest{
+					Utility:     flags.utility,
+					OnlyDrifted: flags.onlyDrifted,
+				})
+				if err != nil {
+					return errors.Wrap(err, "failed to get utility drift report")
+				}
+
+				return utilityDriftPrinter.print(w, flags.tableOptions, report.Utilities, report)
+			})
+		},
+		PreRun: func(cmd *cobra.Command, args []string) {
+			flags.clusterFlags.addFlags(cmd)
+		},
+	}
+	flags.addFlags(cmd)
+
+	return cmd
+}
+
+var utilityDriftPrinter = resourcePrinter[*model.UtilityDrift]{
+	defaultTable: defaultUtilityDriftTableData,
+	wideTable:    wideUtilityDriftTableData,
+	id: func(drift *model.UtilityDrift) string {
+		return drift.Utility
+	},
+}
+
+func defaultUtilityDriftTableData(drifts []*model.UtilityDrift) ([]string, [][]string) {
+	keys := []string{"UTILITY", "DEFAULT VERSION", "ACTUAL VERSIONS", "CLUSTERS", "DRIFTED", "PENDING"}
+	vals := make([][]string, 0, len(drifts))
+	for _, drift := range drifts {
+		vals = append(vals, []string{
+			drift.Utility,
+			drift.DefaultVersion,
+			actualVersionsToString(drift.ActualVersions),
+			strconv.Itoa(len(drift.Clusters)),
+			strconv.Itoa(drift.DriftedClusters),
+			strconv.Itoa(drift.PendingClusters),
+		})
+	}
+	return keys, vals
+}
+
+func wideUtilityDriftTableData(drifts []*model.UtilityDrift) ([]string, [][]string) {
+	keys := []string{"UTILITY", "CLUSTER", "DEFAULT VERSION", "DESIRED VERSION", "ACTUAL VERSION", "DRIFTED", "PENDING"}
+	vals := [][]string{}
+	for _, drift := range drifts {
+		for _, cluster := range drift.Clusters {
+			vals = append(vals, []string{
+				drift.Utility,
+				cluster.ClusterID,
+				drift.DefaultVersion,
+				cluster.DesiredVersion,
+				cluster.ActualVersion,
+				strconv.FormatBool(cluster.Drifted),
+				strconv.FormatBool(cluster.Pending),
+			})
+		}
+	}
+	return keys, vals
+}
+
+// actualVersionsToString formats the number of clusters per version, sorted
+// by version.
+func actualVersionsToString(versions map[string]int) string {
+	names := make([]string, 0, len(versions))
+	for version := range versions {
+		names = append(names, version)
+	}
+	sort.Strings(names)
+
+	counts := make([]string, 0, len(names))
+	for _, version := range names {
+		counts = append(counts, fmt.Sprintf("%s(%d)", version, versions[version]))
+	}
+	return strings.Join(counts, ",")
+}
+
+var clusterUtilityPrinter = resourcePrinter[*model.ClusterUtility]{
+	defaultTable: defaultClusterUtilityTableData,
+	id: func(utility *model.ClusterUtility) string {
+		return utility.Name
+	},
+}
+
+func defaultClusterUtilityTableData(utilities []*model.ClusterUtility) ([]string, [][]string) {
+	keys := []string{"NAME", "CHART", "NAMESPACE", "DEPENDS ON", "BUILTIN", "DESIRED VERSION", "ACTUAL VERSION", "HEALTH"}
+	vals := make([][]string, 0, len(utilities))
+	for _, utility := range utilities {
+		vals = append(vals, []string{
+			utility.Name,
+			utility.ChartReference(),
+			utility.Namespace,
+			strings.Join(utility.DependsOn, ","),
+			strconv.FormatBool(utility.Builtin),
+			utilityVersionToString(utility.DesiredVersion),
+			utilityVersionToString(utility.ActualVersion),
+			utilityHealthToString(utility.Health),
+		})
+	}
+	return keys, vals
+}
+
+func utilityHealthToString(health *model.ClusterUtilityHealth) string {
+	if health == nil {
+		return ""
+	}
+	if health.Healthy {
+		return "healthy"
+	}
+	return "unhealthy: " + health.Message
+}
+
+func utilityVersionToString(version *model.HelmUtilityVersion) string {
+	if version == nil {
+		return ""
+	}
+	return version.Version()
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/spf13/cobra"
+)
+
+type clusterUtilityRegistryFlags struct {
+	clusterFlags
+	tableOptions
+	cluster string
+}
+
+func (flags *clusterUtilityRegistryFlags) addFlags(command *cobra.Command) {
+	command.Flags().StringVar(&flags.cluster, "cluster", "", "The id of the cluster whose utility registry is to be fetched.")
+	flags.tableOptions.addFlags(command)
+	_ = command.MarkFlagRequired("cluster")
+}
+
+type clusterUtilityAddFlags struct {
+	clusterFlags
+	cluster    string
+	name       string
+	repo       string
+	repoURL    string
+	chart      string
+	release    string
+	namespace  string
+	version    string
+	valuesPath string
+	dependsOn  []string
+}
+
+func (flags *clusterUtilityAddFlags) addFlags(command *cobra.Command) {
+	command.Flags().StringVar(&flags.cluster, "cluster", "", "The id of the cluster to add the utility to.")
+	command.Flags().StringVar(&flags.name, "name", "", "The name of the utility.")
+	command.Flags().StringVar(&flags.repo, "repo", "", "The name of the Helm repo holding the chart.")
+	command.Flags().StringVar(&flags.repoURL, "repo-url", "", "The URL of the Helm repo. Optional if the repo is already used by another utility of the cluster.")
+	command.Flags().StringVar(&flags.chart, "chart", "", "The name of the chart in the Helm repo.")
+	command.Flags().StringVar(&flags.release, "release", "", "The name of the Helm release. Defaults to the name of the utility.")
+	command.Flags().StringVar(&flags.namespace, "namespace", "", "The namespace to deploy the utility to.")
+	command.Flags().StringVar(&flags.version, "version", "", "The chart version to deploy.")
+	command.Flags().StringVar(&flags.valuesPath, "values-path", "", "The path or URL of the Helm values file.")
+	command.Flags().StringSliceVar(&flags.dependsOn, "depends-on", nil, "Utilities which must be deployed before this utility.")
+	_ = command.MarkFlagRequired("cluster")
+	_ = command.MarkFlagRequired("name")
+	_ = command.MarkFlagRequired("repo")
+	_ = command.MarkFlagRequired("chart")
+	_ = command.MarkFlagRequired("namespace")
+	_ = command.MarkFlagRequired("version")
+	_ = command.MarkFlagRequired("values-path")
+}
+
+type clusterUtilityRemoveFlags struct {
+	clusterFlags
+	cluster string
+	name    string
+}
+
+func (flags *clusterUtilityRemoveFlags) addFlags(command *cobra.Command) {
+	command.Flags().StringVar(&flags.cluster, "cluster", "", "The id of the cluster to remove the utility from.")
+	command.Flags().StringVar(&flags.name, "name", "", "The name of the utility to remove.")
+	_ = command.MarkFlagRequired("cluster")
+	_ = command.MarkFlagRequired("name")
+}
+
+type clusterUtilityDriftFlags struct {
+	clusterFlags
+	tableOptions
+	watchOptions
+	utility     string
+	onlyDrifted bool
+}
+
+func (flags *clusterUtilityDriftFlags) addFlags(command *cobra.Command) {
+	flags.tableOptions.addFlags(command)
+	flags.watchOptions.addFlags(command)
+	command.Flags().StringVar(&flags.utility, "utility", "", "The utility by which to filter the drift report.")
+	command.Flags().BoolVar(&flags.onlyDrifted, "only-drifted", false, "Only include clusters with drifted or pending utility versions.")
+}
+
+type clusterUtilityRolloutStartFlags struct {
+	clusterFlags
+	utility               string
+	version               string
+	valuesPath            string
+	clusterIDs            []string
+	annotations           []string
+	maxConcurrentClusters int
+	clusterTimeout        time.Duration
+}
+
+func (flags *clusterUtilityRolloutStartFlags) addFlags(command *cobra.Command) {
+	command.Flags().StringVar(&flags.utility, "utility", "", "The utility to roll out.")
+	command.Flags().StringVar(&flags.version, "version", "", "The chart version to roll out.")
+	command.Flags().StringVar(&flags.valuesPath, "values-path", "", "The path or URL of the Helm values file. Defaults to the values currently used by each cluster.")
+	command.Flags().StringSliceVar(&flags.clusterIDs, "cluster", nil, "The ids of the clusters to roll out the utility to.")
+	command.Flags().StringSliceVar(&flags.annotations, "annotation", nil, "Roll out the utility to all clusters with these annotations.")
+	command.Flags().IntVar(&flags.maxConcurrentClusters, "max-concurrent-clusters", model.DefaultUtilityRolloutMaxConcurrentClusters, "The maximum number of clusters upgraded at the same time.")
+	command.Flags().DurationVar(&flags.clusterTimeout, "cluster-timeout", model.DefaultUtilityRolloutClusterTimeoutSeconds*time.Second, "The time a cluster has to finish provisioning the new version before the rollout is paused.")
+	_ = command.MarkFlagRequired("utility")
+	_ = command.MarkFlagRequired("version")
+}
+
+type clusterUtilityRolloutListFlags struct {
+	clusterFlags
+	pagingFlags
+	tableOptions
+	watchOptions
+	utility string
+	state   string
+}
+
+func (flags *clusterUtilityRolloutListFlags) addFlags(command *cobra.Command) {
+	flags.pagingFlags.addFlags(command)
+	flags.tableOptions.addFlags(command)
+	flags.watchOptions.addFlags(command)
+	command.Flags().StringVar(&flags.utility, "utility", "", "The utility by which to filter utility rollouts.")
+	command.Flags().StringVar(&flags.state, "state", "", "The state by which to filter utility rollouts.")
+}
+
+type clusterUtilityRolloutGetFlags struct {
+	clusterFlags
+	tableOptions
+	watchOptions
+	rolloutID string
+}
+
+func (flags *clusterUtilityRolloutGetFlags) addFlags(command *cobra.Command) {
+	flags.tableOptions.addFlags(command)
+	flags.watchOptions.addFlags(command)
+	command.Flags().StringVar(&flags.rolloutID, "rollout", "", "The id of the utility rollout to be fetched.")
+	_ = command.MarkFlagRequired("rollout")
+}
+
+type clusterUtilityRolloutActionFlags struct {
+	clusterFlags
+	rolloutID string
+}
+
+func (flags *clusterUtilityRolloutActionFlags) addFlags(command *cobra.Command) {
+	command.Flags().StringVar(&flags.rolloutID, "rollout", "", "The id of the utility rollout.")
+	_ = command.MarkFlagRequired("rollout")
+}
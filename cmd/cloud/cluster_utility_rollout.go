@@ -0,0 +1,236 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func newCmdClusterUtilityRollout() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rollout",
+		Short: "Roll out utility versions across clusters and manage utility rollouts.",
+	}
+
+	cmd.AddCommand(newCmdClusterUtilityRolloutStart())
+	cmd.AddCommand(newCmdClusterUtilityRolloutList())
+	cmd.AddCommand(newCmdClusterUtilityRolloutGet())
+	cmd.AddCommand(newCmdClusterUtilityRolloutResume())
+	cmd.AddCommand(newCmdClusterUtilityRolloutCancel())
+
+	return cmd
+}
+
+func newCmdClusterUtilityRolloutStart() *cobra.Command {
+	var flags clusterUtilityRolloutStartFlags
+
+	cmd := &cobra.Command{
+		Use:   "start",
+		Short: "Upgrade a utility on the selected clusters a few clusters at a time.",
+		RunE: func(command *cobra.Command, args []string) error {
+			command.SilenceUsage = true
+
+			return executeClusterUtilityRolloutStartCmd(command.Context(), flags)
+		},
+		PreRun: func(cmd *cobra.Command, args []string) {
+			flags.clusterFlags.addFlags(cmd)
+		},
+	}
+	flags.addFlags(cmd)
+
+	return cmd
+}
+
+func executeClusterUtilityRolloutStartCmd(ctx context.Context, flags clusterUtilityRolloutStartFlags) error {
+	client := createClient(ctx, flags.clusterFlags)
+
+	request := &model.UtilityRolloutRequest{
+		Utility:               flags.utility,
+		Version:               flags.version,
+		ValuesPath:            flags.valuesPath,
+		ClusterIDs:            flags.clusterIDs,
+		Annotations:           flags.annotations,
+		MaxConcurrentClusters: flags.maxConcurrentClusters,
+		ClusterTimeoutSeconds: int64(flags.clusterTimeout.Seconds()),
+	}
+
+	if flags.dryRun {
+		return runDryRun(request)
+	}
+
+	rollout, err := client.CreateUtilityRollout(request)
+	if err != nil {
+		return errors.Wrap(err, "failed to create utility rollout")
+	}
+
+	return printJSON(rollout)
+}
+
+func newCmdClusterUtilityRolloutList() *cobra.Command {
+	var flags clusterUtilityRolloutListFlags
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List utility rollouts.",
+		RunE: func(command *cobra.Command, args []string) error {
+			command.SilenceUsage = true
+			client := createClient(command.Context(), flags.clusterFlags)
+
+			paging := getPaging(flags.pagingFlags)
+
+			return watchOutput(command.Context(), os.Stdout, flags.watchOptions, func(w io.Writer) error {
+				rollouts, err := client.GetUtilityRollouts(&model.GetUtilityRolloutsRequest{
+					Utility: flags.utility,
+					State:   flags.state,
+					Paging:  paging,
+				})
+				if err != nil {
+					return errors.Wrap(err, "failed to query utility rollouts")
+				}
+
+				return utilityRolloutPrinter.printList(w, flags.tableOptions, rollouts)
+			})
+		},
+		PreRun: func(cmd *cobra.Command, args []string) {
+			flags.clusterFlags.addFlags(cmd)
+		},
+	}
+	flags.addFlags(cmd)
+
+	return cmd
+}
+
+func newCmdClusterUtilityRolloutGet() *cobra.Command {
+	var flags clusterUtilityRolloutGetFlags
+
+	cmd := &cobra.Command{
+		Use:   "get",
+		Short: "Get a particular utility rollout.",
+		RunE: func(command *cobra.Command, args []string) error {
+			command.SilenceUsage = true
+			client := createClient(command.Context(), flags.clusterFlags)
+
+			return watchOutput(command.Context(), os.Stdout, flags.watchOptions, func(w io.Writer) error {
+				rollout, err := client.GetUtilityRollout(flags.rolloutID)
+				if err != nil {
+					return errors.Wrap(err, "failed to query utility rollout")
+				}
+				if rollout == nil {
+					return nil
+				}
+
+				return utilityRolloutClusterPrinter.print(w, flags.tableOptions, rollout.Clusters, rollout)
+			})
+		},
+		PreRun: func(cmd *cobra.Command, args []string) {
+			flags.clusterFlags.addFlags(cmd)
+		},
+	}
+	flags.addFlags(cmd)
+
+	return cmd
+}
+
+func newCmdClusterUtilityRolloutResume() *cobra.Command {
+	var flags clusterUtilityRolloutActionFlags
+
+	cmd := &cobra.Command{
+		Use:   "resume",
+		Short: "Resume a paused utility rollout, retrying the clusters that failed to upgrade.",
+		RunE: func(command *cobra.Command, args []string) error {
+			command.SilenceUsage = true
+			client := createClient(command.Context(), flags.clusterFlags)
+
+			rollout, err := client.ResumeUtilityRollout(flags.rolloutID)
+			if err != nil {
+				return errors.Wrap(err, "failed to resume utility rollout")
+			}
+
+			return printJSON(rollout)
+		},
+		PreRun: func(cmd *cobra.Command, args []string) {
+			flags.clusterFlags.addFlags(cmd)
+		},
+	}
+	flags.addFlags(cmd)
+
+	return cmd
+}
+
+func newCmdClusterUtilityRolloutCancel() *cobra.Command {
+	var flags clusterUtilityRolloutActionFlags
+
+	cmd := &cobra.Command{
+		Use:   "cancel",
+		Short: "Cancel a utility rollout. Clusters that are already upgrading are not rolled back.",
+		RunE: func(command *cobra.Command, args []string) error {
+			command.SilenceUsage = true
+			client := createClient(command.Context(), flags.clusterFlags)
+
+			rollout, err := client.CancelUtilityRollout(flags.rolloutID)
+			if err != nil {
+				return errors.Wrap(err, "failed to cancel utility rollout")
+			}
+
+			return printJSON(rollout)
+		},
+		PreRun: func(cmd *cobra.Command, args []string) {
+			flags.clusterFlags.addFlags(cmd)
+		},
+	}
+	flags.addFlags(cmd)
+
+	return cmd
+}
+
+var utilityRolloutPrinter = resourcePrinter[*model.UtilityRollout]{
+	defaultTable: defaultUtilityRolloutTableData,
+}
+
+func defaultUtilityRolloutTableData(rollouts []*model.UtilityRollout) ([]string, [][]string) {
+	keys := []string{"ID", "UTILITY", "VERSION", "STATE", "PROGRESS", "FAILED", "REQUEST AT"}
+	vals := make([][]string, 0, len(rollouts))
+	for _, rollout := range rollouts {
+		finished := rollout.Clusters.Count(model.UtilityRolloutClusterSucceeded) + rollout.Clusters.Count(model.UtilityRolloutClusterSkipped)
+		vals = append(vals, []string{
+			rollout.ID,
+			rollout.Utility,
+			rollout.Version,
+			string(rollout.State),
+			fmt.Sprintf("%d/%d", finished, len(rollout.Clusters)),
+			strconv.Itoa(rollout.Clusters.Count(model.UtilityRolloutClusterFailed)),
+			model.DateStringFromMillis(rollout.RequestAt),
+		})
+	}
+	return keys, vals
+}
+
+var utilityRolloutClusterPrinter = resourcePrinter[*model.UtilityRolloutCluster]{
+	defaultTable: defaultUtilityRolloutClusterTableData,
+	id: func(cluster *model.UtilityRolloutCluster) string {
+		return cluster.ClusterID
+	},
+}
+
+func defaultUtilityRolloutClusterTableData(clusters []*model.UtilityRolloutCluster) ([]string, [][]string) {
+	keys := []string{"CLUSTER", "STATE", "START AT", "MESSAGE"}
+	vals := make([][]string, 0, len(clusters))
+	for _, cluster := range clusters {
+		var startAt string
+		if cluster.StartAt != 0 {
+			startAt = model.DateStringFromMillis(cluster.StartAt)
+		}
+		vals = append(vals, []string{cluster.ClusterID, string(cluster.State), startAt, cluster.Message})
+	}
+	return keys, vals
+}
@@ -7,6 +7,8 @@ package main
 import (
 	"context"
 	"fmt"
+	"io"
+	"os"
 
 	"github.com/mattermost/mattermost-cloud/internal/common"
 	"github.com/mattermost/mattermost-cloud/model"
@@ -41,6 +43,8 @@ func newCmdDatabaseMultitenant() *cobra.Command {
 	cmd.AddCommand(newCmdDatabaseMultitenantUpdate())
 	cmd.AddCommand(newCmdDatabaseMultitenantDelete())
 	cmd.AddCommand(newCmdDatabaseMultitenantReport())
+	cmd.AddCommand(newCmdDatabaseMultitenantCapacity())
+	cmd.AddCommand(newCmdDatabaseMultitenantRebalance())
 
 	return cmd
 }
@@ -71,37 +75,22 @@ func executeDatabaseMultitenantListCmd(ctx context.Context, flags databaseMultiT
 
 	paging := getPaging(flags.pagingFlags)
 
-	multitenantDatabases, err := client.GetMultitenantDatabases(&model.GetMultitenantDatabasesRequest{
-		VpcID:        flags.vpcID,
-		DatabaseType: flags.databaseType,
-		Paging:       paging,
-	})
-	if err != nil {
-		return errors.Wrap(err, "failed to query multitenant databases")
-	}
-
-	if enabled, customCols := getTableOutputOption(flags.tableOptions); enabled {
-		var keys []string
-		var vals [][]string
-
-		if len(customCols) > 0 {
-			data := make([]interface{}, 0, len(multitenantDatabases))
-			for _, mtd := range multitenantDatabases {
-				data = append(data, mtd)
-			}
-			keys, vals, err = prepareTableData(customCols, data)
-			if err != nil {
-				return errors.Wrap(err, "failed to prepare table output")
-			}
-		} else {
-			keys, vals = defaultMultitenantDatabaseTableData(multitenantDatabases)
+	return watchOutput(ctx, os.Stdout, flags.watchOptions, func(w io.Writer) error {
+		multitenantDatabases, err := client.GetMultitenantDatabases(&model.GetMultitenantDatabasesRequest{
+			VpcID:        flags.vpcID,
+			DatabaseType: flags.databaseType,
+			Paging:       paging,
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to query multitenant databases")
 		}
 
-		printTable(keys, vals)
-		return nil
-	}
+		return multitenantDatabasePrinter.printList(w, flags.tableOptions, multitenantDatabases)
+	})
+}
 
-	return printJSON(multitenantDatabases)
+var multitenantDatabasePrinter = resourcePrinter[*model.MultitenantDatabase]{
+	defaultTable: defaultMultitenantDatabaseTableData,
 }
 
 func defaultMultitenantDatabaseTableData(multitenantDatabases []*model.MultitenantDatabase) ([]string, [][]string) {
@@ -123,15 +112,17 @@ func newCmdDatabaseMultitenantGet() *cobra.Command {
 			command.SilenceUsage = true
 			client := createClient(command.Context(), flags.clusterFlags)
 
-			multitenantDatabase, err := client.GetMultitenantDatabase(flags.multitenantDatabaseID)
-			if err != nil {
-				return errors.Wrap(err, "failed to query multitenant database")
-			}
-			if multitenantDatabase == nil {
-				return nil
-			}
+			return watchOutput(command.Context(), os.Stdout, flags.watchOptions, func(w io.Writer) error {
+				multitenantDatabase, err := client.GetMultitenantDatabase(flags.multitenantDatabaseID)
+				if err != nil {
+					return errors.Wrap(err, "failed to query multitenant database")
+				}
+				if multitenantDatabase == nil {
+					return nil
+				}
 
-			return printJSON(multitenantDatabase)
+				return multitenantDatabasePrinter.printObject(w, flags.tableOptions, multitenantDatabase)
+			})
 		},
 		PreRun: func(cmd *cobra.Command, args []string) {
 			flags.clusterFlags.addFlags(cmd)
@@ -243,36 +234,21 @@ func executeDatabaseLogicalListCmd(ctx context.Context, flags databaseLogicalLis
 
 	paging := getPaging(flags.pagingFlags)
 
-	logicalDatabases, err := client.GetLogicalDatabases(&model.GetLogicalDatabasesRequest{
-		MultitenantDatabaseID: flags.multitenantDatabaseID,
-		Paging:                paging,
-	})
-	if err != nil {
-		return errors.Wrap(err, "failed to query logical databases")
-	}
-
-	if enabled, customCols := getTableOutputOption(flags.tableOptions); enabled {
-		var keys []string
-		var vals [][]string
-
-		if len(customCols) > 0 {
-			data := make([]interface{}, 0, len(logicalDatabases))
-			for _, ldb := range logicalDatabases {
-				data = append(data, ldb)
-			}
-			keys, vals, err = prepareTableData(customCols, data)
-			if err != nil {
-				return errors.Wrap(err, "failed to prepare table output")
-			}
-		} else {
-			keys, vals = defaultLogicalDatabaseTableData(logicalDatabases)
+	return watchOutput(ctx, os.Stdout, flags.watchOptions, func(w io.Writer) error {
+		logicalDatabases, err := client.GetLogicalDatabases(&model.GetLogicalDatabasesRequest{
+			MultitenantDatabaseID: flags.multitenantDatabaseID,
+			Paging:                paging,
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to query logical databases")
 		}
 
-		printTable(keys, vals)
-		return nil
-	}
+		return logicalDatabasePrinter.printList(w, flags.tableOptions, logicalDatabases)
+	})
+}
 
-	return printJSON(logicalDatabases)
+var logicalDatabasePrinter = resourcePrinter[*model.LogicalDatabase]{
+	defaultTable: defaultLogicalDatabaseTableData,
 }
 
 func defaultLogicalDatabaseTableData(logicalDatabases []*model.LogicalDatabase) ([]string, [][]string) {
@@ -294,15 +270,17 @@ func newCmdDatabaseLogicalGet() *cobra.Command {
 			command.SilenceUsage = true
 			client := createClient(command.Context(), flags.clusterFlags)
 
-			logicalDatabase, err := client.GetLogicalDatabase(flags.logicalDatabaseID)
-			if err != nil {
-				return errors.Wrap(err, "failed to query logical database")
-			}
-			if logicalDatabase == nil {
-				return nil
-			}
+			return watchOutput(command.Context(), os.Stdout, flags.watchOptions, func(w io.Writer) error {
+				logicalDatabase, err := client.GetLogicalDatabase(flags.logicalDatabaseID)
+				if err != nil {
+					return errors.Wrap(err, "failed to query logical database")
+				}
+				if logicalDatabase == nil {
+					return nil
+				}
 
-			return printJSON(logicalDatabase)
+				return logicalDatabasePrinter.printObject(w, flags.tableOptions, logicalDatabase)
+			})
 		},
 		PreRun: func(cmd *cobra.Command, args []string) {
 			flags.clusterFlags.addFlags(cmd)
@@ -376,37 +354,22 @@ func executeDatabaseSchemaListCmd(ctx context.Context, flags databaseSchemaListF
 
 	paging := getPaging(flags.pagingFlags)
 
-	databaseSchemas, err := client.GetDatabaseSchemas(&model.GetDatabaseSchemaRequest{
-		LogicalDatabaseID: flags.logicalDatabaseID,
-		InstallationID:    flags.installationID,
-		Paging:            paging,
-	})
-	if err != nil {
-		return errors.Wrap(err, "failed to query database schemas")
-	}
-
-	if enabled, customCols := getTableOutputOption(flags.tableOptions); enabled {
-		var keys []string
-		var vals [][]string
-
-		if len(customCols) > 0 {
-			data := make([]interface{}, 0, len(databaseSchemas))
-			for _, dbs := range databaseSchemas {
-				data = append(data, dbs)
-			}
-			keys, vals, err = prepareTableData(customCols, data)
-			if err != nil {
-				return errors.Wrap(err, "failed to prepare table output")
-			}
-		} else {
-			keys, vals = defaultDatabaseSchemaTableData(databaseSchemas)
+	return watchOutput(ctx, os.Stdout, flags.watchOptions, func(w io.Writer) error {
+		databaseSchemas, err := client.GetDatabaseSchemas(&model.GetDatabaseSchemaRequest{
+			LogicalDatabaseID: flags.logicalDatabaseID,
+			InstallationID:    flags.installationID,
+			Paging:            paging,
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to query database schemas")
 		}
 
-		printTable(keys, vals)
-		return nil
-	}
+		return databaseSchemaPrinter.printList(w, flags.tableOptions, databaseSchemas)
+	})
+}
 
-	return printJSON(databaseSchemas)
+var databaseSchemaPrinter = resourcePrinter[*model.DatabaseSchema]{
+	defaultTable: defaultDatabaseSchemaTableData,
 }
 
 func defaultDatabaseSchemaTableData(databaseSchemas []*model.DatabaseSchema) ([]string, [][]string) {
@@ -428,15 +391,17 @@ func newCmdDatabaseSchemaGet() *cobra.Command {
 			command.SilenceUsage = true
 			client := createClient(command.Context(), flags.clusterFlags)
 
-			databaseSchema, err := client.GetDatabaseSchema(flags.databaseSchemaID)
-			if err != nil {
-				return errors.Wrap(err, "failed to query database schema")
-			}
-			if databaseSchema == nil {
-				return nil
-			}
+			return watchOutput(command.Context(), os.Stdout, flags.watchOptions, func(w io.Writer) error {
+				databaseSchema, err := client.GetDatabaseSchema(flags.databaseSchemaID)
+				if err != nil {
+					return errors.Wrap(err, "failed to query database schema")
+				}
+				if databaseSchema == nil {
+					return nil
+				}
 
-			return printJSON(databaseSchema)
+				return databaseSchemaPrinter.printObject(w, flags.tableOptions, databaseSchema)
+			})
 		},
 		PreRun: func(cmd *cobra.Command, args []string) {
 			flags.clusterFlags.addFlags(cmd)
@@ -598,3 +563,248 @@ func executeMultiTenantDatabaseReportCmd(ctx context.Context, flags databaseMult
 
 	return nil
 }
+
+func newCmdDatabaseMultitenantCapacity() *cobra.Command {
+	var flags databaseMultiTenantCapacityFlag
+
+	cmd := &cobra.Command{
+		Use:   "capacity",
+		Short: "Show the capacity and growth forecast of multitenant databases.",
+		RunE: func(command *cobra.Command, args []string) error {
+			command.SilenceUsage = true
+			return executeDatabaseMultitenantCapacityCmd(command.Context(), flags)
+		},
+		PreRun: func(cmd *cobra.Command, args []string) {
+			flags.clusterFlags.addFlags(cmd)
+		},
+	}
+
+	flags.addFlags(cmd)
+
+	return cmd
+}
+
+func executeDatabaseMultitenantCapacityCmd(ctx context.Context, flags databaseMultiTenantCapacityFlag) error {
+	client := createClient(ctx, flags.clusterFlags)
+
+	return watchOutput(ctx, os.Stdout, flags.watchOptions, func(w io.Writer) error {
+		capacities, err := client.GetMultitenantDatabasesCapacity(&model.GetMultitenantDatabasesCapacityRequest{
+			VpcID:        flags.vpcID,
+			DatabaseType: flags.databaseType,
+			LookbackDays: flags.lookbackDays,
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to query multitenant database capacity")
+		}
+
+		return multitenantDatabaseCapacityPrinter.printList(w, flags.tableOptions, capacities)
+	})
+}
+
+var multitenantDatabaseCapacityPrinter = resourcePrinter[*model.MultitenantDatabaseCapacity]{
+	defaultTable: defaultMultitenantDatabaseCapacityTableData,
+}
+
+func defaultMultitenantDatabaseCapacityTableData(capacities []*model.MultitenantDatabaseCapacity) ([]string, [][]string) {
+	keys := []string{"ID", "TYPE", "INSTALLATIONS", "WEIGHT", "MAX WEIGHT", "HEADROOM", "UTILIZATION", "GROWTH/DAY", "EXHAUSTION"}
+	vals := make([][]string, 0, len(capacities))
+	for _, capacity := range capacities {
+		vals = append(vals, []string{
+			capacity.MultitenantDatabaseID,
+			capacity.DatabaseType,
+			fmt.Sprintf("%d", capacity.Installations),
+			fmt.Sprintf("%.2f", capacity.Weight),
+			fmt.Sprintf("%.0f", capacity.MaxWeight),
+			fmt.Sprintf("%.2f", capacity.Headroom),
+			fmt.Sprintf("%.1f%%", capacity.Utilization*100),
+			fmt.Sprintf("%.2f", capacity.WeightGrowthPerDay),
+			capacity.ProjectedExhaustionDateString(),
+		})
+	}
+	return keys, vals
+}
+
+func newCmdDatabaseMultitenantRebalance() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rebalance",
+		Short: "Plan, apply and view multitenant database rebalances.",
+	}
+
+	cmd.AddCommand(newCmdDatabaseMultitenantRebalancePlan())
+	cmd.AddCommand(newCmdDatabaseMultitenantRebalanceApply())
+	cmd.AddCommand(newCmdDatabaseMultitenantRebalanceList())
+	cmd.AddCommand(newCmdDatabaseMultitenantRebalanceGet())
+
+	return cmd
+}
+
+func newCmdDatabaseMultitenantRebalancePlan() *cobra.Command {
+	var flags databaseMultiTenantRebalancePlanFlag
+
+	cmd := &cobra.Command{
+		Use:   "plan",
+		Short: "Show the installation moves required to rebalance the multitenant databases of a VPC.",
+		RunE: func(command *cobra.Command, args []string) error {
+			command.SilenceUsage = true
+			return executeDatabaseMultitenantRebalancePlanCmd(command.Context(), flags)
+		},
+		PreRun: func(cmd *cobra.Command, args []string) {
+			flags.clusterFlags.addFlags(cmd)
+		},
+	}
+
+	flags.addFlags(cmd)
+
+	return cmd
+}
+
+func executeDatabaseMultitenantRebalancePlanCmd(ctx context.Context, flags databaseMultiTenantRebalancePlanFlag) error {
+	client := createClient(ctx, flags.clusterFlags)
+
+	plan, err := client.GetMultitenantDatabaseRebalancePlan(&model.MultitenantDatabaseRebalanceRequest{
+		VpcID:        flags.vpcID,
+		DatabaseType: flags.databaseType,
+		MaxMoves:     flags.maxMoves,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to get multitenant database rebalance plan")
+	}
+
+	// Table formats print the moves of the plan.
+	return multitenantDatabaseRebalanceMovePrinter.print(os.Stdout, flags.tableOptions, plan.Moves, plan)
+}
+
+func newCmdDatabaseMultitenantRebalanceApply() *cobra.Command {
+	var flags databaseMultiTenantRebalanceApplyFlag
+
+	cmd := &cobra.Command{
+		Use:   "apply",
+		Short: "Approve a rebalance of the multitenant databases of a VPC and start migrating installations.",
+		RunE: func(command *cobra.Command, args []string) error {
+			command.SilenceUsage = true
+			client := createClient(command.Context(), flags.clusterFlags)
+
+			request := &model.MultitenantDatabaseRebalanceRequest{
+				VpcID:                   flags.vpcID,
+				DatabaseType:            flags.databaseType,
+				MaxMoves:                flags.maxMoves,
+				MaxConcurrentMigrations: flags.maxConcurrentMigrations,
+			}
+
+			if flags.dryRun {
+				return runDryRun(request)
+			}
+
+			rebalance, err := client.RebalanceMultitenantDatabases(request)
+			if err != nil {
+				return errors.Wrap(err, "failed to rebalance multitenant databases")
+			}
+
+			return printJSON(rebalance)
+		},
+		PreRun: func(cmd *cobra.Command, args []string) {
+			flags.clusterFlags.addFlags(cmd)
+		},
+	}
+
+	flags.addFlags(cmd)
+
+	return cmd
+}
+
+func newCmdDatabaseMultitenantRebalanceList() *cobra.Command {
+	var flags databaseMultiTenantRebalanceListFlag
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List multitenant database rebalances.",
+		RunE: func(command *cobra.Command, args []string) error {
+			command.SilenceUsage = true
+			return executeDatabaseMultitenantRebalanceListCmd(command.Context(), flags)
+		},
+		PreRun: func(cmd *cobra.Command, args []string) {
+			flags.clusterFlags.addFlags(cmd)
+		},
+	}
+
+	flags.addFlags(cmd)
+
+	return cmd
+}
+
+func executeDatabaseMultitenantRebalanceListCmd(ctx context.Context, flags databaseMultiTenantRebalanceListFlag) error {
+	client := createClient(ctx, flags.clusterFlags)
+
+	paging := getPaging(flags.pagingFlags)
+
+	return watchOutput(ctx, os.Stdout, flags.watchOptions, func(w io.Writer) error {
+		rebalances, err := client.GetMultitenantDatabaseRebalances(&model.GetMultitenantDatabaseRebalancesRequest{
+			VpcID:  flags.vpcID,
+			State:  flags.state,
+			Paging: paging,
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to query multitenant database rebalances")
+		}
+
+		return multitenantDatabaseRebalancePrinter.printList(w, flags.tableOptions, rebalances)
+	})
+}
+
+var multitenantDatabaseRebalancePrinter = resourcePrinter[*model.MultitenantDatabaseRebalance]{
+	defaultTable: defaultMultitenantDatabaseRebalanceTableData,
+}
+
+func defaultMultitenantDatabaseRebalanceTableData(rebalances []*model.MultitenantDatabaseRebalance) ([]string, [][]string) {
+	keys := []string{"ID", "VPC ID", "STATE", "MOVES", "REQUEST AT"}
+	vals := make([][]string, 0, len(rebalances))
+	for _, rebalance := range rebalances {
+		vals = append(vals, []string{rebalance.ID, rebalance.VpcID, string(rebalance.State), fmt.Sprintf("%d", len(rebalance.Moves)), model.DateStringFromMillis(rebalance.RequestAt)})
+	}
+	return keys, vals
+}
+
+var multitenantDatabaseRebalanceMovePrinter = resourcePrinter[*model.MultitenantDatabaseRebalanceMove]{
+	defaultTable: defaultMultitenantDatabaseRebalanceMoveTableData,
+}
+
+func defaultMultitenantDatabaseRebalanceMoveTableData(moves []*model.MultitenantDatabaseRebalanceMove) ([]string, [][]string) {
+	keys := []string{"INSTALLATION", "SOURCE DATABASE", "DESTINATION DATABASE", "WEIGHT"}
+	vals := make([][]string, 0, len(moves))
+	for _, move := range moves {
+		vals = append(vals, []string{move.InstallationID, move.SourceDatabaseID, move.DestinationDatabaseID, fmt.Sprintf("%.2f", move.Weight)})
+	}
+	return keys, vals
+}
+
+func newCmdDatabaseMultitenantRebalanceGet() *cobra.Command {
+	var flags databaseMultiTenantRebalanceGetFlag
+
+	cmd := &cobra.Command{
+		Use:   "get",
+		Short: "Get a particular multitenant database rebalance.",
+		RunE: func(command *cobra.Command, args []string) error {
+			command.SilenceUsage = true
+			client := createClient(command.Context(), flags.clusterFlags)
+
+			return watchOutput(command.Context(), os.Stdout, flags.watchOptions, func(w io.Writer) error {
+				rebalance, err := client.GetMultitenantDatabaseRebalance(flags.rebalanceID)
+				if err != nil {
+					return errors.Wrap(err, "failed to query multitenant database rebalance")
+				}
+				if rebalance == nil {
+					return nil
+				}
+
+				return multitenantDatabaseRebalancePrinter.printObject(w, flags.tableOptions, rebalance)
+			})
+		},
+		PreRun: func(cmd *cobra.Command, args []string) {
+			flags.clusterFlags.addFlags(cmd)
+		},
+	}
+
+	flags.addFlags(cmd)
+
+	return cmd
+}
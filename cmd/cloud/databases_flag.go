@@ -4,12 +4,16 @@
 
 package main
 
-import "github.com/spf13/cobra"
+import (
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/spf13/cobra"
+)
 
 type databaseMultiTenantListFlag struct {
 	clusterFlags
 	pagingFlags
 	tableOptions
+	watchOptions
 
 	vpcID        string
 	databaseType string
@@ -18,16 +22,21 @@ type databaseMultiTenantListFlag struct {
 func (flags *databaseMultiTenantListFlag) addFlags(command *cobra.Command) {
 	flags.pagingFlags.addFlags(command)
 	flags.tableOptions.addFlags(command)
+	flags.watchOptions.addFlags(command)
 	command.Flags().StringVar(&flags.vpcID, "vpc-id", "", "The VPC ID by which to filter multitenant databases.")
 	command.Flags().StringVar(&flags.databaseType, "database-type", "", "The database type by which to filter multitenant databases.")
 }
 
 type databaseMultiTenantGetFlag struct {
 	clusterFlags
+	tableOptions
+	watchOptions
 	multitenantDatabaseID string
 }
 
 func (flags *databaseMultiTenantGetFlag) addFlags(command *cobra.Command) {
+	flags.tableOptions.addFlags(command)
+	flags.watchOptions.addFlags(command)
 	command.Flags().StringVar(&flags.multitenantDatabaseID, "multitenant-database", "", "The id of the multitenant database to be fetched.")
 	_ = command.MarkFlagRequired("multitenant-database")
 }
@@ -77,25 +86,111 @@ func (flags *databaseMultiTenantReportFlag) addFlags(command *cobra.Command) {
 	_ = command.MarkFlagRequired("multitenant-database")
 }
 
+type databaseMultiTenantCapacityFlag struct {
+	clusterFlags
+	tableOptions
+	watchOptions
+	vpcID        string
+	databaseType string
+	lookbackDays int
+}
+
+func (flags *databaseMultiTenantCapacityFlag) addFlags(command *cobra.Command) {
+	flags.tableOptions.addFlags(command)
+	flags.watchOptions.addFlags(command)
+	command.Flags().StringVar(&flags.vpcID, "vpc-id", "", "The VPC ID by which to filter multitenant databases.")
+	command.Flags().StringVar(&flags.databaseType, "database-type", "", "The database type by which to filter multitenant databases.")
+	command.Flags().IntVar(&flags.lookbackDays, "lookback-days", model.DefaultCapacityLookbackDays, "The number of days of installation creation history used to forecast growth.")
+}
+
+type databaseMultiTenantRebalancePlanFlag struct {
+	clusterFlags
+	tableOptions
+	vpcID        string
+	databaseType string
+	maxMoves     int
+}
+
+func (flags *databaseMultiTenantRebalancePlanFlag) addFlags(command *cobra.Command) {
+	flags.tableOptions.addFlags(command)
+	command.Flags().StringVar(&flags.vpcID, "vpc-id", "", "The VPC ID of the multitenant databases to rebalance.")
+	command.Flags().StringVar(&flags.databaseType, "database-type", model.DatabaseEngineTypePostgres, "The database type of the multitenant databases to rebalance.")
+	command.Flags().IntVar(&flags.maxMoves, "max-moves", 0, "The maximum number of installation moves to plan. Set to 0 for no limit.")
+	_ = command.MarkFlagRequired("vpc-id")
+}
+
+type databaseMultiTenantRebalanceApplyFlag struct {
+	clusterFlags
+	vpcID                   string
+	databaseType            string
+	maxMoves                int
+	maxConcurrentMigrations int
+}
+
+func (flags *databaseMultiTenantRebalanceApplyFlag) addFlags(command *cobra.Command) {
+	command.Flags().StringVar(&flags.vpcID, "vpc-id", "", "The VPC ID of the multitenant databases to rebalance.")
+	command.Flags().StringVar(&flags.databaseType, "database-type", model.DatabaseEngineTypePostgres, "The database type of the multitenant databases to rebalance.")
+	command.Flags().IntVar(&flags.maxMoves, "max-moves", 0, "The maximum number of installation moves to perform. Set to 0 for no limit.")
+	command.Flags().IntVar(&flags.maxConcurrentMigrations, "max-concurrent-migrations", model.DefaultMaxConcurrentRebalanceMigrations, "The maximum number of installation database migrations running at the same time.")
+	_ = command.MarkFlagRequired("vpc-id")
+}
+
+type databaseMultiTenantRebalanceListFlag struct {
+	clusterFlags
+	pagingFlags
+	tableOptions
+	watchOptions
+	vpcID string
+	state string
+}
+
+func (flags *databaseMultiTenantRebalanceListFlag) addFlags(command *cobra.Command) {
+	flags.pagingFlags.addFlags(command)
+	flags.tableOptions.addFlags(command)
+	flags.watchOptions.addFlags(command)
+	command.Flags().StringVar(&flags.vpcID, "vpc-id", "", "The VPC ID by which to filter multitenant database rebalances.")
+	command.Flags().StringVar(&flags.state, "state", "", "The state by which to filter multitenant database rebalances.")
+}
+
+type databaseMultiTenantRebalanceGetFlag struct {
+	clusterFlags
+	tableOptions
+	watchOptions
+	rebalanceID string
+}
+
+func (flags *databaseMultiTenantRebalanceGetFlag) addFlags(command *cobra.Command) {
+	flags.tableOptions.addFlags(command)
+	flags.watchOptions.addFlags(command)
+	command.Flags().StringVar(&flags.rebalanceID, "rebalance", "", "The id of the multitenant database rebalance to be fetched.")
+	_ = command.MarkFlagRequired("rebalance")
+}
+
 type databaseLogicalListFlag struct {
 	clusterFlags
 	pagingFlags
 	tableOptions
+	watchOptions
 	multitenantDatabaseID string
 }
 
 func (flags *databaseLogicalListFlag) addFlags(command *cobra.Command) {
 	flags.pagingFlags.addFlags(command)
 	flags.tableOptions.addFlags(command)
+	flags.watchOptions.addFlags(command)
 	command.Flags().StringVar(&flags.multitenantDatabaseID, "multitenant-database-id", "", "The multitenant database ID by which to filter logical databases.")
 }
 
 type databaseLogicalGetFlag struct {
 	clusterFlags
+	tableOptions
+	watchOptions
 	logicalDatabaseID string
 }
 
 func (flags *databaseLogicalGetFlag) addFlags(command *cobra.Command) {
+	flags.tableOptions.addFlags(command)
+	flags.watchOptions.addFlags(command)
 	command.Flags().StringVar(&flags.logicalDatabaseID, "logical-database", "", "The id of the logical database to be fetched.")
 	_ = command.MarkFlagRequired("logical-database")
 }
@@ -114,6 +209,7 @@ type databaseSchemaListFlag struct {
 	clusterFlags
 	pagingFlags
 	tableOptions
+	watchOptions
 	logicalDatabaseID string
 	installationID    string
 }
@@ -121,16 +217,21 @@ type databaseSchemaListFlag struct {
 func (flags *databaseSchemaListFlag) addFlags(command *cobra.Command) {
 	flags.pagingFlags.addFlags(command)
 	flags.tableOptions.addFlags(command)
+	flags.watchOptions.addFlags(command)
 	command.Flags().StringVar(&flags.logicalDatabaseID, "logical-database-id", "", "The logical database ID by which to filter database schemas.")
 	command.Flags().StringVar(&flags.installationID, "installation-id", "", "The installation ID by which to filter database schemas.")
 }
 
 type databaseSchemaGetFlag struct {
 	clusterFlags
+	tableOptions
+	watchOptions
 	databaseSchemaID string
 }
 
 func (flags *databaseSchemaGetFlag) addFlags(command *cobra.Command) {
+	flags.tableOptions.addFlags(command)
+	flags.watchOptions.addFlags(command)
 	command.Flags().StringVar(&flags.databaseSchemaID, "database-schema", "", "The id of the database schema to be fetched.")
 	_ = command.MarkFlagRequired("database-schema")
 }
@@ -6,9 +6,10 @@ package main
 
 import (
 	"context"
+	"io"
+	"os"
 
 	"github.com/mattermost/mattermost-cloud/model"
-	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
 
@@ -62,40 +63,27 @@ func executeStateChangeEventListCmd(ctx context.Context, flags stateChangeEventL
 
 	paging := getPaging(flags.pagingFlags)
 
-	req := model.ListStateChangeEventsRequest{
-		Paging:       paging,
-		ResourceType: model.ResourceType(flags.resourceType),
-		ResourceID:   flags.resourceID,
-	}
-
-	events, err := client.ListStateChangeEvents(&req)
-	if err != nil {
-		return err
-	}
-
-	if enabled, customCols := getTableOutputOption(flags.tableOptions); enabled {
-		var keys []string
-		var vals [][]string
-
-		if len(customCols) > 0 {
-			data := make([]interface{}, 0, len(events))
-			for _, elem := range events {
-				data = append(data, elem)
-			}
-			keys, vals, err = prepareTableData(customCols, data)
-			if err != nil {
-				return errors.Wrap(err, "failed to prepare table output")
-			}
-		} else {
-			keys, vals = defaultEventsTableData(events)
+	return watchOutput(ctx, os.Stdout, flags.watchOptions, func(w io.Writer) error {
+		req := model.ListStateChangeEventsRequest{
+			Paging:       paging,
+			ResourceType: model.ResourceType(flags.resourceType),
+			ResourceID:   flags.resourceID,
 		}
 
-		printTable(keys, vals)
-		return nil
-	}
+		events, err := client.ListStateChangeEvents(&req)
+		if err != nil {
+			return err
+		}
 
-	return printJSON(events)
+		return eventPrinter.printList(w, flags.tableOptions, events)
+	})
+}
 
+var eventPrinter = resourcePrinter[*model.StateChangeEventData]{
+	defaultTable: defaultEventsTableData,
+	id: func(event *model.StateChangeEventData) string {
+		return event.Event.ID
+	},
 }
 
 func defaultEventsTableData(events []*model.StateChangeEventData) ([]string, [][]string) {
@@ -19,6 +19,7 @@ type stateChangeEventListFlags struct {
 	eventFlags
 	pagingFlags
 	tableOptions
+	watchOptions
 	resourceType string
 	resourceID   string
 }
@@ -26,6 +27,7 @@ type stateChangeEventListFlags struct {
 func (flags *stateChangeEventListFlags) addFlags(command *cobra.Command) {
 	flags.pagingFlags.addFlags(command)
 	flags.tableOptions.addFlags(command)
+	flags.watchOptions.addFlags(command)
 	command.Flags().StringVar(&flags.resourceType, "resource-type", "", "Type of a resource for which to list events.")
 	command.Flags().StringVar(&flags.resourceID, "resource-id", "", "ID of a resource for which to list events.")
 }
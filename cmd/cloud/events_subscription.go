@@ -5,6 +5,11 @@
 package main
 
 import (
+	"io"
+	"os"
+	"strconv"
+	"time"
+
 	"github.com/mattermost/mattermost-cloud/model"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
@@ -22,6 +27,11 @@ func newCmdSubscription() *cobra.Command {
 	cmd.AddCommand(newCmdSubscriptionList())
 	cmd.AddCommand(newCmdSubscriptionGet())
 	cmd.AddCommand(newCmdSubscriptionDelete())
+	cmd.AddCommand(newCmdSubscriptionStatus())
+	cmd.AddCommand(newCmdSubscriptionDeliveries())
+	cmd.AddCommand(newCmdSubscriptionReplay())
+	cmd.AddCommand(newCmdSubscriptionPause())
+	cmd.AddCommand(newCmdSubscriptionResume())
 
 	return cmd
 }
@@ -64,6 +74,9 @@ func newCmdSubscriptionCreate() *cobra.Command {
 				EventType:        model.EventType(flags.eventType),
 				FailureThreshold: flags.failureThreshold,
 				Headers:          headers,
+				Filter:           flags.filterFlags.toSubscriptionFilter(),
+				PayloadFormat:    model.SubscriptionPayloadFormat(flags.payloadFormat),
+				SinkType:         model.SubscriptionSinkType(flags.sinkType),
 			}
 
 			if flags.dryRun {
@@ -100,39 +113,20 @@ func newCmdSubscriptionList() *cobra.Command {
 
 			paging := getPaging(flags.pagingFlags)
 
-			request := &model.ListSubscriptionsRequest{
-				Paging:    paging,
-				Owner:     flags.owner,
-				EventType: model.EventType(flags.eventType),
-			}
-
-			subscriptions, err := client.ListSubscriptions(request)
-			if err != nil {
-				return errors.Wrap(err, "failed to get backup")
-			}
-
-			if enabled, customCols := getTableOutputOption(flags.tableOptions); enabled {
-				var keys []string
-				var vals [][]string
-
-				if len(customCols) > 0 {
-					data := make([]interface{}, 0, len(subscriptions))
-					for _, elem := range subscriptions {
-						data = append(data, elem)
-					}
-					keys, vals, err = prepareTableData(customCols, data)
-					if err != nil {
-						return errors.Wrap(err, "failed to prepare table output")
-					}
-				} else {
-					keys, vals = defaultSubscriptionsTableData(subscriptions)
+			return watchOutput(command.Context(), os.Stdout, flags.watchOptions, func(w io.Writer) error {
+				request := &model.ListSubscriptionsRequest{
+					Paging:    paging,
+					Owner:     flags.owner,
+					EventType: model.EventType(flags.eventType),
 				}
 
-				printTable(keys, vals)
-				return nil
-			}
+				subscriptions, err := client.ListSubscriptions(request)
+				if err != nil {
+					return errors.Wrap(err, "failed to get backup")
+				}
 
-			return printJSON(subscriptions)
+				return subscriptionPrinter.printList(w, flags.tableOptions, subscriptions)
+			})
 		},
 		PreRun: func(cmd *cobra.Command, args []string) {
 			flags.clusterFlags.addFlags(cmd)
@@ -144,17 +138,23 @@ func newCmdSubscriptionList() *cobra.Command {
 	return cmd
 }
 
+var subscriptionPrinter = resourcePrinter[*model.Subscription]{
+	defaultTable: defaultSubscriptionsTableData,
+}
+
 func defaultSubscriptionsTableData(subscriptions []*model.Subscription) ([]string, [][]string) {
-	keys := []string{"ID", "EVENT TYPE", "OWNER", "LAST DELIVERY ATTEMPT", "LAST DELIVERY STATUS"}
+	keys := []string{"ID", "EVENT TYPE", "SINK", "OWNER", "LAST DELIVERY ATTEMPT", "LAST DELIVERY STATUS", "PAUSED"}
 	vals := make([][]string, 0, len(subscriptions))
 
 	for _, sub := range subscriptions {
 		vals = append(vals, []string{
 			sub.ID,
 			string(sub.EventType),
+			string(sub.SinkType),
 			sub.OwnerID,
 			model.TimeFromMillis(sub.LastDeliveryAttemptAt).Format("2006-01-02 15:04:05 -0700 MST"),
 			string(sub.LastDeliveryStatus),
+			strconv.FormatBool(sub.Paused),
 		})
 	}
 
@@ -171,12 +171,14 @@ func newCmdSubscriptionGet() *cobra.Command {
 			command.SilenceUsage = true
 			client := createClient(command.Context(), flags.clusterFlags)
 
-			subscription, err := client.GetSubscription(flags.subID)
-			if err != nil {
-				return errors.Wrap(err, "failed to get subscription")
-			}
+			return watchOutput(command.Context(), os.Stdout, flags.watchOptions, func(w io.Writer) error {
+				subscription, err := client.GetSubscription(flags.subID)
+				if err != nil {
+					return errors.Wrap(err, "failed to get subscription")
+				}
 
-			return printJSON(subscription)
+				return subscriptionPrinter.printObject(w, flags.tableOptions, subscription)
+			})
 		},
 		PreRun: func(cmd *cobra.Command, args []string) {
 			flags.clusterFlags.addFlags(cmd)
@@ -212,3 +214,256 @@ func newCmdSubscriptionDelete() *cobra.Command {
 
 	return cmd
 }
+
+func newCmdSubscriptionStatus() *cobra.Command {
+	var flags subscriptionStatusFlags
+
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show event delivery status of subscriptions.",
+		RunE: func(command *cobra.Command, args []string) error {
+			command.SilenceUsage = true
+			client := createClient(command.Context(), flags.clusterFlags)
+
+			return watchOutput(command.Context(), os.Stdout, flags.watchOptions, func(w io.Writer) error {
+				subIDs := []string{flags.subID}
+				if flags.subID == "" {
+					subscriptions, err := client.ListSubscriptions(&model.ListSubscriptionsRequest{
+						Paging:    getPaging(flags.pagingFlags),
+						Owner:     flags.owner,
+						EventType: model.EventType(flags.eventType),
+					})
+					if err != nil {
+						return errors.Wrap(err, "failed to list subscriptions")
+					}
+					subIDs = make([]string, 0, len(subscriptions))
+					for _, sub := range subscriptions {
+						subIDs = append(subIDs, sub.ID)
+					}
+				}
+
+				stats := make([]*model.SubscriptionStats, 0, len(subIDs))
+				for _, subID := range subIDs {
+					subStats, err := client.GetSubscriptionStats(subID)
+					if err != nil {
+						return errors.Wrapf(err, "failed to get stats of subscription %s", subID)
+					}
+					if subStats == nil {
+						return errors.Errorf("subscription %s not found", subID)
+					}
+					stats = append(stats, subStats)
+				}
+
+				if flags.subID != "" {
+					return subscriptionStatsPrinter.printObject(w, flags.tableOptions, stats[0])
+				}
+				return subscriptionStatsPrinter.printList(w, flags.tableOptions, stats)
+			})
+		},
+		PreRun: func(cmd *cobra.Command, args []string) {
+			flags.clusterFlags.addFlags(cmd)
+		},
+	}
+
+	flags.addFlags(cmd)
+
+	return cmd
+}
+
+var subscriptionStatsPrinter = resourcePrinter[*model.SubscriptionStats]{
+	defaultTable: func(stats []*model.SubscriptionStats) ([]string, [][]string) {
+		return defaultSubscriptionStatusTableData(stats, model.GetMillis())
+	},
+	id: func(stats *model.SubscriptionStats) string {
+		return stats.SubscriptionID
+	},
+}
+
+func defaultSubscriptionStatusTableData(stats []*model.SubscriptionStats, now int64) ([]string, [][]string) {
+	keys := []string{"ID", "PAUSED", "LAST DELIVERY STATUS", "LAST DELIVERY ATTEMPT", "BACKLOG", "RETRYING", "DELIVERED", "FAILED", "OLDEST UNDELIVERED"}
+	vals := make([][]string, 0, len(stats))
+
+	for _, s := range stats {
+		oldestUndelivered := ""
+		if s.OldestUndeliveredEventAt != 0 {
+			oldestUndelivered = s.OldestUndeliveredEventAge(now).Round(time.Second).String()
+		}
+		vals = append(vals, []string{
+			s.SubscriptionID,
+			strconv.FormatBool(s.Paused),
+			string(s.LastDeliveryStatus),
+			model.TimeFromMillis(s.LastDeliveryAttemptAt).Format("2006-01-02 15:04:05 -0700 MST"),
+			strconv.FormatInt(s.Backlog, 10),
+			strconv.FormatInt(s.Retrying, 10),
+			strconv.FormatInt(s.Delivered, 10),
+			strconv.FormatInt(s.Failed, 10),
+			oldestUndelivered,
+		})
+	}
+
+	return keys, vals
+}
+
+func newCmdSubscriptionDeliveries() *cobra.Command {
+	var flags subscriptionDeliveriesFlags
+
+	cmd := &cobra.Command{
+		Use:   "deliveries",
+		Short: "List event deliveries of the subscription.",
+		RunE: func(command *cobra.Command, args []string) error {
+			command.SilenceUsage = true
+			client := createClient(command.Context(), flags.clusterFlags)
+
+			return watchOutput(command.Context(), os.Stdout, flags.watchOptions, func(w io.Writer) error {
+				request := &model.ListSubscriptionDeliveriesRequest{
+					Paging: getPaging(flags.pagingFlags),
+					Status: model.EventDeliveryStatus(flags.status),
+				}
+
+				deliveries, err := client.ListSubscriptionDeliveries(flags.subID, request)
+				if err != nil {
+					return errors.Wrap(err, "failed to list subscription deliveries")
+				}
+
+				return subscriptionDeliveryPrinter.printList(w, flags.tableOptions, deliveries)
+			})
+		},
+		PreRun: func(cmd *cobra.Command, args []string) {
+			flags.clusterFlags.addFlags(cmd)
+		},
+	}
+
+	flags.addFlags(cmd)
+
+	return cmd
+}
+
+var subscriptionDeliveryPrinter = resourcePrinter[*model.StateChangeEventDeliveryData]{
+	defaultTable: defaultSubscriptionDeliveriesTableData,
+	id: func(delivery *model.StateChangeEventDeliveryData) string {
+		return delivery.EventData.Event.ID
+	},
+}
+
+func defaultSubscriptionDeliveriesTableData(deliveries []*model.StateChangeEventDeliveryData) ([]string, [][]string) {
+	keys := []string{"EVENT", "TIMESTAMP", "RESOURCE TYPE", "RESOURCE ID", "NEW STATE", "STATUS", "ATTEMPTS"}
+	vals := make([][]string, 0, len(deliveries))
+
+	for _, delivery := range deliveries {
+		vals = append(vals, []string{
+			delivery.EventData.Event.ID,
+			model.TimeFromMillis(delivery.EventData.Event.Timestamp).Format("2006-01-02 15:04:05 -0700 MST"),
+			string(delivery.EventData.StateChange.ResourceType),
+			delivery.EventData.StateChange.ResourceID,
+			delivery.EventData.StateChange.NewState,
+			string(delivery.EventDelivery.Status),
+			strconv.Itoa(delivery.EventDelivery.Attempts),
+		})
+	}
+
+	return keys, vals
+}
+
+func newCmdSubscriptionReplay() *cobra.Command {
+	var flags subscriptionReplayFlags
+
+	cmd := &cobra.Command{
+		Use:   "replay",
+		Short: "Replay event deliveries of the subscription for specific events or a time range.",
+		RunE: func(command *cobra.Command, args []string) error {
+			command.SilenceUsage = true
+			client := createClient(command.Context(), flags.clusterFlags)
+
+			request := &model.ReplaySubscriptionDeliveriesRequest{
+				EventIDs: flags.eventIDs,
+			}
+			if flags.from != "" {
+				from, err := time.Parse(time.RFC3339, flags.from)
+				if err != nil {
+					return errors.Wrap(err, "failed to parse from time")
+				}
+				to := time.Now()
+				if flags.to != "" {
+					to, err = time.Parse(time.RFC3339, flags.to)
+					if err != nil {
+						return errors.Wrap(err, "failed to parse to time")
+					}
+				}
+				request.From = model.GetMillisAtTime(from)
+				request.To = model.GetMillisAtTime(to)
+			}
+
+			if err := request.Validate(); err != nil {
+				return errors.Wrap(err, "invalid replay request")
+			}
+
+			response, err := client.ReplaySubscriptionDeliveries(flags.subID, request)
+			if err != nil {
+				return errors.Wrap(err, "failed to replay subscription deliveries")
+			}
+
+			return printJSON(response)
+		},
+		PreRun: func(cmd *cobra.Command, args []string) {
+			flags.clusterFlags.addFlags(cmd)
+		},
+	}
+
+	flags.addFlags(cmd)
+
+	return cmd
+}
+
+func newCmdSubscriptionPause() *cobra.Command {
+	var flags subscriptionPauseFlags
+
+	cmd := &cobra.Command{
+		Use:   "pause",
+		Short: "Pause event deliveries to the subscription.",
+		RunE: func(command *cobra.Command, args []string) error {
+			command.SilenceUsage = true
+			client := createClient(command.Context(), flags.clusterFlags)
+
+			subscription, err := client.PauseSubscription(flags.subID)
+			if err != nil {
+				return errors.Wrap(err, "failed to pause subscription")
+			}
+
+			return printJSON(subscription)
+		},
+		PreRun: func(cmd *cobra.Command, args []string) {
+			flags.clusterFlags.addFlags(cmd)
+		},
+	}
+
+	flags.addFlags(cmd)
+
+	return cmd
+}
+
+func newCmdSubscriptionResume() *cobra.Command {
+	var flags subscriptionResumeFlags
+
+	cmd := &cobra.Command{
+		Use:   "resume",
+		Short: "Resume event deliveries to the subscription.",
+		RunE: func(command *cobra.Command, args []string) error {
+			command.SilenceUsage = true
+			client := createClient(command.Context(), flags.clusterFlags)
+
+			subscription, err := client.ResumeSubscription(flags.subID)
+			if err != nil {
+				return errors.Wrap(err, "failed to resume subscription")
+			}
+
+			return printJSON(subscription)
+		},
+		PreRun: func(cmd *cobra.Command, args []string) {
+			flags.clusterFlags.addFlags(cmd)
+		},
+	}
+
+	flags.addFlags(cmd)
+
+	return cmd
+}
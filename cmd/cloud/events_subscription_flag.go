@@ -20,6 +20,45 @@ type subscriptionCreateFlags struct {
 	failureThreshold time.Duration
 	headers          map[string]string
 	headersFromEnv   map[string]string
+	payloadFormat    string
+	sinkType         string
+	filterFlags      subscriptionFilterFlags
+}
+
+type subscriptionFilterFlags struct {
+	resourceTypes []string
+	newStates     []string
+	oldStates     []string
+	ownerID       string
+	groupID       string
+	annotation    string
+}
+
+func (flags *subscriptionFilterFlags) addFlags(command *cobra.Command) {
+	command.Flags().StringSliceVar(&flags.resourceTypes, "filter-resource-type", []string{}, "Only deliver events for the given resource types. Accepts multiple values.")
+	command.Flags().StringSliceVar(&flags.newStates, "filter-new-state", []string{}, "Only deliver events transitioning to one of the given states. Accepts multiple values.")
+	command.Flags().StringSliceVar(&flags.oldStates, "filter-old-state", []string{}, "Only deliver events transitioning from one of the given states. Accepts multiple values.")
+	command.Flags().StringVar(&flags.ownerID, "filter-owner", "", "Only deliver events for installations owned by the given owner.")
+	command.Flags().StringVar(&flags.groupID, "filter-group", "", "Only deliver events for installations in the given group.")
+	command.Flags().StringVar(&flags.annotation, "filter-annotation", "", "Only deliver events for installations or clusters with the given annotation.")
+}
+
+func (flags *subscriptionFilterFlags) toSubscriptionFilter() *model.SubscriptionFilter {
+	filter := &model.SubscriptionFilter{
+		NewStates:  flags.newStates,
+		OldStates:  flags.oldStates,
+		OwnerID:    flags.ownerID,
+		GroupID:    flags.groupID,
+		Annotation: flags.annotation,
+	}
+	for _, resourceType := range flags.resourceTypes {
+		filter.ResourceTypes = append(filter.ResourceTypes, model.ResourceType(resourceType))
+	}
+	if filter.IsEmpty() {
+		return nil
+	}
+
+	return filter
 }
 
 func (flags *subscriptionCreateFlags) addFlags(command *cobra.Command) {
@@ -30,6 +69,9 @@ func (flags *subscriptionCreateFlags) addFlags(command *cobra.Command) {
 	command.Flags().DurationVar(&flags.failureThreshold, "failure-threshold", 0, "Failure threshold of the subscription.")
 	command.Flags().StringToStringVar(&flags.headers, "header", nil, "a header that should be sent with the request")
 	command.Flags().StringToStringVar(&flags.headersFromEnv, "header-from-env", nil, "a header that should be sent with the request, with values read from environment variables")
+	command.Flags().StringVar(&flags.payloadFormat, "payload-format", "", "Format of delivered events. Leave empty for provisioner payload or use cloudevents-structured or cloudevents-binary for CloudEvents 1.0.")
+	command.Flags().StringVar(&flags.sinkType, "sink", string(model.SubscriptionSinkHTTP), "Sink to which events are delivered. One of http, nats, kafka, sqs, sns or file. The URL is interpreted according to the sink, e.g. nats://host:4222/subject, kafka://broker:9092/topic, SQS queue URL, SNS topic ARN or file:///path.")
+	flags.filterFlags.addFlags(command)
 	_ = command.MarkFlagRequired("url")
 	_ = command.MarkFlagRequired("owner")
 	_ = command.MarkFlagRequired("event-type")
@@ -39,6 +81,7 @@ type subscriptionListFlags struct {
 	clusterFlags
 	pagingFlags
 	tableOptions
+	watchOptions
 	owner     string
 	eventType string
 }
@@ -46,16 +89,21 @@ type subscriptionListFlags struct {
 func (flags *subscriptionListFlags) addFlags(command *cobra.Command) {
 	flags.pagingFlags.addFlags(command)
 	flags.tableOptions.addFlags(command)
+	flags.watchOptions.addFlags(command)
 	command.Flags().StringVar(&flags.owner, "owner", "", "OwnerID of the subscription.")
 	command.Flags().StringVar(&flags.eventType, "event-type", "", "Event type of the subscription.")
 }
 
 type subscriptionGetFlags struct {
 	clusterFlags
+	tableOptions
+	watchOptions
 	subID string
 }
 
 func (flags *subscriptionGetFlags) addFlags(command *cobra.Command) {
+	flags.tableOptions.addFlags(command)
+	flags.watchOptions.addFlags(command)
 	command.Flags().StringVar(&flags.subID, "subscription", "", "ID of subscription to get")
 	_ = command.MarkFlagRequired("subscription")
 }
@@ -69,3 +117,76 @@ func (flags *subscriptionDeleteFlags) addFlags(command *cobra.Command) {
 	command.Flags().StringVar(&flags.subID, "subscription", "", "ID of subscription to delete")
 	_ = command.MarkFlagRequired("subscription")
 }
+
+type subscriptionStatusFlags struct {
+	clusterFlags
+	pagingFlags
+	tableOptions
+	watchOptions
+	subID     string
+	owner     string
+	eventType string
+}
+
+func (flags *subscriptionStatusFlags) addFlags(command *cobra.Command) {
+	flags.pagingFlags.addFlags(command)
+	flags.tableOptions.addFlags(command)
+	flags.watchOptions.addFlags(command)
+	command.Flags().StringVar(&flags.subID, "subscription", "", "ID of subscription to get the status of. If not set, the status of listed subscriptions is shown.")
+	command.Flags().StringVar(&flags.owner, "owner", "", "OwnerID of the listed subscriptions.")
+	command.Flags().StringVar(&flags.eventType, "event-type", "", "Event type of the listed subscriptions.")
+}
+
+type subscriptionDeliveriesFlags struct {
+	clusterFlags
+	pagingFlags
+	tableOptions
+	watchOptions
+	subID  string
+	status string
+}
+
+func (flags *subscriptionDeliveriesFlags) addFlags(command *cobra.Command) {
+	flags.pagingFlags.addFlags(command)
+	flags.tableOptions.addFlags(command)
+	flags.watchOptions.addFlags(command)
+	command.Flags().StringVar(&flags.subID, "subscription", "", "ID of subscription to list event deliveries for.")
+	command.Flags().StringVar(&flags.status, "status", "", "Only list event deliveries with the given status, e.g. failed.")
+	_ = command.MarkFlagRequired("subscription")
+}
+
+type subscriptionReplayFlags struct {
+	clusterFlags
+	subID    string
+	eventIDs []string
+	from     string
+	to       string
+}
+
+func (flags *subscriptionReplayFlags) addFlags(command *cobra.Command) {
+	command.Flags().StringVar(&flags.subID, "subscription", "", "ID of subscription to replay event deliveries for.")
+	command.Flags().StringSliceVar(&flags.eventIDs, "event", []string{}, "ID of event to replay. Accepts multiple values.")
+	command.Flags().StringVar(&flags.from, "from", "", "Replay events that occurred at or after the given time, in RFC3339 format.")
+	command.Flags().StringVar(&flags.to, "to", "", "Replay events that occurred at or before the given time, in RFC3339 format. Defaults to now when --from is set.")
+	_ = command.MarkFlagRequired("subscription")
+}
+
+type subscriptionPauseFlags struct {
+	clusterFlags
+	subID string
+}
+
+func (flags *subscriptionPauseFlags) addFlags(command *cobra.Command) {
+	command.Flags().StringVar(&flags.subID, "subscription", "", "ID of subscription to pause")
+	_ = command.MarkFlagRequired("subscription")
+}
+
+type subscriptionResumeFlags struct {
+	clusterFlags
+	subID string
+}
+
+func (flags *subscriptionResumeFlags) addFlags(command *cobra.Command) {
+	command.Flags().StringVar(&flags.subID, "subscription", "", "ID of subscription to resume")
+	_ = command.MarkFlagRequired("subscription")
+}
@@ -8,10 +8,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 
 	"github.com/mattermost/mattermost-cloud/model"
-	"github.com/olekukonko/tablewriter"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
@@ -199,15 +199,18 @@ func newCmdGroupGet() *cobra.Command {
 		RunE: func(command *cobra.Command, args []string) error {
 			command.SilenceUsage = true
 			client := createClient(command.Context(), flags.clusterFlags)
-			group, err := client.GetGroup(flags.groupID)
-			if err != nil {
-				return errors.Wrap(err, "failed to query group")
-			}
-			if group == nil {
-				return nil
-			}
 
-			return printJSON(group)
+			return watchOutput(command.Context(), os.Stdout, flags.watchOptions, func(w io.Writer) error {
+				group, err := client.GetGroup(flags.groupID)
+				if err != nil {
+					return errors.Wrap(err, "failed to query group")
+				}
+				if group == nil {
+					return nil
+				}
+
+				return groupPrinter.printObject(w, flags.tableOptions, group)
+			})
 		},
 		PreRun: func(cmd *cobra.Command, args []string) {
 			flags.clusterFlags.addFlags(cmd)
@@ -243,30 +246,49 @@ func executeGroupListCmd(ctx context.Context, flags groupListFlags) error {
 	client := createClient(ctx, flags.clusterFlags)
 
 	paging := getPaging(flags.pagingFlags)
-	groups, err := client.GetGroups(&model.GetGroupsRequest{
-		Paging:                paging,
-		WithInstallationCount: flags.withInstallationCount,
+
+	return watchOutput(ctx, os.Stdout, flags.watchOptions, func(w io.Writer) error {
+		groups, err := client.GetGroups(&model.GetGroupsRequest{
+			Paging:                paging,
+			WithInstallationCount: flags.withInstallationCount,
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to query groups")
+		}
+
+		return groupPrinter.printList(w, flags.tableOptions, groups)
 	})
-	if err != nil {
-		return errors.Wrap(err, "failed to query groups")
-	}
-	if flags.outputToTable {
-		table := tablewriter.NewWriter(os.Stdout)
-		table.SetAlignment(tablewriter.ALIGN_LEFT)
-		table.SetHeader([]string{"ID", "NAME", "SEQ", "ROL", "IMAGE", "VERSION", "ENV?"})
-
-		for _, group := range groups {
-			hasEnv := "no"
-			if len(group.MattermostEnv) > 0 {
-				hasEnv = "yes"
-			}
-			table.Append([]string{group.ID, group.Name, fmt.Sprintf("%d", group.Sequence), fmt.Sprintf("%d", group.MaxRolling), group.Image, group.Version, hasEnv})
+}
+
+var groupPrinter = resourcePrinter[*model.GroupDTO]{
+	defaultTable: defaultGroupTableData,
+	wideTable:    wideGroupTableData,
+}
+
+func defaultGroupTableData(groups []*model.GroupDTO) ([]string, [][]string) {
+	keys := []string{"ID", "NAME", "SEQ", "ROL", "IMAGE", "VERSION", "ENV?"}
+	vals := make([][]string, 0, len(groups))
+	for _, group := range groups {
+		hasEnv := "no"
+		if len(group.MattermostEnv) > 0 {
+			hasEnv = "yes"
 		}
-		table.Render()
-		return nil
+		vals = append(vals, []string{group.ID, group.Name, fmt.Sprintf("%d", group.Sequence), fmt.Sprintf("%d", group.MaxRolling), group.Image, group.Version, hasEnv})
 	}
+	return keys, vals
+}
 
-	return printJSON(groups)
+func wideGroupTableData(groups []*model.GroupDTO) ([]string, [][]string) {
+	keys, vals := defaultGroupTableData(groups)
+	keys = append(keys, "INSTALLATIONS", "DESCRIPTION")
+	for i, group := range groups {
+		installationCount := ""
+		if group.InstallationCount != nil {
+			installationCount = fmt.Sprintf("%d", *group.InstallationCount)
+		}
+		vals[i] = append(vals[i], installationCount, group.Description)
+	}
+	return keys, vals
 }
 
 func newCmdGroupGetStatus() *cobra.Command {
@@ -90,10 +90,14 @@ func (flags *groupDeleteFlags) addFlags(command *cobra.Command) {
 
 type groupGetFlags struct {
 	clusterFlags
+	tableOptions
+	watchOptions
 	groupID string
 }
 
 func (flags *groupGetFlags) addFlags(command *cobra.Command) {
+	flags.tableOptions.addFlags(command)
+	flags.watchOptions.addFlags(command)
 	command.Flags().StringVar(&flags.groupID, "group", "", "The id of the group to be fetched.")
 
 	_ = command.MarkFlagRequired("group")
@@ -102,14 +106,16 @@ func (flags *groupGetFlags) addFlags(command *cobra.Command) {
 type groupListFlags struct {
 	clusterFlags
 	pagingFlags
-	outputToTable         bool
+	tableOptions
+	watchOptions
 	withInstallationCount bool
 }
 
 func (flags *groupListFlags) addFlags(command *cobra.Command) {
 	flags.pagingFlags.addFlags(command)
+	flags.tableOptions.addFlags(command)
+	flags.watchOptions.addFlags(command)
 
-	command.Flags().BoolVar(&flags.outputToTable, "table", false, "Whether to display the returned group list in a table or not")
 	command.Flags().BoolVar(&flags.withInstallationCount, "include-installation-count", false, "Whether to retrieve the installation count for the groups")
 }
 
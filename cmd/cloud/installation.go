@@ -7,6 +7,7 @@ package main
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"time"
@@ -51,6 +52,9 @@ func newCmdInstallation() *cobra.Command {
 	cmd.AddCommand(newCmdInstallationBackup())
 	cmd.AddCommand(newCmdInstallationOperation())
 	cmd.AddCommand(newCmdInstallationDNS())
+	cmd.AddCommand(newCmdInstallationSize())
+	cmd.AddCommand(newCmdInstallationUsage())
+	cmd.AddCommand(newCmdInstallationRecommendations())
 
 	return cmd
 }
@@ -115,6 +119,13 @@ func executeInstallationCreateCmd(ctx context.Context, flags installationCreateF
 		request.Command = &command
 	}
 
+	if flags.networkPolicyExceptions != "" {
+		request.NetworkPolicyExceptions, err = model.NetworkPolicyExceptionsFromReader(strings.NewReader(flags.networkPolicyExceptions))
+		if err != nil {
+			return errors.Wrap(err, "failed to parse network policy exceptions")
+		}
+	}
+
 	// For CLI to be backward compatible, if only one DNS is passed we use
 	// the old field.
 	// TODO: properly replace with DNSNames
@@ -480,24 +491,26 @@ func newCmdInstallationGet() *cobra.Command {
 			command.SilenceUsage = true
 			client := createClient(command.Context(), flags.clusterFlags)
 
-			installation, err := client.GetInstallation(flags.installationID, &model.GetInstallationRequest{
-				IncludeGroupConfig:          flags.includeGroupConfig,
-				IncludeGroupConfigOverrides: flags.includeGroupConfigOverrides,
-			})
-			if err != nil {
-				return errors.Wrap(err, "failed to query installation")
-			}
-			if installation == nil {
-				return nil
-			}
-			if flags.hideLicense {
-				hideMattermostLicense(installation.Installation)
-			}
-			if flags.hideEnv {
-				hideMattermostEnv(installation.Installation)
-			}
+			return watchOutput(command.Context(), os.Stdout, flags.watchOptions, func(w io.Writer) error {
+				installation, err := client.GetInstallation(flags.installationID, &model.GetInstallationRequest{
+					IncludeGroupConfig:          flags.includeGroupConfig,
+					IncludeGroupConfigOverrides: flags.includeGroupConfigOverrides,
+				})
+				if err != nil {
+					return errors.Wrap(err, "failed to query installation")
+				}
+				if installation == nil {
+					return nil
+				}
+				if flags.hideLicense {
+					hideMattermostLicense(installation.Installation)
+				}
+				if flags.hideEnv {
+					hideMattermostEnv(installation.Installation)
+				}
 
-			return printJSON(installation)
+				return installationPrinter.printObject(w, flags.tableOptions, installation)
+			})
 		},
 		PreRun: func(cmd *cobra.Command, args []string) {
 			flags.clusterFlags.addFlags(cmd)
@@ -533,54 +546,40 @@ func executeInstallationListCmd(ctx context.Context, flags installationListFlags
 
 	paging := getPaging(flags.pagingFlags)
 
-	installations, err := client.GetInstallations(&model.GetInstallationsRequest{
-		OwnerID:                     flags.owner,
-		GroupID:                     flags.group,
-		State:                       flags.state,
-		DNS:                         flags.dns,
-		IncludeGroupConfig:          flags.includeGroupConfig,
-		IncludeGroupConfigOverrides: flags.includeGroupConfigOverrides,
-		DeletionLocked:              flags.deletionLockedFilterValue(),
-		Paging:                      paging,
-	})
-	if err != nil {
-		return errors.Wrap(err, "failed to query installations")
-	}
-
-	if flags.hideLicense {
-		for _, installation := range installations {
-			hideMattermostLicense(installation.Installation)
+	return watchOutput(ctx, os.Stdout, flags.watchOptions, func(w io.Writer) error {
+		installations, err := client.GetInstallations(&model.GetInstallationsRequest{
+			OwnerID:                     flags.owner,
+			GroupID:                     flags.group,
+			State:                       flags.state,
+			DNS:                         flags.dns,
+			IncludeGroupConfig:          flags.includeGroupConfig,
+			IncludeGroupConfigOverrides: flags.includeGroupConfigOverrides,
+			DeletionLocked:              flags.deletionLockedFilterValue(),
+			Paging:                      paging,
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to query installations")
 		}
-	}
 
-	if flags.hideEnv {
-		for _, installation := range installations {
-			hideMattermostEnv(installation.Installation)
+		if flags.hideLicense {
+			for _, installation := range installations {
+				hideMattermostLicense(installation.Installation)
+			}
 		}
-	}
 
-	if enabled, customCols := getTableOutputOption(flags.tableOptions); enabled {
-		var keys []string
-		var vals [][]string
-
-		if len(customCols) > 0 {
-			data := make([]interface{}, 0, len(installations))
-			for _, inst := range installations {
-				data = append(data, inst)
-			}
-			keys, vals, err = prepareTableData(customCols, data)
-			if err != nil {
-				return errors.Wrap(err, "failed to prepare table output")
+		if flags.hideEnv {
+			for _, installation := range installations {
+				hideMattermostEnv(installation.Installation)
 			}
-		} else {
-			keys, vals = defaultInstallationTableData(installations)
 		}
 
-		printTable(keys, vals)
-		return nil
-	}
+		return installationPrinter.printList(w, flags.tableOptions, installations)
+	})
+}
 
-	return printJSON(installations)
+var installationPrinter = resourcePrinter[*model.InstallationDTO]{
+	defaultTable: defaultInstallationTableData,
+	wideTable:    wideInstallationTableData,
 }
 
 func defaultInstallationTableData(installations []*model.InstallationDTO) ([]string, [][]string) {
@@ -601,6 +600,26 @@ func defaultInstallationTableData(installations []*model.InstallationDTO) ([]str
 	return keys, vals
 }
 
+func wideInstallationTableData(installations []*model.InstallationDTO) ([]string, [][]string) {
+	keys, vals := defaultInstallationTableData(installations)
+	keys = append(keys, "OWNER", "GROUP", "SIZE", "AFFINITY", "IMAGE", "CREATED")
+	for i, installation := range installations {
+		var groupID string
+		if installation.IsInGroup() {
+			groupID = *installation.GroupID
+		}
+		vals[i] = append(vals[i],
+			installation.OwnerID,
+			groupID,
+			installation.Size,
+			installation.Affinity,
+			installation.Image,
+			installation.CreationDateString(),
+		)
+	}
+	return keys, vals
+}
+
 func dnsNames(dnsRecords []*model.InstallationDNS) string {
 	names := model.DNSNamesFromRecords(dnsRecords)
 	return strings.Join(names, ", ")
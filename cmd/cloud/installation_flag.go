@@ -5,6 +5,7 @@
 package main
 
 import (
+	"strings"
 	"time"
 
 	"github.com/mattermost/mattermost-cloud/model"
@@ -30,6 +31,7 @@ type installationCreateRequestOptions struct {
 	groupSelectionAnnotations []string
 	command                   []string
 	scheduledDeletionTime     time.Duration
+	networkPolicyExceptions   string
 
 	// Probe override settings
 	probeLivenessFailureThreshold    int32
@@ -51,7 +53,7 @@ func (flags *installationCreateRequestOptions) addFlags(command *cobra.Command)
 	command.Flags().StringVar(&flags.groupID, "group", "", "The id of the group to join")
 	command.Flags().StringVar(&flags.version, "version", "stable", "The Mattermost version to install.")
 	command.Flags().StringVar(&flags.image, "image", "mattermost/mattermost-enterprise-edition", "The Mattermost container image to use.")
-	command.Flags().StringVar(&flags.size, "size", model.InstallationDefaultSize, "The size of the installation. Accepts 100users, 1000users, 5000users, 10000users, 25000users, miniSingleton, or miniHA. Defaults to 100users.")
+	command.Flags().StringVar(&flags.size, "size", model.InstallationDefaultSize, "The size of the installation. Accepts 100users, 1000users, 5000users, 10000users, 25000users, miniSingleton, miniHA, provisionerXL or the name of a size profile. Defaults to 100users.")
 	command.Flags().StringVar(&flags.license, "license", "", "The Mattermost License to use in the server.")
 	command.Flags().StringVar(&flags.affinity, "affinity", model.InstallationAffinityIsolated, "Whether the installation can be scheduled on a cluster with other installations. Accepts isolated or multitenant.")
 	command.Flags().StringVar(&flags.database, "database", model.InstallationDatabaseMysqlOperator, "The Mattermost server database type. Accepts mysql-operator, aws-rds, aws-rds-postgres, aws-multitenant-rds, or aws-multitenant-rds-postgres")
@@ -62,6 +64,7 @@ func (flags *installationCreateRequestOptions) addFlags(command *cobra.Command)
 	command.Flags().StringArrayVar(&flags.groupSelectionAnnotations, "group-selection-annotation", []string{}, "Annotations for automatic group selection. Accepts multiple values, for example: '... --group-selection-annotation abc --group-selection-annotation def'")
 	command.Flags().DurationVar(&flags.scheduledDeletionTime, "scheduled-deletion-time", 0, "The time from now when the installation should be deleted. Use 0 for no scheduled deletion.")
 	command.Flags().StringSliceVar(&flags.command, "command", []string{}, "Override the default command for the Mattermost container. Accepts multiple values, for example: 'mattermost --example-flag test'")
+	command.Flags().StringVar(&flags.networkPolicyExceptions, "network-policy-exceptions", "", `JSON encoded traffic allowed on top of the isolation NetworkPolicies of the installation, for example: '{"IngressNamespaces":["monitoring"],"Egress":[{"CIDR":"10.0.0.0/8","Port":443}]}'`)
 
 	// Probe override flags
 	command.Flags().Int32Var(&flags.probeLivenessFailureThreshold, "probe-liveness-failure-threshold", 0, "Override for the liveness probe failure threshold. Use 0 to use server/operator defaults.")
@@ -110,14 +113,15 @@ func (flags *installationCreateFlags) addFlags(command *cobra.Command) {
 }
 
 type installationPatchRequestChanges struct {
-	ownerIDChanged          bool
-	versionChanged          bool
-	imageChanged            bool
-	sizeChanged             bool
-	licenseChanged          bool
-	allowedIPRangesChanged  bool
-	overrideIPRangesChanged bool
-	commandChanged          bool
+	ownerIDChanged                 bool
+	versionChanged                 bool
+	imageChanged                   bool
+	sizeChanged                    bool
+	licenseChanged                 bool
+	allowedIPRangesChanged         bool
+	overrideIPRangesChanged        bool
+	commandChanged                 bool
+	networkPolicyExceptionsChanged bool
 
 	// Probe override change flags
 	probeLivenessFailureThresholdChanged    bool
@@ -142,6 +146,7 @@ func (flags *installationPatchRequestChanges) addFlags(command *cobra.Command) {
 	flags.allowedIPRangesChanged = command.Flags().Changed("allowed-ip-ranges")
 	flags.overrideIPRangesChanged = command.Flags().Changed("override-ip-ranges")
 	flags.commandChanged = command.Flags().Changed("command")
+	flags.networkPolicyExceptionsChanged = command.Flags().Changed("network-policy-exceptions")
 
 	// Probe override change flags
 	flags.probeLivenessFailureThresholdChanged = command.Flags().Changed("probe-liveness-failure-threshold")
@@ -159,16 +164,17 @@ func (flags *installationPatchRequestChanges) addFlags(command *cobra.Command) {
 
 type installationPatchRequestOptions struct {
 	installationPatchRequestChanges
-	ownerID            string
-	version            string
-	image              string
-	size               string
-	license            string
-	allowedIPRanges    string
-	mattermostEnv      []string
-	mattermostEnvClear bool
-	overrideIPRanges   bool
-	command            []string
+	ownerID                 string
+	version                 string
+	image                   string
+	size                    string
+	license                 string
+	allowedIPRanges         string
+	mattermostEnv           []string
+	mattermostEnvClear      bool
+	overrideIPRanges        bool
+	command                 []string
+	networkPolicyExceptions string
 
 	// Probe override settings
 	probeLivenessFailureThreshold    int32
@@ -188,13 +194,14 @@ func (flags *installationPatchRequestOptions) addFlags(command *cobra.Command) {
 	command.Flags().StringVar(&flags.ownerID, "owner", "", "The new owner value of this installation.")
 	command.Flags().StringVar(&flags.version, "version", "stable", "The Mattermost version to target.")
 	command.Flags().StringVar(&flags.image, "image", "mattermost/mattermost-enterprise-edition", "The Mattermost container image to use.")
-	command.Flags().StringVar(&flags.size, "size", model.InstallationDefaultSize, "The size of the installation. Accepts 100users, 1000users, 5000users, 10000users, 25000users, miniSingleton, or miniHA. Defaults to 100users.")
+	command.Flags().StringVar(&flags.size, "size", model.InstallationDefaultSize, "The size of the installation. Accepts 100users, 1000users, 5000users, 10000users, 25000users, miniSingleton, miniHA, provisionerXL or the name of a size profile. Defaults to 100users.")
 	command.Flags().StringVar(&flags.license, "license", "", "The Mattermost License to use in the server.")
 	command.Flags().StringVar(&flags.allowedIPRanges, "allowed-ip-ranges", "", "JSON Encoded list of IP Ranges that are allowed to access the workspace.")
 	command.Flags().StringArrayVar(&flags.mattermostEnv, "mattermost-env", []string{}, "Env vars to add to the Mattermost App. Accepts format: KEY_NAME=VALUE. Use the flag multiple times to set multiple env vars.")
 	command.Flags().BoolVar(&flags.mattermostEnvClear, "mattermost-env-clear", false, "Clears all env var data.")
 	command.Flags().BoolVar(&flags.overrideIPRanges, "override-ip-ranges", true, "Overrides Allowed IP ranges and force ignoring any previous value.")
 	command.Flags().StringSliceVar(&flags.command, "command", []string{}, "Override the default command for the Mattermost container. Accepts multiple values, for example: 'mattermost --example-flag test'")
+	command.Flags().StringVar(&flags.networkPolicyExceptions, "network-policy-exceptions", "", "JSON encoded traffic allowed on top of the isolation NetworkPolicies of the installation. Replaces the existing exceptions, use '{}' to remove them.")
 
 	// Probe override flags
 	command.Flags().Int32Var(&flags.probeLivenessFailureThreshold, "probe-liveness-failure-threshold", 0, "Override for the liveness probe failure threshold. Use 0 to use server/operator defaults.")
@@ -415,6 +422,13 @@ func (flags *installationUpdateFlags) GetPatchInstallationRequest() (*model.Patc
 		request.AllowedIPRanges = allowedIPRanges
 	}
 
+	if flags.networkPolicyExceptionsChanged {
+		request.NetworkPolicyExceptions, err = model.NetworkPolicyExceptionsFromReader(strings.NewReader(flags.networkPolicyExceptions))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse network policy exceptions")
+		}
+	}
+
 	request.MattermostEnv = mattermostEnv
 	request.PriorityEnv = priorityEnv
 
@@ -628,6 +642,8 @@ func (flags *installationWakeupFlags) GetPatchInstallationRequest() (*model.Patc
 
 type installationGetFlags struct {
 	clusterFlags
+	tableOptions
+	watchOptions
 	installationID              string
 	includeGroupConfig          bool
 	includeGroupConfigOverrides bool
@@ -636,6 +652,8 @@ type installationGetFlags struct {
 }
 
 func (flags *installationGetFlags) addFlags(command *cobra.Command) {
+	flags.tableOptions.addFlags(command)
+	flags.watchOptions.addFlags(command)
 	command.Flags().StringVar(&flags.installationID, "installation", "", "The id of the installation to be fetched.")
 	command.Flags().BoolVar(&flags.includeGroupConfig, "include-group-config", true, "Whether to include group configuration in the installation or not.")
 	command.Flags().BoolVar(&flags.includeGroupConfigOverrides, "include-group-config-overrides", true, "Whether to include a group configuration override summary in the installation or not.")
@@ -679,6 +697,7 @@ type installationListFlags struct {
 	installationGetRequestChanges
 	pagingFlags
 	tableOptions
+	watchOptions
 	hideLicense bool
 	hideEnv     bool
 }
@@ -687,6 +706,7 @@ func (flags *installationListFlags) addFlags(command *cobra.Command) {
 	flags.installationGetRequestOptions.addFlags(command)
 	flags.pagingFlags.addFlags(command)
 	flags.tableOptions.addFlags(command)
+	flags.watchOptions.addFlags(command)
 
 	command.Flags().BoolVar(&flags.hideLicense, "hide-license", true, "Whether to hide the license value in the output or not.")
 	command.Flags().BoolVar(&flags.hideEnv, "hide-env", true, "Whether to hide env vars in the output or not.")
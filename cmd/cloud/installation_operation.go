@@ -14,6 +14,7 @@ func newCmdInstallationOperation() *cobra.Command {
 
 	cmd.AddCommand(newCmdInstallationRestorationOperation())
 	cmd.AddCommand(newCmdInstallationDBMigrationOperation())
+	cmd.AddCommand(newCmdInstallationFilestoreMigrationOperation())
 
 	return cmd
 }
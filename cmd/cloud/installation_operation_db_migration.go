@@ -6,6 +6,8 @@ package main
 
 import (
 	"context"
+	"io"
+	"os"
 
 	"github.com/mattermost/mattermost-cloud/model"
 	"github.com/pkg/errors"
@@ -92,39 +94,24 @@ func executeInstallationDBMigrationsList(ctx context.Context, flags installation
 
 	paging := getPaging(flags.pagingFlags)
 
-	request := &model.GetInstallationDBMigrationOperationsRequest{
-		Paging:         paging,
-		InstallationID: flags.installationID,
-		State:          flags.state,
-	}
-
-	dbMigrationOperations, err := client.GetInstallationDBMigrationOperations(request)
-	if err != nil {
-		return errors.Wrap(err, "failed to list installation database migration operations")
-	}
-
-	if enabled, customCols := getTableOutputOption(flags.tableOptions); enabled {
-		var keys []string
-		var vals [][]string
+	return watchOutput(ctx, os.Stdout, flags.watchOptions, func(w io.Writer) error {
+		request := &model.GetInstallationDBMigrationOperationsRequest{
+			Paging:         paging,
+			InstallationID: flags.installationID,
+			State:          flags.state,
+		}
 
-		if len(customCols) > 0 {
-			data := make([]interface{}, 0, len(dbMigrationOperations))
-			for _, elem := range dbMigrationOperations {
-				data = append(data, elem)
-			}
-			keys, vals, err = prepareTableData(customCols, data)
-			if err != nil {
-				return errors.Wrap(err, "failed to prepare table output")
-			}
-		} else {
-			keys, vals = defaultDBMigrationOperationTableData(dbMigrationOperations)
+		dbMigrationOperations, err := client.GetInstallationDBMigrationOperations(request)
+		if err != nil {
+			return errors.Wrap(err, "failed to list installation database migration operations")
 		}
 
-		printTable(keys, vals)
-		return nil
-	}
+		return dbMigrationOperationPrinter.printList(w, flags.tableOptions, dbMigrationOperations)
+	})
+}
 
-	return printJSON(dbMigrationOperations)
+var dbMigrationOperationPrinter = resourcePrinter[*model.InstallationDBMigrationOperation]{
+	defaultTable: defaultDBMigrationOperationTableData,
 }
 
 func defaultDBMigrationOperationTableData(ops []*model.InstallationDBMigrationOperation) ([]string, [][]string) {
@@ -153,11 +140,13 @@ func newCmdInstallationDBMigrationGet() *cobra.Command {
 
 			client := createClient(command.Context(), flags.clusterFlags)
 
-			migrationOperation, err := client.GetInstallationDBMigrationOperation(flags.dbMigrationID)
-			if err != nil {
-				return errors.Wrap(err, "failed to get installation database migration")
-			}
-			return printJSON(migrationOperation)
+			return watchOutput(command.Context(), os.Stdout, flags.watchOptions, func(w io.Writer) error {
+				migrationOperation, err := client.GetInstallationDBMigrationOperation(flags.dbMigrationID)
+				if err != nil {
+					return errors.Wrap(err, "failed to get installation database migration")
+				}
+				return dbMigrationOperationPrinter.printObject(w, flags.tableOptions, migrationOperation)
+			})
 		},
 		PreRun: func(cmd *cobra.Command, args []string) {
 			flags.clusterFlags.addFlags(cmd)
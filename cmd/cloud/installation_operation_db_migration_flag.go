@@ -28,6 +28,7 @@ type installationDBMigrationsListFlags struct {
 	clusterFlags
 	pagingFlags
 	tableOptions
+	watchOptions
 	installationID string
 	state          string
 }
@@ -37,14 +38,19 @@ func (flags *installationDBMigrationsListFlags) addFlags(command *cobra.Command)
 	command.Flags().StringVar(&flags.state, "state", "", "The state to filter operations by.")
 	flags.pagingFlags.addFlags(command)
 	flags.tableOptions.addFlags(command)
+	flags.watchOptions.addFlags(command)
 }
 
 type installationDBMigrationGetFlags struct {
 	clusterFlags
+	tableOptions
+	watchOptions
 	dbMigrationID string
 }
 
 func (flags *installationDBMigrationGetFlags) addFlags(command *cobra.Command) {
+	flags.tableOptions.addFlags(command)
+	flags.watchOptions.addFlags(command)
 	command.Flags().StringVar(&flags.dbMigrationID, "db-migration", "", "The id of the installation db migration operation.")
 	_ = command.MarkFlagRequired("db-migration")
 }
@@ -0,0 +1,219 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func newCmdInstallationFilestoreMigrationOperation() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "filestore-migration",
+		Short: "Manipulate installation filestore migration operations managed by the provisioning server.",
+	}
+
+	cmd.AddCommand(newCmdInstallationFilestoreMigrationRequest())
+	cmd.AddCommand(newCmdInstallationFilestoreMigrationsList())
+	cmd.AddCommand(newCmdInstallationFilestoreMigrationGet())
+	cmd.AddCommand(newCmdInstallationFilestoreMigrationCommit())
+	cmd.AddCommand(newCmdInstallationFilestoreMigrationRollback())
+
+	return cmd
+}
+
+func newCmdInstallationFilestoreMigrationRequest() *cobra.Command {
+
+	var flags installationFilestoreMigrationRequestFlags
+
+	cmd := &cobra.Command{
+		Use:   "request",
+		Short: "Request filestore migration to different filestore",
+		RunE: func(command *cobra.Command, args []string) error {
+			command.SilenceUsage = true
+
+			client := createClient(command.Context(), flags.clusterFlags)
+
+			request := &model.InstallationFilestoreMigrationRequest{
+				InstallationID:       flags.installationID,
+				DestinationFilestore: flags.destinationFilestore,
+			}
+
+			if flags.dryRun {
+				return runDryRun(request)
+			}
+
+			migrationOperation, err := client.MigrateInstallationFilestore(request)
+			if err != nil {
+				return errors.Wrap(err, "failed to request installation filestore migration")
+			}
+
+			return printJSON(migrationOperation)
+		},
+		PreRun: func(cmd *cobra.Command, args []string) {
+			flags.clusterFlags.addFlags(cmd)
+		},
+	}
+
+	flags.addFlags(cmd)
+
+	return cmd
+}
+
+func newCmdInstallationFilestoreMigrationsList() *cobra.Command {
+	var flags installationFilestoreMigrationsListFlags
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List installation filestore migration operations",
+		RunE: func(command *cobra.Command, args []string) error {
+			command.SilenceUsage = true
+			return executeInstallationFilestoreMigrationsList(command.Context(), flags)
+		},
+		PreRun: func(cmd *cobra.Command, args []string) {
+			flags.clusterFlags.addFlags(cmd)
+		},
+	}
+
+	flags.addFlags(cmd)
+
+	return cmd
+
+}
+
+func executeInstallationFilestoreMigrationsList(ctx context.Context, flags installationFilestoreMigrationsListFlags) error {
+	client := createClient(ctx, flags.clusterFlags)
+
+	paging := getPaging(flags.pagingFlags)
+
+	return watchOutput(ctx, os.Stdout, flags.watchOptions, func(w io.Writer) error {
+		request := &model.GetInstallationFilestoreMigrationOperationsRequest{
+			Paging:         paging,
+			InstallationID: flags.installationID,
+			State:          flags.state,
+		}
+
+		filestoreMigrationOperations, err := client.GetInstallationFilestoreMigrationOperations(request)
+		if err != nil {
+			return errors.Wrap(err, "failed to list installation filestore migration operations")
+		}
+
+		return filestoreMigrationOperationPrinter.printList(w, flags.tableOptions, filestoreMigrationOperations)
+	})
+}
+
+var filestoreMigrationOperationPrinter = resourcePrinter[*model.InstallationFilestoreMigrationOperation]{
+	defaultTable: defaultFilestoreMigrationOperationTableData,
+}
+
+func defaultFilestoreMigrationOperationTableData(ops []*model.InstallationFilestoreMigrationOperation) ([]string, [][]string) {
+	keys := []string{"ID", "INSTALLATION ID", "STATE", "SOURCE", "DESTINATION", "PROGRESS", "REQUEST AT"}
+	vals := make([][]string, 0, len(ops))
+
+	for _, migration := range ops {
+		vals = append(vals, []string{
+			migration.ID,
+			migration.InstallationID,
+			string(migration.State),
+			migration.SourceFilestore,
+			migration.DestinationFilestore,
+			fmt.Sprintf("%.1f%%", migration.CopyProgress()),
+			model.TimeFromMillis(migration.RequestAt).Format("2006-01-02 15:04:05 -0700 MST"),
+		})
+	}
+	return keys, vals
+}
+
+func newCmdInstallationFilestoreMigrationGet() *cobra.Command {
+	var flags installationFilestoreMigrationGetFlags
+
+	cmd := &cobra.Command{
+		Use:   "get",
+		Short: "Fetches given installation filestore migration operation.",
+		RunE: func(command *cobra.Command, args []string) error {
+			command.SilenceUsage = true
+
+			client := createClient(command.Context(), flags.clusterFlags)
+
+			return watchOutput(command.Context(), os.Stdout, flags.watchOptions, func(w io.Writer) error {
+				migrationOperation, err := client.GetInstallationFilestoreMigrationOperation(flags.filestoreMigrationID)
+				if err != nil {
+					return errors.Wrap(err, "failed to get installation filestore migration")
+				}
+				return filestoreMigrationOperationPrinter.printObject(w, flags.tableOptions, migrationOperation)
+			})
+		},
+		PreRun: func(cmd *cobra.Command, args []string) {
+			flags.clusterFlags.addFlags(cmd)
+		},
+	}
+
+	flags.addFlags(cmd)
+
+	return cmd
+}
+
+func newCmdInstallationFilestoreMigrationCommit() *cobra.Command {
+	var flags installationFilestoreMigrationCommitFlags
+
+	cmd := &cobra.Command{
+		Use:   "commit",
+		Short: "Commits filestore migration and cleans up the source filestore",
+		RunE: func(command *cobra.Command, args []string) error {
+			command.SilenceUsage = true
+
+			client := createClient(command.Context(), flags.clusterFlags)
+
+			migrationOperation, err := client.CommitInstallationFilestoreMigration(flags.filestoreMigrationID)
+			if err != nil {
+				return errors.Wrap(err, "failed to commit installation filestore migration")
+			}
+
+			return printJSON(migrationOperation)
+		},
+		PreRun: func(cmd *cobra.Command, args []string) {
+			flags.clusterFlags.addFlags(cmd)
+		},
+	}
+
+	flags.addFlags(cmd)
+
+	return cmd
+
+}
+
+func newCmdInstallationFilestoreMigrationRollback() *cobra.Command {
+	var flags installationFilestoreMigrationRollbackFlags
+
+	cmd := &cobra.Command{
+		Use:   "rollback",
+		Short: "Triggers rollback of filestore migration",
+		RunE: func(command *cobra.Command, args []string) error {
+			command.SilenceUsage = true
+
+			client := createClient(command.Context(), flags.clusterFlags)
+
+			migrationOperation, err := client.RollbackInstallationFilestoreMigration(flags.filestoreMigrationID)
+			if err != nil {
+				return errors.Wrap(err, "failed to trigger rollback of installation filestore migration")
+			}
+
+			return printJSON(migrationOperation)
+		},
+		PreRun: func(cmd *cobra.Command, args []string) {
+			flags.clusterFlags.addFlags(cmd)
+		},
+	}
+
+	flags.addFlags(cmd)
+
+	return cmd
+}
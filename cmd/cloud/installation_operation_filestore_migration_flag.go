@@ -0,0 +1,73 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package main
+
+import (
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/spf13/cobra"
+)
+
+type installationFilestoreMigrationRequestFlags struct {
+	clusterFlags
+	installationID       string
+	destinationFilestore string
+}
+
+func (flags *installationFilestoreMigrationRequestFlags) addFlags(command *cobra.Command) {
+	command.Flags().StringVar(&flags.installationID, "installation", "", "The id of the installation to be migrated.")
+	command.Flags().StringVar(&flags.destinationFilestore, "destination-filestore", model.InstallationFilestoreBifrost, "The destination filestore type.")
+	_ = command.MarkFlagRequired("installation")
+}
+
+type installationFilestoreMigrationsListFlags struct {
+	clusterFlags
+	pagingFlags
+	tableOptions
+	watchOptions
+	installationID string
+	state          string
+}
+
+func (flags *installationFilestoreMigrationsListFlags) addFlags(command *cobra.Command) {
+	command.Flags().StringVar(&flags.installationID, "installation", "", "The id of the installation to query operations.")
+	command.Flags().StringVar(&flags.state, "state", "", "The state to filter operations by.")
+	flags.pagingFlags.addFlags(command)
+	flags.tableOptions.addFlags(command)
+	flags.watchOptions.addFlags(command)
+}
+
+type installationFilestoreMigrationGetFlags struct {
+	clusterFlags
+	tableOptions
+	watchOptions
+	filestoreMigrationID string
+}
+
+func (flags *installationFilestoreMigrationGetFlags) addFlags(command *cobra.Command) {
+	flags.tableOptions.addFlags(command)
+	flags.watchOptions.addFlags(command)
+	command.Flags().StringVar(&flags.filestoreMigrationID, "filestore-migration", "", "The id of the installation filestore migration operation.")
+	_ = command.MarkFlagRequired("filestore-migration")
+}
+
+type installationFilestoreMigrationCommitFlags struct {
+	clusterFlags
+	filestoreMigrationID string
+}
+
+func (flags *installationFilestoreMigrationCommitFlags) addFlags(command *cobra.Command) {
+	command.Flags().StringVar(&flags.filestoreMigrationID, "filestore-migration", "", "The id of the installation filestore migration operation.")
+	_ = command.MarkFlagRequired("filestore-migration")
+}
+
+type installationFilestoreMigrationRollbackFlags struct {
+	clusterFlags
+	filestoreMigrationID string
+}
+
+func (flags *installationFilestoreMigrationRollbackFlags) addFlags(command *cobra.Command) {
+	command.Flags().StringVar(&flags.filestoreMigrationID, "filestore-migration", "", "The id of the installation filestore migration operation.")
+	_ = command.MarkFlagRequired("filestore-migration")
+}
@@ -6,6 +6,9 @@ package main
 
 import (
 	"context"
+	"io"
+	"os"
+	"time"
 
 	"github.com/mattermost/mattermost-cloud/model"
 	"github.com/pkg/errors"
@@ -19,6 +22,7 @@ func newCmdInstallationRestorationOperation() *cobra.Command {
 	}
 
 	cmd.AddCommand(newCmdInstallationRestorationRequest())
+	cmd.AddCommand(newCmdInstallationPointInTimeRestorationRequest())
 	cmd.AddCommand(newCmdInstallationRestorationsListCmd())
 	cmd.AddCommand(newCmdInstallationRestorationGetCmd())
 
@@ -53,6 +57,39 @@ func newCmdInstallationRestorationRequest() *cobra.Command {
 	return cmd
 }
 
+func newCmdInstallationPointInTimeRestorationRequest() *cobra.Command {
+	var flags installationPointInTimeRestorationRequestFlags
+
+	cmd := &cobra.Command{
+		Use:   "request-point-in-time",
+		Short: "Request restoration of single tenant RDS Postgres database to a point in time",
+		RunE: func(command *cobra.Command, args []string) error {
+			command.SilenceUsage = true
+
+			restoreTime, err := time.Parse(time.RFC3339, flags.restoreTime)
+			if err != nil {
+				return errors.Wrap(err, "failed to parse restore time")
+			}
+
+			client := createClient(command.Context(), flags.clusterFlags)
+
+			restorationOperation, err := client.RestoreInstallationDatabaseToPointInTime(flags.installationID, restoreTime.UnixMilli())
+			if err != nil {
+				return errors.Wrap(err, "failed to request installation database point-in-time restoration")
+			}
+
+			return printJSON(restorationOperation)
+		},
+		PreRun: func(cmd *cobra.Command, args []string) {
+			flags.clusterFlags.addFlags(cmd)
+		},
+	}
+
+	flags.addFlags(cmd)
+
+	return cmd
+}
+
 func newCmdInstallationRestorationsListCmd() *cobra.Command {
 
 	var flags installationRestorationsListFlags
@@ -79,50 +116,36 @@ func executeInstallationRestorationsList(ctx context.Context, flags installation
 
 	paging := getPaging(flags.pagingFlags)
 
-	request := &model.GetInstallationDBRestorationOperationsRequest{
-		Paging:                paging,
-		InstallationID:        flags.installationID,
-		ClusterInstallationID: flags.clusterInstallationID,
-		State:                 flags.state,
-	}
-
-	dbRestorationOperations, err := client.GetInstallationDBRestorationOperations(request)
-	if err != nil {
-		return errors.Wrap(err, "failed to list installation database restoration operations")
-	}
-
-	if enabled, customCols := getTableOutputOption(flags.tableOptions); enabled {
-		var keys []string
-		var vals [][]string
+	return watchOutput(ctx, os.Stdout, flags.watchOptions, func(w io.Writer) error {
+		request := &model.GetInstallationDBRestorationOperationsRequest{
+			Paging:                paging,
+			InstallationID:        flags.installationID,
+			ClusterInstallationID: flags.clusterInstallationID,
+			State:                 flags.state,
+		}
 
-		if len(customCols) > 0 {
-			data := make([]interface{}, 0, len(dbRestorationOperations))
-			for _, elem := range dbRestorationOperations {
-				data = append(data, elem)
-			}
-			keys, vals, err = prepareTableData(customCols, data)
-			if err != nil {
-				return errors.Wrap(err, "failed to prepare table output")
-			}
-		} else {
-			keys, vals = defaultDBRestorationOperationTableData(dbRestorationOperations)
+		dbRestorationOperations, err := client.GetInstallationDBRestorationOperations(request)
+		if err != nil {
+			return errors.Wrap(err, "failed to list installation database restoration operations")
 		}
 
-		printTable(keys, vals)
-		return nil
-	}
+		return dbRestorationOperationPrinter.printList(w, flags.tableOptions, dbRestorationOperations)
+	})
+}
 
-	return printJSON(dbRestorationOperations)
+var dbRestorationOperationPrinter = resourcePrinter[*model.InstallationDBRestorationOperation]{
+	defaultTable: defaultDBRestorationOperationTableData,
 }
 
 func defaultDBRestorationOperationTableData(ops []*model.InstallationDBRestorationOperation) ([]string, [][]string) {
-	keys := []string{"ID", "INSTALLATION ID", "BACKUP ID", "STATE", "CLUSTER INSTALLATION ID", "TARGET INSTALLATION STATE", "REQUEST AT"}
+	keys := []string{"ID", "INSTALLATION ID", "TYPE", "BACKUP ID", "STATE", "CLUSTER INSTALLATION ID", "TARGET INSTALLATION STATE", "REQUEST AT"}
 	vals := make([][]string, 0, len(ops))
 
 	for _, restoration := range ops {
 		vals = append(vals, []string{
 			restoration.ID,
 			restoration.InstallationID,
+			string(restoration.Type),
 			restoration.BackupID,
 			string(restoration.State),
 			restoration.ClusterInstallationID,
@@ -143,12 +166,14 @@ func newCmdInstallationRestorationGetCmd() *cobra.Command {
 			command.SilenceUsage = true
 
 			client := createClient(command.Context(), flags.clusterFlags)
-			restorationOperation, err := client.GetInstallationDBRestoration(flags.restorationID)
-			if err != nil {
-				return errors.Wrap(err, "failed to get installation database restoration")
-			}
-
-			return printJSON(restorationOperation)
+			return watchOutput(command.Context(), os.Stdout, flags.watchOptions, func(w io.Writer) error {
+				restorationOperation, err := client.GetInstallationDBRestoration(flags.restorationID)
+				if err != nil {
+					return errors.Wrap(err, "failed to get installation database restoration")
+				}
+
+				return dbRestorationOperationPrinter.printObject(w, flags.tableOptions, restorationOperation)
+			})
 		},
 		PreRun: func(cmd *cobra.Command, args []string) {
 			flags.clusterFlags.addFlags(cmd)
@@ -15,10 +15,24 @@ func (flags *installationRestorationRequestFlags) addFlags(command *cobra.Comman
 	_ = command.MarkFlagRequired("backup")
 }
 
+type installationPointInTimeRestorationRequestFlags struct {
+	clusterFlags
+	installationID string
+	restoreTime    string
+}
+
+func (flags *installationPointInTimeRestorationRequestFlags) addFlags(command *cobra.Command) {
+	command.Flags().StringVar(&flags.installationID, "installation", "", "The id of the installation to be restored.")
+	command.Flags().StringVar(&flags.restoreTime, "restore-time", "", "The time to restore the database to in RFC3339 format, e.g. 2006-01-02T15:04:05Z.")
+	_ = command.MarkFlagRequired("installation")
+	_ = command.MarkFlagRequired("restore-time")
+}
+
 type installationRestorationsListFlags struct {
 	clusterFlags
 	pagingFlags
 	tableOptions
+	watchOptions
 	installationID        string
 	clusterInstallationID string
 	state                 string
@@ -27,6 +41,7 @@ type installationRestorationsListFlags struct {
 func (flags *installationRestorationsListFlags) addFlags(command *cobra.Command) {
 	flags.pagingFlags.addFlags(command)
 	flags.tableOptions.addFlags(command)
+	flags.watchOptions.addFlags(command)
 
 	command.Flags().StringVar(&flags.installationID, "installation", "", "The id of the installation to query operations.")
 	command.Flags().StringVar(&flags.clusterInstallationID, "cluster-installation", "", "The cluster installation to filter operations by.")
@@ -35,10 +50,14 @@ func (flags *installationRestorationsListFlags) addFlags(command *cobra.Command)
 
 type installationRestorationGetFlags struct {
 	clusterFlags
+	tableOptions
+	watchOptions
 	restorationID string
 }
 
 func (flags *installationRestorationGetFlags) addFlags(command *cobra.Command) {
+	flags.tableOptions.addFlags(command)
+	flags.watchOptions.addFlags(command)
 	command.Flags().StringVar(&flags.restorationID, "restoration", "", "The id of restoration operation.")
 	_ = command.MarkFlagRequired("restoration")
 }
@@ -0,0 +1,70 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func newCmdInstallationRecommendations() *cobra.Command {
+	var flags installationRecommendationsFlags
+
+	cmd := &cobra.Command{
+		Use:   "recommendations",
+		Short: "List size recommendations for installations based on their observed usage.",
+		RunE: func(command *cobra.Command, args []string) error {
+			command.SilenceUsage = true
+			client := createClient(command.Context(), flags.clusterFlags)
+
+			recommendations, err := client.GetInstallationSizeRecommendations(&model.GetInstallationSizeRecommendationsRequest{
+				Paging:        getPaging(flags.pagingFlags),
+				OwnerID:       flags.owner,
+				GroupID:       flags.group,
+				MinConfidence: flags.minConfidence,
+			})
+			if err != nil {
+				return errors.Wrap(err, "failed to query installation size recommendations")
+			}
+
+			return installationRecommendationPrinter.printList(os.Stdout, flags.tableOptions, recommendations)
+		},
+		PreRun: func(cmd *cobra.Command, args []string) {
+			flags.clusterFlags.addFlags(cmd)
+		},
+	}
+	flags.addFlags(cmd)
+
+	return cmd
+}
+
+var installationRecommendationPrinter = resourcePrinter[*model.InstallationSizeRecommendation]{
+	defaultTable: defaultInstallationRecommendationTableData,
+	id: func(recommendation *model.InstallationSizeRecommendation) string {
+		return recommendation.InstallationID
+	},
+}
+
+func defaultInstallationRecommendationTableData(recommendations []*model.InstallationSizeRecommendation) ([]string, [][]string) {
+	keys := []string{"INSTALLATION", "NAME", "CURRENT SIZE", "RECOMMENDED SIZE", "ACTION", "CONFIDENCE", "PEAK CPU", "PEAK MEMORY"}
+	vals := make([][]string, 0, len(recommendations))
+	for _, recommendation := range recommendations {
+		vals = append(vals, []string{
+			recommendation.InstallationID,
+			recommendation.Name,
+			recommendation.CurrentSize,
+			recommendation.RecommendedSize,
+			recommendation.Action,
+			fmt.Sprintf("%.2f", recommendation.Confidence),
+			fmt.Sprintf("%dm", recommendation.PeakMilliCPU),
+			formatGiB(recommendation.PeakMemoryBytes),
+		})
+	}
+	return keys, vals
+}
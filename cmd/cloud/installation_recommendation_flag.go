@@ -0,0 +1,22 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+type installationRecommendationsFlags struct {
+	clusterFlags
+	pagingFlags
+	tableOptions
+	owner         string
+	group         string
+	minConfidence float64
+}
+
+func (flags *installationRecommendationsFlags) addFlags(command *cobra.Command) {
+	flags.pagingFlags.addFlags(command)
+	flags.tableOptions.addFlags(command)
+	command.Flags().StringVar(&flags.owner, "owner", "", "The owner ID to filter installations by.")
+	command.Flags().StringVar(&flags.group, "group", "", "The group ID to filter installations by.")
+	command.Flags().Float64Var(&flags.minConfidence, "min-confidence", 0, "Only show recommendations with at least this confidence, between 0 and 1.")
+}
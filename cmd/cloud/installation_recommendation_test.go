@@ -0,0 +1,38 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstallationRecommendationPrinter(t *testing.T) {
+	recommendations := []*model.InstallationSizeRecommendation{
+		{InstallationID: "installation1", Name: "one", CurrentSize: "1000users", RecommendedSize: "cloud10users", Confidence: 0.9},
+		{InstallationID: "installation2", Name: "two", CurrentSize: "cloud10users", RecommendedSize: "1000users", Confidence: 0.5},
+	}
+
+	printList := func(t *testing.T, output string) string {
+		buffer := &bytes.Buffer{}
+		err := installationRecommendationPrinter.printList(buffer, tableOptions{output: output}, recommendations)
+		require.NoError(t, err)
+		return buffer.String()
+	}
+
+	t.Run("name", func(t *testing.T) {
+		assert.Equal(t, "installation1\ninstallation2\n", printList(t, "name"))
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		out := printList(t, "csv")
+		assert.Contains(t, out, "INSTALLATION,NAME,CURRENT SIZE,RECOMMENDED SIZE")
+		assert.Contains(t, out, "installation2,two,cloud10users,1000users")
+	})
+}
@@ -0,0 +1,242 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func newCmdInstallationSize() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "size",
+		Short: "Manage installation size profiles stored in the provisioner.",
+	}
+
+	cmd.AddCommand(newCmdInstallationSizeCreate())
+	cmd.AddCommand(newCmdInstallationSizeGet())
+	cmd.AddCommand(newCmdInstallationSizeList())
+	cmd.AddCommand(newCmdInstallationSizeUpdate())
+	cmd.AddCommand(newCmdInstallationSizeDelete())
+
+	return cmd
+}
+
+func newCmdInstallationSizeCreate() *cobra.Command {
+	var flags installationSizeCreateFlags
+
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create an installation size profile.",
+		RunE: func(command *cobra.Command, args []string) error {
+			command.SilenceUsage = true
+			client := createClient(command.Context(), flags.clusterFlags)
+
+			spec, err := readSizeProfileSpec(flags.specFile)
+			if err != nil {
+				return err
+			}
+
+			request := &model.CreateSizeProfileRequest{
+				Name:        flags.name,
+				Description: flags.description,
+				Spec:        spec,
+			}
+
+			if flags.dryRun {
+				return runDryRun(request)
+			}
+
+			profile, err := client.CreateSizeProfile(request)
+			if err != nil {
+				return errors.Wrap(err, "failed to create size profile")
+			}
+
+			return printJSON(profile)
+		},
+		PreRun: func(cmd *cobra.Command, args []string) {
+			flags.clusterFlags.addFlags(cmd)
+		},
+	}
+	flags.addFlags(cmd)
+
+	return cmd
+}
+
+func newCmdInstallationSizeGet() *cobra.Command {
+	var flags installationSizeFlags
+
+	cmd := &cobra.Command{
+		Use:   "get",
+		Short: "Get a particular installation size profile.",
+		RunE: func(command *cobra.Command, args []string) error {
+			command.SilenceUsage = true
+			client := createClient(command.Context(), flags.clusterFlags)
+
+			profile, err := client.GetSizeProfile(flags.name)
+			if err != nil {
+				return errors.Wrap(err, "failed to query size profile")
+			}
+			if profile == nil {
+				return nil
+			}
+
+			return printJSON(profile)
+		},
+		PreRun: func(cmd *cobra.Command, args []string) {
+			flags.clusterFlags.addFlags(cmd)
+		},
+	}
+	flags.addFlags(cmd)
+
+	return cmd
+}
+
+func newCmdInstallationSizeList() *cobra.Command {
+	var flags installationSizeListFlags
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List installation size profiles.",
+		RunE: func(command *cobra.Command, args []string) error {
+			command.SilenceUsage = true
+			client := createClient(command.Context(), flags.clusterFlags)
+
+			paging := getPaging(flags.pagingFlags)
+
+			return watchOutput(command.Context(), os.Stdout, flags.watchOptions, func(w io.Writer) error {
+				profiles, err := client.GetSizeProfiles(&model.GetSizeProfilesRequest{
+					Paging: paging,
+				})
+				if err != nil {
+					return errors.Wrap(err, "failed to query size profiles")
+				}
+
+				return sizeProfilePrinter.printList(w, flags.tableOptions, profiles)
+			})
+		},
+		PreRun: func(cmd *cobra.Command, args []string) {
+			flags.clusterFlags.addFlags(cmd)
+		},
+	}
+	flags.addFlags(cmd)
+
+	return cmd
+}
+
+func newCmdInstallationSizeUpdate() *cobra.Command {
+	var flags installationSizeUpdateFlags
+
+	cmd := &cobra.Command{
+		Use:   "update",
+		Short: "Update an installation size profile.",
+		RunE: func(command *cobra.Command, args []string) error {
+			command.SilenceUsage = true
+			client := createClient(command.Context(), flags.clusterFlags)
+
+			request := &model.PatchSizeProfileRequest{}
+			if flags.descriptionChanged {
+				request.Description = &flags.description
+			}
+			if len(flags.specFile) != 0 {
+				spec, err := readSizeProfileSpec(flags.specFile)
+				if err != nil {
+					return err
+				}
+				request.Spec = spec
+			}
+
+			if flags.dryRun {
+				return runDryRun(request)
+			}
+
+			profile, err := client.UpdateSizeProfile(flags.name, request)
+			if err != nil {
+				return errors.Wrap(err, "failed to update size profile")
+			}
+
+			return printJSON(profile)
+		},
+		PreRun: func(cmd *cobra.Command, args []string) {
+			flags.clusterFlags.addFlags(cmd)
+			flags.installationSizeUpdateChanges.addFlags(cmd)
+		},
+	}
+	flags.addFlags(cmd)
+
+	return cmd
+}
+
+func newCmdInstallationSizeDelete() *cobra.Command {
+	var flags installationSizeFlags
+
+	cmd := &cobra.Command{
+		Use:   "delete",
+		Short: "Delete an installation size profile that is not used by any installation.",
+		RunE: func(command *cobra.Command, args []string) error {
+			command.SilenceUsage = true
+			client := createClient(command.Context(), flags.clusterFlags)
+
+			err := client.DeleteSizeProfile(flags.name)
+			if err != nil {
+				return errors.Wrap(err, "failed to delete size profile")
+			}
+
+			return nil
+		},
+		PreRun: func(cmd *cobra.Command, args []string) {
+			flags.clusterFlags.addFlags(cmd)
+		},
+	}
+	flags.addFlags(cmd)
+
+	return cmd
+}
+
+func readSizeProfileSpec(path string) (*model.SizeProfileSpec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read size profile spec file")
+	}
+
+	var spec model.SizeProfileSpec
+	err = json.Unmarshal(data, &spec)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse size profile spec file")
+	}
+
+	return &spec, nil
+}
+
+var sizeProfilePrinter = resourcePrinter[*model.SizeProfile]{
+	defaultTable: defaultSizeProfileTableData,
+}
+
+func defaultSizeProfileTableData(profiles []*model.SizeProfile) ([]string, [][]string) {
+	keys := []string{"NAME", "APP REPLICAS", "APP CPU", "APP MEMORY", "DEDICATED JOB SERVER", "DESCRIPTION"}
+	vals := make([][]string, 0, len(profiles))
+	for _, profile := range profiles {
+		if profile.Spec == nil {
+			vals = append(vals, []string{profile.Name, "", "", "", "", profile.Description})
+			continue
+		}
+		requests := profile.Spec.App.Resources.Requests
+		vals = append(vals, []string{
+			profile.Name,
+			fmt.Sprintf("%d", profile.Spec.App.Replicas),
+			requests.Cpu().String(),
+			requests.Memory().String(),
+			fmt.Sprintf("%t", profile.Spec.DedicatedJobServer()),
+			profile.Description,
+		})
+	}
+	return keys, vals
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+type installationSizeFlags struct {
+	clusterFlags
+	name string
+}
+
+func (flags *installationSizeFlags) addFlags(command *cobra.Command) {
+	command.Flags().StringVar(&flags.name, "name", "", "The name of the installation size profile.")
+	_ = command.MarkFlagRequired("name")
+}
+
+type installationSizeCreateFlags struct {
+	installationSizeFlags
+	description string
+	specFile    string
+}
+
+func (flags *installationSizeCreateFlags) addFlags(command *cobra.Command) {
+	flags.installationSizeFlags.addFlags(command)
+	command.Flags().StringVar(&flags.description, "description", "", "A description of the size profile.")
+	command.Flags().StringVar(&flags.specFile, "spec-file", "", "Path to a JSON file with the app, job server, database and filestore sizes of the profile.")
+	_ = command.MarkFlagRequired("spec-file")
+}
+
+type installationSizeUpdateChanges struct {
+	descriptionChanged bool
+}
+
+func (flags *installationSizeUpdateChanges) addFlags(command *cobra.Command) {
+	flags.descriptionChanged = command.Flags().Changed("description")
+}
+
+type installationSizeUpdateFlags struct {
+	installationSizeFlags
+	installationSizeUpdateChanges
+	description string
+	specFile    string
+}
+
+func (flags *installationSizeUpdateFlags) addFlags(command *cobra.Command) {
+	flags.installationSizeFlags.addFlags(command)
+	command.Flags().StringVar(&flags.description, "description", "", "A new description of the size profile.")
+	command.Flags().StringVar(&flags.specFile, "spec-file", "", "Path to a JSON file with the new sizes of the profile.")
+}
+
+type installationSizeListFlags struct {
+	clusterFlags
+	pagingFlags
+	tableOptions
+	watchOptions
+}
+
+func (flags *installationSizeListFlags) addFlags(command *cobra.Command) {
+	flags.pagingFlags.addFlags(command)
+	flags.tableOptions.addFlags(command)
+	flags.watchOptions.addFlags(command)
+}
@@ -0,0 +1,161 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func newCmdInstallationUsage() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "usage",
+		Short: "Show the resources used by installations and their estimated cost.",
+	}
+
+	cmd.AddCommand(newCmdInstallationUsageGet())
+	cmd.AddCommand(newCmdInstallationUsageReport())
+
+	return cmd
+}
+
+func newCmdInstallationUsageGet() *cobra.Command {
+	var flags installationUsageGetFlags
+
+	cmd := &cobra.Command{
+		Use:   "get",
+		Short: "Get the resources used by an installation.",
+		RunE: func(command *cobra.Command, args []string) error {
+			command.SilenceUsage = true
+			client := createClient(command.Context(), flags.clusterFlags)
+
+			return watchOutput(command.Context(), os.Stdout, flags.watchOptions, func(w io.Writer) error {
+				usage, err := client.GetInstallationUsage(flags.installationID, &model.GetInstallationUsageRequest{
+					From:           usageWindowStart(flags.since),
+					IncludeHistory: flags.includeHistory,
+				})
+				if err != nil {
+					return errors.Wrap(err, "failed to query installation usage")
+				}
+				if usage == nil {
+					return nil
+				}
+
+				return installationUsagePrinter.printObject(w, flags.tableOptions, usage)
+			})
+		},
+		PreRun: func(cmd *cobra.Command, args []string) {
+			flags.clusterFlags.addFlags(cmd)
+		},
+	}
+	flags.addFlags(cmd)
+
+	return cmd
+}
+
+func newCmdInstallationUsageReport() *cobra.Command {
+	var flags installationUsageReportFlags
+
+	cmd := &cobra.Command{
+		Use:   "report",
+		Short: "Report the resources used by the installations of the fleet by owner or group.",
+		RunE: func(command *cobra.Command, args []string) error {
+			command.SilenceUsage = true
+			client := createClient(command.Context(), flags.clusterFlags)
+
+			return watchOutput(command.Context(), os.Stdout, flags.watchOptions, func(w io.Writer) error {
+				report, err := client.GetUsageReport(&model.GetUsageReportRequest{
+					GroupBy: flags.groupBy,
+					From:    usageWindowStart(flags.since),
+				})
+				if err != nil {
+					return errors.Wrap(err, "failed to query usage report")
+				}
+
+				return usageReportPrinter.print(w, flags.tableOptions, report.Entries, report)
+			})
+		},
+		PreRun: func(cmd *cobra.Command, args []string) {
+			flags.clusterFlags.addFlags(cmd)
+		},
+	}
+	flags.addFlags(cmd)
+
+	return cmd
+}
+
+// usageWindowStart returns the start of a usage window ending now, or zero
+// to use the server default.
+func usageWindowStart(since time.Duration) int64 {
+	if since <= 0 {
+		return 0
+	}
+	return model.GetMillisAtTime(time.Now().Add(-since))
+}
+
+var installationUsagePrinter = resourcePrinter[*model.InstallationUsageSummary]{
+	defaultTable: defaultInstallationUsageTableData,
+	id:           func(summary *model.InstallationUsageSummary) string { return summary.InstallationID },
+}
+
+func defaultInstallationUsageTableData(summaries []*model.InstallationUsageSummary) ([]string, [][]string) {
+	keys := []string{"INSTALLATION", "SAMPLES", "CPU", "MAX CPU", "MEMORY", "MAX MEMORY", "VOLUMES", "FILESTORE", "DATABASE INSTANCES", "MONTHLY COST"}
+	vals := make([][]string, 0, len(summaries))
+	for _, summary := range summaries {
+		vals = append(vals, []string{
+			summary.InstallationID,
+			fmt.Sprintf("%d", summary.Samples),
+			fmt.Sprintf("%dm", summary.AvgMilliCPU),
+			fmt.Sprintf("%dm", summary.MaxMilliCPU),
+			formatGiB(summary.AvgMemoryBytes),
+			formatGiB(summary.MaxMemoryBytes),
+			formatGiB(summary.VolumeBytes),
+			formatGiB(summary.FilestoreBytes),
+			fmt.Sprintf("%.2f", float64(len(summary.DatabaseInstanceClasses))*summary.DatabaseShare),
+			formatUsageCost(summary.Cost),
+		})
+	}
+	return keys, vals
+}
+
+var usageReportPrinter = resourcePrinter[*model.UsageReportEntry]{
+	defaultTable: defaultUsageReportTableData,
+	id:           func(entry *model.UsageReportEntry) string { return entry.Key },
+}
+
+func defaultUsageReportTableData(entries []*model.UsageReportEntry) ([]string, [][]string) {
+	keys := []string{"KEY", "INSTALLATIONS", "CPU", "MEMORY", "VOLUMES", "FILESTORE", "DATABASE INSTANCES", "MONTHLY COST"}
+	vals := make([][]string, 0, len(entries))
+	for _, entry := range entries {
+		vals = append(vals, []string{
+			entry.Key,
+			fmt.Sprintf("%d", entry.Installations),
+			fmt.Sprintf("%dm", entry.AvgMilliCPU),
+			formatGiB(entry.AvgMemoryBytes),
+			formatGiB(entry.VolumeBytes),
+			formatGiB(entry.FilestoreBytes),
+			fmt.Sprintf("%.2f", entry.DatabaseInstances),
+			formatUsageCost(entry.Cost),
+		})
+	}
+	return keys, vals
+}
+
+func formatUsageCost(cost *model.UsageCostEstimate) string {
+	if cost == nil {
+		return ""
+	}
+	return fmt.Sprintf("%.2f %s", cost.Total, cost.Currency)
+}
+
+func formatGiB(bytes int64) string {
+	return fmt.Sprintf("%.2fGiB", float64(bytes)/(1024*1024*1024))
+}
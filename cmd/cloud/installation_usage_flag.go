@@ -0,0 +1,41 @@
+package main
+
+import (
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/spf13/cobra"
+)
+
+type installationUsageGetFlags struct {
+	clusterFlags
+	tableOptions
+	watchOptions
+	installationID string
+	since          time.Duration
+	includeHistory bool
+}
+
+func (flags *installationUsageGetFlags) addFlags(command *cobra.Command) {
+	flags.tableOptions.addFlags(command)
+	flags.watchOptions.addFlags(command)
+	command.Flags().StringVar(&flags.installationID, "installation", "", "The id of the installation to get the usage of.")
+	command.Flags().DurationVar(&flags.since, "since", 0, "The length of the usage window ending now. Defaults to the last day when not set.")
+	command.Flags().BoolVar(&flags.includeHistory, "include-history", false, "Whether to include the usage samples of the window or not.")
+	_ = command.MarkFlagRequired("installation")
+}
+
+type installationUsageReportFlags struct {
+	clusterFlags
+	tableOptions
+	watchOptions
+	groupBy string
+	since   time.Duration
+}
+
+func (flags *installationUsageReportFlags) addFlags(command *cobra.Command) {
+	flags.tableOptions.addFlags(command)
+	flags.watchOptions.addFlags(command)
+	command.Flags().StringVar(&flags.groupBy, "group-by", model.UsageReportGroupByOwner, "Group the usage of installations by owner or group.")
+	command.Flags().DurationVar(&flags.since, "since", 0, "The length of the usage window ending now. Defaults to the last day when not set.")
+}
@@ -0,0 +1,48 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstallationUsagePrinter(t *testing.T) {
+	summary := &model.InstallationUsageSummary{
+		InstallationID:          "installation1",
+		Samples:                 2,
+		AvgMilliCPU:             250,
+		MaxMilliCPU:             500,
+		AvgMemoryBytes:          1024 * 1024 * 1024,
+		DatabaseInstanceClasses: model.DatabaseInstanceClasses{"db.r5.large", "db.r5.large"},
+		DatabaseShare:           0.25,
+		Cost:                    &model.UsageCostEstimate{Currency: "USD", Total: 12.5},
+	}
+
+	printObject := func(t *testing.T, output string) string {
+		buffer := &bytes.Buffer{}
+		err := installationUsagePrinter.printObject(buffer, tableOptions{output: output}, summary)
+		require.NoError(t, err)
+		return buffer.String()
+	}
+
+	t.Run("name", func(t *testing.T) {
+		assert.Equal(t, "installation1\n", printObject(t, "name"))
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		out := printObject(t, "csv")
+		assert.Contains(t, out, "INSTALLATION,SAMPLES,CPU,MAX CPU,MEMORY")
+		assert.Contains(t, out, "installation1,2,250m,500m,1.00GiB,0.00GiB,0.00GiB,0.00GiB,0.50,12.50 USD")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		assert.Contains(t, printObject(t, "json"), `"InstallationID": "installation1"`)
+	})
+}
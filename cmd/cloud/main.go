@@ -139,8 +139,11 @@ func init() {
 	rootCmd.AddCommand(newCmdWorkbench())
 	rootCmd.AddCommand(newCmdCompletion())
 	rootCmd.AddCommand(newCmdDashboard())
+	rootCmd.AddCommand(newCmdUI())
 	rootCmd.AddCommand(newCmdEvents())
 	rootCmd.AddCommand(newCmdSubscription())
+	rootCmd.AddCommand(newCmdApply())
+	rootCmd.AddCommand(newCmdDiff())
 	rootCmd.AddCommand(newCmdLogin())
 	rootCmd.AddCommand(newCmdContexts())
 }
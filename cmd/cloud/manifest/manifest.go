@@ -0,0 +1,386 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+// Package manifest implements declarative management of provisioner
+// resources described in YAML manifests.
+package manifest
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+	k8sYAML "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+)
+
+// Kind is a kind of resource described by a manifest.
+type Kind string
+
+const (
+	// KindInstallation describes an installation identified by its name or
+	// by any of its DNS names.
+	KindInstallation Kind = "Installation"
+	// KindGroup describes a group identified by its name.
+	KindGroup Kind = "Group"
+	// KindWebhook describes a webhook identified by its owner and URL. The
+	// URL of a webhook is unique across owners.
+	KindWebhook Kind = "Webhook"
+	// KindSubscription describes an event subscription identified by its
+	// owner and name.
+	KindSubscription Kind = "Subscription"
+	// KindClusterAnnotations describes the annotations of a cluster.
+	KindClusterAnnotations Kind = "ClusterAnnotations"
+)
+
+// InstallationSpec is the desired state of an installation. Empty fields are
+// not managed, so that the server defaults and changes made by other tools
+// are preserved.
+type InstallationSpec struct {
+	// Name identifies the installation together with its DNS names.
+	// Defaults to the first label of the first DNS name.
+	Name     string   `json:"name,omitempty"`
+	DNSNames []string `json:"dnsNames,omitempty"`
+	OwnerID  string   `json:"ownerID"`
+	// Group is the name of the group the installation belongs to.
+	Group         string          `json:"group,omitempty"`
+	Version       string          `json:"version,omitempty"`
+	Image         string          `json:"image,omitempty"`
+	Size          string          `json:"size,omitempty"`
+	License       string          `json:"license,omitempty"`
+	Affinity      string          `json:"affinity,omitempty"`
+	Database      string          `json:"database,omitempty"`
+	Filestore     string          `json:"filestore,omitempty"`
+	MattermostEnv model.EnvVarMap `json:"mattermostEnv,omitempty"`
+	PriorityEnv   model.EnvVarMap `json:"priorityEnv,omitempty"`
+	Annotations   []string        `json:"annotations,omitempty"`
+}
+
+// GroupSpec is the desired state of a group.
+type GroupSpec struct {
+	Name          string          `json:"name"`
+	Description   string          `json:"description,omitempty"`
+	Version       string          `json:"version,omitempty"`
+	Image         string          `json:"image,omitempty"`
+	MaxRolling    *int64          `json:"maxRolling,omitempty"`
+	MattermostEnv model.EnvVarMap `json:"mattermostEnv,omitempty"`
+	Annotations   []string        `json:"annotations,omitempty"`
+}
+
+// WebhookSpec is the desired state of a webhook.
+type WebhookSpec struct {
+	OwnerID string        `json:"ownerID"`
+	URL     string        `json:"url"`
+	Headers model.Headers `json:"headers,omitempty"`
+}
+
+// SubscriptionSpec is the desired state of an event subscription.
+type SubscriptionSpec struct {
+	Name             string                          `json:"name"`
+	OwnerID          string                          `json:"ownerID"`
+	URL              string                          `json:"url"`
+	EventType        model.EventType                 `json:"eventType"`
+	FailureThreshold string                          `json:"failureThreshold,omitempty"`
+	Headers          model.Headers                   `json:"headers,omitempty"`
+	Filter           *model.SubscriptionFilter       `json:"filter,omitempty"`
+	PayloadFormat    model.SubscriptionPayloadFormat `json:"payloadFormat,omitempty"`
+	Sink             model.SubscriptionSinkType      `json:"sink,omitempty"`
+}
+
+// ClusterAnnotationsSpec is the desired set of annotations of a cluster.
+type ClusterAnnotationsSpec struct {
+	ClusterID   string   `json:"cluster"`
+	Annotations []string `json:"annotations"`
+}
+
+// Manifests is a set of resources parsed from manifest files.
+type Manifests struct {
+	Installations      []*InstallationSpec
+	Groups             []*GroupSpec
+	Webhooks           []*WebhookSpec
+	Subscriptions      []*SubscriptionSpec
+	ClusterAnnotations []*ClusterAnnotationsSpec
+}
+
+type document struct {
+	Kind Kind            `json:"kind"`
+	Spec json.RawMessage `json:"spec"`
+}
+
+// Load reads manifests from the given files and directories. Directories are
+// walked recursively and all .yaml and .yml files are read in lexical order.
+func Load(paths []string) (*Manifests, error) {
+	manifests := &Manifests{}
+	for _, path := range paths {
+		files, err := manifestFiles(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			err = loadFile(manifests, file)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to load manifest %s", file)
+			}
+		}
+	}
+
+	err := manifests.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return manifests, nil
+}
+
+func manifestFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to stat manifest path")
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	var files []string
+	err = filepath.WalkDir(path, func(file string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		ext := strings.ToLower(filepath.Ext(file))
+		if !entry.IsDir() && (ext == ".yaml" || ext == ".yml") {
+			files = append(files, file)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read manifest directory %s", path)
+	}
+	sort.Strings(files)
+
+	return files, nil
+}
+
+func loadFile(manifests *Manifests, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return errors.Wrap(err, "failed to open file")
+	}
+	defer file.Close()
+
+	return manifests.Parse(file)
+}
+
+// Parse adds resources from a YAML stream with one or more documents.
+func (m *Manifests) Parse(reader io.Reader) error {
+	yamlReader := k8sYAML.NewYAMLReader(bufio.NewReader(reader))
+	for {
+		data, err := yamlReader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrap(err, "failed to read YAML document")
+		}
+		if len(bytes.TrimSpace(data)) == 0 {
+			continue
+		}
+
+		err = m.parseDocument(data)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (m *Manifests) parseDocument(data []byte) error {
+	jsonData, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return errors.Wrap(err, "failed to convert YAML document")
+	}
+	// Documents with comments only are converted to null.
+	if string(jsonData) == "null" {
+		return nil
+	}
+
+	var doc document
+	err = decodeStrict(jsonData, &doc)
+	if err != nil {
+		return errors.Wrap(err, "failed to decode document")
+	}
+
+	switch doc.Kind {
+	case KindInstallation:
+		spec := &InstallationSpec{}
+		err = decodeStrict(doc.Spec, spec)
+		m.Installations = append(m.Installations, spec)
+	case KindGroup:
+		spec := &GroupSpec{}
+		err = decodeStrict(doc.Spec, spec)
+		m.Groups = append(m.Groups, spec)
+	case KindWebhook:
+		spec := &WebhookSpec{}
+		err = decodeStrict(doc.Spec, spec)
+		m.Webhooks = append(m.Webhooks, spec)
+	case KindSubscription:
+		spec := &SubscriptionSpec{}
+		err = decodeStrict(doc.Spec, spec)
+		m.Subscriptions = append(m.Subscriptions, spec)
+	case KindClusterAnnotations:
+		spec := &ClusterAnnotationsSpec{}
+		err = decodeStrict(doc.Spec, spec)
+		m.ClusterAnnotations = append(m.ClusterAnnotations, spec)
+	default:
+		return errors.Errorf("unknown kind %q", doc.Kind)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to decode %s spec", doc.Kind)
+	}
+
+	return nil
+}
+
+func decodeStrict(data []byte, out interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(out)
+}
+
+// Validate validates the manifests and sets defaults of the specs.
+// Every resource can be declared only once.
+func (m *Manifests) Validate() error {
+	seen := map[string]bool{}
+	checkUnique := func(kind Kind, id string) error {
+		key := string(kind) + "/" + id
+		if seen[key] {
+			return errors.Errorf("%s %s is declared more than once", kind, id)
+		}
+		seen[key] = true
+		return nil
+	}
+
+	for _, spec := range m.Installations {
+		if spec.Name == "" && len(spec.DNSNames) > 0 {
+			spec.Name = strings.Split(spec.DNSNames[0], ".")[0]
+		}
+		spec.Name = strings.ToLower(spec.Name)
+		for i := range spec.DNSNames {
+			spec.DNSNames[i] = strings.ToLower(spec.DNSNames[i])
+			if err := checkUnique(KindInstallation, "DNS name "+spec.DNSNames[i]); err != nil {
+				return err
+			}
+		}
+		if spec.Name == "" {
+			return errors.New("installation must have name or DNS names")
+		}
+		if spec.OwnerID == "" {
+			return errors.Errorf("installation %s must have owner", spec.Name)
+		}
+		if spec.Size != "" {
+			// Size profiles are only known to the server, which checks
+			// that they exist when the installation is applied.
+			if err := model.ValidateInstallationSize(spec.Size); err != nil {
+				return errors.Wrapf(err, "installation %s has invalid size", spec.Name)
+			}
+		}
+		for name, envs := range map[string]model.EnvVarMap{"mattermostEnv": spec.MattermostEnv, "priorityEnv": spec.PriorityEnv} {
+			if err := envs.Validate(); err != nil {
+				return errors.Wrapf(err, "installation %s has invalid %s", spec.Name, name)
+			}
+		}
+		if err := checkUnique(KindInstallation, spec.Name); err != nil {
+			return err
+		}
+	}
+
+	for _, spec := range m.Groups {
+		if spec.Name == "" {
+			return errors.New("group must have name")
+		}
+		if err := spec.MattermostEnv.Validate(); err != nil {
+			return errors.Wrapf(err, "group %s has invalid mattermostEnv", spec.Name)
+		}
+		if err := checkUnique(KindGroup, spec.Name); err != nil {
+			return err
+		}
+	}
+
+	for _, spec := range m.Webhooks {
+		if spec.OwnerID == "" || spec.URL == "" {
+			return errors.New("webhook must have owner and URL")
+		}
+		if err := checkUnique(KindWebhook, spec.URL); err != nil {
+			return err
+		}
+	}
+
+	for _, spec := range m.Subscriptions {
+		if spec.Name == "" || spec.OwnerID == "" {
+			return errors.New("subscription must have name and owner")
+		}
+		_, err := spec.createRequest()
+		if err != nil {
+			return errors.Wrapf(err, "subscription %s is invalid", spec.Name)
+		}
+		if err := checkUnique(KindSubscription, subscriptionID(spec.OwnerID, spec.Name)); err != nil {
+			return err
+		}
+	}
+
+	for _, spec := range m.ClusterAnnotations {
+		if spec.ClusterID == "" {
+			return errors.New("cluster annotations must have cluster")
+		}
+		if err := checkUnique(KindClusterAnnotations, spec.ClusterID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// createRequest converts the spec to a request and validates it.
+func (s *SubscriptionSpec) createRequest() (*model.CreateSubscriptionRequest, error) {
+	var failureThreshold time.Duration
+	if s.FailureThreshold != "" {
+		var err error
+		failureThreshold, err = time.ParseDuration(s.FailureThreshold)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid failure threshold")
+		}
+	}
+
+	request := &model.CreateSubscriptionRequest{
+		Name:             s.Name,
+		URL:              s.URL,
+		OwnerID:          s.OwnerID,
+		EventType:        s.EventType,
+		FailureThreshold: failureThreshold,
+		Headers:          s.Headers,
+		Filter:           s.Filter,
+		PayloadFormat:    s.PayloadFormat,
+		SinkType:         s.Sink,
+	}
+	_, err := request.ToSubscription()
+	if err != nil {
+		return nil, err
+	}
+
+	return request, nil
+}
+
+func webhookID(ownerID, url string) string {
+	return ownerID + "/" + url
+}
+
+func subscriptionID(ownerID, name string) string {
+	return ownerID + "/" + name
+}
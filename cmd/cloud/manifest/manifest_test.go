@@ -0,0 +1,187 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testManifests = `
+# Workspaces of the test team.
+kind: Group
+spec:
+  name: test-group
+  version: "9.5"
+  maxRolling: 2
+  mattermostEnv:
+    MM_FEATURE:
+      value: "true"
+  annotations: [multi-tenant]
+---
+kind: Installation
+spec:
+  dnsNames: [Workspace.example.com]
+  ownerID: team
+  group: test-group
+  size: 100users
+  annotations: [multi-tenant]
+---
+kind: Webhook
+spec:
+  ownerID: team
+  url: https://hooks.example.com
+  headers:
+  - key: X-Token
+    value_from_env: TOKEN
+---
+kind: Subscription
+spec:
+  name: states
+  ownerID: team
+  url: nats://nats:4222/events
+  sink: nats
+  eventType: resourceStateChange
+  failureThreshold: 10m
+  filter:
+    resourceTypes: [installation]
+---
+kind: ClusterAnnotations
+spec:
+  cluster: cluster1
+  annotations: [multi-tenant, customer-a]
+---
+`
+
+func TestParse(t *testing.T) {
+	manifests := &Manifests{}
+	err := manifests.Parse(strings.NewReader(testManifests))
+	require.NoError(t, err)
+	require.NoError(t, manifests.Validate())
+
+	maxRolling := int64(2)
+	tokenEnv := "TOKEN"
+	assert.Equal(t, []*GroupSpec{{
+		Name:          "test-group",
+		Version:       "9.5",
+		MaxRolling:    &maxRolling,
+		MattermostEnv: model.EnvVarMap{"MM_FEATURE": {Value: "true"}},
+		Annotations:   []string{"multi-tenant"},
+	}}, manifests.Groups)
+	assert.Equal(t, []*InstallationSpec{{
+		Name:        "workspace",
+		DNSNames:    []string{"workspace.example.com"},
+		OwnerID:     "team",
+		Group:       "test-group",
+		Size:        "100users",
+		Annotations: []string{"multi-tenant"},
+	}}, manifests.Installations)
+	assert.Equal(t, []*WebhookSpec{{
+		OwnerID: "team",
+		URL:     "https://hooks.example.com",
+		Headers: model.Headers{{Key: "X-Token", ValueFromEnv: &tokenEnv}},
+	}}, manifests.Webhooks)
+	require.Len(t, manifests.Subscriptions, 1)
+	assert.Equal(t, []model.ResourceType{model.TypeInstallation}, manifests.Subscriptions[0].Filter.ResourceTypes)
+	request, err := manifests.Subscriptions[0].createRequest()
+	require.NoError(t, err)
+	assert.Equal(t, 10*time.Minute, request.FailureThreshold)
+	assert.Equal(t, []*ClusterAnnotationsSpec{{
+		ClusterID:   "cluster1",
+		Annotations: []string{"multi-tenant", "customer-a"},
+	}}, manifests.ClusterAnnotations)
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, testCase := range []struct {
+		description string
+		manifest    string
+	}{
+		{"unknown kind", "kind: Cluster\nspec: {}"},
+		{"unknown field", "kind: Group\nspec:\n  name: test\n  size: large"},
+		{"invalid YAML", "kind: [Group"},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			manifests := &Manifests{}
+			err := manifests.Parse(strings.NewReader(testCase.manifest))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, testCase := range []struct {
+		description string
+		manifests   *Manifests
+	}{
+		{"installation without name", &Manifests{Installations: []*InstallationSpec{{OwnerID: "team"}}}},
+		{"installation without owner", &Manifests{Installations: []*InstallationSpec{{Name: "test"}}}},
+		{"installation with invalid size", &Manifests{Installations: []*InstallationSpec{{Name: "test", OwnerID: "team", Size: "huge-size"}}}},
+		{"duplicate installation", &Manifests{Installations: []*InstallationSpec{
+			{Name: "test", OwnerID: "team"},
+			{DNSNames: []string{"test.example.com"}, OwnerID: "team"},
+		}}},
+		{"duplicate installation DNS name", &Manifests{Installations: []*InstallationSpec{
+			{Name: "test", DNSNames: []string{"test.example.com"}, OwnerID: "team"},
+			{Name: "other", DNSNames: []string{"other.example.com", "Test.example.com"}, OwnerID: "team"},
+		}}},
+		{"group without name", &Manifests{Groups: []*GroupSpec{{}}}},
+		{"webhook without URL", &Manifests{Webhooks: []*WebhookSpec{{OwnerID: "team"}}}},
+		{"duplicate webhook URL", &Manifests{Webhooks: []*WebhookSpec{
+			{OwnerID: "team", URL: "https://hooks.example.com"},
+			{OwnerID: "other-team", URL: "https://hooks.example.com"},
+		}}},
+		{"subscription with invalid threshold", &Manifests{Subscriptions: []*SubscriptionSpec{{
+			Name: "test", OwnerID: "team", URL: "https://test", EventType: model.ResourceStateChangeEventType, FailureThreshold: "soon",
+		}}}},
+		{"subscription with invalid sink", &Manifests{Subscriptions: []*SubscriptionSpec{{
+			Name: "test", OwnerID: "team", URL: "https://test", EventType: model.ResourceStateChangeEventType, Sink: "carrier-pigeon",
+		}}}},
+		{"cluster annotations without cluster", &Manifests{ClusterAnnotations: []*ClusterAnnotationsSpec{{}}}},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			assert.Error(t, testCase.manifests.Validate())
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "groups"), 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "groups", "group.yaml"), []byte("kind: Group\nspec:\n  name: test-group\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "installation.yml"), []byte("kind: Installation\nspec:\n  name: test\n  ownerID: team\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("# Not a manifest"), 0600))
+
+	t.Run("directory", func(t *testing.T) {
+		manifests, err := Load([]string{dir})
+		require.NoError(t, err)
+		assert.Len(t, manifests.Groups, 1)
+		assert.Len(t, manifests.Installations, 1)
+	})
+
+	t.Run("file", func(t *testing.T) {
+		manifests, err := Load([]string{filepath.Join(dir, "installation.yml")})
+		require.NoError(t, err)
+		assert.Empty(t, manifests.Groups)
+		assert.Len(t, manifests.Installations, 1)
+	})
+
+	t.Run("duplicate", func(t *testing.T) {
+		_, err := Load([]string{dir, filepath.Join(dir, "installation.yml")})
+		assert.Error(t, err)
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		_, err := Load([]string{filepath.Join(dir, "missing")})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,962 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package manifest
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+)
+
+// Client is the subset of model.Client used to read and change resources.
+type Client interface {
+	GetInstallations(request *model.GetInstallationsRequest) ([]*model.InstallationDTO, error)
+	CreateInstallation(request *model.CreateInstallationRequest) (*model.InstallationDTO, error)
+	UpdateInstallation(installationID string, request *model.PatchInstallationRequest) (*model.InstallationDTO, error)
+	DeleteInstallation(installationID string) error
+	AddInstallationDNS(installationID string, request *model.AddDNSRecordRequest) (*model.InstallationDTO, error)
+	AddInstallationAnnotations(installationID string, annotationsRequest *model.AddAnnotationsRequest) (*model.InstallationDTO, error)
+	DeleteInstallationAnnotation(installationID string, annotationName string) error
+	JoinGroup(groupID, installationID string) error
+	LeaveGroup(installationID string, request *model.LeaveGroupRequest) error
+
+	GetGroups(request *model.GetGroupsRequest) ([]*model.GroupDTO, error)
+	CreateGroup(request *model.CreateGroupRequest) (*model.GroupDTO, error)
+	UpdateGroup(request *model.PatchGroupRequest) (*model.GroupDTO, error)
+	DeleteGroup(groupID string) error
+	AddGroupAnnotations(groupID string, annotationsRequest *model.AddAnnotationsRequest) (*model.GroupDTO, error)
+	DeleteGroupAnnotation(groupID string, annotationName string) error
+
+	GetWebhooks(request *model.GetWebhooksRequest) ([]*model.Webhook, error)
+	CreateWebhook(request *model.CreateWebhookRequest) (*model.Webhook, error)
+	DeleteWebhook(webhookID string) error
+
+	ListSubscriptions(request *model.ListSubscriptionsRequest) ([]*model.Subscription, error)
+	CreateSubscription(request *model.CreateSubscriptionRequest) (*model.Subscription, error)
+	DeleteSubscription(subID string) error
+
+	GetCluster(clusterID string) (*model.ClusterDTO, error)
+	AddClusterAnnotations(clusterID string, annotationsRequest *model.AddAnnotationsRequest) (*model.ClusterDTO, error)
+	DeleteClusterAnnotation(clusterID string, annotationName string) error
+}
+
+// Operation is a kind of change of a resource.
+type Operation string
+
+const (
+	// OperationCreate creates a new resource.
+	OperationCreate Operation = "create"
+	// OperationUpdate changes an existing resource in place.
+	OperationUpdate Operation = "update"
+	// OperationReplace deletes a resource and creates it again, because
+	// the resource cannot be updated.
+	OperationReplace Operation = "replace"
+	// OperationDelete deletes a resource which is not declared.
+	OperationDelete Operation = "delete"
+)
+
+// Action is a change of a single resource.
+type Action struct {
+	Kind      Kind
+	Name      string
+	Operation Operation
+	// Changes describe the changed fields. Values of environment variables
+	// and licenses are never included.
+	Changes []string
+
+	steps []func() error
+}
+
+// Options configures computation of the Plan.
+type Options struct {
+	// Prune deletes resources which are not declared in the manifests.
+	// Only kinds present in the manifests are pruned and installations,
+	// webhooks and subscriptions are pruned only for owners present in the
+	// manifests.
+	Prune bool
+}
+
+// Plan is an ordered list of actions bringing the server to the state
+// declared in the manifests.
+type Plan struct {
+	Actions []*Action
+	// Warnings describe differences which cannot be reconciled.
+	Warnings []string
+
+	// groupIDs maps group names to IDs, including groups created while
+	// applying the plan.
+	groupIDs map[string]string
+}
+
+// NewPlan compares the manifests with the server and returns the plan of
+// changes. Resources are created and updated before any are deleted, so
+// that installations can move to new groups before old groups are pruned.
+func NewPlan(client Client, manifests *Manifests, opts Options) (*Plan, error) {
+	p := &planner{
+		client:     client,
+		opts:       opts,
+		plan:       &Plan{groupIDs: map[string]string{}},
+		groupNames: map[string]string{},
+	}
+
+	err := p.planGroups(manifests.Groups, manifests.Installations)
+	if err != nil {
+		return nil, err
+	}
+	err = p.planInstallations(manifests.Installations)
+	if err != nil {
+		return nil, err
+	}
+	err = p.planWebhooks(manifests.Webhooks)
+	if err != nil {
+		return nil, err
+	}
+	err = p.planSubscriptions(manifests.Subscriptions)
+	if err != nil {
+		return nil, err
+	}
+	err = p.planClusterAnnotations(manifests.ClusterAnnotations)
+	if err != nil {
+		return nil, err
+	}
+
+	// Delete installations before groups they may belong to.
+	p.plan.Actions = append(p.plan.Actions, p.deletes...)
+
+	return p.plan, nil
+}
+
+// IsEmpty returns true if the server already matches the manifests.
+func (p *Plan) IsEmpty() bool {
+	return len(p.Actions) == 0
+}
+
+// Print writes a human readable description of the plan.
+func (p *Plan) Print(w io.Writer) {
+	for _, warning := range p.Warnings {
+		fmt.Fprintf(w, "! %s\n", warning)
+	}
+	if p.IsEmpty() {
+		fmt.Fprintln(w, "No changes.")
+		return
+	}
+	for _, action := range p.Actions {
+		fmt.Fprintln(w, action.String())
+		for _, change := range action.Changes {
+			fmt.Fprintf(w, "    %s\n", change)
+		}
+	}
+}
+
+// Apply executes the plan, stopping at the first failed action.
+func (p *Plan) Apply(w io.Writer) error {
+	for _, warning := range p.Warnings {
+		fmt.Fprintf(w, "! %s\n", warning)
+	}
+	for _, action := range p.Actions {
+		fmt.Fprintln(w, action.String())
+		for _, step := range action.steps {
+			err := step()
+			if err != nil {
+				return errors.Wrapf(err, "failed to %s %s %s", action.Operation, action.Kind, action.Name)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (a *Action) String() string {
+	symbols := map[Operation]string{
+		OperationCreate:  "+",
+		OperationUpdate:  "~",
+		OperationReplace: "-/+",
+		OperationDelete:  "-",
+	}
+	return fmt.Sprintf("%s %s %s (%s)", symbols[a.Operation], a.Kind, a.Name, a.Operation)
+}
+
+type planner struct {
+	client     Client
+	opts       Options
+	plan       *Plan
+	groupNames map[string]string
+	deletes    []*Action
+}
+
+func (p *planner) add(action *Action) {
+	if action != nil {
+		p.plan.Actions = append(p.plan.Actions, action)
+	}
+}
+
+func (p *planner) delete(kind Kind, name string, step func() error) {
+	p.deletes = append(p.deletes, &Action{Kind: kind, Name: name, Operation: OperationDelete, steps: []func() error{step}})
+}
+
+func (p *planner) warn(format string, args ...interface{}) {
+	p.plan.Warnings = append(p.plan.Warnings, fmt.Sprintf(format, args...))
+}
+
+func (p *planner) planGroups(specs []*GroupSpec, installationSpecs []*InstallationSpec) error {
+	// Groups are also needed to resolve group names of installations.
+	if len(specs) == 0 && len(installationSpecs) == 0 {
+		return nil
+	}
+
+	groups, err := p.client.GetGroups(&model.GetGroupsRequest{Paging: model.AllPagesNotDeleted()})
+	if err != nil {
+		return errors.Wrap(err, "failed to get groups")
+	}
+	existing := map[string]*model.GroupDTO{}
+	for _, group := range groups {
+		existing[group.Name] = group
+		p.plan.groupIDs[group.Name] = group.ID
+		p.groupNames[group.ID] = group.Name
+	}
+	if len(existing) != len(groups) {
+		for _, spec := range specs {
+			if countGroups(groups, spec.Name) > 1 {
+				return errors.Errorf("group name %s is not unique on the server", spec.Name)
+			}
+		}
+	}
+
+	declared := map[string]bool{}
+	for _, spec := range specs {
+		declared[spec.Name] = true
+		group, ok := existing[spec.Name]
+		if !ok {
+			p.add(p.createGroup(spec))
+			continue
+		}
+		p.add(p.updateGroup(spec, group))
+	}
+
+	if !p.opts.Prune || len(specs) == 0 {
+		return nil
+	}
+	for _, group := range groups {
+		if declared[group.Name] {
+			continue
+		}
+		groupID := group.ID
+		p.delete(KindGroup, group.Name, func() error {
+			return p.client.DeleteGroup(groupID)
+		})
+	}
+
+	return nil
+}
+
+func countGroups(groups []*model.GroupDTO, name string) int {
+	count := 0
+	for _, group := range groups {
+		if group.Name == name {
+			count++
+		}
+	}
+	return count
+}
+
+func (p *planner) createGroup(spec *GroupSpec) *Action {
+	request := &model.CreateGroupRequest{
+		Name:          spec.Name,
+		Description:   spec.Description,
+		Version:       spec.Version,
+		Image:         spec.Image,
+		MattermostEnv: spec.MattermostEnv,
+		Annotations:   spec.Annotations,
+	}
+	if spec.MaxRolling != nil {
+		request.MaxRolling = *spec.MaxRolling
+	}
+
+	return &Action{
+		Kind:      KindGroup,
+		Name:      spec.Name,
+		Operation: OperationCreate,
+		steps: []func() error{func() error {
+			group, err := p.client.CreateGroup(request)
+			if err != nil {
+				return err
+			}
+			p.plan.groupIDs[spec.Name] = group.ID
+			return nil
+		}},
+	}
+}
+
+func (p *planner) updateGroup(spec *GroupSpec, group *model.GroupDTO) *Action {
+	action := &Action{Kind: KindGroup, Name: spec.Name, Operation: OperationUpdate}
+	patch := &model.PatchGroupRequest{ID: group.ID}
+	patched := false
+
+	patchString := func(field, current, desired string, target **string) {
+		if desired != "" && desired != current {
+			*target = &desired
+			patched = true
+			action.Changes = append(action.Changes, valueChange(field, current, desired))
+		}
+	}
+	patchString("description", group.Description, spec.Description, &patch.Description)
+	patchString("version", group.Version, spec.Version, &patch.Version)
+	patchString("image", group.Image, spec.Image, &patch.Image)
+	if spec.MaxRolling != nil && *spec.MaxRolling != group.MaxRolling {
+		patch.MaxRolling = spec.MaxRolling
+		patched = true
+		action.Changes = append(action.Changes, valueChange("maxRolling", group.MaxRolling, *spec.MaxRolling))
+	}
+	if envPatch, changes := diffEnv("mattermostEnv", group.MattermostEnv, spec.MattermostEnv); envPatch != nil {
+		patch.MattermostEnv = envPatch
+		patched = true
+		action.Changes = append(action.Changes, changes...)
+	}
+	if patched {
+		action.steps = append(action.steps, func() error {
+			_, err := p.client.UpdateGroup(patch)
+			return err
+		})
+	}
+
+	added, removed := diffAnnotations(group.Annotations, spec.Annotations)
+	action.Changes = append(action.Changes, annotationChanges(added, removed)...)
+	if len(added) > 0 {
+		action.steps = append(action.steps, func() error {
+			_, err := p.client.AddGroupAnnotations(group.ID, &model.AddAnnotationsRequest{Annotations: added})
+			return err
+		})
+	}
+	for _, annotation := range removed {
+		annotation := annotation
+		action.steps = append(action.steps, func() error {
+			return p.client.DeleteGroupAnnotation(group.ID, annotation)
+		})
+	}
+
+	if len(action.steps) == 0 {
+		return nil
+	}
+	return action
+}
+
+func (p *planner) planInstallations(specs []*InstallationSpec) error {
+	if len(specs) == 0 {
+		return nil
+	}
+
+	installations, err := p.client.GetInstallations(&model.GetInstallationsRequest{
+		Paging: model.AllPagesNotDeleted(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to get installations")
+	}
+	existing := map[string]*model.InstallationDTO{}
+	existingDNS := map[string]*model.InstallationDTO{}
+	for _, installation := range installations {
+		existing[installation.Name] = installation
+		for _, record := range installation.DNSRecords {
+			existingDNS[strings.ToLower(record.DomainName)] = installation
+		}
+	}
+
+	// Declared installations are tracked by ID, as they may be matched by
+	// a DNS name rather than by name.
+	declared := map[string]string{}
+	owners := map[string]bool{}
+	for _, spec := range specs {
+		owners[spec.OwnerID] = true
+		installation, err := matchInstallation(spec, existing, existingDNS)
+		if err != nil {
+			return err
+		}
+		if installation == nil {
+			p.add(p.createInstallation(spec))
+			continue
+		}
+		if other, ok := declared[installation.ID]; ok {
+			return errors.Errorf("installations %s and %s both match installation %s", other, spec.Name, installation.Name)
+		}
+		declared[installation.ID] = spec.Name
+		if installation.Name != spec.Name {
+			p.warn("installation %s is named %s on the server and cannot be renamed", spec.Name, installation.Name)
+		}
+		if isBeingDeleted(installation) {
+			p.warn("installation %s is being deleted and cannot be updated", spec.Name)
+			continue
+		}
+		p.add(p.updateInstallation(spec, installation))
+	}
+
+	if !p.opts.Prune {
+		return nil
+	}
+	for _, installation := range installations {
+		if _, ok := declared[installation.ID]; ok || !owners[installation.OwnerID] || isBeingDeleted(installation) {
+			continue
+		}
+		installationID := installation.ID
+		p.delete(KindInstallation, installation.Name, func() error {
+			return p.client.DeleteInstallation(installationID)
+		})
+	}
+
+	return nil
+}
+
+// matchInstallation returns the installation with the name or any of the DNS
+// names of the spec, or nil if there is none. The name and the DNS names must
+// not identify different installations.
+func matchInstallation(spec *InstallationSpec, byName, byDNS map[string]*model.InstallationDTO) (*model.InstallationDTO, error) {
+	match := byName[spec.Name]
+	for _, dns := range spec.DNSNames {
+		installation, ok := byDNS[dns]
+		if !ok {
+			continue
+		}
+		if match == nil {
+			match = installation
+			continue
+		}
+		if match.ID != installation.ID {
+			return nil, errors.Errorf("installation %s DNS name %s belongs to installation %s, not %s", spec.Name, dns, installation.Name, match.Name)
+		}
+	}
+
+	return match, nil
+}
+
+func isBeingDeleted(installation *model.InstallationDTO) bool {
+	return strings.HasPrefix(installation.State, "deletion") &&
+		installation.State != model.InstallationStateDeletionCancellationRequested
+}
+
+func (p *planner) createInstallation(spec *InstallationSpec) *Action {
+	request := &model.CreateInstallationRequest{
+		Name:          spec.Name,
+		OwnerID:       spec.OwnerID,
+		Version:       spec.Version,
+		Image:         spec.Image,
+		DNSNames:      spec.DNSNames,
+		License:       spec.License,
+		Size:          spec.Size,
+		Affinity:      spec.Affinity,
+		Database:      spec.Database,
+		Filestore:     spec.Filestore,
+		MattermostEnv: spec.MattermostEnv,
+		PriorityEnv:   spec.PriorityEnv,
+		Annotations:   spec.Annotations,
+	}
+
+	return &Action{
+		Kind:      KindInstallation,
+		Name:      spec.Name,
+		Operation: OperationCreate,
+		steps: []func() error{func() error {
+			if spec.Group != "" {
+				groupID, err := p.groupID(spec.Group)
+				if err != nil {
+					return err
+				}
+				request.GroupID = groupID
+			}
+			_, err := p.client.CreateInstallation(request)
+			return err
+		}},
+	}
+}
+
+func (p *planner) updateInstallation(spec *InstallationSpec, installation *model.InstallationDTO) *Action {
+	action := &Action{Kind: KindInstallation, Name: spec.Name, Operation: OperationUpdate}
+	patch := &model.PatchInstallationRequest{}
+	patched := false
+
+	patchString := func(field, current, desired string, target **string) {
+		if desired != "" && desired != current {
+			*target = &desired
+			patched = true
+			if field == "license" {
+				action.Changes = append(action.Changes, "license: changed")
+				return
+			}
+			action.Changes = append(action.Changes, valueChange(field, current, desired))
+		}
+	}
+	patchString("ownerID", installation.OwnerID, spec.OwnerID, &patch.OwnerID)
+	patchString("version", installation.Version, spec.Version, &patch.Version)
+	patchString("image", installation.Image, spec.Image, &patch.Image)
+	patchString("size", installation.Size, spec.Size, &patch.Size)
+	patchString("license", installation.License, spec.License, &patch.License)
+	if envPatch, changes := diffEnv("mattermostEnv", installation.MattermostEnv, spec.MattermostEnv); envPatch != nil {
+		patch.MattermostEnv = envPatch
+		patched = true
+		action.Changes = append(action.Changes, changes...)
+	}
+	if envPatch, changes := diffEnv("priorityEnv", installation.PriorityEnv, spec.PriorityEnv); envPatch != nil {
+		patch.PriorityEnv = envPatch
+		patched = true
+		action.Changes = append(action.Changes, changes...)
+	}
+	if patched {
+		action.steps = append(action.steps, func() error {
+			_, err := p.client.UpdateInstallation(installation.ID, patch)
+			return err
+		})
+	}
+
+	for _, immutable := range []struct{ field, current, desired string }{
+		{"affinity", installation.Affinity, spec.Affinity},
+		{"database", installation.Database, spec.Database},
+		{"filestore", installation.Filestore, spec.Filestore},
+	} {
+		if immutable.desired != "" && immutable.desired != immutable.current {
+			p.warn("installation %s %s cannot be changed from %q to %q", spec.Name, immutable.field, immutable.current, immutable.desired)
+		}
+	}
+
+	p.planInstallationDNS(action, spec, installation)
+	p.planInstallationGroup(action, spec, installation)
+
+	added, removed := diffAnnotations(installation.Annotations, spec.Annotations)
+	action.Changes = append(action.Changes, annotationChanges(added, removed)...)
+	if len(added) > 0 {
+		action.steps = append(action.steps, func() error {
+			_, err := p.client.AddInstallationAnnotations(installation.ID, &model.AddAnnotationsRequest{Annotations: added})
+			return err
+		})
+	}
+	for _, annotation := range removed {
+		annotation := annotation
+		action.steps = append(action.steps, func() error {
+			return p.client.DeleteInstallationAnnotation(installation.ID, annotation)
+		})
+	}
+
+	if len(action.steps) == 0 {
+		return nil
+	}
+	return action
+}
+
+// planInstallationDNS adds missing DNS names. DNS names are never removed,
+// as other systems may still route traffic through them.
+func (p *planner) planInstallationDNS(action *Action, spec *InstallationSpec, installation *model.InstallationDTO) {
+	current := map[string]bool{}
+	for _, record := range installation.DNSRecords {
+		current[record.DomainName] = true
+	}
+	for _, dns := range spec.DNSNames {
+		if current[dns] {
+			continue
+		}
+		dns := dns
+		action.Changes = append(action.Changes, fmt.Sprintf("dnsNames: +%s", dns))
+		action.steps = append(action.steps, func() error {
+			_, err := p.client.AddInstallationDNS(installation.ID, &model.AddDNSRecordRequest{DNS: dns})
+			return err
+		})
+	}
+	for _, dns := range sortedKeys(current) {
+		if len(spec.DNSNames) > 0 && !contains(spec.DNSNames, dns) {
+			p.warn("installation %s DNS name %s is not declared and is kept", spec.Name, dns)
+		}
+	}
+}
+
+func (p *planner) planInstallationGroup(action *Action, spec *InstallationSpec, installation *model.InstallationDTO) {
+	currentGroup := ""
+	if installation.GroupID != nil {
+		currentGroup = p.groupNames[*installation.GroupID]
+	}
+	if spec.Group == currentGroup {
+		return
+	}
+
+	action.Changes = append(action.Changes, valueChange("group", currentGroup, spec.Group))
+	if spec.Group == "" {
+		action.steps = append(action.steps, func() error {
+			return p.client.LeaveGroup(installation.ID, &model.LeaveGroupRequest{RetainConfig: true})
+		})
+		return
+	}
+	action.steps = append(action.steps, func() error {
+		groupID, err := p.groupID(spec.Group)
+		if err != nil {
+			return err
+		}
+		return p.client.JoinGroup(groupID, installation.ID)
+	})
+}
+
+func (p *planner) groupID(name string) (string, error) {
+	groupID, ok := p.plan.groupIDs[name]
+	if !ok {
+		return "", errors.Errorf("group %s does not exist", name)
+	}
+	return groupID, nil
+}
+
+func (p *planner) planWebhooks(specs []*WebhookSpec) error {
+	if len(specs) == 0 {
+		return nil
+	}
+
+	owners := map[string]bool{}
+	for _, spec := range specs {
+		owners[spec.OwnerID] = true
+	}
+
+	existing := map[string]*model.Webhook{}
+	var webhooks []*model.Webhook
+	for _, owner := range sortedKeys(owners) {
+		ownerWebhooks, err := p.client.GetWebhooks(&model.GetWebhooksRequest{
+			Paging:  model.AllPagesNotDeleted(),
+			OwnerID: owner,
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to get webhooks")
+		}
+		for _, webhook := range ownerWebhooks {
+			existing[webhookID(webhook.OwnerID, webhook.URL)] = webhook
+		}
+		webhooks = append(webhooks, ownerWebhooks...)
+	}
+
+	declared := map[string]bool{}
+	for _, spec := range specs {
+		id := webhookID(spec.OwnerID, spec.URL)
+		declared[id] = true
+		request := &model.CreateWebhookRequest{OwnerID: spec.OwnerID, URL: spec.URL, Headers: spec.Headers}
+		create := func() error {
+			_, err := p.client.CreateWebhook(request)
+			return err
+		}
+
+		webhook, ok := existing[id]
+		if !ok {
+			p.add(&Action{Kind: KindWebhook, Name: id, Operation: OperationCreate, steps: []func() error{create}})
+			continue
+		}
+		if headersEqual(webhook.Headers, spec.Headers) {
+			continue
+		}
+		webhookID := webhook.ID
+		p.add(&Action{
+			Kind:      KindWebhook,
+			Name:      id,
+			Operation: OperationReplace,
+			Changes:   []string{"headers: changed"},
+			steps: []func() error{
+				func() error { return p.client.DeleteWebhook(webhookID) },
+				create,
+			},
+		})
+	}
+
+	if !p.opts.Prune {
+		return nil
+	}
+	for _, webhook := range webhooks {
+		id := webhookID(webhook.OwnerID, webhook.URL)
+		if declared[id] {
+			continue
+		}
+		webhookID := webhook.ID
+		p.delete(KindWebhook, id, func() error {
+			return p.client.DeleteWebhook(webhookID)
+		})
+	}
+
+	return nil
+}
+
+func (p *planner) planSubscriptions(specs []*SubscriptionSpec) error {
+	if len(specs) == 0 {
+		return nil
+	}
+
+	owners := map[string]bool{}
+	for _, spec := range specs {
+		owners[spec.OwnerID] = true
+	}
+
+	existing := map[string]*model.Subscription{}
+	var subscriptions []*model.Subscription
+	for _, owner := range sortedKeys(owners) {
+		ownerSubscriptions, err := p.client.ListSubscriptions(&model.ListSubscriptionsRequest{
+			Paging: model.AllPagesNotDeleted(),
+			Owner:  owner,
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to list subscriptions")
+		}
+		for _, sub := range ownerSubscriptions {
+			id := subscriptionID(sub.OwnerID, sub.Name)
+			if _, ok := existing[id]; ok {
+				return errors.Errorf("subscription name %s is not unique on the server", id)
+			}
+			existing[id] = sub
+		}
+		subscriptions = append(subscriptions, ownerSubscriptions...)
+	}
+
+	declared := map[string]bool{}
+	for _, spec := range specs {
+		id := subscriptionID(spec.OwnerID, spec.Name)
+		declared[id] = true
+		request, err := spec.createRequest()
+		if err != nil {
+			return errors.Wrapf(err, "subscription %s is invalid", id)
+		}
+		create := func() error {
+			_, err := p.client.CreateSubscription(request)
+			return err
+		}
+
+		sub, ok := existing[id]
+		if !ok {
+			p.add(&Action{Kind: KindSubscription, Name: id, Operation: OperationCreate, steps: []func() error{create}})
+			continue
+		}
+		changes := subscriptionChanges(sub, request)
+		if len(changes) == 0 {
+			continue
+		}
+		subID := sub.ID
+		p.add(&Action{
+			Kind:      KindSubscription,
+			Name:      id,
+			Operation: OperationReplace,
+			Changes:   changes,
+			steps: []func() error{
+				func() error { return p.client.DeleteSubscription(subID) },
+				create,
+			},
+		})
+	}
+
+	if !p.opts.Prune {
+		return nil
+	}
+	for _, sub := range subscriptions {
+		id := subscriptionID(sub.OwnerID, sub.Name)
+		if declared[id] {
+			continue
+		}
+		subID := sub.ID
+		p.delete(KindSubscription, id, func() error {
+			return p.client.DeleteSubscription(subID)
+		})
+	}
+
+	return nil
+}
+
+// subscriptionChanges returns the fields of the subscription which differ
+// from the request. Subscriptions cannot be updated, so any change
+// requires the subscription to be replaced.
+func subscriptionChanges(sub *model.Subscription, request *model.CreateSubscriptionRequest) []string {
+	desired, _ := request.ToSubscription()
+
+	var changes []string
+	if sub.URL != desired.URL {
+		changes = append(changes, valueChange("url", sub.URL, desired.URL))
+	}
+	if sub.EventType != desired.EventType {
+		changes = append(changes, valueChange("eventType", sub.EventType, desired.EventType))
+	}
+	if sub.FailureThreshold != desired.FailureThreshold {
+		changes = append(changes, valueChange("failureThreshold", sub.FailureThreshold, desired.FailureThreshold))
+	}
+	if !headersEqual(sub.Headers, desired.Headers) {
+		changes = append(changes, "headers: changed")
+	}
+	if !reflect.DeepEqual(normalizeFilter(sub.Filter), normalizeFilter(desired.Filter)) {
+		changes = append(changes, "filter: changed")
+	}
+	if sub.PayloadFormat != desired.PayloadFormat {
+		changes = append(changes, valueChange("payloadFormat", sub.PayloadFormat, desired.PayloadFormat))
+	}
+	currentSink := sub.SinkType
+	if currentSink == "" {
+		currentSink = model.SubscriptionSinkHTTP
+	}
+	if currentSink != desired.SinkType {
+		changes = append(changes, valueChange("sink", currentSink, desired.SinkType))
+	}
+
+	return changes
+}
+
+func normalizeFilter(filter *model.SubscriptionFilter) *model.SubscriptionFilter {
+	if filter.IsEmpty() {
+		return nil
+	}
+	return filter
+}
+
+func (p *planner) planClusterAnnotations(specs []*ClusterAnnotationsSpec) error {
+	for _, spec := range specs {
+		cluster, err := p.client.GetCluster(spec.ClusterID)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get cluster %s", spec.ClusterID)
+		}
+		if cluster == nil {
+			return errors.Errorf("cluster %s not found", spec.ClusterID)
+		}
+
+		annotations := spec.Annotations
+		if annotations == nil {
+			annotations = []string{}
+		}
+		added, removed := diffAnnotations(cluster.Annotations, annotations)
+		if len(added) == 0 && len(removed) == 0 {
+			continue
+		}
+
+		clusterID := spec.ClusterID
+		action := &Action{
+			Kind:      KindClusterAnnotations,
+			Name:      clusterID,
+			Operation: OperationUpdate,
+			Changes:   annotationChanges(added, removed),
+		}
+		if len(added) > 0 {
+			action.steps = append(action.steps, func() error {
+				_, err := p.client.AddClusterAnnotations(clusterID, &model.AddAnnotationsRequest{Annotations: added})
+				return err
+			})
+		}
+		for _, annotation := range removed {
+			annotation := annotation
+			action.steps = append(action.steps, func() error {
+				return p.client.DeleteClusterAnnotation(clusterID, annotation)
+			})
+		}
+		p.add(action)
+	}
+
+	return nil
+}
+
+// diffEnv returns the patch converting current env vars to the desired ones
+// or nil if they match or are not managed. Removed variables are patched
+// with empty values, clearing all variables requires an empty patch.
+func diffEnv(field string, current, desired model.EnvVarMap) (model.EnvVarMap, []string) {
+	if desired == nil {
+		return nil, nil
+	}
+	if len(desired) == 0 {
+		if len(current) == 0 {
+			return nil, nil
+		}
+		return model.EnvVarMap{}, []string{fmt.Sprintf("%s: cleared", field)}
+	}
+
+	patch := model.EnvVarMap{}
+	var changes []string
+	for _, name := range sortedKeys(desired) {
+		currentEnv, ok := current[name]
+		if !ok {
+			changes = append(changes, fmt.Sprintf("%s.%s: added", field, name))
+			patch[name] = desired[name]
+		} else if !reflect.DeepEqual(currentEnv, desired[name]) {
+			changes = append(changes, fmt.Sprintf("%s.%s: changed", field, name))
+			patch[name] = desired[name]
+		}
+	}
+	for _, name := range sortedKeys(current) {
+		if _, ok := desired[name]; !ok {
+			changes = append(changes, fmt.Sprintf("%s.%s: removed", field, name))
+			patch[name] = model.EnvVar{}
+		}
+	}
+	if len(patch) == 0 {
+		return nil, nil
+	}
+
+	return patch, changes
+}
+
+// diffAnnotations returns annotations to add and remove. Nil desired
+// annotations are not managed.
+func diffAnnotations(current []*model.Annotation, desired []string) ([]string, []string) {
+	if desired == nil {
+		return nil, nil
+	}
+
+	currentNames := map[string]bool{}
+	for _, annotation := range current {
+		currentNames[annotation.Name] = true
+	}
+	desiredNames := map[string]bool{}
+	for _, name := range desired {
+		desiredNames[name] = true
+	}
+
+	var added, removed []string
+	for _, name := range sortedKeys(desiredNames) {
+		if !currentNames[name] {
+			added = append(added, name)
+		}
+	}
+	for _, name := range sortedKeys(currentNames) {
+		if !desiredNames[name] {
+			removed = append(removed, name)
+		}
+	}
+
+	return added, removed
+}
+
+func annotationChanges(added, removed []string) []string {
+	var changes []string
+	for _, name := range added {
+		changes = append(changes, fmt.Sprintf("annotations: +%s", name))
+	}
+	for _, name := range removed {
+		changes = append(changes, fmt.Sprintf("annotations: -%s", name))
+	}
+	return changes
+}
+
+func headersEqual(a, b model.Headers) bool {
+	if len(a) == 0 && len(b) == 0 {
+		return true
+	}
+	sortHeaders := func(headers model.Headers) model.Headers {
+		sorted := append(model.Headers{}, headers...)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i].Key < sorted[j].Key })
+		return sorted
+	}
+	return reflect.DeepEqual(sortHeaders(a), sortHeaders(b))
+}
+
+func valueChange(field string, current, desired interface{}) string {
+	return fmt.Sprintf("%s: %q -> %q", field, fmt.Sprint(current), fmt.Sprint(desired))
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,423 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package manifest
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClient is an in-memory Client recording all changing calls.
+type fakeClient struct {
+	installations []*model.InstallationDTO
+	groups        []*model.GroupDTO
+	webhooks      []*model.Webhook
+	subscriptions []*model.Subscription
+	clusters      []*model.ClusterDTO
+
+	calls []string
+}
+
+func (c *fakeClient) record(format string, args ...interface{}) {
+	c.calls = append(c.calls, fmt.Sprintf(format, args...))
+}
+
+func (c *fakeClient) GetInstallations(request *model.GetInstallationsRequest) ([]*model.InstallationDTO, error) {
+	return c.installations, nil
+}
+
+func (c *fakeClient) CreateInstallation(request *model.CreateInstallationRequest) (*model.InstallationDTO, error) {
+	c.record("CreateInstallation %s group=%s", request.Name, request.GroupID)
+	return &model.InstallationDTO{Installation: &model.Installation{ID: model.NewID(), Name: request.Name}}, nil
+}
+
+func (c *fakeClient) UpdateInstallation(installationID string, request *model.PatchInstallationRequest) (*model.InstallationDTO, error) {
+	c.record("UpdateInstallation %s", installationID)
+	return nil, nil
+}
+
+func (c *fakeClient) DeleteInstallation(installationID string) error {
+	c.record("DeleteInstallation %s", installationID)
+	return nil
+}
+
+func (c *fakeClient) AddInstallationDNS(installationID string, request *model.AddDNSRecordRequest) (*model.InstallationDTO, error) {
+	c.record("AddInstallationDNS %s %s", installationID, request.DNS)
+	return nil, nil
+}
+
+func (c *fakeClient) AddInstallationAnnotations(installationID string, annotationsRequest *model.AddAnnotationsRequest) (*model.InstallationDTO, error) {
+	c.record("AddInstallationAnnotations %s %v", installationID, annotationsRequest.Annotations)
+	return nil, nil
+}
+
+func (c *fakeClient) DeleteInstallationAnnotation(installationID string, annotationName string) error {
+	c.record("DeleteInstallationAnnotation %s %s", installationID, annotationName)
+	return nil
+}
+
+func (c *fakeClient) JoinGroup(groupID, installationID string) error {
+	c.record("JoinGroup %s %s", groupID, installationID)
+	return nil
+}
+
+func (c *fakeClient) LeaveGroup(installationID string, request *model.LeaveGroupRequest) error {
+	c.record("LeaveGroup %s", installationID)
+	return nil
+}
+
+func (c *fakeClient) GetGroups(request *model.GetGroupsRequest) ([]*model.GroupDTO, error) {
+	return c.groups, nil
+}
+
+func (c *fakeClient) CreateGroup(request *model.CreateGroupRequest) (*model.GroupDTO, error) {
+	c.record("CreateGroup %s", request.Name)
+	return &model.GroupDTO{Group: &model.Group{ID: "new-" + request.Name, Name: request.Name}}, nil
+}
+
+func (c *fakeClient) UpdateGroup(request *model.PatchGroupRequest) (*model.GroupDTO, error) {
+	c.record("UpdateGroup %s", request.ID)
+	return nil, nil
+}
+
+func (c *fakeClient) DeleteGroup(groupID string) error {
+	c.record("DeleteGroup %s", groupID)
+	return nil
+}
+
+func (c *fakeClient) AddGroupAnnotations(groupID string, annotationsRequest *model.AddAnnotationsRequest) (*model.GroupDTO, error) {
+	c.record("AddGroupAnnotations %s %v", groupID, annotationsRequest.Annotations)
+	return nil, nil
+}
+
+func (c *fakeClient) DeleteGroupAnnotation(groupID string, annotationName string) error {
+	c.record("DeleteGroupAnnotation %s %s", groupID, annotationName)
+	return nil
+}
+
+func (c *fakeClient) GetWebhooks(request *model.GetWebhooksRequest) ([]*model.Webhook, error) {
+	var webhooks []*model.Webhook
+	for _, webhook := range c.webhooks {
+		if webhook.OwnerID == request.OwnerID {
+			webhooks = append(webhooks, webhook)
+		}
+	}
+	return webhooks, nil
+}
+
+func (c *fakeClient) CreateWebhook(request *model.CreateWebhookRequest) (*model.Webhook, error) {
+	c.record("CreateWebhook %s", request.URL)
+	return nil, nil
+}
+
+func (c *fakeClient) DeleteWebhook(webhookID string) error {
+	c.record("DeleteWebhook %s", webhookID)
+	return nil
+}
+
+func (c *fakeClient) ListSubscriptions(request *model.ListSubscriptionsRequest) ([]*model.Subscription, error) {
+	var subscriptions []*model.Subscription
+	for _, sub := range c.subscriptions {
+		if sub.OwnerID == request.Owner {
+			subscriptions = append(subscriptions, sub)
+		}
+	}
+	return subscriptions, nil
+}
+
+func (c *fakeClient) CreateSubscription(request *model.CreateSubscriptionRequest) (*model.Subscription, error) {
+	c.record("CreateSubscription %s", request.Name)
+	return nil, nil
+}
+
+func (c *fakeClient) DeleteSubscription(subID string) error {
+	c.record("DeleteSubscription %s", subID)
+	return nil
+}
+
+func (c *fakeClient) GetCluster(clusterID string) (*model.ClusterDTO, error) {
+	for _, cluster := range c.clusters {
+		if cluster.ID == clusterID {
+			return cluster, nil
+		}
+	}
+	return nil, nil
+}
+
+func (c *fakeClient) AddClusterAnnotations(clusterID string, annotationsRequest *model.AddAnnotationsRequest) (*model.ClusterDTO, error) {
+	c.record("AddClusterAnnotations %s %v", clusterID, annotationsRequest.Annotations)
+	return nil, nil
+}
+
+func (c *fakeClient) DeleteClusterAnnotation(clusterID string, annotationName string) error {
+	c.record("DeleteClusterAnnotation %s %s", clusterID, annotationName)
+	return nil
+}
+
+func newTestFakeClient() *fakeClient {
+	groupID := "group1"
+	return &fakeClient{
+		groups: []*model.GroupDTO{
+			{
+				Group: &model.Group{
+					ID:            "group1",
+					Name:          "test-group",
+					Version:       "9.4",
+					MaxRolling:    2,
+					MattermostEnv: model.EnvVarMap{"MM_FEATURE": {Value: "true"}},
+				},
+				Annotations: []*model.Annotation{{Name: "multi-tenant"}},
+			},
+			{Group: &model.Group{ID: "group2", Name: "old-group"}},
+		},
+		installations: []*model.InstallationDTO{
+			{
+				Installation: &model.Installation{
+					ID:            "installation1",
+					Name:          "workspace",
+					OwnerID:       "team",
+					State:         model.InstallationStateStable,
+					Size:          "100users",
+					Database:      model.InstallationDatabaseMultiTenantRDSPostgres,
+					MattermostEnv: model.EnvVarMap{"MM_OLD": {Value: "1"}},
+					GroupID:       &groupID,
+				},
+				DNSRecords:  []*model.InstallationDNS{{DomainName: "workspace.example.com", IsPrimary: true}},
+				Annotations: []*model.Annotation{{Name: "multi-tenant"}, {Name: "old"}},
+			},
+			{
+				Installation: &model.Installation{ID: "installation2", Name: "legacy", OwnerID: "team", State: model.InstallationStateStable},
+			},
+			{
+				Installation: &model.Installation{ID: "installation3", Name: "other", OwnerID: "other-team", State: model.InstallationStateStable},
+			},
+		},
+		webhooks: []*model.Webhook{
+			{ID: "webhook1", OwnerID: "team", URL: "https://hooks.example.com"},
+			{ID: "webhook2", OwnerID: "team", URL: "https://old.example.com"},
+		},
+		subscriptions: []*model.Subscription{
+			{
+				ID:        "sub1",
+				Name:      "states",
+				OwnerID:   "team",
+				URL:       "https://events.example.com",
+				EventType: model.ResourceStateChangeEventType,
+				SinkType:  model.SubscriptionSinkHTTP,
+			},
+		},
+		clusters: []*model.ClusterDTO{
+			{Cluster: &model.Cluster{ID: "cluster1"}, Annotations: []*model.Annotation{{Name: "multi-tenant"}, {Name: "old"}}},
+		},
+	}
+}
+
+func TestPlan(t *testing.T) {
+	maxRolling := int64(2)
+	manifests := &Manifests{
+		Groups: []*GroupSpec{
+			{Name: "test-group", Version: "9.5", MaxRolling: &maxRolling, MattermostEnv: model.EnvVarMap{"MM_FEATURE": {Value: "true"}}},
+			{Name: "new-group"},
+		},
+		Installations: []*InstallationSpec{
+			{
+				Name:          "workspace",
+				DNSNames:      []string{"workspace.example.com", "workspace.example.org"},
+				OwnerID:       "team",
+				Group:         "new-group",
+				Size:          "100users",
+				Database:      model.InstallationDatabaseSingleTenantRDSPostgres,
+				MattermostEnv: model.EnvVarMap{"MM_NEW": {Value: "2"}},
+				Annotations:   []string{"multi-tenant"},
+			},
+			{Name: "created", DNSNames: []string{"created.example.com"}, OwnerID: "team", Group: "new-group"},
+		},
+		Webhooks: []*WebhookSpec{
+			{OwnerID: "team", URL: "https://hooks.example.com"},
+		},
+		Subscriptions: []*SubscriptionSpec{
+			{Name: "states", OwnerID: "team", URL: "https://events.example.com", EventType: model.ResourceStateChangeEventType, FailureThreshold: "1h"},
+		},
+		ClusterAnnotations: []*ClusterAnnotationsSpec{
+			{ClusterID: "cluster1", Annotations: []string{"multi-tenant"}},
+		},
+	}
+	require.NoError(t, manifests.Validate())
+
+	t.Run("without prune", func(t *testing.T) {
+		client := newTestFakeClient()
+		plan, err := NewPlan(client, manifests, Options{})
+		require.NoError(t, err)
+
+		out := &bytes.Buffer{}
+		plan.Print(out)
+		assert.Equal(t, `! installation workspace database cannot be changed from "aws-multitenant-rds-postgres" to "aws-rds-postgres"
+~ Group test-group (update)
+    version: "9.4" -> "9.5"
++ Group new-group (create)
+~ Installation workspace (update)
+    mattermostEnv.MM_NEW: added
+    mattermostEnv.MM_OLD: removed
+    dnsNames: +workspace.example.org
+    group: "test-group" -> "new-group"
+    annotations: -old
++ Installation created (create)
+-/+ Subscription team/states (replace)
+    failureThreshold: "0s" -> "1h0m0s"
+~ ClusterAnnotations cluster1 (update)
+    annotations: -old
+`, out.String())
+		assert.Empty(t, client.calls)
+
+		err = plan.Apply(&bytes.Buffer{})
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			"UpdateGroup group1",
+			"CreateGroup new-group",
+			"UpdateInstallation installation1",
+			"AddInstallationDNS installation1 workspace.example.org",
+			"JoinGroup new-new-group installation1",
+			"DeleteInstallationAnnotation installation1 old",
+			"CreateInstallation created group=new-new-group",
+			"DeleteSubscription sub1",
+			"CreateSubscription states",
+			"DeleteClusterAnnotation cluster1 old",
+		}, client.calls)
+	})
+
+	t.Run("with prune", func(t *testing.T) {
+		client := newTestFakeClient()
+		plan, err := NewPlan(client, manifests, Options{Prune: true})
+		require.NoError(t, err)
+
+		err = plan.Apply(&bytes.Buffer{})
+		require.NoError(t, err)
+		// Installations of other owners are kept.
+		assert.Equal(t, []string{
+			"DeleteGroup group2",
+			"DeleteInstallation installation2",
+			"DeleteWebhook webhook2",
+		}, client.calls[len(client.calls)-3:])
+	})
+
+	t.Run("no changes", func(t *testing.T) {
+		client := newTestFakeClient()
+		plan, err := NewPlan(client, &Manifests{
+			Groups:   []*GroupSpec{{Name: "test-group", Version: "9.4"}},
+			Webhooks: []*WebhookSpec{{OwnerID: "team", URL: "https://hooks.example.com"}},
+			Subscriptions: []*SubscriptionSpec{
+				{Name: "states", OwnerID: "team", URL: "https://events.example.com", EventType: model.ResourceStateChangeEventType},
+			},
+		}, Options{})
+		require.NoError(t, err)
+		assert.True(t, plan.IsEmpty())
+
+		out := &bytes.Buffer{}
+		plan.Print(out)
+		assert.Equal(t, "No changes.\n", out.String())
+	})
+
+	t.Run("match by DNS name", func(t *testing.T) {
+		client := newTestFakeClient()
+		plan, err := NewPlan(client, &Manifests{
+			Installations: []*InstallationSpec{
+				{Name: "renamed", DNSNames: []string{"workspace.example.com"}, OwnerID: "team", Group: "test-group", Version: "9.5"},
+			},
+		}, Options{Prune: true})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"installation renamed is named workspace on the server and cannot be renamed"}, plan.Warnings)
+
+		err = plan.Apply(&bytes.Buffer{})
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			"UpdateInstallation installation1",
+			"DeleteInstallation installation2",
+		}, client.calls)
+	})
+
+	t.Run("name and DNS name match different installations", func(t *testing.T) {
+		client := newTestFakeClient()
+		_, err := NewPlan(client, &Manifests{
+			Installations: []*InstallationSpec{
+				{Name: "legacy", DNSNames: []string{"workspace.example.com"}, OwnerID: "team"},
+			},
+		}, Options{})
+		assert.Error(t, err)
+	})
+
+	t.Run("installations match the same installation", func(t *testing.T) {
+		client := newTestFakeClient()
+		_, err := NewPlan(client, &Manifests{
+			Installations: []*InstallationSpec{
+				{Name: "workspace", OwnerID: "team"},
+				{Name: "renamed", DNSNames: []string{"workspace.example.com"}, OwnerID: "team"},
+			},
+		}, Options{})
+		assert.Error(t, err)
+	})
+
+	t.Run("unknown group", func(t *testing.T) {
+		client := newTestFakeClient()
+		plan, err := NewPlan(client, &Manifests{
+			Installations: []*InstallationSpec{{Name: "created", OwnerID: "team", Group: "missing"}},
+		}, Options{})
+		require.NoError(t, err)
+		assert.Error(t, plan.Apply(&bytes.Buffer{}))
+	})
+
+	t.Run("unknown cluster", func(t *testing.T) {
+		client := newTestFakeClient()
+		_, err := NewPlan(client, &Manifests{
+			ClusterAnnotations: []*ClusterAnnotationsSpec{{ClusterID: "missing"}},
+		}, Options{})
+		assert.Error(t, err)
+	})
+}
+
+func TestDiffEnv(t *testing.T) {
+	current := model.EnvVarMap{"A": {Value: "1"}, "B": {Value: "2"}}
+
+	patch, changes := diffEnv("env", current, nil)
+	assert.Nil(t, patch)
+	assert.Empty(t, changes)
+
+	patch, changes = diffEnv("env", current, model.EnvVarMap{})
+	assert.Equal(t, model.EnvVarMap{}, patch)
+	assert.Equal(t, []string{"env: cleared"}, changes)
+
+	patch, changes = diffEnv("env", current, model.EnvVarMap{"A": {Value: "1"}, "B": {Value: "3"}, "C": {Value: "4"}})
+	assert.Equal(t, model.EnvVarMap{"B": {Value: "3"}, "C": {Value: "4"}}, patch)
+	assert.Equal(t, []string{"env.B: changed", "env.C: added"}, changes)
+
+	patch, _ = diffEnv("env", current, model.EnvVarMap{"A": {Value: "1"}})
+	assert.Equal(t, model.EnvVarMap{"B": {}}, patch)
+
+	patch, _ = diffEnv("env", current, model.EnvVarMap{"A": {Value: "1"}, "B": {Value: "2"}})
+	assert.Nil(t, patch)
+}
+
+func TestSubscriptionChanges(t *testing.T) {
+	sub := &model.Subscription{
+		URL:       "https://events.example.com",
+		EventType: model.ResourceStateChangeEventType,
+		Filter:    &model.SubscriptionFilter{},
+	}
+	request := &model.CreateSubscriptionRequest{
+		URL:              "https://events.example.com",
+		EventType:        model.ResourceStateChangeEventType,
+		OwnerID:          "team",
+		FailureThreshold: time.Hour,
+	}
+	assert.Equal(t, []string{`failureThreshold: "0s" -> "1h0m0s"`}, subscriptionChanges(sub, request))
+
+	request.FailureThreshold = 0
+	assert.Empty(t, subscriptionChanges(sub, request))
+}
@@ -7,6 +7,9 @@ package main
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/mattermost/mattermost-cloud/clusterdictionary"
 	"github.com/mattermost/mattermost-cloud/model"
@@ -21,6 +24,7 @@ func newCmdClusterNodegroup() *cobra.Command {
 	}
 
 	cmd.AddCommand(newCmdClusterNodegroupCreate())
+	cmd.AddCommand(newCmdClusterNodegroupPatch())
 	cmd.AddCommand(newCmdClusterNodegroupDelete())
 
 	return cmd
@@ -128,3 +132,162 @@ func deleteNodegroup(ctx context.Context, flags clusterNodegroupDeleteFlags) err
 
 	return nil
 }
+
+func newCmdClusterNodegroupPatch() *cobra.Command {
+	var flags clusterNodegroupPatchFlags
+
+	cmd := &cobra.Command{
+		Use:   "patch",
+		Short: "Change a nodegroup of an existing cluster in place.",
+		RunE: func(command *cobra.Command, args []string) error {
+			command.SilenceUsage = true
+			return patchNodegroup(command.Context(), flags)
+		},
+		PreRun: func(cmd *cobra.Command, args []string) {
+			flags.clusterFlags.addFlags(cmd)
+			flags.nodegroupPatchRequestChanges.addFlags(cmd)
+		},
+	}
+
+	flags.addFlags(cmd)
+
+	return cmd
+}
+
+func patchNodegroup(ctx context.Context, flags clusterNodegroupPatchFlags) error {
+	client := createClient(ctx, flags.clusterFlags)
+
+	request, err := flags.GetPatchNodegroupRequest()
+	if err != nil {
+		return err
+	}
+
+	if flags.dryRun {
+		return runDryRun(request)
+	}
+
+	cluster, err := client.PatchNodegroup(flags.clusterID, flags.nodegroup, request)
+	if err != nil {
+		return errors.Wrap(err, "failed to patch nodegroup")
+	}
+
+	if err = printJSON(cluster); err != nil {
+		return errors.Wrap(err, "failed to print cluster response")
+	}
+
+	return nil
+}
+
+// GetPatchNodegroupRequest builds the nodegroup patch request from the flags.
+func (flags *clusterNodegroupPatchFlags) GetPatchNodegroupRequest() (*model.PatchNodegroupRequest, error) {
+	request := &model.PatchNodegroupRequest{
+		RemoveAutoscalingPolicy: flags.removeAutoscalingPolicy,
+	}
+
+	if flags.instanceTypeChanged {
+		request.InstanceType = &flags.instanceType
+	}
+	if flags.capacityTypeChanged {
+		request.CapacityType = &flags.capacityType
+	}
+	if flags.minCountChanged {
+		request.MinCount = &flags.minCount
+	}
+	if flags.maxCountChanged {
+		request.MaxCount = &flags.maxCount
+	}
+
+	if flags.targetCPUUtilization != 0 || flags.targetMemoryUtilization != 0 || len(flags.scaleSchedules) != 0 {
+		request.AutoscalingPolicy = &model.NodeGroupAutoscalingPolicy{
+			TargetCPUUtilization:    flags.targetCPUUtilization,
+			TargetMemoryUtilization: flags.targetMemoryUtilization,
+		}
+		for _, value := range flags.scaleSchedules {
+			schedule, err := parseScaleSchedule(value)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid scale schedule %q", value)
+			}
+			request.AutoscalingPolicy.Schedules = append(request.AutoscalingPolicy.Schedules, schedule)
+		}
+	}
+
+	err := request.Validate()
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid nodegroup patch")
+	}
+
+	return request, nil
+}
+
+var scheduleWeekdays = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// parseScaleSchedule parses a nodegroup scale schedule in the form
+// <name>:<days>:<start-hour>-<end-hour>:<min-count>[:<timezone>].
+func parseScaleSchedule(value string) (model.NodeGroupScaleSchedule, error) {
+	var schedule model.NodeGroupScaleSchedule
+
+	parts := strings.Split(value, ":")
+	if len(parts) != 4 && len(parts) != 5 {
+		return schedule, errors.New("expected <name>:<days>:<start-hour>-<end-hour>:<min-count>[:<timezone>]")
+	}
+
+	schedule.Name = parts[0]
+	if len(parts) == 5 {
+		schedule.Timezone = parts[4]
+	}
+
+	days, err := parseScheduleDays(parts[1])
+	if err != nil {
+		return schedule, err
+	}
+	schedule.Days = days
+
+	start, end, found := strings.Cut(parts[2], "-")
+	if !found {
+		return schedule, errors.Errorf("invalid hours %q", parts[2])
+	}
+	schedule.StartHour, err = strconv.Atoi(start)
+	if err != nil {
+		return schedule, errors.Wrap(err, "invalid start hour")
+	}
+	schedule.EndHour, err = strconv.Atoi(end)
+	if err != nil {
+		return schedule, errors.Wrap(err, "invalid end hour")
+	}
+
+	schedule.MinCount, err = strconv.ParseInt(parts[3], 10, 64)
+	if err != nil {
+		return schedule, errors.Wrap(err, "invalid min count")
+	}
+
+	return schedule, nil
+}
+
+func parseScheduleDays(value string) ([]string, error) {
+	if value == "*" {
+		return nil, nil
+	}
+
+	var days []string
+	for _, item := range strings.Split(value, ",") {
+		first, last, isRange := strings.Cut(strings.ToLower(item), "-")
+		if !isRange {
+			days = append(days, first)
+			continue
+		}
+
+		start := slices.Index(scheduleWeekdays, first)
+		end := slices.Index(scheduleWeekdays, last)
+		if start == -1 || end == -1 {
+			return nil, errors.Errorf("invalid day range %q", item)
+		}
+		for i := start; ; i = (i + 1) % len(scheduleWeekdays) {
+			days = append(days, scheduleWeekdays[i])
+			if i == end {
+				break
+			}
+		}
+	}
+
+	return days, nil
+}
@@ -37,3 +37,48 @@ func (flags *clusterNodegroupDeleteFlags) addFlags(command *cobra.Command) {
 	_ = command.MarkFlagRequired("cluster")
 	_ = command.MarkFlagRequired("nodegroup")
 }
+
+type clusterNodegroupPatchFlags struct {
+	clusterFlags
+	nodegroupPatchRequestChanges
+	clusterID               string
+	nodegroup               string
+	instanceType            string
+	capacityType            string
+	minCount                int64
+	maxCount                int64
+	targetCPUUtilization    int
+	targetMemoryUtilization int
+	scaleSchedules          []string
+	removeAutoscalingPolicy bool
+}
+
+func (flags *clusterNodegroupPatchFlags) addFlags(command *cobra.Command) {
+	command.Flags().StringVar(&flags.clusterID, "cluster", "", "The id of the cluster to be modified.")
+	command.Flags().StringVar(&flags.nodegroup, "nodegroup", "", "The name of the nodegroup to patch.")
+	command.Flags().StringVar(&flags.instanceType, "instance-type", "", "The instance type of the nodegroup. Changing it recreates the nodegroup.")
+	command.Flags().StringVar(&flags.capacityType, "capacity-type", "", "The capacity type of the nodegroup, ON_DEMAND or SPOT. Changing it recreates the nodegroup. Use separate nodegroups to mix spot and on-demand capacity; each nodegroup autoscales independently.")
+	command.Flags().Int64Var(&flags.minCount, "min-count", 0, "The minimum number of nodes of the nodegroup.")
+	command.Flags().Int64Var(&flags.maxCount, "max-count", 0, "The maximum number of nodes of the nodegroup.")
+	command.Flags().IntVar(&flags.targetCPUUtilization, "target-cpu-utilization", 0, "The percentage of allocatable CPU that pod requests should use when autoscaling the nodegroup.")
+	command.Flags().IntVar(&flags.targetMemoryUtilization, "target-memory-utilization", 0, "The percentage of allocatable memory that pod requests should use when autoscaling the nodegroup.")
+	command.Flags().StringArrayVar(&flags.scaleSchedules, "scale-schedule", nil, "A schedule raising the minimum size of the nodegroup in the form <name>:<days>:<start-hour>-<end-hour>:<min-count>[:<timezone>], e.g. business-hours:mon-fri:8-18:4:America/New_York. Days may be '*', a range or a comma separated list. Accepts multiple values.")
+	command.Flags().BoolVar(&flags.removeAutoscalingPolicy, "remove-autoscaling-policy", false, "Remove the autoscaling policy of the nodegroup.")
+
+	_ = command.MarkFlagRequired("cluster")
+	_ = command.MarkFlagRequired("nodegroup")
+}
+
+type nodegroupPatchRequestChanges struct {
+	instanceTypeChanged bool
+	capacityTypeChanged bool
+	minCountChanged     bool
+	maxCountChanged     bool
+}
+
+func (flags *nodegroupPatchRequestChanges) addFlags(command *cobra.Command) {
+	flags.instanceTypeChanged = command.Flags().Changed("instance-type")
+	flags.capacityTypeChanged = command.Flags().Changed("capacity-type")
+	flags.minCountChanged = command.Flags().Changed("min-count")
+	flags.maxCountChanged = command.Flags().Changed("max-count")
+}
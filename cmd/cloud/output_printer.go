@@ -0,0 +1,339 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/term"
+	"k8s.io/client-go/util/jsonpath"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	outputFormatJSON          = "json"
+	outputFormatYAML          = "yaml"
+	outputFormatCSV           = "csv"
+	outputFormatTable         = "table"
+	outputFormatWide          = "wide"
+	outputFormatName          = "name"
+	outputFormatCustomColumns = "custom-columns"
+	outputFormatGoTemplate    = "go-template"
+	outputFormatJSONPath      = "jsonpath"
+)
+
+// outputFormat returns the output format selected by the table options and
+// the argument of the format, if any.
+func (to tableOptions) outputFormat() (string, string, error) {
+	if to.output == "" {
+		switch {
+		case len(to.customCols) > 0:
+			return outputFormatCustomColumns, "", nil
+		case to.outputToTable:
+			return outputFormatTable, "", nil
+		default:
+			return outputFormatJSON, "", nil
+		}
+	}
+
+	format, argument, _ := strings.Cut(to.output, "=")
+	switch format {
+	case outputFormatJSON, outputFormatYAML, outputFormatWide, outputFormatName:
+		if argument != "" {
+			return "", "", errors.Errorf("output format %s does not accept arguments", format)
+		}
+	case outputFormatTable, outputFormatCSV:
+		if argument != "" {
+			return "", "", errors.Errorf("output format %s does not accept arguments", format)
+		}
+		// Custom columns select the columns of the table and CSV output.
+		return format, "", nil
+	case outputFormatCustomColumns:
+		if argument == "" {
+			return "", "", errors.New("output format custom-columns requires columns, e.g. custom-columns=ID:.ID")
+		}
+		if len(to.customCols) > 0 {
+			return "", "", errors.New("--custom-columns cannot be combined with output format custom-columns")
+		}
+		return format, argument, nil
+	case outputFormatGoTemplate, outputFormatJSONPath:
+		if argument == "" {
+			return "", "", errors.Errorf("output format %s requires an expression, e.g. %s={.ID}", format, format)
+		}
+	default:
+		return "", "", errors.Errorf("unsupported output format %q, expected one of json, yaml, csv, table, wide, name, custom-columns=, go-template= or jsonpath=", to.output)
+	}
+
+	if to.outputToTable || len(to.customCols) > 0 {
+		return "", "", errors.Errorf("--table and --custom-columns cannot be combined with output format %s", format)
+	}
+
+	return format, argument, nil
+}
+
+// resourcePrinter prints lists and single objects of a resource in all
+// supported output formats.
+type resourcePrinter[T any] struct {
+	// defaultTable returns the columns and rows of the default table.
+	defaultTable func(items []T) ([]string, [][]string)
+	// wideTable returns the columns and rows of the wide table. The default
+	// table is printed if not set.
+	wideTable func(items []T) ([]string, [][]string)
+	// id returns the ID printed by the name output format. The ID field of
+	// the object is printed if not set.
+	id func(item T) string
+}
+
+// printList prints the list of objects in the selected output format.
+func (p resourcePrinter[T]) printList(w io.Writer, to tableOptions, items []T) error {
+	return p.print(w, to, items, items)
+}
+
+// printObject prints a single object in the selected output format. Table
+// formats print the object as a single row, or no rows for a nil object.
+func (p resourcePrinter[T]) printObject(w io.Writer, to tableOptions, item T) error {
+	value := reflect.ValueOf(item)
+	if !value.IsValid() || (value.Kind() == reflect.Ptr && value.IsNil()) {
+		return p.print(w, to, nil, item)
+	}
+	return p.print(w, to, []T{item}, item)
+}
+
+// print prints the data in the selected output format. Table formats print
+// the items instead, for data that is not a list of the resource.
+func (p resourcePrinter[T]) print(w io.Writer, to tableOptions, items []T, data interface{}) error {
+	format, argument, err := to.outputFormat()
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case outputFormatJSON:
+		return writeJSON(w, data)
+	case outputFormatYAML:
+		return writeYAML(w, data)
+	case outputFormatName:
+		if p.id != nil {
+			for _, item := range items {
+				fmt.Fprintln(w, p.id(item))
+			}
+			return nil
+		}
+		return writeNames(w, toInterfaceSlice(items))
+	case outputFormatGoTemplate:
+		return writeGoTemplate(w, argument, data)
+	case outputFormatJSONPath:
+		return writeJSONPath(w, argument, data)
+	}
+
+	keys, vals, err := p.tableData(format, argument, to.customCols, items)
+	if err != nil {
+		return err
+	}
+	if format == outputFormatCSV {
+		return writeCSV(w, keys, vals)
+	}
+	writeTable(w, keys, vals)
+
+	return nil
+}
+
+func (p resourcePrinter[T]) tableData(format, argument string, customCols []string, items []T) ([]string, [][]string, error) {
+	if format == outputFormatCustomColumns && argument != "" {
+		customCols = strings.Split(argument, ",")
+	}
+	if len(customCols) > 0 {
+		keys, vals, err := prepareTableData(customCols, toInterfaceSlice(items))
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "failed to prepare table output")
+		}
+		return keys, vals, nil
+	}
+
+	if format == outputFormatWide && p.wideTable != nil {
+		keys, vals := p.wideTable(items)
+		return keys, vals, nil
+	}
+	keys, vals := p.defaultTable(items)
+
+	return keys, vals, nil
+}
+
+func toInterfaceSlice[T any](items []T) []interface{} {
+	data := make([]interface{}, 0, len(items))
+	for _, item := range items {
+		data = append(data, item)
+	}
+	return data
+}
+
+func writeJSON(w io.Writer, data interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "    ")
+	return encoder.Encode(data)
+}
+
+func writeYAML(w io.Writer, data interface{}) error {
+	out, err := yaml.Marshal(data)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal output to YAML")
+	}
+	_, err = w.Write(out)
+	return err
+}
+
+func writeCSV(w io.Writer, keys []string, vals [][]string) error {
+	writer := csv.NewWriter(w)
+	err := writer.Write(keys)
+	if err != nil {
+		return errors.Wrap(err, "failed to write CSV header")
+	}
+	err = writer.WriteAll(vals)
+	if err != nil {
+		return errors.Wrap(err, "failed to write CSV rows")
+	}
+	return nil
+}
+
+// writeNames prints the IDs of the objects, one per line.
+func writeNames(w io.Writer, data []interface{}) error {
+	parser := jsonpath.New("name")
+	err := parser.Parse("{.ID}")
+	if err != nil {
+		return errors.Wrap(err, "failed to parse name expression")
+	}
+
+	for _, elem := range data {
+		values, err := parser.FindResults(elem)
+		if err != nil {
+			return errors.Wrap(err, "failed to find object ID")
+		}
+		if len(values) == 0 || len(values[0]) == 0 {
+			return errors.New("object does not have an ID")
+		}
+		fmt.Fprintln(w, values[0][0].Interface())
+	}
+
+	return nil
+}
+
+// genericData converts the data to its JSON representation, so that
+// templates and JSONPath expressions address the same fields as the JSON
+// output.
+func genericData(data interface{}) (interface{}, error) {
+	out, err := json.Marshal(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal output")
+	}
+
+	var generic interface{}
+	err = json.Unmarshal(out, &generic)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal output")
+	}
+
+	return generic, nil
+}
+
+func writeGoTemplate(w io.Writer, text string, data interface{}) error {
+	tmpl, err := template.New("output").Parse(text)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse go-template")
+	}
+
+	generic, err := genericData(data)
+	if err != nil {
+		return err
+	}
+
+	err = tmpl.Execute(w, generic)
+	if err != nil {
+		return errors.Wrap(err, "failed to execute go-template")
+	}
+	fmt.Fprintln(w)
+
+	return nil
+}
+
+func writeJSONPath(w io.Writer, expression string, data interface{}) error {
+	parser := jsonpath.New("output")
+	err := parser.Parse(expression)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse jsonpath expression %q", expression)
+	}
+
+	generic, err := genericData(data)
+	if err != nil {
+		return err
+	}
+
+	err = parser.Execute(w, generic)
+	if err != nil {
+		return errors.Wrap(err, "failed to execute jsonpath expression")
+	}
+	fmt.Fprintln(w)
+
+	return nil
+}
+
+// clearScreen moves the cursor to the top left corner and clears the screen.
+const clearScreen = "\033[H\033[2J"
+
+// watchOutput renders the output once. With watch enabled, the output is
+// rendered periodically and printed again whenever it changes, until the
+// context is done.
+func watchOutput(ctx context.Context, w io.Writer, wo watchOptions, render func(w io.Writer) error) error {
+	if !wo.watch {
+		return render(w)
+	}
+	if wo.watchInterval <= 0 {
+		return errors.New("watch interval must be positive")
+	}
+
+	clear := false
+	if file, ok := w.(*os.File); ok {
+		clear = term.IsTerminal(int(file.Fd()))
+	}
+
+	ticker := time.NewTicker(wo.watchInterval)
+	defer ticker.Stop()
+
+	var last []byte
+	for {
+		buffer := &bytes.Buffer{}
+		err := render(buffer)
+		if err != nil {
+			return err
+		}
+
+		if last == nil || !bytes.Equal(buffer.Bytes(), last) {
+			if clear {
+				fmt.Fprint(w, clearScreen)
+			}
+			_, err = w.Write(buffer.Bytes())
+			if err != nil {
+				return errors.Wrap(err, "failed to write output")
+			}
+			last = buffer.Bytes()
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,214 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutputFormat(t *testing.T) {
+	for _, testCase := range []struct {
+		description      string
+		options          tableOptions
+		expectedFormat   string
+		expectedArgument string
+		expectError      bool
+	}{
+		{"default", tableOptions{}, outputFormatJSON, "", false},
+		{"table flag", tableOptions{outputToTable: true}, outputFormatTable, "", false},
+		{"custom columns flag", tableOptions{customCols: []string{"ID:.ID"}}, outputFormatCustomColumns, "", false},
+		{"yaml", tableOptions{output: "yaml"}, outputFormatYAML, "", false},
+		{"csv with custom columns", tableOptions{output: "csv", customCols: []string{"ID:.ID"}}, outputFormatCSV, "", false},
+		{"custom columns", tableOptions{output: "custom-columns=ID:.ID"}, outputFormatCustomColumns, "ID:.ID", false},
+		{"go template", tableOptions{output: "go-template={{.ID}}"}, outputFormatGoTemplate, "{{.ID}}", false},
+		{"jsonpath", tableOptions{output: "jsonpath={.ID}"}, outputFormatJSONPath, "{.ID}", false},
+		{"unknown", tableOptions{output: "xml"}, "", "", true},
+		{"missing jsonpath", tableOptions{output: "jsonpath="}, "", "", true},
+		{"argument to json", tableOptions{output: "json=pretty"}, "", "", true},
+		{"yaml with table flag", tableOptions{output: "yaml", outputToTable: true}, "", "", true},
+		{"wide with custom columns", tableOptions{output: "wide", customCols: []string{"ID:.ID"}}, "", "", true},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			format, argument, err := testCase.options.outputFormat()
+			if testCase.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expectedFormat, format)
+			assert.Equal(t, testCase.expectedArgument, argument)
+		})
+	}
+}
+
+func TestResourcePrinter(t *testing.T) {
+	webhooks := []*model.Webhook{
+		{ID: "webhook1", OwnerID: "owner1", URL: "https://one.example.com"},
+		{ID: "webhook2", OwnerID: "owner2", URL: "https://two.example.com"},
+	}
+	printer := resourcePrinter[*model.Webhook]{
+		defaultTable: func(webhooks []*model.Webhook) ([]string, [][]string) {
+			keys := []string{"ID", "OWNER"}
+			vals := make([][]string, 0, len(webhooks))
+			for _, webhook := range webhooks {
+				vals = append(vals, []string{webhook.ID, webhook.OwnerID})
+			}
+			return keys, vals
+		},
+	}
+
+	printList := func(t *testing.T, output string) string {
+		buffer := &bytes.Buffer{}
+		err := printer.printList(buffer, tableOptions{output: output}, webhooks)
+		require.NoError(t, err)
+		return buffer.String()
+	}
+
+	t.Run("json", func(t *testing.T) {
+		out := printList(t, "json")
+		webhooks, err := model.WebhooksFromReader(strings.NewReader(out))
+		require.NoError(t, err)
+		assert.Len(t, webhooks, 2)
+	})
+
+	t.Run("yaml", func(t *testing.T) {
+		out := printList(t, "yaml")
+		assert.Contains(t, out, "  ID: webhook1\n")
+		assert.Contains(t, out, "  OwnerID: owner2\n")
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		assert.Equal(t, "ID,OWNER\nwebhook1,owner1\nwebhook2,owner2\n", printList(t, "csv"))
+	})
+
+	t.Run("csv with custom columns", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+		err := printer.printList(buffer, tableOptions{output: "csv", customCols: []string{"URL:.URL"}}, webhooks)
+		require.NoError(t, err)
+		assert.Equal(t, "URL\nhttps://one.example.com\nhttps://two.example.com\n", buffer.String())
+	})
+
+	t.Run("table", func(t *testing.T) {
+		out := printList(t, "table")
+		assert.Contains(t, out, "OWNER")
+		assert.Contains(t, out, "webhook2")
+	})
+
+	t.Run("wide falls back to default table", func(t *testing.T) {
+		assert.Equal(t, printList(t, "table"), printList(t, "wide"))
+	})
+
+	t.Run("custom columns", func(t *testing.T) {
+		out := printList(t, "custom-columns=URL:.URL")
+		assert.Contains(t, out, "https://two.example.com")
+		assert.NotContains(t, out, "OWNER")
+	})
+
+	t.Run("name", func(t *testing.T) {
+		assert.Equal(t, "webhook1\nwebhook2\n", printList(t, "name"))
+	})
+
+	t.Run("go template", func(t *testing.T) {
+		assert.Equal(t, "webhook1=owner1 webhook2=owner2 \n", printList(t, `go-template={{range .}}{{.ID}}={{.OwnerID}} {{end}}`))
+	})
+
+	t.Run("jsonpath", func(t *testing.T) {
+		assert.Equal(t, "webhook1 webhook2\n", printList(t, "jsonpath={[*].ID}"))
+	})
+
+	t.Run("invalid go template", func(t *testing.T) {
+		err := printer.printList(&bytes.Buffer{}, tableOptions{output: "go-template={{.ID"}, webhooks)
+		assert.Error(t, err)
+	})
+
+	t.Run("object", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+		err := printer.printObject(buffer, tableOptions{output: "jsonpath={.URL}"}, webhooks[0])
+		require.NoError(t, err)
+		assert.Equal(t, "https://one.example.com\n", buffer.String())
+
+		buffer.Reset()
+		err = printer.printObject(buffer, tableOptions{output: "csv"}, webhooks[1])
+		require.NoError(t, err)
+		assert.Equal(t, "ID,OWNER\nwebhook2,owner2\n", buffer.String())
+	})
+
+	t.Run("nil object", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+		err := printer.printObject(buffer, tableOptions{output: "csv"}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "ID,OWNER\n", buffer.String())
+	})
+
+	t.Run("name with custom ID", func(t *testing.T) {
+		printer := printer
+		printer.id = func(webhook *model.Webhook) string {
+			return webhook.OwnerID
+		}
+		buffer := &bytes.Buffer{}
+		err := printer.printList(buffer, tableOptions{output: "name"}, webhooks)
+		require.NoError(t, err)
+		assert.Equal(t, "owner1\nowner2\n", buffer.String())
+	})
+}
+
+func TestWatchOutput(t *testing.T) {
+	t.Run("without watch", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+		renders := 0
+		err := watchOutput(context.Background(), buffer, watchOptions{}, func(w io.Writer) error {
+			renders++
+			fmt.Fprintln(w, "output")
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 1, renders)
+		assert.Equal(t, "output\n", buffer.String())
+	})
+
+	t.Run("prints changes only", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		buffer := &bytes.Buffer{}
+		outputs := []string{"one", "one", "two", "two", "three"}
+		renders := 0
+		err := watchOutput(ctx, buffer, watchOptions{watch: true, watchInterval: time.Millisecond}, func(w io.Writer) error {
+			fmt.Fprintln(w, outputs[renders])
+			renders++
+			if renders == len(outputs) {
+				cancel()
+			}
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, len(outputs), renders)
+		assert.Equal(t, "one\ntwo\nthree\n", buffer.String())
+	})
+
+	t.Run("render error", func(t *testing.T) {
+		err := watchOutput(context.Background(), &bytes.Buffer{}, watchOptions{watch: true, watchInterval: time.Millisecond}, func(w io.Writer) error {
+			return fmt.Errorf("failed")
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid interval", func(t *testing.T) {
+		err := watchOutput(context.Background(), &bytes.Buffer{}, watchOptions{watch: true}, func(w io.Writer) error {
+			return nil
+		})
+		assert.Error(t, err)
+	})
+}
@@ -18,6 +18,8 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
 	cf "github.com/cloudflare/cloudflare-go"
 	"github.com/gorilla/mux"
 	awat "github.com/mattermost/awat/model"
@@ -162,6 +164,63 @@ func executeServerCmd(flags serverFlags) error {
 		return errors.Wrap(err, "invalid installation scheduling options")
 	}
 
+	eventRetentionConfig := supervisor.EventRetentionConfig{
+		EventMaxAge:         flags.eventRetentionMaxAge,
+		EventDeliveryMaxAge: flags.eventDeliveryRetentionMaxAge,
+		BatchSize:           flags.eventRetentionBatchSize,
+		ArchiveBucket:       flags.eventRetentionArchiveBucket,
+	}
+	if err = eventRetentionConfig.Validate(); err != nil {
+		return errors.Wrap(err, "invalid event retention options")
+	}
+
+	installationUsageConfig := supervisor.InstallationUsageConfig{
+		Interval:          flags.installationUsageInterval,
+		Retention:         flags.installationUsageRetention,
+		FilestoreInterval: flags.installationUsageFilestoreInterval,
+	}
+
+	utilityRemediationPolicy := model.UtilityRemediationPolicy{
+		Action:         model.UtilityRemediationAction(flags.utilityRemediationAction),
+		InitialBackoff: flags.utilityRemediationInitialBackoff,
+		MaxBackoff:     flags.utilityRemediationMaxBackoff,
+		MaxAttempts:    flags.utilityRemediationMaxAttempts,
+	}
+	if err = utilityRemediationPolicy.Validate(); err != nil {
+		return errors.Wrap(err, "invalid utility remediation options")
+	}
+
+	sizeRecommendationPolicy := model.SizeRecommendationPolicy{
+		Candidates:        flags.sizeRecommendationCandidates,
+		TargetUtilization: flags.sizeRecommendationTargetUtilization,
+		Lookback:          flags.sizeRecommendationLookback,
+		MinSamples:        flags.sizeRecommendationMinSamples,
+	}
+	if err = sizeRecommendationPolicy.Validate(); err != nil {
+		return errors.Wrap(err, "invalid size recommendation options")
+	}
+
+	installationRightSizingConfig := supervisor.InstallationRightSizingConfig{
+		Policy:        sizeRecommendationPolicy,
+		MinConfidence: flags.rightSizingMinConfidence,
+		MaxPerRun:     flags.rightSizingMaxPerRun,
+		MaintenanceWindow: model.MaintenanceWindow{
+			StartHour: flags.rightSizingWindowStart,
+			EndHour:   flags.rightSizingWindowEnd,
+		},
+	}
+	if err = installationRightSizingConfig.MaintenanceWindow.Validate(); err != nil {
+		return errors.Wrap(err, "invalid right-sizing options")
+	}
+
+	var usagePriceTable *model.UsagePriceTable
+	if flags.usagePriceTable != "" {
+		usagePriceTable, err = readUsagePriceTable(flags.usagePriceTable)
+		if err != nil {
+			return errors.Wrap(err, "invalid usage price table")
+		}
+	}
+
 	supervisorsEnabled := flags.supervisorOptions
 	if flags.disableAllSupervisors {
 		supervisorsEnabled = supervisorOptions{} // reset to zero
@@ -171,6 +230,12 @@ func executeServerCmd(flags serverFlags) error {
 		logger.Warn("Server will be running with no supervisors. Only API functionality will work.")
 	}
 
+	// Right-sizing restarts installations, so it must not run at any time of
+	// day unless explicitly configured to.
+	if supervisorsEnabled.installationRightSizingSupervisor && (!flags.rightSizingWindowStartChanged || !flags.rightSizingWindowEndChanged) {
+		return errors.New("the installation right-sizing supervisor requires --right-sizing-window-start and --right-sizing-window-end")
+	}
+
 	model.SetDeployOperators(flags.deployMySQLOperator, flags.deployMinioOperator)
 
 	wd, err := os.Getwd()
@@ -201,6 +266,16 @@ func executeServerCmd(flags serverFlags) error {
 		"import-supervisor":                             supervisorsEnabled.importSupervisor,
 		"installation-db-restoration-supervisor":        supervisorsEnabled.installationDBRestorationSupervisor,
 		"installation-db-migration-supervisor":          supervisorsEnabled.installationDBMigrationSupervisor,
+		"installation-filestore-migration-supervisor":   supervisorsEnabled.installationFilestoreMigrationSupervisor,
+		"multitenant-database-rebalance-supervisor":     supervisorsEnabled.multitenantDatabaseRebalanceSupervisor,
+		"utility-rollout-supervisor":                    supervisorsEnabled.utilityRolloutSupervisor,
+		"event-retention-supervisor":                    supervisorsEnabled.eventRetentionSupervisor,
+		"subscription-stats-supervisor":                 supervisorsEnabled.subscriptionStatsSupervisor,
+		"cluster-utility-health-supervisor":             supervisorsEnabled.clusterUtilityHealthSupervisor,
+		"nodegroup-autoscaling-supervisor":              supervisorsEnabled.nodeGroupAutoscalingSupervisor,
+		"installation-usage-supervisor":                 supervisorsEnabled.installationUsageSupervisor,
+		"installation-right-sizing-supervisor":          supervisorsEnabled.installationRightSizingSupervisor,
+		"utility-remediation-action":                    flags.utilityRemediationAction,
 		"store-version":                                 currentVersion,
 		"state-store":                                   flags.s3StateStore,
 		"working-directory":                             wd,
@@ -262,20 +337,21 @@ func executeServerCmd(flags serverFlags) error {
 	}
 
 	provisioningParams := provisioner.ProvisioningParams{
-		S3StateStore:              flags.s3StateStore,
-		AllowCIDRRangeList:        flags.allowListCIDRRange,
-		VpnCIDRList:               flags.vpnListCIDR,
-		Owner:                     owner,
-		UseExistingAWSResources:   flags.useExistingResources,
-		DeployMysqlOperator:       flags.deployMySQLOperator,
-		DeployMinioOperator:       flags.deployMinioOperator,
-		MattermostOperatorHelmDir: flags.mattermostOperatorHelmDir,
-		NdotsValue:                flags.ndotsDefaultValue,
-		InternalIPRanges:          flags.internalIPRanges,
-		SLOInstallationGroups:     flags.sloInstallationGroups,
-		SLOEnterpriseGroups:       flags.sloEnterpriseGroups,
-		EtcdManagerEnv:            etcdManagerEnv,
-		PodProbeOverrides:         flags.generateProbeOverrides(),
+		S3StateStore:                flags.s3StateStore,
+		AllowCIDRRangeList:          flags.allowListCIDRRange,
+		VpnCIDRList:                 flags.vpnListCIDR,
+		Owner:                       owner,
+		UseExistingAWSResources:     flags.useExistingResources,
+		DeployMysqlOperator:         flags.deployMySQLOperator,
+		DeployMinioOperator:         flags.deployMinioOperator,
+		MattermostOperatorHelmDir:   flags.mattermostOperatorHelmDir,
+		NdotsValue:                  flags.ndotsDefaultValue,
+		InternalIPRanges:            flags.internalIPRanges,
+		SLOInstallationGroups:       flags.sloInstallationGroups,
+		SLOEnterpriseGroups:         flags.sloEnterpriseGroups,
+		EtcdManagerEnv:              etcdManagerEnv,
+		PodProbeOverrides:           flags.generateProbeOverrides(),
+		InstallationNetworkPolicies: flags.installationNetworkPolicies,
 	}
 
 	resourceUtil := utils.NewResourceUtil(instanceID, awsClient, dbClusterUtilizationSettingsFromFlags(flags), flags.disableDBInitCheck, flags.enableS3Versioning)
@@ -318,6 +394,18 @@ func executeServerCmd(flags serverFlags) error {
 		RetryWorkers:    2,
 		UpToDateWorkers: 2,
 		MaxBurstWorkers: 100,
+		Sinks: events.EventSinks{
+			model.SubscriptionSinkSQS: events.NewSQSSink(sqs.NewFromConfig(awsConfig)),
+			model.SubscriptionSinkSNS: events.NewSNSSink(sns.NewFromConfig(awsConfig)),
+		},
+		Metrics: cloudMetrics,
+	}
+	if flags.enableEventFileSink {
+		fileSink, err := events.NewFileSink(flags.eventFileSinkDir)
+		if err != nil {
+			return errors.Wrap(err, "failed to create events file sink")
+		}
+		delivererCfg.Sinks[model.SubscriptionSinkFile] = fileSink
 	}
 	deliveryCtx, deliveryCancel := context.WithCancel(context.Background())
 	eventsDeliverer := events.NewDeliverer(deliveryCtx, sqlStore, instanceID, logger, delivererCfg)
@@ -375,6 +463,15 @@ func executeServerCmd(flags serverFlags) error {
 	if supervisorsEnabled.installationDBMigrationSupervisor {
 		multiDoer = append(multiDoer, supervisor.NewInstallationDBMigrationSupervisor(sqlStore, awsClient, resourceUtil, instanceID, provisionerObj, eventsProducer, logger))
 	}
+	if supervisorsEnabled.installationFilestoreMigrationSupervisor {
+		multiDoer = append(multiDoer, supervisor.NewInstallationFilestoreMigrationSupervisor(sqlStore, awsClient, resourceUtil, instanceID, keepFileStoreData, provisionerObj, eventsProducer, logger))
+	}
+	if supervisorsEnabled.multitenantDatabaseRebalanceSupervisor {
+		multiDoer = append(multiDoer, supervisor.NewMultitenantDatabaseRebalanceSupervisor(sqlStore, awsClient, resourceUtil, eventsProducer, instanceID, logger))
+	}
+	if supervisorsEnabled.utilityRolloutSupervisor {
+		multiDoer = append(multiDoer, supervisor.NewUtilityRolloutSupervisor(sqlStore, provisionerObj, awsClient, eventsProducer, instanceID, logger))
+	}
 
 	serverAuthConfig := &auth.ServerConfig{
 		Issuer:                               flags.Issuer,
@@ -402,9 +499,32 @@ func executeServerCmd(flags serverFlags) error {
 	if flags.slowPoll == 0 {
 		logger.WithField("slow-poll", flags.slowPoll).Info("Slow scheduler is disabled")
 	}
+	var slowMultiDoer supervisor.MultiDoer
 	if supervisorsEnabled.installationDeletionSupervisor {
-		var slowMultiDoer supervisor.MultiDoer
 		slowMultiDoer = append(slowMultiDoer, supervisor.NewInstallationDeletionSupervisor(instanceID, flags.installationDeletionPendingTime, flags.installationDeletionMaxUpdating, sqlStore, eventsProducer, logger))
+	}
+	if supervisorsEnabled.multitenantDatabaseCapacitySupervisor {
+		slowMultiDoer = append(slowMultiDoer, supervisor.NewMultitenantDatabaseCapacitySupervisor(sqlStore, resourceUtil, cloudMetrics, flags.multitenantDatabaseCapacityLookback, logger))
+	}
+	if supervisorsEnabled.eventRetentionSupervisor {
+		slowMultiDoer = append(slowMultiDoer, supervisor.NewEventRetentionSupervisor(sqlStore, awsClient, cloudMetrics, eventRetentionConfig, logger))
+	}
+	if supervisorsEnabled.subscriptionStatsSupervisor {
+		slowMultiDoer = append(slowMultiDoer, supervisor.NewSubscriptionStatsSupervisor(sqlStore, cloudMetrics, logger))
+	}
+	if supervisorsEnabled.clusterUtilityHealthSupervisor {
+		slowMultiDoer = append(slowMultiDoer, supervisor.NewClusterUtilityHealthSupervisor(sqlStore, provisionerObj, eventsProducer, utilityRemediationPolicy, instanceID, logger))
+	}
+	if supervisorsEnabled.nodeGroupAutoscalingSupervisor {
+		slowMultiDoer = append(slowMultiDoer, supervisor.NewNodeGroupAutoscalingSupervisor(sqlStore, provisionerObj, instanceID, logger))
+	}
+	if supervisorsEnabled.installationUsageSupervisor {
+		slowMultiDoer = append(slowMultiDoer, supervisor.NewInstallationUsageSupervisor(sqlStore, provisionerObj, awsClient, installationUsageConfig, instanceID, logger))
+	}
+	if supervisorsEnabled.installationRightSizingSupervisor {
+		slowMultiDoer = append(slowMultiDoer, supervisor.NewInstallationRightSizingSupervisor(sqlStore, eventsProducer, installationRightSizingConfig, instanceID, logger))
+	}
+	if len(slowMultiDoer) > 0 {
 		slowSupervisor := supervisor.NewScheduler(slowMultiDoer, time.Duration(flags.slowPoll)*time.Second, logger)
 		defer slowSupervisor.Close()
 	}
@@ -444,6 +564,8 @@ func executeServerCmd(flags serverFlags) error {
 		InstallationDeletionExpiryDefault: flags.installationDeletionPendingTime,
 		Logger:                            logger,
 		AuthConfig:                        serverAuthConfig,
+		UsagePriceTable:                   usagePriceTable,
+		SizeRecommendationPolicy:          &sizeRecommendationPolicy,
 	})
 
 	srv := &http.Server{
@@ -497,6 +619,24 @@ func dbClusterUtilizationSettingsFromFlags(sf serverFlags) utils.DBClusterUtiliz
 	}
 }
 
+func readUsagePriceTable(path string) (*model.UsagePriceTable, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open usage price table file")
+	}
+	defer file.Close()
+
+	priceTable, err := model.UsagePriceTableFromReader(file)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode usage price table")
+	}
+	if err = priceTable.Validate(); err != nil {
+		return nil, err
+	}
+
+	return priceTable, nil
+}
+
 func checkRequirements(logger logrus.FieldLogger) error {
 	// Check for required tool binaries.
 	silentLogger := logrus.New()
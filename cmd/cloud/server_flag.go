@@ -14,20 +14,51 @@ import (
 )
 
 type supervisorOptions struct {
-	disableAllSupervisors               bool
-	clusterSupervisor                   bool
-	groupSupervisor                     bool
-	installationSupervisor              bool
-	installationDeletionSupervisor      bool
-	clusterInstallationSupervisor       bool
-	backupSupervisor                    bool
-	importSupervisor                    bool
-	installationDBRestorationSupervisor bool
-	installationDBMigrationSupervisor   bool
+	disableAllSupervisors                    bool
+	clusterSupervisor                        bool
+	groupSupervisor                          bool
+	installationSupervisor                   bool
+	installationDeletionSupervisor           bool
+	clusterInstallationSupervisor            bool
+	backupSupervisor                         bool
+	importSupervisor                         bool
+	installationDBRestorationSupervisor      bool
+	installationDBMigrationSupervisor        bool
+	installationFilestoreMigrationSupervisor bool
+	multitenantDatabaseRebalanceSupervisor   bool
+	utilityRolloutSupervisor                 bool
+	multitenantDatabaseCapacitySupervisor    bool
+	eventRetentionSupervisor                 bool
+	subscriptionStatsSupervisor              bool
+	clusterUtilityHealthSupervisor           bool
+	nodeGroupAutoscalingSupervisor           bool
+	installationUsageSupervisor              bool
+	installationRightSizingSupervisor        bool
+
+	multitenantDatabaseCapacityLookback time.Duration
+
+	eventRetentionMaxAge         time.Duration
+	eventDeliveryRetentionMaxAge time.Duration
+	eventRetentionBatchSize      uint64
+	eventRetentionArchiveBucket  string
 
 	installationDeletionPendingTime time.Duration
 	installationDeletionMaxUpdating int64
 
+	utilityRemediationAction         string
+	utilityRemediationInitialBackoff time.Duration
+	utilityRemediationMaxBackoff     time.Duration
+	utilityRemediationMaxAttempts    int
+
+	installationUsageInterval          time.Duration
+	installationUsageRetention         time.Duration
+	installationUsageFilestoreInterval time.Duration
+
+	rightSizingMinConfidence float64
+	rightSizingMaxPerRun     int
+	rightSizingWindowStart   int
+	rightSizingWindowEnd     int
+
 	disableDNSUpdates bool
 	awatAddress       string
 }
@@ -44,9 +75,35 @@ func (flags *supervisorOptions) addFlags(command *cobra.Command) {
 	command.Flags().BoolVar(&flags.importSupervisor, "import-supervisor", false, "Whether this server will run a workspace import supervisor or not.")
 	command.Flags().BoolVar(&flags.installationDBRestorationSupervisor, "installation-db-restoration-supervisor", false, "Whether this server will run an installation db restoration supervisor or not.")
 	command.Flags().BoolVar(&flags.installationDBMigrationSupervisor, "installation-db-migration-supervisor", false, "Whether this server will run an installation db migration supervisor or not.")
+	command.Flags().BoolVar(&flags.installationFilestoreMigrationSupervisor, "installation-filestore-migration-supervisor", false, "Whether this server will run an installation filestore migration supervisor or not.")
+	command.Flags().BoolVar(&flags.multitenantDatabaseRebalanceSupervisor, "multitenant-database-rebalance-supervisor", false, "Whether this server will run a multitenant database rebalance supervisor or not.")
+	command.Flags().BoolVar(&flags.utilityRolloutSupervisor, "utility-rollout-supervisor", false, "Whether this server will run a utility rollout supervisor or not.")
+	command.Flags().BoolVar(&flags.multitenantDatabaseCapacitySupervisor, "multitenant-database-capacity-supervisor", false, "Whether this server will run a multitenant database capacity supervisor exporting capacity metrics or not. (slow-poll supervisor)")
+	command.Flags().BoolVar(&flags.eventRetentionSupervisor, "event-retention-supervisor", false, "Whether this server will run an event retention supervisor pruning old events or not. (slow-poll supervisor)")
+	command.Flags().BoolVar(&flags.subscriptionStatsSupervisor, "subscription-stats-supervisor", false, "Whether this server will run a subscription stats supervisor exporting event delivery backlog metrics or not. (slow-poll supervisor)")
+	command.Flags().BoolVar(&flags.clusterUtilityHealthSupervisor, "cluster-utility-health-supervisor", false, "Whether this server will run a cluster utility health supervisor checking and remediating the health of cluster utilities or not. (slow-poll supervisor)")
+	command.Flags().BoolVar(&flags.nodeGroupAutoscalingSupervisor, "nodegroup-autoscaling-supervisor", false, "Whether this server will run a nodegroup autoscaling supervisor reconciling the autoscaling policies of EKS nodegroups or not. (slow-poll supervisor)")
+	command.Flags().BoolVar(&flags.installationUsageSupervisor, "installation-usage-supervisor", false, "Whether this server will run an installation usage supervisor sampling the resources used by installations or not. (slow-poll supervisor)")
+	command.Flags().BoolVar(&flags.installationRightSizingSupervisor, "installation-right-sizing-supervisor", false, "Whether this server will run an installation right-sizing supervisor applying size recommendations to installations or not. (slow-poll supervisor)")
 
 	command.Flags().DurationVar(&flags.installationDeletionPendingTime, "installation-deletion-pending-time", 3*time.Minute, "The amount of time that installations will stay in the deletion queue before they are actually deleted. Set to 0 for immediate deletion.")
+	command.Flags().DurationVar(&flags.multitenantDatabaseCapacityLookback, "multitenant-database-capacity-lookback", model.DefaultCapacityLookbackDays*24*time.Hour, "The amount of installation creation history used to forecast multitenant database growth.")
 	command.Flags().Int64Var(&flags.installationDeletionMaxUpdating, "installation-deletion-max-updating", 25, "A soft limit on the number of installations that the provisioner will delete at one time from the group of deletion-pending installations.")
+	command.Flags().DurationVar(&flags.eventRetentionMaxAge, "event-retention-max-age", 90*24*time.Hour, "The age after which events delivered to all subscriptions are pruned. Undelivered and failed events are kept. Set to 0 to keep events forever.")
+	command.Flags().DurationVar(&flags.eventDeliveryRetentionMaxAge, "event-delivery-retention-max-age", 30*24*time.Hour, "The time since the last delivery attempt after which delivered event deliveries are pruned. Failed deliveries are kept for replay. Set to 0 to keep event deliveries until their events are pruned.")
+	command.Flags().Uint64Var(&flags.eventRetentionBatchSize, "event-retention-batch-size", 1000, "The number of rows pruned at once by the event retention supervisor.")
+	command.Flags().StringVar(&flags.eventRetentionArchiveBucket, "event-retention-archive-bucket", "", "The S3 bucket to which pruned events are archived as compressed NDJSON. Leave empty to prune without archiving.")
+	command.Flags().StringVar(&flags.utilityRemediationAction, "utility-remediation-action", string(model.UtilityRemediationNone), "The action taken on unhealthy cluster utilities: none, helm-upgrade or restart.")
+	command.Flags().DurationVar(&flags.utilityRemediationInitialBackoff, "utility-remediation-initial-backoff", 10*time.Minute, "The time a cluster utility must stay unhealthy before it is remediated. Doubles after every remediation attempt.")
+	command.Flags().DurationVar(&flags.utilityRemediationMaxBackoff, "utility-remediation-max-backoff", 6*time.Hour, "The maximum time between two remediation attempts of an unhealthy cluster utility.")
+	command.Flags().IntVar(&flags.utilityRemediationMaxAttempts, "utility-remediation-max-attempts", 5, "The maximum number of remediation attempts of an unhealthy cluster utility. Set to 0 for no limit.")
+	command.Flags().DurationVar(&flags.installationUsageInterval, "installation-usage-interval", time.Hour, "The minimum time between two samples of the resources used by installations.")
+	command.Flags().DurationVar(&flags.installationUsageRetention, "installation-usage-retention", 90*24*time.Hour, "The age after which installation usage samples are pruned. Set to 0 to keep samples forever.")
+	command.Flags().DurationVar(&flags.installationUsageFilestoreInterval, "installation-usage-filestore-interval", 24*time.Hour, "The minimum time between two measurements of the S3 filestore of installations. Samples in between reuse the latest measurement. Set to 0 to measure on every sample.")
+	command.Flags().Float64Var(&flags.rightSizingMinConfidence, "right-sizing-min-confidence", 0.8, "The confidence required to automatically apply a size recommendation.")
+	command.Flags().IntVar(&flags.rightSizingMaxPerRun, "right-sizing-max-per-run", 5, "The maximum number of installations resized at once by the right-sizing supervisor.")
+	command.Flags().IntVar(&flags.rightSizingWindowStart, "right-sizing-window-start", 0, "The UTC hour at which the maintenance window of the right-sizing supervisor starts. Required with --installation-right-sizing-supervisor.")
+	command.Flags().IntVar(&flags.rightSizingWindowEnd, "right-sizing-window-end", 0, "The UTC hour at which the maintenance window of the right-sizing supervisor ends. The window is always open when it starts and ends at the same hour. Required with --installation-right-sizing-supervisor.")
 	command.Flags().BoolVar(&flags.disableDNSUpdates, "disable-dns-updates", false, "If set to true DNS updates will be disabled when updating Installations.")
 	command.Flags().StringVar(&flags.awatAddress, "awat", "http://localhost:8077", "The location of the Automatic Workspace Archive Translator if the import supervisor is being used.")
 }
@@ -205,6 +262,7 @@ type installationOptions struct {
 	utilitiesGitPath              string
 	enableS3Versioning            bool
 	internalIPRanges              []string
+	installationNetworkPolicies   bool
 }
 
 func (flags *installationOptions) addFlags(command *cobra.Command) {
@@ -219,6 +277,7 @@ func (flags *installationOptions) addFlags(command *cobra.Command) {
 	command.Flags().StringVar(&flags.utilitiesGitPath, "utilities-git-path", "", "The git path to use for utilities. For example /gitops/gitops.git")
 	command.Flags().BoolVar(&flags.enableS3Versioning, "enable-s3-versioning", false, "Whether to enable S3 versioning for the installation bucket or not")
 	command.Flags().StringSliceVar(&flags.internalIPRanges, "internal-ip-ranges", []string{}, "Some ranges that needed to be allowed for operational reasons")
+	command.Flags().BoolVar(&flags.installationNetworkPolicies, "installation-network-policies", false, "Whether to manage default-deny NetworkPolicies isolating installations, only allowing ingress from nginx and prometheus, and egress to DNS, their database and filestore, and outbound HTTP(S) and SMTP, or not. RDS and external databases are only reached within the cluster VPC, external databases outside of it need an egress exception. Replaces the legacy installation NetworkPolicies.")
 }
 
 type dbUtilizationSettings struct {
@@ -254,6 +313,9 @@ type serverFlagChanged struct {
 	probeReadinessInitialDelaySecondsChanged bool
 	probeReadinessPeriodSecondsChanged       bool
 	probeReadinessTimeoutSecondsChanged      bool
+
+	rightSizingWindowStartChanged bool
+	rightSizingWindowEndChanged   bool
 }
 
 type serverAuthFlags struct {
@@ -291,6 +353,9 @@ func (flags *serverFlagChanged) addFlags(command *cobra.Command) {
 	flags.probeReadinessInitialDelaySecondsChanged = command.Flags().Changed("probe-readiness-initial-delay-seconds")
 	flags.probeReadinessPeriodSecondsChanged = command.Flags().Changed("probe-readiness-period-seconds")
 	flags.probeReadinessTimeoutSecondsChanged = command.Flags().Changed("probe-readiness-timeout-seconds")
+
+	flags.rightSizingWindowStartChanged = command.Flags().Changed("right-sizing-window-start")
+	flags.rightSizingWindowEndChanged = command.Flags().Changed("right-sizing-window-end")
 }
 
 type serverFlags struct {
@@ -318,6 +383,16 @@ type serverFlags struct {
 	maxSchemas    int64
 	enableRoute53 bool
 
+	usagePriceTable string
+
+	enableEventFileSink bool
+	eventFileSinkDir    string
+
+	sizeRecommendationCandidates        []string
+	sizeRecommendationTargetUtilization float64
+	sizeRecommendationLookback          time.Duration
+	sizeRecommendationMinSamples        int64
+
 	poll     int
 	slowPoll int
 }
@@ -346,6 +421,14 @@ func (flags *serverFlags) addFlags(command *cobra.Command) {
 	command.Flags().StringVar(&flags.database, "database", "", "The database backing the provisioning server.")
 	command.Flags().Int64Var(&flags.maxSchemas, "default-max-schemas-per-logical-database", 10, "When importing and creating new proxy multitenant databases, this value is used for MaxInstallationsPerLogicalDatabase.")
 	command.Flags().BoolVar(&flags.enableRoute53, "installation-enable-route53", false, "Specifies whether CNAME records for Installation should be created in Route53 as well.")
+	command.Flags().StringVar(&flags.usagePriceTable, "usage-price-table", "", "The path to a JSON file with the unit prices used to estimate the cost of the resources used by installations. Leave empty to report usage without cost estimates.")
+	command.Flags().BoolVar(&flags.enableEventFileSink, "enable-event-file-sink", false, "Allow subscriptions to deliver events to local files. Intended for development.")
+	command.Flags().StringVar(&flags.eventFileSinkDir, "event-file-sink-dir", "", "The directory to which the events file sink is restricted.")
+	command.MarkFlagsRequiredTogether("enable-event-file-sink", "event-file-sink-dir")
+	command.Flags().StringSliceVar(&flags.sizeRecommendationCandidates, "size-recommendation-candidates", model.DefaultSizeRecommendationCandidates, "The installation sizes that can be recommended from the observed usage of installations.")
+	command.Flags().Float64Var(&flags.sizeRecommendationTargetUtilization, "size-recommendation-target-utilization", 0.8, "The fraction of the requested CPU and memory that the peak usage of an installation should use.")
+	command.Flags().DurationVar(&flags.sizeRecommendationLookback, "size-recommendation-lookback", 7*24*time.Hour, "The installation usage history used to recommend installation sizes.")
+	command.Flags().Int64Var(&flags.sizeRecommendationMinSamples, "size-recommendation-min-samples", 24, "The number of usage samples of an installation required to recommend a size.")
 
 	command.Flags().IntVar(&flags.poll, "poll", 30, "The interval in seconds to poll for background work.")
 	command.Flags().IntVar(&flags.slowPoll, "slow-poll", 60, "The interval in seconds to poll for background work for supervisors that are not time sensitive (slow-poll supervisors).")
@@ -6,7 +6,7 @@ package main
 
 import (
 	"fmt"
-	"os"
+	"io"
 	"regexp"
 	"strings"
 
@@ -15,12 +15,8 @@ import (
 	"k8s.io/client-go/util/jsonpath"
 )
 
-func getTableOutputOption(to tableOptions) (bool, []string) {
-	return to.outputToTable || len(to.customCols) > 0, to.customCols
-}
-
-func printTable(columnNames []string, values [][]string) {
-	table := tablewriter.NewWriter(os.Stdout)
+func writeTable(w io.Writer, columnNames []string, values [][]string) {
+	table := tablewriter.NewWriter(w)
 	table.SetAlignment(tablewriter.ALIGN_LEFT)
 	table.SetHeader(columnNames)
 
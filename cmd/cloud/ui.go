@@ -0,0 +1,397 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/pkg/errors"
+	"github.com/rivo/tview"
+	"github.com/spf13/cobra"
+)
+
+func newCmdUI() *cobra.Command {
+	var flags uiFlags
+
+	cmd := &cobra.Command{
+		Use:   "ui",
+		Short: "Browse and operate cloud server resources in an interactive terminal UI.",
+		Long: `Browse clusters, installations, groups and operations in an interactive terminal UI.
+
+Keys:
+  1-4, Tab     switch between clusters, installations, groups and operations
+  Enter        show details of the selected installation
+  Esc          go back to the list
+  r            refresh
+  q            quit
+
+Installation actions: h hibernate, w wake up, l lock API, u unlock API, b back up.
+Cluster and group actions: l lock API, u unlock API.
+All actions ask for confirmation.`,
+		RunE: func(command *cobra.Command, args []string) error {
+			command.SilenceUsage = true
+			return executeUICmd(command.Context(), flags)
+		},
+		PreRun: func(cmd *cobra.Command, args []string) {
+			flags.clusterFlags.addFlags(cmd)
+		},
+	}
+
+	flags.addFlags(cmd)
+
+	return cmd
+}
+
+func executeUICmd(ctx context.Context, flags uiFlags) error {
+	if flags.refreshSeconds < 1 {
+		return errors.Errorf("refresh seconds (%d) must be set to 1 or higher", flags.refreshSeconds)
+	}
+	client := createClient(ctx, flags.clusterFlags)
+
+	ui := newTUIApp(client, newTUIViews())
+
+	return ui.run(ctx, time.Duration(flags.refreshSeconds)*time.Second)
+}
+
+const (
+	tuiPageList    = "list"
+	tuiPageDetails = "details"
+	tuiPageConfirm = "confirm"
+)
+
+// tuiApp is the terminal UI. All fields are only accessed from the event
+// loop of the application.
+type tuiApp struct {
+	app    *tview.Application
+	client tuiClient
+	views  []*tuiView
+
+	pages   *tview.Pages
+	tabs    *tview.TextView
+	list    *tview.Table
+	details *tview.Table
+	status  *tview.TextView
+
+	current   int
+	data      *tuiTable
+	detailsID string
+	// generation is increased whenever the shown data changes, so that
+	// results of outdated loads are discarded.
+	generation int
+}
+
+func newTUIApp(client tuiClient, views []*tuiView) *tuiApp {
+	ui := &tuiApp{
+		app:     tview.NewApplication(),
+		client:  client,
+		views:   views,
+		pages:   tview.NewPages(),
+		tabs:    tview.NewTextView().SetDynamicColors(true),
+		list:    tview.NewTable().SetSelectable(true, false).SetFixed(1, 0),
+		details: tview.NewTable().SetSelectable(true, false),
+		status:  tview.NewTextView().SetDynamicColors(true),
+	}
+	ui.list.SetBorder(true)
+	ui.details.SetBorder(true)
+
+	ui.pages.AddPage(tuiPageList, ui.list, true, true)
+	ui.pages.AddPage(tuiPageDetails, ui.details, true, false)
+
+	layout := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(ui.tabs, 1, 0, false).
+		AddItem(ui.pages, 0, 1, true).
+		AddItem(ui.status, 1, 0, false)
+
+	ui.app.SetRoot(layout, true).SetInputCapture(ui.handleKey)
+
+	return ui
+}
+
+func (ui *tuiApp) run(ctx context.Context, refreshInterval time.Duration) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go func() {
+		ticker := time.NewTicker(refreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				ui.app.QueueUpdate(ui.reload)
+			}
+		}
+	}()
+
+	ui.showView(0)
+
+	return ui.app.Run()
+}
+
+func (ui *tuiApp) view() *tuiView {
+	return ui.views[ui.current]
+}
+
+// handleKey handles the keys of the list and details pages. Other keys are
+// passed to the focused primitive.
+func (ui *tuiApp) handleKey(event *tcell.EventKey) *tcell.EventKey {
+	if ui.pages.HasPage(tuiPageConfirm) {
+		return event
+	}
+	inDetails := ui.detailsID != ""
+
+	switch event.Key() {
+	case tcell.KeyTab:
+		if !inDetails {
+			ui.showView((ui.current + 1) % len(ui.views))
+		}
+		return nil
+	case tcell.KeyEnter:
+		if !inDetails && ui.view().details != nil {
+			if id := ui.selectedID(); id != "" {
+				ui.showDetails(id)
+			}
+		}
+		return nil
+	case tcell.KeyEscape, tcell.KeyBackspace, tcell.KeyBackspace2:
+		if inDetails {
+			ui.showList()
+		}
+		return nil
+	case tcell.KeyRune:
+	default:
+		return event
+	}
+
+	key := event.Rune()
+	switch {
+	case key == 'q':
+		ui.app.Stop()
+	case key == 'r':
+		ui.reload()
+	case key >= '1' && key <= '9':
+		index := int(key - '1')
+		if !inDetails && index < len(ui.views) {
+			ui.showView(index)
+		}
+	default:
+		action, ok := ui.view().action(key)
+		if !ok {
+			return event
+		}
+		id := ui.detailsID
+		if !inDetails {
+			id = ui.selectedID()
+		}
+		if id != "" {
+			ui.confirm(action, id)
+		}
+	}
+
+	return nil
+}
+
+func (ui *tuiApp) showView(index int) {
+	ui.current = index
+	ui.data = nil
+	ui.list.Clear()
+	ui.list.SetTitle(" " + ui.view().name + " ")
+
+	var tabs []string
+	for i, view := range ui.views {
+		if i == index {
+			tabs = append(tabs, fmt.Sprintf("[black:white] %d %s [-:-]", i+1, view.name))
+		} else {
+			tabs = append(tabs, fmt.Sprintf(" %d %s ", i+1, view.name))
+		}
+	}
+	ui.tabs.SetText(strings.Join(tabs, " "))
+
+	ui.showList()
+}
+
+func (ui *tuiApp) showList() {
+	ui.detailsID = ""
+	ui.pages.SwitchToPage(tuiPageList)
+	ui.app.SetFocus(ui.list)
+	ui.setHelp()
+	ui.reload()
+}
+
+func (ui *tuiApp) showDetails(id string) {
+	ui.detailsID = id
+	ui.details.Clear()
+	ui.details.SetTitle(fmt.Sprintf(" %s %s ", ui.view().kind, id))
+	ui.pages.SwitchToPage(tuiPageDetails)
+	ui.app.SetFocus(ui.details)
+	ui.setHelp()
+	ui.reload()
+}
+
+func (ui *tuiApp) setHelp() {
+	help := []string{"q quit", "r refresh"}
+	if ui.detailsID != "" {
+		help = append(help, "Esc back")
+	} else {
+		help = append(help, "Tab/1-9 switch")
+		if ui.view().details != nil {
+			help = append(help, "Enter details")
+		}
+	}
+	for _, action := range ui.view().actions {
+		help = append(help, fmt.Sprintf("%c %s", action.key, strings.ToLower(action.name)))
+	}
+	ui.status.SetText("[gray]" + strings.Join(help, " | ") + "[-]")
+}
+
+func (ui *tuiApp) setStatus(message string, err error) {
+	if err != nil {
+		ui.status.SetText(fmt.Sprintf("[red]%s: %s[-]", message, tview.Escape(err.Error())))
+		return
+	}
+	ui.status.SetText(fmt.Sprintf("[green]%s[-]", message))
+}
+
+// reload loads the shown data in the background.
+func (ui *tuiApp) reload() {
+	ui.generation++
+	generation := ui.generation
+	view := ui.view()
+	detailsID := ui.detailsID
+
+	go func() {
+		if detailsID != "" {
+			sections, err := view.details(ui.client, detailsID)
+			ui.app.QueueUpdateDraw(func() {
+				if generation != ui.generation {
+					return
+				}
+				if err != nil {
+					ui.setStatus("Failed to load details", err)
+					return
+				}
+				fillTUIDetails(ui.details, sections)
+			})
+			return
+		}
+
+		data, err := view.load(ui.client)
+		ui.app.QueueUpdateDraw(func() {
+			if generation != ui.generation {
+				return
+			}
+			if err != nil {
+				ui.setStatus("Failed to load "+strings.ToLower(view.name), err)
+				return
+			}
+			selected := ui.selectedID()
+			ui.data = data
+			fillTUITable(ui.list, data, selected)
+		})
+	}()
+}
+
+// selectedID returns the ID of the resource selected in the list.
+func (ui *tuiApp) selectedID() string {
+	if ui.data == nil {
+		return ""
+	}
+	row, _ := ui.list.GetSelection()
+	if row < 1 || row > len(ui.data.ids) {
+		return ""
+	}
+	return ui.data.ids[row-1]
+}
+
+// confirm asks for confirmation and runs the action in the background.
+func (ui *tuiApp) confirm(action tuiAction, id string) {
+	kind := ui.view().kind
+	question := action.confirmation(kind, id)
+
+	modal := tview.NewModal().
+		SetText(question).
+		AddButtons([]string{"No", "Yes"}).
+		SetDoneFunc(func(_ int, label string) {
+			ui.pages.RemovePage(tuiPageConfirm)
+			if label != "Yes" {
+				return
+			}
+			ui.setStatus(fmt.Sprintf("%s %s %s...", action.name, kind, id), nil)
+			go func() {
+				err := action.run(ui.client, id)
+				ui.app.QueueUpdateDraw(func() {
+					if err != nil {
+						ui.setStatus(fmt.Sprintf("Failed to %s %s %s", strings.ToLower(action.name), kind, id), err)
+						return
+					}
+					ui.setStatus(fmt.Sprintf("%s %s %s requested", action.name, kind, id), nil)
+					ui.reload()
+				})
+			}()
+		})
+
+	ui.pages.AddPage(tuiPageConfirm, modal, false, true)
+}
+
+func fillTUITable(table *tview.Table, data *tuiTable, selectedID string) {
+	table.Clear()
+	for column, key := range data.keys {
+		table.SetCell(0, column, tuiHeaderCell(key))
+	}
+
+	selectedRow := 1
+	for i, row := range data.rows {
+		for column, value := range row {
+			table.SetCell(i+1, column, tview.NewTableCell(tview.Escape(value)))
+		}
+		if data.ids[i] == selectedID {
+			selectedRow = i + 1
+		}
+	}
+	table.Select(selectedRow, 0)
+}
+
+func fillTUIDetails(table *tview.Table, sections []tuiSection) {
+	row, _ := table.GetSelection()
+	table.Clear()
+
+	line := 0
+	for _, section := range sections {
+		table.SetCell(line, 0, tview.NewTableCell(section.title).
+			SetSelectable(false).
+			SetTextColor(tcell.ColorAqua).
+			SetAttributes(tcell.AttrBold))
+		line++
+		for column, key := range section.table.keys {
+			table.SetCell(line, column, tuiHeaderCell(key))
+		}
+		line++
+		if len(section.table.rows) == 0 {
+			table.SetCell(line, 0, tview.NewTableCell("<none>").SetTextColor(tcell.ColorGray))
+			line++
+		}
+		for _, values := range section.table.rows {
+			for column, value := range values {
+				table.SetCell(line, column, tview.NewTableCell(tview.Escape(value)))
+			}
+			line++
+		}
+		table.SetCell(line, 0, tview.NewTableCell("").SetSelectable(false))
+		line++
+	}
+	table.Select(row, 0)
+}
+
+func tuiHeaderCell(text string) *tview.TableCell {
+	return tview.NewTableCell(text).
+		SetSelectable(false).
+		SetTextColor(tcell.ColorYellow).
+		SetAttributes(tcell.AttrBold)
+}
@@ -0,0 +1,16 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package main
+
+import "github.com/spf13/cobra"
+
+type uiFlags struct {
+	clusterFlags
+	refreshSeconds int
+}
+
+func (flags *uiFlags) addFlags(command *cobra.Command) {
+	command.Flags().IntVar(&flags.refreshSeconds, "refresh-seconds", 10, "The amount of seconds before the shown resources are refreshed.")
+}
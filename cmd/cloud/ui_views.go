@@ -0,0 +1,290 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+)
+
+// tuiClient is the part of the provisioning server client used by the
+// terminal UI.
+type tuiClient interface {
+	GetClusters(request *model.GetClustersRequest) ([]*model.ClusterDTO, error)
+	LockAPIForCluster(clusterID string) error
+	UnlockAPIForCluster(clusterID string) error
+
+	GetInstallations(request *model.GetInstallationsRequest) ([]*model.InstallationDTO, error)
+	GetInstallation(installationID string, request *model.GetInstallationRequest) (*model.InstallationDTO, error)
+	HibernateInstallation(installationID string) (*model.InstallationDTO, error)
+	WakeupInstallation(installationID string, request *model.PatchInstallationRequest) (*model.InstallationDTO, error)
+	LockAPIForInstallation(installationID string) error
+	UnlockAPIForInstallation(installationID string) error
+	CreateInstallationBackup(installationID string) (*model.InstallationBackup, error)
+	GetInstallationBackups(request *model.GetInstallationBackupsRequest) ([]*model.InstallationBackup, error)
+	GetClusterInstallations(request *model.GetClusterInstallationsRequest) ([]*model.ClusterInstallation, error)
+	ListStateChangeEvents(request *model.ListStateChangeEventsRequest) ([]*model.StateChangeEventData, error)
+
+	GetGroups(request *model.GetGroupsRequest) ([]*model.GroupDTO, error)
+	LockAPIForGroup(groupID string) error
+	UnlockAPIForGroup(groupID string) error
+
+	GetInstallationDBMigrationOperations(request *model.GetInstallationDBMigrationOperationsRequest) ([]*model.InstallationDBMigrationOperation, error)
+	GetInstallationFilestoreMigrationOperations(request *model.GetInstallationFilestoreMigrationOperationsRequest) ([]*model.InstallationFilestoreMigrationOperation, error)
+	GetInstallationDBRestorationOperations(request *model.GetInstallationDBRestorationOperationsRequest) ([]*model.InstallationDBRestorationOperation, error)
+}
+
+// tuiTable is a table of resources. The IDs identify the resource of each
+// row.
+type tuiTable struct {
+	keys []string
+	rows [][]string
+	ids  []string
+}
+
+// newTUITable creates a table from table data whose first column is the
+// resource ID.
+func newTUITable(keys []string, rows [][]string) *tuiTable {
+	ids := make([]string, 0, len(rows))
+	for _, row := range rows {
+		ids = append(ids, row[0])
+	}
+	return &tuiTable{keys: keys, rows: rows, ids: ids}
+}
+
+// tuiSection is a titled table shown in the details of a resource.
+type tuiSection struct {
+	title string
+	table *tuiTable
+}
+
+// tuiAction is a keyboard action performed on the selected resource after
+// confirmation.
+type tuiAction struct {
+	key  rune
+	name string
+	run  func(client tuiClient, id string) error
+}
+
+// confirmation returns the question asked before running the action.
+func (a tuiAction) confirmation(kind, id string) string {
+	return fmt.Sprintf("%s %s %s?", a.name, kind, id)
+}
+
+// tuiView is a navigable list of resources of one kind.
+type tuiView struct {
+	name string
+	kind string
+	load func(client tuiClient) (*tuiTable, error)
+	// details loads the sections shown when drilling down into a resource.
+	// Views without details do not support drilling down.
+	details func(client tuiClient, id string) ([]tuiSection, error)
+	actions []tuiAction
+}
+
+// action returns the action bound to the key, if any.
+func (v *tuiView) action(key rune) (tuiAction, bool) {
+	for _, action := range v.actions {
+		if action.key == key {
+			return action, true
+		}
+	}
+	return tuiAction{}, false
+}
+
+// tuiRecentEventsCount is the number of recent events shown in the details
+// of an installation.
+const tuiRecentEventsCount = 20
+
+func newTUIViews() []*tuiView {
+	return []*tuiView{
+		{
+			name: "Clusters",
+			kind: "cluster",
+			load: func(client tuiClient) (*tuiTable, error) {
+				clusters, err := client.GetClusters(&model.GetClustersRequest{Paging: model.AllPagesNotDeleted()})
+				if err != nil {
+					return nil, errors.Wrap(err, "failed to query clusters")
+				}
+				return newTUITable(defaultClustersTableData(clusters)), nil
+			},
+			actions: []tuiAction{
+				{key: 'l', name: "Lock API of", run: func(client tuiClient, id string) error {
+					return client.LockAPIForCluster(id)
+				}},
+				{key: 'u', name: "Unlock API of", run: func(client tuiClient, id string) error {
+					return client.UnlockAPIForCluster(id)
+				}},
+			},
+		},
+		{
+			name: "Installations",
+			kind: "installation",
+			load: func(client tuiClient) (*tuiTable, error) {
+				installations, err := client.GetInstallations(&model.GetInstallationsRequest{Paging: model.AllPagesNotDeleted()})
+				if err != nil {
+					return nil, errors.Wrap(err, "failed to query installations")
+				}
+				return newTUITable(defaultInstallationTableData(installations)), nil
+			},
+			details: loadTUIInstallationDetails,
+			actions: []tuiAction{
+				{key: 'h', name: "Hibernate", run: func(client tuiClient, id string) error {
+					_, err := client.HibernateInstallation(id)
+					return err
+				}},
+				{key: 'w', name: "Wake up", run: func(client tuiClient, id string) error {
+					_, err := client.WakeupInstallation(id, &model.PatchInstallationRequest{})
+					return err
+				}},
+				{key: 'l', name: "Lock API of", run: func(client tuiClient, id string) error {
+					return client.LockAPIForInstallation(id)
+				}},
+				{key: 'u', name: "Unlock API of", run: func(client tuiClient, id string) error {
+					return client.UnlockAPIForInstallation(id)
+				}},
+				{key: 'b', name: "Back up", run: func(client tuiClient, id string) error {
+					_, err := client.CreateInstallationBackup(id)
+					return err
+				}},
+			},
+		},
+		{
+			name: "Groups",
+			kind: "group",
+			load: func(client tuiClient) (*tuiTable, error) {
+				groups, err := client.GetGroups(&model.GetGroupsRequest{Paging: model.AllPagesNotDeleted()})
+				if err != nil {
+					return nil, errors.Wrap(err, "failed to query groups")
+				}
+				return newTUITable(defaultGroupTableData(groups)), nil
+			},
+			actions: []tuiAction{
+				{key: 'l', name: "Lock API of", run: func(client tuiClient, id string) error {
+					return client.LockAPIForGroup(id)
+				}},
+				{key: 'u', name: "Unlock API of", run: func(client tuiClient, id string) error {
+					return client.UnlockAPIForGroup(id)
+				}},
+			},
+		},
+		{
+			name: "Operations",
+			kind: "operation",
+			load: loadTUIOperations,
+		},
+	}
+}
+
+// tuiOperation is a row of the operations view.
+type tuiOperation struct {
+	operationType  string
+	id             string
+	installationID string
+	state          string
+	requestAt      int64
+}
+
+// loadTUIOperations loads installation operations of all types, most recent
+// first.
+func loadTUIOperations(client tuiClient) (*tuiTable, error) {
+	paging := model.AllPagesNotDeleted()
+	var operations []tuiOperation
+
+	dbMigrations, err := client.GetInstallationDBMigrationOperations(&model.GetInstallationDBMigrationOperationsRequest{Paging: paging})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query database migration operations")
+	}
+	for _, op := range dbMigrations {
+		operations = append(operations, tuiOperation{"db-migration", op.ID, op.InstallationID, string(op.State), op.RequestAt})
+	}
+
+	filestoreMigrations, err := client.GetInstallationFilestoreMigrationOperations(&model.GetInstallationFilestoreMigrationOperationsRequest{Paging: paging})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query filestore migration operations")
+	}
+	for _, op := range filestoreMigrations {
+		operations = append(operations, tuiOperation{"filestore-migration", op.ID, op.InstallationID, string(op.State), op.RequestAt})
+	}
+
+	restorations, err := client.GetInstallationDBRestorationOperations(&model.GetInstallationDBRestorationOperationsRequest{Paging: paging})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query database restoration operations")
+	}
+	for _, op := range restorations {
+		operations = append(operations, tuiOperation{"db-restoration", op.ID, op.InstallationID, string(op.State), op.RequestAt})
+	}
+
+	sort.SliceStable(operations, func(i, j int) bool {
+		return operations[i].requestAt > operations[j].requestAt
+	})
+
+	keys := []string{"ID", "TYPE", "INSTALLATION ID", "STATE", "REQUEST AT"}
+	rows := make([][]string, 0, len(operations))
+	for _, op := range operations {
+		rows = append(rows, []string{op.id, op.operationType, op.installationID, op.state, model.DateStringFromMillis(op.requestAt)})
+	}
+
+	return newTUITable(keys, rows), nil
+}
+
+// loadTUIInstallationDetails loads the cluster installations, DNS records,
+// annotations, backups and recent events of an installation.
+func loadTUIInstallationDetails(client tuiClient, id string) ([]tuiSection, error) {
+	installation, err := client.GetInstallation(id, &model.GetInstallationRequest{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query installation")
+	}
+	if installation == nil {
+		return nil, errors.Errorf("installation %s not found", id)
+	}
+
+	clusterInstallations, err := client.GetClusterInstallations(&model.GetClusterInstallationsRequest{
+		InstallationID: id,
+		Paging:         model.AllPagesNotDeleted(),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query cluster installations")
+	}
+
+	backups, err := client.GetInstallationBackups(&model.GetInstallationBackupsRequest{
+		InstallationID: id,
+		Paging:         model.AllPagesNotDeleted(),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query backups")
+	}
+
+	events, err := client.ListStateChangeEvents(&model.ListStateChangeEventsRequest{
+		ResourceType: model.TypeInstallation,
+		ResourceID:   id,
+		Paging:       model.Paging{Page: 0, PerPage: tuiRecentEventsCount},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query events")
+	}
+
+	dnsRows := make([][]string, 0, len(installation.DNSRecords))
+	for _, record := range installation.DNSRecords {
+		dnsRows = append(dnsRows, []string{record.ID, record.DomainName, fmt.Sprintf("%t", record.IsPrimary)})
+	}
+
+	annotationRows := make([][]string, 0, len(installation.Annotations))
+	for _, annotation := range installation.Annotations {
+		annotationRows = append(annotationRows, []string{annotation.ID, annotation.Name})
+	}
+
+	return []tuiSection{
+		{title: "Installation", table: newTUITable(defaultInstallationTableData([]*model.InstallationDTO{installation}))},
+		{title: "Cluster Installations", table: newTUITable(defaultClusterInstallationTableData(clusterInstallations))},
+		{title: "DNS", table: newTUITable([]string{"ID", "DOMAIN NAME", "PRIMARY"}, dnsRows)},
+		{title: "Annotations", table: newTUITable([]string{"ID", "NAME"}, annotationRows)},
+		{title: "Backups", table: newTUITable(defaultBackupTableData(backups))},
+		{title: "Recent Events", table: newTUITable(defaultEventsTableData(events))},
+	}, nil
+}
@@ -0,0 +1,190 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package main
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockTUIClient serves fixed resources and records performed actions.
+type mockTUIClient struct {
+	tuiClient
+
+	installation *model.InstallationDTO
+	err          error
+	calls        []string
+}
+
+func (c *mockTUIClient) GetInstallations(request *model.GetInstallationsRequest) ([]*model.InstallationDTO, error) {
+	return []*model.InstallationDTO{c.installation}, c.err
+}
+
+func (c *mockTUIClient) GetInstallation(installationID string, request *model.GetInstallationRequest) (*model.InstallationDTO, error) {
+	if installationID != c.installation.ID {
+		return nil, nil
+	}
+	return c.installation, c.err
+}
+
+func (c *mockTUIClient) HibernateInstallation(installationID string) (*model.InstallationDTO, error) {
+	c.calls = append(c.calls, "hibernate "+installationID)
+	return c.installation, c.err
+}
+
+func (c *mockTUIClient) CreateInstallationBackup(installationID string) (*model.InstallationBackup, error) {
+	c.calls = append(c.calls, "backup "+installationID)
+	return &model.InstallationBackup{}, c.err
+}
+
+func (c *mockTUIClient) LockAPIForGroup(groupID string) error {
+	c.calls = append(c.calls, "lock group "+groupID)
+	return c.err
+}
+
+func (c *mockTUIClient) GetClusterInstallations(request *model.GetClusterInstallationsRequest) ([]*model.ClusterInstallation, error) {
+	return []*model.ClusterInstallation{
+		{ID: "ci1", ClusterID: "cluster1", InstallationID: request.InstallationID, State: model.ClusterInstallationStateStable},
+	}, nil
+}
+
+func (c *mockTUIClient) GetInstallationBackups(request *model.GetInstallationBackupsRequest) ([]*model.InstallationBackup, error) {
+	return nil, nil
+}
+
+func (c *mockTUIClient) ListStateChangeEvents(request *model.ListStateChangeEventsRequest) ([]*model.StateChangeEventData, error) {
+	if request.ResourceType != model.TypeInstallation || request.PerPage != tuiRecentEventsCount {
+		return nil, errors.New("unexpected events request")
+	}
+	return []*model.StateChangeEventData{
+		{
+			Event:       model.Event{ID: "event1"},
+			StateChange: model.StateChangeEvent{ResourceType: model.TypeInstallation, ResourceID: request.ResourceID, NewState: model.InstallationStateStable},
+		},
+	}, nil
+}
+
+func (c *mockTUIClient) GetInstallationDBMigrationOperations(request *model.GetInstallationDBMigrationOperationsRequest) ([]*model.InstallationDBMigrationOperation, error) {
+	return []*model.InstallationDBMigrationOperation{{ID: "migration1", InstallationID: "installation1", RequestAt: 100}}, nil
+}
+
+func (c *mockTUIClient) GetInstallationFilestoreMigrationOperations(request *model.GetInstallationFilestoreMigrationOperationsRequest) ([]*model.InstallationFilestoreMigrationOperation, error) {
+	return []*model.InstallationFilestoreMigrationOperation{{ID: "migration2", InstallationID: "installation1", RequestAt: 300}}, nil
+}
+
+func (c *mockTUIClient) GetInstallationDBRestorationOperations(request *model.GetInstallationDBRestorationOperationsRequest) ([]*model.InstallationDBRestorationOperation, error) {
+	return []*model.InstallationDBRestorationOperation{{ID: "restoration1", InstallationID: "installation2", RequestAt: 200}}, c.err
+}
+
+func newMockTUIClient() *mockTUIClient {
+	return &mockTUIClient{
+		installation: &model.InstallationDTO{
+			Installation: &model.Installation{ID: "installation1", State: model.InstallationStateStable},
+			DNSRecords:   []*model.InstallationDNS{{ID: "dns1", DomainName: "test.example.com", IsPrimary: true}},
+			Annotations:  []*model.Annotation{{ID: "annotation1", Name: "multi-tenant"}},
+		},
+	}
+}
+
+func getTUIView(t *testing.T, kind string) *tuiView {
+	for _, view := range newTUIViews() {
+		if view.kind == kind {
+			return view
+		}
+	}
+	require.Failf(t, "view not found", "no view of kind %s", kind)
+	return nil
+}
+
+func TestTUIInstallationView(t *testing.T) {
+	client := newMockTUIClient()
+	view := getTUIView(t, "installation")
+
+	t.Run("load", func(t *testing.T) {
+		table, err := view.load(client)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"installation1"}, table.ids)
+		assert.Len(t, table.rows, 1)
+		assert.Equal(t, "ID", table.keys[0])
+	})
+
+	t.Run("load error", func(t *testing.T) {
+		client := newMockTUIClient()
+		client.err = errors.New("unavailable")
+		_, err := view.load(client)
+		assert.Error(t, err)
+	})
+
+	t.Run("details", func(t *testing.T) {
+		sections, err := view.details(client, "installation1")
+		require.NoError(t, err)
+
+		titles := make([]string, 0, len(sections))
+		for _, section := range sections {
+			titles = append(titles, section.title)
+		}
+		assert.Equal(t, []string{"Installation", "Cluster Installations", "DNS", "Annotations", "Backups", "Recent Events"}, titles)
+		assert.Equal(t, []string{"ci1"}, sections[1].table.ids)
+		assert.Equal(t, [][]string{{"dns1", "test.example.com", "true"}}, sections[2].table.rows)
+		assert.Equal(t, [][]string{{"annotation1", "multi-tenant"}}, sections[3].table.rows)
+		assert.Empty(t, sections[4].table.rows)
+		assert.Equal(t, []string{"event1"}, sections[5].table.ids)
+	})
+
+	t.Run("details of missing installation", func(t *testing.T) {
+		_, err := view.details(client, "missing")
+		assert.Error(t, err)
+	})
+
+	t.Run("actions", func(t *testing.T) {
+		client := newMockTUIClient()
+
+		action, ok := view.action('h')
+		require.True(t, ok)
+		assert.Equal(t, "Hibernate installation installation1?", action.confirmation(view.kind, "installation1"))
+		require.NoError(t, action.run(client, "installation1"))
+
+		action, ok = view.action('b')
+		require.True(t, ok)
+		require.NoError(t, action.run(client, "installation1"))
+
+		assert.Equal(t, []string{"hibernate installation1", "backup installation1"}, client.calls)
+
+		_, ok = view.action('x')
+		assert.False(t, ok)
+	})
+}
+
+func TestTUIGroupView(t *testing.T) {
+	client := newMockTUIClient()
+	view := getTUIView(t, "group")
+	assert.Nil(t, view.details)
+
+	action, ok := view.action('l')
+	require.True(t, ok)
+	assert.Equal(t, "Lock API of group group1?", action.confirmation(view.kind, "group1"))
+	require.NoError(t, action.run(client, "group1"))
+	assert.Equal(t, []string{"lock group group1"}, client.calls)
+}
+
+func TestTUIOperationsView(t *testing.T) {
+	view := getTUIView(t, "operation")
+	assert.Empty(t, view.actions)
+
+	table, err := view.load(newMockTUIClient())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"migration2", "restoration1", "migration1"}, table.ids)
+	assert.Equal(t, "filestore-migration", table.rows[0][1])
+	assert.Equal(t, "installation2", table.rows[1][2])
+
+	client := newMockTUIClient()
+	client.err = errors.New("unavailable")
+	_, err = view.load(client)
+	assert.Error(t, err)
+}
@@ -7,10 +7,10 @@ package main
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 
 	"github.com/mattermost/mattermost-cloud/model"
-	"github.com/olekukonko/tablewriter"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
@@ -93,15 +93,17 @@ func newCmdWebhookGet() *cobra.Command {
 			command.SilenceUsage = true
 			client := createClient(command.Context(), flags.clusterFlags)
 
-			webhook, err := client.GetWebhook(flags.webhookID)
-			if err != nil {
-				return errors.Wrap(err, "failed to query webhook")
-			}
-			if webhook == nil {
-				return nil
-			}
+			return watchOutput(command.Context(), os.Stdout, flags.watchOptions, func(w io.Writer) error {
+				webhook, err := client.GetWebhook(flags.webhookID)
+				if err != nil {
+					return errors.Wrap(err, "failed to query webhook")
+				}
+				if webhook == nil {
+					return nil
+				}
 
-			return printJSON(webhook)
+				return webhookPrinter.printObject(w, flags.tableOptions, webhook)
+			})
 		},
 		PreRun: func(cmd *cobra.Command, args []string) {
 			flags.webhookFlags.addFlags(cmd)
@@ -137,28 +139,31 @@ func executeWebhookListCmd(ctx context.Context, flags webhookListFlag) error {
 	client := createClient(ctx, flags.clusterFlags)
 
 	paging := getPaging(flags.pagingFlags)
-	webhooks, err := client.GetWebhooks(&model.GetWebhooksRequest{
-		OwnerID: flags.owner,
-		Paging:  paging,
-	})
-	if err != nil {
-		return errors.Wrap(err, "failed to query webhooks")
-	}
 
-	if flags.outputToTable {
-		table := tablewriter.NewWriter(os.Stdout)
-		table.SetAlignment(tablewriter.ALIGN_LEFT)
-		table.SetHeader([]string{"ID", "OWNER", "URL", "HTTP HEADERS"})
-
-		for _, webhook := range webhooks {
-			table.Append([]string{webhook.ID, webhook.OwnerID, webhook.URL, fmt.Sprintf("%d", webhook.Headers.Count())})
+	return watchOutput(ctx, os.Stdout, flags.watchOptions, func(w io.Writer) error {
+		webhooks, err := client.GetWebhooks(&model.GetWebhooksRequest{
+			OwnerID: flags.owner,
+			Paging:  paging,
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to query webhooks")
 		}
-		table.Render()
 
-		return nil
-	}
+		return webhookPrinter.printList(w, flags.tableOptions, webhooks)
+	})
+}
 
-	return printJSON(webhooks)
+var webhookPrinter = resourcePrinter[*model.Webhook]{
+	defaultTable: defaultWebhookTableData,
+}
+
+func defaultWebhookTableData(webhooks []*model.Webhook) ([]string, [][]string) {
+	keys := []string{"ID", "OWNER", "URL", "HTTP HEADERS"}
+	vals := make([][]string, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		vals = append(vals, []string{webhook.ID, webhook.OwnerID, webhook.URL, fmt.Sprintf("%d", webhook.Headers.Count())})
+	}
+	return keys, vals
 }
 
 func newCmdWebhookDelete() *cobra.Command {
@@ -35,10 +35,14 @@ func (flags *webhookCreateFlag) addFlags(command *cobra.Command) {
 
 type webhookGetFlag struct {
 	webhookFlags
+	tableOptions
+	watchOptions
 	webhookID string
 }
 
 func (flags *webhookGetFlag) addFlags(command *cobra.Command) {
+	flags.tableOptions.addFlags(command)
+	flags.watchOptions.addFlags(command)
 	command.Flags().StringVar(&flags.webhookID, "webhook", "", "The id of the webhook to be fetched.")
 	_ = command.MarkFlagRequired("webhook")
 }
@@ -46,14 +50,16 @@ func (flags *webhookGetFlag) addFlags(command *cobra.Command) {
 type webhookListFlag struct {
 	webhookFlags
 	pagingFlags
-	owner         string
-	outputToTable bool
+	tableOptions
+	watchOptions
+	owner string
 }
 
 func (flags *webhookListFlag) addFlags(command *cobra.Command) {
 	flags.pagingFlags.addFlags(command)
+	flags.tableOptions.addFlags(command)
+	flags.watchOptions.addFlags(command)
 	command.Flags().StringVar(&flags.owner, "owner", "", "The owner by which to filter webhooks.")
-	command.Flags().BoolVar(&flags.outputToTable, "table", false, "Whether to display the returned webhook list in a table or not")
 }
 
 type webhookDeleteFlag struct {
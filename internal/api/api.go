@@ -20,6 +20,8 @@ func Register(rootRouter *mux.Router, context *Context) {
 	}
 	apiRouter.Use(authMiddleware)
 	initCluster(apiRouter, context)
+	initClusterTemplate(apiRouter, context)
+	initSizeProfile(apiRouter, context)
 	initInstallation(apiRouter, context)
 	initClusterInstallation(apiRouter, context)
 	initGroup(apiRouter, context)
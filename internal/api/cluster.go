@@ -5,6 +5,8 @@
 package api
 
 import (
+	"bytes"
+	"io"
 	"net/http"
 
 	"github.com/gorilla/mux"
@@ -23,6 +25,9 @@ func initCluster(apiRouter *mux.Router, context *Context) {
 	clustersRouter.Handle("", addContext(handleGetClusters)).Methods("GET")
 	clustersRouter.Handle("", addContext(handleCreateCluster)).Methods("POST")
 	clustersRouter.Handle("/import", addContext(handleImportCluster)).Methods("POST")
+	clustersRouter.Handle("/utilities/drift", addContext(handleGetUtilityDrift)).Methods("GET")
+	clustersRouter.Handle("/utilities/rollout", addContext(handleCreateUtilityRollout)).Methods("POST")
+	clustersRouter.Handle("/utilities/rollouts", addContext(handleGetUtilityRollouts)).Methods("GET")
 
 	clusterRouter := apiRouter.PathPrefix("/cluster/{cluster:[A-Za-z0-9]{26}}").Subrouter()
 	clusterRouter.Handle("", addContext(handleGetCluster)).Methods("GET")
@@ -30,13 +35,24 @@ func initCluster(apiRouter *mux.Router, context *Context) {
 	clusterRouter.Handle("", addContext(handleUpdateClusterConfiguration)).Methods("PUT")
 	clusterRouter.Handle("/provision", addContext(handleProvisionCluster)).Methods("POST")
 	clusterRouter.Handle("/kubernetes", addContext(handleUpgradeKubernetes)).Methods("PUT")
+	clusterRouter.Handle("/kubernetes/upgrades", addContext(handleGetClusterUpgrades)).Methods("GET")
+	clusterRouter.Handle("/kubernetes/upgrade/resume", addContext(handleResumeClusterUpgrade)).Methods("POST")
 	clusterRouter.Handle("/size", addContext(handleResizeCluster)).Methods("PUT")
 	clusterRouter.Handle("/utilities", addContext(handleGetAllUtilityMetadata)).Methods("GET")
+	clusterRouter.Handle("/utilities", addContext(handleAddClusterUtility)).Methods("POST")
+	clusterRouter.Handle("/utilities/registry", addContext(handleGetClusterUtilityRegistry)).Methods("GET")
+	clusterRouter.Handle("/utility/{utility-name}", addContext(handleRemoveClusterUtility)).Methods("DELETE")
 	clusterRouter.Handle("/annotations", addContext(handleAddClusterAnnotations)).Methods("POST")
 	clusterRouter.Handle("/annotation/{annotation-name}", addContext(handleDeleteClusterAnnotation)).Methods("DELETE")
 	clusterRouter.Handle("/nodegroups", addContext(handleCreateNodegroups)).Methods("POST")
+	clusterRouter.Handle("/nodegroup/{nodegroup}", addContext(handlePatchNodegroup)).Methods("PATCH")
 	clusterRouter.Handle("/nodegroup/{nodegroup}", addContext(handleDeleteNodegroup)).Methods("DELETE")
 	clusterRouter.Handle("", addContext(handleDeleteCluster)).Methods("DELETE")
+
+	utilityRolloutRouter := apiRouter.PathPrefix("/utility_rollout/{rollout:[A-Za-z0-9]{26}}").Subrouter()
+	utilityRolloutRouter.Handle("", addContext(handleGetUtilityRollout)).Methods("GET")
+	utilityRolloutRouter.Handle("/resume", addContext(handleResumeUtilityRollout)).Methods("POST")
+	utilityRolloutRouter.Handle("/cancel", addContext(handleCancelUtilityRollout)).Methods("POST")
 }
 
 // handleGetCluster responds to GET /api/cluster/{cluster}, returning the cluster in question.
@@ -92,13 +108,52 @@ func handleGetClusters(c *Context, w http.ResponseWriter, r *http.Request) {
 // handleCreateCluster responds to POST /api/clusters, beginning the process of
 // creating a new cluster.
 func handleCreateCluster(c *Context, w http.ResponseWriter, r *http.Request) {
-	createClusterRequest, err := model.NewCreateClusterRequestFromReader(r.Body)
+	// The raw request is kept so that a template is only overridden by the
+	// values present in the request, including false and zero values.
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to read request")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	createClusterRequest, err := model.DecodeCreateClusterRequest(bytes.NewReader(body))
 	if err != nil {
 		c.Logger.WithError(err).Error("failed to decode request")
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
 
+	var template *model.ClusterTemplate
+	if len(createClusterRequest.Template) != 0 {
+		template, err = c.Store.GetClusterTemplateByName(createClusterRequest.Template)
+		if err != nil {
+			c.Logger.WithError(err).Error("failed to query cluster template")
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if template == nil {
+			c.Logger.Errorf("cluster template %s not found", createClusterRequest.Template)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		err = createClusterRequest.ApplyTemplate(template, body)
+		if err != nil {
+			c.Logger.WithError(err).Error("failed to apply cluster template")
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+	}
+
+	createClusterRequest.SetDefaults()
+	err = createClusterRequest.Validate()
+	if err != nil {
+		c.Logger.WithError(err).Error("create cluster request failed validation")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
 	cluster := model.Cluster{
 		Provider: createClusterRequest.Provider,
 		ProviderMetadataAWS: &model.AWSMetadata{
@@ -110,6 +165,10 @@ func handleCreateCluster(c *Context, w http.ResponseWriter, r *http.Request) {
 		APISecurityLock:    createClusterRequest.APISecurityLock,
 		State:              model.ClusterStateCreationRequested,
 	}
+	if template != nil {
+		cluster.TemplateID = template.ID
+		cluster.TemplateVersion = template.Version
+	}
 
 	if createClusterRequest.Provisioner == model.ProvisionerEKS {
 		cluster.ProvisionerMetadataEKS = &model.EKSMetadata{}
@@ -401,6 +460,13 @@ func handleUpgradeKubernetes(c *Context, w http.ResponseWriter, r *http.Request)
 			return
 		}
 
+		err = startClusterUpgrade(c, clusterDTO.Cluster, upgradeClusterRequest.SkipPreflight)
+		if err != nil {
+			c.Logger.WithError(err).Error("failed to start cluster upgrade")
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
 		if oldState != newState {
 			err = c.EventProducer.ProduceClusterStateChangeEvent(clusterDTO.Cluster, oldState)
 			if err != nil {
@@ -618,6 +684,82 @@ func handleDeleteNodegroup(c *Context, w http.ResponseWriter, r *http.Request) {
 	outputJSON(c, w, clusterDTO)
 }
 
+// handlePatchNodegroup responds to PATCH /api/cluster/{cluster}/nodegroup/{nodegroup},
+// changing the requested nodegroup in place.
+func handlePatchNodegroup(c *Context, w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	clusterID := vars["cluster"]
+	nodegroup := vars["nodegroup"]
+	c.Logger = c.Logger.WithField("cluster", clusterID).WithField("nodegroup", nodegroup)
+
+	patchNodegroupRequest, err := model.NewPatchNodegroupRequestFromReader(r.Body)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to decode request")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	newState := model.ClusterStateResizeRequested
+
+	clusterDTO, status, unlockOnce := getClusterForTransition(c, clusterID, newState)
+	if status != 0 {
+		c.Logger.Debug("Cluster is not in a valid state for nodegroup patch")
+		w.WriteHeader(status)
+		return
+	}
+	defer unlockOnce()
+
+	if clusterDTO.Provisioner != model.ProvisionerEKS {
+		c.Logger.Debugf("Patching nodegroup for %s cluster is not supported", clusterDTO.Provisioner)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	err = clusterDTO.ProvisionerMetadataEKS.ValidateNodegroupPatch(nodegroup, patchNodegroupRequest)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to validate nodegroup patch request")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	// Autoscaling policy changes are applied directly, so the cluster only
+	// needs to be resized when the nodegroup itself changes.
+	resizeRequired := clusterDTO.ProvisionerMetadataEKS.ApplyNodegroupPatch(nodegroup, patchNodegroupRequest)
+
+	oldState := clusterDTO.State
+	if resizeRequired {
+		clusterDTO.State = newState
+	}
+
+	err = c.Store.UpdateCluster(clusterDTO.Cluster)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to update cluster")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	if oldState != clusterDTO.State {
+		err = c.EventProducer.ProduceClusterStateChangeEvent(clusterDTO.Cluster, oldState)
+		if err != nil {
+			c.Logger.WithError(err).Error("failed to create cluster state change event")
+		}
+	}
+
+	unlockOnce()
+
+	w.Header().Set("Content-Type", "application/json")
+	if !resizeRequired {
+		w.WriteHeader(http.StatusOK)
+		outputJSON(c, w, clusterDTO)
+		return
+	}
+
+	_ = c.Supervisor.Do()
+
+	w.WriteHeader(http.StatusAccepted)
+	outputJSON(c, w, clusterDTO)
+}
+
 // handleDeleteCluster responds to DELETE /api/cluster/{cluster}, beginning the process of
 // deleting the cluster.
 func handleDeleteCluster(c *Context, w http.ResponseWriter, r *http.Request) {
@@ -725,6 +867,126 @@ func handleGetAllUtilityMetadata(c *Context, w http.ResponseWriter, r *http.Requ
 	outputJSON(c, w, cluster.UtilityMetadata)
 }
 
+// handleGetClusterUtilityRegistry responds to GET /api/cluster/{cluster}/utilities/registry,
+// returning the utility registry of the cluster with the utility versions.
+func handleGetClusterUtilityRegistry(c *Context, w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	clusterID := vars["cluster"]
+	c.Logger = c.Logger.WithField("cluster", clusterID).WithField("action", "get-utility-registry")
+
+	cluster, err := c.Store.GetCluster(clusterID)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query cluster")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if cluster == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	utilities, err := cluster.ClusterUtilities()
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to get cluster utility registry")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, utilities)
+}
+
+// handleAddClusterUtility responds to POST /api/cluster/{cluster}/utilities,
+// registering a new utility for the cluster and provisioning the cluster.
+func handleAddClusterUtility(c *Context, w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	clusterID := vars["cluster"]
+	c.Logger = c.Logger.WithField("cluster", clusterID).WithField("action", "add-utility")
+
+	addUtilityRequest, err := model.NewAddClusterUtilityRequestFromReader(r.Body)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to decode request")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	updateClusterUtilities(c, w, clusterID, func(cluster *model.ClusterDTO) (int, error) {
+		err := cluster.AddUtility(addUtilityRequest)
+		if err != nil {
+			return http.StatusBadRequest, errors.Wrap(err, "failed to add utility")
+		}
+		return 0, nil
+	})
+}
+
+// handleRemoveClusterUtility responds to DELETE /api/cluster/{cluster}/utility/{utility-name},
+// removing the utility from the registry of the cluster and provisioning the cluster.
+func handleRemoveClusterUtility(c *Context, w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	clusterID := vars["cluster"]
+	utilityName := vars["utility-name"]
+	c.Logger = c.Logger.
+		WithField("cluster", clusterID).
+		WithField("action", "remove-utility").
+		WithField("utility-name", utilityName)
+
+	updateClusterUtilities(c, w, clusterID, func(cluster *model.ClusterDTO) (int, error) {
+		err := cluster.RemoveUtility(utilityName)
+		if errors.Is(err, model.ErrUtilityNotFound) {
+			return http.StatusNotFound, err
+		}
+		if err != nil {
+			return http.StatusBadRequest, errors.Wrap(err, "failed to remove utility")
+		}
+		return 0, nil
+	})
+}
+
+// updateClusterUtilities applies a change to the utility registry of the
+// cluster and requests the cluster to be provisioned to reconcile it.
+func updateClusterUtilities(c *Context, w http.ResponseWriter, clusterID string, update func(cluster *model.ClusterDTO) (int, error)) {
+	newState := model.ClusterStateProvisioningRequested
+
+	clusterDTO, status, unlockOnce := getClusterForTransition(c, clusterID, newState)
+	if status != 0 {
+		w.WriteHeader(status)
+		return
+	}
+	defer unlockOnce()
+
+	status, err := update(clusterDTO)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to update cluster utilities")
+		w.WriteHeader(status)
+		return
+	}
+
+	oldState := clusterDTO.State
+	clusterDTO.State = newState
+
+	err = c.Store.UpdateCluster(clusterDTO.Cluster)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to update cluster")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	if oldState != newState {
+		err = c.EventProducer.ProduceClusterStateChangeEvent(clusterDTO.Cluster, oldState)
+		if err != nil {
+			c.Logger.WithError(err).Error("Failed to create cluster state change event")
+		}
+	}
+
+	unlockOnce()
+	c.Supervisor.Do()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	outputJSON(c, w, clusterDTO)
+}
+
 // handleAddClusterAnnotations responds to POST /api/cluster/{cluster}/annotations,
 // adds the set of annotations to the Cluster.
 func handleAddClusterAnnotations(c *Context, w http.ResponseWriter, r *http.Request) {
@@ -760,6 +1022,8 @@ func handleAddClusterAnnotations(c *Context, w http.ResponseWriter, r *http.Requ
 	}
 
 	clusterDTO.Annotations = append(clusterDTO.Annotations, annotations...)
+	produceAnnotationChangeEvent(c, model.TypeCluster, clusterID, clusterDTO.State,
+		&model.AnnotationChangeEventData{Added: model.GetAnnotationsNames(annotations)})
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
@@ -800,6 +1064,8 @@ func handleDeleteClusterAnnotation(c *Context, w http.ResponseWriter, r *http.Re
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
+	produceAnnotationChangeEvent(c, model.TypeCluster, clusterID, clusterDTO.State,
+		&model.AnnotationChangeEventData{Removed: []string{annotationName}})
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusNoContent)
@@ -820,6 +1086,13 @@ func annotationsFromRequest(req *http.Request) ([]*model.Annotation, error) {
 	return annotations, nil
 }
 
+func produceAnnotationChangeEvent(c *Context, resourceType model.ResourceType, resourceID, state string, data *model.AnnotationChangeEventData) {
+	err := c.EventProducer.ProduceAnnotationChangeEvent(resourceType, resourceID, state, data)
+	if err != nil {
+		c.Logger.WithError(err).Error("Failed to create annotation change event")
+	}
+}
+
 // getClusterForTransition locks the cluster and validates if it can be transitioned to desired state.
 func getClusterForTransition(c *Context, clusterID, newState string) (*model.ClusterDTO, int, func()) {
 	clusterDTO, status, unlockOnce := lockCluster(c, clusterID)
@@ -0,0 +1,216 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost-cloud/model"
+)
+
+// initClusterTemplate registers cluster template endpoints on the given router.
+func initClusterTemplate(apiRouter *mux.Router, context *Context) {
+	addContext := func(handler contextHandlerFunc) *contextHandler {
+		return newContextHandler(context, handler)
+	}
+
+	templatesRouter := apiRouter.PathPrefix("/cluster_templates").Subrouter()
+	templatesRouter.Handle("", addContext(handleGetClusterTemplates)).Methods("GET")
+	templatesRouter.Handle("", addContext(handleCreateClusterTemplate)).Methods("POST")
+
+	templateRouter := apiRouter.PathPrefix("/cluster_template/{template}").Subrouter()
+	templateRouter.Handle("", addContext(handleGetClusterTemplate)).Methods("GET")
+	templateRouter.Handle("", addContext(handleUpdateClusterTemplate)).Methods("PUT")
+	templateRouter.Handle("", addContext(handleDeleteClusterTemplate)).Methods("DELETE")
+	templateRouter.Handle("/clusters", addContext(handleGetClusterTemplateDivergence)).Methods("GET")
+}
+
+// handleGetClusterTemplates responds to GET /api/cluster_templates, returning
+// a list of cluster templates.
+func handleGetClusterTemplates(c *Context, w http.ResponseWriter, r *http.Request) {
+	paging, err := parsePaging(r.URL)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to parse paging parameters")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	templates, err := c.Store.GetClusterTemplates(&model.ClusterTemplateFilter{Paging: paging})
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query cluster templates")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, templates)
+}
+
+// handleCreateClusterTemplate responds to POST /api/cluster_templates,
+// creating a new cluster template.
+func handleCreateClusterTemplate(c *Context, w http.ResponseWriter, r *http.Request) {
+	createRequest, err := model.NewCreateClusterTemplateRequestFromReader(r.Body)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to decode request")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	c.Logger = c.Logger.WithField("template", createRequest.Name)
+
+	existing, err := c.Store.GetClusterTemplateByName(createRequest.Name)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query cluster template")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if existing != nil {
+		c.Logger.Error("cluster template with the given name already exists")
+		w.WriteHeader(http.StatusConflict)
+		return
+	}
+
+	template := &model.ClusterTemplate{
+		Name:        createRequest.Name,
+		Description: createRequest.Description,
+		Spec:        createRequest.Spec,
+	}
+
+	err = c.Store.CreateClusterTemplate(template)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to create cluster template")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, template)
+}
+
+// handleGetClusterTemplate responds to GET /api/cluster_template/{template},
+// returning the cluster template in question.
+func handleGetClusterTemplate(c *Context, w http.ResponseWriter, r *http.Request) {
+	template, status := getClusterTemplate(c, r)
+	if status != 0 {
+		w.WriteHeader(status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, template)
+}
+
+// handleUpdateClusterTemplate responds to PUT /api/cluster_template/{template},
+// updating the cluster template. Changing the spec increments the version of
+// the template.
+func handleUpdateClusterTemplate(c *Context, w http.ResponseWriter, r *http.Request) {
+	template, status := getClusterTemplate(c, r)
+	if status != 0 {
+		w.WriteHeader(status)
+		return
+	}
+
+	patchRequest, err := model.NewPatchClusterTemplateRequestFromReader(r.Body)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to decode request")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if patchRequest.Apply(template) {
+		err = c.Store.UpdateClusterTemplate(template)
+		if err != nil {
+			c.Logger.WithError(err).Error("failed to update cluster template")
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, template)
+}
+
+// handleDeleteClusterTemplate responds to DELETE /api/cluster_template/{template},
+// marking the cluster template as deleted. Clusters created from the template
+// are not affected.
+func handleDeleteClusterTemplate(c *Context, w http.ResponseWriter, r *http.Request) {
+	template, status := getClusterTemplate(c, r)
+	if status != 0 {
+		w.WriteHeader(status)
+		return
+	}
+
+	err := c.Store.DeleteClusterTemplate(template.ID)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to delete cluster template")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleGetClusterTemplateDivergence responds to GET
+// /api/cluster_template/{template}/clusters, returning how each cluster
+// created from the template differs from its current version.
+func handleGetClusterTemplateDivergence(c *Context, w http.ResponseWriter, r *http.Request) {
+	onlyDiverged, err := parseBool(r.URL, "only_diverged", false)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to parse request parameters")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	template, status := getClusterTemplate(c, r)
+	if status != 0 {
+		w.WriteHeader(status)
+		return
+	}
+
+	clusters, err := c.Store.GetClusters(&model.ClusterFilter{
+		Paging:     model.AllPagesNotDeleted(),
+		TemplateID: template.ID,
+	})
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query clusters")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	divergences := []*model.ClusterTemplateDivergence{}
+	for _, cluster := range clusters {
+		divergence := template.Divergence(cluster)
+		if divergence == nil || (onlyDiverged && !divergence.IsDiverged()) {
+			continue
+		}
+		divergences = append(divergences, divergence)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, divergences)
+}
+
+// getClusterTemplate fetches the cluster template named in the request path,
+// returning a non-zero status code if it could not be found.
+func getClusterTemplate(c *Context, r *http.Request) (*model.ClusterTemplate, int) {
+	name := mux.Vars(r)["template"]
+	c.Logger = c.Logger.WithField("template", name)
+
+	template, err := c.Store.GetClusterTemplateByName(name)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query cluster template")
+		return nil, http.StatusInternalServerError
+	}
+	if template == nil {
+		return nil, http.StatusNotFound
+	}
+
+	return template, 0
+}
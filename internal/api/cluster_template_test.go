@@ -0,0 +1,233 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package api_test
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost-cloud/internal/api"
+	"github.com/mattermost/mattermost-cloud/internal/store"
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/internal/testutil"
+	"github.com/mattermost/mattermost-cloud/internal/util"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClusterTemplates(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	defer store.CloseConnection(t, sqlStore)
+
+	router := mux.NewRouter()
+	api.Register(router, &api.Context{
+		Store:         sqlStore,
+		Supervisor:    &mockSupervisor{},
+		EventProducer: testutil.SetupTestEventsProducer(sqlStore, logger),
+		Metrics:       &mockMetrics{},
+		Logger:        logger,
+		Provisioner:   &mockProvisioner{},
+	})
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	client := model.NewClient(ts.URL)
+
+	spec := &model.ClusterTemplateSpec{
+		Provider:           model.ProviderAWS,
+		Zones:              []string{"zone"},
+		NodeInstanceType:   "m6i.large",
+		NodeMinCount:       3,
+		NodeMaxCount:       3,
+		AllowInstallations: true,
+		Annotations:        []string{"team-sre"},
+	}
+
+	t.Run("no templates", func(t *testing.T) {
+		templates, err := client.GetClusterTemplates(&model.GetClusterTemplatesRequest{Paging: model.AllPagesNotDeleted()})
+		require.NoError(t, err)
+		assert.Empty(t, templates)
+	})
+
+	var template *model.ClusterTemplate
+	t.Run("create", func(t *testing.T) {
+		var err error
+		template, err = client.CreateClusterTemplate(&model.CreateClusterTemplateRequest{
+			Name:        "standard",
+			Description: "standard clusters",
+			Spec:        spec,
+		})
+		require.NoError(t, err)
+		assert.NotEmpty(t, template.ID)
+		assert.Equal(t, int64(1), template.Version)
+		assert.Equal(t, spec, template.Spec)
+	})
+
+	t.Run("invalid create requests", func(t *testing.T) {
+		_, err := client.CreateClusterTemplate(&model.CreateClusterTemplateRequest{Name: "standard", Spec: spec})
+		require.EqualError(t, err, "failed with status code 409")
+
+		_, err = client.CreateClusterTemplate(&model.CreateClusterTemplateRequest{Name: "Invalid Name", Spec: spec})
+		require.EqualError(t, err, "failed with status code 400")
+
+		_, err = client.CreateClusterTemplate(&model.CreateClusterTemplateRequest{Name: "nospec"})
+		require.EqualError(t, err, "failed with status code 400")
+
+		_, err = client.CreateClusterTemplate(&model.CreateClusterTemplateRequest{
+			Name: "nested",
+			Spec: &model.ClusterTemplateSpec{Template: "standard"},
+		})
+		require.EqualError(t, err, "failed with status code 400")
+
+		resp, err := http.Post(fmt.Sprintf("%s/api/cluster_templates", ts.URL), "application/json", bytes.NewReader([]byte("invalid")))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("get", func(t *testing.T) {
+		fetchedTemplate, err := client.GetClusterTemplate(template.Name)
+		require.NoError(t, err)
+		assert.Equal(t, template, fetchedTemplate)
+
+		templates, err := client.GetClusterTemplates(&model.GetClusterTemplatesRequest{Paging: model.AllPagesNotDeleted()})
+		require.NoError(t, err)
+		assert.Equal(t, []*model.ClusterTemplate{template}, templates)
+	})
+
+	t.Run("get unknown template", func(t *testing.T) {
+		fetchedTemplate, err := client.GetClusterTemplate("unknown")
+		require.NoError(t, err)
+		assert.Nil(t, fetchedTemplate)
+	})
+
+	var cluster1 *model.ClusterDTO
+	t.Run("create cluster from template", func(t *testing.T) {
+		var err error
+		cluster1, err = client.CreateCluster(&model.CreateClusterRequest{Template: template.Name})
+		require.NoError(t, err)
+		assert.Equal(t, template.ID, cluster1.TemplateID)
+		assert.Equal(t, template.Version, cluster1.TemplateVersion)
+		assert.True(t, cluster1.AllowInstallations)
+		assert.Equal(t, "m6i.large", cluster1.ProvisionerMetadataKops.NodeInstanceType)
+	})
+
+	t.Run("create cluster overriding template values with false", func(t *testing.T) {
+		resp, err := http.Post(fmt.Sprintf("%s/api/clusters", ts.URL), "application/json", bytes.NewReader([]byte(
+			`{"template": "standard", "allow-installations": false}`,
+		)))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusAccepted, resp.StatusCode)
+
+		cluster, err := model.DTOFromReader[model.ClusterDTO](resp.Body)
+		require.NoError(t, err)
+		assert.False(t, cluster.AllowInstallations)
+		assert.Equal(t, template.ID, cluster.TemplateID)
+	})
+
+	t.Run("create cluster with client overriding template values with false", func(t *testing.T) {
+		cluster, err := client.CreateCluster(&model.CreateClusterRequest{
+			Template:           template.Name,
+			AllowInstallations: false,
+			TemplateOverrides:  []string{"allow-installations"},
+		})
+		require.NoError(t, err)
+		assert.False(t, cluster.AllowInstallations)
+		assert.Equal(t, template.ID, cluster.TemplateID)
+
+		cluster, err = client.CreateCluster(&model.CreateClusterRequest{
+			Template:           template.Name,
+			AllowInstallations: false,
+		})
+		require.NoError(t, err)
+		assert.True(t, cluster.AllowInstallations)
+	})
+
+	t.Run("create cluster with template overrides but no template", func(t *testing.T) {
+		_, err := client.CreateCluster(&model.CreateClusterRequest{
+			Provider:          model.ProviderAWS,
+			Zones:             []string{"zone"},
+			TemplateOverrides: []string{"allow-installations"},
+		})
+		require.EqualError(t, err, "failed with status code 400")
+	})
+
+	t.Run("create cluster from unknown template", func(t *testing.T) {
+		_, err := client.CreateCluster(&model.CreateClusterRequest{Template: "unknown"})
+		require.EqualError(t, err, "failed with status code 400")
+	})
+
+	t.Run("update", func(t *testing.T) {
+		description := "updated"
+		updatedTemplate, err := client.UpdateClusterTemplate(template.Name, &model.PatchClusterTemplateRequest{Description: &description})
+		require.NoError(t, err)
+		assert.Equal(t, description, updatedTemplate.Description)
+		assert.Equal(t, int64(1), updatedTemplate.Version)
+
+		updatedSpec := *spec
+		updatedSpec.NodeInstanceType = "m6i.xlarge"
+		updatedTemplate, err = client.UpdateClusterTemplate(template.Name, &model.PatchClusterTemplateRequest{Spec: &updatedSpec})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), updatedTemplate.Version)
+		assert.Equal(t, "m6i.xlarge", updatedTemplate.Spec.NodeInstanceType)
+		template = updatedTemplate
+	})
+
+	t.Run("invalid update requests", func(t *testing.T) {
+		_, err := client.UpdateClusterTemplate("unknown", &model.PatchClusterTemplateRequest{Description: util.SToP("updated")})
+		require.EqualError(t, err, "failed with status code 404")
+
+		_, err = client.UpdateClusterTemplate(template.Name, &model.PatchClusterTemplateRequest{})
+		require.EqualError(t, err, "failed with status code 400")
+
+		_, err = client.UpdateClusterTemplate(template.Name, &model.PatchClusterTemplateRequest{Spec: &model.ClusterTemplateSpec{Provider: "azure"}})
+		require.EqualError(t, err, "failed with status code 400")
+	})
+
+	t.Run("divergence", func(t *testing.T) {
+		divergences, err := client.GetClusterTemplateDivergence(template.Name, &model.GetClusterTemplateDivergenceRequest{})
+		require.NoError(t, err)
+		require.Len(t, divergences, 4)
+		for _, divergence := range divergences {
+			assert.Equal(t, int64(1), divergence.TemplateVersion)
+			assert.Equal(t, int64(2), divergence.CurrentVersion)
+			assert.True(t, divergence.IsDiverged())
+		}
+
+		divergences, err = client.GetClusterTemplateDivergence(template.Name, &model.GetClusterTemplateDivergenceRequest{OnlyDiverged: true})
+		require.NoError(t, err)
+		assert.Len(t, divergences, 4)
+	})
+
+	t.Run("invalid divergence requests", func(t *testing.T) {
+		_, err := client.GetClusterTemplateDivergence("unknown", &model.GetClusterTemplateDivergenceRequest{})
+		require.EqualError(t, err, "failed with status code 404")
+
+		resp, err := http.Get(fmt.Sprintf("%s/api/cluster_template/%s/clusters?only_diverged=invalid", ts.URL, template.Name))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		err := client.DeleteClusterTemplate(template.Name)
+		require.NoError(t, err)
+
+		fetchedTemplate, err := client.GetClusterTemplate(template.Name)
+		require.NoError(t, err)
+		assert.Nil(t, fetchedTemplate)
+
+		err = client.DeleteClusterTemplate(template.Name)
+		require.EqualError(t, err, "failed with status code 404")
+
+		cluster, err := client.GetCluster(cluster1.ID)
+		require.NoError(t, err)
+		assert.Equal(t, template.ID, cluster.TemplateID)
+	})
+}
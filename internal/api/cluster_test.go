@@ -1299,6 +1299,132 @@ func TestGetAllUtilityMetadata(t *testing.T) {
 	assert.Equal(t, model.DefaultUtilityVersions[model.FluentbitCanonicalName], utilityMetadata.DesiredVersions.Fluentbit)
 }
 
+func TestClusterUtilityRegistry(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	defer store.CloseConnection(t, sqlStore)
+
+	router := mux.NewRouter()
+	api.Register(router, &api.Context{
+		Store:         sqlStore,
+		Supervisor:    &mockSupervisor{},
+		EventProducer: testutil.SetupTestEventsProducer(sqlStore, logger),
+		Metrics:       &mockMetrics{},
+		Logger:        logger,
+		Provisioner:   &mockProvisioner{},
+	})
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	client := model.NewClient(ts.URL)
+
+	cluster, err := client.CreateCluster(&model.CreateClusterRequest{
+		Provider: model.ProviderAWS,
+		Zones:    []string{"zone"},
+	})
+	require.NoError(t, err)
+
+	setStable := func(t *testing.T) {
+		cluster.State = model.ClusterStateStable
+		err := sqlStore.UpdateCluster(cluster.Cluster)
+		require.NoError(t, err)
+	}
+
+	request := &model.AddClusterUtilityRequest{
+		Name:       "cert-manager",
+		Repo:       "jetstack",
+		RepoURL:    "https://charts.jetstack.io",
+		Chart:      "cert-manager",
+		Namespace:  "cert-manager",
+		Version:    "1.14.4",
+		ValuesPath: "cert_manager_values.yaml",
+	}
+
+	t.Run("builtin registry", func(t *testing.T) {
+		utilities, err := client.GetClusterUtilityRegistry(cluster.ID)
+		require.NoError(t, err)
+		require.Len(t, utilities, len(model.BuiltinUtilities))
+		assert.Equal(t, model.PgbouncerCanonicalName, utilities[0].Name)
+		assert.True(t, utilities[0].Builtin)
+	})
+
+	t.Run("unknown cluster", func(t *testing.T) {
+		_, err := client.GetClusterUtilityRegistry(model.NewID())
+		require.EqualError(t, err, "failed with status code 404")
+
+		_, err = client.AddClusterUtility(model.NewID(), request)
+		require.EqualError(t, err, "failed with status code 404")
+	})
+
+	t.Run("while provisioning", func(t *testing.T) {
+		cluster.State = model.ClusterStateCreationInProgress
+		err := sqlStore.UpdateCluster(cluster.Cluster)
+		require.NoError(t, err)
+
+		_, err = client.AddClusterUtility(cluster.ID, request)
+		require.EqualError(t, err, "failed with status code 400")
+	})
+
+	t.Run("invalid request", func(t *testing.T) {
+		setStable(t)
+
+		_, err := client.AddClusterUtility(cluster.ID, &model.AddClusterUtilityRequest{Name: "cert-manager"})
+		require.EqualError(t, err, "failed with status code 400")
+	})
+
+	t.Run("add utility", func(t *testing.T) {
+		setStable(t)
+
+		clusterResp, err := client.AddClusterUtility(cluster.ID, request)
+		require.NoError(t, err)
+		assert.Equal(t, model.ClusterStateProvisioningRequested, clusterResp.State)
+
+		utilities, err := client.GetClusterUtilityRegistry(cluster.ID)
+		require.NoError(t, err)
+		require.Len(t, utilities, len(model.BuiltinUtilities)+1)
+		added := utilities[len(utilities)-1]
+		assert.Equal(t, "cert-manager", added.Name)
+		assert.Equal(t, "cert-manager", added.Release)
+		assert.False(t, added.Builtin)
+		assert.Equal(t, &model.HelmUtilityVersion{Chart: "1.14.4", ValuesPath: "cert_manager_values.yaml"}, added.DesiredVersion)
+	})
+
+	t.Run("add duplicate utility", func(t *testing.T) {
+		setStable(t)
+
+		_, err := client.AddClusterUtility(cluster.ID, request)
+		require.EqualError(t, err, "failed with status code 400")
+	})
+
+	t.Run("remove builtin utility", func(t *testing.T) {
+		setStable(t)
+
+		_, err := client.RemoveClusterUtility(cluster.ID, model.NginxCanonicalName)
+		require.EqualError(t, err, "failed with status code 400")
+	})
+
+	t.Run("remove unknown utility", func(t *testing.T) {
+		setStable(t)
+
+		_, err := client.RemoveClusterUtility(cluster.ID, "external-dns")
+		require.EqualError(t, err, "failed with status code 404")
+	})
+
+	t.Run("remove utility", func(t *testing.T) {
+		setStable(t)
+
+		clusterResp, err := client.RemoveClusterUtility(cluster.ID, "cert-manager")
+		require.NoError(t, err)
+		assert.Equal(t, model.ClusterStateProvisioningRequested, clusterResp.State)
+		require.Len(t, clusterResp.UtilityMetadata.RemovedUtilities, 1)
+		assert.Equal(t, "cert-manager", clusterResp.UtilityMetadata.RemovedUtilities[0].Name)
+
+		utilities, err := client.GetClusterUtilityRegistry(cluster.ID)
+		require.NoError(t, err)
+		assert.Len(t, utilities, len(model.BuiltinUtilities))
+	})
+}
+
 func TestClusterAnnotations(t *testing.T) {
 	logger := testlib.MakeLogger(t)
 	sqlStore := store.MakeTestSQLStore(t, logger)
@@ -0,0 +1,152 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+)
+
+// handleGetClusterUpgrades responds to GET /api/cluster/{cluster}/kubernetes/upgrades,
+// returning the upgrades of the cluster, most recent first.
+func handleGetClusterUpgrades(c *Context, w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	clusterID := vars["cluster"]
+	c.Logger = c.Logger.WithField("cluster", clusterID).WithField("action", "list-cluster-upgrades")
+
+	paging, err := parsePaging(r.URL)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to parse paging parameters")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	cluster, err := c.Store.GetCluster(clusterID)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query cluster")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if cluster == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	filter := &model.ClusterUpgradeFilter{
+		Paging:    paging,
+		ClusterID: clusterID,
+	}
+	state := parseString(r.URL, "state", "")
+	if state != "" {
+		filter.States = []model.ClusterUpgradeState{model.ClusterUpgradeState(state)}
+	}
+
+	upgrades, err := c.Store.GetClusterUpgrades(filter)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query cluster upgrades")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, upgrades)
+}
+
+// handleResumeClusterUpgrade responds to POST /api/cluster/{cluster}/kubernetes/upgrade/resume,
+// resuming the failed upgrade of the cluster from the phase it failed in.
+func handleResumeClusterUpgrade(c *Context, w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	clusterID := vars["cluster"]
+	c.Logger = c.Logger.WithField("cluster", clusterID).WithField("action", "resume-cluster-upgrade")
+
+	newState := model.ClusterStateUpgradeRequested
+
+	clusterDTO, status, unlockOnce := getClusterForTransition(c, clusterID, newState)
+	if status != 0 {
+		w.WriteHeader(status)
+		return
+	}
+	defer unlockOnce()
+
+	if clusterDTO.State != model.ClusterStateUpgradeFailed {
+		c.Logger.Warnf("Cannot resume the upgrade of a cluster in state %s", clusterDTO.State)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	upgrade, err := c.Store.GetLatestClusterUpgrade(clusterID)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query cluster upgrade")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if upgrade == nil {
+		c.Logger.Warn("Cluster has no upgrade to resume")
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	err = upgrade.Resume()
+	if err != nil {
+		c.Logger.WithError(err).Warn("Cannot resume cluster upgrade")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	err = c.Store.UpdateClusterUpgrade(upgrade)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to update cluster upgrade")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	oldState := clusterDTO.State
+	clusterDTO.State = newState
+	err = c.Store.UpdateCluster(clusterDTO.Cluster)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to update cluster")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	err = c.EventProducer.ProduceClusterStateChangeEvent(clusterDTO.Cluster, oldState)
+	if err != nil {
+		c.Logger.WithError(err).Error("Failed to create cluster state change event")
+	}
+
+	unlockOnce()
+	c.Supervisor.Do()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	outputJSON(c, w, upgrade)
+}
+
+// startClusterUpgrade records a new upgrade of the cluster, failing the
+// upgrade it supersedes if that one was still in progress.
+func startClusterUpgrade(c *Context, cluster *model.Cluster, skipPreflight bool) error {
+	previous, err := c.Store.GetLatestClusterUpgrade(cluster.ID)
+	if err != nil {
+		return errors.Wrap(err, "failed to query latest cluster upgrade")
+	}
+	if previous != nil && previous.State == model.ClusterUpgradeStateInProgress {
+		previous.Fail("superseded by a new upgrade request")
+		err = c.Store.UpdateClusterUpgrade(previous)
+		if err != nil {
+			return errors.Wrap(err, "failed to update superseded cluster upgrade")
+		}
+	}
+
+	err = c.Store.CreateClusterUpgrade(model.NewClusterUpgrade(cluster.ID, cluster.UpgradeVersion(), skipPreflight))
+	if err != nil {
+		return errors.Wrap(err, "failed to create cluster upgrade")
+	}
+
+	return nil
+}
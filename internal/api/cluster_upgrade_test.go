@@ -0,0 +1,174 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package api_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost-cloud/internal/api"
+	"github.com/mattermost/mattermost-cloud/internal/store"
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/internal/testutil"
+	"github.com/mattermost/mattermost-cloud/internal/util"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClusterUpgrades(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	defer store.CloseConnection(t, sqlStore)
+
+	router := mux.NewRouter()
+	api.Register(router, &api.Context{
+		Store:         sqlStore,
+		Supervisor:    &mockSupervisor{},
+		EventProducer: testutil.SetupTestEventsProducer(sqlStore, logger),
+		Metrics:       &mockMetrics{},
+		Logger:        logger,
+		Provisioner:   &mockProvisioner{},
+	})
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	client := model.NewClient(ts.URL)
+
+	cluster1, err := client.CreateCluster(&model.CreateClusterRequest{
+		Provider: model.ProviderAWS,
+		Zones:    []string{"zone"},
+	})
+	require.NoError(t, err)
+
+	cluster1.State = model.ClusterStateStable
+	err = sqlStore.UpdateCluster(cluster1.Cluster)
+	require.NoError(t, err)
+
+	t.Run("unknown cluster", func(t *testing.T) {
+		upgrades, errTest := client.GetClusterUpgrades(model.NewID(), &model.GetClusterUpgradesRequest{Paging: model.AllPagesNotDeleted()})
+		require.EqualError(t, errTest, "failed with status code 404")
+		assert.Nil(t, upgrades)
+
+		upgrade, errTest := client.ResumeClusterUpgrade(model.NewID())
+		require.EqualError(t, errTest, "failed with status code 404")
+		assert.Nil(t, upgrade)
+	})
+
+	t.Run("no upgrades", func(t *testing.T) {
+		upgrades, errTest := client.GetClusterUpgrades(cluster1.ID, &model.GetClusterUpgradesRequest{Paging: model.AllPagesNotDeleted()})
+		require.NoError(t, errTest)
+		assert.Empty(t, upgrades)
+	})
+
+	t.Run("invalid paging", func(t *testing.T) {
+		resp, errTest := http.Get(fmt.Sprintf("%s/api/cluster/%s/kubernetes/upgrades?page=invalid", ts.URL, cluster1.ID))
+		require.NoError(t, errTest)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("upgrade records progress", func(t *testing.T) {
+		_, errTest := client.UpgradeCluster(cluster1.ID, &model.PatchUpgradeClusterRequest{Version: util.SToP("latest"), SkipPreflight: true})
+		require.NoError(t, errTest)
+
+		upgrades, errTest := client.GetClusterUpgrades(cluster1.ID, &model.GetClusterUpgradesRequest{Paging: model.AllPagesNotDeleted()})
+		require.NoError(t, errTest)
+		require.Len(t, upgrades, 1)
+		assert.Equal(t, cluster1.ID, upgrades[0].ClusterID)
+		assert.Equal(t, model.ClusterUpgradeStateInProgress, upgrades[0].State)
+		assert.Equal(t, model.ClusterUpgradePhasePreflight, upgrades[0].Phase)
+		assert.True(t, upgrades[0].SkipPreflight)
+	})
+
+	t.Run("resume while upgrading", func(t *testing.T) {
+		upgrade, errTest := client.ResumeClusterUpgrade(cluster1.ID)
+		require.EqualError(t, errTest, "failed with status code 400")
+		assert.Nil(t, upgrade)
+	})
+
+	t.Run("resume upgrade that did not fail", func(t *testing.T) {
+		cluster1.State = model.ClusterStateUpgradeFailed
+		errTest := sqlStore.UpdateCluster(cluster1.Cluster)
+		require.NoError(t, errTest)
+
+		upgrade, errTest := client.ResumeClusterUpgrade(cluster1.ID)
+		require.EqualError(t, errTest, "failed with status code 400")
+		assert.Nil(t, upgrade)
+	})
+
+	t.Run("resume failed upgrade", func(t *testing.T) {
+		latest, errTest := sqlStore.GetLatestClusterUpgrade(cluster1.ID)
+		require.NoError(t, errTest)
+		latest.Phase = model.ClusterUpgradePhaseNodeGroups
+		latest.Fail("nodegroup failed to roll")
+		errTest = sqlStore.UpdateClusterUpgrade(latest)
+		require.NoError(t, errTest)
+
+		failed, errTest := client.GetClusterUpgrades(cluster1.ID, &model.GetClusterUpgradesRequest{
+			Paging: model.AllPagesNotDeleted(),
+			State:  string(model.ClusterUpgradeStateFailed),
+		})
+		require.NoError(t, errTest)
+		require.Len(t, failed, 1)
+
+		upgrade, errTest := client.ResumeClusterUpgrade(cluster1.ID)
+		require.NoError(t, errTest)
+		assert.Equal(t, latest.ID, upgrade.ID)
+		assert.Equal(t, model.ClusterUpgradeStateInProgress, upgrade.State)
+		assert.Equal(t, model.ClusterUpgradePhaseNodeGroups, upgrade.Phase)
+		assert.Empty(t, upgrade.Message)
+
+		cluster, errTest := client.GetCluster(cluster1.ID)
+		require.NoError(t, errTest)
+		assert.Equal(t, model.ClusterStateUpgradeRequested, cluster.State)
+	})
+
+	t.Run("new upgrade supersedes upgrade in progress", func(t *testing.T) {
+		cluster1.State = model.ClusterStateStable
+		errTest := sqlStore.UpdateCluster(cluster1.Cluster)
+		require.NoError(t, errTest)
+
+		_, errTest = client.UpgradeCluster(cluster1.ID, &model.PatchUpgradeClusterRequest{Version: util.SToP("latest")})
+		require.NoError(t, errTest)
+
+		upgrades, errTest := client.GetClusterUpgrades(cluster1.ID, &model.GetClusterUpgradesRequest{Paging: model.AllPagesNotDeleted()})
+		require.NoError(t, errTest)
+		require.Len(t, upgrades, 2)
+		assert.Equal(t, model.ClusterUpgradeStateInProgress, upgrades[0].State)
+		assert.Equal(t, model.ClusterUpgradeStateFailed, upgrades[1].State)
+		assert.Equal(t, "superseded by a new upgrade request", upgrades[1].Message)
+	})
+
+	t.Run("resume without upgrade", func(t *testing.T) {
+		cluster2, errTest := client.CreateCluster(&model.CreateClusterRequest{
+			Provider: model.ProviderAWS,
+			Zones:    []string{"zone"},
+		})
+		require.NoError(t, errTest)
+		cluster2.State = model.ClusterStateUpgradeFailed
+		errTest = sqlStore.UpdateCluster(cluster2.Cluster)
+		require.NoError(t, errTest)
+
+		upgrade, errTest := client.ResumeClusterUpgrade(cluster2.ID)
+		require.EqualError(t, errTest, "failed with status code 404")
+		assert.Nil(t, upgrade)
+	})
+
+	t.Run("resume while api-security-locked", func(t *testing.T) {
+		errTest := sqlStore.LockClusterAPI(cluster1.ID)
+		require.NoError(t, errTest)
+		defer func() {
+			errDefer := sqlStore.UnlockClusterAPI(cluster1.ID)
+			require.NoError(t, errDefer)
+		}()
+
+		upgrade, errTest := client.ResumeClusterUpgrade(cluster1.ID)
+		require.EqualError(t, errTest, "failed with status code 403")
+		assert.Nil(t, upgrade)
+	})
+}
@@ -22,13 +22,19 @@ func (s *mockDNSProvider) DeleteDNSRecords(customerDNSName []string, logger log.
 	return nil
 }
 
-type mockMetrics struct{}
+type mockMetrics struct {
+	DeletedSubscriptions []string
+}
 
 func (m *mockMetrics) IncrementAPIRequest() {}
 
 func (m *mockMetrics) ObserveAPIEndpointDuration(handler, method string, statusCode int, elapsed float64) {
 }
 
+func (m *mockMetrics) DeleteSubscriptionMetrics(subscriptionID string) {
+	m.DeletedSubscriptions = append(m.DeletedSubscriptions, subscriptionID)
+}
+
 type mockProvisioner struct {
 	Output       []byte
 	DebugData    model.ClusterInstallationDebugData
@@ -23,6 +23,35 @@ type Store interface {
 	model.InstallationDatabaseStoreInterface
 	DeleteMultitenantDatabase(multitenantDatabaseID string) error
 	DeleteLogicalDatabase(logicalDatabaseID string) error
+	CreateMultitenantDatabaseRebalance(rebalance *model.MultitenantDatabaseRebalance) error
+	GetMultitenantDatabaseRebalance(id string) (*model.MultitenantDatabaseRebalance, error)
+	GetMultitenantDatabaseRebalances(filter *model.MultitenantDatabaseRebalanceFilter) ([]*model.MultitenantDatabaseRebalance, error)
+
+	CreateUtilityRollout(rollout *model.UtilityRollout) error
+	GetUtilityRollout(id string) (*model.UtilityRollout, error)
+	GetUtilityRollouts(filter *model.UtilityRolloutFilter) ([]*model.UtilityRollout, error)
+	UpdateUtilityRollout(rollout *model.UtilityRollout) error
+	LockUtilityRollouts(ids []string, lockerID string) (bool, error)
+	UnlockUtilityRollouts(ids []string, lockerID string, force bool) (bool, error)
+
+	CreateClusterUpgrade(upgrade *model.ClusterUpgrade) error
+	GetLatestClusterUpgrade(clusterID string) (*model.ClusterUpgrade, error)
+	GetClusterUpgrades(filter *model.ClusterUpgradeFilter) ([]*model.ClusterUpgrade, error)
+	UpdateClusterUpgrade(upgrade *model.ClusterUpgrade) error
+
+	CreateClusterTemplate(template *model.ClusterTemplate) error
+	GetClusterTemplateByName(name string) (*model.ClusterTemplate, error)
+	GetClusterTemplates(filter *model.ClusterTemplateFilter) ([]*model.ClusterTemplate, error)
+	UpdateClusterTemplate(template *model.ClusterTemplate) error
+	DeleteClusterTemplate(id string) error
+
+	CreateSizeProfile(profile *model.SizeProfile) error
+	GetSizeProfileByName(name string) (*model.SizeProfile, error)
+	GetSizeProfiles(filter *model.SizeProfileFilter) ([]*model.SizeProfile, error)
+	UpdateSizeProfile(profile *model.SizeProfile) error
+	DeleteSizeProfile(id string) error
+
+	GetInstallationUsages(filter *model.InstallationUsageFilter) ([]*model.InstallationUsage, error)
 
 	CreateCluster(cluster *model.Cluster, annotations []*model.Annotation) error
 	GetCluster(clusterID string) (*model.Cluster, error)
@@ -101,6 +130,7 @@ type Store interface {
 	UnlockInstallationBackupAPI(backupID string) error
 
 	TriggerInstallationRestoration(installation *model.Installation, backup *model.InstallationBackup) (*model.InstallationDBRestorationOperation, error)
+	TriggerInstallationPointInTimeRestoration(installation *model.Installation, restoreTime int64) (*model.InstallationDBRestorationOperation, error)
 	GetInstallationDBRestorationOperation(id string) (*model.InstallationDBRestorationOperation, error)
 	GetInstallationDBRestorationOperations(filter *model.InstallationDBRestorationFilter) ([]*model.InstallationDBRestorationOperation, error)
 
@@ -119,10 +149,22 @@ type Store interface {
 	LockInstallationDBMigrationOperation(id, lockerID string) (bool, error)
 	UnlockInstallationDBMigrationOperation(id, lockerID string, force bool) (bool, error)
 
+	TriggerInstallationFilestoreMigration(filestoreMigrationOp *model.InstallationFilestoreMigrationOperation, installation *model.Installation) (*model.InstallationFilestoreMigrationOperation, error)
+	TriggerInstallationFilestoreMigrationRollback(filestoreMigrationOp *model.InstallationFilestoreMigrationOperation, installation *model.Installation) error
+	GetInstallationFilestoreMigrationOperations(filter *model.InstallationFilestoreMigrationFilter) ([]*model.InstallationFilestoreMigrationOperation, error)
+	GetInstallationFilestoreMigrationOperation(id string) (*model.InstallationFilestoreMigrationOperation, error)
+	UpdateInstallationFilestoreMigrationOperationState(filestoreMigration *model.InstallationFilestoreMigrationOperation) error
+	LockInstallationFilestoreMigrationOperation(id, lockerID string) (bool, error)
+	UnlockInstallationFilestoreMigrationOperation(id, lockerID string, force bool) (bool, error)
+
 	CreateSubscription(sub *model.Subscription) error
 	GetSubscriptions(filter *model.SubscriptionsFilter) ([]*model.Subscription, error)
 	GetSubscription(subID string) (*model.Subscription, error)
 	DeleteSubscription(subID string) error
+	UpdateSubscriptionPaused(subID string, paused bool) error
+	GetSubscriptionStats(subID string) (*model.SubscriptionStats, error)
+	GetEventDeliveries(filter *model.EventDeliveryFilter) ([]*model.StateChangeEventDeliveryData, error)
+	ReplayEventDeliveries(subID string, request *model.ReplaySubscriptionDeliveriesRequest) (int64, error)
 
 	GetStateChangeEvents(filter *model.StateChangeEventFilter) ([]*model.StateChangeEventData, error)
 
@@ -158,12 +200,18 @@ type AwsClient interface {
 // DBProvider describes the interface required to get database for specific installation and specified type.
 type DBProvider interface {
 	GetDatabase(installationID, dbType string) model.Database
+	GetMultitenantDatabaseMaxInstallations(databaseType string) int
 }
 
 // EventProducer produces Provisioners' state change events.
 type EventProducer interface {
 	ProduceInstallationStateChangeEvent(installation *model.Installation, oldState string, extraDataFields ...events.DataField) error
 	ProduceClusterStateChangeEvent(cluster *model.Cluster, oldState string, extraDataFields ...events.DataField) error
+	ProduceInstallationSpecUpdateEvent(installation *model.Installation, data *model.InstallationSpecUpdateEventData) error
+	ProduceInstallationDNSRecordChangeEvent(installation *model.Installation, data *model.DNSRecordChangeEventData) error
+	ProduceAnnotationChangeEvent(resourceType model.ResourceType, resourceID, state string, data *model.AnnotationChangeEventData) error
+	ProduceGroupConfigChangeEvent(group *model.Group, data *model.GroupConfigChangeEventData) error
+	ProduceLockChangeEvent(resourceType model.ResourceType, resourceID, state string, locked bool) error
 }
 
 // InstallationDNSProvider allows for domain name management of installations.
@@ -175,6 +223,7 @@ type InstallationDNSProvider interface {
 type Metrics interface {
 	IncrementAPIRequest()
 	ObserveAPIEndpointDuration(handler, method string, statusCode int, elapsed float64)
+	DeleteSubscriptionMetrics(subscriptionID string)
 }
 
 // Context provides the API with all necessary data and interfaces for responding to requests.
@@ -194,6 +243,8 @@ type Context struct {
 	RequestID                         string
 	Environment                       string
 	AuthConfig                        *auth.ServerConfig
+	UsagePriceTable                   *model.UsagePriceTable
+	SizeRecommendationPolicy          *model.SizeRecommendationPolicy
 }
 
 // Clone creates a shallow copy of context, allowing clones to apply per-request changes.
@@ -209,5 +260,7 @@ func (c *Context) Clone() *Context {
 		Metrics:                           c.Metrics,
 		Logger:                            c.Logger,
 		InstallationDeletionExpiryDefault: c.InstallationDeletionExpiryDefault,
+		UsagePriceTable:                   c.UsagePriceTable,
+		SizeRecommendationPolicy:          c.SizeRecommendationPolicy,
 	}
 }
@@ -20,11 +20,18 @@ func initMultitenantDatabases(apiRouter *mux.Router, context *Context) {
 
 	MultitenantDatabasesRouter := apiRouter.PathPrefix("/multitenant_databases").Subrouter()
 	MultitenantDatabasesRouter.Handle("", addContext(handleGetMultitenantDatabases)).Methods("GET")
+	MultitenantDatabasesRouter.Handle("/capacity", addContext(handleGetMultitenantDatabasesCapacity)).Methods("GET")
+	MultitenantDatabasesRouter.Handle("/rebalance", addContext(handleGetMultitenantDatabaseRebalancePlan)).Methods("GET")
+	MultitenantDatabasesRouter.Handle("/rebalance", addContext(handleRebalanceMultitenantDatabases)).Methods("POST")
+	MultitenantDatabasesRouter.Handle("/rebalances", addContext(handleGetMultitenantDatabaseRebalances)).Methods("GET")
 
 	MultitenantDatabaseRouter := apiRouter.PathPrefix("/multitenant_database/{multitenant_database:[A-Za-z0-9]{26}}").Subrouter()
 	MultitenantDatabaseRouter.Handle("", addContext(handleGetMultitenantDatabase)).Methods("GET")
 	MultitenantDatabaseRouter.Handle("", addContext(handleUpdateMultitenantDatabase)).Methods("PUT")
 	MultitenantDatabaseRouter.Handle("", addContext(handleDeleteMultitenantDatabase)).Methods("DELETE")
+
+	MultitenantDatabaseRebalanceRouter := apiRouter.PathPrefix("/multitenant_database_rebalance/{rebalance:[A-Za-z0-9]{26}}").Subrouter()
+	MultitenantDatabaseRebalanceRouter.Handle("", addContext(handleGetMultitenantDatabaseRebalance)).Methods("GET")
 }
 
 // handleGetMultitenantDatabases responds to GET /api/databases/multitenant_databases,
@@ -0,0 +1,49 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/internal/common"
+	"github.com/mattermost/mattermost-cloud/model"
+)
+
+// handleGetMultitenantDatabasesCapacity responds to GET /api/databases/multitenant_databases/capacity,
+// returning the capacity and growth forecast of multitenant databases.
+func handleGetMultitenantDatabasesCapacity(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.Logger = c.Logger.WithField("action", "get-multitenant-databases-capacity")
+
+	lookbackDays, err := parseInt(r.URL, "lookback_days", model.DefaultCapacityLookbackDays)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to parse lookback_days")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if lookbackDays < 1 {
+		c.Logger.Errorf("lookback_days must be 1 or greater, got %d", lookbackDays)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	filter := &model.MultitenantDatabaseFilter{
+		VpcID:                 parseString(r.URL, "vpc_id", ""),
+		DatabaseType:          parseString(r.URL, "database_type", ""),
+		Paging:                model.AllPagesNotDeleted(),
+		MaxInstallationsLimit: model.NoInstallationsLimit,
+	}
+
+	capacities, err := common.GetMultitenantDatabaseCapacities(c.Store, c.DBProvider, filter, time.Duration(lookbackDays)*24*time.Hour)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to compute multitenant database capacity")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, capacities)
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package api_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost-cloud/internal/api"
+	"github.com/mattermost/mattermost-cloud/internal/store"
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetMultitenantDatabasesCapacity(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	defer store.CloseConnection(t, sqlStore)
+
+	router := mux.NewRouter()
+	api.Register(router, &api.Context{
+		Store:      sqlStore,
+		Supervisor: &mockSupervisor{},
+		Metrics:    &mockMetrics{},
+		Logger:     logger,
+		DBProvider: &dbProviderMock{},
+	})
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	client := model.NewClient(ts.URL)
+
+	t.Run("no databases", func(t *testing.T) {
+		capacities, err := client.GetMultitenantDatabasesCapacity(&model.GetMultitenantDatabasesCapacityRequest{})
+		require.NoError(t, err)
+		require.Empty(t, capacities)
+	})
+
+	t.Run("invalid lookback", func(t *testing.T) {
+		resp, err := http.Get(fmt.Sprintf("%s/api/databases/multitenant_databases/capacity?lookback_days=0", ts.URL))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("results", func(t *testing.T) {
+		installation := &model.Installation{
+			Name:     "capacity",
+			State:    model.InstallationStateStable,
+			Database: model.InstallationDatabaseMultiTenantRDSPostgres,
+		}
+		err := sqlStore.CreateInstallation(installation, nil, nil)
+		require.NoError(t, err)
+
+		database := &model.MultitenantDatabase{
+			RdsClusterID:  model.NewID(),
+			VpcID:         "vpc1",
+			DatabaseType:  model.DatabaseEngineTypePostgres,
+			Installations: model.MultitenantDatabaseInstallations{installation.ID},
+		}
+		err = sqlStore.CreateMultitenantDatabase(database)
+		require.NoError(t, err)
+
+		capacities, err := client.GetMultitenantDatabasesCapacity(&model.GetMultitenantDatabasesCapacityRequest{VpcID: "vpc1"})
+		require.NoError(t, err)
+		require.Len(t, capacities, 1)
+		assert.Equal(t, database.ID, capacities[0].MultitenantDatabaseID)
+		assert.Equal(t, model.DefaultDatabaseWeight, capacities[0].Weight)
+		assert.Equal(t, 10.0, capacities[0].MaxWeight)
+		assert.Equal(t, 9.0, capacities[0].Headroom)
+		assert.NotZero(t, capacities[0].WeightGrowthPerDay)
+		assert.NotZero(t, capacities[0].ProjectedExhaustionAt)
+	})
+}
@@ -0,0 +1,212 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+)
+
+// handleGetMultitenantDatabaseRebalancePlan responds to GET /api/databases/multitenant_databases/rebalance,
+// returning a dry-run rebalance plan for the multitenant databases of a VPC.
+func handleGetMultitenantDatabaseRebalancePlan(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.Logger = c.Logger.WithField("action", "plan-multitenant-database-rebalance")
+
+	maxMoves, err := parseInt(r.URL, "max_moves", 0)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to parse max_moves")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	request := &model.MultitenantDatabaseRebalanceRequest{
+		VpcID:        parseString(r.URL, "vpc_id", ""),
+		DatabaseType: parseString(r.URL, "database_type", ""),
+		MaxMoves:     maxMoves,
+	}
+	request.SetDefaults()
+	err = request.Validate()
+	if err != nil {
+		c.Logger.WithError(err).Error("invalid rebalance request")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	plan, err := buildMultitenantDatabaseRebalancePlan(c, request)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to build multitenant database rebalance plan")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, plan)
+}
+
+// handleRebalanceMultitenantDatabases responds to POST /api/databases/multitenant_databases/rebalance,
+// approving a rebalance plan and scheduling its migrations.
+func handleRebalanceMultitenantDatabases(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.Logger = c.Logger.WithField("action", "rebalance-multitenant-databases")
+
+	request, err := model.NewMultitenantDatabaseRebalanceRequestFromReader(r.Body)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to decode request")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	c.Logger = c.Logger.WithField("vpc", request.VpcID)
+
+	pendingRebalances, err := c.Store.GetMultitenantDatabaseRebalances(&model.MultitenantDatabaseRebalanceFilter{
+		Paging: model.AllPagesNotDeleted(),
+		VpcID:  request.VpcID,
+		States: model.AllMultitenantDatabaseRebalanceStatesPendingWork,
+	})
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query pending multitenant database rebalances")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if len(pendingRebalances) > 0 {
+		c.Logger.Errorf("Rebalance %s is still in progress for this VPC", pendingRebalances[0].ID)
+		w.WriteHeader(http.StatusConflict)
+		return
+	}
+
+	plan, err := buildMultitenantDatabaseRebalancePlan(c, request)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to build multitenant database rebalance plan")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if len(plan.Moves) == 0 {
+		c.Logger.Info("Multitenant databases are already balanced")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	for _, move := range plan.Moves {
+		move.State = model.MultitenantDatabaseRebalanceMovePending
+	}
+
+	rebalance := &model.MultitenantDatabaseRebalance{
+		VpcID:                   request.VpcID,
+		DatabaseType:            request.DatabaseType,
+		State:                   model.MultitenantDatabaseRebalanceStateRequested,
+		MaxConcurrentMigrations: request.MaxConcurrentMigrations,
+		Moves:                   plan.Moves,
+	}
+	err = c.Store.CreateMultitenantDatabaseRebalance(rebalance)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to create multitenant database rebalance")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	c.Supervisor.Do()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	outputJSON(c, w, rebalance)
+}
+
+// handleGetMultitenantDatabaseRebalances responds to GET /api/databases/multitenant_databases/rebalances,
+// returning a list of multitenant database rebalances.
+func handleGetMultitenantDatabaseRebalances(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.Logger = c.Logger.WithField("action", "list-multitenant-database-rebalances")
+
+	paging, err := parsePaging(r.URL)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to parse paging parameters")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	filter := &model.MultitenantDatabaseRebalanceFilter{
+		Paging: paging,
+		VpcID:  parseString(r.URL, "vpc_id", ""),
+	}
+	state := parseString(r.URL, "state", "")
+	if state != "" {
+		filter.States = []model.MultitenantDatabaseRebalanceState{model.MultitenantDatabaseRebalanceState(state)}
+	}
+
+	rebalances, err := c.Store.GetMultitenantDatabaseRebalances(filter)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query multitenant database rebalances")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, rebalances)
+}
+
+// handleGetMultitenantDatabaseRebalance responds to GET /api/databases/multitenant_database_rebalance/{rebalance},
+// returning the multitenant database rebalance in question.
+func handleGetMultitenantDatabaseRebalance(c *Context, w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	rebalanceID := vars["rebalance"]
+	c.Logger = c.Logger.WithField("rebalance", rebalanceID)
+
+	rebalance, err := c.Store.GetMultitenantDatabaseRebalance(rebalanceID)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query multitenant database rebalance")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if rebalance == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, rebalance)
+}
+
+func buildMultitenantDatabaseRebalancePlan(c *Context, request *model.MultitenantDatabaseRebalanceRequest) (*model.MultitenantDatabaseRebalancePlan, error) {
+	multitenantDatabases, err := c.Store.GetMultitenantDatabases(&model.MultitenantDatabaseFilter{
+		VpcID:                 request.VpcID,
+		DatabaseType:          request.DatabaseType,
+		MaxInstallationsLimit: model.NoInstallationsLimit,
+		Paging:                model.AllPagesNotDeleted(),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query multitenant databases")
+	}
+
+	var installationIDs []string
+	for _, multitenantDatabase := range multitenantDatabases {
+		installationIDs = append(installationIDs, multitenantDatabase.Installations...)
+	}
+
+	installations := make(map[string]*model.Installation, len(installationIDs))
+	if len(installationIDs) > 0 {
+		installationList, err := c.Store.GetInstallations(&model.InstallationFilter{
+			InstallationIDs: installationIDs,
+			Paging:          model.AllPagesNotDeleted(),
+		}, false, false)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to query installations")
+		}
+		for _, installation := range installationList {
+			installations[installation.ID] = installation
+		}
+	}
+
+	return model.NewMultitenantDatabaseRebalancePlan(
+		request.VpcID,
+		request.DatabaseType,
+		multitenantDatabases,
+		installations,
+		float64(c.DBProvider.GetMultitenantDatabaseMaxInstallations(request.DatabaseType)),
+		request.MaxMoves,
+	), nil
+}
@@ -0,0 +1,160 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package api_test
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost-cloud/internal/api"
+	"github.com/mattermost/mattermost-cloud/internal/store"
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultitenantDatabaseRebalance(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	defer store.CloseConnection(t, sqlStore)
+
+	router := mux.NewRouter()
+	api.Register(router, &api.Context{
+		Store:      sqlStore,
+		Supervisor: &mockSupervisor{},
+		Metrics:    &mockMetrics{},
+		Logger:     logger,
+		DBProvider: &dbProviderMock{},
+	})
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	client := model.NewClient(ts.URL)
+
+	var installationIDs model.MultitenantDatabaseInstallations
+	for i := 0; i < 4; i++ {
+		installation := &model.Installation{
+			Name:     fmt.Sprintf("rebalance%d", i),
+			State:    model.InstallationStateHibernating,
+			Database: model.InstallationDatabaseMultiTenantRDSPostgres,
+		}
+		err := sqlStore.CreateInstallation(installation, nil, nil)
+		require.NoError(t, err)
+		installationIDs = append(installationIDs, installation.ID)
+	}
+
+	heavyDatabase := &model.MultitenantDatabase{
+		RdsClusterID:  model.NewID(),
+		VpcID:         "vpc1",
+		DatabaseType:  model.DatabaseEngineTypePostgres,
+		Installations: installationIDs,
+	}
+	err := sqlStore.CreateMultitenantDatabase(heavyDatabase)
+	require.NoError(t, err)
+	lightDatabase := &model.MultitenantDatabase{
+		RdsClusterID: model.NewID(),
+		VpcID:        "vpc1",
+		DatabaseType: model.DatabaseEngineTypePostgres,
+	}
+	err = sqlStore.CreateMultitenantDatabase(lightDatabase)
+	require.NoError(t, err)
+
+	t.Run("plan", func(t *testing.T) {
+		plan, err := client.GetMultitenantDatabaseRebalancePlan(&model.MultitenantDatabaseRebalanceRequest{VpcID: "vpc1"})
+		require.NoError(t, err)
+		assert.Equal(t, 10.0, plan.MaxWeight)
+		require.Len(t, plan.Databases, 2)
+		require.Len(t, plan.Moves, 2)
+		for _, move := range plan.Moves {
+			assert.Equal(t, heavyDatabase.ID, move.SourceDatabaseID)
+			assert.Equal(t, lightDatabase.ID, move.DestinationDatabaseID)
+		}
+	})
+
+	t.Run("plan with max moves", func(t *testing.T) {
+		plan, err := client.GetMultitenantDatabaseRebalancePlan(&model.MultitenantDatabaseRebalanceRequest{VpcID: "vpc1", MaxMoves: 1})
+		require.NoError(t, err)
+		assert.Len(t, plan.Moves, 1)
+	})
+
+	t.Run("invalid plan requests", func(t *testing.T) {
+		_, err := client.GetMultitenantDatabaseRebalancePlan(&model.MultitenantDatabaseRebalanceRequest{})
+		require.EqualError(t, err, "failed with status code 400")
+
+		_, err = client.GetMultitenantDatabaseRebalancePlan(&model.MultitenantDatabaseRebalanceRequest{VpcID: "vpc1", DatabaseType: model.DatabaseEngineTypeMySQL})
+		require.EqualError(t, err, "failed with status code 400")
+
+		resp, err := http.Get(fmt.Sprintf("%s/api/databases/multitenant_databases/rebalance?vpc_id=vpc1&max_moves=invalid", ts.URL))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("invalid rebalance requests", func(t *testing.T) {
+		_, err := client.RebalanceMultitenantDatabases(&model.MultitenantDatabaseRebalanceRequest{})
+		require.EqualError(t, err, "failed with status code 400")
+
+		_, err = client.RebalanceMultitenantDatabases(&model.MultitenantDatabaseRebalanceRequest{VpcID: "vpc1", MaxMoves: -1})
+		require.EqualError(t, err, "failed with status code 400")
+
+		resp, err := http.Post(fmt.Sprintf("%s/api/databases/multitenant_databases/rebalance", ts.URL), "application/json", bytes.NewReader([]byte("invalid")))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("already balanced", func(t *testing.T) {
+		_, err := client.RebalanceMultitenantDatabases(&model.MultitenantDatabaseRebalanceRequest{VpcID: "vpc2"})
+		require.EqualError(t, err, "failed with status code 400")
+	})
+
+	var rebalance *model.MultitenantDatabaseRebalance
+	t.Run("rebalance", func(t *testing.T) {
+		rebalance, err = client.RebalanceMultitenantDatabases(&model.MultitenantDatabaseRebalanceRequest{VpcID: "vpc1", MaxConcurrentMigrations: 2})
+		require.NoError(t, err)
+		assert.NotEmpty(t, rebalance.ID)
+		assert.Equal(t, model.MultitenantDatabaseRebalanceStateRequested, rebalance.State)
+		assert.Equal(t, 2, rebalance.MaxConcurrentMigrations)
+		require.Len(t, rebalance.Moves, 2)
+		for _, move := range rebalance.Moves {
+			assert.Equal(t, model.MultitenantDatabaseRebalanceMovePending, move.State)
+		}
+	})
+
+	t.Run("rebalance in progress", func(t *testing.T) {
+		_, err = client.RebalanceMultitenantDatabases(&model.MultitenantDatabaseRebalanceRequest{VpcID: "vpc1"})
+		require.EqualError(t, err, "failed with status code 409")
+	})
+
+	t.Run("get rebalances", func(t *testing.T) {
+		rebalances, err := client.GetMultitenantDatabaseRebalances(&model.GetMultitenantDatabaseRebalancesRequest{
+			Paging: model.AllPagesNotDeleted(),
+			VpcID:  "vpc1",
+		})
+		require.NoError(t, err)
+		require.Len(t, rebalances, 1)
+		assert.Equal(t, rebalance, rebalances[0])
+
+		rebalances, err = client.GetMultitenantDatabaseRebalances(&model.GetMultitenantDatabaseRebalancesRequest{
+			Paging: model.AllPagesNotDeleted(),
+			State:  string(model.MultitenantDatabaseRebalanceStateSucceeded),
+		})
+		require.NoError(t, err)
+		assert.Empty(t, rebalances)
+	})
+
+	t.Run("get rebalance", func(t *testing.T) {
+		fetchedRebalance, err := client.GetMultitenantDatabaseRebalance(rebalance.ID)
+		require.NoError(t, err)
+		assert.Equal(t, rebalance, fetchedRebalance)
+
+		fetchedRebalance, err = client.GetMultitenantDatabaseRebalance(model.NewID())
+		require.NoError(t, err)
+		assert.Nil(t, fetchedRebalance)
+	})
+}
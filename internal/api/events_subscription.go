@@ -25,6 +25,11 @@ func initSubscription(apiRouter *mux.Router, context *Context) {
 	subscriptionRouter := apiRouter.PathPrefix("/subscription/{subscription:[A-Za-z0-9]{26}}").Subrouter()
 	subscriptionRouter.Handle("", addContext(handleGetSubscription)).Methods("GET")
 	subscriptionRouter.Handle("", addContext(handleDeleteSubscription)).Methods("DELETE")
+	subscriptionRouter.Handle("/stats", addContext(handleGetSubscriptionStats)).Methods("GET")
+	subscriptionRouter.Handle("/deliveries", addContext(handleListSubscriptionDeliveries)).Methods("GET")
+	subscriptionRouter.Handle("/replay", addContext(handleReplaySubscriptionDeliveries)).Methods("POST")
+	subscriptionRouter.Handle("/pause", addContext(handlePauseSubscription)).Methods("POST")
+	subscriptionRouter.Handle("/resume", addContext(handleResumeSubscription)).Methods("POST")
 }
 
 // handleRegisterSubscription responds to POST /api/subscriptions, registering new subscription.
@@ -135,6 +140,166 @@ func handleDeleteSubscription(c *Context, w http.ResponseWriter, r *http.Request
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
+	c.Metrics.DeleteSubscriptionMetrics(subID)
 
 	w.WriteHeader(http.StatusOK)
 }
+
+// handleGetSubscriptionStats responds to GET /api/subscription/{subscription}/stats,
+// returning the event delivery stats of the subscription.
+func handleGetSubscriptionStats(c *Context, w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	subID := vars["subscription"]
+	c.Logger = c.Logger.WithField("subscription", subID)
+
+	stats, err := c.Store.GetSubscriptionStats(subID)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to get subscription stats")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if stats == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, stats)
+}
+
+// handleListSubscriptionDeliveries responds to GET /api/subscription/{subscription}/deliveries,
+// returning the specified page of event deliveries of the subscription.
+func handleListSubscriptionDeliveries(c *Context, w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	subID := vars["subscription"]
+	c.Logger = c.Logger.WithField("subscription", subID)
+
+	paging, err := parsePaging(r.URL)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to parse paging parameters")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	subscription, err := c.Store.GetSubscription(subID)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query subscription")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if subscription == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	filter := &model.EventDeliveryFilter{
+		Paging:         paging,
+		SubscriptionID: subID,
+		Status:         model.EventDeliveryStatus(r.URL.Query().Get("status")),
+	}
+
+	deliveries, err := c.Store.GetEventDeliveries(filter)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query event deliveries")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if deliveries == nil {
+		deliveries = []*model.StateChangeEventDeliveryData{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, deliveries)
+}
+
+// handleReplaySubscriptionDeliveries responds to POST /api/subscription/{subscription}/replay,
+// scheduling the requested event deliveries to be sent again.
+func handleReplaySubscriptionDeliveries(c *Context, w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	subID := vars["subscription"]
+	c.Logger = c.Logger.WithField("subscription", subID)
+
+	replayRequest, err := model.NewReplaySubscriptionDeliveriesRequestFromReader(r.Body)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to decode request")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	subscription, status := getActiveSubscription(c, subID)
+	if status != 0 {
+		w.WriteHeader(status)
+		return
+	}
+
+	replayed, err := c.Store.ReplayEventDeliveries(subscription.ID, replayRequest)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to replay event deliveries")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	c.Logger.Infof("Scheduled %d event deliveries for replay", replayed)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	outputJSON(c, w, model.ReplaySubscriptionDeliveriesResponse{Replayed: replayed})
+}
+
+// handlePauseSubscription responds to POST /api/subscription/{subscription}/pause,
+// stopping event deliveries to the subscription.
+func handlePauseSubscription(c *Context, w http.ResponseWriter, r *http.Request) {
+	updateSubscriptionPaused(c, w, r, true)
+}
+
+// handleResumeSubscription responds to POST /api/subscription/{subscription}/resume,
+// resuming event deliveries to the subscription.
+func handleResumeSubscription(c *Context, w http.ResponseWriter, r *http.Request) {
+	updateSubscriptionPaused(c, w, r, false)
+}
+
+func updateSubscriptionPaused(c *Context, w http.ResponseWriter, r *http.Request, paused bool) {
+	vars := mux.Vars(r)
+	subID := vars["subscription"]
+	c.Logger = c.Logger.WithField("subscription", subID)
+
+	subscription, status := getActiveSubscription(c, subID)
+	if status != 0 {
+		w.WriteHeader(status)
+		return
+	}
+
+	if subscription.Paused != paused {
+		err := c.Store.UpdateSubscriptionPaused(subscription.ID, paused)
+		if err != nil {
+			c.Logger.WithError(err).Error("failed to update subscription")
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		subscription.Paused = paused
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, subscription)
+}
+
+// getActiveSubscription returns the subscription that is not deleted or
+// the HTTP status code to respond with.
+func getActiveSubscription(c *Context, subID string) (*model.Subscription, int) {
+	subscription, err := c.Store.GetSubscription(subID)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query subscription")
+		return nil, http.StatusInternalServerError
+	}
+	if subscription == nil {
+		return nil, http.StatusNotFound
+	}
+	if subscription.IsDeleted() {
+		c.Logger.Warn("subscription is deleted")
+		return nil, http.StatusBadRequest
+	}
+
+	return subscription, 0
+}
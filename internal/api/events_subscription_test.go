@@ -26,11 +26,12 @@ func TestCreateGetDeleteSubscriptions(t *testing.T) {
 	sqlStore := store.MakeTestSQLStore(t, logger)
 	defer store.CloseConnection(t, sqlStore)
 
+	metrics := &mockMetrics{}
 	router := mux.NewRouter()
 	api.Register(router, &api.Context{
 		Store:      sqlStore,
 		Supervisor: &mockSupervisor{},
-		Metrics:    &mockMetrics{},
+		Metrics:    metrics,
 		Logger:     logger,
 	})
 
@@ -70,10 +71,12 @@ func TestCreateGetDeleteSubscriptions(t *testing.T) {
 	// Delete subscription
 	err = client.DeleteSubscription(sub.ID)
 	require.NoError(t, err)
+	assert.Equal(t, []string{sub.ID}, metrics.DeletedSubscriptions)
 
 	t.Run("fail to delete twice", func(t *testing.T) {
 		errTest := client.DeleteSubscription(sub.ID)
 		require.Error(t, errTest)
+		assert.Equal(t, []string{sub.ID}, metrics.DeletedSubscriptions)
 	})
 
 	fetchedSub, err = client.GetSubscription(sub.ID)
@@ -231,3 +234,151 @@ func TestListSubscriptions(t *testing.T) {
 		})
 	}
 }
+
+func TestSubscriptionDeadLetters(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	defer store.CloseConnection(t, sqlStore)
+
+	router := mux.NewRouter()
+	api.Register(router, &api.Context{
+		Store:      sqlStore,
+		Supervisor: &mockSupervisor{},
+		Metrics:    &mockMetrics{},
+		Logger:     logger,
+	})
+
+	ts := httptest.NewServer(router)
+	client := model.NewClient(ts.URL)
+
+	sub, err := client.CreateSubscription(&model.CreateSubscriptionRequest{
+		URL:       "https://test",
+		OwnerID:   "tester",
+		EventType: model.ResourceStateChangeEventType,
+	})
+	require.NoError(t, err)
+
+	eventData := &model.StateChangeEventData{
+		Event: model.Event{
+			EventType: model.ResourceStateChangeEventType,
+			Timestamp: model.GetMillis(),
+		},
+		StateChange: model.StateChangeEvent{
+			OldState:     "old",
+			NewState:     "new",
+			ResourceID:   "installation1",
+			ResourceType: model.TypeInstallation,
+		},
+	}
+	err = sqlStore.CreateStateChangeEvent(eventData)
+	require.NoError(t, err)
+
+	deliveries, err := client.ListSubscriptionDeliveries(sub.ID, &model.ListSubscriptionDeliveriesRequest{
+		Paging: model.AllPagesNotDeleted(),
+	})
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, eventData.Event.ID, deliveries[0].EventData.Event.ID)
+
+	deliveries[0].EventDelivery.Status = model.EventDeliveryFailed
+	err = sqlStore.UpdateEventDeliveryStatus(&deliveries[0].EventDelivery)
+	require.NoError(t, err)
+
+	t.Run("list failed deliveries", func(t *testing.T) {
+		failed, errTest := client.ListSubscriptionDeliveries(sub.ID, &model.ListSubscriptionDeliveriesRequest{
+			Paging: model.AllPagesNotDeleted(),
+			Status: model.EventDeliveryFailed,
+		})
+		require.NoError(t, errTest)
+		assert.Len(t, failed, 1)
+	})
+
+	t.Run("invalid replay request", func(t *testing.T) {
+		_, errTest := client.ReplaySubscriptionDeliveries(sub.ID, &model.ReplaySubscriptionDeliveriesRequest{})
+		require.Error(t, errTest)
+	})
+
+	t.Run("replay time range", func(t *testing.T) {
+		response, errTest := client.ReplaySubscriptionDeliveries(sub.ID, &model.ReplaySubscriptionDeliveriesRequest{
+			From: eventData.Event.Timestamp - 1000,
+			To:   eventData.Event.Timestamp,
+		})
+		require.NoError(t, errTest)
+		assert.Equal(t, int64(1), response.Replayed)
+
+		failed, errTest := client.ListSubscriptionDeliveries(sub.ID, &model.ListSubscriptionDeliveriesRequest{
+			Paging: model.AllPagesNotDeleted(),
+			Status: model.EventDeliveryFailed,
+		})
+		require.NoError(t, errTest)
+		assert.Empty(t, failed)
+	})
+
+	t.Run("pause and resume", func(t *testing.T) {
+		paused, errTest := client.PauseSubscription(sub.ID)
+		require.NoError(t, errTest)
+		assert.True(t, paused.Paused)
+
+		resumed, errTest := client.ResumeSubscription(sub.ID)
+		require.NoError(t, errTest)
+		assert.False(t, resumed.Paused)
+	})
+}
+
+func TestGetSubscriptionStats(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	defer store.CloseConnection(t, sqlStore)
+
+	router := mux.NewRouter()
+	api.Register(router, &api.Context{
+		Store:      sqlStore,
+		Supervisor: &mockSupervisor{},
+		Metrics:    &mockMetrics{},
+		Logger:     logger,
+	})
+
+	ts := httptest.NewServer(router)
+	client := model.NewClient(ts.URL)
+
+	sub, err := client.CreateSubscription(&model.CreateSubscriptionRequest{
+		URL:       "https://test",
+		OwnerID:   "tester",
+		EventType: model.ResourceStateChangeEventType,
+	})
+	require.NoError(t, err)
+
+	t.Run("unknown subscription", func(t *testing.T) {
+		stats, errTest := client.GetSubscriptionStats(model.NewID())
+		require.NoError(t, errTest)
+		assert.Nil(t, stats)
+	})
+
+	t.Run("no events", func(t *testing.T) {
+		stats, errTest := client.GetSubscriptionStats(sub.ID)
+		require.NoError(t, errTest)
+		assert.Equal(t, &model.SubscriptionStats{SubscriptionID: sub.ID}, stats)
+	})
+
+	eventData := &model.StateChangeEventData{
+		Event: model.Event{
+			EventType: model.ResourceStateChangeEventType,
+			Timestamp: model.GetMillis(),
+		},
+		StateChange: model.StateChangeEvent{
+			OldState:     "old",
+			NewState:     "new",
+			ResourceID:   "installation1",
+			ResourceType: model.TypeInstallation,
+		},
+	}
+	err = sqlStore.CreateStateChangeEvent(eventData)
+	require.NoError(t, err)
+
+	t.Run("pending event", func(t *testing.T) {
+		stats, errTest := client.GetSubscriptionStats(sub.ID)
+		require.NoError(t, errTest)
+		assert.Equal(t, int64(1), stats.Backlog)
+		assert.Equal(t, eventData.Event.Timestamp, stats.OldestUndeliveredEventAt)
+	})
+}
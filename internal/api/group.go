@@ -157,6 +157,7 @@ func handleUpdateGroup(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	oldGroup := groupDTO.Group.Clone()
 	if patchGroupRequest.Apply(groupDTO.Group) {
 		err := c.Store.UpdateGroup(groupDTO.Group, patchGroupRequest.ForceSequenceUpdate)
 		if err != nil {
@@ -164,6 +165,13 @@ func handleUpdateGroup(c *Context, w http.ResponseWriter, r *http.Request) {
 			w.WriteHeader(http.StatusInternalServerError)
 			return
 		}
+
+		if data := model.NewGroupConfigChangeEventData(oldGroup, groupDTO.Group); data != nil {
+			err = c.EventProducer.ProduceGroupConfigChangeEvent(groupDTO.Group, data)
+			if err != nil {
+				c.Logger.WithError(err).Error("Failed to create group config change event")
+			}
+		}
 	}
 
 	c.Supervisor.Do()
@@ -322,6 +330,8 @@ func handleAddGroupAnnotations(c *Context, w http.ResponseWriter, r *http.Reques
 	}
 
 	groupDTO.Annotations = append(groupDTO.Annotations, annotations...)
+	produceAnnotationChangeEvent(c, model.TypeGroup, groupID, model.NonApplicableState,
+		&model.AnnotationChangeEventData{Added: model.GetAnnotationsNames(annotations)})
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
@@ -358,6 +368,8 @@ func handleDeleteGroupAnnotation(c *Context, w http.ResponseWriter, r *http.Requ
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
+	produceAnnotationChangeEvent(c, model.TypeGroup, groupID, model.NonApplicableState,
+		&model.AnnotationChangeEventData{Removed: []string{annotationName}})
 
 	w.WriteHeader(http.StatusNoContent)
 }
@@ -32,6 +32,8 @@ func initInstallation(apiRouter *mux.Router, context *Context) {
 	initInstallationBackup(installationsRouter, context)
 	initInstallationRestoration(installationsRouter, context)
 	initInstallationDBMigration(installationsRouter, context)
+	initInstallationFilestoreMigration(installationsRouter, context)
+	initInstallationUsage(installationsRouter, context)
 
 	installationsRouter.Handle("", addContext(handleGetInstallations)).Methods("GET")
 	installationsRouter.Handle("", addContext(handleCreateInstallation)).Methods("POST")
@@ -56,6 +58,7 @@ func initInstallation(apiRouter *mux.Router, context *Context) {
 	installationRouter.Handle("/deletion/schedule", addContext(handleUpdateInstallationScheduledDeletion)).Methods("PUT")
 	installationRouter.Handle("/annotations", addContext(handleAddInstallationAnnotations)).Methods("POST")
 	installationRouter.Handle("/annotation/{annotation-name}", addContext(handleDeleteInstallationAnnotation)).Methods("DELETE")
+	installationRouter.Handle("/usage", addContext(handleGetInstallationUsage)).Methods("GET")
 
 	// DNS manipulation
 	installationRouter.Handle("/dns", addContext(handleAddDNSRecord)).Methods("POST")
@@ -189,6 +192,11 @@ func handleCreateInstallation(c *Context, w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	if status := checkInstallationSize(c, createInstallationRequest.Size); status != 0 {
+		w.WriteHeader(status)
+		return
+	}
+
 	if createInstallationRequest.GroupID == "" && len(createInstallationRequest.GroupSelectionAnnotations) > 0 {
 		var groupID string
 		groupID, err = selectGroupForAnnotation(c, createInstallationRequest.GroupSelectionAnnotations)
@@ -245,6 +253,7 @@ func handleCreateInstallation(c *Context, w http.ResponseWriter, r *http.Request
 		ExternalDatabaseConfig:     createInstallationRequest.ExternalDatabaseConfig.ToDBConfig(createInstallationRequest.Database),
 		PodProbeOverrides:          createInstallationRequest.PodProbeOverrides,
 		Command:                    createInstallationRequest.Command,
+		NetworkPolicyExceptions:    createInstallationRequest.NetworkPolicyExceptions,
 		CRVersion:                  model.DefaultCRVersion,
 		State:                      model.InstallationStateCreationRequested,
 	}
@@ -373,6 +382,13 @@ func handleUpdateInstallation(c *Context, w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	if patchInstallationRequest.Size != nil {
+		if status := checkInstallationSize(c, *patchInstallationRequest.Size); status != 0 {
+			w.WriteHeader(status)
+			return
+		}
+	}
+
 	newState := model.InstallationStateUpdateRequested
 
 	installationDTO, status, unlockOnce := getInstallationForTransition(c, installationID, newState)
@@ -383,6 +399,7 @@ func handleUpdateInstallation(c *Context, w http.ResponseWriter, r *http.Request
 	defer unlockOnce()
 
 	oldState := installationDTO.State
+	oldInstallation := installationDTO.Installation.Clone()
 
 	if patchInstallationRequest.Apply(installationDTO.Installation) {
 		installationDTO.State = newState
@@ -398,6 +415,13 @@ func handleUpdateInstallation(c *Context, w http.ResponseWriter, r *http.Request
 		if err != nil {
 			c.Logger.WithError(err).Error("Failed to create installation state change event")
 		}
+
+		if data := model.NewInstallationSpecUpdateEventData(oldInstallation, installationDTO.Installation); data != nil {
+			err = c.EventProducer.ProduceInstallationSpecUpdateEvent(installationDTO.Installation, data)
+			if err != nil {
+				c.Logger.WithError(err).Error("Failed to create installation spec update event")
+			}
+		}
 	}
 
 	unlockOnce()
@@ -785,6 +809,13 @@ func handleWakeupInstallation(c *Context, w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	if patchInstallationRequest.Size != nil {
+		if status := checkInstallationSize(c, *patchInstallationRequest.Size); status != 0 {
+			w.WriteHeader(status)
+			return
+		}
+	}
+
 	newState := model.InstallationStateWakeUpRequested
 
 	installationDTO, status, unlockOnce := getInstallationForTransition(c, installationID, newState)
@@ -1069,6 +1100,8 @@ func handleAddInstallationAnnotations(c *Context, w http.ResponseWriter, r *http
 	}
 
 	installationDTO.Annotations = append(installationDTO.Annotations, annotations...)
+	produceAnnotationChangeEvent(c, model.TypeInstallation, installationID, installationDTO.State,
+		&model.AnnotationChangeEventData{Added: model.GetAnnotationsNames(annotations)})
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
@@ -1105,6 +1138,8 @@ func handleDeleteInstallationAnnotation(c *Context, w http.ResponseWriter, r *ht
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
+	produceAnnotationChangeEvent(c, model.TypeInstallation, installationID, installationDTO.State,
+		&model.AnnotationChangeEventData{Removed: []string{annotationName}})
 
 	w.WriteHeader(http.StatusNoContent)
 }
@@ -1190,3 +1225,24 @@ func getInstallationForTransition(c *Context, installationID, newState string) (
 
 	return installationDTO, 0, unlockOnce
 }
+
+// checkInstallationSize returns an error status if the size is neither an
+// Operator or provisioner size, nor the name of an existing size profile.
+func checkInstallationSize(c *Context, size string) int {
+	_, err := model.GetInstallationSize(size)
+	if err == nil {
+		return 0
+	}
+
+	profile, err := c.Store.GetSizeProfileByName(model.SizeProfileName(size))
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to get size profile")
+		return http.StatusInternalServerError
+	}
+	if profile == nil {
+		c.Logger.Errorf("unrecognized installation size %q", size)
+		return http.StatusBadRequest
+	}
+
+	return 0
+}
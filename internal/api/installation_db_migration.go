@@ -10,7 +10,6 @@ import (
 
 	"github.com/gorilla/mux"
 	"github.com/mattermost/mattermost-cloud/internal/common"
-	"github.com/mattermost/mattermost-cloud/internal/tools/aws"
 	"github.com/mattermost/mattermost-cloud/internal/webhook"
 	"github.com/mattermost/mattermost-cloud/model"
 	"github.com/pkg/errors"
@@ -283,9 +282,8 @@ func handleRollbackInstallationDatabaseMigration(c *Context, w http.ResponseWrit
 }
 
 func validateDBMigration(c *Context, installation *model.Installation, migrationRequest *model.InstallationDBMigrationRequest, currentDB *model.MultitenantDatabase) error {
-	if migrationRequest.DestinationDatabase != model.InstallationDatabaseMultiTenantRDSPostgres ||
-		installation.Database != model.InstallationDatabaseMultiTenantRDSPostgres {
-		return errors.Errorf("db migration is supported only when both source and destination are %q database types", model.InstallationDatabaseMultiTenantRDSPostgres)
+	if !model.IsSupportedDBMigration(installation.Database, migrationRequest.DestinationDatabase) {
+		return errors.Errorf("db migration from %q to %q database is not supported", installation.Database, migrationRequest.DestinationDatabase)
 	}
 
 	if migrationRequest.DestinationMultiTenant == nil {
@@ -308,7 +306,13 @@ func validateDBMigration(c *Context, installation *model.Installation, migration
 		return errors.New("databases VPCs do not match, only migration inside the same VPC is supported")
 	}
 
-	err = common.ValidateDBMigrationDestination(c.Store, destinationDB, installation.ID, aws.DefaultRDSMultitenantDatabasePostgresCountLimit)
+	if currentDB.DatabaseType != destinationDB.DatabaseType {
+		return errors.Errorf("destination database type %q does not match current database type %q", destinationDB.DatabaseType, currentDB.DatabaseType)
+	}
+
+	maxWeight := float64(c.DBProvider.GetMultitenantDatabaseMaxInstallations(destinationDB.DatabaseType))
+
+	err = common.ValidateDBMigrationDestination(c.Store, destinationDB, installation.ID, maxWeight)
 	if err != nil {
 		return errors.Wrap(err, "destination database validation failed")
 	}
@@ -5,6 +5,7 @@
 package api_test
 
 import (
+	"fmt"
 	"net/http/httptest"
 	"testing"
 	"time"
@@ -34,6 +35,7 @@ func TestTriggerInstallationDBMigration(t *testing.T) {
 		EventProducer: testutil.SetupTestEventsProducer(sqlStore, logger),
 		Metrics:       &mockMetrics{},
 		Logger:        logger,
+		DBProvider:    &dbProviderMock{},
 	})
 
 	ts := httptest.NewServer(router)
@@ -139,6 +141,25 @@ func TestTriggerInstallationDBMigration(t *testing.T) {
 		assert.Contains(t, errTest.Error(), "400")
 	})
 
+	t.Run("fail to trigger migration if destination database engine does not match", func(t *testing.T) {
+		destinationDB := &model.MultitenantDatabase{
+			ID:           "database4",
+			VpcID:        "vpc1",
+			DatabaseType: model.DatabaseEngineTypeMySQL,
+		}
+		errTest := sqlStore.CreateMultitenantDatabase(destinationDB)
+		require.NoError(t, errTest)
+
+		migrationRequest := &model.InstallationDBMigrationRequest{
+			InstallationID:         installation1.ID,
+			DestinationDatabase:    model.InstallationDatabaseMultiTenantRDSPostgres,
+			DestinationMultiTenant: &model.MultiTenantDBMigrationData{DatabaseID: "database4"},
+		}
+		_, errTest = client.MigrateInstallationDatabase(migrationRequest)
+		require.Error(t, errTest)
+		assert.Contains(t, errTest.Error(), "400")
+	})
+
 	t.Run("fail to trigger migration if destination database in different vpc", func(t *testing.T) {
 		destinationDB := &model.MultitenantDatabase{
 			ID:           "database3",
@@ -157,6 +178,38 @@ func TestTriggerInstallationDBMigration(t *testing.T) {
 		require.Error(t, errTest)
 		assert.Contains(t, errTest.Error(), "400")
 	})
+
+	t.Run("fail to trigger migration if destination database reached the provider limit", func(t *testing.T) {
+		var installationIDs model.MultitenantDatabaseInstallations
+		for i := 0; i < 10; i++ {
+			installation := &model.Installation{
+				Name:     fmt.Sprintf("full%d", i),
+				State:    model.InstallationStateStable,
+				Database: model.InstallationDatabaseMultiTenantRDSPostgres,
+			}
+			errTest := sqlStore.CreateInstallation(installation, nil, nil)
+			require.NoError(t, errTest)
+			installationIDs = append(installationIDs, installation.ID)
+		}
+
+		destinationDB := &model.MultitenantDatabase{
+			ID:            "database5",
+			VpcID:         "vpc1",
+			DatabaseType:  model.DatabaseEngineTypePostgres,
+			Installations: installationIDs,
+		}
+		errTest := sqlStore.CreateMultitenantDatabase(destinationDB)
+		require.NoError(t, errTest)
+
+		migrationRequest := &model.InstallationDBMigrationRequest{
+			InstallationID:         installation1.ID,
+			DestinationDatabase:    model.InstallationDatabaseMultiTenantRDSPostgres,
+			DestinationMultiTenant: &model.MultiTenantDBMigrationData{DatabaseID: "database5"},
+		}
+		_, errTest = client.MigrateInstallationDatabase(migrationRequest)
+		require.Error(t, errTest)
+		assert.Contains(t, errTest.Error(), "400")
+	})
 }
 
 func TestGetInstallationDBMigrationOperations(t *testing.T) {
@@ -297,6 +350,10 @@ func (dbp *dbProviderMock) GetDatabase(installationID, dbType string) model.Data
 	return dbp.mock
 }
 
+func (dbp *dbProviderMock) GetMultitenantDatabaseMaxInstallations(databaseType string) int {
+	return 10
+}
+
 func TestCommitInstallationDBMigrationOperation(t *testing.T) {
 	logger := testlib.MakeLogger(t)
 	sqlStore := store.MakeTestSQLStore(t, logger)
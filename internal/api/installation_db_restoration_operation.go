@@ -22,6 +22,7 @@ func initInstallationRestoration(apiRouter *mux.Router, context *Context) {
 
 	restorationsRouter.Handle("", addContext(handleTriggerInstallationDBRestoration)).Methods("POST")
 	restorationsRouter.Handle("", addContext(handleGetInstallationDBRestorationOperations)).Methods("GET")
+	restorationsRouter.Handle("/point-in-time", addContext(handleTriggerInstallationDBPointInTimeRestoration)).Methods("POST")
 
 	restorationRouter := apiRouter.PathPrefix("/operations/database/restoration/{restoration:[A-Za-z0-9]{26}}").Subrouter()
 	restorationRouter.Handle("", addContext(handleGetInstallationDBRestorationOperation)).Methods("GET")
@@ -79,6 +80,52 @@ func handleTriggerInstallationDBRestoration(c *Context, w http.ResponseWriter, r
 	outputJSON(c, w, dbRestoration)
 }
 
+// handleTriggerInstallationDBPointInTimeRestoration responds to POST /api/installations/operations/database/restorations/point-in-time,
+// requests restoration of Installation's database to a point in time.
+func handleTriggerInstallationDBPointInTimeRestoration(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.Logger = c.Logger.
+		WithField("action", "restore-installation-database-point-in-time")
+
+	restoreRequest, err := model.NewInstallationDBPointInTimeRestorationRequestFromReader(r.Body)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to decode request")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	err = restoreRequest.Validate()
+	if err != nil {
+		c.Logger.WithError(err).Error("invalid point-in-time restoration request")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	c.Logger = c.Logger.
+		WithField("installation", restoreRequest.InstallationID).
+		WithField("restore-time", restoreRequest.RestoreTime)
+
+	newState := model.InstallationStateDBRestorationInProgress
+
+	installationDTO, status, unlockOnce := getInstallationForTransition(c, restoreRequest.InstallationID, newState)
+	if status != 0 {
+		w.WriteHeader(status)
+		return
+	}
+	defer unlockOnce()
+
+	dbRestoration, err := common.TriggerInstallationDBPointInTimeRestoration(c.Store, installationDTO.Installation, restoreRequest.RestoreTime, c.EventProducer, c.Environment, c.Logger)
+	if err != nil {
+		c.Logger.WithError(err).Error("Failed to trigger installation db point-in-time restoration")
+		w.WriteHeader(common.ErrToStatus(err))
+		return
+	}
+
+	unlockOnce()
+	c.Supervisor.Do()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	outputJSON(c, w, dbRestoration)
+}
+
 // handleGetInstallationDBRestorationOperations responds to GET /api/installations/operations/database/restorations,
 // returns list of installation restoration operation.
 func handleGetInstallationDBRestorationOperations(c *Context, w http.ResponseWriter, r *http.Request) {
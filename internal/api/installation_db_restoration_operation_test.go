@@ -43,7 +43,7 @@ func TestTriggerInstallationDBRestoration(t *testing.T) {
 			Filestore: model.InstallationFilestoreBifrost,
 		})
 	require.NoError(t, err)
-	backup1 := &model.InstallationBackup{InstallationID: installation1.ID, State: model.InstallationBackupStateBackupSucceeded}
+	backup1 := &model.InstallationBackup{InstallationID: installation1.ID, BackedUpDatabaseType: installation1.Database, State: model.InstallationBackupStateBackupSucceeded}
 	err = sqlStore.CreateInstallationBackup(backup1)
 	require.NoError(t, err)
 
@@ -68,6 +68,7 @@ func handleAddDNSRecord(c *Context, w http.ResponseWriter, r *http.Request) {
 		c.Logger.WithError(err).Error("Failed to create installation state change event")
 	}
 	installationDTO.DNSRecords = append(installationDTO.DNSRecords, dnsRecord)
+	produceDNSRecordChangeEvent(c, installationDTO.Installation, &model.DNSRecordChangeEventData{Added: []string{dnsRecord.DomainName}})
 
 	unlockOnce()
 	c.Supervisor.Do()
@@ -219,6 +220,7 @@ func handleDeleteDNSRecord(c *Context, w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		c.Logger.WithError(err).Error("Failed to create installation state change event")
 	}
+	produceDNSRecordChangeEvent(c, installationDTO.Installation, &model.DNSRecordChangeEventData{Removed: []string{installationDNS.DomainName}})
 
 	unlockOnce()
 
@@ -236,3 +238,10 @@ func handleDeleteDNSRecord(c *Context, w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusAccepted)
 	outputJSON(c, w, installationDTO)
 }
+
+func produceDNSRecordChangeEvent(c *Context, installation *model.Installation, data *model.DNSRecordChangeEventData) {
+	err := c.EventProducer.ProduceInstallationDNSRecordChangeEvent(installation, data)
+	if err != nil {
+		c.Logger.WithError(err).Error("Failed to create DNS record change event")
+	}
+}
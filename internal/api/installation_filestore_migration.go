@@ -0,0 +1,272 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost-cloud/internal/webhook"
+	"github.com/mattermost/mattermost-cloud/model"
+)
+
+// initInstallationFilestoreMigration registers installation filestore migration operation endpoints on the given router.
+func initInstallationFilestoreMigration(apiRouter *mux.Router, context *Context) {
+	addContext := func(handler contextHandlerFunc) *contextHandler {
+		return newContextHandler(context, handler)
+	}
+
+	migrationsRouter := apiRouter.PathPrefix("/operations/filestore/migrations").Subrouter()
+
+	migrationsRouter.Handle("", addContext(handleTriggerInstallationFilestoreMigration)).Methods("POST")
+	migrationsRouter.Handle("", addContext(handleGetInstallationFilestoreMigrationOperations)).Methods("GET")
+
+	migrationRouter := apiRouter.PathPrefix("/operations/filestore/migration/{migration:[A-Za-z0-9]{26}}").Subrouter()
+	migrationRouter.Handle("", addContext(handleGetInstallationFilestoreMigrationOperation)).Methods("GET")
+	migrationRouter.Handle("/commit", addContext(handleCommitInstallationFilestoreMigration)).Methods("POST")
+	migrationRouter.Handle("/rollback", addContext(handleRollbackInstallationFilestoreMigration)).Methods("POST")
+}
+
+// handleTriggerInstallationFilestoreMigration responds to POST /api/installations/operations/filestore/migrations,
+// requests migration of Installation's files to a different filestore.
+func handleTriggerInstallationFilestoreMigration(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.Logger = c.Logger.WithField("action", "migrate-installation-filestore")
+
+	migrationRequest, err := model.NewInstallationFilestoreMigrationRequestFromReader(r.Body)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to decode request")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	err = migrationRequest.Validate()
+	if err != nil {
+		c.Logger.WithError(err).Error("invalid filestore migration request")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	c.Logger = c.Logger.WithField("installation", migrationRequest.InstallationID)
+
+	newState := model.InstallationStateFilestoreMigrationInProgress
+
+	installationDTO, status, unlockOnce := getInstallationForTransition(c, migrationRequest.InstallationID, newState)
+	if status != 0 {
+		w.WriteHeader(status)
+		return
+	}
+	defer unlockOnce()
+
+	if !model.IsSupportedFilestoreMigration(installationDTO.Filestore, migrationRequest.DestinationFilestore) {
+		c.Logger.Errorf("Filestore migration from %s to %s is not supported", installationDTO.Filestore, migrationRequest.DestinationFilestore)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	filestoreMigrations, err := c.Store.GetInstallationFilestoreMigrationOperations(&model.InstallationFilestoreMigrationFilter{
+		Paging:         model.AllPagesNotDeleted(),
+		InstallationID: installationDTO.ID,
+		States:         []model.InstallationFilestoreMigrationOperationState{model.InstallationFilestoreMigrationStateSucceeded},
+	})
+	if err != nil {
+		c.Logger.WithError(err).Error("Failed to query succeeded installation filestore migrations")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if len(filestoreMigrations) > 0 {
+		c.Logger.Error("Filestore migration cannot be started if other successful migration is not committed")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	filestoreMigrationOperation := &model.InstallationFilestoreMigrationOperation{
+		InstallationID:       migrationRequest.InstallationID,
+		SourceFilestore:      installationDTO.Filestore,
+		DestinationFilestore: migrationRequest.DestinationFilestore,
+	}
+
+	oldInstallationState := installationDTO.State
+
+	filestoreMigrationOperation, err = c.Store.TriggerInstallationFilestoreMigration(filestoreMigrationOperation, installationDTO.Installation)
+	if err != nil {
+		c.Logger.WithError(err).Error("Failed to trigger filestore migration operation")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	webhookPayload := &model.WebhookPayload{
+		Type:      model.TypeInstallationFilestoreMigration,
+		ID:        filestoreMigrationOperation.ID,
+		NewState:  string(filestoreMigrationOperation.State),
+		OldState:  "n/a",
+		Timestamp: time.Now().UnixNano(),
+		ExtraData: map[string]string{"Installation": filestoreMigrationOperation.InstallationID, "Environment": c.Environment},
+	}
+	err = webhook.SendToAllWebhooks(c.Store, webhookPayload, c.Logger.WithField("webhookEvent", webhookPayload.NewState))
+	if err != nil {
+		c.Logger.WithError(err).Error("Unable to process and send webhooks")
+	}
+
+	err = c.EventProducer.ProduceInstallationStateChangeEvent(installationDTO.Installation, oldInstallationState)
+	if err != nil {
+		c.Logger.WithError(err).Error("Failed to create installation state change event")
+	}
+
+	unlockOnce()
+	c.Supervisor.Do()
+
+	w.WriteHeader(http.StatusAccepted)
+	outputJSON(c, w, filestoreMigrationOperation)
+}
+
+// handleGetInstallationFilestoreMigrationOperations responds to GET /api/installations/operations/filestore/migrations,
+// returns list of installation filestore migration operations.
+func handleGetInstallationFilestoreMigrationOperations(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.Logger = c.Logger.
+		WithField("action", "list-installation-filestore-migrations")
+
+	paging, err := parsePaging(r.URL)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to parse paging parameters")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	installationID := r.URL.Query().Get("installation")
+	state := r.URL.Query().Get("state")
+	var states []model.InstallationFilestoreMigrationOperationState
+	if state != "" {
+		states = append(states, model.InstallationFilestoreMigrationOperationState(state))
+	}
+
+	filestoreMigrations, err := c.Store.GetInstallationFilestoreMigrationOperations(&model.InstallationFilestoreMigrationFilter{
+		Paging:         paging,
+		InstallationID: installationID,
+		States:         states,
+	})
+	if err != nil {
+		c.Logger.WithError(err).Error("Failed to list installation filestore migrations")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, filestoreMigrations)
+}
+
+// handleGetInstallationFilestoreMigrationOperation responds to GET /api/installations/operations/filestore/migration/{migration},
+// returns specified installation filestore migration operation.
+func handleGetInstallationFilestoreMigrationOperation(c *Context, w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	migrationID := vars["migration"]
+
+	c.Logger = c.Logger.
+		WithField("action", "get-installation-filestore-migration").
+		WithField("migration-operation", migrationID)
+
+	filestoreMigrationOp, err := c.Store.GetInstallationFilestoreMigrationOperation(migrationID)
+	if err != nil {
+		c.Logger.WithError(err).Error("Failed to get installation filestore migration")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if filestoreMigrationOp == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, filestoreMigrationOp)
+}
+
+// handleCommitInstallationFilestoreMigration responds to POST /api/installations/operations/filestore/migration/{migration}/commit,
+// commits filestore migration and schedules cleanup of the source filestore.
+func handleCommitInstallationFilestoreMigration(c *Context, w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	migrationID := vars["migration"]
+
+	c.Logger = c.Logger.WithField("action", "commit-installation-filestore-migration").
+		WithField("migration-operation", migrationID)
+
+	filestoreMigrationOperation, status, unlockOnce := lockInstallationFilestoreMigrationOperation(c, migrationID)
+	if status != 0 {
+		w.WriteHeader(status)
+		return
+	}
+	defer unlockOnce()
+
+	newState := model.InstallationFilestoreMigrationStateCommitRequested
+
+	if !filestoreMigrationOperation.ValidTransitionState(newState) {
+		c.Logger.Warn("Cannot commit filestore migration that hasn't succeeded")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	filestoreMigrationOperation.State = newState
+	err := c.Store.UpdateInstallationFilestoreMigrationOperationState(filestoreMigrationOperation)
+	if err != nil {
+		c.Logger.WithError(err).Error("Failed to set operation status to commit requested")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	unlockOnce()
+	c.Supervisor.Do()
+
+	w.WriteHeader(http.StatusAccepted)
+	outputJSON(c, w, filestoreMigrationOperation)
+}
+
+// handleRollbackInstallationFilestoreMigration responds to POST /api/installations/operations/filestore/migration/{migration}/rollback,
+// rollbacks filestore migration.
+func handleRollbackInstallationFilestoreMigration(c *Context, w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	migrationID := vars["migration"]
+
+	c.Logger = c.Logger.WithField("action", "rollback-installation-filestore-migration").
+		WithField("migration-operation", migrationID)
+
+	filestoreMigrationOperation, status, unlockOnce := lockInstallationFilestoreMigrationOperation(c, migrationID)
+	if status != 0 {
+		w.WriteHeader(status)
+		return
+	}
+	defer unlockOnce()
+
+	newState := model.InstallationFilestoreMigrationStateRollbackRequested
+
+	if !filestoreMigrationOperation.ValidTransitionState(newState) {
+		c.Logger.Warn("Cannot rollback filestore migration, invalid state")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	installationDTO, status, unlockInstOnce := lockInstallation(c, filestoreMigrationOperation.InstallationID)
+	if status != 0 {
+		w.WriteHeader(status)
+		return
+	}
+	defer unlockInstOnce()
+
+	if installationDTO.State != model.InstallationStateHibernating {
+		c.Logger.Error("Installation needs to be hibernated to be rolled back")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	err := c.Store.TriggerInstallationFilestoreMigrationRollback(filestoreMigrationOperation, installationDTO.Installation)
+	if err != nil {
+		c.Logger.WithError(err).Error("Failed to trigger filestore migration rollback")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	unlockOnce()
+	c.Supervisor.Do()
+
+	w.WriteHeader(http.StatusAccepted)
+	outputJSON(c, w, filestoreMigrationOperation)
+}
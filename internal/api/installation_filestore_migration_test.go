@@ -0,0 +1,241 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package api_test
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost-cloud/internal/api"
+	"github.com/mattermost/mattermost-cloud/internal/store"
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/internal/testutil"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTriggerInstallationFilestoreMigration(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	defer store.CloseConnection(t, sqlStore)
+
+	router := mux.NewRouter()
+	api.Register(router, &api.Context{
+		Store:         sqlStore,
+		Supervisor:    &mockSupervisor{},
+		EventProducer: testutil.SetupTestEventsProducer(sqlStore, logger),
+		Metrics:       &mockMetrics{},
+		Logger:        logger,
+	})
+
+	ts := httptest.NewServer(router)
+	client := model.NewClient(ts.URL)
+	installation1, err := client.CreateInstallation(
+		&model.CreateInstallationRequest{
+			OwnerID:   "owner",
+			DNS:       "dns1.example.com",
+			Database:  model.InstallationDatabaseMultiTenantRDSPostgres,
+			Filestore: model.InstallationFilestoreAwsS3,
+		})
+	require.NoError(t, err)
+	installation1.State = model.InstallationStateHibernating
+	err = sqlStore.UpdateInstallation(installation1.Installation)
+	require.NoError(t, err)
+
+	migrationRequest := &model.InstallationFilestoreMigrationRequest{
+		InstallationID:       installation1.ID,
+		DestinationFilestore: model.InstallationFilestoreBifrost,
+	}
+
+	migrationOperation, err := client.MigrateInstallationFilestore(migrationRequest)
+	require.NoError(t, err)
+
+	assert.Equal(t, model.InstallationFilestoreMigrationStateRequested, migrationOperation.State)
+	assert.Equal(t, installation1.ID, migrationOperation.InstallationID)
+	assert.Equal(t, model.InstallationFilestoreAwsS3, migrationOperation.SourceFilestore)
+	assert.Equal(t, model.InstallationFilestoreBifrost, migrationOperation.DestinationFilestore)
+
+	installation, err := sqlStore.GetInstallation(installation1.ID, false, false)
+	require.NoError(t, err)
+	assert.Equal(t, model.InstallationStateFilestoreMigrationInProgress, installation.State)
+
+	t.Run("fail to trigger migration if state is not hibernating", func(t *testing.T) {
+		_, err = client.MigrateInstallationFilestore(migrationRequest)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "400")
+	})
+
+	installation1.State = model.InstallationStateHibernating
+	err = sqlStore.UpdateInstallation(installation1.Installation)
+	require.NoError(t, err)
+
+	t.Run("fail to trigger migration if other migration succeeded but not committed", func(t *testing.T) {
+		succeededMigration := &model.InstallationFilestoreMigrationOperation{State: model.InstallationFilestoreMigrationStateSucceeded, InstallationID: installation1.ID}
+		errTest := sqlStore.CreateInstallationFilestoreMigrationOperation(succeededMigration)
+		require.NoError(t, errTest)
+		defer func() {
+			errDefer := sqlStore.DeleteInstallationFilestoreMigrationOperation(succeededMigration.ID)
+			assert.NoError(t, errDefer)
+		}()
+
+		_, errTest = client.MigrateInstallationFilestore(migrationRequest)
+		require.Error(t, errTest)
+		assert.Contains(t, errTest.Error(), "400")
+	})
+
+	t.Run("fail to trigger migration if destination filestore not supported", func(t *testing.T) {
+		migrationRequest := &model.InstallationFilestoreMigrationRequest{
+			InstallationID:       installation1.ID,
+			DestinationFilestore: model.InstallationFilestoreMultiTenantAwsS3,
+		}
+		_, errTest := client.MigrateInstallationFilestore(migrationRequest)
+		require.Error(t, errTest)
+		assert.Contains(t, errTest.Error(), "400")
+	})
+}
+
+func TestGetInstallationFilestoreMigrationOperations(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	defer store.CloseConnection(t, sqlStore)
+
+	router := mux.NewRouter()
+	api.Register(router, &api.Context{
+		Store:      sqlStore,
+		Supervisor: &mockSupervisor{},
+		Metrics:    &mockMetrics{},
+		Logger:     logger,
+	})
+
+	ts := httptest.NewServer(router)
+	client := model.NewClient(ts.URL)
+
+	migrationOperations := []*model.InstallationFilestoreMigrationOperation{
+		{
+			InstallationID: "installation1",
+			State:          model.InstallationFilestoreMigrationStateRequested,
+		},
+		{
+			InstallationID: "installation1",
+			State:          model.InstallationFilestoreMigrationStateFailed,
+		},
+		{
+			InstallationID: "installation2",
+			State:          model.InstallationFilestoreMigrationStateRequested,
+		},
+	}
+
+	for i := range migrationOperations {
+		err := sqlStore.CreateInstallationFilestoreMigrationOperation(migrationOperations[i])
+		require.NoError(t, err)
+		time.Sleep(1 * time.Millisecond)
+	}
+
+	for _, testCase := range []struct {
+		description string
+		filter      model.GetInstallationFilestoreMigrationOperationsRequest
+		found       []*model.InstallationFilestoreMigrationOperation
+	}{
+		{
+			description: "all not deleted",
+			filter:      model.GetInstallationFilestoreMigrationOperationsRequest{Paging: model.AllPagesNotDeleted()},
+			found:       migrationOperations,
+		},
+		{
+			description: "filter by installation ID",
+			filter:      model.GetInstallationFilestoreMigrationOperationsRequest{Paging: model.AllPagesNotDeleted(), InstallationID: "installation1"},
+			found:       []*model.InstallationFilestoreMigrationOperation{migrationOperations[0], migrationOperations[1]},
+		},
+		{
+			description: "filter by state",
+			filter:      model.GetInstallationFilestoreMigrationOperationsRequest{Paging: model.AllPagesNotDeleted(), State: string(model.InstallationFilestoreMigrationStateRequested)},
+			found:       []*model.InstallationFilestoreMigrationOperation{migrationOperations[0], migrationOperations[2]},
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			operations, err := client.GetInstallationFilestoreMigrationOperations(&testCase.filter)
+			require.NoError(t, err)
+			require.Equal(t, len(testCase.found), len(operations))
+
+			for i := 0; i < len(testCase.found); i++ {
+				assert.Equal(t, testCase.found[i], operations[len(testCase.found)-1-i])
+			}
+		})
+	}
+
+	t.Run("get single operation", func(t *testing.T) {
+		fetchedOp, err := client.GetInstallationFilestoreMigrationOperation(migrationOperations[0].ID)
+		require.NoError(t, err)
+		assert.Equal(t, migrationOperations[0], fetchedOp)
+
+		_, err = client.GetInstallationFilestoreMigrationOperation(model.NewID())
+		require.EqualError(t, err, "failed with status code 404")
+	})
+}
+
+func TestCommitAndRollbackInstallationFilestoreMigrationOperation(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	defer store.CloseConnection(t, sqlStore)
+
+	router := mux.NewRouter()
+	api.Register(router, &api.Context{
+		Store:      sqlStore,
+		Supervisor: &mockSupervisor{},
+		Metrics:    &mockMetrics{},
+		Logger:     logger,
+	})
+
+	ts := httptest.NewServer(router)
+	client := model.NewClient(ts.URL)
+
+	installation := &model.Installation{
+		Name:  "name",
+		State: model.InstallationStateHibernating,
+	}
+	err := sqlStore.CreateInstallation(installation, nil, nil)
+	require.NoError(t, err)
+
+	t.Run("commit", func(t *testing.T) {
+		migrationOp := &model.InstallationFilestoreMigrationOperation{
+			InstallationID: installation.ID,
+			State:          model.InstallationFilestoreMigrationStateSucceeded,
+		}
+		err = sqlStore.CreateInstallationFilestoreMigrationOperation(migrationOp)
+		require.NoError(t, err)
+
+		committedOp, err := client.CommitInstallationFilestoreMigration(migrationOp.ID)
+		require.NoError(t, err)
+		assert.Equal(t, model.InstallationFilestoreMigrationStateCommitRequested, committedOp.State)
+
+		_, err = client.CommitInstallationFilestoreMigration(migrationOp.ID)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "400")
+	})
+
+	t.Run("rollback", func(t *testing.T) {
+		migrationOp := &model.InstallationFilestoreMigrationOperation{
+			InstallationID: installation.ID,
+			State:          model.InstallationFilestoreMigrationStateSucceeded,
+		}
+		err = sqlStore.CreateInstallationFilestoreMigrationOperation(migrationOp)
+		require.NoError(t, err)
+
+		rollbackOp, err := client.RollbackInstallationFilestoreMigration(migrationOp.ID)
+		require.NoError(t, err)
+		assert.Equal(t, model.InstallationFilestoreMigrationStateRollbackRequested, rollbackOp.State)
+
+		installation, err = sqlStore.GetInstallation(installation.ID, false, false)
+		require.NoError(t, err)
+		assert.Equal(t, model.InstallationStateFilestoreMigrationRollbackInProgress, installation.State)
+
+		_, err = client.RollbackInstallationFilestoreMigration(migrationOp.ID)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "400")
+	})
+}
@@ -0,0 +1,260 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package api
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+)
+
+// defaultUsageWindow is the time window of the usage reported when none is
+// requested.
+const defaultUsageWindow = 24 * time.Hour
+
+// initInstallationUsage registers installation usage endpoints on the given router.
+func initInstallationUsage(apiRouter *mux.Router, context *Context) {
+	addContext := func(handler contextHandlerFunc) *contextHandler {
+		return newContextHandler(context, handler)
+	}
+
+	apiRouter.Handle("/usage", addContext(handleGetUsageReport)).Methods("GET")
+	apiRouter.Handle("/recommendations", addContext(handleGetInstallationSizeRecommendations)).Methods("GET")
+}
+
+// handleGetInstallationUsage responds to GET /api/installation/{installation}/usage,
+// returning the resources used by the installation over a time window.
+func handleGetInstallationUsage(c *Context, w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	installationID := vars["installation"]
+	c.Logger = c.Logger.
+		WithField("installation", installationID).
+		WithField("action", "get-installation-usage")
+
+	from, to, err := parseUsageWindow(r.URL)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to parse usage window")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	includeHistory, err := parseBool(r.URL, "include_history", false)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to parse include_history")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	installation, err := c.Store.GetInstallation(installationID, false, false)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to get installation")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if installation == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	samples, err := c.Store.GetInstallationUsages(&model.InstallationUsageFilter{
+		InstallationID: installationID,
+		CreatedAfter:   from,
+		CreatedBefore:  to,
+	})
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to get installation usage")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	summary := summarizeInstallationUsage(c, installation, samples)
+	if includeHistory {
+		summary.History = samples
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, summary)
+}
+
+// handleGetUsageReport responds to GET /api/installations/usage, returning
+// the resources used by the installations of the fleet over a time window
+// grouped by owner or group.
+func handleGetUsageReport(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.Logger = c.Logger.WithField("action", "get-usage-report")
+
+	groupBy := parseString(r.URL, "group_by", model.UsageReportGroupByOwner)
+	if !model.IsValidUsageReportGroupBy(groupBy) {
+		c.Logger.Errorf("unsupported usage report grouping %s", groupBy)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	from, to, err := parseUsageWindow(r.URL)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to parse usage window")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	samples, err := c.Store.GetInstallationUsages(&model.InstallationUsageFilter{
+		CreatedAfter:  from,
+		CreatedBefore: to,
+	})
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to get installation usage")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	samplesByInstallation := map[string][]*model.InstallationUsage{}
+	var installationIDs []string
+	for _, sample := range samples {
+		if _, ok := samplesByInstallation[sample.InstallationID]; !ok {
+			installationIDs = append(installationIDs, sample.InstallationID)
+		}
+		samplesByInstallation[sample.InstallationID] = append(samplesByInstallation[sample.InstallationID], sample)
+	}
+
+	var summaries []*model.InstallationUsageSummary
+	if len(installationIDs) > 0 {
+		// Installations deleted during the window are still reported.
+		installations, err := c.Store.GetInstallations(&model.InstallationFilter{
+			InstallationIDs: installationIDs,
+			Paging:          model.AllPagesWithDeleted(),
+		}, false, false)
+		if err != nil {
+			c.Logger.WithError(err).Error("failed to get installations")
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		for _, installation := range installations {
+			summaries = append(summaries, summarizeInstallationUsage(c, installation, samplesByInstallation[installation.ID]))
+		}
+	}
+
+	report, err := model.NewUsageReport(groupBy, from, to, summaries)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to create usage report")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, report)
+}
+
+// handleGetInstallationSizeRecommendations responds to GET
+// /api/installations/recommendations, returning size recommendations for
+// stable installations based on their usage over the lookback window of the
+// recommendation policy.
+func handleGetInstallationSizeRecommendations(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.Logger = c.Logger.WithField("action", "get-installation-size-recommendations")
+
+	if c.SizeRecommendationPolicy == nil {
+		c.Logger.Error("no size recommendation policy is configured")
+		w.WriteHeader(http.StatusNotImplemented)
+		return
+	}
+
+	paging, err := parsePaging(r.URL)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to parse paging parameters")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	minConfidence, err := strconv.ParseFloat(parseString(r.URL, "min_confidence", "0"), 64)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to parse min_confidence")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	installations, err := c.Store.GetInstallations(&model.InstallationFilter{
+		Paging:  paging,
+		OwnerID: parseString(r.URL, "owner", ""),
+		GroupID: parseString(r.URL, "group", ""),
+		State:   model.InstallationStateStable,
+	}, false, false)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to get installations")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	recommendations := []*model.InstallationSizeRecommendation{}
+	if len(installations) > 0 {
+		installationIDs := make([]string, 0, len(installations))
+		for _, installation := range installations {
+			installationIDs = append(installationIDs, installation.ID)
+		}
+
+		samples, err := c.Store.GetInstallationUsages(&model.InstallationUsageFilter{
+			InstallationIDs: installationIDs,
+			CreatedAfter:    model.GetMillis() - c.SizeRecommendationPolicy.Lookback.Milliseconds(),
+		})
+		if err != nil {
+			c.Logger.WithError(err).Error("failed to get installation usage")
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		all, err := c.SizeRecommendationPolicy.RecommendInstallationSizes(installations, samples)
+		if err != nil {
+			c.Logger.WithError(err).Error("failed to recommend installation sizes")
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		for _, recommendation := range all {
+			if recommendation.Confidence >= minConfidence {
+				recommendations = append(recommendations, recommendation)
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, recommendations)
+}
+
+// summarizeInstallationUsage summarizes the usage samples of an installation
+// and estimates their cost when a price table is configured.
+func summarizeInstallationUsage(c *Context, installation *model.Installation, samples []*model.InstallationUsage) *model.InstallationUsageSummary {
+	summary := model.SummarizeInstallationUsage(installation.ID, samples)
+	summary.OwnerID = installation.OwnerID
+	if installation.GroupID != nil {
+		summary.GroupID = *installation.GroupID
+	}
+	if c.UsagePriceTable != nil {
+		summary.Cost = c.UsagePriceTable.EstimateMonthlyCost(summary)
+	}
+
+	return summary
+}
+
+// parseUsageWindow returns the time window of the requested usage, which
+// defaults to the last day.
+func parseUsageWindow(u *url.URL) (int64, int64, error) {
+	to, err := parseInt64(u, "to", model.GetMillis())
+	if err != nil {
+		return 0, 0, err
+	}
+	from, err := parseInt64(u, "from", to-defaultUsageWindow.Milliseconds())
+	if err != nil {
+		return 0, 0, err
+	}
+	if from > to {
+		return 0, 0, errors.New("usage window must not end before it starts")
+	}
+
+	return from, to, nil
+}
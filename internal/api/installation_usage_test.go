@@ -0,0 +1,150 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package api_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost-cloud/internal/api"
+	"github.com/mattermost/mattermost-cloud/internal/store"
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/internal/testutil"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstallationUsage(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	defer store.CloseConnection(t, sqlStore)
+
+	router := mux.NewRouter()
+	api.Register(router, &api.Context{
+		Store:         sqlStore,
+		Supervisor:    &mockSupervisor{},
+		EventProducer: testutil.SetupTestEventsProducer(sqlStore, logger),
+		Metrics:       &mockMetrics{},
+		Logger:        logger,
+		UsagePriceTable: &model.UsagePriceTable{
+			Currency:    "USD",
+			CPUCoreHour: 1,
+		},
+	})
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	client := model.NewClient(ts.URL)
+
+	installation1 := &model.Installation{Name: "usage1", OwnerID: "owner1"}
+	err := sqlStore.CreateInstallation(installation1, nil, nil)
+	require.NoError(t, err)
+	installation2 := &model.Installation{Name: "usage2", OwnerID: "owner2"}
+	err = sqlStore.CreateInstallation(installation2, nil, nil)
+	require.NoError(t, err)
+
+	for _, usage := range []*model.InstallationUsage{
+		{InstallationID: installation1.ID, MilliCPU: 1000, MemoryBytes: 100, FilestoreBytes: 10},
+		{InstallationID: installation1.ID, MilliCPU: 3000, MemoryBytes: 300, FilestoreBytes: 20},
+		{InstallationID: installation2.ID, MilliCPU: 500},
+	} {
+		err = sqlStore.CreateInstallationUsage(usage)
+		require.NoError(t, err)
+	}
+
+	t.Run("unknown installation", func(t *testing.T) {
+		summary, errTest := client.GetInstallationUsage(model.NewID(), &model.GetInstallationUsageRequest{})
+		require.NoError(t, errTest)
+		assert.Nil(t, summary)
+	})
+
+	t.Run("installation usage", func(t *testing.T) {
+		summary, errTest := client.GetInstallationUsage(installation1.ID, &model.GetInstallationUsageRequest{})
+		require.NoError(t, errTest)
+		assert.Equal(t, installation1.ID, summary.InstallationID)
+		assert.Equal(t, "owner1", summary.OwnerID)
+		assert.Equal(t, int64(2), summary.Samples)
+		assert.Equal(t, int64(2000), summary.AvgMilliCPU)
+		assert.Equal(t, int64(3000), summary.MaxMilliCPU)
+		assert.Equal(t, int64(200), summary.AvgMemoryBytes)
+		assert.Equal(t, int64(20), summary.FilestoreBytes)
+		assert.Empty(t, summary.History)
+		require.NotNil(t, summary.Cost)
+		assert.Equal(t, "USD", summary.Cost.Currency)
+		assert.Equal(t, 2.0*730, summary.Cost.CPU)
+	})
+
+	t.Run("installation usage with history", func(t *testing.T) {
+		summary, errTest := client.GetInstallationUsage(installation1.ID, &model.GetInstallationUsageRequest{IncludeHistory: true})
+		require.NoError(t, errTest)
+		require.Len(t, summary.History, 2)
+		assert.Equal(t, int64(1000), summary.History[0].MilliCPU)
+		assert.Equal(t, int64(3000), summary.History[1].MilliCPU)
+	})
+
+	t.Run("installation usage outside window", func(t *testing.T) {
+		summary, errTest := client.GetInstallationUsage(installation1.ID, &model.GetInstallationUsageRequest{From: 1, To: 2})
+		require.NoError(t, errTest)
+		assert.Equal(t, int64(0), summary.Samples)
+	})
+
+	t.Run("invalid installation usage requests", func(t *testing.T) {
+		_, errTest := client.GetInstallationUsage(installation1.ID, &model.GetInstallationUsageRequest{From: 2, To: 1})
+		require.EqualError(t, errTest, "failed with status code 400")
+
+		resp, errTest := http.Get(fmt.Sprintf("%s/api/installation/%s/usage?from=invalid", ts.URL, installation1.ID))
+		require.NoError(t, errTest)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		resp, errTest = http.Get(fmt.Sprintf("%s/api/installation/%s/usage?include_history=invalid", ts.URL, installation1.ID))
+		require.NoError(t, errTest)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("usage report by owner", func(t *testing.T) {
+		report, errTest := client.GetUsageReport(&model.GetUsageReportRequest{})
+		require.NoError(t, errTest)
+		assert.Equal(t, model.UsageReportGroupByOwner, report.GroupBy)
+		require.Len(t, report.Entries, 2)
+		assert.Equal(t, "owner1", report.Entries[0].Key)
+		assert.Equal(t, int64(2000), report.Entries[0].AvgMilliCPU)
+		assert.Equal(t, "owner2", report.Entries[1].Key)
+		assert.Equal(t, int64(500), report.Entries[1].AvgMilliCPU)
+	})
+
+	t.Run("usage report by group", func(t *testing.T) {
+		report, errTest := client.GetUsageReport(&model.GetUsageReportRequest{GroupBy: model.UsageReportGroupByGroup})
+		require.NoError(t, errTest)
+		require.Len(t, report.Entries, 1)
+		assert.Empty(t, report.Entries[0].Key)
+		assert.Equal(t, int64(2), report.Entries[0].Installations)
+	})
+
+	t.Run("usage report of deleted installation", func(t *testing.T) {
+		errTest := sqlStore.DeleteInstallation(installation2.ID)
+		require.NoError(t, errTest)
+
+		report, errTest := client.GetUsageReport(&model.GetUsageReportRequest{})
+		require.NoError(t, errTest)
+		assert.Len(t, report.Entries, 2)
+	})
+
+	t.Run("invalid usage report requests", func(t *testing.T) {
+		_, errTest := client.GetUsageReport(&model.GetUsageReportRequest{GroupBy: "cluster"})
+		require.EqualError(t, errTest, "failed with status code 400")
+
+		_, errTest = client.GetUsageReport(&model.GetUsageReportRequest{From: 2, To: 1})
+		require.EqualError(t, errTest, "failed with status code 400")
+	})
+
+	t.Run("recommendations without policy", func(t *testing.T) {
+		_, errTest := client.GetInstallationSizeRecommendations(&model.GetInstallationSizeRecommendationsRequest{Paging: model.AllPagesNotDeleted()})
+		require.EqualError(t, errTest, "failed with status code 501")
+	})
+}
@@ -222,6 +222,41 @@ func lockInstallationDBMigrationOperation(c *Context, operationID string) (*mode
 	}
 }
 
+// lockInstallationFilestoreMigrationOperation synchronizes access to the given filestore migration operation
+// across potentially multiple provisioning servers.
+func lockInstallationFilestoreMigrationOperation(c *Context, operationID string) (*model.InstallationFilestoreMigrationOperation, int, func()) {
+	filestoreMigrationOperation, err := c.Store.GetInstallationFilestoreMigrationOperation(operationID)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query filestore migration operation")
+		return nil, http.StatusInternalServerError, nil
+	}
+	if filestoreMigrationOperation == nil {
+		return nil, http.StatusNotFound, nil
+	}
+
+	locked, err := c.Store.LockInstallationFilestoreMigrationOperation(operationID, c.RequestID)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to lock filestore migration operation")
+		return nil, http.StatusInternalServerError, nil
+	} else if !locked {
+		c.Logger.Error("failed to acquire lock for filestore migration operation")
+		return nil, http.StatusConflict, nil
+	}
+
+	unlockOnce := sync.Once{}
+
+	return filestoreMigrationOperation, 0, func() {
+		unlockOnce.Do(func() {
+			unlocked, err := c.Store.UnlockInstallationFilestoreMigrationOperation(filestoreMigrationOperation.ID, c.RequestID, false)
+			if err != nil {
+				c.Logger.WithError(err).Errorf("failed to unlock filestore migration operation")
+			} else if !unlocked {
+				c.Logger.Warn("failed to release lock for filestore migration operation")
+			}
+		})
+	}
+}
+
 // lockDatabase synchronizes access to the given multitenant database across
 // potentially multiple provisioning servers.
 func lockDatabase(c *Context, databaseID string) (*model.MultitenantDatabase, int, func()) {
@@ -256,3 +291,38 @@ func lockDatabase(c *Context, databaseID string) (*model.MultitenantDatabase, in
 		})
 	}
 }
+
+// lockUtilityRollout synchronizes access to the given utility rollout across
+// potentially multiple provisioning servers.
+func lockUtilityRollout(c *Context, rolloutID string) (*model.UtilityRollout, int, func()) {
+	rollout, err := c.Store.GetUtilityRollout(rolloutID)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query utility rollout")
+		return nil, http.StatusInternalServerError, nil
+	}
+	if rollout == nil {
+		return nil, http.StatusNotFound, nil
+	}
+
+	locked, err := c.Store.LockUtilityRollouts([]string{rolloutID}, c.RequestID)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to lock utility rollout")
+		return nil, http.StatusInternalServerError, nil
+	} else if !locked {
+		c.Logger.Error("failed to acquire lock for utility rollout")
+		return nil, http.StatusConflict, nil
+	}
+
+	unlockOnce := sync.Once{}
+
+	return rollout, 0, func() {
+		unlockOnce.Do(func() {
+			unlocked, err := c.Store.UnlockUtilityRollouts([]string{rollout.ID}, c.RequestID, false)
+			if err != nil {
+				c.Logger.WithError(err).Errorf("failed to unlock utility rollout")
+			} else if !unlocked {
+				c.Logger.Warn("failed to release lock for utility rollout")
+			}
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"net/http"
 
 	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost-cloud/model"
 )
 
 // initSecurity registers security endpoints on the given router.
@@ -66,6 +67,7 @@ func handleClusterLockAPI(c *Context, w http.ResponseWriter, r *http.Request) {
 			w.WriteHeader(http.StatusInternalServerError)
 			return
 		}
+		produceLockChangeEvent(c, model.TypeCluster, cluster.ID, cluster.State, true)
 	}
 
 	w.WriteHeader(http.StatusOK)
@@ -96,6 +98,7 @@ func handleClusterUnlockAPI(c *Context, w http.ResponseWriter, r *http.Request)
 			w.WriteHeader(http.StatusInternalServerError)
 			return
 		}
+		produceLockChangeEvent(c, model.TypeCluster, cluster.ID, cluster.State, false)
 	}
 
 	w.WriteHeader(http.StatusOK)
@@ -126,6 +129,7 @@ func handleInstallationLockAPI(c *Context, w http.ResponseWriter, r *http.Reques
 			w.WriteHeader(http.StatusInternalServerError)
 			return
 		}
+		produceLockChangeEvent(c, model.TypeInstallation, installation.ID, installation.State, true)
 	}
 
 	w.WriteHeader(http.StatusOK)
@@ -156,6 +160,7 @@ func handleInstallationUnlockAPI(c *Context, w http.ResponseWriter, r *http.Requ
 			w.WriteHeader(http.StatusInternalServerError)
 			return
 		}
+		produceLockChangeEvent(c, model.TypeInstallation, installation.ID, installation.State, false)
 	}
 
 	w.WriteHeader(http.StatusOK)
@@ -244,6 +249,7 @@ func handleClusterInstallationLockAPI(c *Context, w http.ResponseWriter, r *http
 			w.WriteHeader(http.StatusInternalServerError)
 			return
 		}
+		produceLockChangeEvent(c, model.TypeClusterInstallation, clusterInstallation.ID, clusterInstallation.State, true)
 	}
 
 	w.WriteHeader(http.StatusOK)
@@ -274,6 +280,7 @@ func handleClusterInstallationUnlockAPI(c *Context, w http.ResponseWriter, r *ht
 			w.WriteHeader(http.StatusInternalServerError)
 			return
 		}
+		produceLockChangeEvent(c, model.TypeClusterInstallation, clusterInstallation.ID, clusterInstallation.State, false)
 	}
 
 	w.WriteHeader(http.StatusOK)
@@ -304,6 +311,7 @@ func handleGroupLockAPI(c *Context, w http.ResponseWriter, r *http.Request) {
 			w.WriteHeader(http.StatusInternalServerError)
 			return
 		}
+		produceLockChangeEvent(c, model.TypeGroup, group.ID, model.NonApplicableState, true)
 	}
 
 	w.WriteHeader(http.StatusOK)
@@ -334,6 +342,7 @@ func handleGroupUnlockAPI(c *Context, w http.ResponseWriter, r *http.Request) {
 			w.WriteHeader(http.StatusInternalServerError)
 			return
 		}
+		produceLockChangeEvent(c, model.TypeGroup, group.ID, model.NonApplicableState, false)
 	}
 
 	w.WriteHeader(http.StatusOK)
@@ -364,6 +373,7 @@ func handleBackupLockAPI(c *Context, w http.ResponseWriter, r *http.Request) {
 			w.WriteHeader(http.StatusInternalServerError)
 			return
 		}
+		produceLockChangeEvent(c, model.TypeInstallationBackup, backupMetadata.ID, string(backupMetadata.State), true)
 	}
 
 	w.WriteHeader(http.StatusOK)
@@ -394,7 +404,15 @@ func handleBackupUnlockAPI(c *Context, w http.ResponseWriter, r *http.Request) {
 			w.WriteHeader(http.StatusInternalServerError)
 			return
 		}
+		produceLockChangeEvent(c, model.TypeInstallationBackup, backupMetadata.ID, string(backupMetadata.State), false)
 	}
 
 	w.WriteHeader(http.StatusOK)
 }
+
+func produceLockChangeEvent(c *Context, resourceType model.ResourceType, resourceID, state string, locked bool) {
+	err := c.EventProducer.ProduceLockChangeEvent(resourceType, resourceID, state, locked)
+	if err != nil {
+		c.Logger.WithError(err).Error("Failed to create lock change event")
+	}
+}
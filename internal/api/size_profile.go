@@ -0,0 +1,190 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost-cloud/model"
+)
+
+// initSizeProfile registers installation size profile endpoints on the given
+// router.
+func initSizeProfile(apiRouter *mux.Router, context *Context) {
+	addContext := func(handler contextHandlerFunc) *contextHandler {
+		return newContextHandler(context, handler)
+	}
+
+	sizesRouter := apiRouter.PathPrefix("/sizes").Subrouter()
+	sizesRouter.Handle("", addContext(handleGetSizeProfiles)).Methods("GET")
+	sizesRouter.Handle("", addContext(handleCreateSizeProfile)).Methods("POST")
+
+	sizeRouter := apiRouter.PathPrefix("/size/{size}").Subrouter()
+	sizeRouter.Handle("", addContext(handleGetSizeProfile)).Methods("GET")
+	sizeRouter.Handle("", addContext(handleUpdateSizeProfile)).Methods("PUT")
+	sizeRouter.Handle("", addContext(handleDeleteSizeProfile)).Methods("DELETE")
+}
+
+// handleGetSizeProfiles responds to GET /api/sizes, returning a list of size
+// profiles.
+func handleGetSizeProfiles(c *Context, w http.ResponseWriter, r *http.Request) {
+	paging, err := parsePaging(r.URL)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to parse paging parameters")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	profiles, err := c.Store.GetSizeProfiles(&model.SizeProfileFilter{Paging: paging})
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query size profiles")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, profiles)
+}
+
+// handleCreateSizeProfile responds to POST /api/sizes, creating a new size
+// profile.
+func handleCreateSizeProfile(c *Context, w http.ResponseWriter, r *http.Request) {
+	createRequest, err := model.NewCreateSizeProfileRequestFromReader(r.Body)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to decode request")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	c.Logger = c.Logger.WithField("size", createRequest.Name)
+
+	existing, err := c.Store.GetSizeProfileByName(createRequest.Name)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query size profile")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if existing != nil {
+		c.Logger.Error("size profile with the given name already exists")
+		w.WriteHeader(http.StatusConflict)
+		return
+	}
+
+	profile := &model.SizeProfile{
+		Name:        createRequest.Name,
+		Description: createRequest.Description,
+		Spec:        createRequest.Spec,
+	}
+
+	err = c.Store.CreateSizeProfile(profile)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to create size profile")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, profile)
+}
+
+// handleGetSizeProfile responds to GET /api/size/{size}, returning the size
+// profile in question.
+func handleGetSizeProfile(c *Context, w http.ResponseWriter, r *http.Request) {
+	profile, status := getSizeProfile(c, r)
+	if status != 0 {
+		w.WriteHeader(status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, profile)
+}
+
+// handleUpdateSizeProfile responds to PUT /api/size/{size}, updating the size
+// profile. Installations using the profile pick up the change the next time
+// they are reconciled.
+func handleUpdateSizeProfile(c *Context, w http.ResponseWriter, r *http.Request) {
+	profile, status := getSizeProfile(c, r)
+	if status != 0 {
+		w.WriteHeader(status)
+		return
+	}
+
+	patchRequest, err := model.NewPatchSizeProfileRequestFromReader(r.Body)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to decode request")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if patchRequest.Apply(profile) {
+		err = c.Store.UpdateSizeProfile(profile)
+		if err != nil {
+			c.Logger.WithError(err).Error("failed to update size profile")
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, profile)
+}
+
+// handleDeleteSizeProfile responds to DELETE /api/size/{size}, marking the
+// size profile as deleted. Profiles still used by installations cannot be
+// deleted.
+func handleDeleteSizeProfile(c *Context, w http.ResponseWriter, r *http.Request) {
+	profile, status := getSizeProfile(c, r)
+	if status != 0 {
+		w.WriteHeader(status)
+		return
+	}
+
+	installationCount, err := c.Store.GetInstallationsCount(&model.InstallationFilter{
+		Paging: model.AllPagesNotDeleted(),
+		Size:   profile.Name,
+	})
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query installations using size profile")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if installationCount > 0 {
+		c.Logger.Errorf("size profile is used by %d installations", installationCount)
+		w.WriteHeader(http.StatusConflict)
+		return
+	}
+
+	err = c.Store.DeleteSizeProfile(profile.ID)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to delete size profile")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// getSizeProfile fetches the size profile named in the request path,
+// returning a non-zero status code if it could not be found.
+func getSizeProfile(c *Context, r *http.Request) (*model.SizeProfile, int) {
+	name := mux.Vars(r)["size"]
+	c.Logger = c.Logger.WithField("size", name)
+
+	profile, err := c.Store.GetSizeProfileByName(name)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query size profile")
+		return nil, http.StatusInternalServerError
+	}
+	if profile == nil {
+		return nil, http.StatusNotFound
+	}
+
+	return profile, 0
+}
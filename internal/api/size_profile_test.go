@@ -0,0 +1,166 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package api_test
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost-cloud/internal/api"
+	"github.com/mattermost/mattermost-cloud/internal/store"
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/internal/util"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestSizeProfiles(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	defer store.CloseConnection(t, sqlStore)
+
+	router := mux.NewRouter()
+	api.Register(router, &api.Context{
+		Store:      sqlStore,
+		Supervisor: &mockSupervisor{},
+		Metrics:    &mockMetrics{},
+		Logger:     logger,
+	})
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	client := model.NewClient(ts.URL)
+
+	spec := &model.SizeProfileSpec{
+		App: model.SizeProfileComponent{
+			Replicas: 2,
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("500m"),
+					corev1.ResourceMemory: resource.MustParse("2Gi"),
+				},
+			},
+		},
+	}
+
+	t.Run("no profiles", func(t *testing.T) {
+		profiles, err := client.GetSizeProfiles(&model.GetSizeProfilesRequest{Paging: model.AllPagesNotDeleted()})
+		require.NoError(t, err)
+		assert.Empty(t, profiles)
+	})
+
+	var profile *model.SizeProfile
+	t.Run("create", func(t *testing.T) {
+		var err error
+		profile, err = client.CreateSizeProfile(&model.CreateSizeProfileRequest{
+			Name:        "custom",
+			Description: "custom size",
+			Spec:        spec,
+		})
+		require.NoError(t, err)
+		assert.NotEmpty(t, profile.ID)
+		assert.Equal(t, "custom", profile.Name)
+		assert.Equal(t, int32(2), profile.Spec.App.Replicas)
+	})
+
+	t.Run("invalid create requests", func(t *testing.T) {
+		_, err := client.CreateSizeProfile(&model.CreateSizeProfileRequest{Name: "custom", Spec: spec})
+		require.EqualError(t, err, "failed with status code 409")
+
+		_, err = client.CreateSizeProfile(&model.CreateSizeProfileRequest{Name: "invalid-name", Spec: spec})
+		require.EqualError(t, err, "failed with status code 400")
+
+		_, err = client.CreateSizeProfile(&model.CreateSizeProfileRequest{Name: "nospec"})
+		require.EqualError(t, err, "failed with status code 400")
+
+		_, err = client.CreateSizeProfile(&model.CreateSizeProfileRequest{Name: "noreplicas", Spec: &model.SizeProfileSpec{}})
+		require.EqualError(t, err, "failed with status code 400")
+
+		resp, err := http.Post(fmt.Sprintf("%s/api/sizes", ts.URL), "application/json", bytes.NewReader([]byte("invalid")))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("get", func(t *testing.T) {
+		fetchedProfile, err := client.GetSizeProfile(profile.Name)
+		require.NoError(t, err)
+		assert.Equal(t, profile.ID, fetchedProfile.ID)
+
+		profiles, err := client.GetSizeProfiles(&model.GetSizeProfilesRequest{Paging: model.AllPagesNotDeleted()})
+		require.NoError(t, err)
+		require.Len(t, profiles, 1)
+		assert.Equal(t, profile.ID, profiles[0].ID)
+	})
+
+	t.Run("invalid paging", func(t *testing.T) {
+		resp, err := http.Get(fmt.Sprintf("%s/api/sizes?page=invalid", ts.URL))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("unknown profile", func(t *testing.T) {
+		fetchedProfile, err := client.GetSizeProfile("unknown")
+		require.NoError(t, err)
+		assert.Nil(t, fetchedProfile)
+
+		_, err = client.UpdateSizeProfile("unknown", &model.PatchSizeProfileRequest{Description: util.SToP("updated")})
+		require.EqualError(t, err, "failed with status code 404")
+
+		err = client.DeleteSizeProfile("unknown")
+		require.EqualError(t, err, "failed with status code 404")
+	})
+
+	t.Run("update", func(t *testing.T) {
+		updatedSpec := *spec
+		updatedSpec.App.Replicas = 4
+		updatedProfile, err := client.UpdateSizeProfile(profile.Name, &model.PatchSizeProfileRequest{
+			Description: util.SToP("updated"),
+			Spec:        &updatedSpec,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "updated", updatedProfile.Description)
+		assert.Equal(t, int32(4), updatedProfile.Spec.App.Replicas)
+
+		fetchedProfile, err := client.GetSizeProfile(profile.Name)
+		require.NoError(t, err)
+		assert.Equal(t, int32(4), fetchedProfile.Spec.App.Replicas)
+	})
+
+	t.Run("invalid update requests", func(t *testing.T) {
+		_, err := client.UpdateSizeProfile(profile.Name, &model.PatchSizeProfileRequest{})
+		require.EqualError(t, err, "failed with status code 400")
+
+		_, err = client.UpdateSizeProfile(profile.Name, &model.PatchSizeProfileRequest{Spec: &model.SizeProfileSpec{}})
+		require.EqualError(t, err, "failed with status code 400")
+	})
+
+	t.Run("delete profile in use", func(t *testing.T) {
+		installation := &model.Installation{Name: "sized", Size: "custom-6"}
+		err := sqlStore.CreateInstallation(installation, nil, nil)
+		require.NoError(t, err)
+
+		err = client.DeleteSizeProfile(profile.Name)
+		require.EqualError(t, err, "failed with status code 409")
+
+		err = sqlStore.DeleteInstallation(installation.ID)
+		require.NoError(t, err)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		err := client.DeleteSizeProfile(profile.Name)
+		require.NoError(t, err)
+
+		fetchedProfile, err := client.GetSizeProfile(profile.Name)
+		require.NoError(t, err)
+		assert.Nil(t, fetchedProfile)
+	})
+}
@@ -0,0 +1,314 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost-cloud/internal/webhook"
+	"github.com/mattermost/mattermost-cloud/model"
+)
+
+// handleGetUtilityDrift responds to GET /api/clusters/utilities/drift,
+// returning the utility version drift report of all clusters.
+func handleGetUtilityDrift(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.Logger = c.Logger.WithField("action", "get-utility-drift")
+
+	onlyDrifted, err := parseBool(r.URL, "only_drifted", false)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to parse only_drifted parameter")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	clusters, err := c.Store.GetClusters(&model.ClusterFilter{
+		Paging: model.AllPagesNotDeleted(),
+	})
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query clusters")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	report, err := model.NewUtilityDriftReport(clusters, parseString(r.URL, "utility", ""), onlyDrifted)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to build utility drift report")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, report)
+}
+
+// handleCreateUtilityRollout responds to POST /api/clusters/utilities/rollout,
+// scheduling the upgrade of a utility across the selected clusters.
+func handleCreateUtilityRollout(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.Logger = c.Logger.WithField("action", "create-utility-rollout")
+
+	request, err := model.NewUtilityRolloutRequestFromReader(r.Body)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to decode request")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	c.Logger = c.Logger.WithField("utility", request.Utility)
+
+	pendingRollouts, err := c.Store.GetUtilityRollouts(&model.UtilityRolloutFilter{
+		Paging:  model.AllPagesNotDeleted(),
+		Utility: request.Utility,
+		States:  model.AllUtilityRolloutStatesPendingWork,
+	})
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query pending utility rollouts")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if len(pendingRollouts) > 0 {
+		c.Logger.Errorf("Rollout %s is still in progress for this utility", pendingRollouts[0].ID)
+		w.WriteHeader(http.StatusConflict)
+		return
+	}
+
+	clusterIDs, status := utilityRolloutClusterIDs(c, request)
+	if status != 0 {
+		w.WriteHeader(status)
+		return
+	}
+	if len(clusterIDs) == 0 {
+		c.Logger.Error("No clusters match the rollout request")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	rolloutClusters := model.UtilityRolloutClusters{}
+	for _, clusterID := range clusterIDs {
+		rolloutClusters = append(rolloutClusters, &model.UtilityRolloutCluster{
+			ClusterID: clusterID,
+			State:     model.UtilityRolloutClusterPending,
+		})
+	}
+
+	rollout := &model.UtilityRollout{
+		Utility:               request.Utility,
+		Version:               request.Version,
+		ValuesPath:            request.ValuesPath,
+		State:                 model.UtilityRolloutStateRequested,
+		MaxConcurrentClusters: request.MaxConcurrentClusters,
+		ClusterTimeoutSeconds: request.ClusterTimeoutSeconds,
+		Clusters:              rolloutClusters,
+	}
+	err = c.Store.CreateUtilityRollout(rollout)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to create utility rollout")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	c.Supervisor.Do()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	outputJSON(c, w, rollout)
+}
+
+// utilityRolloutClusterIDs returns the IDs of the clusters selected by the
+// rollout request.
+func utilityRolloutClusterIDs(c *Context, request *model.UtilityRolloutRequest) ([]string, int) {
+	if len(request.ClusterIDs) > 0 {
+		for _, clusterID := range request.ClusterIDs {
+			cluster, err := c.Store.GetCluster(clusterID)
+			if err != nil {
+				c.Logger.WithError(err).Error("failed to query cluster")
+				return nil, http.StatusInternalServerError
+			}
+			if cluster == nil || cluster.DeleteAt != 0 {
+				c.Logger.Errorf("Cluster %s not found", clusterID)
+				return nil, http.StatusBadRequest
+			}
+		}
+		return request.ClusterIDs, 0
+	}
+
+	annotations, err := c.Store.GetAnnotationsByName(request.Annotations)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to get annotations by name")
+		return nil, http.StatusInternalServerError
+	}
+	if len(annotations) != len(request.Annotations) {
+		c.Logger.Error("Some annotations for cluster selection do not exist")
+		return nil, http.StatusBadRequest
+	}
+
+	clusters, err := c.Store.GetClusters(&model.ClusterFilter{
+		Paging: model.AllPagesNotDeleted(),
+		Annotations: &model.AnnotationsFilter{
+			MatchAllIDs: model.GetAnnotationsIDs(annotations),
+		},
+	})
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query clusters with annotations")
+		return nil, http.StatusInternalServerError
+	}
+
+	clusterIDs := make([]string, 0, len(clusters))
+	for _, cluster := range clusters {
+		clusterIDs = append(clusterIDs, cluster.ID)
+	}
+
+	return clusterIDs, 0
+}
+
+// handleGetUtilityRollouts responds to GET /api/clusters/utilities/rollouts,
+// returning a list of utility rollouts.
+func handleGetUtilityRollouts(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.Logger = c.Logger.WithField("action", "list-utility-rollouts")
+
+	paging, err := parsePaging(r.URL)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to parse paging parameters")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	filter := &model.UtilityRolloutFilter{
+		Paging:  paging,
+		Utility: parseString(r.URL, "utility", ""),
+	}
+	state := parseString(r.URL, "state", "")
+	if state != "" {
+		filter.States = []model.UtilityRolloutState{model.UtilityRolloutState(state)}
+	}
+
+	rollouts, err := c.Store.GetUtilityRollouts(filter)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query utility rollouts")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, rollouts)
+}
+
+// handleGetUtilityRollout responds to GET /api/utility_rollout/{rollout},
+// returning the utility rollout in question.
+func handleGetUtilityRollout(c *Context, w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	rolloutID := vars["rollout"]
+	c.Logger = c.Logger.WithField("rollout", rolloutID)
+
+	rollout, err := c.Store.GetUtilityRollout(rolloutID)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query utility rollout")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if rollout == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, rollout)
+}
+
+// handleResumeUtilityRollout responds to POST /api/utility_rollout/{rollout}/resume,
+// retrying the failed clusters of a paused utility rollout.
+func handleResumeUtilityRollout(c *Context, w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	rolloutID := vars["rollout"]
+	c.Logger = c.Logger.WithField("rollout", rolloutID).WithField("action", "resume-utility-rollout")
+
+	rollout, status, unlockOnce := lockUtilityRollout(c, rolloutID)
+	if status != 0 {
+		w.WriteHeader(status)
+		return
+	}
+	defer unlockOnce()
+
+	if rollout.State != model.UtilityRolloutStatePaused {
+		c.Logger.Warnf("Cannot resume rollout in state %s", rollout.State)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	for _, rolloutCluster := range rollout.Clusters {
+		if rolloutCluster.State == model.UtilityRolloutClusterFailed {
+			rolloutCluster.State = model.UtilityRolloutClusterPending
+			rolloutCluster.Message = ""
+		}
+	}
+
+	updateUtilityRolloutState(c, w, rollout, model.UtilityRolloutStateInProgress, unlockOnce)
+}
+
+// handleCancelUtilityRollout responds to POST /api/utility_rollout/{rollout}/cancel,
+// skipping the clusters of a utility rollout that were not upgraded yet.
+func handleCancelUtilityRollout(c *Context, w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	rolloutID := vars["rollout"]
+	c.Logger = c.Logger.WithField("rollout", rolloutID).WithField("action", "cancel-utility-rollout")
+
+	rollout, status, unlockOnce := lockUtilityRollout(c, rolloutID)
+	if status != 0 {
+		w.WriteHeader(status)
+		return
+	}
+	defer unlockOnce()
+
+	if rollout.IsFinished() {
+		c.Logger.Warnf("Cannot cancel rollout in state %s", rollout.State)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	for _, rolloutCluster := range rollout.Clusters {
+		if rolloutCluster.State == model.UtilityRolloutClusterPending {
+			rolloutCluster.State = model.UtilityRolloutClusterSkipped
+			rolloutCluster.Message = "rollout cancelled"
+		}
+	}
+	rollout.CompleteAt = model.GetMillis()
+
+	updateUtilityRolloutState(c, w, rollout, model.UtilityRolloutStateCancelled, unlockOnce)
+}
+
+func updateUtilityRolloutState(c *Context, w http.ResponseWriter, rollout *model.UtilityRollout, newState model.UtilityRolloutState, unlockOnce func()) {
+	oldState := rollout.State
+	rollout.State = newState
+
+	err := c.Store.UpdateUtilityRollout(rollout)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to update utility rollout")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	webhookPayload := &model.WebhookPayload{
+		Type:      model.TypeUtilityRollout,
+		ID:        rollout.ID,
+		NewState:  string(rollout.State),
+		OldState:  string(oldState),
+		Timestamp: time.Now().UnixNano(),
+		ExtraData: map[string]string{"Utility": rollout.Utility, "Version": rollout.Version, "Environment": c.Environment},
+	}
+	err = webhook.SendToAllWebhooks(c.Store, webhookPayload, c.Logger.WithField("webhookEvent", webhookPayload.NewState))
+	if err != nil {
+		c.Logger.WithError(err).Error("Unable to process and send webhooks")
+	}
+
+	unlockOnce()
+	c.Supervisor.Do()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	outputJSON(c, w, rollout)
+}
@@ -0,0 +1,214 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package api_test
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost-cloud/internal/api"
+	"github.com/mattermost/mattermost-cloud/internal/store"
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUtilityRollouts(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	defer store.CloseConnection(t, sqlStore)
+
+	router := mux.NewRouter()
+	api.Register(router, &api.Context{
+		Store:      sqlStore,
+		Supervisor: &mockSupervisor{},
+		Metrics:    &mockMetrics{},
+		Logger:     logger,
+	})
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	client := model.NewClient(ts.URL)
+
+	cluster1 := &model.Cluster{}
+	err := sqlStore.CreateCluster(cluster1, []*model.Annotation{{Name: "rollout"}})
+	require.NoError(t, err)
+	cluster2 := &model.Cluster{}
+	err = sqlStore.CreateCluster(cluster2, []*model.Annotation{{Name: "rollout"}, {Name: "canary"}})
+	require.NoError(t, err)
+
+	t.Run("invalid requests", func(t *testing.T) {
+		for _, testCase := range []struct {
+			description string
+			request     *model.UtilityRolloutRequest
+		}{
+			{"no utility", &model.UtilityRolloutRequest{Version: "1.0.0", ClusterIDs: []string{cluster1.ID}}},
+			{"no version", &model.UtilityRolloutRequest{Utility: model.NginxCanonicalName, ClusterIDs: []string{cluster1.ID}}},
+			{"unmanaged version", &model.UtilityRolloutRequest{Utility: model.NginxCanonicalName, Version: model.UnmanagedUtilityVersion, ClusterIDs: []string{cluster1.ID}}},
+			{"no cluster selection", &model.UtilityRolloutRequest{Utility: model.NginxCanonicalName, Version: "1.0.0"}},
+			{"both cluster selections", &model.UtilityRolloutRequest{Utility: model.NginxCanonicalName, Version: "1.0.0", ClusterIDs: []string{cluster1.ID}, Annotations: []string{"rollout"}}},
+			{"negative concurrency", &model.UtilityRolloutRequest{Utility: model.NginxCanonicalName, Version: "1.0.0", ClusterIDs: []string{cluster1.ID}, MaxConcurrentClusters: -1}},
+			{"unknown cluster", &model.UtilityRolloutRequest{Utility: model.NginxCanonicalName, Version: "1.0.0", ClusterIDs: []string{model.NewID()}}},
+			{"unknown annotation", &model.UtilityRolloutRequest{Utility: model.NginxCanonicalName, Version: "1.0.0", Annotations: []string{"unknown"}}},
+		} {
+			t.Run(testCase.description, func(t *testing.T) {
+				_, err := client.CreateUtilityRollout(testCase.request)
+				require.EqualError(t, err, "failed with status code 400")
+			})
+		}
+
+		resp, err := http.Post(fmt.Sprintf("%s/api/clusters/utilities/rollout", ts.URL), "application/json", bytes.NewReader([]byte("invalid")))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	var rollout *model.UtilityRollout
+	t.Run("create by annotations", func(t *testing.T) {
+		rollout, err = client.CreateUtilityRollout(&model.UtilityRolloutRequest{
+			Utility:     model.NginxCanonicalName,
+			Version:     "4.0.18",
+			Annotations: []string{"rollout", "canary"},
+		})
+		require.NoError(t, err)
+		assert.NotEmpty(t, rollout.ID)
+		assert.Equal(t, model.UtilityRolloutStateRequested, rollout.State)
+		assert.Equal(t, model.DefaultUtilityRolloutMaxConcurrentClusters, rollout.MaxConcurrentClusters)
+		assert.Equal(t, int64(model.DefaultUtilityRolloutClusterTimeoutSeconds), rollout.ClusterTimeoutSeconds)
+		require.Len(t, rollout.Clusters, 1)
+		assert.Equal(t, cluster2.ID, rollout.Clusters[0].ClusterID)
+		assert.Equal(t, model.UtilityRolloutClusterPending, rollout.Clusters[0].State)
+	})
+
+	t.Run("rollout in progress for the utility", func(t *testing.T) {
+		_, err = client.CreateUtilityRollout(&model.UtilityRolloutRequest{
+			Utility:    model.NginxCanonicalName,
+			Version:    "4.0.19",
+			ClusterIDs: []string{cluster1.ID},
+		})
+		require.EqualError(t, err, "failed with status code 409")
+	})
+
+	t.Run("resume rollout that is not paused", func(t *testing.T) {
+		_, err = client.ResumeUtilityRollout(rollout.ID)
+		require.EqualError(t, err, "failed with status code 400")
+	})
+
+	t.Run("resume paused rollout", func(t *testing.T) {
+		rollout.State = model.UtilityRolloutStatePaused
+		rollout.Clusters[0].State = model.UtilityRolloutClusterFailed
+		rollout.Clusters[0].Message = "timed out"
+		err = sqlStore.UpdateUtilityRollout(rollout)
+		require.NoError(t, err)
+
+		rollout, err = client.ResumeUtilityRollout(rollout.ID)
+		require.NoError(t, err)
+		assert.Equal(t, model.UtilityRolloutStateInProgress, rollout.State)
+		assert.Equal(t, model.UtilityRolloutClusterPending, rollout.Clusters[0].State)
+		assert.Empty(t, rollout.Clusters[0].Message)
+	})
+
+	t.Run("cancel rollout", func(t *testing.T) {
+		rollout, err = client.CancelUtilityRollout(rollout.ID)
+		require.NoError(t, err)
+		assert.Equal(t, model.UtilityRolloutStateCancelled, rollout.State)
+		assert.Equal(t, model.UtilityRolloutClusterSkipped, rollout.Clusters[0].State)
+		assert.NotZero(t, rollout.CompleteAt)
+
+		_, err = client.CancelUtilityRollout(rollout.ID)
+		require.EqualError(t, err, "failed with status code 400")
+	})
+
+	t.Run("unknown rollout", func(t *testing.T) {
+		fetchedRollout, err := client.GetUtilityRollout(model.NewID())
+		require.NoError(t, err)
+		assert.Nil(t, fetchedRollout)
+
+		_, err = client.ResumeUtilityRollout(model.NewID())
+		require.EqualError(t, err, "failed with status code 404")
+
+		_, err = client.CancelUtilityRollout(model.NewID())
+		require.EqualError(t, err, "failed with status code 404")
+	})
+
+	t.Run("create by cluster IDs", func(t *testing.T) {
+		secondRollout, err := client.CreateUtilityRollout(&model.UtilityRolloutRequest{
+			Utility:               model.NginxCanonicalName,
+			Version:               "4.0.19",
+			ClusterIDs:            []string{cluster1.ID, cluster2.ID},
+			MaxConcurrentClusters: 2,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 2, secondRollout.MaxConcurrentClusters)
+		require.Len(t, secondRollout.Clusters, 2)
+
+		rollouts, err := client.GetUtilityRollouts(&model.GetUtilityRolloutsRequest{
+			Paging:  model.AllPagesNotDeleted(),
+			Utility: model.NginxCanonicalName,
+		})
+		require.NoError(t, err)
+		require.Len(t, rollouts, 2)
+		assert.Equal(t, secondRollout.ID, rollouts[0].ID)
+
+		rollouts, err = client.GetUtilityRollouts(&model.GetUtilityRolloutsRequest{
+			Paging: model.AllPagesNotDeleted(),
+			State:  string(model.UtilityRolloutStateCancelled),
+		})
+		require.NoError(t, err)
+		require.Len(t, rollouts, 1)
+		assert.Equal(t, rollout.ID, rollouts[0].ID)
+
+		fetchedRollout, err := client.GetUtilityRollout(secondRollout.ID)
+		require.NoError(t, err)
+		assert.Equal(t, secondRollout, fetchedRollout)
+	})
+}
+
+func TestGetUtilityDrift(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	defer store.CloseConnection(t, sqlStore)
+
+	router := mux.NewRouter()
+	api.Register(router, &api.Context{
+		Store:      sqlStore,
+		Supervisor: &mockSupervisor{},
+		Metrics:    &mockMetrics{},
+		Logger:     logger,
+	})
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	client := model.NewClient(ts.URL)
+
+	t.Run("no clusters", func(t *testing.T) {
+		report, err := client.GetUtilityDrift(&model.GetUtilityDriftRequest{})
+		require.NoError(t, err)
+		assert.Empty(t, report.Utilities)
+	})
+
+	t.Run("invalid only drifted", func(t *testing.T) {
+		resp, err := http.Get(fmt.Sprintf("%s/api/clusters/utilities/drift?only_drifted=invalid", ts.URL))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("filter by utility", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			err := sqlStore.CreateCluster(&model.Cluster{}, nil)
+			require.NoError(t, err)
+		}
+
+		report, err := client.GetUtilityDrift(&model.GetUtilityDriftRequest{Utility: model.NginxCanonicalName})
+		require.NoError(t, err)
+		require.Len(t, report.Utilities, 1)
+		assert.Equal(t, model.NginxCanonicalName, report.Utilities[0].Utility)
+		assert.Len(t, report.Utilities[0].Clusters, 2)
+	})
+}
@@ -6,6 +6,7 @@ package common
 
 import (
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/mattermost/mattermost-cloud/internal/events"
@@ -20,6 +21,11 @@ type installationRestorationStore interface {
 	GetWebhooks(filter *model.WebhookFilter) ([]*model.Webhook, error)
 }
 
+type installationPointInTimeRestorationStore interface {
+	TriggerInstallationPointInTimeRestoration(installation *model.Installation, restoreTime int64) (*model.InstallationDBRestorationOperation, error)
+	GetWebhooks(filter *model.WebhookFilter) ([]*model.Webhook, error)
+}
+
 type eventProducer interface {
 	ProduceInstallationStateChangeEvent(installation *model.Installation, oldState string, extraDataFields ...events.DataField) error
 }
@@ -58,3 +64,38 @@ func TriggerInstallationDBRestoration(store installationRestorationStore, instal
 
 	return dbRestoration, nil
 }
+
+// TriggerInstallationDBPointInTimeRestoration validates, triggers and reports installation database point-in-time restoration.
+func TriggerInstallationDBPointInTimeRestoration(store installationPointInTimeRestorationStore, installation *model.Installation, restoreTime int64, eventsProducer eventProducer, env string, logger log.FieldLogger) (*model.InstallationDBRestorationOperation, error) {
+	err := model.EnsureInstallationReadyForPointInTimeRestoration(installation, restoreTime)
+	if err != nil {
+		return nil, ErrWrap(http.StatusBadRequest, err, "installation cannot be restored")
+	}
+
+	oldInstallationState := installation.State
+
+	dbRestoration, err := store.TriggerInstallationPointInTimeRestoration(installation, restoreTime)
+	if err != nil {
+		return nil, ErrWrap(http.StatusInternalServerError, err, "failed to create Installation DB restoration operation")
+	}
+
+	webhookPayload := &model.WebhookPayload{
+		Type:      model.TypeInstallationDBRestoration,
+		ID:        dbRestoration.ID,
+		NewState:  string(model.InstallationDBRestorationStateRequested),
+		OldState:  "n/a",
+		Timestamp: time.Now().UnixNano(),
+		ExtraData: map[string]string{"Installation": dbRestoration.InstallationID, "RestoreTime": strconv.FormatInt(restoreTime, 10), "Environment": env},
+	}
+	err = webhook.SendToAllWebhooks(store, webhookPayload, logger.WithField("webhookEvent", webhookPayload.NewState))
+	if err != nil {
+		logger.WithError(err).Error("Unable to process and send webhooks")
+	}
+
+	err = eventsProducer.ProduceInstallationStateChangeEvent(installation, oldInstallationState)
+	if err != nil {
+		logger.WithError(err).Error("Failed to create installation state change event")
+	}
+
+	return dbRestoration, nil
+}
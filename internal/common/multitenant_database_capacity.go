@@ -0,0 +1,80 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package common
+
+import (
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+)
+
+type multitenantDatabaseCapacityStore interface {
+	GetMultitenantDatabases(filter *model.MultitenantDatabaseFilter) ([]*model.MultitenantDatabase, error)
+	GetLogicalDatabases(filter *model.LogicalDatabaseFilter) ([]*model.LogicalDatabase, error)
+	GetInstallations(filter *model.InstallationFilter, includeGroupConfig, includeGroupConfigOverrides bool) ([]*model.Installation, error)
+}
+
+// MultitenantDatabaseLimitProvider provides the maximum number of
+// installations supported by a multitenant database type.
+type MultitenantDatabaseLimitProvider interface {
+	GetMultitenantDatabaseMaxInstallations(databaseType string) int
+}
+
+// GetMultitenantDatabaseCapacities computes the capacity of every multitenant
+// database matching the filter.
+func GetMultitenantDatabaseCapacities(store multitenantDatabaseCapacityStore, limits MultitenantDatabaseLimitProvider, filter *model.MultitenantDatabaseFilter, lookback time.Duration) ([]*model.MultitenantDatabaseCapacity, error) {
+	multitenantDatabases, err := store.GetMultitenantDatabases(filter)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query multitenant databases")
+	}
+
+	var installationIDs []string
+	for _, multitenantDatabase := range multitenantDatabases {
+		installationIDs = append(installationIDs, multitenantDatabase.Installations...)
+	}
+
+	installationsByID := make(map[string]*model.Installation, len(installationIDs))
+	if len(installationIDs) > 0 {
+		installations, err := store.GetInstallations(&model.InstallationFilter{
+			InstallationIDs: installationIDs,
+			Paging:          model.AllPagesNotDeleted(),
+		}, false, false)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to query installations")
+		}
+		for _, installation := range installations {
+			installationsByID[installation.ID] = installation
+		}
+	}
+
+	now := model.GetMillis()
+	capacities := make([]*model.MultitenantDatabaseCapacity, 0, len(multitenantDatabases))
+	for _, multitenantDatabase := range multitenantDatabases {
+		var installations []*model.Installation
+		for _, installationID := range multitenantDatabase.Installations {
+			if installation, ok := installationsByID[installationID]; ok {
+				installations = append(installations, installation)
+			}
+		}
+
+		var logicalDatabaseCount int
+		if model.IsProxyDatabaseType(multitenantDatabase.DatabaseType) {
+			logicalDatabases, err := store.GetLogicalDatabases(&model.LogicalDatabaseFilter{
+				MultitenantDatabaseID: multitenantDatabase.ID,
+				Paging:                model.AllPagesNotDeleted(),
+			})
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to query logical databases for %s", multitenantDatabase.ID)
+			}
+			logicalDatabaseCount = len(logicalDatabases)
+		}
+
+		maxWeight := float64(limits.GetMultitenantDatabaseMaxInstallations(multitenantDatabase.DatabaseType))
+		capacities = append(capacities, model.NewMultitenantDatabaseCapacity(multitenantDatabase, installations, logicalDatabaseCount, maxWeight, lookback, now))
+	}
+
+	return capacities, nil
+}
@@ -5,7 +5,6 @@
 package events
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -26,6 +25,8 @@ const (
 	workerIdleDelay = 6 * time.Second
 
 	retryDelay = 20 * time.Second
+
+	sinkSendTimeout = 15 * time.Second
 )
 
 type delivererStore interface {
@@ -38,11 +39,16 @@ type delivererStore interface {
 	UpdateEventDeliveryStatus(delivery *model.EventDelivery) error
 }
 
+// DeliveryMetrics records event delivery attempts.
+type DeliveryMetrics interface {
+	ObserveEventDeliveryAttempt(subscriptionID, sinkType string, delivered bool, latency time.Duration)
+}
+
 // EventDeliverer is responsible for delivering events.
 type EventDeliverer struct {
 	ctx        context.Context
 	store      delivererStore
-	client     *http.Client
+	sinks      EventSinks
 	instanceID string
 	config     DelivererConfig
 	logger     logrus.FieldLogger
@@ -56,20 +62,36 @@ type DelivererConfig struct {
 	RetryWorkers    int
 	UpToDateWorkers int
 	MaxBurstWorkers int
+	// Sinks are added to the sinks available by default (HTTP, NATS and
+	// Kafka). Sinks requiring configuration, such as SQS, SNS or file, have
+	// to be provided here to be usable by subscriptions.
+	Sinks EventSinks
+	// Metrics, if set, records delivery attempts of all subscriptions.
+	Metrics DeliveryMetrics
 }
 
 // NewDeliverer creates new EventDeliverer component.
 func NewDeliverer(ctx context.Context, store delivererStore, instanceID string, log logrus.FieldLogger, cfg DelivererConfig) *EventDeliverer {
+	sinks := defaultSinks(NewHTTPSink(&http.Client{Timeout: sinkSendTimeout}))
+	for sinkType, sink := range cfg.Sinks {
+		sinks[sinkType] = sink
+	}
+
 	delivery := &EventDeliverer{
 		ctx:        ctx,
 		store:      store,
-		client:     &http.Client{Timeout: 15 * time.Second},
+		sinks:      sinks,
 		instanceID: instanceID,
 		config:     cfg,
 		logger:     log.WithField("component", "eventsDelivery"),
 		retryDelay: retryDelay,
 	}
 
+	go func() {
+		<-ctx.Done()
+		sinks.close(delivery.logger)
+	}()
+
 	for i := 0; i < delivery.config.RetryWorkers; i++ {
 		go delivery.newWorker().ProcessRetrying(ctx)
 	}
@@ -136,18 +158,22 @@ loop:
 }
 
 // sender is a helper struct for separation of logic.
-// It uses HTTP client of EventDeliverer.
+// It uses sinks of EventDeliverer.
 type sender struct {
+	ctx        context.Context
 	store      delivererStore
-	client     *http.Client
+	sinks      EventSinks
+	metrics    DeliveryMetrics
 	instanceID string
 	logger     logrus.FieldLogger
 }
 
 func (d *EventDeliverer) newWorker() *sender {
 	return &sender{
+		ctx:        d.ctx,
 		store:      d.store,
-		client:     d.client,
+		sinks:      d.sinks,
+		metrics:    d.config.Metrics,
 		instanceID: d.instanceID,
 		logger:     d.logger.WithField("worker", model.NewID()),
 	}
@@ -249,13 +275,15 @@ func (s *sender) processDelivery(sub *model.Subscription, delivery *model.StateC
 	var subDeliveryStatus model.SubscriptionDeliveryStatus
 
 	delivery.EventHeaders = sub.Headers
-	err := s.sendEvent(sub.ID, sub.URL, delivery, log)
+	err := s.sendEvent(sub, delivery, log)
+	s.observeDeliveryAttempt(sub, delivery, err == nil)
 	if err != nil {
 		log.WithError(err).Error("Failed to deliver event")
 
 		// We abort delivery on the subscription only if the event will be retried
 		// otherwise we mark event as failed and continue.
-		if delivery.EventData.Event.Timestamp+sub.FailureThreshold.Milliseconds() < delivery.EventDelivery.LastAttempt {
+		thresholdStart := delivery.EventDelivery.FailureThresholdStart(delivery.EventData.Event.Timestamp)
+		if thresholdStart+sub.FailureThreshold.Milliseconds() < delivery.EventDelivery.LastAttempt {
 			delivery.EventDelivery.Status = model.EventDeliveryFailed
 		} else {
 			subDeliveryStatus = model.SubscriptionDeliveryFailed
@@ -274,39 +302,61 @@ func (s *sender) processDelivery(sub *model.Subscription, delivery *model.StateC
 	return subDeliveryStatus, subDeliveryStatus != model.SubscriptionDeliveryFailed
 }
 
-func (s *sender) sendEvent(subscription_id, url string, data *model.StateChangeEventDeliveryData, log logrus.FieldLogger) error {
-	payload, err := json.Marshal(data.EventData.ToEventPayload())
+func (s *sender) sendEvent(sub *model.Subscription, data *model.StateChangeEventDeliveryData, log logrus.FieldLogger) error {
+	sink, err := s.sinks.get(sub.SinkType)
 	if err != nil {
-		return errors.Wrap(err, "failed to marshal event payload")
+		return err
 	}
 
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(payload))
-	if err != nil {
-		s.logger.WithField("subscription_url", url).WithError(err).Error("Unable to create request")
-		return errors.Wrap(err, "unable to create request from payload")
-	}
-	req.Header.Set("Content-Type", contentTypeApplicationJSON)
-	headers := data.EventHeaders.GetHeaders()
-	for key, value := range headers {
-		req.Header.Set(key, value)
-	}
-	resp, err := s.client.Do(req)
+	msg, err := newSinkMessage(sub, data)
 	if err != nil {
-		return errors.Wrap(err, "failed to deliver event")
+		return err
 	}
-	defer drainBody(resp.Body)
-	if resp.StatusCode >= 500 {
-		return errors.Errorf(
-			"consumer failed to receive event, got %d response code, body: %s",
-			resp.StatusCode, attemptToReadBody(resp.Body),
-		)
+
+	ctx, cancel := context.WithTimeout(s.ctx, sinkSendTimeout)
+	defer cancel()
+
+	return sink.Send(ctx, sub.URL, msg, log.WithField("sink", sub.SinkType))
+}
+
+// observeDeliveryAttempt records the attempt together with the time from the
+// occurrence of the event to the end of the attempt.
+func (s *sender) observeDeliveryAttempt(sub *model.Subscription, delivery *model.StateChangeEventDeliveryData, delivered bool) {
+	if s.metrics == nil {
+		return
 	}
-	if resp.StatusCode != http.StatusOK {
-		log.Errorf("Event delivery resulted in status %d. Treating it as consumer error, delivery will not be retried",
-			resp.StatusCode)
+	sinkType := sub.SinkType
+	if sinkType == "" {
+		sinkType = model.SubscriptionSinkHTTP
 	}
+	latency := time.Duration(model.GetMillis()-delivery.EventData.Event.Timestamp) * time.Millisecond
+	s.metrics.ObserveEventDeliveryAttempt(sub.ID, string(sinkType), delivered, latency)
+}
 
-	return nil
+// buildEventPayload encodes the event in the payload format of the subscription.
+// It returns the request body, its content type and additional headers.
+func buildEventPayload(format model.SubscriptionPayloadFormat, data *model.StateChangeEventDeliveryData) ([]byte, string, map[string]string, error) {
+	switch format {
+	case model.SubscriptionPayloadFormatCloudEventsStructured:
+		payload, err := json.Marshal(data.EventData.ToCloudEvent())
+		if err != nil {
+			return nil, "", nil, errors.Wrap(err, "failed to marshal cloud event")
+		}
+		return payload, model.CloudEventsContentType, nil, nil
+	case model.SubscriptionPayloadFormatCloudEventsBinary:
+		cloudEvent := data.EventData.ToCloudEvent()
+		payload, err := json.Marshal(cloudEvent.Data)
+		if err != nil {
+			return nil, "", nil, errors.Wrap(err, "failed to marshal cloud event data")
+		}
+		return payload, contentTypeApplicationJSON, cloudEvent.BinaryModeHeaders(), nil
+	default:
+		payload, err := json.Marshal(data.EventData.ToEventPayload())
+		if err != nil {
+			return nil, "", nil, errors.Wrap(err, "failed to marshal event payload")
+		}
+		return payload, contentTypeApplicationJSON, nil, nil
+	}
 }
 
 func (s *sender) unlockSubscription(subID string, log logrus.FieldLogger) {
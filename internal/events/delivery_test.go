@@ -5,7 +5,9 @@
 package events
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -187,6 +189,112 @@ func TestDelivery_GiveUpWhenThresholdReached(t *testing.T) {
 	assert.Equal(t, model.EventDeliveryFailed, deliveries[0].Status)
 }
 
+func TestDelivery_PauseResumeAndReplay(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	defer store.CloseConnection(t, sqlStore)
+
+	instanceID := model.NewID()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cfg := DelivererConfig{
+		MaxBurstWorkers: 50,
+	}
+	eventsDeliverer := NewDeliverer(ctx, sqlStore, instanceID, logger, cfg)
+
+	deliveredEvents := make(chan *model.StateChangeEventPayload, 1)
+
+	r := mux.NewRouter()
+	r.HandleFunc("/event", successEventHandler(t, deliveredEvents))
+	consumerSever := httptest.NewServer(r)
+
+	subscription := &model.Subscription{
+		URL:                fmt.Sprintf("%s/event", consumerSever.URL),
+		EventType:          model.ResourceStateChangeEventType,
+		LastDeliveryStatus: model.SubscriptionDeliveryNone,
+		FailureThreshold:   1 * time.Minute,
+		Paused:             true,
+	}
+	err := sqlStore.CreateSubscription(subscription)
+	require.NoError(t, err)
+
+	eventData := createFixedEventData(t, sqlStore)
+
+	// Paused subscription should not receive events.
+	eventsDeliverer.SignalNewEvents(model.ResourceStateChangeEventType)
+	assert.Empty(t, deliveredEvents)
+
+	err = sqlStore.UpdateSubscriptionPaused(subscription.ID, false)
+	require.NoError(t, err)
+
+	eventsDeliverer.SignalNewEvents(model.ResourceStateChangeEventType)
+	awaitEvents(t, deliveredEvents, eventData.Event.ID, 1)
+
+	// Delivered event can be replayed.
+	replayed, err := sqlStore.ReplayEventDeliveries(subscription.ID, &model.ReplaySubscriptionDeliveriesRequest{
+		EventIDs: []string{eventData.Event.ID},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), replayed)
+
+	eventsDeliverer.SignalNewEvents(model.ResourceStateChangeEventType)
+	awaitEvents(t, deliveredEvents, eventData.Event.ID, 1)
+}
+
+func TestBuildEventPayload(t *testing.T) {
+	data := &model.StateChangeEventDeliveryData{
+		EventData: model.StateChangeEventData{
+			Event: model.Event{
+				ID:        "event1",
+				EventType: model.ResourceStateChangeEventType,
+				Timestamp: model.GetMillis(),
+			},
+			StateChange: model.StateChangeEvent{
+				OldState:     "old",
+				NewState:     "new",
+				ResourceID:   "abcd",
+				ResourceType: model.TypeInstallation,
+			},
+		},
+	}
+
+	t.Run("provisioner", func(t *testing.T) {
+		payload, contentType, headers, err := buildEventPayload(model.SubscriptionPayloadFormatProvisioner, data)
+		require.NoError(t, err)
+		assert.Equal(t, "application/json", contentType)
+		assert.Empty(t, headers)
+
+		eventPayload, err := model.NewStateChangeEventPayloadFromReader(bytes.NewReader(payload))
+		require.NoError(t, err)
+		assert.Equal(t, "event1", eventPayload.EventID)
+	})
+
+	t.Run("cloudevents structured", func(t *testing.T) {
+		payload, contentType, headers, err := buildEventPayload(model.SubscriptionPayloadFormatCloudEventsStructured, data)
+		require.NoError(t, err)
+		assert.Equal(t, "application/cloudevents+json", contentType)
+		assert.Empty(t, headers)
+
+		var cloudEvent model.CloudEvent
+		err = json.Unmarshal(payload, &cloudEvent)
+		require.NoError(t, err)
+		assert.Equal(t, data.EventData.ToCloudEvent(), cloudEvent)
+	})
+
+	t.Run("cloudevents binary", func(t *testing.T) {
+		payload, contentType, headers, err := buildEventPayload(model.SubscriptionPayloadFormatCloudEventsBinary, data)
+		require.NoError(t, err)
+		assert.Equal(t, "application/json", contentType)
+		assert.Equal(t, "event1", headers["ce-id"])
+		assert.Equal(t, "abcd", headers["ce-subject"])
+
+		eventPayload, err := model.NewStateChangeEventPayloadFromReader(bytes.NewReader(payload))
+		require.NoError(t, err)
+		assert.Equal(t, "event1", eventPayload.EventID)
+	})
+}
+
 func successEventHandler(t *testing.T, deliveryChan chan<- *model.StateChangeEventPayload) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		payload, err := model.NewStateChangeEventPayloadFromReader(r.Body)
@@ -251,3 +359,35 @@ func createFixedEventData(t *testing.T, sqlStore *store.SQLStore) model.StateCha
 
 	return eventData
 }
+
+type mockDeliveryMetrics struct {
+	subscriptionID string
+	sinkType       string
+	delivered      bool
+	latency        time.Duration
+}
+
+func (m *mockDeliveryMetrics) ObserveEventDeliveryAttempt(subscriptionID, sinkType string, delivered bool, latency time.Duration) {
+	m.subscriptionID = subscriptionID
+	m.sinkType = sinkType
+	m.delivered = delivered
+	m.latency = latency
+}
+
+func TestObserveDeliveryAttempt(t *testing.T) {
+	metrics := &mockDeliveryMetrics{}
+	worker := &sender{metrics: metrics}
+
+	sub := &model.Subscription{ID: "subscription1"}
+	delivery := &model.StateChangeEventDeliveryData{
+		EventData: model.StateChangeEventData{
+			Event: model.Event{Timestamp: model.GetMillis() - 60*1000},
+		},
+	}
+
+	worker.observeDeliveryAttempt(sub, delivery, true)
+	assert.Equal(t, "subscription1", metrics.subscriptionID)
+	assert.Equal(t, string(model.SubscriptionSinkHTTP), metrics.sinkType)
+	assert.True(t, metrics.delivered)
+	assert.GreaterOrEqual(t, metrics.latency, time.Minute)
+}
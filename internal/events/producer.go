@@ -28,7 +28,7 @@ type DataField struct {
 	Value string
 }
 
-// EventProducer produces Provisioners' state change events.
+// EventProducer produces Provisioners' events.
 type EventProducer struct {
 	store       producerStore
 	signaler    deliverySignaler
@@ -97,11 +97,123 @@ func (e *EventProducer) ProduceClusterInstallationStateChangeEvent(clusterInstal
 	return e.produceStateChangeEvent(stateChangeEvent, extraData)
 }
 
+// ProduceInstallationSpecUpdateEvent produces event describing changes of the Installation specification.
+func (e *EventProducer) ProduceInstallationSpecUpdateEvent(installation *model.Installation, data *model.InstallationSpecUpdateEventData) error {
+	extraData := e.initExtraData(nil)
+	extraData["Name"] = installation.Name
+
+	return e.produceResourceEvent(model.InstallationSpecUpdateEventType, installationResource(installation), extraData, data)
+}
+
+// ProduceInstallationDNSRecordChangeEvent produces event describing DNS records added to or removed from the Installation.
+func (e *EventProducer) ProduceInstallationDNSRecordChangeEvent(installation *model.Installation, data *model.DNSRecordChangeEventData) error {
+	extraData := e.initExtraData(nil)
+	extraData["Name"] = installation.Name
+
+	return e.produceResourceEvent(model.DNSRecordChangeEventType, installationResource(installation), extraData, data)
+}
+
+// ProduceAnnotationChangeEvent produces event describing annotations added to or removed from the resource.
+func (e *EventProducer) ProduceAnnotationChangeEvent(resourceType model.ResourceType, resourceID, state string, data *model.AnnotationChangeEventData) error {
+	resource := model.StateChangeEvent{
+		OldState:     state,
+		NewState:     state,
+		ResourceID:   resourceID,
+		ResourceType: resourceType,
+	}
+
+	return e.produceResourceEvent(model.AnnotationChangeEventType, resource, e.initExtraData(nil), data)
+}
+
+// ProduceGroupConfigChangeEvent produces event describing changes of the Group configuration.
+func (e *EventProducer) ProduceGroupConfigChangeEvent(group *model.Group, data *model.GroupConfigChangeEventData) error {
+	resource := model.StateChangeEvent{
+		OldState:     model.NonApplicableState,
+		NewState:     model.NonApplicableState,
+		ResourceID:   group.ID,
+		ResourceType: model.TypeGroup,
+	}
+
+	extraData := e.initExtraData(nil)
+	extraData["Name"] = group.Name
+
+	return e.produceResourceEvent(model.GroupConfigChangeEventType, resource, extraData, data)
+}
+
+// ProduceClusterUtilityVersionChangeEvent produces event describing version change of a utility deployed to the Cluster.
+func (e *EventProducer) ProduceClusterUtilityVersionChangeEvent(cluster *model.Cluster, data *model.ClusterUtilityVersionChangeEventData) error {
+	resource := model.StateChangeEvent{
+		OldState:     cluster.State,
+		NewState:     cluster.State,
+		ResourceID:   cluster.ID,
+		ResourceType: model.TypeCluster,
+	}
+
+	return e.produceResourceEvent(model.ClusterUtilityVersionChangeEventType, resource, e.initExtraData(nil), data)
+}
+
+// ProduceClusterUtilityUnhealthyEvent produces event describing a utility deployed to the Cluster becoming unhealthy.
+func (e *EventProducer) ProduceClusterUtilityUnhealthyEvent(cluster *model.Cluster, data *model.ClusterUtilityUnhealthyEventData) error {
+	resource := model.StateChangeEvent{
+		OldState:     cluster.State,
+		NewState:     cluster.State,
+		ResourceID:   cluster.ID,
+		ResourceType: model.TypeCluster,
+	}
+
+	return e.produceResourceEvent(model.ClusterUtilityUnhealthyEventType, resource, e.initExtraData(nil), data)
+}
+
+// ProduceLockChangeEvent produces event describing API security lock or unlock of the resource.
+func (e *EventProducer) ProduceLockChangeEvent(resourceType model.ResourceType, resourceID, state string, locked bool) error {
+	resource := model.StateChangeEvent{
+		OldState:     state,
+		NewState:     state,
+		ResourceID:   resourceID,
+		ResourceType: resourceType,
+	}
+
+	return e.produceResourceEvent(model.LockChangeEventType, resource, e.initExtraData(nil), &model.LockChangeEventData{Locked: locked})
+}
+
+func installationResource(installation *model.Installation) model.StateChangeEvent {
+	return model.StateChangeEvent{
+		OldState:     installation.State,
+		NewState:     installation.State,
+		ResourceID:   installation.ID,
+		ResourceType: model.TypeInstallation,
+	}
+}
+
 func (e *EventProducer) produceStateChangeEvent(stateChangeEvent model.StateChangeEvent, extraData map[string]string) error {
+	eventData, err := e.produceEvent(model.ResourceStateChangeEventType, stateChangeEvent, model.EventExtraData{Fields: extraData})
+	if err != nil {
+		return err
+	}
+
+	webhookPayload := eventData.ToWebhookPayload()
+
+	e.sendWebhook(&webhookPayload, e.logger.WithField("event", eventData.Event.ID))
+	return nil
+}
+
+// produceResourceEvent produces event other than state change for a resource.
+// Webhooks are not sent for such events as they are consumed only by subscriptions.
+func (e *EventProducer) produceResourceEvent(eventType model.EventType, resource model.StateChangeEvent, extraData map[string]string, data interface{}) error {
+	rawData, err := model.MarshalEventData(data)
+	if err != nil {
+		return err
+	}
+
+	_, err = e.produceEvent(eventType, resource, model.EventExtraData{Fields: extraData, Data: rawData})
+	return err
+}
+
+func (e *EventProducer) produceEvent(eventType model.EventType, stateChangeEvent model.StateChangeEvent, extraData model.EventExtraData) (*model.StateChangeEventData, error) {
 	event := model.Event{
-		EventType: model.ResourceStateChangeEventType,
+		EventType: eventType,
 		Timestamp: model.GetMillis(),
-		ExtraData: model.EventExtraData{Fields: extraData},
+		ExtraData: extraData,
 	}
 
 	eventData := model.StateChangeEventData{
@@ -111,17 +223,12 @@ func (e *EventProducer) produceStateChangeEvent(stateChangeEvent model.StateChan
 
 	err := e.store.CreateStateChangeEvent(&eventData)
 	if err != nil {
-		return errors.Wrap(err, "failed to create state change event")
+		return nil, errors.Wrapf(err, "failed to create %s event", eventType)
 	}
 
-	log := e.logger.WithField("event", eventData.Event.ID)
-
-	e.signaler.SignalNewEvents(model.ResourceStateChangeEventType)
+	e.signaler.SignalNewEvents(eventType)
 
-	webhookPayload := eventData.ToWebhookPayload()
-
-	e.sendWebhook(&webhookPayload, log)
-	return nil
+	return &eventData, nil
 }
 
 func (e *EventProducer) sendWebhook(payload *model.WebhookPayload, log logrus.FieldLogger) {
@@ -6,6 +6,7 @@ package events
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -103,6 +104,70 @@ func Test_ProduceAndDeliverEvents(t *testing.T) {
 	assert.Equal(t, installation.Name, webhookPayload.ExtraData["Name"])
 }
 
+func Test_ProduceAndDeliverTypedEvents(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	defer store.CloseConnection(t, sqlStore)
+
+	installation := &model.Installation{
+		Name:  "test",
+		State: model.InstallationStateStable,
+	}
+	err := sqlStore.CreateInstallation(installation, nil, nil)
+	require.NoError(t, err)
+
+	eventChan := make(chan *model.StateChangeEventPayload)
+
+	r := mux.NewRouter()
+	r.HandleFunc("/event", func(w http.ResponseWriter, r *http.Request) {
+		payload, errHandler := model.NewStateChangeEventPayloadFromReader(r.Body)
+		require.NoError(t, errHandler)
+		eventChan <- payload
+	})
+	consumerSever := httptest.NewServer(r)
+
+	subscription := &model.Subscription{
+		URL:                fmt.Sprintf("%s/event", consumerSever.URL),
+		EventType:          model.InstallationSpecUpdateEventType,
+		LastDeliveryStatus: model.SubscriptionDeliveryNone,
+	}
+	err = sqlStore.CreateSubscription(subscription)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	eventsDeliverer := NewDeliverer(ctx, sqlStore, model.NewID(), logger, DelivererConfig{MaxBurstWorkers: 5})
+	eventProducer := NewProducer(sqlStore, eventsDeliverer, "test", logger)
+
+	// Events of other types should not be delivered to the subscription.
+	err = eventProducer.ProduceLockChangeEvent(model.TypeInstallation, installation.ID, installation.State, true)
+	require.NoError(t, err)
+
+	specUpdate := &model.InstallationSpecUpdateEventData{
+		Changes: map[string]model.ValueChange{"Version": {Old: "7.0.0", New: "7.1.0"}},
+	}
+	err = eventProducer.ProduceInstallationSpecUpdateEvent(installation, specUpdate)
+	require.NoError(t, err)
+
+	var eventPayload *model.StateChangeEventPayload
+	select {
+	case eventPayload = <-eventChan:
+	case <-time.After(3 * time.Second):
+		t.Fatal("timeout waiting for event")
+	}
+
+	assert.Equal(t, model.InstallationSpecUpdateEventType, eventPayload.EventType)
+	assert.Equal(t, installation.ID, eventPayload.ResourceID)
+	assert.Equal(t, model.InstallationStateStable, eventPayload.NewState)
+	assert.Equal(t, installation.Name, eventPayload.ExtraData["Name"])
+
+	var data model.InstallationSpecUpdateEventData
+	err = json.Unmarshal(eventPayload.Data, &data)
+	require.NoError(t, err)
+	assert.Equal(t, specUpdate, &data)
+}
+
 func awaitWebhookAndEvent(webhookChan <-chan *model.WebhookPayload, eventChan <-chan *model.StateChangeEventPayload) (*model.WebhookPayload, *model.StateChangeEventPayload, error) {
 	gotWebhook := false
 	gotEvent := false
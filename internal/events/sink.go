@@ -0,0 +1,101 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package events
+
+import (
+	"context"
+	"io"
+
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// eventIDHeader is set on every message so that consumers of brokers
+	// without native deduplication can discard redelivered events.
+	eventIDHeader = "X-Provisioner-Event-ID"
+)
+
+// SinkMessage is an encoded event ready to be sent by EventSink.
+type SinkMessage struct {
+	EventID string
+	// Key groups messages which order has to be preserved, it is set to
+	// the resource ID. Brokers use it for partitioning.
+	Key         string
+	Body        []byte
+	ContentType string
+	Headers     map[string]string
+}
+
+// EventSink sends events to the subscription target.
+//
+// Sinks provide at-least-once semantics: returned error causes the delivery
+// to be retried until the subscription failure threshold is reached, which
+// is tracked by the store. Nil error marks the event as delivered.
+type EventSink interface {
+	Send(ctx context.Context, target string, msg *SinkMessage, log logrus.FieldLogger) error
+}
+
+// EventSinks maps sink types to their implementations.
+type EventSinks map[model.SubscriptionSinkType]EventSink
+
+// defaultSinks returns sinks which do not require any external configuration.
+func defaultSinks(httpSink EventSink) EventSinks {
+	return EventSinks{
+		model.SubscriptionSinkHTTP:  httpSink,
+		model.SubscriptionSinkNATS:  NewNATSSink(),
+		model.SubscriptionSinkKafka: NewKafkaSink(),
+	}
+}
+
+func (s EventSinks) get(sinkType model.SubscriptionSinkType) (EventSink, error) {
+	if sinkType == "" {
+		sinkType = model.SubscriptionSinkHTTP
+	}
+	sink, ok := s[sinkType]
+	if !ok {
+		return nil, errors.Errorf("sink %q is not configured", sinkType)
+	}
+	return sink, nil
+}
+
+// close releases connections held by the sinks.
+func (s EventSinks) close(log logrus.FieldLogger) {
+	for sinkType, sink := range s {
+		closer, ok := sink.(io.Closer)
+		if !ok {
+			continue
+		}
+		err := closer.Close()
+		if err != nil {
+			log.WithError(err).WithField("sink", sinkType).Error("Failed to close event sink")
+		}
+	}
+}
+
+// newSinkMessage encodes the event in the payload format of the subscription.
+func newSinkMessage(sub *model.Subscription, data *model.StateChangeEventDeliveryData) (*SinkMessage, error) {
+	payload, contentType, formatHeaders, err := buildEventPayload(sub.PayloadFormat, data)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build event payload")
+	}
+
+	headers := map[string]string{eventIDHeader: data.EventData.Event.ID}
+	for key, value := range formatHeaders {
+		headers[key] = value
+	}
+	for key, value := range data.EventHeaders.GetHeaders() {
+		headers[key] = value
+	}
+
+	return &SinkMessage{
+		EventID:     data.EventData.Event.ID,
+		Key:         data.EventData.StateChange.ResourceID,
+		Body:        payload,
+		ContentType: contentType,
+		Headers:     headers,
+	}, nil
+}
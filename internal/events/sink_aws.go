@@ -0,0 +1,141 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package events
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	snsTypes "github.com/aws/aws-sdk-go-v2/service/sns/types"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	sqsTypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// awsMaxMessageAttributes is the limit of message attributes in SQS and SNS.
+	awsMaxMessageAttributes = 10
+
+	awsFIFOSuffix = ".fifo"
+)
+
+// SQSAPI represents the series of calls we require from the AWS SDK v2 SQS Client
+type SQSAPI interface {
+	SendMessage(ctx context.Context, params *sqs.SendMessageInput, optFns ...func(*sqs.Options)) (*sqs.SendMessageOutput, error)
+}
+
+// SNSAPI represents the series of calls we require from the AWS SDK v2 SNS Client
+type SNSAPI interface {
+	Publish(ctx context.Context, params *sns.PublishInput, optFns ...func(*sns.Options)) (*sns.PublishOutput, error)
+}
+
+// SQSSink sends events to AWS SQS queues.
+type SQSSink struct {
+	client SQSAPI
+}
+
+// NewSQSSink creates new SQSSink.
+func NewSQSSink(client SQSAPI) *SQSSink {
+	return &SQSSink{client: client}
+}
+
+// Send sends the message to the queue with the target URL. Headers are sent
+// as message attributes. For FIFO queues the resource ID is used as message
+// group and the event ID for deduplication.
+func (s *SQSSink) Send(ctx context.Context, target string, msg *SinkMessage, log logrus.FieldLogger) error {
+	input := &sqs.SendMessageInput{
+		QueueUrl:          aws.String(target),
+		MessageBody:       aws.String(string(msg.Body)),
+		MessageAttributes: map[string]sqsTypes.MessageAttributeValue{},
+	}
+	for key, value := range messageAttributes(msg, log) {
+		input.MessageAttributes[key] = sqsTypes.MessageAttributeValue{
+			DataType:    aws.String("String"),
+			StringValue: aws.String(value),
+		}
+	}
+	if strings.HasSuffix(target, awsFIFOSuffix) {
+		input.MessageGroupId = aws.String(msg.Key)
+		input.MessageDeduplicationId = aws.String(msg.EventID)
+	}
+
+	_, err := s.client.SendMessage(ctx, input)
+	if err != nil {
+		return errors.Wrap(err, "failed to send event to SQS")
+	}
+
+	return nil
+}
+
+// SNSSink publishes events to AWS SNS topics.
+type SNSSink struct {
+	client SNSAPI
+}
+
+// NewSNSSink creates new SNSSink.
+func NewSNSSink(client SNSAPI) *SNSSink {
+	return &SNSSink{client: client}
+}
+
+// Send publishes the message to the topic with the target ARN. Headers are
+// sent as message attributes. For FIFO topics the resource ID is used as
+// message group and the event ID for deduplication.
+func (s *SNSSink) Send(ctx context.Context, target string, msg *SinkMessage, log logrus.FieldLogger) error {
+	input := &sns.PublishInput{
+		TopicArn:          aws.String(target),
+		Message:           aws.String(string(msg.Body)),
+		MessageAttributes: map[string]snsTypes.MessageAttributeValue{},
+	}
+	for key, value := range messageAttributes(msg, log) {
+		input.MessageAttributes[key] = snsTypes.MessageAttributeValue{
+			DataType:    aws.String("String"),
+			StringValue: aws.String(value),
+		}
+	}
+	if strings.HasSuffix(target, awsFIFOSuffix) {
+		input.MessageGroupId = aws.String(msg.Key)
+		input.MessageDeduplicationId = aws.String(msg.EventID)
+	}
+
+	_, err := s.client.Publish(ctx, input)
+	if err != nil {
+		return errors.Wrap(err, "failed to publish event to SNS")
+	}
+
+	return nil
+}
+
+// messageAttributes returns message headers to be sent as SQS or SNS
+// attributes. AWS allows at most 10 attributes, so the content type and
+// event ID are always included and the remaining headers are included in
+// alphabetical order until the limit is reached.
+func messageAttributes(msg *SinkMessage, log logrus.FieldLogger) map[string]string {
+	attributes := map[string]string{
+		"Content-Type": msg.ContentType,
+		eventIDHeader:  msg.EventID,
+	}
+
+	keys := make([]string, 0, len(msg.Headers))
+	for key := range msg.Headers {
+		if _, ok := attributes[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for i, key := range keys {
+		if len(attributes) == awsMaxMessageAttributes {
+			log.Warnf("Message attributes limit reached, skipping %d headers", len(keys)-i)
+			break
+		}
+		attributes[key] = msg.Headers[key]
+	}
+
+	return attributes
+}
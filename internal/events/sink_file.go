@@ -0,0 +1,120 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package events
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// FileSink appends events to a local file or stdout, one event per line.
+// It is intended for local development and debugging, and only writes to
+// files within its base directory.
+type FileSink struct {
+	lock    sync.Mutex
+	baseDir string
+	stdout  io.Writer
+}
+
+// NewFileSink creates new FileSink writing to files within baseDir.
+func NewFileSink(baseDir string) (*FileSink, error) {
+	if baseDir == "" {
+		return nil, errors.New("file sink directory must be set")
+	}
+	absDir, err := filepath.Abs(baseDir)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get absolute path of file sink directory")
+	}
+	resolvedDir, err := filepath.EvalSymlinks(absDir)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to resolve file sink directory")
+	}
+	info, err := os.Stat(resolvedDir)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to stat file sink directory")
+	}
+	if !info.IsDir() {
+		return nil, errors.Errorf("file sink path %s is not a directory", baseDir)
+	}
+
+	return &FileSink{baseDir: resolvedDir, stdout: os.Stdout}, nil
+}
+
+// Send writes the message body followed by a new line.
+func (s *FileSink) Send(ctx context.Context, target string, msg *SinkMessage, log logrus.FieldLogger) error {
+	path, err := model.ParseFileSinkURL(target)
+	if err != nil {
+		return err
+	}
+
+	line := append(append([]byte{}, msg.Body...), '\n')
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if path == model.SubscriptionSinkFileStdout {
+		_, err = s.stdout.Write(line)
+		if err != nil {
+			return errors.Wrap(err, "failed to write event to stdout")
+		}
+		return nil
+	}
+
+	path, err = s.resolvePath(path)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return errors.Wrap(err, "failed to open events file")
+	}
+	defer file.Close()
+
+	_, err = file.Write(line)
+	if err != nil {
+		return errors.Wrap(err, "failed to write event to file")
+	}
+
+	return nil
+}
+
+// resolvePath resolves symlinks of the events file path and ensures the
+// result is within the base directory of the sink.
+func (s *FileSink) resolvePath(path string) (string, error) {
+	path = filepath.Clean(path)
+	dir, err := filepath.EvalSymlinks(filepath.Dir(path))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to resolve events file directory")
+	}
+	resolved := filepath.Join(dir, filepath.Base(path))
+
+	// The events file itself may be a symlink leading out of the directory.
+	// Dangling symlinks are rejected as opening them creates their target.
+	info, err := os.Lstat(resolved)
+	if err == nil && info.Mode()&os.ModeSymlink != 0 {
+		resolved, err = filepath.EvalSymlinks(resolved)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to resolve events file")
+		}
+	} else if err != nil && !os.IsNotExist(err) {
+		return "", errors.Wrap(err, "failed to stat events file")
+	}
+
+	rel, err := filepath.Rel(s.baseDir, resolved)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", errors.Errorf("events file %s is outside of the file sink directory", path)
+	}
+
+	return resolved, nil
+}
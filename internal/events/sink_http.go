@@ -0,0 +1,56 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package events
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// HTTPSink delivers events with HTTP POST requests.
+type HTTPSink struct {
+	client *http.Client
+}
+
+// NewHTTPSink creates new HTTPSink.
+func NewHTTPSink(client *http.Client) *HTTPSink {
+	return &HTTPSink{client: client}
+}
+
+// Send posts the message to the target URL. Server errors are retried, other
+// non-successful responses are treated as consumer errors and not retried.
+func (s *HTTPSink) Send(ctx context.Context, target string, msg *SinkMessage, log logrus.FieldLogger) error {
+	req, err := http.NewRequestWithContext(ctx, "POST", target, bytes.NewBuffer(msg.Body))
+	if err != nil {
+		log.WithField("subscription_url", target).WithError(err).Error("Unable to create request")
+		return errors.Wrap(err, "unable to create request from payload")
+	}
+	req.Header.Set("Content-Type", msg.ContentType)
+	for key, value := range msg.Headers {
+		req.Header.Set(key, value)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "failed to deliver event")
+	}
+	defer drainBody(resp.Body)
+	if resp.StatusCode >= 500 {
+		return errors.Errorf(
+			"consumer failed to receive event, got %d response code, body: %s",
+			resp.StatusCode, attemptToReadBody(resp.Body),
+		)
+	}
+	// CloudEvents consumers, such as Knative brokers, respond with 202 Accepted.
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		log.Errorf("Event delivery resulted in status %d. Treating it as consumer error, delivery will not be retried",
+			resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package events
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+	"github.com/segmentio/kafka-go"
+	"github.com/sirupsen/logrus"
+)
+
+// KafkaSink produces events to Kafka topics. Writers are reused between
+// deliveries to the same brokers and topic.
+type KafkaSink struct {
+	lock    sync.Mutex
+	writers map[string]*kafka.Writer
+}
+
+// NewKafkaSink creates new KafkaSink.
+func NewKafkaSink() *KafkaSink {
+	return &KafkaSink{writers: map[string]*kafka.Writer{}}
+}
+
+// Send produces the message to the topic from the target URL and waits for
+// acknowledgement of all in-sync replicas. Messages are keyed with resource
+// ID, so that events of single resource land in the same partition in order.
+func (s *KafkaSink) Send(ctx context.Context, target string, msg *SinkMessage, log logrus.FieldLogger) error {
+	writer, err := s.getWriter(target)
+	if err != nil {
+		return err
+	}
+
+	headers := []kafka.Header{{Key: "Content-Type", Value: []byte(msg.ContentType)}}
+	for key, value := range msg.Headers {
+		headers = append(headers, kafka.Header{Key: key, Value: []byte(value)})
+	}
+
+	err = writer.WriteMessages(ctx, kafka.Message{
+		Key:     []byte(msg.Key),
+		Value:   msg.Body,
+		Headers: headers,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to produce event to Kafka")
+	}
+
+	return nil
+}
+
+func (s *KafkaSink) getWriter(target string) (*kafka.Writer, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if writer, ok := s.writers[target]; ok {
+		return writer, nil
+	}
+
+	brokers, topic, err := model.ParseKafkaSinkURL(target)
+	if err != nil {
+		return nil, err
+	}
+	writer := &kafka.Writer{
+		Addr:         kafka.TCP(brokers...),
+		Topic:        topic,
+		Balancer:     &kafka.Hash{},
+		RequiredAcks: kafka.RequireAll,
+		// Events are sent one by one, do not wait for batch to fill up.
+		BatchTimeout: 10 * time.Millisecond,
+		MaxAttempts:  1,
+	}
+	s.writers[target] = writer
+
+	return writer, nil
+}
+
+// Close flushes and closes all Kafka writers.
+func (s *KafkaSink) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var lastErr error
+	for target, writer := range s.writers {
+		err := writer.Close()
+		if err != nil {
+			lastErr = errors.Wrapf(err, "failed to close Kafka writer for %s", target)
+		}
+		delete(s.writers, target)
+	}
+	return lastErr
+}
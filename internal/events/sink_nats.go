@@ -0,0 +1,131 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package events
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"sync"
+
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/nats-io/nats.go"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// NATSSink publishes events to NATS subjects, optionally through JetStream.
+// Connections are reused between deliveries to the same server.
+type NATSSink struct {
+	lock  sync.Mutex
+	conns map[string]*nats.Conn
+}
+
+// NewNATSSink creates new NATSSink.
+func NewNATSSink() *NATSSink {
+	return &NATSSink{conns: map[string]*nats.Conn{}}
+}
+
+// Send publishes the message to the subject from the target URL. With
+// JetStream enabled the event ID is used as the message ID, so that the
+// stream discards duplicates of retried deliveries.
+func (s *NATSSink) Send(ctx context.Context, target string, msg *SinkMessage, log logrus.FieldLogger) error {
+	server, subject, err := model.ParseNATSSinkURL(target)
+	if err != nil {
+		return err
+	}
+	jetStream, err := natsJetStreamEnabled(target)
+	if err != nil {
+		return err
+	}
+
+	conn, err := s.getConn(server)
+	if err != nil {
+		return err
+	}
+
+	natsMsg := nats.NewMsg(subject)
+	natsMsg.Data = msg.Body
+	natsMsg.Header.Set("Content-Type", msg.ContentType)
+	for key, value := range msg.Headers {
+		natsMsg.Header.Set(key, value)
+	}
+
+	if jetStream {
+		natsMsg.Header.Set(nats.MsgIdHdr, msg.EventID)
+		js, err := conn.JetStream()
+		if err != nil {
+			return errors.Wrap(err, "failed to get JetStream context")
+		}
+		_, err = js.PublishMsg(natsMsg, nats.Context(ctx))
+		if err != nil {
+			return errors.Wrap(err, "failed to publish event to JetStream")
+		}
+		return nil
+	}
+
+	err = conn.PublishMsg(natsMsg)
+	if err != nil {
+		return errors.Wrap(err, "failed to publish event to NATS")
+	}
+	// Flushing waits for the server to process the message, it requires a deadline.
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, sinkSendTimeout)
+		defer cancel()
+	}
+	err = conn.FlushWithContext(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to flush NATS connection")
+	}
+
+	return nil
+}
+
+func (s *NATSSink) getConn(server string) (*nats.Conn, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if conn, ok := s.conns[server]; ok && !conn.IsClosed() {
+		return conn, nil
+	}
+
+	conn, err := nats.Connect(server, nats.Name("mattermost-cloud-provisioner"))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to connect to NATS")
+	}
+	s.conns[server] = conn
+
+	return conn, nil
+}
+
+// Close closes all NATS connections.
+func (s *NATSSink) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for server, conn := range s.conns {
+		conn.Close()
+		delete(s.conns, server)
+	}
+	return nil
+}
+
+func natsJetStreamEnabled(target string) (bool, error) {
+	u, err := url.Parse(target)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to parse NATS sink URL")
+	}
+	value := u.Query().Get("jetstream")
+	if value == "" {
+		return false, nil
+	}
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, errors.Wrap(err, "invalid jetstream parameter of NATS sink URL")
+	}
+
+	return enabled, nil
+}
@@ -0,0 +1,379 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package events
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/nats-io/nats.go"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testSinkMessage() *SinkMessage {
+	return &SinkMessage{
+		EventID:     "event1",
+		Key:         "resource1",
+		Body:        []byte(`{"eventId":"event1"}`),
+		ContentType: contentTypeApplicationJSON,
+		Headers:     map[string]string{eventIDHeader: "event1", "X-Custom": "custom"},
+	}
+}
+
+func TestNewSinkMessage(t *testing.T) {
+	value := "custom"
+	sub := &model.Subscription{PayloadFormat: model.SubscriptionPayloadFormatCloudEventsBinary}
+	data := &model.StateChangeEventDeliveryData{
+		EventData: model.StateChangeEventData{
+			Event:       model.Event{ID: "event1", EventType: model.ResourceStateChangeEventType},
+			StateChange: model.StateChangeEvent{ResourceID: "resource1", ResourceType: model.TypeInstallation},
+		},
+		EventHeaders: model.Headers{{Key: "X-Custom", Value: &value}},
+	}
+
+	msg, err := newSinkMessage(sub, data)
+	require.NoError(t, err)
+	assert.Equal(t, "event1", msg.EventID)
+	assert.Equal(t, "resource1", msg.Key)
+	assert.Equal(t, contentTypeApplicationJSON, msg.ContentType)
+	assert.Equal(t, "event1", msg.Headers[eventIDHeader])
+	assert.Equal(t, "event1", msg.Headers["ce-id"])
+	assert.Equal(t, "custom", msg.Headers["X-Custom"])
+}
+
+func TestEventSinks_Get(t *testing.T) {
+	sinks := defaultSinks(NewHTTPSink(http.DefaultClient))
+
+	sink, err := sinks.get("")
+	require.NoError(t, err)
+	assert.IsType(t, &HTTPSink{}, sink)
+
+	_, err = sinks.get(model.SubscriptionSinkSQS)
+	require.Error(t, err)
+}
+
+func TestHTTPSink(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sink := NewHTTPSink(http.DefaultClient)
+
+	var received *http.Request
+	var body []byte
+	statusCode := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(statusCode)
+	}))
+	defer server.Close()
+
+	err := sink.Send(context.Background(), server.URL, testSinkMessage(), logger)
+	require.NoError(t, err)
+	assert.Equal(t, contentTypeApplicationJSON, received.Header.Get("Content-Type"))
+	assert.Equal(t, "custom", received.Header.Get("X-Custom"))
+	assert.Equal(t, `{"eventId":"event1"}`, string(body))
+
+	t.Run("consumer error is not retried", func(t *testing.T) {
+		statusCode = http.StatusBadRequest
+		err = sink.Send(context.Background(), server.URL, testSinkMessage(), logger)
+		require.NoError(t, err)
+	})
+
+	t.Run("server error is retried", func(t *testing.T) {
+		statusCode = http.StatusInternalServerError
+		err = sink.Send(context.Background(), server.URL, testSinkMessage(), logger)
+		require.Error(t, err)
+	})
+}
+
+func TestFileSink(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	dir := t.TempDir()
+	sink, err := NewFileSink(dir)
+	require.NoError(t, err)
+
+	path := filepath.Join(dir, "events.ndjson")
+	for i := 0; i < 2; i++ {
+		err := sink.Send(context.Background(), "file://"+path, testSinkMessage(), logger)
+		require.NoError(t, err)
+	}
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "{\"eventId\":\"event1\"}\n{\"eventId\":\"event1\"}\n", string(content))
+
+	stdout := &bytes.Buffer{}
+	sink.stdout = stdout
+	err = sink.Send(context.Background(), model.SubscriptionSinkFileStdout, testSinkMessage(), logger)
+	require.NoError(t, err)
+	assert.Equal(t, "{\"eventId\":\"event1\"}\n", stdout.String())
+}
+
+func TestFileSink_RejectsPathsOutsideDirectory(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	dir := t.TempDir()
+	outside := t.TempDir()
+	sink, err := NewFileSink(dir)
+	require.NoError(t, err)
+
+	outsideFile := filepath.Join(outside, "authorized_keys")
+	require.NoError(t, os.Symlink(outsideFile, filepath.Join(dir, "link.ndjson")))
+	require.NoError(t, os.Symlink(outside, filepath.Join(dir, "linkdir")))
+
+	for _, path := range []string{
+		outsideFile,
+		dir,
+		filepath.Join(dir, "..", filepath.Base(outside), "events.ndjson"),
+		filepath.Join(dir, "link.ndjson"),
+		filepath.Join(dir, "linkdir", "events.ndjson"),
+	} {
+		t.Run(path, func(t *testing.T) {
+			err := sink.Send(context.Background(), "file://"+path, testSinkMessage(), logger)
+			require.Error(t, err)
+		})
+	}
+
+	_, err = os.Stat(outsideFile)
+	assert.True(t, os.IsNotExist(err))
+	_, err = os.Stat(filepath.Join(outside, "events.ndjson"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestNewFileSink(t *testing.T) {
+	_, err := NewFileSink("")
+	require.Error(t, err)
+
+	file := filepath.Join(t.TempDir(), "file")
+	require.NoError(t, os.WriteFile(file, nil, 0600))
+	_, err = NewFileSink(file)
+	require.Error(t, err)
+}
+
+type mockSQSClient struct {
+	input *sqs.SendMessageInput
+}
+
+func (m *mockSQSClient) SendMessage(ctx context.Context, params *sqs.SendMessageInput, optFns ...func(*sqs.Options)) (*sqs.SendMessageOutput, error) {
+	m.input = params
+	return &sqs.SendMessageOutput{}, nil
+}
+
+type mockSNSClient struct {
+	input *sns.PublishInput
+}
+
+func (m *mockSNSClient) Publish(ctx context.Context, params *sns.PublishInput, optFns ...func(*sns.Options)) (*sns.PublishOutput, error) {
+	m.input = params
+	return &sns.PublishOutput{}, nil
+}
+
+func TestSQSSink(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	client := &mockSQSClient{}
+	sink := NewSQSSink(client)
+
+	queueURL := "https://sqs.us-east-1.amazonaws.com/123456789012/events"
+	err := sink.Send(context.Background(), queueURL, testSinkMessage(), logger)
+	require.NoError(t, err)
+	assert.Equal(t, queueURL, *client.input.QueueUrl)
+	assert.Equal(t, `{"eventId":"event1"}`, *client.input.MessageBody)
+	assert.Equal(t, "custom", *client.input.MessageAttributes["X-Custom"].StringValue)
+	assert.Equal(t, "event1", *client.input.MessageAttributes[eventIDHeader].StringValue)
+	assert.Nil(t, client.input.MessageGroupId)
+
+	err = sink.Send(context.Background(), queueURL+".fifo", testSinkMessage(), logger)
+	require.NoError(t, err)
+	assert.Equal(t, "resource1", *client.input.MessageGroupId)
+	assert.Equal(t, "event1", *client.input.MessageDeduplicationId)
+}
+
+func TestSNSSink(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	client := &mockSNSClient{}
+	sink := NewSNSSink(client)
+
+	topicARN := "arn:aws:sns:us-east-1:123456789012:events.fifo"
+	err := sink.Send(context.Background(), topicARN, testSinkMessage(), logger)
+	require.NoError(t, err)
+	assert.Equal(t, topicARN, *client.input.TopicArn)
+	assert.Equal(t, `{"eventId":"event1"}`, *client.input.Message)
+	assert.Equal(t, contentTypeApplicationJSON, *client.input.MessageAttributes["Content-Type"].StringValue)
+	assert.Equal(t, "resource1", *client.input.MessageGroupId)
+	assert.Equal(t, "event1", *client.input.MessageDeduplicationId)
+}
+
+func TestMessageAttributes_Limit(t *testing.T) {
+	msg := testSinkMessage()
+	for i := 0; i < 20; i++ {
+		msg.Headers[fmt.Sprintf("X-Header-%02d", i)] = "value"
+	}
+
+	attributes := messageAttributes(msg, testlib.MakeLogger(t))
+	assert.Len(t, attributes, awsMaxMessageAttributes)
+	assert.Equal(t, contentTypeApplicationJSON, attributes["Content-Type"])
+	assert.Equal(t, "event1", attributes[eventIDHeader])
+	assert.Contains(t, attributes, "X-Header-00")
+}
+
+// natsPublish is a message captured by fakeNATSServer.
+type natsPublish struct {
+	subject string
+	headers string
+	payload string
+}
+
+// fakeNATSServer is a local stand-in for NATS server supporting the subset
+// of the protocol used for core NATS publishing.
+func fakeNATSServer(t *testing.T) (string, <-chan natsPublish) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	published := make(chan natsPublish, 10)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		fmt.Fprint(conn, "INFO {\"server_id\":\"fake\",\"version\":\"2.10.0\",\"proto\":1,\"headers\":true,\"max_payload\":1048576}\r\n")
+		reader := bufio.NewReader(conn)
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			fields := strings.Fields(line)
+			if len(fields) == 0 {
+				continue
+			}
+			switch fields[0] {
+			case "PING":
+				fmt.Fprint(conn, "PONG\r\n")
+			case "HPUB":
+				headerSize, _ := strconv.Atoi(fields[len(fields)-2])
+				totalSize, _ := strconv.Atoi(fields[len(fields)-1])
+				data := make([]byte, totalSize+2)
+				_, err = io.ReadFull(reader, data)
+				if err != nil {
+					return
+				}
+				published <- natsPublish{
+					subject: fields[1],
+					headers: string(data[:headerSize]),
+					payload: string(data[headerSize:totalSize]),
+				}
+			}
+		}
+	}()
+
+	return "nats://" + listener.Addr().String(), published
+}
+
+func TestNATSSink(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	server, published := fakeNATSServer(t)
+
+	sink := NewNATSSink()
+	defer sink.Close()
+
+	err := sink.Send(context.Background(), server+"/provisioner.events", testSinkMessage(), logger)
+	require.NoError(t, err)
+
+	select {
+	case msg := <-published:
+		assert.Equal(t, "provisioner.events", msg.subject)
+		assert.Equal(t, `{"eventId":"event1"}`, msg.payload)
+		assert.Contains(t, msg.headers, "X-Custom: custom")
+		assert.Contains(t, msg.headers, "Content-Type: application/json")
+	case <-time.After(3 * time.Second):
+		t.Fatal("timeout waiting for NATS message")
+	}
+}
+
+// TestNATSSink_JetStream requires NATS server with JetStream enabled,
+// e.g. started with `docker compose --profile events up -d`.
+func TestNATSSink_JetStream(t *testing.T) {
+	natsURL := os.Getenv("CLOUD_EVENTS_NATS_URL")
+	if natsURL == "" {
+		t.Skip("CLOUD_EVENTS_NATS_URL not set")
+	}
+	logger := testlib.MakeLogger(t)
+
+	subject := "provisioner.test." + model.NewID()
+	conn, err := nats.Connect(natsURL)
+	require.NoError(t, err)
+	defer conn.Close()
+	js, err := conn.JetStream()
+	require.NoError(t, err)
+	stream, err := js.AddStream(&nats.StreamConfig{Name: "PROVISIONER_TEST_" + model.NewID(), Subjects: []string{subject}})
+	require.NoError(t, err)
+	defer js.DeleteStream(stream.Config.Name)
+
+	sink := NewNATSSink()
+	defer sink.Close()
+
+	// Retried delivery of the same event is deduplicated by the stream.
+	for i := 0; i < 2; i++ {
+		err = sink.Send(context.Background(), natsURL+"/"+subject+"?jetstream=true", testSinkMessage(), logger)
+		require.NoError(t, err)
+	}
+
+	info, err := js.StreamInfo(stream.Config.Name)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), info.State.Msgs)
+}
+
+// TestKafkaSink requires Kafka compatible broker, e.g. started with
+// `docker compose --profile events up -d`.
+func TestKafkaSink(t *testing.T) {
+	brokers := os.Getenv("CLOUD_EVENTS_KAFKA_BROKERS")
+	if brokers == "" {
+		t.Skip("CLOUD_EVENTS_KAFKA_BROKERS not set")
+	}
+	logger := testlib.MakeLogger(t)
+
+	topic := "provisioner-test-" + model.NewID()
+	conn, err := kafka.Dial("tcp", strings.Split(brokers, ",")[0])
+	require.NoError(t, err)
+	err = conn.CreateTopics(kafka.TopicConfig{Topic: topic, NumPartitions: 1, ReplicationFactor: 1})
+	require.NoError(t, err)
+	conn.Close()
+
+	sink := NewKafkaSink()
+	defer sink.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	err = sink.Send(ctx, fmt.Sprintf("kafka://%s/%s", brokers, topic), testSinkMessage(), logger)
+	require.NoError(t, err)
+
+	reader := kafka.NewReader(kafka.ReaderConfig{Brokers: strings.Split(brokers, ","), Topic: topic})
+	defer reader.Close()
+
+	msg, err := reader.ReadMessage(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "resource1", string(msg.Key))
+	assert.Equal(t, `{"eventId":"event1"}`, string(msg.Value))
+}
@@ -5,7 +5,11 @@
 package metrics
 
 import (
+	"math"
 	"strconv"
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/model"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
@@ -41,6 +45,24 @@ type CloudMetrics struct {
 	ClusterProvisioningDurationHist *prometheus.HistogramVec
 	ClusterResizeDurationHist       *prometheus.HistogramVec
 	ClusterDeletionDurationHist     *prometheus.HistogramVec
+
+	// MultitenantDatabase
+	MultitenantDatabaseWeightGauge              *prometheus.GaugeVec
+	MultitenantDatabaseMaxWeightGauge           *prometheus.GaugeVec
+	MultitenantDatabaseUtilizationGauge         *prometheus.GaugeVec
+	MultitenantDatabaseLogicalDatabaseFillGauge *prometheus.GaugeVec
+	MultitenantDatabaseGrowthPerDayGauge        *prometheus.GaugeVec
+	MultitenantDatabaseDaysUntilExhaustionGauge *prometheus.GaugeVec
+
+	// Events
+	EventRowsPrunedCounter *prometheus.CounterVec
+
+	// Subscription
+	SubscriptionDeliveryLatencyHist       *prometheus.HistogramVec
+	SubscriptionDeliveryAttemptsCounter   *prometheus.CounterVec
+	SubscriptionDeliveryFailuresCounter   *prometheus.CounterVec
+	SubscriptionBacklogGauge              *prometheus.GaugeVec
+	SubscriptionOldestUndeliveredAgeGauge *prometheus.GaugeVec
 }
 
 // New creates a new Prometheus-based Metrics object to be used
@@ -191,6 +213,118 @@ func New() *CloudMetrics {
 			},
 			[]string{},
 		),
+
+		MultitenantDatabaseWeightGauge: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: provisionerNamespace,
+				Subsystem: provisionerSubsystemApp,
+				Name:      "multitenant_database_weight",
+				Help:      "The total installation weight of multitenant databases",
+			},
+			multitenantDatabaseLabels(),
+		),
+		MultitenantDatabaseMaxWeightGauge: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: provisionerNamespace,
+				Subsystem: provisionerSubsystemApp,
+				Name:      "multitenant_database_max_weight",
+				Help:      "The maximum installation weight supported by multitenant databases",
+			},
+			multitenantDatabaseLabels(),
+		),
+		MultitenantDatabaseUtilizationGauge: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: provisionerNamespace,
+				Subsystem: provisionerSubsystemApp,
+				Name:      "multitenant_database_utilization_ratio",
+				Help:      "The ratio of used to maximum installation weight of multitenant databases",
+			},
+			multitenantDatabaseLabels(),
+		),
+		MultitenantDatabaseLogicalDatabaseFillGauge: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: provisionerNamespace,
+				Subsystem: provisionerSubsystemApp,
+				Name:      "multitenant_database_logical_database_fill_ratio",
+				Help:      "The ratio of installations to logical database slots of proxy multitenant databases",
+			},
+			multitenantDatabaseLabels(),
+		),
+		MultitenantDatabaseGrowthPerDayGauge: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: provisionerNamespace,
+				Subsystem: provisionerSubsystemApp,
+				Name:      "multitenant_database_weight_growth_per_day",
+				Help:      "The installation weight added per day to multitenant databases",
+			},
+			multitenantDatabaseLabels(),
+		),
+		MultitenantDatabaseDaysUntilExhaustionGauge: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: provisionerNamespace,
+				Subsystem: provisionerSubsystemApp,
+				Name:      "multitenant_database_days_until_exhaustion",
+				Help:      "The projected number of days until multitenant databases reach their maximum weight",
+			},
+			multitenantDatabaseLabels(),
+		),
+
+		EventRowsPrunedCounter: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: provisionerNamespace,
+				Subsystem: provisionerSubsystemApp,
+				Name:      "event_rows_pruned_total",
+				Help:      "The total number of rows pruned from the event tables by the retention supervisor",
+			},
+			[]string{"table"},
+		),
+
+		SubscriptionDeliveryLatencyHist: promauto.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: provisionerNamespace,
+				Subsystem: provisionerSubsystemApp,
+				Name:      "subscription_delivery_latency_seconds",
+				Help:      "The time from the occurrence of events to their successful delivery to subscriptions",
+				Buckets:   deliveryLatencyBuckets(),
+			},
+			[]string{"subscription", "sink"},
+		),
+		SubscriptionDeliveryAttemptsCounter: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: provisionerNamespace,
+				Subsystem: provisionerSubsystemApp,
+				Name:      "subscription_delivery_attempts_total",
+				Help:      "The total number of event delivery attempts to subscriptions",
+			},
+			[]string{"subscription", "sink"},
+		),
+		SubscriptionDeliveryFailuresCounter: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: provisionerNamespace,
+				Subsystem: provisionerSubsystemApp,
+				Name:      "subscription_delivery_failures_total",
+				Help:      "The total number of failed event delivery attempts to subscriptions",
+			},
+			[]string{"subscription", "sink"},
+		),
+		SubscriptionBacklogGauge: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: provisionerNamespace,
+				Subsystem: provisionerSubsystemApp,
+				Name:      "subscription_backlog_events",
+				Help:      "The number of events awaiting delivery to subscriptions",
+			},
+			[]string{"subscription"},
+		),
+		SubscriptionOldestUndeliveredAgeGauge: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: provisionerNamespace,
+				Subsystem: provisionerSubsystemApp,
+				Name:      "subscription_oldest_undelivered_event_age_seconds",
+				Help:      "The age of the oldest event awaiting delivery to subscriptions",
+			},
+			[]string{"subscription"},
+		),
 	}
 }
 
@@ -204,7 +338,92 @@ func (cm *CloudMetrics) ObserveAPIEndpointDuration(handler, method string, statu
 	cm.APITimesHistograms.With(prometheus.Labels{"handler": handler, "method": method, "status_code": strconv.Itoa(statusCode)}).Observe(elapsed)
 }
 
+// ObserveMultitenantDatabaseCapacities replaces the multitenant database
+// capacity gauges with the provided capacities.
+func (cm *CloudMetrics) ObserveMultitenantDatabaseCapacities(capacities []*model.MultitenantDatabaseCapacity) {
+	cm.MultitenantDatabaseWeightGauge.Reset()
+	cm.MultitenantDatabaseMaxWeightGauge.Reset()
+	cm.MultitenantDatabaseUtilizationGauge.Reset()
+	cm.MultitenantDatabaseLogicalDatabaseFillGauge.Reset()
+	cm.MultitenantDatabaseGrowthPerDayGauge.Reset()
+	cm.MultitenantDatabaseDaysUntilExhaustionGauge.Reset()
+
+	now := model.GetMillis()
+	for _, capacity := range capacities {
+		labels := prometheus.Labels{
+			"multitenant_database": capacity.MultitenantDatabaseID,
+			"vpc":                  capacity.VpcID,
+			"database_type":        capacity.DatabaseType,
+		}
+		cm.MultitenantDatabaseWeightGauge.With(labels).Set(capacity.Weight)
+		cm.MultitenantDatabaseMaxWeightGauge.With(labels).Set(capacity.MaxWeight)
+		cm.MultitenantDatabaseUtilizationGauge.With(labels).Set(capacity.Utilization)
+		cm.MultitenantDatabaseGrowthPerDayGauge.With(labels).Set(capacity.WeightGrowthPerDay)
+		if model.IsProxyDatabaseType(capacity.DatabaseType) {
+			cm.MultitenantDatabaseLogicalDatabaseFillGauge.With(labels).Set(capacity.LogicalDatabaseFill)
+		}
+		if capacity.ProjectedExhaustionAt != 0 {
+			days := float64(capacity.ProjectedExhaustionAt-now) / float64(24*time.Hour/time.Millisecond)
+			cm.MultitenantDatabaseDaysUntilExhaustionGauge.With(labels).Set(math.Max(days, 0))
+		}
+	}
+}
+
+// ObserveEventsPruned increases the pruned rows counters of event tables.
+func (cm *CloudMetrics) ObserveEventsPruned(result *model.EventsPruneResult) {
+	cm.EventRowsPrunedCounter.With(prometheus.Labels{"table": "Event"}).Add(float64(result.Events))
+	cm.EventRowsPrunedCounter.With(prometheus.Labels{"table": "StateChangeEvent"}).Add(float64(result.StateChangeEvents))
+	cm.EventRowsPrunedCounter.With(prometheus.Labels{"table": "EventDelivery"}).Add(float64(result.EventDeliveries))
+}
+
+// ObserveEventDeliveryAttempt records an attempt to deliver an event to the
+// subscription. Latency is only recorded for successful deliveries.
+func (cm *CloudMetrics) ObserveEventDeliveryAttempt(subscriptionID, sinkType string, delivered bool, latency time.Duration) {
+	labels := prometheus.Labels{"subscription": subscriptionID, "sink": sinkType}
+	cm.SubscriptionDeliveryAttemptsCounter.With(labels).Inc()
+	if !delivered {
+		cm.SubscriptionDeliveryFailuresCounter.With(labels).Inc()
+		return
+	}
+	cm.SubscriptionDeliveryLatencyHist.With(labels).Observe(latency.Seconds())
+}
+
+// ObserveSubscriptionsStats replaces the subscription backlog gauges with
+// the provided stats.
+func (cm *CloudMetrics) ObserveSubscriptionsStats(stats []*model.SubscriptionStats) {
+	cm.SubscriptionBacklogGauge.Reset()
+	cm.SubscriptionOldestUndeliveredAgeGauge.Reset()
+
+	now := model.GetMillis()
+	for _, s := range stats {
+		labels := prometheus.Labels{"subscription": s.SubscriptionID}
+		cm.SubscriptionBacklogGauge.With(labels).Set(float64(s.Backlog))
+		cm.SubscriptionOldestUndeliveredAgeGauge.With(labels).Set(s.OldestUndeliveredEventAge(now).Seconds())
+	}
+}
+
+// DeleteSubscriptionMetrics removes the series of the given subscription so
+// that deleted subscriptions are no longer exported.
+func (cm *CloudMetrics) DeleteSubscriptionMetrics(subscriptionID string) {
+	labels := prometheus.Labels{"subscription": subscriptionID}
+	cm.SubscriptionDeliveryLatencyHist.DeletePartialMatch(labels)
+	cm.SubscriptionDeliveryAttemptsCounter.DeletePartialMatch(labels)
+	cm.SubscriptionDeliveryFailuresCounter.DeletePartialMatch(labels)
+	cm.SubscriptionBacklogGauge.Delete(labels)
+	cm.SubscriptionOldestUndeliveredAgeGauge.Delete(labels)
+}
+
+func multitenantDatabaseLabels() []string {
+	return []string{"multitenant_database", "vpc", "database_type"}
+}
+
 // 15 second buckets up to 5 minutes.
 func standardDurationBuckets() []float64 {
 	return prometheus.LinearBuckets(0, 15, 20)
 }
+
+// Exponential buckets from 100 milliseconds up to about 5 hours, covering
+// retried deliveries.
+func deliveryLatencyBuckets() []float64 {
+	return prometheus.ExponentialBuckets(0.1, 3, 12)
+}
@@ -137,6 +137,26 @@ func (mr *MockRDSAPIMockRecorder) DeleteDBCluster(arg0, arg1 interface{}, arg2 .
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDBCluster", reflect.TypeOf((*MockRDSAPI)(nil).DeleteDBCluster), varargs...)
 }
 
+// RestoreDBClusterToPointInTime mocks base method
+func (m *MockRDSAPI) RestoreDBClusterToPointInTime(arg0 context.Context, arg1 *rds.RestoreDBClusterToPointInTimeInput, arg2 ...func(*rds.Options)) (*rds.RestoreDBClusterToPointInTimeOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RestoreDBClusterToPointInTime", varargs...)
+	ret0, _ := ret[0].(*rds.RestoreDBClusterToPointInTimeOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreDBClusterToPointInTime indicates an expected call of RestoreDBClusterToPointInTime
+func (mr *MockRDSAPIMockRecorder) RestoreDBClusterToPointInTime(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreDBClusterToPointInTime", reflect.TypeOf((*MockRDSAPI)(nil).RestoreDBClusterToPointInTime), varargs...)
+}
+
 // DeleteDBInstance mocks base method
 func (m *MockRDSAPI) DeleteDBInstance(arg0 context.Context, arg1 *rds.DeleteDBInstanceInput, arg2 ...func(*rds.Options)) (*rds.DeleteDBInstanceOutput, error) {
 	m.ctrl.T.Helper()
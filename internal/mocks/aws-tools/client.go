@@ -15,6 +15,7 @@ import (
 	logrus "github.com/sirupsen/logrus"
 	v1 "k8s.io/api/core/v1"
 	reflect "reflect"
+	time "time"
 )
 
 // MockAWS is a mock of AWS interface
@@ -314,6 +315,20 @@ func (mr *MockAWSMockRecorder) S3EnsureObjectDeleted(bucketName, path interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "S3EnsureObjectDeleted", reflect.TypeOf((*MockAWS)(nil).S3EnsureObjectDeleted), bucketName, path)
 }
 
+// S3PutObject mocks base method
+func (m *MockAWS) S3PutObject(bucketName, path, contentType string, body []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "S3PutObject", bucketName, path, contentType, body)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// S3PutObject indicates an expected call of S3PutObject
+func (mr *MockAWSMockRecorder) S3PutObject(bucketName, path, contentType, body interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "S3PutObject", reflect.TypeOf((*MockAWS)(nil).S3PutObject), bucketName, path, contentType, body)
+}
+
 // S3LargeCopy mocks base method
 func (m *MockAWS) S3LargeCopy(srcBucketName, srcKey, destBucketName, destKey *string, logger logrus.FieldLogger) error {
 	m.ctrl.T.Helper()
@@ -357,6 +372,139 @@ func (mr *MockAWSMockRecorder) GetS3RegionURL() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetS3RegionURL", reflect.TypeOf((*MockAWS)(nil).GetS3RegionURL))
 }
 
+// GetS3FilestoreLocation mocks base method
+func (m *MockAWS) GetS3FilestoreLocation(installationID, filestoreType string, store model.InstallationDatabaseStoreInterface) (*aws.S3FilestoreLocation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetS3FilestoreLocation", installationID, filestoreType, store)
+	ret0, _ := ret[0].(*aws.S3FilestoreLocation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetS3FilestoreLocation indicates an expected call of GetS3FilestoreLocation
+func (mr *MockAWSMockRecorder) GetS3FilestoreLocation(installationID, filestoreType, store interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetS3FilestoreLocation", reflect.TypeOf((*MockAWS)(nil).GetS3FilestoreLocation), installationID, filestoreType, store)
+}
+
+// S3CopyObjectsBatch mocks base method
+func (m *MockAWS) S3CopyObjectsBatch(source, destination *aws.S3FilestoreLocation, continuationToken string, logger logrus.FieldLogger) (*aws.S3CopyBatchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "S3CopyObjectsBatch", source, destination, continuationToken, logger)
+	ret0, _ := ret[0].(*aws.S3CopyBatchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// S3CopyObjectsBatch indicates an expected call of S3CopyObjectsBatch
+func (mr *MockAWSMockRecorder) S3CopyObjectsBatch(source, destination, continuationToken, logger interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "S3CopyObjectsBatch", reflect.TypeOf((*MockAWS)(nil).S3CopyObjectsBatch), source, destination, continuationToken, logger)
+}
+
+// S3GetObjectsSummary mocks base method
+func (m *MockAWS) S3GetObjectsSummary(location *aws.S3FilestoreLocation) (*aws.S3ObjectsSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "S3GetObjectsSummary", location)
+	ret0, _ := ret[0].(*aws.S3ObjectsSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// S3GetObjectsSummary indicates an expected call of S3GetObjectsSummary
+func (mr *MockAWSMockRecorder) S3GetObjectsSummary(location interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "S3GetObjectsSummary", reflect.TypeOf((*MockAWS)(nil).S3GetObjectsSummary), location)
+}
+
+// S3CompareObjects mocks base method
+func (m *MockAWS) S3CompareObjects(source, destination *aws.S3FilestoreLocation) (*aws.S3ObjectsComparison, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "S3CompareObjects", source, destination)
+	ret0, _ := ret[0].(*aws.S3ObjectsComparison)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// S3CompareObjects indicates an expected call of S3CompareObjects
+func (mr *MockAWSMockRecorder) S3CompareObjects(source, destination interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "S3CompareObjects", reflect.TypeOf((*MockAWS)(nil).S3CompareObjects), source, destination)
+}
+
+// GetInstallationDatabaseInstanceClasses mocks base method
+func (m *MockAWS) GetInstallationDatabaseInstanceClasses(installationID string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInstallationDatabaseInstanceClasses", installationID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInstallationDatabaseInstanceClasses indicates an expected call of GetInstallationDatabaseInstanceClasses
+func (mr *MockAWSMockRecorder) GetInstallationDatabaseInstanceClasses(installationID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInstallationDatabaseInstanceClasses", reflect.TypeOf((*MockAWS)(nil).GetInstallationDatabaseInstanceClasses), installationID)
+}
+
+// GetMultitenantDatabaseInstanceClasses mocks base method
+func (m *MockAWS) GetMultitenantDatabaseInstanceClasses(rdsClusterID string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMultitenantDatabaseInstanceClasses", rdsClusterID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMultitenantDatabaseInstanceClasses indicates an expected call of GetMultitenantDatabaseInstanceClasses
+func (mr *MockAWSMockRecorder) GetMultitenantDatabaseInstanceClasses(rdsClusterID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMultitenantDatabaseInstanceClasses", reflect.TypeOf((*MockAWS)(nil).GetMultitenantDatabaseInstanceClasses), rdsClusterID)
+}
+
+// RDSRestoreDBClusterToPointInTime mocks base method
+func (m *MockAWS) RDSRestoreDBClusterToPointInTime(installationID, restorationID string, restoreTime time.Time, logger logrus.FieldLogger) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RDSRestoreDBClusterToPointInTime", installationID, restorationID, restoreTime, logger)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RDSRestoreDBClusterToPointInTime indicates an expected call of RDSRestoreDBClusterToPointInTime
+func (mr *MockAWSMockRecorder) RDSRestoreDBClusterToPointInTime(installationID, restorationID, restoreTime, logger interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RDSRestoreDBClusterToPointInTime", reflect.TypeOf((*MockAWS)(nil).RDSRestoreDBClusterToPointInTime), installationID, restorationID, restoreTime, logger)
+}
+
+// RDSCheckPointInTimeRestore mocks base method
+func (m *MockAWS) RDSCheckPointInTimeRestore(installationID, restorationID string, logger logrus.FieldLogger) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RDSCheckPointInTimeRestore", installationID, restorationID, logger)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RDSCheckPointInTimeRestore indicates an expected call of RDSCheckPointInTimeRestore
+func (mr *MockAWSMockRecorder) RDSCheckPointInTimeRestore(installationID, restorationID, logger interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RDSCheckPointInTimeRestore", reflect.TypeOf((*MockAWS)(nil).RDSCheckPointInTimeRestore), installationID, restorationID, logger)
+}
+
+// RDSCleanupPointInTimeRestore mocks base method
+func (m *MockAWS) RDSCleanupPointInTimeRestore(installationID, restorationID string, logger logrus.FieldLogger) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RDSCleanupPointInTimeRestore", installationID, restorationID, logger)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RDSCleanupPointInTimeRestore indicates an expected call of RDSCleanupPointInTimeRestore
+func (mr *MockAWSMockRecorder) RDSCleanupPointInTimeRestore(installationID, restorationID, logger interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RDSCleanupPointInTimeRestore", reflect.TypeOf((*MockAWS)(nil).RDSCleanupPointInTimeRestore), installationID, restorationID, logger)
+}
+
 // GeneratePerseusUtilitySecret mocks base method
 func (m *MockAWS) GeneratePerseusUtilitySecret(clusterID string, logger logrus.FieldLogger) (*v1.Secret, error) {
 	m.ctrl.T.Helper()
@@ -402,6 +550,21 @@ func (mr *MockAWSMockRecorder) GetCIDRByVPCTag(vpcTagName, logger interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCIDRByVPCTag", reflect.TypeOf((*MockAWS)(nil).GetCIDRByVPCTag), vpcTagName, logger)
 }
 
+// GetCIDRByVPCID mocks base method
+func (m *MockAWS) GetCIDRByVPCID(vpcID string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCIDRByVPCID", vpcID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCIDRByVPCID indicates an expected call of GetCIDRByVPCID
+func (mr *MockAWSMockRecorder) GetCIDRByVPCID(vpcID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCIDRByVPCID", reflect.TypeOf((*MockAWS)(nil).GetCIDRByVPCID), vpcID)
+}
+
 // FixSubnetTagsForVPC mocks base method
 func (m *MockAWS) FixSubnetTagsForVPC(vpc string, logger logrus.FieldLogger) error {
 	m.ctrl.T.Helper()
@@ -534,6 +697,20 @@ func (mr *MockAWSMockRecorder) EnsureEKSNodeGroupMigrated(cluster, nodeGroupPref
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnsureEKSNodeGroupMigrated", reflect.TypeOf((*MockAWS)(nil).EnsureEKSNodeGroupMigrated), cluster, nodeGroupPrefix)
 }
 
+// EnsureEKSNodeGroupScaled mocks base method
+func (m *MockAWS) EnsureEKSNodeGroupScaled(clusterName, nodeGroupName string, minCount, desiredCount, maxCount int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnsureEKSNodeGroupScaled", clusterName, nodeGroupName, minCount, desiredCount, maxCount)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnsureEKSNodeGroupScaled indicates an expected call of EnsureEKSNodeGroupScaled
+func (mr *MockAWSMockRecorder) EnsureEKSNodeGroupScaled(clusterName, nodeGroupName, minCount, desiredCount, maxCount interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnsureEKSNodeGroupScaled", reflect.TypeOf((*MockAWS)(nil).EnsureEKSNodeGroupScaled), clusterName, nodeGroupName, minCount, desiredCount, maxCount)
+}
+
 // GetActiveEKSCluster mocks base method
 func (m *MockAWS) GetActiveEKSCluster(clusterName string) (*types.Cluster, error) {
 	m.ctrl.T.Helper()
@@ -80,7 +80,7 @@ func (o BackupOperator) TriggerBackup(
 		dataResidence.PathPrefix = installation.ID
 	}
 
-	envVars = append(envVars, prepareEnvs(dataResidence, storageEndpoint, fileStoreCfg.Secret, dbSecret)...)
+	envVars = append(envVars, prepareEnvs(dataResidence, storageEndpoint, fileStoreCfg.Secret, dbSecret, backup.BackedUpDatabaseType)...)
 
 	backupJobName := makeJobName(backupAction, backup.ID)
 	job := o.createBackupRestoreJob(backupJobName, installation.ID, backupAction, envVars, backupBackoffLimit)
@@ -125,7 +125,7 @@ func (o BackupOperator) TriggerRestore(
 		envVars = bifrostEnvs()
 	}
 
-	envVars = append(envVars, prepareEnvs(*backup.DataResidence, storageEndpoint, fileStoreCfg.Secret, dbSecret)...)
+	envVars = append(envVars, prepareEnvs(*backup.DataResidence, storageEndpoint, fileStoreCfg.Secret, dbSecret, backup.BackedUpDatabaseType)...)
 
 	restoreJobName := makeJobName(restoreAction, backup.ID)
 	job := o.createBackupRestoreJob(restoreJobName, installation.ID, restoreAction, envVars, restoreBackoffLimit)
@@ -285,7 +285,7 @@ func (o BackupOperator) createBackupRestoreContainer(action string, envs []corev
 	}
 }
 
-func prepareEnvs(dataRes model.S3DataResidence, endpoint string, fileStoreSecret, dbSecret string) []corev1.EnvVar {
+func prepareEnvs(dataRes model.S3DataResidence, endpoint string, fileStoreSecret, dbSecret, databaseType string) []corev1.EnvVar {
 	envs := []corev1.EnvVar{
 		{
 			Name:  "BRT_STORAGE_REGION",
@@ -311,6 +311,10 @@ func prepareEnvs(dataRes model.S3DataResidence, endpoint string, fileStoreSecret
 			Name:      "BRT_DATABASE",
 			ValueFrom: envSourceFromSecret(dbSecret, "DB_CONNECTION_STRING"),
 		},
+		{
+			Name:  "BRT_DATABASE_TYPE",
+			Value: model.BackupRestoreDatabaseEngine(databaseType),
+		},
 		{
 			Name:      "BRT_STORAGE_ACCESS_KEY",
 			ValueFrom: envSourceFromSecret(fileStoreSecret, "accesskey"),
@@ -34,9 +34,10 @@ func TestOperator_TriggerBackup(t *testing.T) {
 	databaseSecret := "database-secret"
 
 	backupMeta := &model.InstallationBackup{
-		ID:             "backup-1",
-		InstallationID: "installation-1",
-		State:          model.InstallationBackupStateBackupRequested,
+		ID:                   "backup-1",
+		InstallationID:       "installation-1",
+		BackedUpDatabaseType: model.InstallationDatabaseMultiTenantRDSMySQL,
+		State:                model.InstallationBackupStateBackupRequested,
 	}
 
 	operator := NewBackupOperator("mattermost/backup-restore:test", "us", 100)
@@ -106,6 +107,7 @@ func TestOperator_TriggerBackup(t *testing.T) {
 			assertEnvVarFromSecret(t, "BRT_STORAGE_ACCESS_KEY", fileStoreSecret, "accesskey", envs)
 			assertEnvVarFromSecret(t, "BRT_STORAGE_SECRET_KEY", fileStoreSecret, "secretkey", envs)
 			assertEnvVarFromSecret(t, "BRT_DATABASE", databaseSecret, "DB_CONNECTION_STRING", envs)
+			assertEnvVarEqual(t, "BRT_DATABASE_TYPE", model.DatabaseEngineTypeMySQL, envs)
 
 			for k, v := range testCase.extraEnvs {
 				assertEnvVarEqual(t, k, v, envs)
@@ -180,9 +182,10 @@ func TestOperator_TriggerBackup(t *testing.T) {
 // Tests CheckBackupStatus and CheckRestoreStatus as their logic is almost exactly the same
 func TestOperator_CheckJobStatus(t *testing.T) {
 	backupMeta := &model.InstallationBackup{
-		ID:             "backup-1",
-		InstallationID: "installation-1",
-		State:          model.InstallationBackupStateBackupRequested,
+		ID:                   "backup-1",
+		InstallationID:       "installation-1",
+		BackedUpDatabaseType: model.InstallationDatabaseMultiTenantRDSMySQL,
+		State:                model.InstallationBackupStateBackupRequested,
 	}
 
 	k8sClient := fake.NewSimpleClientset()
@@ -319,9 +322,10 @@ func TestOperator_TriggerRestore(t *testing.T) {
 	databaseSecret := "database-secret"
 
 	backupMeta := &model.InstallationBackup{
-		ID:             "backup-rest-1",
-		InstallationID: "installation-1",
-		State:          model.InstallationBackupStateBackupRequested,
+		ID:                   "backup-rest-1",
+		InstallationID:       "installation-1",
+		BackedUpDatabaseType: model.InstallationDatabaseMultiTenantRDSPostgres,
+		State:                model.InstallationBackupStateBackupRequested,
 		DataResidence: &model.S3DataResidence{
 			Region:     "us-east",
 			URL:        "filestore.com",
@@ -393,6 +397,7 @@ func TestOperator_TriggerRestore(t *testing.T) {
 			assertEnvVarFromSecret(t, "BRT_STORAGE_ACCESS_KEY", fileStoreSecret, "accesskey", envs)
 			assertEnvVarFromSecret(t, "BRT_STORAGE_SECRET_KEY", fileStoreSecret, "secretkey", envs)
 			assertEnvVarFromSecret(t, "BRT_DATABASE", databaseSecret, "DB_CONNECTION_STRING", envs)
+			assertEnvVarEqual(t, "BRT_DATABASE_TYPE", model.DatabaseEngineTypePostgres, envs)
 
 			for k, v := range testCase.extraEnvs {
 				assertEnvVarEqual(t, k, v, envs)
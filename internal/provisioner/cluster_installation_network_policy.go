@@ -0,0 +1,320 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package provisioner
+
+import (
+	"fmt"
+
+	"github.com/mattermost/mattermost-cloud/internal/provisioner/prometheus"
+	"github.com/mattermost/mattermost-cloud/internal/provisioner/utility"
+	"github.com/mattermost/mattermost-cloud/k8s"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	mattermostPort        = 8065
+	mattermostMetricsPort = 8067
+	mysqlPort             = 3306
+	postgresPort          = 5432
+	dnsPort               = 53
+	// bifrostPort is the port of the bifrost pods behind bifrostEndpoint.
+	bifrostPort = 8087
+
+	// metadataCIDR is the instance metadata endpoint, which installations
+	// must never reach.
+	metadataCIDR = "169.254.169.254/32"
+
+	// namespaceNameLabel is set by Kubernetes on every namespace.
+	namespaceNameLabel = "kubernetes.io/metadata.name"
+)
+
+const (
+	networkPolicyManifest       = "manifests/network-policies/mm-installation-netpol.yaml"
+	legacyNetworkPolicyManifest = "manifests/network-policies/mm-installation-netpol-legacy.yaml"
+)
+
+// legacyNetworkPolicyNames are the NetworkPolicies of
+// legacyNetworkPolicyManifest. They are superseded by the installation
+// network policies, and would cancel their restrictions as NetworkPolicies
+// are additive.
+var legacyNetworkPolicyNames = []string{
+	"deny-from-other-namespaces",
+	"external-mm-allow",
+	"external-mm-v1beta-allow",
+	"deny-metadata-access",
+}
+
+// outboundPorts are the TCP ports installations reach outside of the cluster
+// for integrations: HTTP and HTTPS for the push proxy, licensing,
+// marketplace, S3 and outgoing webhooks, and SMTP for email notifications.
+var outboundPorts = []int32{80, 443, 25, 465, 587}
+
+// ensureNetworkPolicies creates or updates the NetworkPolicies isolating the
+// installation from the other tenants of the cluster. When installation
+// network policies are disabled, any existing ones are removed.
+func (provisioner Provisioner) ensureNetworkPolicies(installation *model.Installation, clusterInstallation *model.ClusterInstallation, cluster *model.Cluster, k8sClient *k8s.KubeClient, logger log.FieldLogger) error {
+	if !provisioner.params.InstallationNetworkPolicies {
+		return deleteNetworkPolicies(clusterInstallation, k8sClient, logger)
+	}
+
+	databaseCIDR, err := provisioner.databaseEgressCIDR(installation, cluster)
+	if err != nil {
+		return errors.Wrap(err, "failed to get database egress CIDR")
+	}
+
+	for _, networkPolicy := range generateNetworkPolicies(installation, clusterInstallation, cluster, databaseCIDR) {
+		_, err := k8sClient.CreateOrUpdateNetworkPolicyV1(clusterInstallation.Namespace, networkPolicy)
+		if err != nil {
+			return errors.Wrapf(err, "failed to create or update NetworkPolicy %s", networkPolicy.Name)
+		}
+	}
+
+	err = deleteNamedNetworkPolicies(clusterInstallation.Namespace, legacyNetworkPolicyNames, k8sClient, logger)
+	if err != nil {
+		return errors.Wrap(err, "failed to delete legacy NetworkPolicies")
+	}
+
+	logger.Debug("Successfully ensured installation NetworkPolicies")
+	return nil
+}
+
+// databaseEgressCIDR returns the CIDR block the database of the installation
+// is reached in: the VPC of the cluster for RDS and external databases, or an
+// empty string for databases reached in cluster. External databases outside
+// of the cluster VPC need an egress exception.
+func (provisioner Provisioner) databaseEgressCIDR(installation *model.Installation, cluster *model.Cluster) (string, error) {
+	switch installation.Database {
+	case model.InstallationDatabaseSingleTenantRDSMySQL,
+		model.InstallationDatabaseMultiTenantRDSMySQL,
+		model.InstallationDatabaseSingleTenantRDSPostgres,
+		model.InstallationDatabaseMultiTenantRDSPostgres:
+		if cluster.VpcID() == "" {
+			return "", errors.New("cluster has no VPC for the installation database")
+		}
+	case model.InstallationDatabaseExternal:
+		if cluster.VpcID() == "" {
+			return "", nil
+		}
+	default:
+		return "", nil
+	}
+
+	return provisioner.awsClient.GetCIDRByVPCID(cluster.VpcID())
+}
+
+// networkPolicyManifests returns the NetworkPolicy manifests applied to every
+// installation namespace. The legacy manifest is only applied when
+// installation network policies are disabled.
+func networkPolicyManifests(installationNetworkPolicies bool) []string {
+	if installationNetworkPolicies {
+		return []string{networkPolicyManifest}
+	}
+
+	return []string{networkPolicyManifest, legacyNetworkPolicyManifest}
+}
+
+// deleteNetworkPolicies removes the NetworkPolicies managed for the
+// installation if they exist.
+func deleteNetworkPolicies(clusterInstallation *model.ClusterInstallation, k8sClient *k8s.KubeClient, logger log.FieldLogger) error {
+	names := []string{makeDefaultDenyNetworkPolicyName(clusterInstallation), makeAllowNetworkPolicyName(clusterInstallation)}
+
+	return deleteNamedNetworkPolicies(clusterInstallation.Namespace, names, k8sClient, logger)
+}
+
+// deleteNamedNetworkPolicies removes the given NetworkPolicies of the
+// namespace if they exist.
+func deleteNamedNetworkPolicies(namespace string, names []string, k8sClient *k8s.KubeClient, logger log.FieldLogger) error {
+	for _, name := range names {
+		err := k8sClient.DeleteNetworkPolicyV1(namespace, name)
+		if k8sErrors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return errors.Wrapf(err, "failed to delete NetworkPolicy %s", name)
+		}
+		logger.Debugf("Successfully deleted NetworkPolicy %s", name)
+	}
+
+	return nil
+}
+
+// generateNetworkPolicies returns a policy denying all traffic of the
+// installation pods, and a policy allowing the traffic they need: ingress
+// from nginx and metrics scraping from prometheus, and egress to DNS, to the
+// database and filestore of the installation and to outboundPorts. Databases
+// outside of the cluster are only reached in databaseCIDR. Traffic within the
+// namespace is allowed for operator managed databases and filestores.
+func generateNetworkPolicies(installation *model.Installation, clusterInstallation *model.ClusterInstallation, cluster *model.Cluster, databaseCIDR string) []*networkingv1.NetworkPolicy {
+	labels := generateClusterInstallationResourceLabels(installation, clusterInstallation, cluster)
+	policyTypes := []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress}
+	sameNamespace := networkingv1.NetworkPolicyPeer{PodSelector: &metav1.LabelSelector{}}
+
+	ingress := []networkingv1.NetworkPolicyIngressRule{
+		{From: []networkingv1.NetworkPolicyPeer{sameNamespace}},
+		{
+			From: []networkingv1.NetworkPolicyPeer{
+				namespacePeer(utility.NamespaceNginx),
+				namespacePeer(utility.NamespaceNginxInternal),
+			},
+			Ports: tcpPorts(mattermostPort),
+		},
+		{
+			From:  []networkingv1.NetworkPolicyPeer{namespacePeer(prometheus.Namespace)},
+			Ports: tcpPorts(mattermostMetricsPort),
+		},
+	}
+
+	egress := []networkingv1.NetworkPolicyEgressRule{
+		{To: []networkingv1.NetworkPolicyPeer{sameNamespace}},
+		{
+			To: []networkingv1.NetworkPolicyPeer{namespacePeer("kube-system")},
+			Ports: []networkingv1.NetworkPolicyPort{
+				{Protocol: protocolPtr(corev1.ProtocolUDP), Port: portPtr(dnsPort)},
+				{Protocol: protocolPtr(corev1.ProtocolTCP), Port: portPtr(dnsPort)},
+			},
+		},
+		externalEgressRule(outboundPorts...),
+	}
+
+	switch installation.Database {
+	case model.InstallationDatabaseMultiTenantRDSPostgresPGBouncer:
+		egress = append(egress, networkingv1.NetworkPolicyEgressRule{
+			To:    []networkingv1.NetworkPolicyPeer{namespacePeer(utility.NamespacePgbouncer)},
+			Ports: tcpPorts(postgresPort),
+		})
+	case model.InstallationDatabaseSingleTenantRDSMySQL, model.InstallationDatabaseMultiTenantRDSMySQL:
+		egress = append(egress, cidrEgressRule(databaseCIDR, mysqlPort))
+	case model.InstallationDatabasePerseus:
+		egress = append(egress, networkingv1.NetworkPolicyEgressRule{
+			To:    []networkingv1.NetworkPolicyPeer{namespacePeer(perseusNamespace)},
+			Ports: tcpPorts(postgresPort),
+		})
+	case model.InstallationDatabaseSingleTenantRDSPostgres, model.InstallationDatabaseMultiTenantRDSPostgres:
+		egress = append(egress, cidrEgressRule(databaseCIDR, postgresPort))
+	case model.InstallationDatabaseExternal:
+		// External databases are reached in the cluster VPC on the default
+		// MySQL and PostgreSQL ports, other addresses and ports need an
+		// exception.
+		if databaseCIDR != "" {
+			egress = append(egress, cidrEgressRule(databaseCIDR, mysqlPort, postgresPort))
+		}
+	}
+
+	// S3 filestores are reached over HTTPS, which is part of outboundPorts.
+	if installation.Filestore == model.InstallationFilestoreBifrost {
+		egress = append(egress, networkingv1.NetworkPolicyEgressRule{
+			To:    []networkingv1.NetworkPolicyPeer{namespacePeer(bifrostNamespace)},
+			Ports: tcpPorts(bifrostPort),
+		})
+	}
+
+	if exceptions := installation.NetworkPolicyExceptions; exceptions != nil {
+		for _, namespace := range exceptions.IngressNamespaces {
+			ingress = append(ingress, networkingv1.NetworkPolicyIngressRule{
+				From: []networkingv1.NetworkPolicyPeer{namespacePeer(namespace)},
+			})
+		}
+		for _, exception := range exceptions.Egress {
+			rule := networkingv1.NetworkPolicyEgressRule{
+				To: []networkingv1.NetworkPolicyPeer{{IPBlock: &networkingv1.IPBlock{CIDR: exception.CIDR}}},
+			}
+			if exception.Port != 0 {
+				rule.Ports = tcpPorts(exception.Port)
+			}
+			egress = append(egress, rule)
+		}
+	}
+
+	return []*networkingv1.NetworkPolicy{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      makeDefaultDenyNetworkPolicyName(clusterInstallation),
+				Namespace: clusterInstallation.Namespace,
+				Labels:    labels,
+			},
+			Spec: networkingv1.NetworkPolicySpec{
+				PodSelector: metav1.LabelSelector{},
+				PolicyTypes: policyTypes,
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      makeAllowNetworkPolicyName(clusterInstallation),
+				Namespace: clusterInstallation.Namespace,
+				Labels:    labels,
+			},
+			Spec: networkingv1.NetworkPolicySpec{
+				PodSelector: metav1.LabelSelector{},
+				PolicyTypes: policyTypes,
+				Ingress:     ingress,
+				Egress:      egress,
+			},
+		},
+	}
+}
+
+// externalEgressRule allows egress on the given TCP ports to any address but
+// the instance metadata endpoint.
+func externalEgressRule(ports ...int32) networkingv1.NetworkPolicyEgressRule {
+	return networkingv1.NetworkPolicyEgressRule{
+		To: []networkingv1.NetworkPolicyPeer{{
+			IPBlock: &networkingv1.IPBlock{
+				CIDR:   "0.0.0.0/0",
+				Except: []string{metadataCIDR},
+			},
+		}},
+		Ports: tcpPorts(ports...),
+	}
+}
+
+// cidrEgressRule allows egress on the given TCP ports to the CIDR block.
+func cidrEgressRule(cidr string, ports ...int32) networkingv1.NetworkPolicyEgressRule {
+	return networkingv1.NetworkPolicyEgressRule{
+		To:    []networkingv1.NetworkPolicyPeer{{IPBlock: &networkingv1.IPBlock{CIDR: cidr}}},
+		Ports: tcpPorts(ports...),
+	}
+}
+
+func namespacePeer(namespace string) networkingv1.NetworkPolicyPeer {
+	return networkingv1.NetworkPolicyPeer{
+		NamespaceSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{namespaceNameLabel: namespace},
+		},
+	}
+}
+
+func tcpPorts(ports ...int32) []networkingv1.NetworkPolicyPort {
+	var policyPorts []networkingv1.NetworkPolicyPort
+	for _, port := range ports {
+		policyPorts = append(policyPorts, networkingv1.NetworkPolicyPort{
+			Protocol: protocolPtr(corev1.ProtocolTCP),
+			Port:     portPtr(port),
+		})
+	}
+	return policyPorts
+}
+
+func protocolPtr(protocol corev1.Protocol) *corev1.Protocol {
+	return &protocol
+}
+
+func portPtr(port int32) *intstr.IntOrString {
+	value := intstr.FromInt32(port)
+	return &value
+}
+
+func makeDefaultDenyNetworkPolicyName(clusterInstallation *model.ClusterInstallation) string {
+	return fmt.Sprintf("%s-default-deny", makeClusterInstallationName(clusterInstallation))
+}
+
+func makeAllowNetworkPolicyName(clusterInstallation *model.ClusterInstallation) string {
+	return fmt.Sprintf("%s-allow", makeClusterInstallationName(clusterInstallation))
+}
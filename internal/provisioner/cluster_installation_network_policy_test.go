@@ -0,0 +1,270 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package provisioner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/mattermost/mattermost-cloud/internal/provisioner/prometheus"
+	"github.com/mattermost/mattermost-cloud/internal/provisioner/utility"
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/k8s"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestGenerateNetworkPolicies(t *testing.T) {
+	cluster := &model.Cluster{ID: "cluster1"}
+	clusterInstallation := &model.ClusterInstallation{ID: "ci1", InstallationID: "installation1", Namespace: "installation1"}
+
+	ports := func(policyPorts []networkingv1.NetworkPolicyPort) []int32 {
+		var ports []int32
+		for _, port := range policyPorts {
+			ports = append(ports, port.Port.IntVal)
+		}
+		return ports
+	}
+	namespace := func(peer networkingv1.NetworkPolicyPeer) string {
+		return peer.NamespaceSelector.MatchLabels[namespaceNameLabel]
+	}
+
+	t.Run("default deny and allow policies", func(t *testing.T) {
+		installation := &model.Installation{
+			ID:        "installation1",
+			Database:  model.InstallationDatabaseMultiTenantRDSPostgresPGBouncer,
+			Filestore: model.InstallationFilestoreBifrost,
+		}
+
+		policies := generateNetworkPolicies(installation, clusterInstallation, cluster, "")
+		require.Len(t, policies, 2)
+
+		deny := policies[0]
+		assert.Equal(t, "mm-inst-default-deny", deny.Name)
+		assert.Equal(t, "installation1", deny.Namespace)
+		assert.Empty(t, deny.Spec.Ingress)
+		assert.Empty(t, deny.Spec.Egress)
+		assert.ElementsMatch(t, []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress}, deny.Spec.PolicyTypes)
+
+		allow := policies[1]
+		assert.Equal(t, "mm-inst-allow", allow.Name)
+		assert.Equal(t, "installation1", allow.Labels["installation-id"])
+
+		require.Len(t, allow.Spec.Ingress, 3)
+		assert.Equal(t, utility.NamespaceNginx, namespace(allow.Spec.Ingress[1].From[0]))
+		assert.Equal(t, utility.NamespaceNginxInternal, namespace(allow.Spec.Ingress[1].From[1]))
+		assert.Equal(t, []int32{mattermostPort}, ports(allow.Spec.Ingress[1].Ports))
+		assert.Equal(t, prometheus.Namespace, namespace(allow.Spec.Ingress[2].From[0]))
+		assert.Equal(t, []int32{mattermostMetricsPort}, ports(allow.Spec.Ingress[2].Ports))
+
+		require.Len(t, allow.Spec.Egress, 5)
+		assert.Equal(t, utility.NamespacePgbouncer, namespace(allow.Spec.Egress[3].To[0]))
+		assert.Equal(t, []int32{postgresPort}, ports(allow.Spec.Egress[3].Ports))
+		assert.Equal(t, bifrostNamespace, namespace(allow.Spec.Egress[4].To[0]))
+		assert.Equal(t, []int32{bifrostPort}, ports(allow.Spec.Egress[4].Ports))
+	})
+
+	t.Run("bifrost installation reaches outbound integrations", func(t *testing.T) {
+		installation := &model.Installation{
+			ID:        "installation1",
+			Database:  model.InstallationDatabaseMultiTenantRDSPostgresPGBouncer,
+			Filestore: model.InstallationFilestoreBifrost,
+		}
+
+		allow := generateNetworkPolicies(installation, clusterInstallation, cluster, "")[1]
+		outbound := allow.Spec.Egress[2]
+		require.Len(t, outbound.To, 1)
+		assert.Equal(t, "0.0.0.0/0", outbound.To[0].IPBlock.CIDR)
+		assert.Equal(t, []string{metadataCIDR}, outbound.To[0].IPBlock.Except)
+		assert.ElementsMatch(t, []int32{80, 443, 25, 465, 587}, ports(outbound.Ports))
+	})
+
+	t.Run("rds database in vpc and s3 filestore", func(t *testing.T) {
+		installation := &model.Installation{
+			ID:        "installation1",
+			Database:  model.InstallationDatabaseSingleTenantRDSMySQL,
+			Filestore: model.InstallationFilestoreAwsS3,
+		}
+
+		allow := generateNetworkPolicies(installation, clusterInstallation, cluster, "10.10.0.0/16")[1]
+		require.Len(t, allow.Spec.Egress, 4)
+		assert.Equal(t, "10.10.0.0/16", allow.Spec.Egress[3].To[0].IPBlock.CIDR)
+		assert.Empty(t, allow.Spec.Egress[3].To[0].IPBlock.Except)
+		assert.Equal(t, []int32{mysqlPort}, ports(allow.Spec.Egress[3].Ports))
+		assert.Contains(t, ports(allow.Spec.Egress[2].Ports), int32(443))
+	})
+
+	t.Run("external database", func(t *testing.T) {
+		installation := &model.Installation{
+			ID:        "installation1",
+			Database:  model.InstallationDatabaseExternal,
+			Filestore: model.InstallationFilestoreAwsS3,
+		}
+
+		allow := generateNetworkPolicies(installation, clusterInstallation, cluster, "10.10.0.0/16")[1]
+		require.Len(t, allow.Spec.Egress, 4)
+		assert.Equal(t, "10.10.0.0/16", allow.Spec.Egress[3].To[0].IPBlock.CIDR)
+		assert.Equal(t, []int32{mysqlPort, postgresPort}, ports(allow.Spec.Egress[3].Ports))
+
+		allow = generateNetworkPolicies(installation, clusterInstallation, cluster, "")[1]
+		assert.Len(t, allow.Spec.Egress, 3)
+	})
+
+	t.Run("perseus database is reached in cluster", func(t *testing.T) {
+		installation := &model.Installation{
+			ID:        "installation1",
+			Database:  model.InstallationDatabasePerseus,
+			Filestore: model.InstallationFilestoreBifrost,
+		}
+
+		allow := generateNetworkPolicies(installation, clusterInstallation, cluster, "")[1]
+		require.Len(t, allow.Spec.Egress, 5)
+		require.Len(t, allow.Spec.Egress[3].To, 1)
+		assert.Nil(t, allow.Spec.Egress[3].To[0].IPBlock)
+		assert.Equal(t, perseusNamespace, namespace(allow.Spec.Egress[3].To[0]))
+		assert.Equal(t, []int32{postgresPort}, ports(allow.Spec.Egress[3].Ports))
+	})
+
+	t.Run("operator database and filestore stay in namespace", func(t *testing.T) {
+		installation := &model.Installation{
+			ID:        "installation1",
+			Database:  model.InstallationDatabaseMysqlOperator,
+			Filestore: model.InstallationFilestoreMinioOperator,
+		}
+
+		allow := generateNetworkPolicies(installation, clusterInstallation, cluster, "")[1]
+		assert.Len(t, allow.Spec.Egress, 3)
+	})
+
+	t.Run("exceptions", func(t *testing.T) {
+		installation := &model.Installation{
+			ID:        "installation1",
+			Database:  model.InstallationDatabaseMysqlOperator,
+			Filestore: model.InstallationFilestoreMinioOperator,
+			NetworkPolicyExceptions: &model.NetworkPolicyExceptions{
+				IngressNamespaces: []string{"monitoring"},
+				Egress: []model.NetworkPolicyEgressException{
+					{CIDR: "10.0.0.0/8", Port: 443},
+					{CIDR: "192.168.0.1/32"},
+				},
+			},
+		}
+
+		allow := generateNetworkPolicies(installation, clusterInstallation, cluster, "")[1]
+		require.Len(t, allow.Spec.Ingress, 4)
+		assert.Equal(t, "monitoring", namespace(allow.Spec.Ingress[3].From[0]))
+		assert.Empty(t, allow.Spec.Ingress[3].Ports)
+
+		require.Len(t, allow.Spec.Egress, 5)
+		assert.Equal(t, "10.0.0.0/8", allow.Spec.Egress[3].To[0].IPBlock.CIDR)
+		assert.Equal(t, []int32{443}, ports(allow.Spec.Egress[3].Ports))
+		assert.Equal(t, "192.168.0.1/32", allow.Spec.Egress[4].To[0].IPBlock.CIDR)
+		assert.Empty(t, allow.Spec.Egress[4].Ports)
+	})
+}
+
+func TestDatabaseEgressCIDR(t *testing.T) {
+	provisioner := Provisioner{}
+	cluster := &model.Cluster{ID: "cluster1", Provisioner: model.ProvisionerEKS, ProvisionerMetadataEKS: &model.EKSMetadata{}}
+
+	t.Run("in cluster database", func(t *testing.T) {
+		cidr, err := provisioner.databaseEgressCIDR(&model.Installation{Database: model.InstallationDatabaseMultiTenantRDSPostgresPGBouncer}, cluster)
+		require.NoError(t, err)
+		assert.Empty(t, cidr)
+	})
+
+	t.Run("external database without vpc", func(t *testing.T) {
+		cidr, err := provisioner.databaseEgressCIDR(&model.Installation{Database: model.InstallationDatabaseExternal}, cluster)
+		require.NoError(t, err)
+		assert.Empty(t, cidr)
+	})
+
+	t.Run("rds database without vpc", func(t *testing.T) {
+		_, err := provisioner.databaseEgressCIDR(&model.Installation{Database: model.InstallationDatabaseMultiTenantRDSPostgres}, cluster)
+		require.Error(t, err)
+	})
+}
+
+func TestNetworkPolicyManifests(t *testing.T) {
+	t.Run("legacy manifest skipped with installation network policies", func(t *testing.T) {
+		assert.Equal(t, []string{networkPolicyManifest}, networkPolicyManifests(true))
+	})
+
+	t.Run("legacy manifest applied without installation network policies", func(t *testing.T) {
+		assert.Equal(t, []string{networkPolicyManifest, legacyNetworkPolicyManifest}, networkPolicyManifests(false))
+	})
+
+	t.Run("legacy policy names match the legacy manifest", func(t *testing.T) {
+		manifest, err := os.ReadFile(filepath.Join("..", "..", legacyNetworkPolicyManifest))
+		require.NoError(t, err)
+
+		var names []string
+		for _, match := range regexp.MustCompile(`(?m)^  name: (\S+)$`).FindAllStringSubmatch(string(manifest), -1) {
+			names = append(names, match[1])
+		}
+		assert.ElementsMatch(t, legacyNetworkPolicyNames, names)
+	})
+}
+
+func TestEnsureNetworkPolicies(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	cluster := &model.Cluster{ID: "cluster1"}
+	clusterInstallation := &model.ClusterInstallation{ID: "ci1", InstallationID: "installation1", Namespace: "installation1"}
+	installation := &model.Installation{
+		ID:        "installation1",
+		Database:  model.InstallationDatabaseMysqlOperator,
+		Filestore: model.InstallationFilestoreMinioOperator,
+	}
+
+	newKubeClient := func(names ...string) *k8s.KubeClient {
+		clientset := fake.NewSimpleClientset()
+		for _, name := range names {
+			_, err := clientset.NetworkingV1().NetworkPolicies(clusterInstallation.Namespace).Create(
+				context.TODO(),
+				&networkingv1.NetworkPolicy{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: clusterInstallation.Namespace}},
+				metav1.CreateOptions{},
+			)
+			require.NoError(t, err)
+		}
+		return &k8s.KubeClient{Clientset: clientset}
+	}
+	policyNames := func(k8sClient *k8s.KubeClient) []string {
+		policies, err := k8sClient.Clientset.NetworkingV1().NetworkPolicies(clusterInstallation.Namespace).List(context.TODO(), metav1.ListOptions{})
+		require.NoError(t, err)
+		var names []string
+		for _, policy := range policies.Items {
+			names = append(names, policy.Name)
+		}
+		return names
+	}
+
+	t.Run("enabled removes legacy policies", func(t *testing.T) {
+		provisioner := Provisioner{params: ProvisioningParams{InstallationNetworkPolicies: true}}
+		k8sClient := newKubeClient(append([]string{"allow-metrics-prom", "operator-db-allow"}, legacyNetworkPolicyNames...)...)
+
+		err := provisioner.ensureNetworkPolicies(installation, clusterInstallation, cluster, k8sClient, logger)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"allow-metrics-prom", "operator-db-allow", "mm-inst-default-deny", "mm-inst-allow"}, policyNames(k8sClient))
+
+		err = provisioner.ensureNetworkPolicies(installation, clusterInstallation, cluster, k8sClient, logger)
+		require.NoError(t, err)
+	})
+
+	t.Run("disabled removes installation policies", func(t *testing.T) {
+		provisioner := Provisioner{params: ProvisioningParams{InstallationNetworkPolicies: false}}
+		k8sClient := newKubeClient(append([]string{"mm-inst-default-deny", "mm-inst-allow"}, legacyNetworkPolicyNames...)...)
+
+		err := provisioner.ensureNetworkPolicies(installation, clusterInstallation, cluster, k8sClient, logger)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, legacyNetworkPolicyNames, policyNames(k8sClient))
+	})
+}
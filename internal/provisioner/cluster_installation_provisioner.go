@@ -187,7 +187,7 @@ func (provisioner Provisioner) createClusterInstallation(clusterInstallation *mo
 		return errors.Wrap(err, "failed to create k8s client from file")
 	}
 
-	installationName, err := prepareClusterInstallationEnv(clusterInstallation, k8sClient)
+	installationName, err := provisioner.prepareClusterInstallationEnv(clusterInstallation, k8sClient)
 	if err != nil {
 		return errors.Wrap(err, "failed to prepare cluster installation env")
 	}
@@ -219,7 +219,7 @@ func (provisioner Provisioner) createClusterInstallation(clusterInstallation *mo
 	// Set custom command if provided
 	ensureCustomCommand(mattermost, installation)
 
-	err = setMMInstanceSize(installation, mattermost)
+	err = setMMInstanceSize(installation, mattermost, provisioner.store)
 	if err != nil {
 		return errors.Wrap(err, "failed to set Mattermost instance size")
 	}
@@ -266,6 +266,11 @@ func (provisioner Provisioner) createClusterInstallation(clusterInstallation *mo
 		}
 	}
 
+	err = provisioner.ensureNetworkPolicies(installation, clusterInstallation, cluster, k8sClient, logger)
+	if err != nil {
+		return errors.Wrap(err, "failed to ensure NetworkPolicies")
+	}
+
 	ctx := context.TODO()
 	_, err = k8sClient.MattermostClientsetV1Beta.MattermostV1beta1().Mattermosts(clusterInstallation.Namespace).Create(ctx, mattermost, metav1.CreateOptions{})
 	if err != nil {
@@ -415,7 +420,7 @@ func (provisioner Provisioner) updateClusterInstallation(
 		return errors.Wrap(err, "failed to create k8s client from file")
 	}
 
-	installationName, err := prepareClusterInstallationEnv(clusterInstallation, k8sClient)
+	installationName, err := provisioner.prepareClusterInstallationEnv(clusterInstallation, k8sClient)
 	if err != nil {
 		return errors.Wrap(err, "failed to prepare cluster installation env")
 	}
@@ -461,7 +466,7 @@ func (provisioner Provisioner) updateClusterInstallation(
 	//    when the size request change comes in on the API, but would require
 	//    new scheduling logic. For now, take care when resizing.
 	//    TODO: address these issue.
-	err = setMMInstanceSize(installation, mattermost)
+	err = setMMInstanceSize(installation, mattermost, provisioner.store)
 	if err != nil {
 		return errors.Wrap(err, "failed to set Mattermost instance size")
 	}
@@ -546,6 +551,11 @@ func (provisioner Provisioner) updateClusterInstallation(
 		return errors.Wrap(err, "failed to ensure PodDisruptionBudget")
 	}
 
+	err = provisioner.ensureNetworkPolicies(installation, clusterInstallation, cluster, k8sClient, logger)
+	if err != nil {
+		return errors.Wrap(err, "failed to ensure NetworkPolicies")
+	}
+
 	logger.Info("Updated cluster installation")
 
 	return nil
@@ -926,7 +936,7 @@ func prepareClusterUtilities(
 	return nil
 }
 
-func prepareClusterInstallationEnv(clusterInstallation *model.ClusterInstallation, k8sClient *k8s.KubeClient) (string, error) {
+func (provisioner Provisioner) prepareClusterInstallationEnv(clusterInstallation *model.ClusterInstallation, k8sClient *k8s.KubeClient) (string, error) {
 	_, err := k8sClient.CreateOrUpdateNamespace(clusterInstallation.Namespace)
 	if err != nil {
 		return "", errors.Wrapf(err, "failed to create namespace %s", clusterInstallation.Namespace)
@@ -934,13 +944,15 @@ func prepareClusterInstallationEnv(clusterInstallation *model.ClusterInstallatio
 
 	installationName := makeClusterInstallationName(clusterInstallation)
 
-	file := k8s.ManifestFile{
-		Path:            "manifests/network-policies/mm-installation-netpol.yaml",
-		DeployNamespace: clusterInstallation.Namespace,
-	}
-	err = k8sClient.CreateFromFile(file, installationName)
-	if err != nil {
-		return "", errors.Wrapf(err, "failed to create network policy %s", clusterInstallation.Namespace)
+	for _, path := range networkPolicyManifests(provisioner.params.InstallationNetworkPolicies) {
+		file := k8s.ManifestFile{
+			Path:            path,
+			DeployNamespace: clusterInstallation.Namespace,
+		}
+		err = k8sClient.CreateFromFile(file, installationName)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to create network policy %s", clusterInstallation.Namespace)
+		}
 	}
 
 	return installationName, nil
@@ -1069,7 +1081,7 @@ func generateAffinityConfig(installation *model.Installation, clusterInstallatio
 	}
 }
 
-func setMMInstanceSize(installation *model.Installation, mattermost *mmv1beta1.Mattermost) error {
+func setMMInstanceSize(installation *model.Installation, mattermost *mmv1beta1.Mattermost, sizeProfiles model.SizeProfileGetter) error {
 	if strings.HasPrefix(installation.Size, model.ProvisionerSizePrefix) {
 		resSize, err := model.ParseProvisionerSize(installation.Size)
 		if err != nil {
@@ -1078,7 +1090,20 @@ func setMMInstanceSize(installation *model.Installation, mattermost *mmv1beta1.M
 		overrideReplicasAndResourcesFromSize(resSize, mattermost)
 		return nil
 	}
-	mattermost.Spec.Size = installation.Size
+
+	if _, err := mmv1alpha1.GetClusterSize(installation.Size); err == nil {
+		mattermost.Spec.Size = installation.Size
+		return nil
+	}
+
+	// Any other size references a size profile.
+	resSize, profile, err := model.ResolveInstallationSize(installation.Size, sizeProfiles)
+	if err != nil {
+		return errors.Wrap(err, "failed to resolve installation size profile")
+	}
+	overrideReplicasAndResourcesFromSize(resSize, mattermost)
+	mattermost.Spec.JobServer = &mmv1beta1.JobServer{DedicatedJobServer: profile.Spec.DedicatedJobServer()}
+
 	return nil
 }
 
@@ -23,6 +23,13 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+const (
+	// bifrostNamespace is the namespace of the bifrost filestore proxy.
+	bifrostNamespace = "bifrost"
+	// perseusNamespace is the namespace of the perseus database proxy.
+	perseusNamespace = "perseus"
+)
+
 func provisionCluster(
 	cluster *model.Cluster,
 	kubeconfigPath string,
@@ -131,9 +138,7 @@ func provisionCluster(
 	// The perseus and bifrost utilities cannot have downtime so they are not
 	// part of the standard namespace cleanup and recreation flow. We always
 	// only update both.
-	perseusNamespace := "perseus"
 	namespaces = append(namespaces, perseusNamespace)
-	bifrostNamespace := "bifrost"
 	namespaces = append(namespaces, bifrostNamespace)
 
 	logger.Info("Creating utility namespaces")
@@ -0,0 +1,172 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package provisioner
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mattermost/mattermost-cloud/internal/provisioner/utility"
+	"github.com/mattermost/mattermost-cloud/k8s"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	upgradeCheckDeprecatedAPIs          = "deprecated-apis"
+	upgradeCheckPodDisruptionBudgets    = "pod-disruption-budgets"
+	upgradeCheckUtilityCompatibility    = "utility-compatibility"
+	upgradeCheckSurgeCapacity           = "surge-capacity"
+	upgradeCheckNodesReady              = "nodes-ready"
+	upgradeCheckClusterInstallations    = "cluster-installations-ready"
+	mattermostWorkloadSelector          = "app=mattermost"
+	maxUpgradeCheckMessageEntries       = 10
+	upgradeCheckMessageEntriesSeparator = "; "
+)
+
+// preflightClusterUpgrade checks that the cluster can be upgraded to the
+// given kubernetes version. Surging clusters add nodes while rolling
+// nodegroups, so they don't need spare capacity to drain a node.
+func preflightClusterUpgrade(cluster *model.Cluster, kubeconfigPath, version string, surge bool, logger log.FieldLogger) (model.ClusterUpgradeChecks, error) {
+	k8sClient, err := k8s.NewFromFile(kubeconfigPath, logger)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create k8s client")
+	}
+
+	var checks model.ClusterUpgradeChecks
+
+	resources, err := utility.InstalledManifestResources(kubeconfigPath, logger)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get installed manifest resources")
+	}
+	// The removals affecting the latest version are not known in advance,
+	// so they are only reported.
+	checks = append(checks, newUpgradeCheck(upgradeCheckDeprecatedAPIs, version != "latest", model.FindRemovedKubernetesAPIs(resources, version)))
+
+	blockingPDBs, err := k8sClient.GetBlockingPodDisruptionBudgets()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to check pod disruption budgets")
+	}
+	var pdbProblems []string
+	for _, pdb := range blockingPDBs {
+		pdbProblems = append(pdbProblems, fmt.Sprintf("%s allows no disruption", pdb))
+	}
+	checks = append(checks, newUpgradeCheck(upgradeCheckPodDisruptionBudgets, true, pdbProblems))
+
+	incompatible, err := utility.IncompatibleUtilityCharts(cluster, kubeconfigPath, version, logger)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to check utility chart compatibility")
+	}
+	checks = append(checks, newUpgradeCheck(upgradeCheckUtilityCompatibility, true, incompatible))
+
+	var capacityProblems []string
+	if !surge {
+		clusterResources, err := getClusterResources(kubeconfigPath, true, logger)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get cluster resources")
+		}
+		capacityProblems = surgeCapacityProblems(clusterResources)
+	}
+	checks = append(checks, newUpgradeCheck(upgradeCheckSurgeCapacity, true, capacityProblems))
+
+	return checks, nil
+}
+
+// surgeCapacityProblems describes why the workloads of the cluster would not
+// fit on the remaining worker nodes while a node is drained.
+func surgeCapacityProblems(resources *k8s.ClusterResources) []string {
+	if resources.WorkerNodeCount < 2 {
+		return []string{fmt.Sprintf("%d schedulable worker nodes; draining a node requires surge", resources.WorkerNodeCount)}
+	}
+
+	var problems []string
+	remainingCPU := resources.MilliTotalCPU - resources.MilliTotalCPU/resources.WorkerNodeCount
+	if resources.MilliUsedCPU > remainingCPU {
+		problems = append(problems, fmt.Sprintf("requested CPU %dm exceeds the %dm left while draining a node", resources.MilliUsedCPU, remainingCPU))
+	}
+	remainingMemory := resources.MilliTotalMemory - resources.MilliTotalMemory/resources.WorkerNodeCount
+	if resources.MilliUsedMemory > remainingMemory {
+		problems = append(problems, fmt.Sprintf("requested memory %dMi exceeds the %dMi left while draining a node", resources.MilliUsedMemory/1000/1024/1024, remainingMemory/1000/1024/1024))
+	}
+
+	return problems
+}
+
+// verifyClusterUpgrade checks that all nodes of the cluster run the given
+// kubernetes version and that the cluster installations are ready.
+func verifyClusterUpgrade(kubeconfigPath, version string, clusterInstallations []*model.ClusterInstallation, logger log.FieldLogger) (model.ClusterUpgradeChecks, error) {
+	k8sClient, err := k8s.NewFromFile(kubeconfigPath, logger)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create k8s client")
+	}
+
+	nodes, err := k8sClient.GetNodeReadiness()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get node readiness")
+	}
+	var nodeProblems []string
+	for _, node := range nodes {
+		if !node.Ready {
+			nodeProblems = append(nodeProblems, fmt.Sprintf("node %s is not ready", node.Name))
+			continue
+		}
+		if !kubeletVersionMatches(node.KubeletVersion, version) {
+			nodeProblems = append(nodeProblems, fmt.Sprintf("node %s runs kubelet %s", node.Name, node.KubeletVersion))
+		}
+	}
+
+	var installationProblems []string
+	for _, clusterInstallation := range clusterInstallations {
+		workloads, err := k8sClient.GetWorkloadReadiness(clusterInstallation.Namespace, mattermostWorkloadSelector)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get workloads of cluster installation %s", clusterInstallation.ID)
+		}
+		for _, workload := range workloads {
+			if !workload.IsReady() {
+				installationProblems = append(installationProblems, fmt.Sprintf("installation %s %s %s has %d/%d ready pods", clusterInstallation.InstallationID, workload.Kind, workload.Name, workload.Ready, workload.Desired))
+			}
+		}
+	}
+
+	return model.ClusterUpgradeChecks{
+		newUpgradeCheck(upgradeCheckNodesReady, true, nodeProblems),
+		newUpgradeCheck(upgradeCheckClusterInstallations, true, installationProblems),
+	}, nil
+}
+
+// kubeletVersionMatches returns true if the kubelet version is the given
+// kubernetes version, which may omit the patch version. Every version
+// matches when the target version is unknown.
+func kubeletVersionMatches(kubeletVersion, version string) bool {
+	if version == "" || version == "latest" {
+		return true
+	}
+	kubeletVersion = strings.TrimPrefix(kubeletVersion, "v")
+	version = strings.TrimPrefix(version, "v")
+
+	return kubeletVersion == version ||
+		strings.HasPrefix(kubeletVersion, version+".") ||
+		strings.HasPrefix(kubeletVersion, version+"-") ||
+		strings.HasPrefix(kubeletVersion, version+"+")
+}
+
+// newUpgradeCheck builds the result of an upgrade check from the problems
+// it found, truncating long lists of problems.
+func newUpgradeCheck(name string, blocking bool, problems []string) *model.ClusterUpgradeCheck {
+	check := &model.ClusterUpgradeCheck{
+		Name:     name,
+		Passed:   len(problems) == 0,
+		Blocking: blocking,
+	}
+	if len(problems) > maxUpgradeCheckMessageEntries {
+		check.Message = strings.Join(problems[:maxUpgradeCheckMessageEntries], upgradeCheckMessageEntriesSeparator) +
+			fmt.Sprintf(" and %d more", len(problems)-maxUpgradeCheckMessageEntries)
+	} else {
+		check.Message = strings.Join(problems, upgradeCheckMessageEntriesSeparator)
+	}
+
+	return check
+}
@@ -0,0 +1,65 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package provisioner
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mattermost/mattermost-cloud/k8s"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSurgeCapacityProblems(t *testing.T) {
+	t.Run("enough capacity", func(t *testing.T) {
+		assert.Empty(t, surgeCapacityProblems(&k8s.ClusterResources{
+			WorkerNodeCount:  3,
+			MilliTotalCPU:    6000,
+			MilliUsedCPU:     4000,
+			MilliTotalMemory: 3000,
+			MilliUsedMemory:  2000,
+		}))
+	})
+
+	t.Run("not enough cpu", func(t *testing.T) {
+		problems := surgeCapacityProblems(&k8s.ClusterResources{
+			WorkerNodeCount:  3,
+			MilliTotalCPU:    6000,
+			MilliUsedCPU:     4500,
+			MilliTotalMemory: 3000,
+			MilliUsedMemory:  1000,
+		})
+		assert.Equal(t, []string{"requested CPU 4500m exceeds the 4000m left while draining a node"}, problems)
+	})
+
+	t.Run("single node", func(t *testing.T) {
+		assert.Len(t, surgeCapacityProblems(&k8s.ClusterResources{WorkerNodeCount: 1}), 1)
+	})
+}
+
+func TestKubeletVersionMatches(t *testing.T) {
+	assert.True(t, kubeletVersionMatches("v1.29.4", "1.29.4"))
+	assert.True(t, kubeletVersionMatches("v1.29.3-eks-ae9a62a", "1.29"))
+	assert.True(t, kubeletVersionMatches("v1.28.1", "latest"))
+	assert.True(t, kubeletVersionMatches("v1.28.1", ""))
+	assert.False(t, kubeletVersionMatches("v1.28.9", "1.29"))
+	assert.False(t, kubeletVersionMatches("v1.29.4", "1.2"))
+}
+
+func TestNewUpgradeCheck(t *testing.T) {
+	check := newUpgradeCheck("nodes-ready", true, nil)
+	assert.Equal(t, &model.ClusterUpgradeCheck{Name: "nodes-ready", Passed: true, Blocking: true}, check)
+
+	var problems []string
+	for i := 0; i < 12; i++ {
+		problems = append(problems, fmt.Sprintf("node %d is not ready", i))
+	}
+	check = newUpgradeCheck("nodes-ready", false, problems)
+	assert.False(t, check.Passed)
+	assert.False(t, check.Blocking)
+	assert.Contains(t, check.Message, "node 9 is not ready and 2 more")
+	assert.NotContains(t, check.Message, "node 10")
+}
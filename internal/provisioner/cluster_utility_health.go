@@ -0,0 +1,42 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package provisioner
+
+import (
+	"github.com/mattermost/mattermost-cloud/internal/provisioner/utility"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// CheckClusterUtilityHealth evaluates the health of the utilities deployed
+// to the cluster.
+func (provisioner Provisioner) CheckClusterUtilityHealth(cluster *model.Cluster) ([]*model.ClusterUtilityHealth, error) {
+	logger := provisioner.logger.WithField("cluster", cluster.ID)
+
+	configLocation, err := provisioner.getClusterKubecfg(cluster)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get kubeconfig")
+	}
+
+	return utility.CheckUtilityHealth(cluster, configLocation, logger)
+}
+
+// RestartClusterUtility restarts the pods of a utility deployed to the
+// cluster.
+func (provisioner Provisioner) RestartClusterUtility(cluster *model.Cluster, utilityName string) error {
+	logger := provisioner.logger.WithFields(log.Fields{
+		"cluster": cluster.ID,
+		"utility": utilityName,
+	})
+	logger.Info("Restarting cluster utility")
+
+	configLocation, err := provisioner.getClusterKubecfg(cluster)
+	if err != nil {
+		return errors.Wrap(err, "failed to get kubeconfig")
+	}
+
+	return utility.RestartUtility(cluster, utilityName, configLocation, logger)
+}
@@ -311,10 +311,12 @@ func (provisioner *EKSProvisioner) CheckNodegroupsCreated(cluster *model.Cluster
 				Name:              ngMetadata.Name,
 				Type:              ngMetadata.Type,
 				InstanceType:      nodeGroup.InstanceTypes[0],
+				CapacityType:      string(nodeGroup.CapacityType),
 				MinCount:          int64(ptr.ToInt32(nodeGroup.ScalingConfig.MinSize)),
 				MaxCount:          int64(ptr.ToInt32(nodeGroup.ScalingConfig.MaxSize)),
 				WithPublicSubnet:  ngMetadata.WithPublicSubnet,
 				WithSecurityGroup: ngMetadata.WithSecurityGroup,
+				AutoscalingPolicy: ngMetadata.AutoscalingPolicy,
 			}
 		}(ng, meta)
 	}
@@ -427,8 +429,27 @@ func (provisioner *EKSProvisioner) ProvisionCluster(cluster *model.Cluster) erro
 	return provisionCluster(cluster, kubeConfigPath, provisioner.awsClient, provisioner.params, provisioner.store, logger)
 }
 
-// UpgradeCluster upgrades EKS cluster - not implemented.
-func (provisioner *EKSProvisioner) UpgradeCluster(cluster *model.Cluster) error {
+// PreflightClusterUpgrade checks that the EKS cluster can be upgraded.
+func (provisioner *EKSProvisioner) PreflightClusterUpgrade(cluster *model.Cluster) (model.ClusterUpgradeChecks, error) {
+	logger := provisioner.logger.WithField("cluster", cluster.ID)
+
+	kubeconfigPath, err := provisioner.getKubeConfigPath(cluster)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get kubeconfig")
+	}
+
+	var version string
+	if cluster.ProvisionerMetadataEKS.ChangeRequest != nil {
+		version = cluster.ProvisionerMetadataEKS.ChangeRequest.Version
+	}
+
+	// NodeGroups are migrated by creating a replacement NodeGroup before
+	// removing the old one, so the cluster always surges.
+	return preflightClusterUpgrade(cluster, kubeconfigPath, version, true, logger)
+}
+
+// UpgradeClusterControlPlane upgrades the version of the EKS control plane.
+func (provisioner *EKSProvisioner) UpgradeClusterControlPlane(cluster *model.Cluster) error {
 	logger := provisioner.logger.WithField("cluster", cluster.ID)
 
 	eksMetadata := cluster.ProvisionerMetadataEKS
@@ -450,85 +471,113 @@ func (provisioner *EKSProvisioner) UpgradeCluster(cluster *model.Cluster) error
 		}
 	}
 
-	if changeRequest.AMI != "" || changeRequest.MaxPodsPerNode > 0 {
+	if changeRequest.Version == "" || changeRequest.Version == eksMetadata.Version {
+		logger.Info("Skipping EKS control plane version update")
+		return nil
+	}
 
-		var wg sync.WaitGroup
-		var errOccurred bool
+	clusterUpdateRequest, err := provisioner.awsClient.EnsureEKSClusterUpdated(cluster)
+	if err != nil {
+		return errors.Wrap(err, "failed to update EKS cluster")
+	}
 
-		for ng, meta := range changeRequest.NodeGroups {
-			wg.Add(1)
-			go func(ngPrefix string, ngMetadata model.NodeGroupMetadata) {
-				defer wg.Done()
-				logger.Debugf("Migrating EKS NodeGroup for %s", ngPrefix)
+	if clusterUpdateRequest != nil && clusterUpdateRequest.Id != nil {
+		wait := 3600 // seconds
+		logger.Infof("Waiting up to %d seconds for EKS cluster to be updated...", wait)
+		err = provisioner.awsClient.WaitForEKSClusterUpdateToBeCompleted(eksMetadata.Name, *clusterUpdateRequest.Id, wait)
+		if err != nil {
+			return errors.Wrap(err, "failed to update EKS cluster")
+		}
+	}
 
-				oldMetadata := eksMetadata.NodeGroups[ngPrefix]
-				ngMetadata.CopyMissingFieldsFrom(oldMetadata)
-				changeRequest.NodeGroups[ngPrefix] = ngMetadata
+	eksMetadata.Version = changeRequest.Version
 
-				err = provisioner.prepareLaunchTemplate(cluster, ngPrefix, ngMetadata.WithSecurityGroup, logger)
-				if err != nil {
-					logger.WithError(err).Error("failed to ensure launch template")
-					errOccurred = true
-					return
-				}
+	err = provisioner.clusterUpdateStore.UpdateCluster(cluster)
+	if err != nil {
+		return errors.Wrap(err, "failed to store cluster")
+	}
 
-				err = provisioner.awsClient.EnsureEKSNodeGroupMigrated(cluster, ngPrefix)
-				if err != nil {
-					logger.WithError(err).Errorf("failed to migrate EKS NodeGroup for %s", ngPrefix)
-					errOccurred = true
-					return
-				}
-				oldMetadata = eksMetadata.NodeGroups[ngPrefix]
-				oldMetadata.Name = ngMetadata.Name
-				eksMetadata.NodeGroups[ngPrefix] = oldMetadata
+	return nil
+}
 
-				logger.Debugf("Successfully migrated EKS NodeGroup for %s", ngPrefix)
-			}(ng, meta)
-		}
+// UpgradeClusterNodeGroup migrates an EKS NodeGroup to a replacement using
+// the upgraded launch template. The AMI and max pods of the cluster are
+// updated once every NodeGroup is migrated.
+func (provisioner *EKSProvisioner) UpgradeClusterNodeGroup(cluster *model.Cluster, nodeGroup string) error {
+	logger := provisioner.logger.WithFields(log.Fields{
+		"cluster":   cluster.ID,
+		"nodegroup": nodeGroup,
+	})
 
-		wg.Wait()
+	eksMetadata := cluster.ProvisionerMetadataEKS
+	changeRequest := eksMetadata.ChangeRequest
 
-		if errOccurred {
-			return errors.New("failed to migrate one of the EKS NodeGroups")
-		}
+	ngMetadata, found := changeRequest.NodeGroups[nodeGroup]
+	if !found || (changeRequest.AMI == "" && changeRequest.MaxPodsPerNode == 0) {
+		logger.Info("Skipping EKS NodeGroup migration")
+		return nil
+	}
+
+	oldMetadata := eksMetadata.NodeGroups[nodeGroup]
+	if oldMetadata.Name == ngMetadata.Name {
+		logger.Info("EKS NodeGroup already migrated")
+		return nil
+	}
 
+	logger.Debug("Migrating EKS NodeGroup")
+
+	ngMetadata.CopyMissingFieldsFrom(oldMetadata)
+	changeRequest.NodeGroups[nodeGroup] = ngMetadata
+
+	err := provisioner.prepareLaunchTemplate(cluster, nodeGroup, ngMetadata.WithSecurityGroup, logger)
+	if err != nil {
+		return errors.Wrap(err, "failed to ensure launch template")
+	}
+
+	err = provisioner.awsClient.EnsureEKSNodeGroupMigrated(cluster, nodeGroup)
+	if err != nil {
+		return errors.Wrapf(err, "failed to migrate EKS NodeGroup for %s", nodeGroup)
+	}
+	oldMetadata.Name = ngMetadata.Name
+	eksMetadata.NodeGroups[nodeGroup] = oldMetadata
+
+	logger.Debug("Successfully migrated EKS NodeGroup")
+
+	migrated := true
+	for ng, meta := range changeRequest.NodeGroups {
+		if eksMetadata.NodeGroups[ng].Name != meta.Name {
+			migrated = false
+			break
+		}
+	}
+	if migrated {
 		if changeRequest.AMI != "" {
 			eksMetadata.AMI = changeRequest.AMI
 		}
 		if changeRequest.MaxPodsPerNode > 0 {
 			eksMetadata.MaxPodsPerNode = changeRequest.MaxPodsPerNode
 		}
-
-		err = provisioner.clusterUpdateStore.UpdateCluster(cluster)
-		if err != nil {
-			return errors.Wrap(err, "failed to store cluster")
-		}
 	}
 
-	if changeRequest.Version != "" {
-		clusterUpdateRequest, err := provisioner.awsClient.EnsureEKSClusterUpdated(cluster)
-		if err != nil {
-			return errors.Wrap(err, "failed to update EKS cluster")
-		}
+	err = provisioner.clusterUpdateStore.UpdateCluster(cluster)
+	if err != nil {
+		return errors.Wrap(err, "failed to store cluster")
+	}
 
-		if clusterUpdateRequest != nil && clusterUpdateRequest.Id != nil {
-			wait := 3600 // seconds
-			logger.Infof("Waiting up to %d seconds for EKS cluster to be updated...", wait)
-			err = provisioner.awsClient.WaitForEKSClusterUpdateToBeCompleted(eksMetadata.Name, *clusterUpdateRequest.Id, wait)
-			if err != nil {
-				return errors.Wrap(err, "failed to update EKS cluster")
-			}
-		}
+	return nil
+}
 
-		eksMetadata.Version = changeRequest.Version
+// VerifyClusterUpgrade checks that the nodes run the upgraded version and
+// that the cluster installations are ready.
+func (provisioner *EKSProvisioner) VerifyClusterUpgrade(cluster *model.Cluster, clusterInstallations []*model.ClusterInstallation) (model.ClusterUpgradeChecks, error) {
+	logger := provisioner.logger.WithField("cluster", cluster.ID)
 
-		err = provisioner.clusterUpdateStore.UpdateCluster(cluster)
-		if err != nil {
-			return errors.Wrap(err, "failed to store cluster")
-		}
+	kubeconfigPath, err := provisioner.getKubeConfigPath(cluster)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get kubeconfig")
 	}
 
-	return nil
+	return verifyClusterUpgrade(kubeconfigPath, cluster.ProvisionerMetadataEKS.Version, clusterInstallations, logger)
 }
 
 // RotateClusterNodes rotates cluster nodes - not implemented.
@@ -558,17 +607,60 @@ func (provisioner *EKSProvisioner) isMigrationRequired(oldNodeGroup *eksTypes.No
 		return true
 	}
 
-	scalingInfo := oldNodeGroup.ScalingConfig
-	if *scalingInfo.MinSize != int32(ngChangeRequest.MinCount) {
-		return true
+	capacityType := ngChangeRequest.CapacityType
+	if capacityType == "" {
+		capacityType = model.NodeGroupCapacityOnDemand
 	}
-	if *scalingInfo.MaxSize != int32(ngChangeRequest.MaxCount) {
+	if oldNodeGroup.CapacityType != "" && string(oldNodeGroup.CapacityType) != capacityType {
 		return true
 	}
 
 	return false
 }
 
+// isScalingUpdateRequired returns whether the scaling configuration of the
+// EKS NodeGroup differs from the requested one.
+func (provisioner *EKSProvisioner) isScalingUpdateRequired(oldNodeGroup *eksTypes.Nodegroup, ngMetadata model.NodeGroupMetadata) bool {
+	scalingInfo := oldNodeGroup.ScalingConfig
+	if scalingInfo == nil {
+		return true
+	}
+
+	return ptr.ToInt32(scalingInfo.MinSize) != int32(ngMetadata.MinCount) ||
+		ptr.ToInt32(scalingInfo.MaxSize) != int32(ngMetadata.MaxCount)
+}
+
+// resizeNodeGroupInPlace updates the scaling configuration of an EKS NodeGroup
+// without recreating it. The desired size is kept within the new bounds.
+func (provisioner *EKSProvisioner) resizeNodeGroupInPlace(eksMetadata *model.EKSMetadata, oldNodeGroup *eksTypes.Nodegroup, ngPrefix string, ngMetadata model.NodeGroupMetadata, logger log.FieldLogger) error {
+	nodeGroupName := eksMetadata.NodeGroups[ngPrefix].Name
+
+	desiredCount := int64(ptr.ToInt32(oldNodeGroup.ScalingConfig.DesiredSize))
+	if desiredCount < ngMetadata.MinCount {
+		desiredCount = ngMetadata.MinCount
+	}
+	if desiredCount > ngMetadata.MaxCount {
+		desiredCount = ngMetadata.MaxCount
+	}
+
+	logger.Debugf("Scaling EKS NodeGroup %s in place", nodeGroupName)
+
+	err := provisioner.awsClient.EnsureEKSNodeGroupScaled(eksMetadata.Name, nodeGroupName, ngMetadata.MinCount, desiredCount, ngMetadata.MaxCount)
+	if err != nil {
+		return err
+	}
+
+	wait := 600 // seconds
+	logger.Infof("Waiting up to %d seconds for EKS NodeGroup %s to become active...", wait, nodeGroupName)
+
+	_, err = provisioner.awsClient.WaitForActiveEKSNodeGroup(eksMetadata.Name, nodeGroupName, wait)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // ResizeCluster resizes cluster - not implemented.
 func (provisioner *EKSProvisioner) ResizeCluster(cluster *model.Cluster) error {
 	logger := provisioner.logger.WithField("cluster", cluster.ID)
@@ -608,6 +700,24 @@ func (provisioner *EKSProvisioner) ResizeCluster(cluster *model.Cluster) error {
 
 			if !provisioner.isMigrationRequired(oldEKSNodeGroup, eksMetadata, ngPrefix, logger) {
 				logger.Debugf("EKS NodeGroup migration not required for %s", ngPrefix)
+
+				if !provisioner.isScalingUpdateRequired(oldEKSNodeGroup, ngMetadata) {
+					return
+				}
+
+				err2 = provisioner.resizeNodeGroupInPlace(eksMetadata, oldEKSNodeGroup, ngPrefix, ngMetadata, logger)
+				if err2 != nil {
+					logger.WithError(err2).Errorf("failed to scale EKS NodeGroup for %s", ngPrefix)
+					errOccurred = true
+					return
+				}
+
+				oldNodeGroup := eksMetadata.NodeGroups[ngPrefix]
+				oldNodeGroup.MinCount = ngMetadata.MinCount
+				oldNodeGroup.MaxCount = ngMetadata.MaxCount
+				eksMetadata.NodeGroups[ngPrefix] = oldNodeGroup
+
+				logger.Debugf("Successfully scaled EKS NodeGroup for %s", ngPrefix)
 				return
 			}
 
@@ -634,6 +744,7 @@ func (provisioner *EKSProvisioner) ResizeCluster(cluster *model.Cluster) error {
 			oldNodeGroup := eksMetadata.NodeGroups[ngPrefix]
 			oldNodeGroup.Name = ngMetadata.Name
 			oldNodeGroup.InstanceType = nodeGroup.InstanceTypes[0]
+			oldNodeGroup.CapacityType = string(nodeGroup.CapacityType)
 			oldNodeGroup.MinCount = int64(ptr.ToInt32(nodeGroup.ScalingConfig.MinSize))
 			oldNodeGroup.MaxCount = int64(ptr.ToInt32(nodeGroup.ScalingConfig.MaxSize))
 			eksMetadata.NodeGroups[ngPrefix] = oldNodeGroup
@@ -142,13 +142,34 @@ func (provisioner *ExternalProvisioner) ProvisionCluster(cluster *model.Cluster)
 	return nil
 }
 
-// UpgradeCluster is no-op for external clusters.
-func (provisioner *ExternalProvisioner) UpgradeCluster(cluster *model.Cluster) error {
+// PreflightClusterUpgrade is no-op for external clusters.
+func (provisioner *ExternalProvisioner) PreflightClusterUpgrade(cluster *model.Cluster) (model.ClusterUpgradeChecks, error) {
+	provisioner.logger.WithField("cluster", cluster.ID).Info("Cluster is managed externally; skipping upgrade preflight checks...")
+
+	return nil, nil
+}
+
+// UpgradeClusterControlPlane is no-op for external clusters.
+func (provisioner *ExternalProvisioner) UpgradeClusterControlPlane(cluster *model.Cluster) error {
 	provisioner.logger.WithField("cluster", cluster.ID).Info("Cluster is managed externally; skipping upgrade...")
 
 	return nil
 }
 
+// UpgradeClusterNodeGroup is no-op for external clusters.
+func (provisioner *ExternalProvisioner) UpgradeClusterNodeGroup(cluster *model.Cluster, nodeGroup string) error {
+	provisioner.logger.WithField("cluster", cluster.ID).Info("Cluster is managed externally; skipping nodegroup upgrade...")
+
+	return nil
+}
+
+// VerifyClusterUpgrade is no-op for external clusters.
+func (provisioner *ExternalProvisioner) VerifyClusterUpgrade(cluster *model.Cluster, clusterInstallations []*model.ClusterInstallation) (model.ClusterUpgradeChecks, error) {
+	provisioner.logger.WithField("cluster", cluster.ID).Info("Cluster is managed externally; skipping upgrade verification...")
+
+	return nil, nil
+}
+
 // RotateClusterNodes is no-op for external clusters.
 func (provisioner *ExternalProvisioner) RotateClusterNodes(cluster *model.Cluster) error {
 	provisioner.logger.WithField("cluster", cluster.ID).Info("Cluster is managed externally; skipping node rotation...")
@@ -0,0 +1,23 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package provisioner
+
+import (
+	"github.com/mattermost/mattermost-cloud/k8s"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+)
+
+// GetNamespaceUsageGetter returns a client measuring the resources actually
+// used by the namespaces of the given cluster. The client can be reused for
+// all the namespaces of the cluster.
+func (provisioner Provisioner) GetNamespaceUsageGetter(cluster *model.Cluster) (k8s.NamespaceUsageGetter, error) {
+	k8sClient, err := provisioner.k8sClient(cluster)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create k8s client")
+	}
+
+	return k8sClient, nil
+}
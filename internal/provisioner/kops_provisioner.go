@@ -7,6 +7,7 @@ package provisioner
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/mattermost/mattermost-cloud/internal/provisioner/utility"
@@ -472,8 +473,29 @@ func (provisioner *KopsProvisioner) ProvisionCluster(cluster *model.Cluster) err
 	return provisionCluster(cluster, kopsClient.GetKubeConfigPath(), provisioner.awsClient, provisioner.params, provisioner.store, logger)
 }
 
-// UpgradeCluster upgrades a cluster to the latest recommended production ready k8s version.
-func (provisioner *KopsProvisioner) UpgradeCluster(cluster *model.Cluster) error {
+// PreflightClusterUpgrade checks that the cluster can be upgraded.
+func (provisioner *KopsProvisioner) PreflightClusterUpgrade(cluster *model.Cluster) (model.ClusterUpgradeChecks, error) {
+	logger := provisioner.logger.WithField("cluster", cluster.ID)
+
+	kubeconfigPath, err := provisioner.getKubeConfigPath(cluster)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get kubeconfig")
+	}
+
+	kopsMetadata := cluster.ProvisionerMetadataKops
+	var version string
+	if kopsMetadata.ChangeRequest != nil {
+		version = kopsMetadata.ChangeRequest.Version
+	}
+	maxSurge, _, _ := kopsRollingUpdateSettings(kopsMetadata)
+
+	return preflightClusterUpgrade(cluster, kubeconfigPath, version, maxSurge > 0, logger)
+}
+
+// UpgradeClusterControlPlane applies the requested changes to the cluster
+// spec and rolls the control plane instance groups. The worker instance
+// groups are rolled separately.
+func (provisioner *KopsProvisioner) UpgradeClusterControlPlane(cluster *model.Cluster) error {
 	logger := provisioner.logger.WithField("cluster", cluster.ID)
 
 	kopsMetadata := cluster.ProvisionerMetadataKops
@@ -552,6 +574,16 @@ func (provisioner *KopsProvisioner) UpgradeCluster(cluster *model.Cluster) error
 			return errors.Wrap(err, "failed to update kops instance group instance Metadata with custom encryption key")
 		}
 	}
+
+	maxSurge, nodeInterval, postDrainDelay := kopsRollingUpdateSettings(kopsMetadata)
+	if maxSurge > 0 {
+		logger.Infof("Surging up to %d nodes while rolling instance groups", maxSurge)
+		err = updateWorkersKopsInstanceGroupValue(kops, kopsMetadata, fmt.Sprintf("spec.rollingUpdate.maxSurge=%d", maxSurge))
+		if err != nil {
+			return errors.Wrap(err, "failed to update kops instance group rolling update settings")
+		}
+	}
+
 	err = kops.UpdateCluster(kopsMetadata.Name, kops.GetOutputDirectory())
 	if err != nil {
 		return err
@@ -578,7 +610,7 @@ func (provisioner *KopsProvisioner) UpgradeCluster(cluster *model.Cluster) error
 		return err
 	}
 
-	logger.Info("Upgrading cluster")
+	logger.Info("Upgrading cluster control plane")
 
 	err = terraformClient.Plan()
 	if err != nil {
@@ -589,19 +621,17 @@ func (provisioner *KopsProvisioner) UpgradeCluster(cluster *model.Cluster) error
 		return err
 	}
 
-	if cluster.ProvisionerMetadataKops.RotatorRequest.Config != nil {
-		if *cluster.ProvisionerMetadataKops.RotatorRequest.Config.UseRotator {
-			logger.Info("Using node rotator for node upgrade")
-			err = provisioner.RotateClusterNodes(cluster)
-			if err != nil {
-				return err
-			}
-		}
+	var masterInstanceGroups []string
+	for name := range kopsMetadata.MasterInstanceGroups {
+		masterInstanceGroups = append(masterInstanceGroups, name)
 	}
-
-	err = kops.RollingUpdateCluster(kopsMetadata.Name)
-	if err != nil {
-		return err
+	if len(masterInstanceGroups) > 0 {
+		sort.Strings(masterInstanceGroups)
+		logger.Infof("Rolling control plane instance groups %s", strings.Join(masterInstanceGroups, ", "))
+		err = kops.RollingUpdateInstanceGroups(kopsMetadata.Name, masterInstanceGroups, nodeInterval, postDrainDelay)
+		if err != nil {
+			return err
+		}
 	}
 
 	err = attachPolicyRoles(cluster, provisioner.awsClient, logger)
@@ -622,11 +652,65 @@ func (provisioner *KopsProvisioner) UpgradeCluster(cluster *model.Cluster) error
 		}
 	}
 
-	logger.Info("Successfully upgraded cluster")
+	logger.Info("Successfully upgraded cluster control plane")
+
+	return nil
+}
+
+// UpgradeClusterNodeGroup rolls the nodes of a worker instance group.
+func (provisioner *KopsProvisioner) UpgradeClusterNodeGroup(cluster *model.Cluster, nodeGroup string) error {
+	logger := provisioner.logger.WithFields(log.Fields{
+		"cluster":   cluster.ID,
+		"nodegroup": nodeGroup,
+	})
+
+	kopsMetadata := cluster.ProvisionerMetadataKops
+
+	kops, err := kops.New(provisioner.params.S3StateStore, logger)
+	if err != nil {
+		return errors.Wrap(err, "failed to create kops wrapper")
+	}
+	defer kops.Close()
+
+	logger.Info("Rolling instance group")
+
+	_, nodeInterval, postDrainDelay := kopsRollingUpdateSettings(kopsMetadata)
+	err = kops.RollingUpdateInstanceGroups(kopsMetadata.Name, []string{nodeGroup}, nodeInterval, postDrainDelay)
+	if err != nil {
+		return err
+	}
+
+	wait := 1000
+	logger.Infof("Waiting up to %d seconds for k8s cluster to become ready...", wait)
+	err = kops.WaitForKubernetesReadiness(kopsMetadata.Name, wait)
+	if err != nil {
+		kops.ValidateCluster(kopsMetadata.Name, false)
+		return err
+	}
+
+	logger.Info("Successfully rolled instance group")
 
 	return nil
 }
 
+// VerifyClusterUpgrade checks that the nodes run the upgraded version and
+// that the cluster installations are ready.
+func (provisioner *KopsProvisioner) VerifyClusterUpgrade(cluster *model.Cluster, clusterInstallations []*model.ClusterInstallation) (model.ClusterUpgradeChecks, error) {
+	logger := provisioner.logger.WithField("cluster", cluster.ID)
+
+	kubeconfigPath, err := provisioner.getKubeConfigPath(cluster)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get kubeconfig")
+	}
+
+	var version string
+	if cluster.ProvisionerMetadataKops.ChangeRequest != nil {
+		version = cluster.ProvisionerMetadataKops.ChangeRequest.Version
+	}
+
+	return verifyClusterUpgrade(kubeconfigPath, version, clusterInstallations, logger)
+}
+
 // RotateClusterNodes rotates k8s cluster nodes using the Mattermost node rotator
 func (provisioner *KopsProvisioner) RotateClusterNodes(cluster *model.Cluster) error {
 	logger := provisioner.logger.WithField("cluster", cluster.ID)
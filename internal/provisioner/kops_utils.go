@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/mattermost/mattermost-cloud/internal/tools/kops"
 	"github.com/mattermost/mattermost-cloud/internal/tools/terraform"
@@ -164,3 +165,31 @@ func ModifyAMISuffix(ami, archLabel string) string {
 	// If the existing suffix matches the label, or no label is provided, return the AMI unmodified.
 	return ami
 }
+
+// kopsRollingUpdateSettings returns the rolling update settings derived from
+// the rotator config of the cluster: the number of nodes surged while
+// rolling an instance group and the delays after replacing and draining
+// each node. The kops defaults are used when the rotator is disabled.
+func kopsRollingUpdateSettings(kopsMetadata *model.KopsMetadata) (int, time.Duration, time.Duration) {
+	if kopsMetadata.RotatorRequest == nil || kopsMetadata.RotatorRequest.Config == nil {
+		return 0, 0, 0
+	}
+	config := kopsMetadata.RotatorRequest.Config
+	if config.UseRotator == nil || !*config.UseRotator {
+		return 0, 0, 0
+	}
+
+	var maxSurge int
+	var nodeInterval, postDrainDelay time.Duration
+	if config.MaxScaling != nil {
+		maxSurge = *config.MaxScaling
+	}
+	if config.WaitBetweenRotations != nil {
+		nodeInterval = time.Duration(*config.WaitBetweenRotations) * time.Second
+	}
+	if config.WaitBetweenDrains != nil {
+		postDrainDelay = time.Duration(*config.WaitBetweenDrains) * time.Second
+	}
+
+	return maxSurge, nodeInterval, postDrainDelay
+}
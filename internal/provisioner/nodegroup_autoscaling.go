@@ -0,0 +1,54 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package provisioner
+
+import (
+	"fmt"
+
+	"github.com/mattermost/mattermost-cloud/k8s"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// GetNodeGroupResources returns a snapshot of the resources of the nodes of
+// the given EKS nodegroup.
+func (provisioner Provisioner) GetNodeGroupResources(cluster *model.Cluster, nodeGroup string) (*k8s.ClusterResources, error) {
+	logger := provisioner.logger.WithFields(log.Fields{
+		"cluster":   cluster.ID,
+		"nodegroup": nodeGroup,
+	})
+
+	configLocation, err := provisioner.getClusterKubecfg(cluster)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get kube config path")
+	}
+
+	// EKS nodegroups label their nodes with the nodegroup prefix. Nodes of
+	// additional nodegroups are tainted, so they are not filtered out as
+	// unschedulable.
+	return getNodeResources(configLocation, fmt.Sprintf("type=%s", nodeGroup), false, logger)
+}
+
+// ScaleNodeGroup updates the scaling configuration of the given EKS nodegroup
+// in place.
+func (provisioner Provisioner) ScaleNodeGroup(cluster *model.Cluster, nodeGroup string, minCount, desiredCount, maxCount int64) error {
+	eksMetadata := cluster.ProvisionerMetadataEKS
+	if cluster.Provisioner != model.ProvisionerEKS || eksMetadata == nil {
+		return errors.Errorf("scaling nodegroups is not supported for %s clusters", cluster.Provisioner)
+	}
+
+	ngMetadata, found := eksMetadata.NodeGroups[nodeGroup]
+	if !found {
+		return errors.Errorf("nodegroup %s not found", nodeGroup)
+	}
+
+	provisioner.logger.WithFields(log.Fields{
+		"cluster":   cluster.ID,
+		"nodegroup": nodeGroup,
+	}).Debugf("Scaling EKS NodeGroup %s to min=%d desired=%d max=%d", ngMetadata.Name, minCount, desiredCount, maxCount)
+
+	return provisioner.awsClient.EnsureEKSNodeGroupScaled(eksMetadata.Name, ngMetadata.Name, minCount, desiredCount, maxCount)
+}
@@ -47,6 +47,9 @@ type ProvisioningParams struct {
 	SLOEnterpriseGroups       []string
 	EtcdManagerEnv            map[string]string
 	PodProbeOverrides         model.PodProbeOverrides
+	// InstallationNetworkPolicies enables the NetworkPolicies isolating
+	// installations from each other.
+	InstallationNetworkPolicies bool
 }
 
 type Provisioner struct {
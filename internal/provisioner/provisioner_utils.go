@@ -76,13 +76,21 @@ func getPublicLoadBalancerEndpoint(kubeconfigPath string, namespace string, logg
 }
 
 func getClusterResources(kubeconfigPath string, onlySchedulable bool, logger log.FieldLogger) (*k8s.ClusterResources, error) {
+	return getNodeResources(kubeconfigPath, "", onlySchedulable, logger)
+}
+
+// getNodeResources returns a snapshot of the resources of the nodes matching
+// the label selector, or of all nodes if the selector is empty.
+func getNodeResources(kubeconfigPath, labelSelector string, onlySchedulable bool, logger log.FieldLogger) (*k8s.ClusterResources, error) {
 	k8sClient, err := k8s.NewFromFile(kubeconfigPath, logger)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create k8s client from file")
 	}
 
 	ctx := context.TODO()
-	nodes, err := k8sClient.Clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	nodes, err := k8sClient.Clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{
+		LabelSelector: labelSelector,
+	})
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to list nodes")
 	}
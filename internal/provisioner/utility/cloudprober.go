@@ -14,6 +14,7 @@ import (
 )
 
 type cloudprober struct {
+	definition     *model.UtilityDefinition
 	cluster        *model.Cluster
 	kubeconfigPath string
 	logger         log.FieldLogger
@@ -21,7 +22,7 @@ type cloudprober struct {
 	desiredVersion *model.HelmUtilityVersion
 }
 
-func newCloudproberOrUnmanagedHandle(cluster *model.Cluster, kubeconfigPath string, awsClient aws.AWS, logger log.FieldLogger) (Utility, error) {
+func newCloudproberOrUnmanagedHandle(definition *model.UtilityDefinition, cluster *model.Cluster, kubeconfigPath string, awsClient aws.AWS, logger log.FieldLogger) (Utility, error) {
 	desired := cluster.DesiredUtilityVersion(model.CloudproberCanonicalName)
 	actual := cluster.ActualUtilityVersion(model.CloudproberCanonicalName)
 
@@ -29,7 +30,7 @@ func newCloudproberOrUnmanagedHandle(cluster *model.Cluster, kubeconfigPath stri
 		return newUnmanagedHandle(model.CloudproberCanonicalName, kubeconfigPath, []string{}, cluster, awsClient, logger), nil
 	}
 
-	cloudprober := newCloudproberHandle(desired, cluster, kubeconfigPath, logger)
+	cloudprober := newCloudproberHandle(definition, desired, cluster, kubeconfigPath, logger)
 	err := cloudprober.validate()
 	if err != nil {
 		return nil, errors.Wrap(err, "cloudprober utility config is invalid")
@@ -38,8 +39,9 @@ func newCloudproberOrUnmanagedHandle(cluster *model.Cluster, kubeconfigPath stri
 	return cloudprober, nil
 }
 
-func newCloudproberHandle(desiredVersion *model.HelmUtilityVersion, cluster *model.Cluster, kubeconfigPath string, logger log.FieldLogger) *cloudprober {
+func newCloudproberHandle(definition *model.UtilityDefinition, desiredVersion *model.HelmUtilityVersion, cluster *model.Cluster, kubeconfigPath string, logger log.FieldLogger) *cloudprober {
 	return &cloudprober{
+		definition:     definition,
 		cluster:        cluster,
 		logger:         logger.WithField("cluster-utility", model.CloudproberCanonicalName),
 		kubeconfigPath: kubeconfigPath,
@@ -101,9 +103,9 @@ func (c *cloudprober) ActualVersion() *model.HelmUtilityVersion {
 
 func (c *cloudprober) newHelmDeployment(logger log.FieldLogger) *helmDeployment {
 	return newHelmDeployment(
-		"chartmuseum/cloudprober",
-		"cloudprober",
-		"cloudprober",
+		c.definition.ChartReference(),
+		c.definition.Release,
+		c.definition.Namespace,
 		c.kubeconfigPath,
 		c.desiredVersion,
 		defaultHelmDeploymentSetArgument,
@@ -14,6 +14,7 @@ import (
 )
 
 type fluentbit struct {
+	definition     *model.UtilityDefinition
 	awsClient      aws.AWS
 	kubeconfigPath string
 	logger         log.FieldLogger
@@ -21,7 +22,7 @@ type fluentbit struct {
 	actualVersion  *model.HelmUtilityVersion
 }
 
-func newFluentbitOrUnmanagedHandle(cluster *model.Cluster, kubeconfigPath string, awsClient aws.AWS, logger log.FieldLogger) (Utility, error) {
+func newFluentbitOrUnmanagedHandle(definition *model.UtilityDefinition, cluster *model.Cluster, kubeconfigPath string, awsClient aws.AWS, logger log.FieldLogger) (Utility, error) {
 	desired := cluster.DesiredUtilityVersion(model.FluentbitCanonicalName)
 	actual := cluster.ActualUtilityVersion(model.FluentbitCanonicalName)
 
@@ -29,7 +30,7 @@ func newFluentbitOrUnmanagedHandle(cluster *model.Cluster, kubeconfigPath string
 		return newUnmanagedHandle(model.FluentbitCanonicalName, kubeconfigPath, []string{}, cluster, awsClient, logger), nil
 	}
 
-	fluentbit := newFluentbitHandle(cluster, desired, kubeconfigPath, awsClient, logger)
+	fluentbit := newFluentbitHandle(definition, cluster, desired, kubeconfigPath, awsClient, logger)
 	err := fluentbit.validate()
 	if err != nil {
 		return nil, errors.Wrap(err, "fluentbit utility config is invalid")
@@ -38,8 +39,9 @@ func newFluentbitOrUnmanagedHandle(cluster *model.Cluster, kubeconfigPath string
 	return fluentbit, nil
 }
 
-func newFluentbitHandle(cluster *model.Cluster, desiredVersion *model.HelmUtilityVersion, kubeconfigPath string, awsClient aws.AWS, logger log.FieldLogger) *fluentbit {
+func newFluentbitHandle(definition *model.UtilityDefinition, cluster *model.Cluster, desiredVersion *model.HelmUtilityVersion, kubeconfigPath string, awsClient aws.AWS, logger log.FieldLogger) *fluentbit {
 	return &fluentbit{
+		definition:     definition,
 		awsClient:      awsClient,
 		kubeconfigPath: kubeconfigPath,
 		logger:         logger.WithField("cluster-utility", model.FluentbitCanonicalName),
@@ -100,9 +102,9 @@ func (f *fluentbit) Name() string {
 
 func (f *fluentbit) newHelmDeployment() *helmDeployment {
 	return newHelmDeployment(
-		"fluent/fluent-bit",
-		"fluent-bit",
-		"fluent-bit",
+		f.definition.ChartReference(),
+		f.definition.Release,
+		f.definition.Namespace,
 		f.kubeconfigPath,
 		f.desiredVersion,
 		defaultHelmDeploymentSetArgument,
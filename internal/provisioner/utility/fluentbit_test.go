@@ -20,7 +20,7 @@ func TestNewHelmDeploymentWithDefaultConfiguration(t *testing.T) {
 
 	logger := log.New()
 	awsClient := mocks.NewMockAWS(ctrl)
-	fluentbit := newFluentbitHandle(&model.Cluster{
+	fluentbit := newFluentbitHandle(model.BuiltinUtility(model.FluentbitCanonicalName), &model.Cluster{
 		UtilityMetadata: &model.UtilityMetadata{
 			ActualVersions: model.UtilityGroupVersions{},
 		},
@@ -14,6 +14,7 @@ import (
 )
 
 type metricsServer struct {
+	definition     *model.UtilityDefinition
 	kubeconfigPath string
 	logger         log.FieldLogger
 	desiredVersion *model.HelmUtilityVersion
@@ -21,7 +22,7 @@ type metricsServer struct {
 	provisioner    string
 }
 
-func newMetricsServerOrUnmanagedHandle(cluster *model.Cluster, kubeconfigPath string, awsClient aws.AWS, logger log.FieldLogger) (Utility, error) {
+func newMetricsServerOrUnmanagedHandle(definition *model.UtilityDefinition, cluster *model.Cluster, kubeconfigPath string, awsClient aws.AWS, logger log.FieldLogger) (Utility, error) {
 	desired := cluster.DesiredUtilityVersion(model.MetricsServerCanonicalName)
 	actual := cluster.ActualUtilityVersion(model.MetricsServerCanonicalName)
 
@@ -29,7 +30,7 @@ func newMetricsServerOrUnmanagedHandle(cluster *model.Cluster, kubeconfigPath st
 		return newUnmanagedHandle(model.MetricsServerCanonicalName, kubeconfigPath, []string{}, cluster, awsClient, logger), nil
 	}
 
-	metricsServer := newMetricsServerHandle(desired, cluster, kubeconfigPath, logger)
+	metricsServer := newMetricsServerHandle(definition, desired, cluster, kubeconfigPath, logger)
 	err := metricsServer.validate()
 	if err != nil {
 		return nil, errors.Wrap(err, "metrics server utility config is invalid")
@@ -38,8 +39,9 @@ func newMetricsServerOrUnmanagedHandle(cluster *model.Cluster, kubeconfigPath st
 	return metricsServer, nil
 }
 
-func newMetricsServerHandle(desiredVersion *model.HelmUtilityVersion, cluster *model.Cluster, kubeconfigPath string, logger log.FieldLogger) *metricsServer {
+func newMetricsServerHandle(definition *model.UtilityDefinition, desiredVersion *model.HelmUtilityVersion, cluster *model.Cluster, kubeconfigPath string, logger log.FieldLogger) *metricsServer {
 	return &metricsServer{
+		definition:     definition,
 		kubeconfigPath: kubeconfigPath,
 		logger:         logger.WithField("cluster-utility", model.MetricsServerCanonicalName),
 		desiredVersion: desiredVersion,
@@ -111,9 +113,9 @@ func (m *metricsServer) newHelmDeployment(logger log.FieldLogger) *helmDeploymen
 	}
 
 	return newHelmDeployment(
-		"metrics-server/metrics-server",
-		"metrics-server",
-		"kube-system",
+		m.definition.ChartReference(),
+		m.definition.Release,
+		m.definition.Namespace,
 		m.kubeconfigPath,
 		m.desiredVersion,
 		strings.Join(setArguments, ","),
@@ -19,7 +19,7 @@ func TestNewHelmDeploymentWithDefaultConfigurationMetricsServer(t *testing.T) {
 	defer ctrl.Finish()
 
 	logger := log.New()
-	metricsServer := newMetricsServerHandle(&model.HelmUtilityVersion{Chart: "3.12.1"}, &model.Cluster{
+	metricsServer := newMetricsServerHandle(model.BuiltinUtility(model.MetricsServerCanonicalName), &model.HelmUtilityVersion{Chart: "3.12.1"}, &model.Cluster{
 		UtilityMetadata: &model.UtilityMetadata{
 			ActualVersions: model.UtilityGroupVersions{},
 		},
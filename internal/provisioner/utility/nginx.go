@@ -15,10 +15,12 @@ import (
 )
 
 const (
-	namespaceNginx = "nginx"
+	// NamespaceNginx is the namespace of the public ingress controller.
+	NamespaceNginx = "nginx"
 )
 
 type nginx struct {
+	definition     *model.UtilityDefinition
 	awsClient      aws.AWS
 	kubeconfigPath string
 	logger         log.FieldLogger
@@ -28,7 +30,7 @@ type nginx struct {
 	provisioner    string
 }
 
-func newNginxOrUnmanagedHandle(cluster *model.Cluster, kubeconfigPath string, awsClient aws.AWS, logger log.FieldLogger) (Utility, error) {
+func newNginxOrUnmanagedHandle(definition *model.UtilityDefinition, cluster *model.Cluster, kubeconfigPath string, awsClient aws.AWS, logger log.FieldLogger) (Utility, error) {
 	desired := cluster.DesiredUtilityVersion(model.NginxCanonicalName)
 	actual := cluster.ActualUtilityVersion(model.NginxCanonicalName)
 
@@ -36,7 +38,7 @@ func newNginxOrUnmanagedHandle(cluster *model.Cluster, kubeconfigPath string, aw
 		return newUnmanagedHandle(model.NginxCanonicalName, kubeconfigPath, []string{}, cluster, awsClient, logger), nil
 	}
 
-	nginx := newNginxHandle(desired, cluster, kubeconfigPath, awsClient, logger)
+	nginx := newNginxHandle(definition, desired, cluster, kubeconfigPath, awsClient, logger)
 	err := nginx.validate()
 	if err != nil {
 		return nil, errors.Wrap(err, "nginx utility config is invalid")
@@ -45,8 +47,9 @@ func newNginxOrUnmanagedHandle(cluster *model.Cluster, kubeconfigPath string, aw
 	return nginx, nil
 }
 
-func newNginxHandle(desiredVersion *model.HelmUtilityVersion, cluster *model.Cluster, kubeconfigPath string, awsClient aws.AWS, logger log.FieldLogger) *nginx {
+func newNginxHandle(definition *model.UtilityDefinition, desiredVersion *model.HelmUtilityVersion, cluster *model.Cluster, kubeconfigPath string, awsClient aws.AWS, logger log.FieldLogger) *nginx {
 	return &nginx{
+		definition:     definition,
 		awsClient:      awsClient,
 		kubeconfigPath: kubeconfigPath,
 		cluster:        cluster,
@@ -102,7 +105,7 @@ func (n *nginx) CreateOrUpgrade() error {
 
 func (n *nginx) addLoadBalancerNameTag() error {
 
-	endpoint, elbType, err := getElasticLoadBalancerInfo(namespaceNginx, n.logger, n.kubeconfigPath)
+	endpoint, elbType, err := getElasticLoadBalancerInfo(n.definition.Namespace, n.logger, n.kubeconfigPath)
 	if err != nil {
 		return errors.Wrap(err, "couldn't get the loadbalancer endpoint (nginx)")
 	}
@@ -187,9 +190,9 @@ func (n *nginx) newHelmDeployment() (*helmDeployment, error) {
 	}
 
 	return newHelmDeployment(
-		"ingress-nginx/ingress-nginx",
-		"nginx",
-		namespaceNginx,
+		n.definition.ChartReference(),
+		n.definition.Release,
+		n.definition.Namespace,
 		n.kubeconfigPath,
 		n.desiredVersion,
 		strings.Join(setArguments, ","),
@@ -15,12 +15,12 @@ import (
 )
 
 const (
-	namespaceNginxInternal           = "nginx-internal"
-	chartDeploymentNameNginxInternal = "nginx-internal"
-	chartNameNginxInternal           = "ingress-nginx/ingress-nginx"
+	// NamespaceNginxInternal is the namespace of the private ingress controller.
+	NamespaceNginxInternal = "nginx-internal"
 )
 
 type nginxInternal struct {
+	definition     *model.UtilityDefinition
 	awsClient      aws.AWS
 	kubeconfigPath string
 	logger         log.FieldLogger
@@ -30,7 +30,7 @@ type nginxInternal struct {
 	provisioner    string
 }
 
-func newNginxInternalOrUnmanagedHandle(cluster *model.Cluster, kubeconfigPath string, awsClient aws.AWS, logger log.FieldLogger) (Utility, error) {
+func newNginxInternalOrUnmanagedHandle(definition *model.UtilityDefinition, cluster *model.Cluster, kubeconfigPath string, awsClient aws.AWS, logger log.FieldLogger) (Utility, error) {
 	desired := cluster.DesiredUtilityVersion(model.NginxInternalCanonicalName)
 	actual := cluster.ActualUtilityVersion(model.NginxInternalCanonicalName)
 
@@ -38,7 +38,7 @@ func newNginxInternalOrUnmanagedHandle(cluster *model.Cluster, kubeconfigPath st
 		return newUnmanagedHandle(model.NginxInternalCanonicalName, kubeconfigPath, []string{}, cluster, awsClient, logger), nil
 	}
 
-	nginxInternal := newNginxInternalHandle(desired, cluster, kubeconfigPath, awsClient, logger)
+	nginxInternal := newNginxInternalHandle(definition, desired, cluster, kubeconfigPath, awsClient, logger)
 	err := nginxInternal.validate()
 	if err != nil {
 		return nil, errors.Wrap(err, "nginx internal utility config is invalid")
@@ -47,8 +47,9 @@ func newNginxInternalOrUnmanagedHandle(cluster *model.Cluster, kubeconfigPath st
 	return nginxInternal, nil
 }
 
-func newNginxInternalHandle(version *model.HelmUtilityVersion, cluster *model.Cluster, kubeconfigPath string, awsClient aws.AWS, logger log.FieldLogger) *nginxInternal {
+func newNginxInternalHandle(definition *model.UtilityDefinition, version *model.HelmUtilityVersion, cluster *model.Cluster, kubeconfigPath string, awsClient aws.AWS, logger log.FieldLogger) *nginxInternal {
 	return &nginxInternal{
+		definition:     definition,
 		awsClient:      awsClient,
 		kubeconfigPath: kubeconfigPath,
 		cluster:        cluster,
@@ -105,7 +106,7 @@ func (n *nginxInternal) CreateOrUpgrade() error {
 
 func (n *nginxInternal) addLoadBalancerNameTag() error {
 
-	endpoint, elbType, err := getElasticLoadBalancerInfo(namespaceNginxInternal, n.logger, n.kubeconfigPath)
+	endpoint, elbType, err := getElasticLoadBalancerInfo(NamespaceNginxInternal, n.logger, n.kubeconfigPath)
 	if err != nil {
 		return errors.Wrap(err, "couldn't get the loadbalancer endpoint (nginx-internal)")
 	}
@@ -181,9 +182,9 @@ func (n *nginxInternal) newHelmDeployment(withArguments bool) (*helmDeployment,
 	}
 
 	return newHelmDeployment(
-		chartNameNginxInternal,
-		chartDeploymentNameNginxInternal,
-		namespaceNginxInternal,
+		n.definition.ChartReference(),
+		n.definition.Release,
+		n.definition.Namespace,
 		n.kubeconfigPath,
 		n.desiredVersion,
 		strings.Join(setArguments, ","),
@@ -14,13 +14,14 @@ import (
 )
 
 type nodeProblemDetector struct {
+	definition     *model.UtilityDefinition
 	kubeconfigPath string
 	logger         log.FieldLogger
 	desiredVersion *model.HelmUtilityVersion
 	actualVersion  *model.HelmUtilityVersion
 }
 
-func newNodeProblemDetectorOrUnmanagedHandle(cluster *model.Cluster, kubeconfigPath string, awsClient aws.AWS, logger log.FieldLogger) (Utility, error) {
+func newNodeProblemDetectorOrUnmanagedHandle(definition *model.UtilityDefinition, cluster *model.Cluster, kubeconfigPath string, awsClient aws.AWS, logger log.FieldLogger) (Utility, error) {
 	desired := cluster.DesiredUtilityVersion(model.NodeProblemDetectorCanonicalName)
 	actual := cluster.ActualUtilityVersion(model.NodeProblemDetectorCanonicalName)
 
@@ -28,7 +29,7 @@ func newNodeProblemDetectorOrUnmanagedHandle(cluster *model.Cluster, kubeconfigP
 		return newUnmanagedHandle(model.NodeProblemDetectorCanonicalName, kubeconfigPath, []string{}, cluster, awsClient, logger), nil
 	}
 
-	nodeProblemDetector := newNodeProblemDetectorHandle(desired, cluster, kubeconfigPath, logger)
+	nodeProblemDetector := newNodeProblemDetectorHandle(definition, desired, cluster, kubeconfigPath, logger)
 	err := nodeProblemDetector.validate()
 	if err != nil {
 		return nil, errors.Wrap(err, "node problem detector utility config is invalid")
@@ -37,8 +38,9 @@ func newNodeProblemDetectorOrUnmanagedHandle(cluster *model.Cluster, kubeconfigP
 	return nodeProblemDetector, nil
 }
 
-func newNodeProblemDetectorHandle(desiredVersion *model.HelmUtilityVersion, cluster *model.Cluster, kubeconfigPath string, logger log.FieldLogger) *nodeProblemDetector {
+func newNodeProblemDetectorHandle(definition *model.UtilityDefinition, desiredVersion *model.HelmUtilityVersion, cluster *model.Cluster, kubeconfigPath string, logger log.FieldLogger) *nodeProblemDetector {
 	return &nodeProblemDetector{
+		definition:     definition,
 		kubeconfigPath: kubeconfigPath,
 		logger:         logger.WithField("cluster-utility", model.NodeProblemDetectorCanonicalName),
 		desiredVersion: desiredVersion,
@@ -96,9 +98,9 @@ func (n *nodeProblemDetector) Name() string {
 
 func (n *nodeProblemDetector) newHelmDeployment(logger log.FieldLogger) *helmDeployment {
 	return newHelmDeployment(
-		"deliveryhero/node-problem-detector",
-		"node-problem-detector",
-		"node-problem-detector",
+		n.definition.ChartReference(),
+		n.definition.Release,
+		n.definition.Namespace,
 		n.kubeconfigPath,
 		n.desiredVersion,
 		defaultHelmDeploymentSetArgument,
@@ -18,7 +18,13 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+const (
+	// NamespacePgbouncer is the namespace of the pgbouncer connection pooler.
+	NamespacePgbouncer = "pgbouncer"
+)
+
 type pgbouncer struct {
+	definition     *model.UtilityDefinition
 	awsClient      aws.AWS
 	environment    string
 	kubeconfigPath string
@@ -28,7 +34,7 @@ type pgbouncer struct {
 	actualVersion  *model.HelmUtilityVersion
 }
 
-func newPgbouncerOrUnmanagedHandle(cluster *model.Cluster, kubeconfigPath string, awsClient aws.AWS, logger log.FieldLogger) (Utility, error) {
+func newPgbouncerOrUnmanagedHandle(definition *model.UtilityDefinition, cluster *model.Cluster, kubeconfigPath string, awsClient aws.AWS, logger log.FieldLogger) (Utility, error) {
 	desired := cluster.DesiredUtilityVersion(model.PgbouncerCanonicalName)
 	actual := cluster.ActualUtilityVersion(model.PgbouncerCanonicalName)
 
@@ -36,7 +42,7 @@ func newPgbouncerOrUnmanagedHandle(cluster *model.Cluster, kubeconfigPath string
 		return newUnmanagedHandle(model.PgbouncerCanonicalName, kubeconfigPath, []string{}, cluster, awsClient, logger), nil
 	}
 
-	pgbouncer := newPgbouncerHandle(cluster, desired, kubeconfigPath, awsClient, logger)
+	pgbouncer := newPgbouncerHandle(definition, cluster, desired, kubeconfigPath, awsClient, logger)
 	err := pgbouncer.validate()
 	if err != nil {
 		return nil, errors.Wrap(err, "pgbouncer utility config is invalid")
@@ -45,8 +51,9 @@ func newPgbouncerOrUnmanagedHandle(cluster *model.Cluster, kubeconfigPath string
 	return pgbouncer, nil
 }
 
-func newPgbouncerHandle(cluster *model.Cluster, desiredVersion *model.HelmUtilityVersion, kubeconfigPath string, awsClient aws.AWS, logger log.FieldLogger) *pgbouncer {
+func newPgbouncerHandle(definition *model.UtilityDefinition, cluster *model.Cluster, desiredVersion *model.HelmUtilityVersion, kubeconfigPath string, awsClient aws.AWS, logger log.FieldLogger) *pgbouncer {
 	return &pgbouncer{
+		definition:     definition,
 		awsClient:      awsClient,
 		environment:    awsClient.GetCloudEnvironmentName(),
 		cluster:        cluster,
@@ -129,9 +136,9 @@ func (p *pgbouncer) Migrate() error {
 
 func (p *pgbouncer) newHelmDeployment() *helmDeployment {
 	return newHelmDeployment(
-		"chartmuseum/pgbouncer",
-		"pgbouncer",
-		"pgbouncer",
+		p.definition.ChartReference(),
+		p.definition.Release,
+		p.definition.Namespace,
 		p.kubeconfigPath,
 		p.desiredVersion,
 		defaultHelmDeploymentSetArgument,
@@ -151,7 +158,7 @@ func DeployPgbouncerManifests(k8sClient *k8s.KubeClient, logger log.FieldLogger)
 	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(180)*time.Second)
 	defer cancel()
 
-	_, err := k8sClient.CreateOrUpdateNamespace("pgbouncer")
+	_, err := k8sClient.CreateOrUpdateNamespace(NamespacePgbouncer)
 	if err != nil {
 		return errors.Wrapf(err, "failed to create the pgbouncer namespace")
 	}
@@ -159,12 +166,12 @@ func DeployPgbouncerManifests(k8sClient *k8s.KubeClient, logger log.FieldLogger)
 	// Both of these files should only be created on the first provision and
 	// should never be overwritten with cluster provisioning afterwards.
 	var file k8s.ManifestFile
-	_, err = k8sClient.Clientset.CoreV1().ConfigMaps("pgbouncer").Get(ctx, "pgbouncer-configmap", metav1.GetOptions{})
+	_, err = k8sClient.Clientset.CoreV1().ConfigMaps(NamespacePgbouncer).Get(ctx, "pgbouncer-configmap", metav1.GetOptions{})
 	if k8sErrors.IsNotFound(err) {
 		logger.Info("Configmap resource for pgbouncer-configmap does not exist, will be created...")
 		file = k8s.ManifestFile{
 			Path:            "manifests/pgbouncer-manifests/pgbouncer-configmap.yaml",
-			DeployNamespace: "pgbouncer",
+			DeployNamespace: NamespacePgbouncer,
 		}
 		err = k8sClient.CreateFromFile(file, "")
 		if err != nil {
@@ -174,12 +181,12 @@ func DeployPgbouncerManifests(k8sClient *k8s.KubeClient, logger log.FieldLogger)
 		return errors.Wrap(err, "failed to get configmap for pgbouncer-configmap")
 	}
 
-	_, err = k8sClient.Clientset.CoreV1().Secrets("pgbouncer").Get(ctx, "pgbouncer-userlist-secret", metav1.GetOptions{})
+	_, err = k8sClient.Clientset.CoreV1().Secrets(NamespacePgbouncer).Get(ctx, "pgbouncer-userlist-secret", metav1.GetOptions{})
 	if k8sErrors.IsNotFound(err) {
 		logger.Info("Secret resource for pgbouncer-userlist-secret does not exist, will be created...")
 		file = k8s.ManifestFile{
 			Path:            "manifests/pgbouncer-manifests/pgbouncer-secret.yaml",
-			DeployNamespace: "pgbouncer",
+			DeployNamespace: NamespacePgbouncer,
 		}
 		err = k8sClient.CreateFromFile(file, "")
 		if err != nil {
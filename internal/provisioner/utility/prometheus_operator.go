@@ -10,7 +10,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/mattermost/mattermost-cloud/internal/provisioner/prometheus"
 	"github.com/mattermost/mattermost-cloud/internal/tools/aws"
 	"github.com/mattermost/mattermost-cloud/k8s"
 	"github.com/mattermost/mattermost-cloud/model"
@@ -22,6 +21,7 @@ import (
 )
 
 type prometheusOperator struct {
+	definition         *model.UtilityDefinition
 	awsClient          aws.AWS
 	cluster            *model.Cluster
 	allowCIDRRangeList []string
@@ -31,7 +31,7 @@ type prometheusOperator struct {
 	actualVersion      *model.HelmUtilityVersion
 }
 
-func newPrometheusOperatorOrUnmanagedHandle(cluster *model.Cluster, kubeconfigPath string, allowCIDRRangeList []string, awsClient aws.AWS, logger log.FieldLogger) (Utility, error) {
+func newPrometheusOperatorOrUnmanagedHandle(definition *model.UtilityDefinition, cluster *model.Cluster, kubeconfigPath string, allowCIDRRangeList []string, awsClient aws.AWS, logger log.FieldLogger) (Utility, error) {
 	desired := cluster.DesiredUtilityVersion(model.PrometheusOperatorCanonicalName)
 	actual := cluster.ActualUtilityVersion(model.PrometheusOperatorCanonicalName)
 
@@ -39,7 +39,7 @@ func newPrometheusOperatorOrUnmanagedHandle(cluster *model.Cluster, kubeconfigPa
 		return newUnmanagedHandle(model.PrometheusOperatorCanonicalName, kubeconfigPath, allowCIDRRangeList, cluster, awsClient, logger), nil
 	}
 
-	prometheusOperator := newPrometheusOperatorHandle(cluster, desired, kubeconfigPath, allowCIDRRangeList, awsClient, logger)
+	prometheusOperator := newPrometheusOperatorHandle(definition, cluster, desired, kubeconfigPath, allowCIDRRangeList, awsClient, logger)
 	err := prometheusOperator.validate()
 	if err != nil {
 		return nil, errors.Wrap(err, "prometheus operator utility config is invalid")
@@ -48,8 +48,9 @@ func newPrometheusOperatorOrUnmanagedHandle(cluster *model.Cluster, kubeconfigPa
 	return prometheusOperator, nil
 }
 
-func newPrometheusOperatorHandle(cluster *model.Cluster, desiredVersion *model.HelmUtilityVersion, kubeconfigPath string, allowCIDRRangeList []string, awsClient aws.AWS, logger log.FieldLogger) *prometheusOperator {
+func newPrometheusOperatorHandle(definition *model.UtilityDefinition, cluster *model.Cluster, desiredVersion *model.HelmUtilityVersion, kubeconfigPath string, allowCIDRRangeList []string, awsClient aws.AWS, logger log.FieldLogger) *prometheusOperator {
 	return &prometheusOperator{
+		definition:         definition,
 		awsClient:          awsClient,
 		cluster:            cluster,
 		allowCIDRRangeList: allowCIDRRangeList,
@@ -106,12 +107,12 @@ func (p *prometheusOperator) CreateOrUpgrade() error {
 		return errors.Wrap(err, "failed to set up the k8s client")
 	}
 
-	_, err = k8sClient.CreateOrUpdateNamespace(prometheus.Namespace)
+	_, err = k8sClient.CreateOrUpdateNamespace(p.definition.Namespace)
 	if err != nil {
 		return errors.Wrapf(err, "failed to create the prometheus namespace")
 	}
 
-	_, err = k8sClient.CreateOrUpdateSecret(prometheus.Namespace, thanosObjStoreSecret)
+	_, err = k8sClient.CreateOrUpdateSecret(p.definition.Namespace, thanosObjStoreSecret)
 	if err != nil {
 		return errors.Wrapf(err, "failed to create the Thanos object storage secret")
 	}
@@ -145,7 +146,7 @@ func (p *prometheusOperator) CreateOrUpgrade() error {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(180)*time.Second)
 	defer cancel()
 
-	endpoint, err := getPrivateLoadBalancerEndpoint(ctx, namespaceNginxInternal, logger.WithField("prometheus-action", "create"), p.kubeconfigPath)
+	endpoint, err := getPrivateLoadBalancerEndpoint(ctx, NamespaceNginxInternal, logger.WithField("prometheus-action", "create"), p.kubeconfigPath)
 	if err != nil {
 		return errors.Wrap(err, "couldn't get the load balancer endpoint (nginx) for Prometheus")
 	}
@@ -196,9 +197,9 @@ func (p *prometheusOperator) newHelmDeployment(prometheusDNS string) *helmDeploy
 	helmValueArguments := fmt.Sprintf("prometheus.prometheusSpec.externalLabels.clusterID=%s,prometheus.ingress.hosts={%s},prometheus.ingress.annotations.nginx\\.ingress\\.kubernetes\\.io/whitelist-source-range=%s", p.cluster.ID, prometheusDNS, strings.Join(p.allowCIDRRangeList, "\\,"))
 
 	return newHelmDeployment(
-		"prometheus-community/kube-prometheus-stack",
-		"prometheus-operator",
-		prometheus.Namespace,
+		p.definition.ChartReference(),
+		p.definition.Release,
+		p.definition.Namespace,
 		p.kubeconfigPath,
 		p.desiredVersion,
 		helmValueArguments,
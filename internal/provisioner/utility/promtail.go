@@ -15,6 +15,7 @@ import (
 )
 
 type promtail struct {
+	definition     *model.UtilityDefinition
 	awsClient      aws.AWS
 	environment    string
 	kubeconfigPath string
@@ -24,7 +25,7 @@ type promtail struct {
 	actualVersion  *model.HelmUtilityVersion
 }
 
-func newPromtailOrUnmanagedHandle(cluster *model.Cluster, kubeconfigPath string, awsClient aws.AWS, logger log.FieldLogger) (Utility, error) {
+func newPromtailOrUnmanagedHandle(definition *model.UtilityDefinition, cluster *model.Cluster, kubeconfigPath string, awsClient aws.AWS, logger log.FieldLogger) (Utility, error) {
 	desired := cluster.DesiredUtilityVersion(model.PromtailCanonicalName)
 	actual := cluster.ActualUtilityVersion(model.PromtailCanonicalName)
 
@@ -32,7 +33,7 @@ func newPromtailOrUnmanagedHandle(cluster *model.Cluster, kubeconfigPath string,
 		return newUnmanagedHandle(model.PromtailCanonicalName, kubeconfigPath, []string{}, cluster, awsClient, logger), nil
 	}
 
-	promtail := newPromtailHandle(cluster, desired, kubeconfigPath, awsClient, logger)
+	promtail := newPromtailHandle(definition, cluster, desired, kubeconfigPath, awsClient, logger)
 	err := promtail.validate()
 	if err != nil {
 		return nil, errors.Wrap(err, "promtail utility config is invalid")
@@ -41,8 +42,9 @@ func newPromtailOrUnmanagedHandle(cluster *model.Cluster, kubeconfigPath string,
 	return promtail, nil
 }
 
-func newPromtailHandle(cluster *model.Cluster, desiredVersion *model.HelmUtilityVersion, kubeconfigPath string, awsClient aws.AWS, logger log.FieldLogger) *promtail {
+func newPromtailHandle(definition *model.UtilityDefinition, cluster *model.Cluster, desiredVersion *model.HelmUtilityVersion, kubeconfigPath string, awsClient aws.AWS, logger log.FieldLogger) *promtail {
 	return &promtail{
+		definition:     definition,
 		awsClient:      awsClient,
 		environment:    awsClient.GetCloudEnvironmentName(),
 		cluster:        cluster,
@@ -115,9 +117,9 @@ func (p *promtail) Migrate() error {
 
 func (p *promtail) newHelmDeployment() *helmDeployment {
 	return newHelmDeployment(
-		"grafana/promtail",
-		"promtail",
-		"promtail",
+		p.definition.ChartReference(),
+		p.definition.Release,
+		p.definition.Namespace,
 		p.kubeconfigPath,
 		p.desiredVersion,
 		fmt.Sprintf("extraArgs={-client.external-labels=cluster=%s}", p.cluster.ID),
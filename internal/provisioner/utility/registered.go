@@ -0,0 +1,132 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package utility
+
+import (
+	"strings"
+
+	"github.com/mattermost/mattermost-cloud/internal/tools/aws"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// registered is a utility added to the utility registry of a cluster. It
+// is deployed with its Helm chart and values only.
+type registered struct {
+	definition     *model.UtilityDefinition
+	kubeconfigPath string
+	logger         log.FieldLogger
+	desiredVersion *model.HelmUtilityVersion
+	actualVersion  *model.HelmUtilityVersion
+}
+
+func newRegisteredOrUnmanagedHandle(definition *model.UtilityDefinition, cluster *model.Cluster, kubeconfigPath string, awsClient aws.AWS, logger log.FieldLogger) (Utility, error) {
+	desired := cluster.DesiredUtilityVersion(definition.Name)
+	actual := cluster.ActualUtilityVersion(definition.Name)
+
+	if model.UtilityIsUnmanaged(desired, actual) {
+		return newUnmanagedHandle(definition.Name, kubeconfigPath, []string{}, cluster, awsClient, logger), nil
+	}
+
+	utility := newRegisteredHandle(definition, desired, actual, kubeconfigPath, logger)
+	err := utility.validate()
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s utility config is invalid", definition.Name)
+	}
+
+	return utility, nil
+}
+
+func newRegisteredHandle(definition *model.UtilityDefinition, desiredVersion, actualVersion *model.HelmUtilityVersion, kubeconfigPath string, logger log.FieldLogger) *registered {
+	return &registered{
+		definition:     definition,
+		kubeconfigPath: kubeconfigPath,
+		logger:         logger.WithField("cluster-utility", definition.Name),
+		desiredVersion: desiredVersion,
+		actualVersion:  actualVersion,
+	}
+}
+
+func (r *registered) validate() error {
+	if r.kubeconfigPath == "" {
+		return errors.New("kubeconfig path cannot be empty")
+	}
+	if r.definition.Repo == "" || r.definition.Chart == "" {
+		return errors.New("chart cannot be empty")
+	}
+	if r.definition.Release == "" {
+		return errors.New("release cannot be empty")
+	}
+	if r.definition.Namespace == "" {
+		return errors.New("namespace cannot be empty")
+	}
+
+	return nil
+}
+
+func (r *registered) Destroy() error {
+	helm := r.newHelmDeployment(r.logger)
+	return helm.Delete()
+}
+
+func (r *registered) Migrate() error {
+	return nil
+}
+
+func (r *registered) CreateOrUpgrade() error {
+	logger := r.logger.WithField("registered-utility-action", "upgrade")
+	h := r.newHelmDeployment(logger)
+
+	err := h.Update()
+	if err != nil {
+		return err
+	}
+
+	actualVersion, err := h.Version()
+	if err != nil {
+		return err
+	}
+	r.actualVersion = actualVersion
+
+	return nil
+}
+
+func (r *registered) DesiredVersion() *model.HelmUtilityVersion {
+	return r.desiredVersion
+}
+
+func (r *registered) ActualVersion() *model.HelmUtilityVersion {
+	if r.actualVersion == nil {
+		return nil
+	}
+	return &model.HelmUtilityVersion{
+		Chart:      strings.TrimPrefix(r.actualVersion.Version(), r.definition.Chart+"-"),
+		ValuesPath: r.actualVersion.Values(),
+	}
+}
+
+func (r *registered) Name() string {
+	return r.definition.Name
+}
+
+func (r *registered) ValuesPath() string {
+	if r.desiredVersion == nil {
+		return ""
+	}
+	return r.desiredVersion.Values()
+}
+
+func (r *registered) newHelmDeployment(logger log.FieldLogger) *helmDeployment {
+	return newHelmDeployment(
+		r.definition.ChartReference(),
+		r.definition.Release,
+		r.definition.Namespace,
+		r.kubeconfigPath,
+		r.desiredVersion,
+		defaultHelmDeploymentSetArgument,
+		logger,
+	)
+}
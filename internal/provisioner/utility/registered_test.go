@@ -0,0 +1,73 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package utility
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-cloud/model"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRegisteredOrUnmanagedHandle(t *testing.T) {
+	logger := log.New()
+	definition := &model.UtilityDefinition{
+		Name:      "cert-manager",
+		Repo:      "jetstack",
+		RepoURL:   "https://charts.jetstack.io",
+		Chart:     "cert-manager",
+		Release:   "cert-manager",
+		Namespace: "cert-manager",
+	}
+
+	t.Run("managed", func(t *testing.T) {
+		cluster := &model.Cluster{
+			UtilityMetadata: &model.UtilityMetadata{
+				Utilities: []*model.UtilityDefinition{definition},
+				DesiredVersions: model.UtilityGroupVersions{
+					Custom: map[string]*model.HelmUtilityVersion{"cert-manager": {Chart: "1.14.4", ValuesPath: "values.yaml"}},
+				},
+				ActualVersions: model.UtilityGroupVersions{
+					Custom: map[string]*model.HelmUtilityVersion{"cert-manager": {Chart: "cert-manager-v1.14.3", ValuesPath: "values.yaml"}},
+				},
+			},
+		}
+
+		utility, err := newRegisteredOrUnmanagedHandle(definition, cluster, "kubeconfig", nil, logger)
+		require.NoError(t, err)
+		require.IsType(t, &registered{}, utility)
+		assert.Equal(t, "cert-manager", utility.Name())
+		assert.Equal(t, "values.yaml", utility.ValuesPath())
+		assert.Equal(t, "1.14.4", utility.DesiredVersion().Version())
+		assert.Equal(t, "v1.14.3", utility.ActualVersion().Version())
+
+		helmDeployment := utility.(*registered).newHelmDeployment(logger)
+		assert.Equal(t, "jetstack/cert-manager", helmDeployment.chartName)
+		assert.Equal(t, "cert-manager", helmDeployment.chartDeploymentName)
+		assert.Equal(t, "cert-manager", helmDeployment.namespace)
+	})
+
+	t.Run("unmanaged", func(t *testing.T) {
+		cluster := &model.Cluster{
+			UtilityMetadata: &model.UtilityMetadata{
+				Utilities: []*model.UtilityDefinition{definition},
+				DesiredVersions: model.UtilityGroupVersions{
+					Custom: map[string]*model.HelmUtilityVersion{"cert-manager": {Chart: model.UnmanagedUtilityVersion}},
+				},
+			},
+		}
+
+		utility, err := newRegisteredOrUnmanagedHandle(definition, cluster, "kubeconfig", nil, logger)
+		require.NoError(t, err)
+		assert.IsType(t, &unmanaged{}, utility)
+	})
+
+	t.Run("no kubeconfig", func(t *testing.T) {
+		_, err := newRegisteredOrUnmanagedHandle(definition, &model.Cluster{}, "", nil, logger)
+		assert.Error(t, err)
+	})
+}
@@ -14,6 +14,7 @@ import (
 )
 
 type rtcd struct {
+	definition     *model.UtilityDefinition
 	environment    string
 	kubeconfigPath string
 	cluster        *model.Cluster
@@ -22,7 +23,7 @@ type rtcd struct {
 	actualVersion  *model.HelmUtilityVersion
 }
 
-func newRtcdOrUnmanagedHandle(cluster *model.Cluster, kubeconfigPath string, awsClient aws.AWS, logger log.FieldLogger) (Utility, error) {
+func newRtcdOrUnmanagedHandle(definition *model.UtilityDefinition, cluster *model.Cluster, kubeconfigPath string, awsClient aws.AWS, logger log.FieldLogger) (Utility, error) {
 	desired := cluster.DesiredUtilityVersion(model.RtcdCanonicalName)
 	actual := cluster.ActualUtilityVersion(model.RtcdCanonicalName)
 
@@ -30,7 +31,7 @@ func newRtcdOrUnmanagedHandle(cluster *model.Cluster, kubeconfigPath string, aws
 		return newUnmanagedHandle(model.RtcdCanonicalName, kubeconfigPath, []string{}, cluster, awsClient, logger), nil
 	}
 
-	rtcd := newRtcdHandle(cluster, desired, kubeconfigPath, awsClient, logger)
+	rtcd := newRtcdHandle(definition, cluster, desired, kubeconfigPath, awsClient, logger)
 	err := rtcd.validate()
 	if err != nil {
 		return nil, errors.Wrap(err, "rtcd utility config is invalid")
@@ -39,8 +40,9 @@ func newRtcdOrUnmanagedHandle(cluster *model.Cluster, kubeconfigPath string, aws
 	return rtcd, nil
 }
 
-func newRtcdHandle(cluster *model.Cluster, desiredVersion *model.HelmUtilityVersion, kubeconfigPath string, awsClient aws.AWS, logger log.FieldLogger) *rtcd {
+func newRtcdHandle(definition *model.UtilityDefinition, cluster *model.Cluster, desiredVersion *model.HelmUtilityVersion, kubeconfigPath string, awsClient aws.AWS, logger log.FieldLogger) *rtcd {
 	return &rtcd{
+		definition:     definition,
 		environment:    awsClient.GetCloudEnvironmentName(),
 		kubeconfigPath: kubeconfigPath,
 		cluster:        cluster,
@@ -114,9 +116,9 @@ func (r *rtcd) Migrate() error {
 
 func (r *rtcd) newHelmDeployment() *helmDeployment {
 	return newHelmDeployment(
-		"mattermost/mattermost-rtcd",
-		"mattermost-rtcd",
-		"mattermost-rtcd",
+		r.definition.ChartReference(),
+		r.definition.Release,
+		r.definition.Namespace,
 		r.kubeconfigPath,
 		r.desiredVersion,
 		defaultHelmDeploymentSetArgument,
@@ -15,6 +15,7 @@ import (
 )
 
 type teleport struct {
+	definition     *model.UtilityDefinition
 	awsClient      aws.AWS
 	kubeconfigPath string
 	environment    string
@@ -24,7 +25,7 @@ type teleport struct {
 	actualVersion  *model.HelmUtilityVersion
 }
 
-func newTeleportOrUnmanagedHandle(cluster *model.Cluster, kubeconfigPath string, awsClient aws.AWS, logger log.FieldLogger) (Utility, error) {
+func newTeleportOrUnmanagedHandle(definition *model.UtilityDefinition, cluster *model.Cluster, kubeconfigPath string, awsClient aws.AWS, logger log.FieldLogger) (Utility, error) {
 	desired := cluster.DesiredUtilityVersion(model.TeleportCanonicalName)
 	actual := cluster.ActualUtilityVersion(model.TeleportCanonicalName)
 
@@ -32,7 +33,7 @@ func newTeleportOrUnmanagedHandle(cluster *model.Cluster, kubeconfigPath string,
 		return newUnmanagedHandle(model.TeleportCanonicalName, kubeconfigPath, []string{}, cluster, awsClient, logger), nil
 	}
 
-	teleport := newTeleportHandle(cluster, desired, kubeconfigPath, awsClient, logger)
+	teleport := newTeleportHandle(definition, cluster, desired, kubeconfigPath, awsClient, logger)
 	err := teleport.validate()
 	if err != nil {
 		return nil, errors.Wrap(err, "teleport utility config is invalid")
@@ -41,8 +42,9 @@ func newTeleportOrUnmanagedHandle(cluster *model.Cluster, kubeconfigPath string,
 	return teleport, nil
 }
 
-func newTeleportHandle(cluster *model.Cluster, desiredVersion *model.HelmUtilityVersion, kubeconfigPath string, awsClient aws.AWS, logger log.FieldLogger) *teleport {
+func newTeleportHandle(definition *model.UtilityDefinition, cluster *model.Cluster, desiredVersion *model.HelmUtilityVersion, kubeconfigPath string, awsClient aws.AWS, logger log.FieldLogger) *teleport {
 	return &teleport{
+		definition:     definition,
 		awsClient:      awsClient,
 		kubeconfigPath: kubeconfigPath,
 		environment:    awsClient.GetCloudEnvironmentName(),
@@ -125,9 +127,9 @@ func (t *teleport) Migrate() error {
 func (t *teleport) newHelmDeployment() *helmDeployment {
 	teleportClusterName := fmt.Sprintf("cloud-%s-%s", t.environment, t.cluster.ID)
 	return newHelmDeployment(
-		"chartmuseum/teleport-kube-agent",
-		"teleport-kube-agent",
-		"teleport",
+		t.definition.ChartReference(),
+		t.definition.Release,
+		t.definition.Namespace,
 		t.kubeconfigPath,
 		t.desiredVersion,
 		fmt.Sprintf("kubeClusterName=%s", teleportClusterName),
@@ -10,7 +10,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/mattermost/mattermost-cloud/internal/provisioner/prometheus"
 	"github.com/mattermost/mattermost-cloud/internal/tools/aws"
 	"github.com/mattermost/mattermost-cloud/model"
 	"github.com/pkg/errors"
@@ -18,6 +17,7 @@ import (
 )
 
 type thanos struct {
+	definition         *model.UtilityDefinition
 	awsClient          aws.AWS
 	kubeconfigPath     string
 	allowCIDRRangeList []string
@@ -27,7 +27,7 @@ type thanos struct {
 	desiredVersion     *model.HelmUtilityVersion
 }
 
-func newThanosOrUnmanagedHandle(cluster *model.Cluster, kubeconfigPath string, allowCIDRRangeList []string, awsClient aws.AWS, logger log.FieldLogger) (Utility, error) {
+func newThanosOrUnmanagedHandle(definition *model.UtilityDefinition, cluster *model.Cluster, kubeconfigPath string, allowCIDRRangeList []string, awsClient aws.AWS, logger log.FieldLogger) (Utility, error) {
 	desired := cluster.DesiredUtilityVersion(model.ThanosCanonicalName)
 	actual := cluster.ActualUtilityVersion(model.ThanosCanonicalName)
 
@@ -35,7 +35,7 @@ func newThanosOrUnmanagedHandle(cluster *model.Cluster, kubeconfigPath string, a
 		return newUnmanagedHandle(model.ThanosCanonicalName, kubeconfigPath, allowCIDRRangeList, cluster, awsClient, logger), nil
 	}
 
-	thanos := newThanosHandle(cluster, desired, kubeconfigPath, allowCIDRRangeList, awsClient, logger)
+	thanos := newThanosHandle(definition, cluster, desired, kubeconfigPath, allowCIDRRangeList, awsClient, logger)
 	err := thanos.validate()
 	if err != nil {
 		return nil, errors.Wrap(err, "thanos utility config is invalid")
@@ -44,8 +44,9 @@ func newThanosOrUnmanagedHandle(cluster *model.Cluster, kubeconfigPath string, a
 	return thanos, nil
 }
 
-func newThanosHandle(cluster *model.Cluster, desiredVersion *model.HelmUtilityVersion, kubeconfigPath string, allowCIDRRangeList []string, awsClient aws.AWS, logger log.FieldLogger) *thanos {
+func newThanosHandle(definition *model.UtilityDefinition, cluster *model.Cluster, desiredVersion *model.HelmUtilityVersion, kubeconfigPath string, allowCIDRRangeList []string, awsClient aws.AWS, logger log.FieldLogger) *thanos {
 	return &thanos{
+		definition:         definition,
 		awsClient:          awsClient,
 		kubeconfigPath:     kubeconfigPath,
 		allowCIDRRangeList: allowCIDRRangeList,
@@ -105,7 +106,7 @@ func (t *thanos) CreateOrUpgrade() error {
 		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(120)*time.Second)
 		defer cancel()
 
-		endpoint, err := getPrivateLoadBalancerEndpoint(ctx, namespaceNginxInternal, logger.WithField("thanos-action", "create"), t.kubeconfigPath)
+		endpoint, err := getPrivateLoadBalancerEndpoint(ctx, NamespaceNginxInternal, logger.WithField("thanos-action", "create"), t.kubeconfigPath)
 		if err != nil {
 			return errors.Wrap(err, "couldn't get the load balancer endpoint (nginx) for Thanos")
 		}
@@ -124,7 +125,7 @@ func (t *thanos) CreateOrUpgrade() error {
 		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(120)*time.Second)
 		defer cancel()
 
-		endpoint, err := getPrivateLoadBalancerEndpoint(ctx, prometheus.Namespace, logger.WithField("thanos-action", "create"), t.kubeconfigPath)
+		endpoint, err := getPrivateLoadBalancerEndpoint(ctx, t.definition.Namespace, logger.WithField("thanos-action", "create"), t.kubeconfigPath)
 		if err != nil {
 			return errors.Wrap(err, "couldn't get the load balancer endpoint for Thanos")
 		}
@@ -175,9 +176,9 @@ func (t *thanos) newHelmDeployment(thanosDNS string) *helmDeployment {
 	helmValueArguments := fmt.Sprintf("query.ingress.hostname=%s,query.ingress.annotations.nginx\\.ingress\\.kubernetes\\.io/whitelist-source-range=%s", thanosDNS, strings.Join(t.allowCIDRRangeList, "\\,"))
 
 	return newHelmDeployment(
-		"bitnami/thanos",
-		"thanos",
-		prometheus.Namespace,
+		t.definition.ChartReference(),
+		t.definition.Release,
+		t.definition.Namespace,
 		t.kubeconfigPath,
 		t.desiredVersion,
 		helmValueArguments,
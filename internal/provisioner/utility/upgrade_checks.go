@@ -0,0 +1,178 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package utility
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/blang/semver"
+	"github.com/mattermost/mattermost-cloud/internal/tools/helm"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"sigs.k8s.io/yaml"
+)
+
+// manifestObject holds the fields of a manifest document identifying the
+// resource it declares.
+type manifestObject struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Metadata   struct {
+		Name string `json:"name"`
+	} `json:"metadata"`
+}
+
+// chartMetadata holds the fields of a Chart.yaml used for compatibility
+// checks.
+type chartMetadata struct {
+	KubeVersion string `json:"kubeVersion"`
+}
+
+// InstalledManifestResources returns the resources declared by all Helm
+// releases installed on the cluster.
+func InstalledManifestResources(kubeconfigPath string, logger log.FieldLogger) ([]model.KubernetesManifestResource, error) {
+	releaseList, err := newHelmDeployment("", "", "", kubeconfigPath, nil, defaultHelmDeploymentSetArgument, logger).List()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list helm releases")
+	}
+
+	helmClient, err := helm.New(kubeconfigPath, logger)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to create helm wrapper")
+	}
+	defer helmClient.Close()
+
+	var resources []model.KubernetesManifestResource
+	for _, release := range releaseList.asSlice() {
+		manifest, err := helmClient.RunCommandRaw("get", "manifest", release.Name, "--namespace", release.Namespace)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get manifest of helm release %s", release.Name)
+		}
+
+		releaseResources, err := parseManifestResources(fmt.Sprintf("helm release %s/%s", release.Namespace, release.Name), manifest)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse manifest of helm release %s", release.Name)
+		}
+		resources = append(resources, releaseResources...)
+	}
+
+	return resources, nil
+}
+
+var manifestSeparator = regexp.MustCompile(`(?m)^---\s*$`)
+
+// parseManifestResources returns the resources declared by a multi-document
+// YAML manifest.
+func parseManifestResources(source string, manifest []byte) ([]model.KubernetesManifestResource, error) {
+	var resources []model.KubernetesManifestResource
+	for _, document := range manifestSeparator.Split(string(manifest), -1) {
+		if len(bytes.TrimSpace([]byte(document))) == 0 {
+			continue
+		}
+
+		var object manifestObject
+		err := yaml.Unmarshal([]byte(document), &object)
+		if err != nil {
+			return nil, err
+		}
+		if object.APIVersion == "" || object.Kind == "" {
+			continue
+		}
+
+		resources = append(resources, model.KubernetesManifestResource{
+			APIVersion: object.APIVersion,
+			Kind:       object.Kind,
+			Name:       object.Metadata.Name,
+			Source:     source,
+		})
+	}
+
+	return resources, nil
+}
+
+// IncompatibleUtilityCharts returns a description of every utility deployed
+// to the cluster whose chart declares it does not support the given
+// kubernetes version. Charts which can't be inspected or declare no
+// constraint are considered compatible.
+func IncompatibleUtilityCharts(cluster *model.Cluster, kubeconfigPath, version string, logger log.FieldLogger) ([]string, error) {
+	if version == "" || version == "latest" {
+		return nil, nil
+	}
+
+	registry, err := cluster.UtilityRegistry()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get utility registry")
+	}
+
+	helmClient, err := helm.New(kubeconfigPath, logger)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to create helm wrapper")
+	}
+	defer helmClient.Close()
+
+	var incompatible []string
+	for _, definition := range registry {
+		if !utilityHealthChecked(cluster, definition.Name) {
+			continue
+		}
+		chartVersion := cluster.ActualUtilityVersion(definition.Name).Version()
+
+		output, err := helmClient.RunCommandRaw("show", "chart", definition.ChartReference(), "--version", chartVersion)
+		if err != nil {
+			logger.WithError(err).Warnf("Unable to inspect chart %s version %s; assuming compatibility", definition.ChartReference(), chartVersion)
+			continue
+		}
+
+		var chart chartMetadata
+		err = yaml.Unmarshal(output, &chart)
+		if err != nil {
+			logger.WithError(err).Warnf("Unable to parse chart %s version %s; assuming compatibility", definition.ChartReference(), chartVersion)
+			continue
+		}
+
+		if !chartSupportsKubernetesVersion(chart.KubeVersion, version) {
+			incompatible = append(incompatible, fmt.Sprintf("%s chart %s requires kubernetes %s", definition.Name, chartVersion, chart.KubeVersion))
+		}
+	}
+
+	return incompatible, nil
+}
+
+var constraintOperatorSpacing = regexp.MustCompile(`(>=|<=|!=|>|<|=)\s+`)
+
+// chartSupportsKubernetesVersion returns true if the kubeVersion constraint
+// of a chart accepts the given kubernetes version. Constraints which can't
+// be parsed are accepted.
+func chartSupportsKubernetesVersion(constraint, version string) bool {
+	constraint = strings.TrimSpace(constraint)
+	if constraint == "" {
+		return true
+	}
+
+	parsedVersion, err := semver.ParseTolerant(version)
+	if err != nil {
+		return true
+	}
+
+	// Charts commonly use the Helm constraint syntax, which allows spaces
+	// after operators and comma separated ranges.
+	constraint = constraintOperatorSpacing.ReplaceAllString(constraint, "$1")
+	constraint = strings.ReplaceAll(constraint, ",", " ")
+	versionRange, err := semver.ParseRange(constraint)
+	if err != nil {
+		return true
+	}
+
+	// Prerelease suffixes such as -0 are used by charts to also accept
+	// provider builds like 1.29.0-eks; compare the plain version.
+	parsedVersion.Pre = nil
+	parsedVersion.Build = nil
+
+	return versionRange(parsedVersion)
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package utility
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseManifestResources(t *testing.T) {
+	manifest := []byte(`---
+# Source: chart/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: controller
+---
+# Source: chart/templates/empty.yaml
+---
+apiVersion: policy/v1beta1
+kind: PodDisruptionBudget
+metadata:
+  name: controller
+`)
+
+	resources, err := parseManifestResources("helm release nginx/nginx", manifest)
+	require.NoError(t, err)
+	assert.Equal(t, []model.KubernetesManifestResource{
+		{APIVersion: "apps/v1", Kind: "Deployment", Name: "controller", Source: "helm release nginx/nginx"},
+		{APIVersion: "policy/v1beta1", Kind: "PodDisruptionBudget", Name: "controller", Source: "helm release nginx/nginx"},
+	}, resources)
+
+	_, err = parseManifestResources("invalid", []byte("kind: [unterminated"))
+	assert.Error(t, err)
+}
+
+func TestChartSupportsKubernetesVersion(t *testing.T) {
+	for _, tc := range []struct {
+		constraint string
+		version    string
+		expected   bool
+	}{
+		{"", "1.29.4", true},
+		{">=1.19.0-0", "1.29.4", true},
+		{">= 1.19.0-0", "1.18.2", false},
+		{">=1.21.0-0 <1.29.0-0", "1.29.0", false},
+		{">=1.21.0-0, <1.30.0-0", "1.29.0", true},
+		{"^1.22.0", "1.29.0", true},
+		{">=1.21.0", "invalid", true},
+	} {
+		t.Run(tc.constraint+" "+tc.version, func(t *testing.T) {
+			assert.Equal(t, tc.expected, chartSupportsKubernetesVersion(tc.constraint, tc.version))
+		})
+	}
+}
@@ -53,6 +53,8 @@ type Utility interface {
 // inside of the cluster
 type utilityGroup struct {
 	utilities          []Utility
+	removedUtilities   []Utility
+	helmRepos          map[string]string
 	logger             log.FieldLogger
 	cluster            *model.Cluster
 	awsClient          aws.AWS
@@ -60,18 +62,34 @@ type utilityGroup struct {
 	allowCIDRRangeList []string
 }
 
-// List of repos to add during helm setup
-var helmRepos = map[string]string{
-	"chartmuseum":          "https://chartmuseum.internal.core.cloud.mattermost.com",
-	"ingress-nginx":        "https://kubernetes.github.io/ingress-nginx",
-	"prometheus-community": "https://prometheus-community.github.io/helm-charts",
-	"bitnami":              "https://charts.bitnami.com/bitnami",
-	"fluent":               "https://fluent.github.io/helm-charts",
-	"grafana":              "https://grafana.github.io/helm-charts",
-	"deliveryhero":         "https://charts.deliveryhero.io/",
-	"metrics-server":       "https://kubernetes-sigs.github.io/metrics-server/",
-	"vmware-tanzu":         "https://vmware-tanzu.github.io/helm-charts/",
-	"mattermost":           "https://helm.mattermost.com",
+// builtinUtilityHandle creates the handle of a builtin utility, taking its
+// chart, release and namespace from the registry definition.
+type builtinUtilityHandle func(definition *model.UtilityDefinition, cluster *model.Cluster, kubeconfigPath string, allowCIDRRangeList []string, awsClient aws.AWS, logger log.FieldLogger) (Utility, error)
+
+// withoutCIDRRanges adapts handle constructors of utilities which do not
+// restrict access by CIDR ranges.
+func withoutCIDRRanges(newHandle func(*model.UtilityDefinition, *model.Cluster, string, aws.AWS, log.FieldLogger) (Utility, error)) builtinUtilityHandle {
+	return func(definition *model.UtilityDefinition, cluster *model.Cluster, kubeconfigPath string, _ []string, awsClient aws.AWS, logger log.FieldLogger) (Utility, error) {
+		return newHandle(definition, cluster, kubeconfigPath, awsClient, logger)
+	}
+}
+
+// builtinUtilityHandles holds the handle constructors of the builtin
+// utilities of the registry, keyed by utility name.
+var builtinUtilityHandles = map[string]builtinUtilityHandle{
+	model.PgbouncerCanonicalName:           withoutCIDRRanges(newPgbouncerOrUnmanagedHandle),
+	model.NginxCanonicalName:               withoutCIDRRanges(newNginxOrUnmanagedHandle),
+	model.NginxInternalCanonicalName:       withoutCIDRRanges(newNginxInternalOrUnmanagedHandle),
+	model.PrometheusOperatorCanonicalName:  newPrometheusOperatorOrUnmanagedHandle,
+	model.ThanosCanonicalName:              newThanosOrUnmanagedHandle,
+	model.FluentbitCanonicalName:           withoutCIDRRanges(newFluentbitOrUnmanagedHandle),
+	model.TeleportCanonicalName:            withoutCIDRRanges(newTeleportOrUnmanagedHandle),
+	model.PromtailCanonicalName:            withoutCIDRRanges(newPromtailOrUnmanagedHandle),
+	model.NodeProblemDetectorCanonicalName: withoutCIDRRanges(newNodeProblemDetectorOrUnmanagedHandle),
+	model.RtcdCanonicalName:                withoutCIDRRanges(newRtcdOrUnmanagedHandle),
+	model.MetricsServerCanonicalName:       withoutCIDRRanges(newMetricsServerOrUnmanagedHandle),
+	model.VeleroCanonicalName:              withoutCIDRRanges(newVeleroOrUnmanagedHandle),
+	model.CloudproberCanonicalName:         withoutCIDRRanges(newCloudproberOrUnmanagedHandle),
 }
 
 func NewUtilityGroupHandle(
@@ -83,89 +101,43 @@ func NewUtilityGroupHandle(
 ) (*utilityGroup, error) {
 	logger := parentLogger.WithField("utility-group", "create-handle")
 
-	pgbouncer, err := newPgbouncerOrUnmanagedHandle(cluster, kubeconfigPath, awsClient, logger)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to get handle for Pgbouncer")
-	}
-
-	nginx, err := newNginxOrUnmanagedHandle(cluster, kubeconfigPath, awsClient, logger)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to get handle for NGINX")
-	}
-
-	nginxInternal, err := newNginxInternalOrUnmanagedHandle(cluster, kubeconfigPath, awsClient, logger)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to get handle for NGINX INTERNAL")
-	}
-
-	prometheusOperator, err := newPrometheusOperatorOrUnmanagedHandle(cluster, kubeconfigPath, allowCIDRRangeList, awsClient, logger)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to get handle for Prometheus Operator")
-	}
-
-	thanos, err := newThanosOrUnmanagedHandle(cluster, kubeconfigPath, allowCIDRRangeList, awsClient, logger)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to get handle for Thanos")
-	}
-
-	fluentbit, err := newFluentbitOrUnmanagedHandle(cluster, kubeconfigPath, awsClient, logger)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to get handle for Fluentbit")
-	}
-
-	teleport, err := newTeleportOrUnmanagedHandle(cluster, kubeconfigPath, awsClient, logger)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to get handle for Teleport")
-	}
-
-	promtail, err := newPromtailOrUnmanagedHandle(cluster, kubeconfigPath, awsClient, logger)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to get handle for Pgbouncer")
-	}
-
-	rtcd, err := newRtcdOrUnmanagedHandle(cluster, kubeconfigPath, awsClient, logger)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to get handle for RTCD")
-	}
-
-	nodeProblemDetector, err := newNodeProblemDetectorOrUnmanagedHandle(cluster, kubeconfigPath, awsClient, logger)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to get handle for Node Problem Detector")
-	}
-
-	metricsServer, err := newMetricsServerOrUnmanagedHandle(cluster, kubeconfigPath, awsClient, logger)
+	// the registry is sorted in deployment order, so that utilities
+	// are deployed after the utilities they depend on
+	registry, err := cluster.UtilityRegistry()
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to get handle for Metrics Server")
+		return nil, errors.Wrap(err, "failed to get utility registry")
 	}
 
-	velero, err := newVeleroOrUnmanagedHandle(cluster, kubeconfigPath, awsClient, logger)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to get handle for Velero")
+	utilities := make([]Utility, 0, len(registry))
+	for _, definition := range registry {
+		var utility Utility
+		if newHandle, ok := builtinUtilityHandles[definition.Name]; ok {
+			utility, err = newHandle(definition, cluster, kubeconfigPath, allowCIDRRangeList, awsClient, logger)
+		} else {
+			utility, err = newRegisteredOrUnmanagedHandle(definition, cluster, kubeconfigPath, awsClient, logger)
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get handle for %s", definition.Name)
+		}
+		utilities = append(utilities, utility)
 	}
 
-	cloudprober, err := newCloudproberOrUnmanagedHandle(cluster, kubeconfigPath, awsClient, logger)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to get handle for cloudprober")
+	var removedUtilities []Utility
+	if cluster.UtilityMetadata != nil {
+		for _, definition := range cluster.UtilityMetadata.RemovedUtilities {
+			utility := newRegisteredHandle(definition, nil, nil, kubeconfigPath, logger)
+			err = utility.validate()
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to get handle for removed utility %s", definition.Name)
+			}
+			removedUtilities = append(removedUtilities, utility)
+		}
 	}
 
-	// the order of utilities here matters; the utilities are deployed
-	// in order to resolve dependencies between them
 	return &utilityGroup{
-		utilities: []Utility{
-			pgbouncer,
-			nginx,
-			nginxInternal,
-			prometheusOperator,
-			thanos,
-			fluentbit,
-			teleport,
-			promtail,
-			nodeProblemDetector,
-			rtcd,
-			metricsServer,
-			velero,
-			cloudprober,
-		},
+		utilities:          utilities,
+		removedUtilities:   removedUtilities,
+		helmRepos:          model.HelmRepos(registry),
 		logger:             logger,
 		cluster:            cluster,
 		awsClient:          awsClient,
@@ -205,7 +177,7 @@ func (group utilityGroup) ProvisionUtilityGroup() error {
 	logger.Info("Ensuring all Helm repos are added")
 
 	logger.Info("Adding new Helm repos.")
-	for repoName, repoURL := range helmRepos {
+	for repoName, repoURL := range group.helmRepos {
 		logger.Infof("Adding helm repo %s", repoName)
 		err = helmClient.RepoAdd(repoName, repoURL)
 		if err != nil {
@@ -219,6 +191,16 @@ func (group utilityGroup) ProvisionUtilityGroup() error {
 		return errors.Wrap(err, "failed to ensure helm repos are updated")
 	}
 
+	for _, utility := range group.removedUtilities {
+		logger.Infof("Uninstalling removed utility %s", utility.Name())
+
+		err = utility.Destroy()
+		if err != nil {
+			return errors.Wrapf(err, "failed to uninstall removed utility %s", utility.Name())
+		}
+		group.cluster.SetUtilityUninstalled(utility.Name())
+	}
+
 	for _, utility := range group.utilities {
 		logger.Infof("Provisioning utility %s\n", utility.Name())
 
@@ -0,0 +1,37 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package utility
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-cloud/internal/provisioner/prometheus"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuiltinUtilityHandles(t *testing.T) {
+	t.Run("every builtin utility has a handle", func(t *testing.T) {
+		require.Len(t, builtinUtilityHandles, len(model.BuiltinUtilities))
+		for _, definition := range model.BuiltinUtilities {
+			assert.Contains(t, builtinUtilityHandles, definition.Name)
+		}
+	})
+
+	t.Run("namespace constants match the registry", func(t *testing.T) {
+		for name, namespace := range map[string]string{
+			model.NginxCanonicalName:              NamespaceNginx,
+			model.NginxInternalCanonicalName:      NamespaceNginxInternal,
+			model.PgbouncerCanonicalName:          NamespacePgbouncer,
+			model.PrometheusOperatorCanonicalName: prometheus.Namespace,
+			model.ThanosCanonicalName:             prometheus.Namespace,
+		} {
+			definition := model.BuiltinUtility(name)
+			require.NotNil(t, definition, name)
+			assert.Equal(t, namespace, definition.Namespace, name)
+		}
+	})
+}
@@ -0,0 +1,140 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package utility
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mattermost/mattermost-cloud/k8s"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// helmReleaseStatusDeployed is the status of a healthy Helm release.
+const helmReleaseStatusDeployed = "deployed"
+
+// utilityWorkloadSelector returns the label selector matching the workloads
+// deployed by the Helm release of the utility.
+func utilityWorkloadSelector(definition *model.UtilityDefinition) string {
+	return fmt.Sprintf("app.kubernetes.io/instance=%s", definition.Release)
+}
+
+// CheckUtilityHealth evaluates the health of the utilities managed by the
+// provisioner on the cluster from the status of their Helm release and
+// the readiness of their workloads.
+func CheckUtilityHealth(cluster *model.Cluster, kubeconfigPath string, logger log.FieldLogger) ([]*model.ClusterUtilityHealth, error) {
+	registry, err := cluster.UtilityRegistry()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get utility registry")
+	}
+
+	k8sClient, err := k8s.NewFromFile(kubeconfigPath, logger)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create k8s client")
+	}
+
+	releaseList, err := newHelmDeployment("", "", "", kubeconfigPath, nil, defaultHelmDeploymentSetArgument, logger).List()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list helm releases")
+	}
+	releases := map[string]helmReleaseJSON{}
+	for _, release := range releaseList.asSlice() {
+		releases[release.Namespace+"/"+release.Name] = release
+	}
+
+	var health []*model.ClusterUtilityHealth
+	for _, definition := range registry {
+		if !utilityHealthChecked(cluster, definition.Name) {
+			continue
+		}
+
+		workloads, err := k8sClient.GetWorkloadReadiness(definition.Namespace, utilityWorkloadSelector(definition))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get workloads of utility %s", definition.Name)
+		}
+
+		var release *helmReleaseJSON
+		if r, ok := releases[definition.Namespace+"/"+definition.Release]; ok {
+			release = &r
+		}
+		health = append(health, evaluateUtilityHealth(definition, release, workloads))
+	}
+
+	return health, nil
+}
+
+// RestartUtility restarts the workloads of the utility on the cluster.
+func RestartUtility(cluster *model.Cluster, utility, kubeconfigPath string, logger log.FieldLogger) error {
+	registry, err := cluster.UtilityRegistry()
+	if err != nil {
+		return errors.Wrap(err, "failed to get utility registry")
+	}
+
+	for _, definition := range registry {
+		if definition.Name != utility {
+			continue
+		}
+
+		k8sClient, err := k8s.NewFromFile(kubeconfigPath, logger)
+		if err != nil {
+			return errors.Wrap(err, "failed to create k8s client")
+		}
+
+		return k8sClient.RestartWorkloads(definition.Namespace, utilityWorkloadSelector(definition))
+	}
+
+	return model.ErrUtilityNotFound
+}
+
+// utilityHealthChecked returns true if the utility is deployed and managed
+// by the provisioner on the cluster.
+func utilityHealthChecked(cluster *model.Cluster, utility string) bool {
+	actualVersion := cluster.ActualUtilityVersion(utility)
+	if actualVersion == nil || actualVersion.Version() == "" {
+		return false
+	}
+	return !model.UtilityIsUnmanaged(cluster.DesiredUtilityVersion(utility), actualVersion)
+}
+
+// evaluateUtilityHealth builds the health of the utility from its Helm
+// release, or nil if the release is missing, and its workloads.
+func evaluateUtilityHealth(definition *model.UtilityDefinition, release *helmReleaseJSON, workloads []k8s.WorkloadReadiness) *model.ClusterUtilityHealth {
+	health := &model.ClusterUtilityHealth{
+		Utility:   definition.Name,
+		Healthy:   true,
+		CheckedAt: model.GetMillis(),
+	}
+
+	var problems []string
+	if release == nil {
+		health.Healthy = false
+		problems = append(problems, fmt.Sprintf("helm release %s not found", definition.Release))
+	} else {
+		health.HelmStatus = release.Status
+		if release.Status != helmReleaseStatusDeployed {
+			health.Healthy = false
+			problems = append(problems, fmt.Sprintf("helm release %s is %s", definition.Release, release.Status))
+		}
+	}
+
+	for _, workload := range workloads {
+		health.Workloads = append(health.Workloads, &model.UtilityWorkloadHealth{
+			Kind:    workload.Kind,
+			Name:    workload.Name,
+			Desired: workload.Desired,
+			Ready:   workload.Ready,
+		})
+		if !workload.IsReady() {
+			health.Healthy = false
+			problems = append(problems, fmt.Sprintf("%s %s has %d/%d ready pods", workload.Kind, workload.Name, workload.Ready, workload.Desired))
+		}
+	}
+
+	health.Message = strings.Join(problems, "; ")
+
+	return health
+}
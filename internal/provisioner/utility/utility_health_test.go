@@ -0,0 +1,61 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package utility
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-cloud/k8s"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvaluateUtilityHealth(t *testing.T) {
+	definition := model.BuiltinUtility(model.NginxCanonicalName)
+	deployed := &helmReleaseJSON{Name: "nginx", Namespace: "nginx", Status: "deployed"}
+	readyWorkloads := []k8s.WorkloadReadiness{
+		{Kind: k8s.WorkloadKindDeployment, Name: "nginx-controller", Desired: 2, Ready: 2},
+	}
+
+	t.Run("healthy", func(t *testing.T) {
+		health := evaluateUtilityHealth(definition, deployed, readyWorkloads)
+		assert.True(t, health.Healthy)
+		assert.Equal(t, model.NginxCanonicalName, health.Utility)
+		assert.Equal(t, "deployed", health.HelmStatus)
+		assert.Empty(t, health.Message)
+		assert.Equal(t, []*model.UtilityWorkloadHealth{
+			{Kind: k8s.WorkloadKindDeployment, Name: "nginx-controller", Desired: 2, Ready: 2},
+		}, health.Workloads)
+	})
+
+	t.Run("missing release", func(t *testing.T) {
+		health := evaluateUtilityHealth(definition, nil, readyWorkloads)
+		assert.False(t, health.Healthy)
+		assert.Empty(t, health.HelmStatus)
+		assert.Equal(t, "helm release nginx not found", health.Message)
+	})
+
+	t.Run("failed release and unready workload", func(t *testing.T) {
+		failed := &helmReleaseJSON{Name: "nginx", Namespace: "nginx", Status: "failed"}
+		workloads := []k8s.WorkloadReadiness{
+			{Kind: k8s.WorkloadKindDaemonSet, Name: "nginx-node", Desired: 3, Ready: 1},
+		}
+
+		health := evaluateUtilityHealth(definition, failed, workloads)
+		assert.False(t, health.Healthy)
+		assert.Equal(t, "failed", health.HelmStatus)
+		assert.Equal(t, "helm release nginx is failed; DaemonSet nginx-node has 1/3 ready pods", health.Message)
+	})
+}
+
+func TestUtilityHealthChecked(t *testing.T) {
+	cluster := &model.Cluster{}
+	cluster.SetUtilityActualVersion(model.NginxCanonicalName, &model.HelmUtilityVersion{Chart: "4.0.0", ValuesPath: "values.yaml"})
+	cluster.SetUtilityActualVersion(model.FluentbitCanonicalName, &model.HelmUtilityVersion{Chart: model.UnmanagedUtilityVersion})
+
+	assert.True(t, utilityHealthChecked(cluster, model.NginxCanonicalName))
+	assert.False(t, utilityHealthChecked(cluster, model.FluentbitCanonicalName))
+	assert.False(t, utilityHealthChecked(cluster, model.PgbouncerCanonicalName))
+}
@@ -15,6 +15,7 @@ import (
 )
 
 type velero struct {
+	definition     *model.UtilityDefinition
 	awsClient      aws.AWS
 	cluster        *model.Cluster
 	kubeconfigPath string
@@ -23,7 +24,7 @@ type velero struct {
 	desiredVersion *model.HelmUtilityVersion
 }
 
-func newVeleroOrUnmanagedHandle(cluster *model.Cluster, kubeconfigPath string, awsClient aws.AWS, logger log.FieldLogger) (Utility, error) {
+func newVeleroOrUnmanagedHandle(definition *model.UtilityDefinition, cluster *model.Cluster, kubeconfigPath string, awsClient aws.AWS, logger log.FieldLogger) (Utility, error) {
 	desired := cluster.DesiredUtilityVersion(model.VeleroCanonicalName)
 	actual := cluster.ActualUtilityVersion(model.VeleroCanonicalName)
 
@@ -31,7 +32,7 @@ func newVeleroOrUnmanagedHandle(cluster *model.Cluster, kubeconfigPath string, a
 		return newUnmanagedHandle(model.VeleroCanonicalName, kubeconfigPath, []string{}, cluster, awsClient, logger), nil
 	}
 
-	velero := newVeleroHandle(desired, cluster, kubeconfigPath, awsClient, logger)
+	velero := newVeleroHandle(definition, desired, cluster, kubeconfigPath, awsClient, logger)
 	err := velero.validate()
 	if err != nil {
 		return nil, errors.Wrap(err, "velero utility config is invalid")
@@ -40,8 +41,9 @@ func newVeleroOrUnmanagedHandle(cluster *model.Cluster, kubeconfigPath string, a
 	return velero, nil
 }
 
-func newVeleroHandle(desiredVersion *model.HelmUtilityVersion, cluster *model.Cluster, kubeconfigPath string, awsClient aws.AWS, logger log.FieldLogger) *velero {
+func newVeleroHandle(definition *model.UtilityDefinition, desiredVersion *model.HelmUtilityVersion, cluster *model.Cluster, kubeconfigPath string, awsClient aws.AWS, logger log.FieldLogger) *velero {
 	return &velero{
+		definition:     definition,
 		awsClient:      awsClient,
 		cluster:        cluster,
 		kubeconfigPath: kubeconfigPath,
@@ -103,9 +105,9 @@ func (v *velero) newHelmDeployment(logger log.FieldLogger) *helmDeployment {
 	helmValueArguments := fmt.Sprintf("configuration.backupStorageLocation[0].prefix=%s", v.cluster.ID)
 
 	return newHelmDeployment(
-		"vmware-tanzu/velero",
-		"velero",
-		"velero",
+		v.definition.ChartReference(),
+		v.definition.Release,
+		v.definition.Namespace,
 		v.kubeconfigPath,
 		v.desiredVersion,
 		helmValueArguments,
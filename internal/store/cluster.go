@@ -19,7 +19,7 @@ var clusterSelect sq.SelectBuilder
 func init() {
 	clusterSelect = sq.
 		Select("Cluster.ID", "Name", "Provider", "Provisioner", "ProviderMetadataRaw", "ProvisionerMetadataRaw",
-			"UtilityMetadataRaw", "PgBouncerConfig", "State", "AllowInstallations", "CreateAt", "DeleteAt",
+			"UtilityMetadataRaw", "PgBouncerConfig", "TemplateID", "TemplateVersion", "State", "AllowInstallations", "CreateAt", "DeleteAt",
 			"APISecurityLock", "SchedulingLockAcquiredBy", "SchedulingLockAcquiredAt", "LockAcquiredBy", "LockAcquiredAt").
 		From("Cluster")
 }
@@ -181,6 +181,10 @@ func (sqlStore *SQLStore) GetClusters(filter *model.ClusterFilter) ([]*model.Clu
 func (sqlStore *SQLStore) applyClustersFilter(builder sq.SelectBuilder, filter *model.ClusterFilter) sq.SelectBuilder {
 	builder = applyPagingFilter(builder, filter.Paging)
 
+	if len(filter.TemplateID) > 0 {
+		builder = builder.Where("TemplateID = ?", filter.TemplateID)
+	}
+
 	if filter.Annotations != nil && len(filter.Annotations.MatchAllIDs) > 0 {
 		builder = builder.Join(fmt.Sprintf("%s ON Cluster.ID=%s.ClusterID", clusterAnnotationTable, clusterAnnotationTable)).
 			// this where statement resolves to: ... WHERE ClusterAnnotation.AnnotationID IN ([ALL PROVIDED IDS])
@@ -264,6 +268,8 @@ func (sqlStore *SQLStore) createCluster(execer execer, cluster *model.Cluster) e
 			"ProvisionerMetadataRaw":   rawMetadata.ProvisionerMetadataRaw,
 			"UtilityMetadataRaw":       rawMetadata.UtilityMetadataRaw,
 			"PgBouncerConfig":          cluster.PgBouncerConfig,
+			"TemplateID":               cluster.TemplateID,
+			"TemplateVersion":          cluster.TemplateVersion,
 			"AllowInstallations":       cluster.AllowInstallations,
 			"CreateAt":                 cluster.CreateAt,
 			"DeleteAt":                 0,
@@ -298,6 +304,7 @@ func (sqlStore *SQLStore) UpdateCluster(cluster *model.Cluster) error {
 			"ProvisionerMetadataRaw": rawMetadata.ProvisionerMetadataRaw,
 			"UtilityMetadataRaw":     rawMetadata.UtilityMetadataRaw,
 			"PgBouncerConfig":        cluster.PgBouncerConfig,
+			"TemplateVersion":        cluster.TemplateVersion,
 			"AllowInstallations":     cluster.AllowInstallations,
 		}).
 		Where("ID = ?", cluster.ID),
@@ -0,0 +1,132 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package store
+
+import (
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+)
+
+const (
+	clusterTemplateTable = "ClusterTemplate"
+)
+
+var clusterTemplateSelect sq.SelectBuilder
+
+func init() {
+	clusterTemplateSelect = sq.
+		Select(
+			"ID",
+			"Name",
+			"Description",
+			"Version",
+			"Spec",
+			"CreateAt",
+			"UpdateAt",
+			"DeleteAt",
+		).
+		From(clusterTemplateTable)
+}
+
+// CreateClusterTemplate records the supplied cluster template to the
+// datastore, assigning it a unique ID.
+func (sqlStore *SQLStore) CreateClusterTemplate(template *model.ClusterTemplate) error {
+	template.ID = model.NewID()
+	template.Version = 1
+	template.CreateAt = model.GetMillis()
+	template.UpdateAt = template.CreateAt
+
+	_, err := sqlStore.execBuilder(sqlStore.db, sq.
+		Insert(clusterTemplateTable).
+		SetMap(map[string]interface{}{
+			"ID":          template.ID,
+			"Name":        template.Name,
+			"Description": template.Description,
+			"Version":     template.Version,
+			"Spec":        template.Spec,
+			"CreateAt":    template.CreateAt,
+			"UpdateAt":    template.UpdateAt,
+			"DeleteAt":    0,
+		}),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to create cluster template")
+	}
+
+	return nil
+}
+
+// GetClusterTemplateByName fetches the cluster template with the given name
+// that is not deleted.
+func (sqlStore *SQLStore) GetClusterTemplateByName(name string) (*model.ClusterTemplate, error) {
+	var template model.ClusterTemplate
+	builder := clusterTemplateSelect.
+		Where("Name = ?", name).
+		Where("DeleteAt = 0")
+	err := sqlStore.getBuilder(sqlStore.db, &template, builder)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "failed to get cluster template by name")
+	}
+
+	return &template, nil
+}
+
+// GetClusterTemplates fetches the given page of cluster templates. The first
+// page is 0.
+func (sqlStore *SQLStore) GetClusterTemplates(filter *model.ClusterTemplateFilter) ([]*model.ClusterTemplate, error) {
+	builder := clusterTemplateSelect.
+		OrderBy("Name ASC")
+	builder = applyPagingFilter(builder, filter.Paging)
+
+	templates := []*model.ClusterTemplate{}
+	err := sqlStore.selectBuilder(sqlStore.db, &templates, builder)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query for cluster templates")
+	}
+
+	return templates, nil
+}
+
+// UpdateClusterTemplate updates the given cluster template in the database.
+func (sqlStore *SQLStore) UpdateClusterTemplate(template *model.ClusterTemplate) error {
+	template.UpdateAt = model.GetMillis()
+
+	_, err := sqlStore.execBuilder(sqlStore.db, sq.
+		Update(clusterTemplateTable).
+		SetMap(map[string]interface{}{
+			"Description": template.Description,
+			"Version":     template.Version,
+			"Spec":        template.Spec,
+			"UpdateAt":    template.UpdateAt,
+		}).
+		Where("ID = ?", template.ID),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to update cluster template")
+	}
+
+	return nil
+}
+
+// DeleteClusterTemplate marks the given cluster template as deleted, but
+// does not remove the record from the database.
+func (sqlStore *SQLStore) DeleteClusterTemplate(id string) error {
+	_, err := sqlStore.execBuilder(sqlStore.db, sq.
+		Update(clusterTemplateTable).
+		Set("DeleteAt", model.GetMillis()).
+		Where("ID = ?", id).
+		Where("DeleteAt = 0"),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to mark cluster template as deleted")
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package store
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClusterTemplates(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := MakeTestSQLStore(t, logger)
+	defer CloseConnection(t, sqlStore)
+
+	template1 := &model.ClusterTemplate{
+		Name:        "standard",
+		Description: "standard clusters",
+		Spec: &model.ClusterTemplateSpec{
+			Provisioner:        model.ProvisionerKops,
+			NodeInstanceType:   "m6i.large",
+			NodeMinCount:       3,
+			AllowInstallations: true,
+			PgBouncerConfig:    &model.PgBouncerConfig{MinPoolSize: 5, DefaultPoolSize: 10},
+		},
+	}
+	template2 := &model.ClusterTemplate{
+		Name: "large",
+		Spec: &model.ClusterTemplateSpec{NodeInstanceType: "m6i.xlarge"},
+	}
+
+	err := sqlStore.CreateClusterTemplate(template1)
+	require.NoError(t, err)
+	assert.NotEmpty(t, template1.ID)
+	assert.Equal(t, int64(1), template1.Version)
+
+	err = sqlStore.CreateClusterTemplate(template2)
+	require.NoError(t, err)
+
+	t.Run("get by name", func(t *testing.T) {
+		fetchedTemplate, err := sqlStore.GetClusterTemplateByName(template1.Name)
+		require.NoError(t, err)
+		assert.Equal(t, template1, fetchedTemplate)
+	})
+
+	t.Run("get unknown template", func(t *testing.T) {
+		fetchedTemplate, err := sqlStore.GetClusterTemplateByName("unknown")
+		require.NoError(t, err)
+		assert.Nil(t, fetchedTemplate)
+	})
+
+	t.Run("get templates", func(t *testing.T) {
+		templates, err := sqlStore.GetClusterTemplates(&model.ClusterTemplateFilter{Paging: model.AllPagesNotDeleted()})
+		require.NoError(t, err)
+		assert.Equal(t, []*model.ClusterTemplate{template2, template1}, templates)
+
+		templates, err = sqlStore.GetClusterTemplates(&model.ClusterTemplateFilter{Paging: model.Paging{Page: 0, PerPage: 1}})
+		require.NoError(t, err)
+		assert.Equal(t, []*model.ClusterTemplate{template2}, templates)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		template1.Description = "updated"
+		template1.Version++
+		template1.Spec.NodeMinCount = 5
+
+		err := sqlStore.UpdateClusterTemplate(template1)
+		require.NoError(t, err)
+
+		fetchedTemplate, err := sqlStore.GetClusterTemplateByName(template1.Name)
+		require.NoError(t, err)
+		assert.Equal(t, template1, fetchedTemplate)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		err := sqlStore.DeleteClusterTemplate(template2.ID)
+		require.NoError(t, err)
+
+		fetchedTemplate, err := sqlStore.GetClusterTemplateByName(template2.Name)
+		require.NoError(t, err)
+		assert.Nil(t, fetchedTemplate)
+
+		templates, err := sqlStore.GetClusterTemplates(&model.ClusterTemplateFilter{Paging: model.AllPagesNotDeleted()})
+		require.NoError(t, err)
+		assert.Equal(t, []*model.ClusterTemplate{template1}, templates)
+
+		templates, err = sqlStore.GetClusterTemplates(&model.ClusterTemplateFilter{Paging: model.AllPagesWithDeleted()})
+		require.NoError(t, err)
+		assert.Len(t, templates, 2)
+	})
+
+	t.Run("recreate deleted template name", func(t *testing.T) {
+		recreated := &model.ClusterTemplate{Name: template2.Name, Spec: &model.ClusterTemplateSpec{}}
+		err := sqlStore.CreateClusterTemplate(recreated)
+		require.NoError(t, err)
+
+		fetchedTemplate, err := sqlStore.GetClusterTemplateByName(template2.Name)
+		require.NoError(t, err)
+		assert.Equal(t, recreated.ID, fetchedTemplate.ID)
+	})
+}
@@ -0,0 +1,150 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package store
+
+import (
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+)
+
+const (
+	clusterUpgradeTable = "ClusterUpgrade"
+)
+
+var clusterUpgradeSelect sq.SelectBuilder
+
+func init() {
+	clusterUpgradeSelect = sq.
+		Select(
+			"ID",
+			"ClusterID",
+			"Version",
+			"State",
+			"Phase",
+			"SkipPreflight",
+			"Preflight",
+			"NodeGroups",
+			"Verification",
+			"Message",
+			"CreateAt",
+			"UpdateAt",
+			"CompleteAt",
+		).
+		From(clusterUpgradeTable)
+}
+
+// CreateClusterUpgrade records the supplied cluster upgrade to the
+// datastore, assigning it a unique ID.
+func (sqlStore *SQLStore) CreateClusterUpgrade(upgrade *model.ClusterUpgrade) error {
+	upgrade.ID = model.NewID()
+	upgrade.CreateAt = model.GetMillis()
+	upgrade.UpdateAt = upgrade.CreateAt
+
+	_, err := sqlStore.execBuilder(sqlStore.db, sq.
+		Insert(clusterUpgradeTable).
+		SetMap(map[string]interface{}{
+			"ID":            upgrade.ID,
+			"ClusterID":     upgrade.ClusterID,
+			"Version":       upgrade.Version,
+			"State":         upgrade.State,
+			"Phase":         upgrade.Phase,
+			"SkipPreflight": upgrade.SkipPreflight,
+			"Preflight":     upgrade.Preflight,
+			"NodeGroups":    upgrade.NodeGroups,
+			"Verification":  upgrade.Verification,
+			"Message":       upgrade.Message,
+			"CreateAt":      upgrade.CreateAt,
+			"UpdateAt":      upgrade.UpdateAt,
+			"CompleteAt":    upgrade.CompleteAt,
+		}),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to create cluster upgrade")
+	}
+
+	return nil
+}
+
+// GetClusterUpgrade fetches the given cluster upgrade.
+func (sqlStore *SQLStore) GetClusterUpgrade(id string) (*model.ClusterUpgrade, error) {
+	var upgrade model.ClusterUpgrade
+	err := sqlStore.getBuilder(sqlStore.db, &upgrade, clusterUpgradeSelect.Where("ID = ?", id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "failed to get cluster upgrade by id")
+	}
+
+	return &upgrade, nil
+}
+
+// GetLatestClusterUpgrade fetches the most recent upgrade of the given
+// cluster, or nil if the cluster was never upgraded.
+func (sqlStore *SQLStore) GetLatestClusterUpgrade(clusterID string) (*model.ClusterUpgrade, error) {
+	var upgrade model.ClusterUpgrade
+	builder := clusterUpgradeSelect.
+		Where("ClusterID = ?", clusterID).
+		OrderBy("CreateAt DESC").
+		Limit(1)
+	err := sqlStore.getBuilder(sqlStore.db, &upgrade, builder)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "failed to get latest cluster upgrade")
+	}
+
+	return &upgrade, nil
+}
+
+// GetClusterUpgrades fetches the given page of cluster upgrades. The first
+// page is 0.
+func (sqlStore *SQLStore) GetClusterUpgrades(filter *model.ClusterUpgradeFilter) ([]*model.ClusterUpgrade, error) {
+	builder := clusterUpgradeSelect.
+		OrderBy("CreateAt DESC")
+	builder = applyPagingFilter(builder, filter.Paging)
+
+	if len(filter.ClusterID) > 0 {
+		builder = builder.Where("ClusterID = ?", filter.ClusterID)
+	}
+	if len(filter.States) > 0 {
+		builder = builder.Where(sq.Eq{"State": filter.States})
+	}
+
+	upgrades := []*model.ClusterUpgrade{}
+	err := sqlStore.selectBuilder(sqlStore.db, &upgrades, builder)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query for cluster upgrades")
+	}
+
+	return upgrades, nil
+}
+
+// UpdateClusterUpgrade updates the progress of the given cluster upgrade.
+func (sqlStore *SQLStore) UpdateClusterUpgrade(upgrade *model.ClusterUpgrade) error {
+	upgrade.UpdateAt = model.GetMillis()
+
+	_, err := sqlStore.execBuilder(sqlStore.db, sq.
+		Update(clusterUpgradeTable).
+		SetMap(map[string]interface{}{
+			"State":        upgrade.State,
+			"Phase":        upgrade.Phase,
+			"Preflight":    upgrade.Preflight,
+			"NodeGroups":   upgrade.NodeGroups,
+			"Verification": upgrade.Verification,
+			"Message":      upgrade.Message,
+			"UpdateAt":     upgrade.UpdateAt,
+			"CompleteAt":   upgrade.CompleteAt,
+		}).
+		Where("ID = ?", upgrade.ID),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to update cluster upgrade")
+	}
+
+	return nil
+}
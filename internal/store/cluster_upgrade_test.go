@@ -0,0 +1,126 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClusterUpgrade(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := MakeTestSQLStore(t, logger)
+	defer CloseConnection(t, sqlStore)
+
+	upgrade := model.NewClusterUpgrade("cluster1", "1.28", false)
+
+	err := sqlStore.CreateClusterUpgrade(upgrade)
+	require.NoError(t, err)
+	assert.NotEmpty(t, upgrade.ID)
+	assert.NotZero(t, upgrade.CreateAt)
+
+	fetchedUpgrade, err := sqlStore.GetClusterUpgrade(upgrade.ID)
+	require.NoError(t, err)
+	assert.Equal(t, upgrade, fetchedUpgrade)
+
+	t.Run("update", func(t *testing.T) {
+		upgrade.Phase = model.ClusterUpgradePhaseNodeGroups
+		upgrade.Preflight = model.ClusterUpgradeChecks{
+			{Name: "deprecated-apis", Passed: true, Blocking: true},
+		}
+		upgrade.SetNodeGroups([]string{"ng1", "ng2"})
+		upgrade.NodeGroups[0].State = model.ClusterUpgradeNodeGroupFailed
+		upgrade.NodeGroups[0].Message = "timed out"
+		upgrade.Fail("nodegroup ng1 failed to roll")
+
+		err = sqlStore.UpdateClusterUpgrade(upgrade)
+		require.NoError(t, err)
+
+		fetchedUpgrade, err = sqlStore.GetClusterUpgrade(upgrade.ID)
+		require.NoError(t, err)
+		assert.Equal(t, upgrade, fetchedUpgrade)
+	})
+
+	t.Run("unknown upgrade", func(t *testing.T) {
+		fetchedUpgrade, err = sqlStore.GetClusterUpgrade("unknown")
+		require.NoError(t, err)
+		assert.Nil(t, fetchedUpgrade)
+	})
+}
+
+func TestGetClusterUpgrades(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := MakeTestSQLStore(t, logger)
+	defer CloseConnection(t, sqlStore)
+
+	upgrades := []*model.ClusterUpgrade{
+		{ClusterID: "cluster1", State: model.ClusterUpgradeStateSucceeded},
+		{ClusterID: "cluster1", State: model.ClusterUpgradeStateFailed},
+		{ClusterID: "cluster2", State: model.ClusterUpgradeStateInProgress},
+	}
+	for _, upgrade := range upgrades {
+		err := sqlStore.CreateClusterUpgrade(upgrade)
+		require.NoError(t, err)
+		time.Sleep(1 * time.Millisecond)
+	}
+
+	for _, testCase := range []struct {
+		description string
+		filter      *model.ClusterUpgradeFilter
+		fetchedIDs  []string
+	}{
+		{
+			description: "fetch all",
+			filter:      &model.ClusterUpgradeFilter{Paging: model.AllPagesNotDeleted()},
+			fetchedIDs:  []string{upgrades[2].ID, upgrades[1].ID, upgrades[0].ID},
+		},
+		{
+			description: "fetch by cluster",
+			filter:      &model.ClusterUpgradeFilter{Paging: model.AllPagesNotDeleted(), ClusterID: "cluster1"},
+			fetchedIDs:  []string{upgrades[1].ID, upgrades[0].ID},
+		},
+		{
+			description: "fetch by cluster and state",
+			filter: &model.ClusterUpgradeFilter{
+				Paging:    model.AllPagesNotDeleted(),
+				ClusterID: "cluster1",
+				States:    []model.ClusterUpgradeState{model.ClusterUpgradeStateSucceeded},
+			},
+			fetchedIDs: []string{upgrades[0].ID},
+		},
+		{
+			description: "fetch page",
+			filter:      &model.ClusterUpgradeFilter{Paging: model.Paging{Page: 0, PerPage: 1, IncludeDeleted: false}},
+			fetchedIDs:  []string{upgrades[2].ID},
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			fetchedUpgrades, err := sqlStore.GetClusterUpgrades(testCase.filter)
+			require.NoError(t, err)
+
+			var fetchedIDs []string
+			for _, upgrade := range fetchedUpgrades {
+				fetchedIDs = append(fetchedIDs, upgrade.ID)
+			}
+			assert.Equal(t, testCase.fetchedIDs, fetchedIDs)
+		})
+	}
+
+	t.Run("latest upgrade", func(t *testing.T) {
+		latest, err := sqlStore.GetLatestClusterUpgrade("cluster1")
+		require.NoError(t, err)
+		require.NotNil(t, latest)
+		assert.Equal(t, upgrades[1].ID, latest.ID)
+
+		latest, err = sqlStore.GetLatestClusterUpgrade("unknown")
+		require.NoError(t, err)
+		assert.Nil(t, latest)
+	})
+}
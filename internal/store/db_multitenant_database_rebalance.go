@@ -0,0 +1,148 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package store
+
+import (
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+)
+
+const (
+	multitenantDatabaseRebalanceTable = "MultitenantDatabaseRebalance"
+)
+
+var multitenantDatabaseRebalanceSelect sq.SelectBuilder
+
+func init() {
+	multitenantDatabaseRebalanceSelect = sq.
+		Select(
+			"ID",
+			"VpcID",
+			"DatabaseType",
+			"State",
+			"MaxConcurrentMigrations",
+			"Moves",
+			"RequestAt",
+			"CompleteAt",
+			"DeleteAt",
+			"LockAcquiredBy",
+			"LockAcquiredAt",
+		).
+		From(multitenantDatabaseRebalanceTable)
+}
+
+// CreateMultitenantDatabaseRebalance records the supplied multitenant database
+// rebalance to the datastore, assigning it a unique ID.
+func (sqlStore *SQLStore) CreateMultitenantDatabaseRebalance(rebalance *model.MultitenantDatabaseRebalance) error {
+	rebalance.ID = model.NewID()
+	rebalance.RequestAt = model.GetMillis()
+
+	_, err := sqlStore.execBuilder(sqlStore.db, sq.
+		Insert(multitenantDatabaseRebalanceTable).
+		SetMap(map[string]interface{}{
+			"ID":                      rebalance.ID,
+			"VpcID":                   rebalance.VpcID,
+			"DatabaseType":            rebalance.DatabaseType,
+			"State":                   rebalance.State,
+			"MaxConcurrentMigrations": rebalance.MaxConcurrentMigrations,
+			"Moves":                   rebalance.Moves,
+			"RequestAt":               rebalance.RequestAt,
+			"CompleteAt":              rebalance.CompleteAt,
+			"DeleteAt":                0,
+			"LockAcquiredBy":          nil,
+			"LockAcquiredAt":          0,
+		}),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to create multitenant database rebalance")
+	}
+
+	return nil
+}
+
+// GetMultitenantDatabaseRebalance fetches the given multitenant database rebalance.
+func (sqlStore *SQLStore) GetMultitenantDatabaseRebalance(id string) (*model.MultitenantDatabaseRebalance, error) {
+	var rebalance model.MultitenantDatabaseRebalance
+	err := sqlStore.getBuilder(sqlStore.db, &rebalance, multitenantDatabaseRebalanceSelect.Where("ID = ?", id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "failed to get multitenant database rebalance by id")
+	}
+
+	return &rebalance, nil
+}
+
+// GetMultitenantDatabaseRebalances fetches the given page of multitenant
+// database rebalances. The first page is 0.
+func (sqlStore *SQLStore) GetMultitenantDatabaseRebalances(filter *model.MultitenantDatabaseRebalanceFilter) ([]*model.MultitenantDatabaseRebalance, error) {
+	builder := multitenantDatabaseRebalanceSelect.
+		OrderBy("RequestAt DESC")
+	builder = applyPagingFilter(builder, filter.Paging)
+
+	if len(filter.VpcID) > 0 {
+		builder = builder.Where(sq.Eq{"VpcID": filter.VpcID})
+	}
+	if len(filter.States) > 0 {
+		builder = builder.Where(sq.Eq{"State": filter.States})
+	}
+
+	return sqlStore.getMultitenantDatabaseRebalances(builder)
+}
+
+// GetUnlockedMultitenantDatabaseRebalancesPendingWork returns unlocked
+// multitenant database rebalances in a pending state.
+func (sqlStore *SQLStore) GetUnlockedMultitenantDatabaseRebalancesPendingWork() ([]*model.MultitenantDatabaseRebalance, error) {
+	builder := multitenantDatabaseRebalanceSelect.
+		Where(sq.Eq{
+			"State": model.AllMultitenantDatabaseRebalanceStatesPendingWork,
+		}).
+		Where("LockAcquiredAt = 0").
+		Where("DeleteAt = 0").
+		OrderBy("RequestAt ASC")
+
+	return sqlStore.getMultitenantDatabaseRebalances(builder)
+}
+
+func (sqlStore *SQLStore) getMultitenantDatabaseRebalances(builder builder) ([]*model.MultitenantDatabaseRebalance, error) {
+	rebalances := []*model.MultitenantDatabaseRebalance{}
+	err := sqlStore.selectBuilder(sqlStore.db, &rebalances, builder)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query for multitenant database rebalances")
+	}
+
+	return rebalances, nil
+}
+
+// UpdateMultitenantDatabaseRebalance updates the given multitenant database rebalance.
+func (sqlStore *SQLStore) UpdateMultitenantDatabaseRebalance(rebalance *model.MultitenantDatabaseRebalance) error {
+	_, err := sqlStore.execBuilder(sqlStore.db, sq.
+		Update(multitenantDatabaseRebalanceTable).
+		SetMap(map[string]interface{}{
+			"State":      rebalance.State,
+			"Moves":      rebalance.Moves,
+			"CompleteAt": rebalance.CompleteAt,
+		}).
+		Where("ID = ?", rebalance.ID),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to update multitenant database rebalance")
+	}
+
+	return nil
+}
+
+// LockMultitenantDatabaseRebalances marks the rebalances as locked for exclusive use by the caller.
+func (sqlStore *SQLStore) LockMultitenantDatabaseRebalances(ids []string, lockerID string) (bool, error) {
+	return sqlStore.lockRows(multitenantDatabaseRebalanceTable, ids, lockerID)
+}
+
+// UnlockMultitenantDatabaseRebalances releases locks previously acquired against a caller.
+func (sqlStore *SQLStore) UnlockMultitenantDatabaseRebalances(ids []string, lockerID string, force bool) (bool, error) {
+	return sqlStore.unlockRows(multitenantDatabaseRebalanceTable, ids, lockerID, force)
+}
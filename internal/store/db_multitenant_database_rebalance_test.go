@@ -0,0 +1,160 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultitenantDatabaseRebalance(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := MakeTestSQLStore(t, logger)
+	defer CloseConnection(t, sqlStore)
+
+	rebalance := &model.MultitenantDatabaseRebalance{
+		VpcID:                   "vpc1",
+		DatabaseType:            model.DatabaseEngineTypePostgres,
+		State:                   model.MultitenantDatabaseRebalanceStateRequested,
+		MaxConcurrentMigrations: 2,
+		Moves: model.MultitenantDatabaseRebalanceMoves{
+			{
+				InstallationID:        "installation1",
+				SourceDatabaseID:      "database1",
+				DestinationDatabaseID: "database2",
+				Weight:                1,
+				State:                 model.MultitenantDatabaseRebalanceMovePending,
+			},
+		},
+	}
+
+	err := sqlStore.CreateMultitenantDatabaseRebalance(rebalance)
+	require.NoError(t, err)
+	assert.NotEmpty(t, rebalance.ID)
+	assert.NotZero(t, rebalance.RequestAt)
+
+	fetchedRebalance, err := sqlStore.GetMultitenantDatabaseRebalance(rebalance.ID)
+	require.NoError(t, err)
+	assert.Equal(t, rebalance, fetchedRebalance)
+
+	t.Run("update", func(t *testing.T) {
+		rebalance.State = model.MultitenantDatabaseRebalanceStateInProgress
+		rebalance.Moves[0].State = model.MultitenantDatabaseRebalanceMoveInProgress
+		rebalance.Moves[0].DBMigrationID = "migration1"
+
+		err = sqlStore.UpdateMultitenantDatabaseRebalance(rebalance)
+		require.NoError(t, err)
+
+		fetchedRebalance, err = sqlStore.GetMultitenantDatabaseRebalance(rebalance.ID)
+		require.NoError(t, err)
+		assert.Equal(t, rebalance, fetchedRebalance)
+	})
+
+	t.Run("lock", func(t *testing.T) {
+		locked, err := sqlStore.LockMultitenantDatabaseRebalances([]string{rebalance.ID}, "locker")
+		require.NoError(t, err)
+		assert.True(t, locked)
+
+		pending, err := sqlStore.GetUnlockedMultitenantDatabaseRebalancesPendingWork()
+		require.NoError(t, err)
+		assert.Empty(t, pending)
+
+		unlocked, err := sqlStore.UnlockMultitenantDatabaseRebalances([]string{rebalance.ID}, "locker", false)
+		require.NoError(t, err)
+		assert.True(t, unlocked)
+
+		pending, err = sqlStore.GetUnlockedMultitenantDatabaseRebalancesPendingWork()
+		require.NoError(t, err)
+		require.Len(t, pending, 1)
+		assert.Equal(t, rebalance.ID, pending[0].ID)
+	})
+
+	t.Run("unknown rebalance", func(t *testing.T) {
+		fetchedRebalance, err = sqlStore.GetMultitenantDatabaseRebalance("unknown")
+		require.NoError(t, err)
+		assert.Nil(t, fetchedRebalance)
+	})
+}
+
+func TestGetMultitenantDatabaseRebalances(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := MakeTestSQLStore(t, logger)
+	defer CloseConnection(t, sqlStore)
+
+	rebalances := []*model.MultitenantDatabaseRebalance{
+		{VpcID: "vpc1", State: model.MultitenantDatabaseRebalanceStateRequested},
+		{VpcID: "vpc1", State: model.MultitenantDatabaseRebalanceStateSucceeded},
+		{VpcID: "vpc2", State: model.MultitenantDatabaseRebalanceStateInProgress},
+		{VpcID: "vpc2", State: model.MultitenantDatabaseRebalanceStateFailed},
+	}
+	for _, rebalance := range rebalances {
+		err := sqlStore.CreateMultitenantDatabaseRebalance(rebalance)
+		require.NoError(t, err)
+		time.Sleep(1 * time.Millisecond)
+	}
+
+	for _, testCase := range []struct {
+		description string
+		filter      *model.MultitenantDatabaseRebalanceFilter
+		fetchedIDs  []string
+	}{
+		{
+			description: "fetch all",
+			filter:      &model.MultitenantDatabaseRebalanceFilter{Paging: model.AllPagesNotDeleted()},
+			fetchedIDs:  []string{rebalances[3].ID, rebalances[2].ID, rebalances[1].ID, rebalances[0].ID},
+		},
+		{
+			description: "fetch by vpc",
+			filter:      &model.MultitenantDatabaseRebalanceFilter{Paging: model.AllPagesNotDeleted(), VpcID: "vpc1"},
+			fetchedIDs:  []string{rebalances[1].ID, rebalances[0].ID},
+		},
+		{
+			description: "fetch pending work",
+			filter: &model.MultitenantDatabaseRebalanceFilter{
+				Paging: model.AllPagesNotDeleted(),
+				States: model.AllMultitenantDatabaseRebalanceStatesPendingWork,
+			},
+			fetchedIDs: []string{rebalances[2].ID, rebalances[0].ID},
+		},
+		{
+			description: "fetch by vpc and state",
+			filter: &model.MultitenantDatabaseRebalanceFilter{
+				Paging: model.AllPagesNotDeleted(),
+				VpcID:  "vpc2",
+				States: []model.MultitenantDatabaseRebalanceState{model.MultitenantDatabaseRebalanceStateFailed},
+			},
+			fetchedIDs: []string{rebalances[3].ID},
+		},
+		{
+			description: "fetch page",
+			filter:      &model.MultitenantDatabaseRebalanceFilter{Paging: model.Paging{Page: 0, PerPage: 2, IncludeDeleted: false}},
+			fetchedIDs:  []string{rebalances[3].ID, rebalances[2].ID},
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			fetchedRebalances, err := sqlStore.GetMultitenantDatabaseRebalances(testCase.filter)
+			require.NoError(t, err)
+
+			var fetchedIDs []string
+			for _, rebalance := range fetchedRebalances {
+				fetchedIDs = append(fetchedIDs, rebalance.ID)
+			}
+			assert.Equal(t, testCase.fetchedIDs, fetchedIDs)
+		})
+	}
+
+	t.Run("unlocked pending work", func(t *testing.T) {
+		pending, err := sqlStore.GetUnlockedMultitenantDatabaseRebalancesPendingWork()
+		require.NoError(t, err)
+		require.Len(t, pending, 2)
+		assert.Equal(t, rebalances[0].ID, pending[0].ID)
+		assert.Equal(t, rebalances[2].ID, pending[1].ID)
+	})
+}
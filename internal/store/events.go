@@ -21,7 +21,7 @@ const (
 )
 
 var (
-	eventDeliveryColumns = []string{"ID", "EventID", "SubscriptionID", "Status", "LastAttempt", "Attempts"}
+	eventDeliveryColumns = []string{"ID", "EventID", "SubscriptionID", "Status", "LastAttempt", "Attempts", "ReplayedAt"}
 
 	stateChangeEventSelect = sq.Select("sc.ID, sc.ResourceID, sc.ResourceType, sc.OldState, sc.NewState, sc.EventID, e.Timestamp, e.EventType, e.ExtraData").
 				From("StateChangeEvent as sc").
@@ -54,6 +54,11 @@ func (sqlStore *SQLStore) CreateStateChangeEvent(event *model.StateChangeEventDa
 		return errors.Wrap(err, "failed to get subscriptions")
 	}
 
+	subscriptions, err = sqlStore.filterSubscriptionsForEvent(tx, subscriptions, event.StateChange)
+	if err != nil {
+		return errors.Wrap(err, "failed to filter subscriptions")
+	}
+
 	err = sqlStore.createEventDeliveries(tx, &event.Event, subscriptions)
 	if err != nil {
 		return errors.Wrap(err, "failed to create event deliveries")
@@ -66,6 +71,85 @@ func (sqlStore *SQLStore) CreateStateChangeEvent(event *model.StateChangeEventDa
 	return nil
 }
 
+// filterSubscriptionsForEvent returns subscriptions which filters match the event.
+// Filters are evaluated when deliveries are created, so that subscriptions are
+// never claimed for the events they are not interested in.
+func (sqlStore *SQLStore) filterSubscriptionsForEvent(db dbInterface, subscriptions []*model.Subscription, event model.StateChangeEvent) ([]*model.Subscription, error) {
+	var metadata *model.EventResourceMetadata
+	matching := make([]*model.Subscription, 0, len(subscriptions))
+	for _, sub := range subscriptions {
+		if sub.Filter.RequiresResourceMetadata() && metadata == nil {
+			var err error
+			metadata, err = sqlStore.getEventResourceMetadata(db, event.ResourceType, event.ResourceID)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to get event resource metadata")
+			}
+		}
+		if sub.Filter.Matches(event, metadata) {
+			matching = append(matching, sub)
+		}
+	}
+
+	return matching, nil
+}
+
+// getEventResourceMetadata fetches owner, group and annotations of the event resource.
+// Cluster installations inherit them from their installation, groups match
+// themselves.
+func (sqlStore *SQLStore) getEventResourceMetadata(db dbInterface, resourceType model.ResourceType, resourceID string) (*model.EventResourceMetadata, error) {
+	metadata := &model.EventResourceMetadata{}
+
+	switch resourceType {
+	case model.TypeCluster:
+		annotations, err := sqlStore.getAnnotationsForCluster(db, resourceID)
+		if err != nil {
+			return nil, err
+		}
+		metadata.Annotations = model.GetAnnotationsNames(annotations)
+		return metadata, nil
+	case model.TypeClusterInstallation:
+		var installationID string
+		err := sqlStore.getBuilder(db, &installationID,
+			sq.Select("InstallationID").From("ClusterInstallation").Where("ID = ?", resourceID))
+		if err == sql.ErrNoRows {
+			return metadata, nil
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get cluster installation")
+		}
+		resourceID = installationID
+	case model.TypeGroup:
+		metadata.GroupID = resourceID
+		return metadata, nil
+	case model.TypeInstallation:
+	default:
+		return metadata, nil
+	}
+
+	var installation struct {
+		OwnerID string
+		GroupID sql.NullString
+	}
+	err := sqlStore.getBuilder(db, &installation,
+		sq.Select("OwnerID", "GroupID").From("Installation").Where("ID = ?", resourceID))
+	if err == sql.ErrNoRows {
+		return metadata, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get installation")
+	}
+	metadata.OwnerID = installation.OwnerID
+	metadata.GroupID = installation.GroupID.String
+
+	annotations, err := sqlStore.getAnnotationsForInstallation(db, resourceID)
+	if err != nil {
+		return nil, err
+	}
+	metadata.Annotations = model.GetAnnotationsNames(annotations)
+
+	return metadata, nil
+}
+
 func (sqlStore *SQLStore) createEvent(db execer, event *model.Event) error {
 	event.ID = model.NewID()
 
@@ -135,7 +219,7 @@ func (sqlStore *SQLStore) createEventDeliveries(db dbInterface, event *model.Eve
 func (sqlStore *SQLStore) insertEventDeliveries(db dbInterface, event *model.Event, subscriptions []*model.Subscription) error {
 	builder := sq.Insert("EventDelivery").Columns(eventDeliveryColumns...)
 	for _, sub := range subscriptions {
-		builder = builder.Values(model.NewID(), event.ID, sub.ID, model.EventDeliveryNotAttempted, 0, 0)
+		builder = builder.Values(model.NewID(), event.ID, sub.ID, model.EventDeliveryNotAttempted, 0, 0, 0)
 	}
 
 	_, err := sqlStore.execBuilder(db, builder)
@@ -184,13 +268,75 @@ func (sqlStore *SQLStore) GetStateChangeEventsToProcess(subID string) ([]*model.
 		return nil, errors.Wrap(err, "failed to query event deliveries for subscription")
 	}
 
+	return sqlStore.getStateChangeEventDeliveriesData(sqlStore.db, eventDeliveries)
+}
+
+// GetEventDeliveries returns StateChangeEventDeliveryData matching the filter in order of occurrence.
+func (sqlStore *SQLStore) GetEventDeliveries(filter *model.EventDeliveryFilter) ([]*model.StateChangeEventDeliveryData, error) {
+	query := sq.Select(prefixAll("ed.", eventDeliveryColumns)...).
+		From("EventDelivery as ed").
+		Join("Event as e on ed.EventID = e.ID").
+		OrderBy("e.Timestamp ASC")
+
+	if filter.Paging.PerPage != model.AllPerPage {
+		query = query.
+			Limit(uint64(filter.Paging.PerPage)).
+			Offset(uint64(filter.Paging.Page * filter.Paging.PerPage))
+	}
+	if filter.SubscriptionID != "" {
+		query = query.Where("ed.SubscriptionID = ?", filter.SubscriptionID)
+	}
+	if filter.Status != "" {
+		query = query.Where("ed.Status = ?", filter.Status)
+	}
+
+	var eventDeliveries []*model.EventDelivery
+	err := sqlStore.selectBuilder(sqlStore.db, &eventDeliveries, query)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query event deliveries")
+	}
+
+	return sqlStore.getStateChangeEventDeliveriesData(sqlStore.db, eventDeliveries)
+}
+
+// ReplayEventDeliveries schedules event deliveries of the subscription for
+// another attempt and returns number of replayed deliveries. Deliveries that
+// are still pending are left untouched.
+func (sqlStore *SQLStore) ReplayEventDeliveries(subID string, request *model.ReplaySubscriptionDeliveriesRequest) (int64, error) {
+	query := sq.Update(eventDeliveryTable).
+		SetMap(map[string]interface{}{
+			"Status":     model.EventDeliveryNotAttempted,
+			"ReplayedAt": model.GetMillis(),
+		}).
+		Where("SubscriptionID = ?", subID).
+		Where(sq.NotEq{"Status": model.EventPendingDeliveryStatuses})
+
+	if len(request.EventIDs) > 0 {
+		query = query.Where(sq.Eq{"EventID": request.EventIDs})
+	} else {
+		query = query.Where(sq.Expr("EventID IN (SELECT ID FROM Event WHERE Timestamp >= ? AND Timestamp <= ?)", request.From, request.To))
+	}
+
+	result, err := sqlStore.execBuilder(sqlStore.db, query)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to replay event deliveries")
+	}
+	replayed, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get number of replayed event deliveries")
+	}
+
+	return replayed, nil
+}
+
+func (sqlStore *SQLStore) getStateChangeEventDeliveriesData(db queryer, eventDeliveries []*model.EventDelivery) ([]*model.StateChangeEventDeliveryData, error) {
 	eventIDs := make([]string, 0, len(eventDeliveries))
 	for _, e := range eventDeliveries {
 		eventIDs = append(eventIDs, e.EventID)
 	}
 
 	var eventsData []stateChangeEventData
-	err = sqlStore.selectBuilder(sqlStore.db, &eventsData,
+	err := sqlStore.selectBuilder(db, &eventsData,
 		stateChangeEventSelect.
 			Where(sq.Eq{"sc.EventID": eventIDs}).
 			OrderBy("e.Timestamp ASC"),
@@ -0,0 +1,166 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package store
+
+import (
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+)
+
+// GetStateChangeEventsToPrune returns up to limit oldest state change events
+// which occurred before the given time and were delivered to all of their
+// subscriptions.
+func (sqlStore *SQLStore) GetStateChangeEventsToPrune(olderThan int64, limit uint64) ([]*model.StateChangeEventData, error) {
+	query := stateChangeEventSelect.
+		Where("e.Timestamp < ?", olderThan).
+		Where(sq.Expr("NOT EXISTS (SELECT 1 FROM EventDelivery as ed WHERE ed.EventID = e.ID AND ?)",
+			sq.NotEq{"ed.Status": model.EventPrunableDeliveryStatuses})).
+		OrderBy("e.Timestamp ASC").
+		Limit(limit)
+
+	var eventsData []stateChangeEventData
+	err := sqlStore.selectBuilder(sqlStore.db, &eventsData, query)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query state change events to prune")
+	}
+
+	out := make([]*model.StateChangeEventData, len(eventsData))
+	for i, ed := range eventsData {
+		data, err := ed.toStateChangeEventData()
+		if err != nil {
+			return nil, err
+		}
+
+		out[i] = &data
+	}
+
+	return out, nil
+}
+
+// GetEventDeliveriesForEvents returns all deliveries of the given events.
+func (sqlStore *SQLStore) GetEventDeliveriesForEvents(eventIDs []string) ([]*model.EventDelivery, error) {
+	deliveries := []*model.EventDelivery{}
+	err := sqlStore.selectBuilder(sqlStore.db, &deliveries,
+		sq.Select(eventDeliveryColumns...).
+			From(eventDeliveryTable).
+			Where(sq.Eq{"EventID": eventIDs}),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query event deliveries for events")
+	}
+
+	return deliveries, nil
+}
+
+// DeleteStateChangeEvents deletes the given events together with their state
+// change data and deliveries. Events that have undelivered deliveries,
+// including failed ones kept for replay, are skipped.
+func (sqlStore *SQLStore) DeleteStateChangeEvents(eventIDs []string) (*model.EventsPruneResult, error) {
+	result := &model.EventsPruneResult{}
+	if len(eventIDs) == 0 {
+		return result, nil
+	}
+
+	tx, err := sqlStore.beginTransaction(sqlStore.db)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to begin transaction")
+	}
+	defer tx.RollbackUnlessCommitted()
+
+	// Lock the deliveries so that they cannot be replayed while the events
+	// are being deleted.
+	var deliveries []*model.EventDelivery
+	err = sqlStore.selectBuilder(tx, &deliveries,
+		sq.Select(eventDeliveryColumns...).
+			From(eventDeliveryTable).
+			Where(sq.Eq{"EventID": eventIDs}).
+			Suffix("FOR UPDATE"),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to lock event deliveries")
+	}
+
+	undelivered := map[string]bool{}
+	for _, delivery := range deliveries {
+		if !delivery.Status.IsPrunable() {
+			undelivered[delivery.EventID] = true
+		}
+	}
+	toDelete := make([]string, 0, len(eventIDs))
+	for _, eventID := range eventIDs {
+		if !undelivered[eventID] {
+			toDelete = append(toDelete, eventID)
+		}
+	}
+	if len(toDelete) == 0 {
+		return result, nil
+	}
+
+	result.EventDeliveries, err = sqlStore.deleteRows(tx, sq.Delete(eventDeliveryTable).Where(sq.Eq{"EventID": toDelete}))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to delete event deliveries")
+	}
+	result.StateChangeEvents, err = sqlStore.deleteRows(tx, sq.Delete(stateChangeEventTable).Where(sq.Eq{"EventID": toDelete}))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to delete state change events")
+	}
+	result.Events, err = sqlStore.deleteRows(tx, sq.Delete(eventTable).Where(sq.Eq{"ID": toDelete}))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to delete events")
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to commit transaction")
+	}
+
+	return result, nil
+}
+
+// GetEventDeliveriesToPrune returns up to limit oldest delivered event
+// deliveries which were last attempted before the given time.
+func (sqlStore *SQLStore) GetEventDeliveriesToPrune(olderThan int64, limit uint64) ([]*model.EventDelivery, error) {
+	deliveries := []*model.EventDelivery{}
+	err := sqlStore.selectBuilder(sqlStore.db, &deliveries,
+		sq.Select(eventDeliveryColumns...).
+			From(eventDeliveryTable).
+			Where(sq.Eq{"Status": model.EventPrunableDeliveryStatuses}).
+			Where("LastAttempt < ?", olderThan).
+			OrderBy("LastAttempt ASC").
+			Limit(limit),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query event deliveries to prune")
+	}
+
+	return deliveries, nil
+}
+
+// DeleteEventDeliveries deletes the given event deliveries if they were delivered.
+func (sqlStore *SQLStore) DeleteEventDeliveries(deliveryIDs []string) (int64, error) {
+	if len(deliveryIDs) == 0 {
+		return 0, nil
+	}
+
+	deleted, err := sqlStore.deleteRows(sqlStore.db, sq.Delete(eventDeliveryTable).
+		Where(sq.Eq{"ID": deliveryIDs}).
+		Where(sq.Eq{"Status": model.EventPrunableDeliveryStatuses}),
+	)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to delete event deliveries")
+	}
+
+	return deleted, nil
+}
+
+func (sqlStore *SQLStore) deleteRows(db execer, query sq.DeleteBuilder) (int64, error) {
+	result, err := sqlStore.execBuilder(db, query)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package store
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventsRetention(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := MakeTestSQLStore(t, logger)
+	defer CloseConnection(t, sqlStore)
+
+	sub := &model.Subscription{
+		URL:       "test",
+		EventType: model.ResourceStateChangeEventType,
+	}
+	err := sqlStore.CreateSubscription(sub)
+	require.NoError(t, err)
+
+	createEvent := func(timestamp int64) *model.StateChangeEventData {
+		eventData := &model.StateChangeEventData{
+			Event: model.Event{
+				EventType: model.ResourceStateChangeEventType,
+				Timestamp: timestamp,
+			},
+			StateChange: model.StateChangeEvent{
+				OldState:     "old",
+				NewState:     "new",
+				ResourceID:   "installation1",
+				ResourceType: model.TypeInstallation,
+			},
+		}
+		err = sqlStore.CreateStateChangeEvent(eventData)
+		require.NoError(t, err)
+		return eventData
+	}
+
+	delivered := createEvent(100)
+	failed := createEvent(110)
+	pending := createEvent(200)
+	recent := createEvent(model.GetMillis())
+
+	deliveries, err := sqlStore.GetEventDeliveriesForEvents([]string{delivered.Event.ID})
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	deliveries[0].Status = model.EventDeliveryDelivered
+	deliveries[0].LastAttempt = 150
+	err = sqlStore.UpdateEventDeliveryStatus(deliveries[0])
+	require.NoError(t, err)
+
+	failedDeliveries, err := sqlStore.GetEventDeliveriesForEvents([]string{failed.Event.ID})
+	require.NoError(t, err)
+	require.Len(t, failedDeliveries, 1)
+	failedDeliveries[0].Status = model.EventDeliveryFailed
+	failedDeliveries[0].LastAttempt = 120
+	err = sqlStore.UpdateEventDeliveryStatus(failedDeliveries[0])
+	require.NoError(t, err)
+
+	t.Run("never prune events with pending deliveries", func(t *testing.T) {
+		events, err := sqlStore.GetStateChangeEventsToPrune(recent.Event.Timestamp, 10)
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, delivered.Event.ID, events[0].Event.ID)
+
+		result, err := sqlStore.DeleteStateChangeEvents([]string{pending.Event.ID})
+		require.NoError(t, err)
+		assert.True(t, result.IsEmpty())
+	})
+
+	t.Run("never prune failed deliveries kept for replay", func(t *testing.T) {
+		result, err := sqlStore.DeleteStateChangeEvents([]string{failed.Event.ID})
+		require.NoError(t, err)
+		assert.True(t, result.IsEmpty())
+
+		deleted, err := sqlStore.DeleteEventDeliveries([]string{failedDeliveries[0].ID})
+		require.NoError(t, err)
+		assert.Zero(t, deleted)
+
+		event, err := sqlStore.GetStateChangeEvent(failed.Event.ID)
+		require.NoError(t, err)
+		assert.NotNil(t, event)
+
+		remaining, err := sqlStore.GetEventDeliveriesForEvents([]string{failed.Event.ID})
+		require.NoError(t, err)
+		require.Len(t, remaining, 1)
+		assert.Equal(t, model.EventDeliveryFailed, remaining[0].Status)
+	})
+
+	t.Run("prune delivered deliveries", func(t *testing.T) {
+		toPrune, err := sqlStore.GetEventDeliveriesToPrune(200, 10)
+		require.NoError(t, err)
+		require.Len(t, toPrune, 1)
+		assert.Equal(t, deliveries[0].ID, toPrune[0].ID)
+	})
+
+	t.Run("prune delivered event", func(t *testing.T) {
+		result, err := sqlStore.DeleteStateChangeEvents([]string{delivered.Event.ID})
+		require.NoError(t, err)
+		assert.Equal(t, &model.EventsPruneResult{Events: 1, StateChangeEvents: 1, EventDeliveries: 1}, result)
+
+		event, err := sqlStore.GetStateChangeEvent(delivered.Event.ID)
+		require.NoError(t, err)
+		assert.Nil(t, event)
+
+		event, err = sqlStore.GetStateChangeEvent(pending.Event.ID)
+		require.NoError(t, err)
+		assert.NotNil(t, event)
+	})
+}
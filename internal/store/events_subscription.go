@@ -29,6 +29,10 @@ var (
 		"DeleteAt",
 		"LockAcquiredBy",
 		"LockAcquiredAt",
+		"Filter",
+		"Paused",
+		"PayloadFormat",
+		"SinkType",
 	}
 
 	subscriptionsSelect = sq.Select(subscriptionsColumns...).
@@ -38,6 +42,8 @@ var (
 				From(fmt.Sprintf("%s as sub", subscriptionsTable)).
 				Join("EventDelivery ON sub.ID=EventDelivery.SubscriptionID").
 				Where("DeleteAt = 0").
+		// Skip paused subscriptions.
+		Where("sub.Paused = ?", false).
 		// Take only not claimed subscriptions.
 		Where("sub.LockAcquiredAt = 0").
 		Where(sq.Eq{"LockAcquiredBy": nil}).
@@ -137,6 +143,9 @@ func (sqlStore *SQLStore) CountSubscriptionsForEvent(eventType model.EventType)
 func (sqlStore *SQLStore) CreateSubscription(sub *model.Subscription) error {
 	sub.ID = model.NewID()
 	sub.CreateAt = model.GetMillis()
+	if sub.SinkType == "" {
+		sub.SinkType = model.SubscriptionSinkHTTP
+	}
 
 	_, err := sqlStore.execBuilder(sqlStore.db, sq.Insert(subscriptionsTable).
 		SetMap(map[string]interface{}{
@@ -153,6 +162,10 @@ func (sqlStore *SQLStore) CreateSubscription(sub *model.Subscription) error {
 			"LockAcquiredAt":        sub.LockAcquiredAt,
 			"LockAcquiredBy":        sub.LockAcquiredBy,
 			"Headers":               sub.Headers,
+			"Filter":                sub.Filter,
+			"Paused":                sub.Paused,
+			"PayloadFormat":         sub.PayloadFormat,
+			"SinkType":              sub.SinkType,
 		}))
 	if err != nil {
 		return errors.Wrap(err, "failed to create subscription")
@@ -178,6 +191,20 @@ func (sqlStore *SQLStore) UpdateSubscriptionStatus(sub *model.Subscription) erro
 	return nil
 }
 
+// UpdateSubscriptionPaused pauses or resumes event deliveries of the subscription.
+func (sqlStore *SQLStore) UpdateSubscriptionPaused(subID string, paused bool) error {
+	_, err := sqlStore.execBuilder(sqlStore.db, sq.Update(subscriptionsTable).
+		Set("Paused", paused).
+		Where("ID = ?", subID).
+		Where("DeleteAt = 0"),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to update subscription paused state")
+	}
+
+	return nil
+}
+
 // GetSubscriptions fetches subscriptions specified by the filter.
 func (sqlStore *SQLStore) GetSubscriptions(filter *model.SubscriptionsFilter) ([]*model.Subscription, error) {
 	return sqlStore.getSubscriptions(sqlStore.db, filter)
@@ -240,3 +267,86 @@ func prefixAll(prefix string, strs []string) []string {
 	}
 	return out
 }
+
+type subscriptionDeliveryCount struct {
+	SubscriptionID string
+	Status         model.EventDeliveryStatus
+	Count          int64
+	OldestEventAt  int64
+}
+
+// GetSubscriptionStats returns delivery stats of the subscription.
+func (sqlStore *SQLStore) GetSubscriptionStats(subID string) (*model.SubscriptionStats, error) {
+	sub, err := sqlStore.GetSubscription(subID)
+	if err != nil {
+		return nil, err
+	}
+	if sub == nil {
+		return nil, nil
+	}
+
+	counts, err := sqlStore.getSubscriptionDeliveryCounts(sq.Eq{"ed.SubscriptionID": subID})
+	if err != nil {
+		return nil, err
+	}
+
+	stats := model.NewSubscriptionStats(sub)
+	for _, c := range counts {
+		stats.AddDeliveries(c.Status, c.Count, c.OldestEventAt)
+	}
+
+	return stats, nil
+}
+
+// GetSubscriptionsStats returns delivery stats of all subscriptions that are
+// not deleted.
+func (sqlStore *SQLStore) GetSubscriptionsStats() ([]*model.SubscriptionStats, error) {
+	subs, err := sqlStore.GetSubscriptions(&model.SubscriptionsFilter{Paging: model.AllPagesNotDeleted()})
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := sqlStore.getSubscriptionDeliveryCounts(
+		sq.Expr(fmt.Sprintf("ed.SubscriptionID IN (SELECT ID FROM %s WHERE DeleteAt = 0)", subscriptionsTable)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	statsByID := make(map[string]*model.SubscriptionStats, len(subs))
+	out := make([]*model.SubscriptionStats, 0, len(subs))
+	for _, sub := range subs {
+		stats := model.NewSubscriptionStats(sub)
+		statsByID[sub.ID] = stats
+		out = append(out, stats)
+	}
+	for _, c := range counts {
+		if stats, ok := statsByID[c.SubscriptionID]; ok {
+			stats.AddDeliveries(c.Status, c.Count, c.OldestEventAt)
+		}
+	}
+
+	return out, nil
+}
+
+// getSubscriptionDeliveryCounts counts event deliveries matching the
+// predicate by subscription and status.
+func (sqlStore *SQLStore) getSubscriptionDeliveryCounts(pred interface{}) ([]*subscriptionDeliveryCount, error) {
+	query := sq.Select(
+		"ed.SubscriptionID as SubscriptionID",
+		"ed.Status as Status",
+		"COUNT(*) as Count",
+		"MIN(e.Timestamp) as OldestEventAt").
+		From("EventDelivery as ed").
+		Join("Event as e on ed.EventID = e.ID").
+		Where(pred).
+		GroupBy("ed.SubscriptionID", "ed.Status")
+
+	var counts []*subscriptionDeliveryCount
+	err := sqlStore.selectBuilder(sqlStore.db, &counts, query)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to count event deliveries")
+	}
+
+	return counts, nil
+}
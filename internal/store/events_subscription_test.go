@@ -162,3 +162,94 @@ func TestGetSubscriptions(t *testing.T) {
 		})
 	}
 }
+
+func TestGetSubscriptionStats(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := MakeTestSQLStore(t, logger)
+	defer CloseConnection(t, sqlStore)
+
+	sub1 := &model.Subscription{
+		URL:                   "test1",
+		EventType:             model.ResourceStateChangeEventType,
+		LastDeliveryStatus:    model.SubscriptionDeliveryFailed,
+		LastDeliveryAttemptAt: 100,
+	}
+	err := sqlStore.CreateSubscription(sub1)
+	require.NoError(t, err)
+	sub2 := &model.Subscription{
+		URL:       "test2",
+		EventType: model.ResourceStateChangeEventType,
+	}
+	err = sqlStore.CreateSubscription(sub2)
+	require.NoError(t, err)
+	sub3 := &model.Subscription{
+		URL:       "test3",
+		EventType: model.ResourceStateChangeEventType,
+	}
+	err = sqlStore.CreateSubscription(sub3)
+	require.NoError(t, err)
+	err = sqlStore.DeleteSubscription(sub3.ID)
+	require.NoError(t, err)
+
+	var timestamps []int64
+	for i := 0; i < 3; i++ {
+		eventData := &model.StateChangeEventData{
+			Event: model.Event{
+				EventType: model.ResourceStateChangeEventType,
+				Timestamp: model.GetMillis() + int64(i),
+			},
+			StateChange: model.StateChangeEvent{
+				OldState:     "old",
+				NewState:     "new",
+				ResourceID:   "installation1",
+				ResourceType: "installation",
+			},
+		}
+		err = sqlStore.CreateStateChangeEvent(eventData)
+		require.NoError(t, err)
+		timestamps = append(timestamps, eventData.Event.Timestamp)
+	}
+
+	deliveries, err := sqlStore.GetStateChangeEventsToProcess(sub1.ID)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 3)
+	for i, status := range []model.EventDeliveryStatus{model.EventDeliveryDelivered, model.EventDeliveryRetrying} {
+		deliveries[i].EventDelivery.Status = status
+		err = sqlStore.UpdateEventDeliveryStatus(&deliveries[i].EventDelivery)
+		require.NoError(t, err)
+	}
+	oldestUndelivered := deliveries[1].EventData.Event.Timestamp
+
+	t.Run("single subscription", func(t *testing.T) {
+		stats, err := sqlStore.GetSubscriptionStats(sub1.ID)
+		require.NoError(t, err)
+		assert.Equal(t, &model.SubscriptionStats{
+			SubscriptionID:           sub1.ID,
+			LastDeliveryStatus:       model.SubscriptionDeliveryFailed,
+			LastDeliveryAttemptAt:    100,
+			Backlog:                  2,
+			Retrying:                 1,
+			Delivered:                1,
+			OldestUndeliveredEventAt: oldestUndelivered,
+		}, stats)
+	})
+
+	t.Run("unknown subscription", func(t *testing.T) {
+		stats, err := sqlStore.GetSubscriptionStats(model.NewID())
+		require.NoError(t, err)
+		assert.Nil(t, stats)
+	})
+
+	t.Run("all subscriptions", func(t *testing.T) {
+		stats, err := sqlStore.GetSubscriptionsStats()
+		require.NoError(t, err)
+		require.Len(t, stats, 2)
+		statsByID := map[string]*model.SubscriptionStats{}
+		for _, s := range stats {
+			statsByID[s.SubscriptionID] = s
+		}
+		assert.Equal(t, int64(2), statsByID[sub1.ID].Backlog)
+		assert.Equal(t, int64(3), statsByID[sub2.ID].Backlog)
+		assert.Equal(t, timestamps[0], statsByID[sub2.ID].OldestUndeliveredEventAt)
+	})
+}
@@ -277,3 +277,68 @@ func TestGetStateChangeEvents(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateStateChangeEvent_SubscriptionFilter(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := MakeTestSQLStore(t, logger)
+	defer CloseConnection(t, sqlStore)
+
+	groupID := "group1"
+	installation := &model.Installation{
+		Name:      "filtered",
+		OwnerID:   "owner1",
+		GroupID:   &groupID,
+		Database:  model.InstallationDatabaseMysqlOperator,
+		Filestore: model.InstallationFilestoreMinioOperator,
+		State:     model.InstallationStateStable,
+	}
+	err := sqlStore.CreateInstallation(installation, nil, fixDNSRecords(1))
+	require.NoError(t, err)
+
+	unfiltered := &model.Subscription{URL: "unfiltered", EventType: model.ResourceStateChangeEventType}
+	byType := &model.Subscription{URL: "by-type", EventType: model.ResourceStateChangeEventType,
+		Filter: &model.SubscriptionFilter{ResourceTypes: []model.ResourceType{model.TypeCluster}}}
+	byOwner := &model.Subscription{URL: "by-owner", EventType: model.ResourceStateChangeEventType,
+		Filter: &model.SubscriptionFilter{OwnerID: "owner1", NewStates: []string{model.InstallationStateStable}}}
+	byOtherGroup := &model.Subscription{URL: "by-other-group", EventType: model.ResourceStateChangeEventType,
+		Filter: &model.SubscriptionFilter{GroupID: "group2"}}
+
+	for _, sub := range []*model.Subscription{unfiltered, byType, byOwner, byOtherGroup} {
+		err = sqlStore.CreateSubscription(sub)
+		require.NoError(t, err)
+	}
+
+	fetched, err := sqlStore.GetSubscription(byOwner.ID)
+	require.NoError(t, err)
+	assert.Equal(t, byOwner.Filter, fetched.Filter)
+
+	err = sqlStore.CreateStateChangeEvent(&model.StateChangeEventData{
+		Event: model.Event{
+			EventType: model.ResourceStateChangeEventType,
+			Timestamp: model.GetMillis(),
+		},
+		StateChange: model.StateChangeEvent{
+			OldState:     model.InstallationStateCreationRequested,
+			NewState:     model.InstallationStateStable,
+			ResourceID:   installation.ID,
+			ResourceType: model.TypeInstallation,
+		},
+	})
+	require.NoError(t, err)
+
+	for _, testCase := range []struct {
+		subscription *model.Subscription
+		deliveries   int
+	}{
+		{unfiltered, 1},
+		{byType, 0},
+		{byOwner, 1},
+		{byOtherGroup, 0},
+	} {
+		t.Run(testCase.subscription.URL, func(t *testing.T) {
+			deliveries, err := sqlStore.GetDeliveriesForSubscription(testCase.subscription.ID)
+			require.NoError(t, err)
+			assert.Len(t, deliveries, testCase.deliveries)
+		})
+	}
+}
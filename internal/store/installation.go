@@ -32,6 +32,7 @@ func init() {
 			"Installation.DeletionPendingExpiry", "APISecurityLock", "LockAcquiredBy",
 			"LockAcquiredAt", "CRVersion", "Installation.DeletionLocked",
 			"AllowedIPRanges", "Volumes", "ScheduledDeletionTime", "PodProbeOverrides",
+			"NetworkPolicyExceptions",
 		).From(installationTable)
 }
 
@@ -201,6 +202,13 @@ func (sqlStore *SQLStore) applyInstallationFilter(builder sq.SelectBuilder, filt
 	if filter.Name != "" {
 		builder = builder.Where("Installation.Name = ?", filter.Name)
 	}
+	if filter.Size != "" {
+		// Sizes may carry a replicas segment, for example: provisionerXL-6.
+		builder = builder.Where(sq.Or{
+			sq.Eq{"Installation.Size": filter.Size},
+			sq.Like{"Installation.Size": filter.Size + "-%"},
+		})
+	}
 	if filter.DeletionLocked != nil {
 		builder = builder.Where("Installation.DeletionLocked = ?", filter.DeletionLocked)
 	}
@@ -474,34 +482,35 @@ func (sqlStore *SQLStore) createInstallation(db execer, installation *model.Inst
 	}
 
 	insertsMap := map[string]interface{}{
-		"Name":                  installation.Name,
-		"ID":                    installation.ID,
-		"OwnerID":               installation.OwnerID,
-		"GroupID":               installation.GroupID,
-		"GroupSequence":         nil,
-		"Version":               installation.Version,
-		"Image":                 installation.Image,
-		"Database":              installation.Database,
-		"Filestore":             installation.Filestore,
-		"Size":                  installation.Size,
-		"Affinity":              installation.Affinity,
-		"State":                 installation.State,
-		"License":               installation.License,
-		"MattermostEnvRaw":      envJSON,
-		"PriorityEnvRaw":        priorityEnvJSON,
-		"CreateAt":              installation.CreateAt,
-		"DeleteAt":              0,
-		"DeletionPendingExpiry": 0,
-		"ScheduledDeletionTime": installation.ScheduledDeletionTime,
-		"APISecurityLock":       installation.APISecurityLock,
-		"LockAcquiredBy":        nil,
-		"LockAcquiredAt":        0,
-		"CRVersion":             installation.CRVersion,
-		"DeletionLocked":        installation.DeletionLocked,
-		"AllowedIPRanges":       installation.AllowedIPRanges,
-		"Volumes":               installation.Volumes,
-		"PodProbeOverrides":     installation.PodProbeOverrides,
-		"Command":               installation.Command,
+		"Name":                    installation.Name,
+		"ID":                      installation.ID,
+		"OwnerID":                 installation.OwnerID,
+		"GroupID":                 installation.GroupID,
+		"GroupSequence":           nil,
+		"Version":                 installation.Version,
+		"Image":                   installation.Image,
+		"Database":                installation.Database,
+		"Filestore":               installation.Filestore,
+		"Size":                    installation.Size,
+		"Affinity":                installation.Affinity,
+		"State":                   installation.State,
+		"License":                 installation.License,
+		"MattermostEnvRaw":        envJSON,
+		"PriorityEnvRaw":          priorityEnvJSON,
+		"CreateAt":                installation.CreateAt,
+		"DeleteAt":                0,
+		"DeletionPendingExpiry":   0,
+		"ScheduledDeletionTime":   installation.ScheduledDeletionTime,
+		"APISecurityLock":         installation.APISecurityLock,
+		"LockAcquiredBy":          nil,
+		"LockAcquiredAt":          0,
+		"CRVersion":               installation.CRVersion,
+		"DeletionLocked":          installation.DeletionLocked,
+		"AllowedIPRanges":         installation.AllowedIPRanges,
+		"Volumes":                 installation.Volumes,
+		"PodProbeOverrides":       installation.PodProbeOverrides,
+		"Command":                 installation.Command,
+		"NetworkPolicyExceptions": installation.NetworkPolicyExceptions,
 	}
 
 	singleTenantDBConfJSON, err := installation.SingleTenantDatabaseConfig.ToJSON()
@@ -560,27 +569,28 @@ func (sqlStore *SQLStore) updateInstallation(db execer, installation *model.Inst
 	_, err = sqlStore.execBuilder(db, sq.
 		Update("Installation").
 		SetMap(map[string]interface{}{
-			"Name":                  installation.Name,
-			"OwnerID":               installation.OwnerID,
-			"GroupID":               installation.GroupID,
-			"GroupSequence":         installation.GroupSequence,
-			"Version":               installation.Version,
-			"Image":                 installation.Image,
-			"Database":              installation.Database,
-			"Filestore":             installation.Filestore,
-			"Size":                  installation.Size,
-			"Affinity":              installation.Affinity,
-			"License":               installation.License,
-			"MattermostEnvRaw":      envJSON,
-			"PriorityEnvRaw":        priorityEnvJSON,
-			"State":                 installation.State,
-			"CRVersion":             installation.CRVersion,
-			"DeletionPendingExpiry": installation.DeletionPendingExpiry,
-			"ScheduledDeletionTime": installation.ScheduledDeletionTime,
-			"AllowedIPRanges":       installation.AllowedIPRanges,
-			"Volumes":               installation.Volumes,
-			"PodProbeOverrides":     installation.PodProbeOverrides,
-			"Command":               installation.Command,
+			"Name":                    installation.Name,
+			"OwnerID":                 installation.OwnerID,
+			"GroupID":                 installation.GroupID,
+			"GroupSequence":           installation.GroupSequence,
+			"Version":                 installation.Version,
+			"Image":                   installation.Image,
+			"Database":                installation.Database,
+			"Filestore":               installation.Filestore,
+			"Size":                    installation.Size,
+			"Affinity":                installation.Affinity,
+			"License":                 installation.License,
+			"MattermostEnvRaw":        envJSON,
+			"PriorityEnvRaw":          priorityEnvJSON,
+			"State":                   installation.State,
+			"CRVersion":               installation.CRVersion,
+			"DeletionPendingExpiry":   installation.DeletionPendingExpiry,
+			"ScheduledDeletionTime":   installation.ScheduledDeletionTime,
+			"AllowedIPRanges":         installation.AllowedIPRanges,
+			"Volumes":                 installation.Volumes,
+			"PodProbeOverrides":       installation.PodProbeOverrides,
+			"Command":                 installation.Command,
+			"NetworkPolicyExceptions": installation.NetworkPolicyExceptions,
 		}).
 		Where("ID = ?", installation.ID),
 	)
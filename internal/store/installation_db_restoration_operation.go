@@ -23,6 +23,8 @@ func init() {
 		Select("ID",
 			"InstallationID",
 			"BackupID",
+			"Type",
+			"RestoreTime",
 			"RequestAt",
 			"State",
 			"TargetInstallationState",
@@ -49,10 +51,34 @@ func (sqlStore *SQLStore) TriggerInstallationRestoration(installation *model.Ins
 	dbRestorationOp := &model.InstallationDBRestorationOperation{
 		InstallationID:          installation.ID,
 		BackupID:                backup.ID,
+		Type:                    model.InstallationDBRestorationTypeBackup,
 		State:                   model.InstallationDBRestorationStateRequested,
 		TargetInstallationState: targetInstallationState,
 	}
 
+	return sqlStore.triggerInstallationDBRestoration(installation, dbRestorationOp)
+}
+
+// TriggerInstallationPointInTimeRestoration creates new point-in-time InstallationDBRestorationOperation
+// in Requested state and changes installation state to InstallationStateDBRestorationInProgress.
+func (sqlStore *SQLStore) TriggerInstallationPointInTimeRestoration(installation *model.Installation, restoreTime int64) (*model.InstallationDBRestorationOperation, error) {
+	targetInstallationState, err := model.DetermineAfterRestorationState(installation)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to determine target installation state")
+	}
+
+	dbRestorationOp := &model.InstallationDBRestorationOperation{
+		InstallationID:          installation.ID,
+		Type:                    model.InstallationDBRestorationTypePointInTime,
+		RestoreTime:             restoreTime,
+		State:                   model.InstallationDBRestorationStateRequested,
+		TargetInstallationState: targetInstallationState,
+	}
+
+	return sqlStore.triggerInstallationDBRestoration(installation, dbRestorationOp)
+}
+
+func (sqlStore *SQLStore) triggerInstallationDBRestoration(installation *model.Installation, dbRestorationOp *model.InstallationDBRestorationOperation) (*model.InstallationDBRestorationOperation, error) {
 	tx, err := sqlStore.beginTransaction(sqlStore.db)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to start transaction")
@@ -86,6 +112,9 @@ func (sqlStore *SQLStore) CreateInstallationDBRestorationOperation(dbRestoration
 func (sqlStore *SQLStore) createInstallationDBRestoration(db execer, dbRestoration *model.InstallationDBRestorationOperation) error {
 	dbRestoration.ID = model.NewID()
 	dbRestoration.RequestAt = model.GetMillis()
+	if dbRestoration.Type == "" {
+		dbRestoration.Type = model.InstallationDBRestorationTypeBackup
+	}
 
 	_, err := sqlStore.execBuilder(db, sq.
 		Insert(installationDBRestorationTable).
@@ -93,6 +122,8 @@ func (sqlStore *SQLStore) createInstallationDBRestoration(db execer, dbRestorati
 			"ID":                      dbRestoration.ID,
 			"InstallationID":          dbRestoration.InstallationID,
 			"BackupID":                dbRestoration.BackupID,
+			"Type":                    dbRestoration.Type,
+			"RestoreTime":             dbRestoration.RestoreTime,
 			"State":                   dbRestoration.State,
 			"RequestAt":               dbRestoration.RequestAt,
 			"TargetInstallationState": dbRestoration.TargetInstallationState,
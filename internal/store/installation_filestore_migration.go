@@ -0,0 +1,277 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package store
+
+import (
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+)
+
+const (
+	installationFilestoreMigrationTable = "InstallationFilestoreMigrationOperation"
+)
+
+var installationFilestoreMigrationSelect sq.SelectBuilder
+
+func init() {
+	installationFilestoreMigrationSelect = sq.
+		Select("ID",
+			"InstallationID",
+			"RequestAt",
+			"State",
+			"SourceFilestore",
+			"DestinationFilestore",
+			"ObjectsTotal",
+			"BytesTotal",
+			"ObjectsCopied",
+			"BytesCopied",
+			"ContinuationToken",
+			"CompleteAt",
+			"DeleteAt",
+			"LockAcquiredBy",
+			"LockAcquiredAt",
+		).
+		From(installationFilestoreMigrationTable)
+}
+
+// TriggerInstallationFilestoreMigration creates new InstallationFilestoreMigrationOperation in Requested state
+// and changes installation state to InstallationStateFilestoreMigrationInProgress.
+func (sqlStore *SQLStore) TriggerInstallationFilestoreMigration(filestoreMigrationOp *model.InstallationFilestoreMigrationOperation, installation *model.Installation) (*model.InstallationFilestoreMigrationOperation, error) {
+	filestoreMigrationOp.InstallationID = installation.ID
+	filestoreMigrationOp.State = model.InstallationFilestoreMigrationStateRequested
+
+	tx, err := sqlStore.beginTransaction(sqlStore.db)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to start transaction")
+	}
+	defer tx.RollbackUnlessCommitted()
+
+	err = sqlStore.createInstallationFilestoreMigration(tx, filestoreMigrationOp)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create installation filestore migration")
+	}
+
+	installation.State = model.InstallationStateFilestoreMigrationInProgress
+	err = sqlStore.updateInstallation(tx, installation)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to update installation")
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to commit transaction")
+	}
+
+	return filestoreMigrationOp, nil
+}
+
+// TriggerInstallationFilestoreMigrationRollback triggers rollback of filestore migration in single transaction.
+func (sqlStore *SQLStore) TriggerInstallationFilestoreMigrationRollback(filestoreMigrationOp *model.InstallationFilestoreMigrationOperation, installation *model.Installation) error {
+	filestoreMigrationOp.State = model.InstallationFilestoreMigrationStateRollbackRequested
+	installation.State = model.InstallationStateFilestoreMigrationRollbackInProgress
+
+	tx, err := sqlStore.beginTransaction(sqlStore.db)
+	if err != nil {
+		return errors.Wrap(err, "failed to start transaction")
+	}
+	defer tx.RollbackUnlessCommitted()
+
+	err = sqlStore.updateInstallationFilestoreMigration(tx, filestoreMigrationOp)
+	if err != nil {
+		return errors.Wrap(err, "failed to update installation filestore migration")
+	}
+
+	err = sqlStore.updateInstallation(tx, installation)
+	if err != nil {
+		return errors.Wrap(err, "failed to update installation")
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errors.Wrap(err, "failed to commit transaction")
+	}
+
+	return nil
+}
+
+// CreateInstallationFilestoreMigrationOperation records installation filestore migration to the database, assigning it a unique ID.
+func (sqlStore *SQLStore) CreateInstallationFilestoreMigrationOperation(filestoreMigration *model.InstallationFilestoreMigrationOperation) error {
+	return sqlStore.createInstallationFilestoreMigration(sqlStore.db, filestoreMigration)
+}
+
+// createInstallationFilestoreMigration records installation filestore migration to the database, assigning it a unique ID.
+func (sqlStore *SQLStore) createInstallationFilestoreMigration(db execer, filestoreMigration *model.InstallationFilestoreMigrationOperation) error {
+	filestoreMigration.ID = model.NewID()
+	filestoreMigration.RequestAt = model.GetMillis()
+
+	_, err := sqlStore.execBuilder(db, sq.
+		Insert(installationFilestoreMigrationTable).
+		SetMap(map[string]interface{}{
+			"ID":                   filestoreMigration.ID,
+			"InstallationID":       filestoreMigration.InstallationID,
+			"RequestAt":            filestoreMigration.RequestAt,
+			"State":                filestoreMigration.State,
+			"SourceFilestore":      filestoreMigration.SourceFilestore,
+			"DestinationFilestore": filestoreMigration.DestinationFilestore,
+			"ObjectsTotal":         filestoreMigration.ObjectsTotal,
+			"BytesTotal":           filestoreMigration.BytesTotal,
+			"ObjectsCopied":        filestoreMigration.ObjectsCopied,
+			"BytesCopied":          filestoreMigration.BytesCopied,
+			"ContinuationToken":    filestoreMigration.ContinuationToken,
+			"CompleteAt":           filestoreMigration.CompleteAt,
+			"DeleteAt":             0,
+			"LockAcquiredBy":       filestoreMigration.LockAcquiredBy,
+			"LockAcquiredAt":       filestoreMigration.LockAcquiredAt,
+		}),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to create installation filestore migration operation")
+	}
+
+	return nil
+}
+
+// GetInstallationFilestoreMigrationOperation fetches the given installation filestore migration.
+func (sqlStore *SQLStore) GetInstallationFilestoreMigrationOperation(id string) (*model.InstallationFilestoreMigrationOperation, error) {
+	builder := installationFilestoreMigrationSelect.
+		Where("ID = ?", id)
+
+	var migrationOp model.InstallationFilestoreMigrationOperation
+	err := sqlStore.getBuilder(sqlStore.db, &migrationOp, builder)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "failed to query for installation filestore migration")
+	}
+
+	return &migrationOp, nil
+}
+
+// GetInstallationFilestoreMigrationOperations fetches the given page of created installation filestore migration. The first page is 0.
+func (sqlStore *SQLStore) GetInstallationFilestoreMigrationOperations(filter *model.InstallationFilestoreMigrationFilter) ([]*model.InstallationFilestoreMigrationOperation, error) {
+	builder := installationFilestoreMigrationSelect.
+		OrderBy("RequestAt DESC")
+	builder = sqlStore.applyInstallationFilestoreMigrationFilter(builder, filter)
+
+	return sqlStore.getInstallationFilestoreMigrationOperations(builder)
+}
+
+// GetUnlockedInstallationFilestoreMigrationOperationsPendingWork returns unlocked installation filestore migrations in a pending state.
+func (sqlStore *SQLStore) GetUnlockedInstallationFilestoreMigrationOperationsPendingWork() ([]*model.InstallationFilestoreMigrationOperation, error) {
+	builder := installationFilestoreMigrationSelect.
+		Where(sq.Eq{
+			"State": model.AllInstallationFilestoreMigrationOperationsStatesPendingWork,
+		}).
+		Where("LockAcquiredAt = 0").
+		OrderBy("RequestAt ASC")
+
+	return sqlStore.getInstallationFilestoreMigrationOperations(builder)
+}
+
+func (sqlStore *SQLStore) getInstallationFilestoreMigrationOperations(builder builder) ([]*model.InstallationFilestoreMigrationOperation, error) {
+	var migrationOps []*model.InstallationFilestoreMigrationOperation
+	err := sqlStore.selectBuilder(sqlStore.db, &migrationOps, builder)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query for installation filestore migrations")
+	}
+
+	return migrationOps, nil
+}
+
+// UpdateInstallationFilestoreMigrationOperationState updates the given installation filestore migration state.
+func (sqlStore *SQLStore) UpdateInstallationFilestoreMigrationOperationState(filestoreMigration *model.InstallationFilestoreMigrationOperation) error {
+	return sqlStore.updateInstallationFilestoreMigrationFields(
+		sqlStore.db,
+		filestoreMigration.ID, map[string]interface{}{
+			"State": filestoreMigration.State,
+		})
+}
+
+// UpdateInstallationFilestoreMigrationOperation updates the given installation filestore migration.
+func (sqlStore *SQLStore) UpdateInstallationFilestoreMigrationOperation(filestoreMigration *model.InstallationFilestoreMigrationOperation) error {
+	return sqlStore.updateInstallationFilestoreMigration(sqlStore.db, filestoreMigration)
+}
+
+func (sqlStore *SQLStore) updateInstallationFilestoreMigration(db execer, filestoreMigration *model.InstallationFilestoreMigrationOperation) error {
+	return sqlStore.updateInstallationFilestoreMigrationFields(
+		db,
+		filestoreMigration.ID, map[string]interface{}{
+			"State":             filestoreMigration.State,
+			"ObjectsTotal":      filestoreMigration.ObjectsTotal,
+			"BytesTotal":        filestoreMigration.BytesTotal,
+			"ObjectsCopied":     filestoreMigration.ObjectsCopied,
+			"BytesCopied":       filestoreMigration.BytesCopied,
+			"ContinuationToken": filestoreMigration.ContinuationToken,
+			"CompleteAt":        filestoreMigration.CompleteAt,
+		})
+}
+
+func (sqlStore *SQLStore) updateInstallationFilestoreMigrationFields(db execer, id string, fields map[string]interface{}) error {
+	_, err := sqlStore.execBuilder(db, sq.
+		Update(installationFilestoreMigrationTable).
+		SetMap(fields).
+		Where("ID = ?", id))
+	if err != nil {
+		return errors.Wrapf(err, "failed to update installation filestore migration fields: %s", getMapKeys(fields))
+	}
+
+	return nil
+}
+
+// DeleteInstallationFilestoreMigrationOperation marks the given migration operation as deleted,
+// but does not remove the record from the database.
+func (sqlStore *SQLStore) DeleteInstallationFilestoreMigrationOperation(id string) error {
+	_, err := sqlStore.execBuilder(sqlStore.db, sq.
+		Update(installationFilestoreMigrationTable).
+		Set("DeleteAt", model.GetMillis()).
+		Where("ID = ?", id).
+		Where("DeleteAt = ?", 0))
+	if err != nil {
+		return errors.Wrap(err, "failed to to mark filestore migration as deleted")
+	}
+
+	return nil
+}
+
+// LockInstallationFilestoreMigrationOperation marks the InstallationFilestoreMigrationOperation as locked for exclusive use by the caller.
+func (sqlStore *SQLStore) LockInstallationFilestoreMigrationOperation(id, lockerID string) (bool, error) {
+	return sqlStore.lockRows(installationFilestoreMigrationTable, []string{id}, lockerID)
+}
+
+// LockInstallationFilestoreMigrationOperations marks InstallationFilestoreMigrationOperation as locked for exclusive use by the caller.
+func (sqlStore *SQLStore) LockInstallationFilestoreMigrationOperations(ids []string, lockerID string) (bool, error) {
+	return sqlStore.lockRows(installationFilestoreMigrationTable, ids, lockerID)
+}
+
+// UnlockInstallationFilestoreMigrationOperation releases a lock previously acquired against a caller.
+func (sqlStore *SQLStore) UnlockInstallationFilestoreMigrationOperation(id, lockerID string, force bool) (bool, error) {
+	return sqlStore.unlockRows(installationFilestoreMigrationTable, []string{id}, lockerID, force)
+}
+
+// UnlockInstallationFilestoreMigrationOperations releases a locks previously acquired against a caller.
+func (sqlStore *SQLStore) UnlockInstallationFilestoreMigrationOperations(ids []string, lockerID string, force bool) (bool, error) {
+	return sqlStore.unlockRows(installationFilestoreMigrationTable, ids, lockerID, force)
+}
+
+func (sqlStore *SQLStore) applyInstallationFilestoreMigrationFilter(builder sq.SelectBuilder, filter *model.InstallationFilestoreMigrationFilter) sq.SelectBuilder {
+	builder = applyPagingFilter(builder, filter.Paging)
+
+	if len(filter.IDs) > 0 {
+		builder = builder.Where(sq.Eq{"ID": filter.IDs})
+	}
+	if filter.InstallationID != "" {
+		builder = builder.Where("InstallationID = ?", filter.InstallationID)
+	}
+	if len(filter.States) > 0 {
+		builder = builder.Where(sq.Eq{
+			"State": filter.States,
+		})
+	}
+
+	return builder
+}
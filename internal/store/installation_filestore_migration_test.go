@@ -0,0 +1,182 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTriggerInstallationFilestoreMigration(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := MakeTestSQLStore(t, logger)
+	defer CloseConnection(t, sqlStore)
+
+	installation := setupHibernatingInstallation(t, sqlStore)
+
+	filestoreMigrationOp := &model.InstallationFilestoreMigrationOperation{
+		SourceFilestore:      model.InstallationFilestoreAwsS3,
+		DestinationFilestore: model.InstallationFilestoreBifrost,
+	}
+
+	migrationOp, err := sqlStore.TriggerInstallationFilestoreMigration(filestoreMigrationOp, installation)
+	require.NoError(t, err)
+	assert.Equal(t, installation.ID, migrationOp.InstallationID)
+	assert.Equal(t, model.InstallationFilestoreMigrationStateRequested, migrationOp.State)
+
+	fetchOp, err := sqlStore.GetInstallationFilestoreMigrationOperation(migrationOp.ID)
+	require.NoError(t, err)
+	assert.Equal(t, migrationOp, fetchOp)
+
+	installation, err = sqlStore.GetInstallation(installation.ID, false, false)
+	require.NoError(t, err)
+	assert.Equal(t, model.InstallationStateFilestoreMigrationInProgress, installation.State)
+
+	t.Run("rollback", func(t *testing.T) {
+		err = sqlStore.TriggerInstallationFilestoreMigrationRollback(migrationOp, installation)
+		require.NoError(t, err)
+		assert.Equal(t, model.InstallationFilestoreMigrationStateRollbackRequested, migrationOp.State)
+
+		fetchOp, err = sqlStore.GetInstallationFilestoreMigrationOperation(migrationOp.ID)
+		require.NoError(t, err)
+		assert.Equal(t, migrationOp, fetchOp)
+
+		installation, err = sqlStore.GetInstallation(installation.ID, false, false)
+		require.NoError(t, err)
+		assert.Equal(t, model.InstallationStateFilestoreMigrationRollbackInProgress, installation.State)
+	})
+}
+
+func TestInstallationFilestoreMigrationOperation(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := MakeTestSQLStore(t, logger)
+	defer CloseConnection(t, sqlStore)
+
+	installation := setupHibernatingInstallation(t, sqlStore)
+
+	filestoreMigrationOp := &model.InstallationFilestoreMigrationOperation{
+		InstallationID:       installation.ID,
+		SourceFilestore:      model.InstallationFilestoreAwsS3,
+		DestinationFilestore: model.InstallationFilestoreBifrost,
+		State:                model.InstallationFilestoreMigrationStateCopyInProgress,
+	}
+
+	err := sqlStore.CreateInstallationFilestoreMigrationOperation(filestoreMigrationOp)
+	require.NoError(t, err)
+	assert.NotEmpty(t, filestoreMigrationOp.ID)
+
+	fetchedMigration, err := sqlStore.GetInstallationFilestoreMigrationOperation(filestoreMigrationOp.ID)
+	require.NoError(t, err)
+	assert.Equal(t, filestoreMigrationOp, fetchedMigration)
+
+	t.Run("update progress", func(t *testing.T) {
+		filestoreMigrationOp.ObjectsTotal = 10
+		filestoreMigrationOp.BytesTotal = 1000
+		filestoreMigrationOp.ObjectsCopied = 5
+		filestoreMigrationOp.BytesCopied = 500
+		filestoreMigrationOp.ContinuationToken = "token"
+
+		err = sqlStore.UpdateInstallationFilestoreMigrationOperation(filestoreMigrationOp)
+		require.NoError(t, err)
+
+		fetchedMigration, err = sqlStore.GetInstallationFilestoreMigrationOperation(filestoreMigrationOp.ID)
+		require.NoError(t, err)
+		assert.Equal(t, filestoreMigrationOp, fetchedMigration)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		err = sqlStore.DeleteInstallationFilestoreMigrationOperation(filestoreMigrationOp.ID)
+		require.NoError(t, err)
+
+		fetchedMigration, err = sqlStore.GetInstallationFilestoreMigrationOperation(filestoreMigrationOp.ID)
+		require.NoError(t, err)
+		assert.NotEqual(t, int64(0), fetchedMigration.DeleteAt)
+	})
+
+	t.Run("unknown migration", func(t *testing.T) {
+		fetchedMigration, err = sqlStore.GetInstallationFilestoreMigrationOperation("unknown")
+		require.NoError(t, err)
+		assert.Nil(t, fetchedMigration)
+	})
+}
+
+func TestGetInstallationFilestoreMigrations(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := MakeTestSQLStore(t, logger)
+	defer CloseConnection(t, sqlStore)
+
+	installation1 := setupHibernatingInstallation(t, sqlStore)
+	installation2 := setupHibernatingInstallation(t, sqlStore)
+
+	filestoreMigrations := []*model.InstallationFilestoreMigrationOperation{
+		{InstallationID: installation1.ID, State: model.InstallationFilestoreMigrationStateRequested},
+		{InstallationID: installation1.ID, State: model.InstallationFilestoreMigrationStateCopyInProgress},
+		{InstallationID: installation1.ID, State: model.InstallationFilestoreMigrationStateFailed},
+		{InstallationID: installation2.ID, State: model.InstallationFilestoreMigrationStateRequested},
+		{InstallationID: installation2.ID, State: model.InstallationFilestoreMigrationStateSucceeded},
+	}
+
+	for i := range filestoreMigrations {
+		err := sqlStore.CreateInstallationFilestoreMigrationOperation(filestoreMigrations[i])
+		require.NoError(t, err)
+		time.Sleep(1 * time.Millisecond) // Ensure RequestAt is different for all operations.
+	}
+
+	for _, testCase := range []struct {
+		description string
+		filter      *model.InstallationFilestoreMigrationFilter
+		fetchedIds  []string
+	}{
+		{
+			description: "fetch all",
+			filter:      &model.InstallationFilestoreMigrationFilter{Paging: model.AllPagesNotDeleted()},
+			fetchedIds:  []string{filestoreMigrations[4].ID, filestoreMigrations[3].ID, filestoreMigrations[2].ID, filestoreMigrations[1].ID, filestoreMigrations[0].ID},
+		},
+		{
+			description: "fetch all for installation 1",
+			filter:      &model.InstallationFilestoreMigrationFilter{InstallationID: installation1.ID, Paging: model.AllPagesNotDeleted()},
+			fetchedIds:  []string{filestoreMigrations[2].ID, filestoreMigrations[1].ID, filestoreMigrations[0].ID},
+		},
+		{
+			description: "fetch requested operations",
+			filter:      &model.InstallationFilestoreMigrationFilter{States: []model.InstallationFilestoreMigrationOperationState{model.InstallationFilestoreMigrationStateRequested}, Paging: model.AllPagesNotDeleted()},
+			fetchedIds:  []string{filestoreMigrations[3].ID, filestoreMigrations[0].ID},
+		},
+		{
+			description: "fetch with IDs",
+			filter:      &model.InstallationFilestoreMigrationFilter{IDs: []string{filestoreMigrations[0].ID, filestoreMigrations[4].ID}, Paging: model.AllPagesNotDeleted()},
+			fetchedIds:  []string{filestoreMigrations[4].ID, filestoreMigrations[0].ID},
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			fetchedMigrations, err := sqlStore.GetInstallationFilestoreMigrationOperations(testCase.filter)
+			require.NoError(t, err)
+			assert.Equal(t, len(testCase.fetchedIds), len(fetchedMigrations))
+
+			for i, m := range fetchedMigrations {
+				assert.Equal(t, testCase.fetchedIds[i], m.ID)
+			}
+		})
+	}
+
+	t.Run("pending work", func(t *testing.T) {
+		pending, err := sqlStore.GetUnlockedInstallationFilestoreMigrationOperationsPendingWork()
+		require.NoError(t, err)
+		assert.Equal(t, 3, len(pending))
+
+		locked, err := sqlStore.LockInstallationFilestoreMigrationOperation(filestoreMigrations[0].ID, "abc")
+		require.NoError(t, err)
+		assert.True(t, locked)
+
+		pending, err = sqlStore.GetUnlockedInstallationFilestoreMigrationOperationsPendingWork()
+		require.NoError(t, err)
+		assert.Equal(t, 2, len(pending))
+	})
+}
@@ -0,0 +1,108 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package store
+
+import (
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+)
+
+const (
+	installationUsageTable = "InstallationUsage"
+)
+
+var installationUsageSelect sq.SelectBuilder
+
+func init() {
+	installationUsageSelect = sq.
+		Select(
+			"ID",
+			"InstallationID",
+			"ClusterID",
+			"PodCount",
+			"MilliCPU",
+			"MemoryBytes",
+			"VolumeBytes",
+			"FilestoreBytes",
+			"FilestoreMeasureAt",
+			"DatabaseInstanceClasses",
+			"DatabaseShare",
+			"CreateAt",
+		).
+		From(installationUsageTable)
+}
+
+// CreateInstallationUsage records the supplied installation usage sample to
+// the datastore, assigning it a unique ID.
+func (sqlStore *SQLStore) CreateInstallationUsage(usage *model.InstallationUsage) error {
+	usage.ID = model.NewID()
+	usage.CreateAt = model.GetMillis()
+
+	_, err := sqlStore.execBuilder(sqlStore.db, sq.
+		Insert(installationUsageTable).
+		SetMap(map[string]interface{}{
+			"ID":                      usage.ID,
+			"InstallationID":          usage.InstallationID,
+			"ClusterID":               usage.ClusterID,
+			"PodCount":                usage.PodCount,
+			"MilliCPU":                usage.MilliCPU,
+			"MemoryBytes":             usage.MemoryBytes,
+			"VolumeBytes":             usage.VolumeBytes,
+			"FilestoreBytes":          usage.FilestoreBytes,
+			"FilestoreMeasureAt":      usage.FilestoreMeasureAt,
+			"DatabaseInstanceClasses": usage.DatabaseInstanceClasses,
+			"DatabaseShare":           usage.DatabaseShare,
+			"CreateAt":                usage.CreateAt,
+		}),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to create installation usage")
+	}
+
+	return nil
+}
+
+// GetInstallationUsages fetches the installation usage samples matching the
+// filter, oldest first.
+func (sqlStore *SQLStore) GetInstallationUsages(filter *model.InstallationUsageFilter) ([]*model.InstallationUsage, error) {
+	builder := installationUsageSelect.
+		OrderBy("CreateAt ASC")
+
+	if len(filter.InstallationID) > 0 {
+		builder = builder.Where("InstallationID = ?", filter.InstallationID)
+	}
+	if len(filter.InstallationIDs) > 0 {
+		builder = builder.Where(sq.Eq{"InstallationID": filter.InstallationIDs})
+	}
+	if filter.CreatedAfter > 0 {
+		builder = builder.Where("CreateAt >= ?", filter.CreatedAfter)
+	}
+	if filter.CreatedBefore > 0 {
+		builder = builder.Where("CreateAt <= ?", filter.CreatedBefore)
+	}
+
+	usages := []*model.InstallationUsage{}
+	err := sqlStore.selectBuilder(sqlStore.db, &usages, builder)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query for installation usages")
+	}
+
+	return usages, nil
+}
+
+// DeleteInstallationUsagesOlderThan removes the installation usage samples
+// recorded before the given time and returns the number of samples removed.
+func (sqlStore *SQLStore) DeleteInstallationUsagesOlderThan(millis int64) (int64, error) {
+	deleted, err := sqlStore.deleteRows(sqlStore.db, sq.
+		Delete(installationUsageTable).
+		Where("CreateAt < ?", millis),
+	)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to delete installation usages")
+	}
+
+	return deleted, nil
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstallationUsage(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := MakeTestSQLStore(t, logger)
+	defer CloseConnection(t, sqlStore)
+
+	usages := []*model.InstallationUsage{
+		{
+			InstallationID:          "installation1",
+			ClusterID:               "cluster1",
+			PodCount:                2,
+			MilliCPU:                200,
+			MemoryBytes:             1000,
+			VolumeBytes:             3000,
+			FilestoreBytes:          10,
+			FilestoreMeasureAt:      model.GetMillis(),
+			DatabaseInstanceClasses: model.DatabaseInstanceClasses{"db.r5.large"},
+			DatabaseShare:           1,
+		},
+		{InstallationID: "installation2", ClusterID: "cluster1", DatabaseShare: 0.25},
+		{InstallationID: "installation1", ClusterID: "cluster1", MilliCPU: 300},
+	}
+	for _, usage := range usages {
+		err := sqlStore.CreateInstallationUsage(usage)
+		require.NoError(t, err)
+		assert.NotEmpty(t, usage.ID)
+		assert.NotZero(t, usage.CreateAt)
+		time.Sleep(1 * time.Millisecond)
+	}
+
+	for _, testCase := range []struct {
+		description string
+		filter      *model.InstallationUsageFilter
+		expected    []*model.InstallationUsage
+	}{
+		{
+			description: "fetch all",
+			filter:      &model.InstallationUsageFilter{},
+			expected:    usages,
+		},
+		{
+			description: "fetch by installation",
+			filter:      &model.InstallationUsageFilter{InstallationID: "installation1"},
+			expected:    []*model.InstallationUsage{usages[0], usages[2]},
+		},
+		{
+			description: "fetch by installations",
+			filter:      &model.InstallationUsageFilter{InstallationIDs: []string{"installation2", "unknown"}},
+			expected:    []*model.InstallationUsage{usages[1]},
+		},
+		{
+			description: "fetch created after",
+			filter:      &model.InstallationUsageFilter{CreatedAfter: usages[1].CreateAt},
+			expected:    []*model.InstallationUsage{usages[1], usages[2]},
+		},
+		{
+			description: "fetch created before",
+			filter:      &model.InstallationUsageFilter{CreatedBefore: usages[1].CreateAt},
+			expected:    []*model.InstallationUsage{usages[0], usages[1]},
+		},
+		{
+			description: "fetch unknown installation",
+			filter:      &model.InstallationUsageFilter{InstallationID: "unknown"},
+			expected:    []*model.InstallationUsage{},
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			fetchedUsages, err := sqlStore.GetInstallationUsages(testCase.filter)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expected, fetchedUsages)
+		})
+	}
+
+	t.Run("delete older than", func(t *testing.T) {
+		deleted, err := sqlStore.DeleteInstallationUsagesOlderThan(usages[1].CreateAt)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), deleted)
+
+		fetchedUsages, err := sqlStore.GetInstallationUsages(&model.InstallationUsageFilter{})
+		require.NoError(t, err)
+		assert.Equal(t, []*model.InstallationUsage{usages[1], usages[2]}, fetchedUsages)
+
+		deleted, err = sqlStore.DeleteInstallationUsagesOlderThan(usages[1].CreateAt)
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), deleted)
+	})
+}
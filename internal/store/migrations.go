@@ -2293,6 +2293,250 @@ var migrations = []migration{
 			return errors.Wrap(err, "failed to create Command column")
 		}
 
+		return nil
+	}},
+	{semver.MustParse("0.54.0"), semver.MustParse("0.55.0"), func(e execer) error {
+		_, err := e.Exec(`
+			CREATE TABLE MultitenantDatabaseRebalance (
+				ID TEXT PRIMARY KEY,
+				VpcID TEXT NOT NULL,
+				DatabaseType TEXT NOT NULL,
+				State TEXT NOT NULL,
+				MaxConcurrentMigrations INT NOT NULL,
+				Moves JSON DEFAULT NULL,
+				RequestAt BIGINT NOT NULL,
+				CompleteAt BIGINT NOT NULL,
+				DeleteAt BIGINT NOT NULL,
+				LockAcquiredBy TEXT NULL,
+				LockAcquiredAt BIGINT NOT NULL
+			);
+		`)
+		if err != nil {
+			return errors.Wrap(err, "failed to create MultitenantDatabaseRebalance table")
+		}
+
+		return nil
+	}}, {semver.MustParse("0.55.0"), semver.MustParse("0.56.0"), func(e execer) error {
+		_, err := e.Exec(`
+			CREATE TABLE InstallationFilestoreMigrationOperation (
+				ID TEXT PRIMARY KEY,
+				InstallationID TEXT NOT NULL,
+				RequestAt BIGINT NOT NULL,
+				State TEXT NOT NULL,
+				SourceFilestore TEXT NOT NULL,
+				DestinationFilestore TEXT NOT NULL,
+				ObjectsTotal BIGINT NOT NULL,
+				BytesTotal BIGINT NOT NULL,
+				ObjectsCopied BIGINT NOT NULL,
+				BytesCopied BIGINT NOT NULL,
+				ContinuationToken TEXT NOT NULL,
+				CompleteAt BIGINT NOT NULL,
+				DeleteAt BIGINT NOT NULL,
+				LockAcquiredBy TEXT NULL,
+				LockAcquiredAt BIGINT NOT NULL
+			);
+		`)
+		if err != nil {
+			return errors.Wrap(err, "failed to create InstallationFilestoreMigrationOperation table")
+		}
+
+		return nil
+	}}, {semver.MustParse("0.56.0"), semver.MustParse("0.57.0"), func(e execer) error {
+		_, err := e.Exec(`
+			ALTER TABLE InstallationDBRestorationOperation
+			ADD COLUMN Type TEXT NOT NULL DEFAULT 'backup';
+		`)
+		if err != nil {
+			return errors.Wrap(err, "failed to create Type column")
+		}
+
+		_, err = e.Exec(`
+			ALTER TABLE InstallationDBRestorationOperation
+			ADD COLUMN RestoreTime BIGINT NOT NULL DEFAULT '0';
+		`)
+		if err != nil {
+			return errors.Wrap(err, "failed to create RestoreTime column")
+		}
+
+		return nil
+	}}, {semver.MustParse("0.57.0"), semver.MustParse("0.58.0"), func(e execer) error {
+		_, err := e.Exec(`ALTER TABLE Subscription ADD COLUMN Filter JSONB NULL;`)
+		if err != nil {
+			return errors.Wrap(err, "failed to create Filter column")
+		}
+
+		return nil
+	}}, {semver.MustParse("0.58.0"), semver.MustParse("0.59.0"), func(e execer) error {
+		_, err := e.Exec(`ALTER TABLE Subscription ADD COLUMN Paused BOOLEAN NOT NULL DEFAULT FALSE;`)
+		if err != nil {
+			return errors.Wrap(err, "failed to create Paused column")
+		}
+
+		_, err = e.Exec(`ALTER TABLE EventDelivery ADD COLUMN ReplayedAt BIGINT NOT NULL DEFAULT '0';`)
+		if err != nil {
+			return errors.Wrap(err, "failed to create ReplayedAt column")
+		}
+
+		return nil
+	}}, {semver.MustParse("0.59.0"), semver.MustParse("0.60.0"), func(e execer) error {
+		_, err := e.Exec(`ALTER TABLE Subscription ADD COLUMN PayloadFormat TEXT NOT NULL DEFAULT '';`)
+		if err != nil {
+			return errors.Wrap(err, "failed to create PayloadFormat column")
+		}
+
+		return nil
+	}}, {semver.MustParse("0.60.0"), semver.MustParse("0.61.0"), func(e execer) error {
+		_, err := e.Exec(`ALTER TABLE Subscription ADD COLUMN SinkType TEXT NOT NULL DEFAULT 'http';`)
+		if err != nil {
+			return errors.Wrap(err, "failed to create SinkType column")
+		}
+
+		return nil
+	}}, {semver.MustParse("0.61.0"), semver.MustParse("0.62.0"), func(e execer) error {
+		_, err := e.Exec(`
+			CREATE TABLE UtilityRollout (
+				ID TEXT PRIMARY KEY,
+				Utility TEXT NOT NULL,
+				Version TEXT NOT NULL,
+				ValuesPath TEXT NOT NULL,
+				State TEXT NOT NULL,
+				MaxConcurrentClusters INT NOT NULL,
+				ClusterTimeoutSeconds BIGINT NOT NULL,
+				Clusters JSON DEFAULT NULL,
+				RequestAt BIGINT NOT NULL,
+				CompleteAt BIGINT NOT NULL,
+				DeleteAt BIGINT NOT NULL,
+				LockAcquiredBy TEXT NULL,
+				LockAcquiredAt BIGINT NOT NULL
+			);
+		`)
+		if err != nil {
+			return errors.Wrap(err, "failed to create UtilityRollout table")
+		}
+
+		return nil
+	}}, {semver.MustParse("0.62.0"), semver.MustParse("0.63.0"), func(e execer) error {
+		_, err := e.Exec(`
+			CREATE TABLE ClusterUpgrade (
+				ID TEXT PRIMARY KEY,
+				ClusterID TEXT NOT NULL,
+				Version TEXT NOT NULL,
+				State TEXT NOT NULL,
+				Phase TEXT NOT NULL,
+				SkipPreflight BOOLEAN NOT NULL,
+				Preflight JSON DEFAULT NULL,
+				NodeGroups JSON DEFAULT NULL,
+				Verification JSON DEFAULT NULL,
+				Message TEXT NOT NULL,
+				CreateAt BIGINT NOT NULL,
+				UpdateAt BIGINT NOT NULL,
+				CompleteAt BIGINT NOT NULL
+			);
+		`)
+		if err != nil {
+			return errors.Wrap(err, "failed to create ClusterUpgrade table")
+		}
+
+		_, err = e.Exec(`CREATE INDEX ix_ClusterUpgrade_ClusterID ON ClusterUpgrade (ClusterID);`)
+		if err != nil {
+			return errors.Wrap(err, "failed to create ClusterUpgrade ClusterID index")
+		}
+
+		return nil
+	}}, {semver.MustParse("0.63.0"), semver.MustParse("0.64.0"), func(e execer) error {
+		_, err := e.Exec(`
+			CREATE TABLE ClusterTemplate (
+				ID TEXT PRIMARY KEY,
+				Name TEXT NOT NULL,
+				Description TEXT NOT NULL,
+				Version BIGINT NOT NULL,
+				Spec JSON DEFAULT NULL,
+				CreateAt BIGINT NOT NULL,
+				UpdateAt BIGINT NOT NULL,
+				DeleteAt BIGINT NOT NULL
+			);
+		`)
+		if err != nil {
+			return errors.Wrap(err, "failed to create ClusterTemplate table")
+		}
+
+		_, err = e.Exec(`CREATE UNIQUE INDEX ClusterTemplate_Name_DeleteAt ON ClusterTemplate (Name, DeleteAt);`)
+		if err != nil {
+			return errors.Wrap(err, "failed to create ClusterTemplate Name index")
+		}
+
+		_, err = e.Exec(`ALTER TABLE Cluster ADD COLUMN TemplateID TEXT NOT NULL DEFAULT '';`)
+		if err != nil {
+			return errors.Wrap(err, "failed to add TemplateID column to Cluster table")
+		}
+
+		_, err = e.Exec(`ALTER TABLE Cluster ADD COLUMN TemplateVersion BIGINT NOT NULL DEFAULT 0;`)
+		if err != nil {
+			return errors.Wrap(err, "failed to add TemplateVersion column to Cluster table")
+		}
+
+		return nil
+	}}, {semver.MustParse("0.64.0"), semver.MustParse("0.65.0"), func(e execer) error {
+		_, err := e.Exec(`
+			CREATE TABLE SizeProfile (
+				ID TEXT PRIMARY KEY,
+				Name TEXT NOT NULL,
+				Description TEXT NOT NULL,
+				Spec JSON DEFAULT NULL,
+				CreateAt BIGINT NOT NULL,
+				UpdateAt BIGINT NOT NULL,
+				DeleteAt BIGINT NOT NULL
+			);
+		`)
+		if err != nil {
+			return errors.Wrap(err, "failed to create SizeProfile table")
+		}
+
+		_, err = e.Exec(`CREATE UNIQUE INDEX SizeProfile_Name_DeleteAt ON SizeProfile (Name, DeleteAt);`)
+		if err != nil {
+			return errors.Wrap(err, "failed to create SizeProfile Name index")
+		}
+
+		return nil
+	}}, {semver.MustParse("0.65.0"), semver.MustParse("0.66.0"), func(e execer) error {
+		_, err := e.Exec(`
+			CREATE TABLE InstallationUsage (
+				ID TEXT PRIMARY KEY,
+				InstallationID TEXT NOT NULL,
+				ClusterID TEXT NOT NULL,
+				PodCount BIGINT NOT NULL,
+				MilliCPU BIGINT NOT NULL,
+				MemoryBytes BIGINT NOT NULL,
+				VolumeBytes BIGINT NOT NULL,
+				FilestoreBytes BIGINT NOT NULL,
+				FilestoreMeasureAt BIGINT NOT NULL,
+				DatabaseInstanceClasses JSON DEFAULT NULL,
+				DatabaseShare DOUBLE PRECISION NOT NULL,
+				CreateAt BIGINT NOT NULL
+			);
+		`)
+		if err != nil {
+			return errors.Wrap(err, "failed to create InstallationUsage table")
+		}
+
+		_, err = e.Exec(`CREATE INDEX InstallationUsage_InstallationID_CreateAt ON InstallationUsage (InstallationID, CreateAt);`)
+		if err != nil {
+			return errors.Wrap(err, "failed to create InstallationUsage InstallationID index")
+		}
+
+		_, err = e.Exec(`CREATE INDEX InstallationUsage_CreateAt ON InstallationUsage (CreateAt);`)
+		if err != nil {
+			return errors.Wrap(err, "failed to create InstallationUsage CreateAt index")
+		}
+
+		return nil
+	}},
+	{semver.MustParse("0.66.0"), semver.MustParse("0.67.0"), func(e execer) error {
+		_, err := e.Exec(`ALTER TABLE Installation ADD COLUMN NetworkPolicyExceptions JSON DEFAULT NULL;`)
+		if err != nil {
+			return errors.Wrap(err, "failed to create NetworkPolicyExceptions column")
+		}
+
 		return nil
 	}},
 }
@@ -0,0 +1,128 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package store
+
+import (
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+)
+
+const (
+	sizeProfileTable = "SizeProfile"
+)
+
+var sizeProfileSelect sq.SelectBuilder
+
+func init() {
+	sizeProfileSelect = sq.
+		Select(
+			"ID",
+			"Name",
+			"Description",
+			"Spec",
+			"CreateAt",
+			"UpdateAt",
+			"DeleteAt",
+		).
+		From(sizeProfileTable)
+}
+
+// CreateSizeProfile records the supplied size profile to the
+// datastore, assigning it a unique ID.
+func (sqlStore *SQLStore) CreateSizeProfile(profile *model.SizeProfile) error {
+	profile.ID = model.NewID()
+	profile.CreateAt = model.GetMillis()
+	profile.UpdateAt = profile.CreateAt
+
+	_, err := sqlStore.execBuilder(sqlStore.db, sq.
+		Insert(sizeProfileTable).
+		SetMap(map[string]interface{}{
+			"ID":          profile.ID,
+			"Name":        profile.Name,
+			"Description": profile.Description,
+			"Spec":        profile.Spec,
+			"CreateAt":    profile.CreateAt,
+			"UpdateAt":    profile.UpdateAt,
+			"DeleteAt":    0,
+		}),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to create size profile")
+	}
+
+	return nil
+}
+
+// GetSizeProfileByName fetches the size profile with the given name
+// that is not deleted.
+func (sqlStore *SQLStore) GetSizeProfileByName(name string) (*model.SizeProfile, error) {
+	var profile model.SizeProfile
+	builder := sizeProfileSelect.
+		Where("Name = ?", name).
+		Where("DeleteAt = 0")
+	err := sqlStore.getBuilder(sqlStore.db, &profile, builder)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "failed to get size profile by name")
+	}
+
+	return &profile, nil
+}
+
+// GetSizeProfiles fetches the given page of size profiles. The first
+// page is 0.
+func (sqlStore *SQLStore) GetSizeProfiles(filter *model.SizeProfileFilter) ([]*model.SizeProfile, error) {
+	builder := sizeProfileSelect.
+		OrderBy("Name ASC")
+	builder = applyPagingFilter(builder, filter.Paging)
+
+	profiles := []*model.SizeProfile{}
+	err := sqlStore.selectBuilder(sqlStore.db, &profiles, builder)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query for size profiles")
+	}
+
+	return profiles, nil
+}
+
+// UpdateSizeProfile updates the given size profile in the database.
+func (sqlStore *SQLStore) UpdateSizeProfile(profile *model.SizeProfile) error {
+	profile.UpdateAt = model.GetMillis()
+
+	_, err := sqlStore.execBuilder(sqlStore.db, sq.
+		Update(sizeProfileTable).
+		SetMap(map[string]interface{}{
+			"Description": profile.Description,
+			"Spec":        profile.Spec,
+			"UpdateAt":    profile.UpdateAt,
+		}).
+		Where("ID = ?", profile.ID),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to update size profile")
+	}
+
+	return nil
+}
+
+// DeleteSizeProfile marks the given size profile as deleted, but
+// does not remove the record from the database.
+func (sqlStore *SQLStore) DeleteSizeProfile(id string) error {
+	_, err := sqlStore.execBuilder(sqlStore.db, sq.
+		Update(sizeProfileTable).
+		Set("DeleteAt", model.GetMillis()).
+		Where("ID = ?", id).
+		Where("DeleteAt = 0"),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to mark size profile as deleted")
+	}
+
+	return nil
+}
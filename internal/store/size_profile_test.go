@@ -0,0 +1,143 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package store
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func testSizeProfileSpec(replicas int32) *model.SizeProfileSpec {
+	return &model.SizeProfileSpec{
+		App: model.SizeProfileComponent{
+			Replicas: replicas,
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("500m"),
+					corev1.ResourceMemory: resource.MustParse("2Gi"),
+				},
+			},
+		},
+	}
+}
+
+func TestSizeProfiles(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := MakeTestSQLStore(t, logger)
+	defer CloseConnection(t, sqlStore)
+
+	profile1 := &model.SizeProfile{
+		Name:        "custom",
+		Description: "custom size",
+		Spec:        testSizeProfileSpec(2),
+	}
+	profile2 := &model.SizeProfile{
+		Name: "another",
+		Spec: testSizeProfileSpec(1),
+	}
+
+	err := sqlStore.CreateSizeProfile(profile1)
+	require.NoError(t, err)
+	assert.NotEmpty(t, profile1.ID)
+
+	err = sqlStore.CreateSizeProfile(profile2)
+	require.NoError(t, err)
+
+	t.Run("get by name", func(t *testing.T) {
+		fetchedProfile, err := sqlStore.GetSizeProfileByName(profile1.Name)
+		require.NoError(t, err)
+		assert.Equal(t, profile1.ID, fetchedProfile.ID)
+		assert.Equal(t, profile1.Description, fetchedProfile.Description)
+		assert.Equal(t, int32(2), fetchedProfile.Spec.App.Replicas)
+		assert.True(t, fetchedProfile.Spec.App.Resources.Requests.Memory().Equal(resource.MustParse("2Gi")))
+	})
+
+	t.Run("get unknown profile", func(t *testing.T) {
+		fetchedProfile, err := sqlStore.GetSizeProfileByName("unknown")
+		require.NoError(t, err)
+		assert.Nil(t, fetchedProfile)
+	})
+
+	t.Run("get profiles", func(t *testing.T) {
+		profiles, err := sqlStore.GetSizeProfiles(&model.SizeProfileFilter{Paging: model.AllPagesNotDeleted()})
+		require.NoError(t, err)
+		require.Len(t, profiles, 2)
+		assert.Equal(t, profile2.ID, profiles[0].ID)
+		assert.Equal(t, profile1.ID, profiles[1].ID)
+
+		profiles, err = sqlStore.GetSizeProfiles(&model.SizeProfileFilter{Paging: model.Paging{Page: 1, PerPage: 1}})
+		require.NoError(t, err)
+		require.Len(t, profiles, 1)
+		assert.Equal(t, profile1.ID, profiles[0].ID)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		profile1.Description = "updated"
+		profile1.Spec = testSizeProfileSpec(4)
+
+		err := sqlStore.UpdateSizeProfile(profile1)
+		require.NoError(t, err)
+
+		fetchedProfile, err := sqlStore.GetSizeProfileByName(profile1.Name)
+		require.NoError(t, err)
+		assert.Equal(t, "updated", fetchedProfile.Description)
+		assert.Equal(t, int32(4), fetchedProfile.Spec.App.Replicas)
+		assert.Equal(t, profile1.UpdateAt, fetchedProfile.UpdateAt)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		err := sqlStore.DeleteSizeProfile(profile2.ID)
+		require.NoError(t, err)
+
+		fetchedProfile, err := sqlStore.GetSizeProfileByName(profile2.Name)
+		require.NoError(t, err)
+		assert.Nil(t, fetchedProfile)
+
+		profiles, err := sqlStore.GetSizeProfiles(&model.SizeProfileFilter{Paging: model.AllPagesNotDeleted()})
+		require.NoError(t, err)
+		require.Len(t, profiles, 1)
+		assert.Equal(t, profile1.ID, profiles[0].ID)
+
+		recreated := &model.SizeProfile{Name: profile2.Name, Spec: testSizeProfileSpec(1)}
+		err = sqlStore.CreateSizeProfile(recreated)
+		require.NoError(t, err)
+	})
+}
+
+func TestGetInstallationsBySize(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := MakeTestSQLStore(t, logger)
+	defer CloseConnection(t, sqlStore)
+
+	for _, installation := range []*model.Installation{
+		{Name: "size1", Size: "custom"},
+		{Name: "size2", Size: "custom-6"},
+		{Name: "size3", Size: "customer"},
+		{Name: "size4", Size: "1000users"},
+	} {
+		err := sqlStore.CreateInstallation(installation, nil, nil)
+		require.NoError(t, err)
+	}
+
+	count, err := sqlStore.GetInstallationsCount(&model.InstallationFilter{
+		Paging: model.AllPagesNotDeleted(),
+		Size:   "custom",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	count, err = sqlStore.GetInstallationsCount(&model.InstallationFilter{
+		Paging: model.AllPagesNotDeleted(),
+		Size:   "unknown",
+	})
+	require.NoError(t, err)
+	assert.Zero(t, count)
+}
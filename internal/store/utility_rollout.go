@@ -0,0 +1,152 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package store
+
+import (
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+)
+
+const (
+	utilityRolloutTable = "UtilityRollout"
+)
+
+var utilityRolloutSelect sq.SelectBuilder
+
+func init() {
+	utilityRolloutSelect = sq.
+		Select(
+			"ID",
+			"Utility",
+			"Version",
+			"ValuesPath",
+			"State",
+			"MaxConcurrentClusters",
+			"ClusterTimeoutSeconds",
+			"Clusters",
+			"RequestAt",
+			"CompleteAt",
+			"DeleteAt",
+			"LockAcquiredBy",
+			"LockAcquiredAt",
+		).
+		From(utilityRolloutTable)
+}
+
+// CreateUtilityRollout records the supplied utility rollout to the
+// datastore, assigning it a unique ID.
+func (sqlStore *SQLStore) CreateUtilityRollout(rollout *model.UtilityRollout) error {
+	rollout.ID = model.NewID()
+	rollout.RequestAt = model.GetMillis()
+
+	_, err := sqlStore.execBuilder(sqlStore.db, sq.
+		Insert(utilityRolloutTable).
+		SetMap(map[string]interface{}{
+			"ID":                    rollout.ID,
+			"Utility":               rollout.Utility,
+			"Version":               rollout.Version,
+			"ValuesPath":            rollout.ValuesPath,
+			"State":                 rollout.State,
+			"MaxConcurrentClusters": rollout.MaxConcurrentClusters,
+			"ClusterTimeoutSeconds": rollout.ClusterTimeoutSeconds,
+			"Clusters":              rollout.Clusters,
+			"RequestAt":             rollout.RequestAt,
+			"CompleteAt":            rollout.CompleteAt,
+			"DeleteAt":              0,
+			"LockAcquiredBy":        nil,
+			"LockAcquiredAt":        0,
+		}),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to create utility rollout")
+	}
+
+	return nil
+}
+
+// GetUtilityRollout fetches the given utility rollout.
+func (sqlStore *SQLStore) GetUtilityRollout(id string) (*model.UtilityRollout, error) {
+	var rollout model.UtilityRollout
+	err := sqlStore.getBuilder(sqlStore.db, &rollout, utilityRolloutSelect.Where("ID = ?", id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "failed to get utility rollout by id")
+	}
+
+	return &rollout, nil
+}
+
+// GetUtilityRollouts fetches the given page of utility rollouts. The first
+// page is 0.
+func (sqlStore *SQLStore) GetUtilityRollouts(filter *model.UtilityRolloutFilter) ([]*model.UtilityRollout, error) {
+	builder := utilityRolloutSelect.
+		OrderBy("RequestAt DESC")
+	builder = applyPagingFilter(builder, filter.Paging)
+
+	if len(filter.Utility) > 0 {
+		builder = builder.Where(sq.Eq{"Utility": filter.Utility})
+	}
+	if len(filter.States) > 0 {
+		builder = builder.Where(sq.Eq{"State": filter.States})
+	}
+
+	return sqlStore.getUtilityRollouts(builder)
+}
+
+// GetUnlockedUtilityRolloutsPendingWork returns unlocked utility rollouts in
+// a pending state.
+func (sqlStore *SQLStore) GetUnlockedUtilityRolloutsPendingWork() ([]*model.UtilityRollout, error) {
+	builder := utilityRolloutSelect.
+		Where(sq.Eq{
+			"State": model.AllUtilityRolloutStatesPendingWork,
+		}).
+		Where("LockAcquiredAt = 0").
+		Where("DeleteAt = 0").
+		OrderBy("RequestAt ASC")
+
+	return sqlStore.getUtilityRollouts(builder)
+}
+
+func (sqlStore *SQLStore) getUtilityRollouts(builder builder) ([]*model.UtilityRollout, error) {
+	rollouts := []*model.UtilityRollout{}
+	err := sqlStore.selectBuilder(sqlStore.db, &rollouts, builder)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query for utility rollouts")
+	}
+
+	return rollouts, nil
+}
+
+// UpdateUtilityRollout updates the given utility rollout.
+func (sqlStore *SQLStore) UpdateUtilityRollout(rollout *model.UtilityRollout) error {
+	_, err := sqlStore.execBuilder(sqlStore.db, sq.
+		Update(utilityRolloutTable).
+		SetMap(map[string]interface{}{
+			"State":      rollout.State,
+			"Clusters":   rollout.Clusters,
+			"CompleteAt": rollout.CompleteAt,
+		}).
+		Where("ID = ?", rollout.ID),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to update utility rollout")
+	}
+
+	return nil
+}
+
+// LockUtilityRollouts marks the rollouts as locked for exclusive use by the caller.
+func (sqlStore *SQLStore) LockUtilityRollouts(ids []string, lockerID string) (bool, error) {
+	return sqlStore.lockRows(utilityRolloutTable, ids, lockerID)
+}
+
+// UnlockUtilityRollouts releases locks previously acquired against a caller.
+func (sqlStore *SQLStore) UnlockUtilityRollouts(ids []string, lockerID string, force bool) (bool, error) {
+	return sqlStore.unlockRows(utilityRolloutTable, ids, lockerID, force)
+}
@@ -0,0 +1,151 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUtilityRollout(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := MakeTestSQLStore(t, logger)
+	defer CloseConnection(t, sqlStore)
+
+	rollout := &model.UtilityRollout{
+		Utility:               model.NginxCanonicalName,
+		Version:               "4.0.18",
+		ValuesPath:            "https://example.com/values.yaml",
+		State:                 model.UtilityRolloutStateRequested,
+		MaxConcurrentClusters: 2,
+		ClusterTimeoutSeconds: 600,
+		Clusters: model.UtilityRolloutClusters{
+			{ClusterID: "cluster1", State: model.UtilityRolloutClusterPending},
+			{ClusterID: "cluster2", State: model.UtilityRolloutClusterPending},
+		},
+	}
+
+	err := sqlStore.CreateUtilityRollout(rollout)
+	require.NoError(t, err)
+	assert.NotEmpty(t, rollout.ID)
+	assert.NotZero(t, rollout.RequestAt)
+
+	fetchedRollout, err := sqlStore.GetUtilityRollout(rollout.ID)
+	require.NoError(t, err)
+	assert.Equal(t, rollout, fetchedRollout)
+
+	t.Run("update", func(t *testing.T) {
+		rollout.State = model.UtilityRolloutStateInProgress
+		rollout.Clusters[0].State = model.UtilityRolloutClusterSucceeded
+		rollout.Clusters[1].State = model.UtilityRolloutClusterFailed
+		rollout.Clusters[1].Message = "timed out"
+
+		err = sqlStore.UpdateUtilityRollout(rollout)
+		require.NoError(t, err)
+
+		fetchedRollout, err = sqlStore.GetUtilityRollout(rollout.ID)
+		require.NoError(t, err)
+		assert.Equal(t, rollout, fetchedRollout)
+	})
+
+	t.Run("lock", func(t *testing.T) {
+		locked, err := sqlStore.LockUtilityRollouts([]string{rollout.ID}, "locker")
+		require.NoError(t, err)
+		assert.True(t, locked)
+
+		locked, err = sqlStore.LockUtilityRollouts([]string{rollout.ID}, "other")
+		require.NoError(t, err)
+		assert.False(t, locked)
+
+		pending, err := sqlStore.GetUnlockedUtilityRolloutsPendingWork()
+		require.NoError(t, err)
+		assert.Empty(t, pending)
+
+		unlocked, err := sqlStore.UnlockUtilityRollouts([]string{rollout.ID}, "locker", false)
+		require.NoError(t, err)
+		assert.True(t, unlocked)
+
+		pending, err = sqlStore.GetUnlockedUtilityRolloutsPendingWork()
+		require.NoError(t, err)
+		require.Len(t, pending, 1)
+		assert.Equal(t, rollout.ID, pending[0].ID)
+	})
+
+	t.Run("unknown rollout", func(t *testing.T) {
+		fetchedRollout, err = sqlStore.GetUtilityRollout("unknown")
+		require.NoError(t, err)
+		assert.Nil(t, fetchedRollout)
+	})
+}
+
+func TestGetUtilityRollouts(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := MakeTestSQLStore(t, logger)
+	defer CloseConnection(t, sqlStore)
+
+	rollouts := []*model.UtilityRollout{
+		{Utility: model.NginxCanonicalName, State: model.UtilityRolloutStateSucceeded},
+		{Utility: model.NginxCanonicalName, State: model.UtilityRolloutStatePaused},
+		{Utility: model.PrometheusOperatorCanonicalName, State: model.UtilityRolloutStateInProgress},
+		{Utility: model.PrometheusOperatorCanonicalName, State: model.UtilityRolloutStateCancelled},
+	}
+	for _, rollout := range rollouts {
+		err := sqlStore.CreateUtilityRollout(rollout)
+		require.NoError(t, err)
+		time.Sleep(1 * time.Millisecond)
+	}
+
+	for _, testCase := range []struct {
+		description string
+		filter      *model.UtilityRolloutFilter
+		fetchedIDs  []string
+	}{
+		{
+			description: "fetch all",
+			filter:      &model.UtilityRolloutFilter{Paging: model.AllPagesNotDeleted()},
+			fetchedIDs:  []string{rollouts[3].ID, rollouts[2].ID, rollouts[1].ID, rollouts[0].ID},
+		},
+		{
+			description: "fetch by utility",
+			filter:      &model.UtilityRolloutFilter{Paging: model.AllPagesNotDeleted(), Utility: model.NginxCanonicalName},
+			fetchedIDs:  []string{rollouts[1].ID, rollouts[0].ID},
+		},
+		{
+			description: "fetch pending work",
+			filter:      &model.UtilityRolloutFilter{Paging: model.AllPagesNotDeleted(), States: model.AllUtilityRolloutStatesPendingWork},
+			fetchedIDs:  []string{rollouts[2].ID, rollouts[1].ID},
+		},
+		{
+			description: "fetch by utility and state",
+			filter: &model.UtilityRolloutFilter{
+				Paging:  model.AllPagesNotDeleted(),
+				Utility: model.PrometheusOperatorCanonicalName,
+				States:  []model.UtilityRolloutState{model.UtilityRolloutStateCancelled},
+			},
+			fetchedIDs: []string{rollouts[3].ID},
+		},
+		{
+			description: "fetch page",
+			filter:      &model.UtilityRolloutFilter{Paging: model.Paging{Page: 1, PerPage: 3, IncludeDeleted: false}},
+			fetchedIDs:  []string{rollouts[0].ID},
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			fetchedRollouts, err := sqlStore.GetUtilityRollouts(testCase.filter)
+			require.NoError(t, err)
+
+			var fetchedIDs []string
+			for _, rollout := range fetchedRollouts {
+				fetchedIDs = append(fetchedIDs, rollout.ID)
+			}
+			assert.Equal(t, testCase.fetchedIDs, fetchedIDs)
+		})
+	}
+}
@@ -29,6 +29,12 @@ type clusterStore interface {
 	GetWebhooks(filter *model.WebhookFilter) ([]*model.Webhook, error)
 
 	GetStateChangeEvents(filter *model.StateChangeEventFilter) ([]*model.StateChangeEventData, error)
+
+	GetClusterInstallations(filter *model.ClusterInstallationFilter) ([]*model.ClusterInstallation, error)
+
+	CreateClusterUpgrade(upgrade *model.ClusterUpgrade) error
+	GetLatestClusterUpgrade(clusterID string) (*model.ClusterUpgrade, error)
+	UpdateClusterUpgrade(upgrade *model.ClusterUpgrade) error
 }
 
 // ClusterProvisioner abstracts the provisioning operations required by the cluster supervisor.
@@ -40,7 +46,10 @@ type ClusterProvisioner interface {
 	CheckNodegroupsCreated(cluster *model.Cluster) (bool, error)
 	DeleteNodegroups(cluster *model.Cluster) error
 	ProvisionCluster(cluster *model.Cluster) error
-	UpgradeCluster(cluster *model.Cluster) error
+	PreflightClusterUpgrade(cluster *model.Cluster) (model.ClusterUpgradeChecks, error)
+	UpgradeClusterControlPlane(cluster *model.Cluster) error
+	UpgradeClusterNodeGroup(cluster *model.Cluster, nodeGroup string) error
+	VerifyClusterUpgrade(cluster *model.Cluster, clusterInstallations []*model.ClusterInstallation) (model.ClusterUpgradeChecks, error)
 	ResizeCluster(cluster *model.Cluster) error
 	DeleteCluster(cluster *model.Cluster) (bool, error)
 	RefreshClusterMetadata(cluster *model.Cluster) error
@@ -131,6 +140,7 @@ func (s *ClusterSupervisor) Supervise(cluster *model.Cluster) {
 
 	logger.Debugf("Supervising cluster in state %s", cluster.State)
 
+	oldVersions := utilityChartVersions(cluster)
 	newState := s.transitionCluster(cluster, logger)
 
 	cluster, err = s.store.GetCluster(cluster.ID)
@@ -139,6 +149,10 @@ func (s *ClusterSupervisor) Supervise(cluster *model.Cluster) {
 		return
 	}
 
+	// Utility versions are stored by several transitions, including failed
+	// ones, so the events are produced from the versions that were persisted.
+	s.produceUtilityVersionChangeEvents(cluster, oldVersions, logger)
+
 	if cluster.State == newState {
 		return
 	}
@@ -228,22 +242,21 @@ func (s *ClusterSupervisor) provisionCluster(cluster *model.Cluster, logger log.
 	return s.refreshClusterMetadata(cluster, logger)
 }
 
-func (s *ClusterSupervisor) upgradeCluster(cluster *model.Cluster, logger log.FieldLogger) string {
-	s.grafana.AddGrafanaClusterUpgradeAnnotation(cluster.ID, logger)
-	err := s.provisioner.GetClusterProvisioner(cluster.Provisioner).UpgradeCluster(cluster)
-	if err != nil {
-		logger.WithError(err).Error("Failed to upgrade cluster")
-		logger.Info("Updating cluster store with latest cluster data")
-		err = s.store.UpdateCluster(cluster)
+func (s *ClusterSupervisor) produceUtilityVersionChangeEvents(cluster *model.Cluster, oldVersions map[string]string, logger log.FieldLogger) {
+	changes := model.NewClusterUtilityVersionChangeEventsData(oldVersions, utilityChartVersions(cluster))
+	for _, change := range changes {
+		err := s.eventsProducer.ProduceClusterUtilityVersionChangeEvent(cluster, change)
 		if err != nil {
-			logger.WithError(err).Error("Failed to save updated cluster metadata")
-			return model.ClusterStateRefreshMetadata
+			logger.WithError(err).WithField("utility", change.Utility).Error("Failed to create cluster utility version change event")
 		}
-		return model.ClusterStateUpgradeFailed
 	}
+}
 
-	logger.Info("Finished upgrading cluster")
-	return s.refreshClusterMetadata(cluster, logger)
+func utilityChartVersions(cluster *model.Cluster) map[string]string {
+	if cluster.UtilityMetadata == nil {
+		return nil
+	}
+	return cluster.UtilityMetadata.ActualVersions.ChartVersions()
 }
 
 func (s *ClusterSupervisor) resizeCluster(cluster *model.Cluster, logger log.FieldLogger) string {
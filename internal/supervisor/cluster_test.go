@@ -24,6 +24,9 @@ type mockClusterStore struct {
 
 	UnlockChan         chan interface{}
 	UpdateClusterCalls int
+
+	ClusterUpgrades           []*model.ClusterUpgrade
+	UpdateClusterUpgradeCalls int
 }
 
 func (s *mockClusterStore) GetCluster(clusterID string) (*model.Cluster, error) {
@@ -87,6 +90,30 @@ func (s *mockClusterStore) GetStateChangeEvents(filter *model.StateChangeEventFi
 	return nil, nil
 }
 
+func (s *mockClusterStore) GetClusterInstallations(filter *model.ClusterInstallationFilter) ([]*model.ClusterInstallation, error) {
+	return nil, nil
+}
+
+func (s *mockClusterStore) CreateClusterUpgrade(upgrade *model.ClusterUpgrade) error {
+	upgrade.ID = model.NewID()
+	s.ClusterUpgrades = append(s.ClusterUpgrades, upgrade)
+	return nil
+}
+
+func (s *mockClusterStore) GetLatestClusterUpgrade(clusterID string) (*model.ClusterUpgrade, error) {
+	for i := len(s.ClusterUpgrades) - 1; i >= 0; i-- {
+		if s.ClusterUpgrades[i].ClusterID == clusterID {
+			return s.ClusterUpgrades[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *mockClusterStore) UpdateClusterUpgrade(upgrade *model.ClusterUpgrade) error {
+	s.UpdateClusterUpgradeCalls++
+	return nil
+}
+
 type mockClusterProvisionerOption struct {
 	mock *mockClusterProvisioner
 }
@@ -98,7 +125,12 @@ func (p *mockClusterProvisionerOption) GetClusterProvisioner(provisioner string)
 	return p.mock
 }
 
-type mockClusterProvisioner struct{}
+type mockClusterProvisioner struct {
+	PreflightChecks    model.ClusterUpgradeChecks
+	NodeGroupErrors    map[string]error
+	UpgradedNodeGroups []string
+	UtilityVersions    map[string]*model.HelmUtilityVersion
+}
 
 func (p *mockClusterProvisioner) DeleteNodegroups(cluster *model.Cluster) error {
 	return nil
@@ -129,13 +161,35 @@ func (p *mockClusterProvisioner) CheckNodegroupsCreated(cluster *model.Cluster)
 }
 
 func (p *mockClusterProvisioner) ProvisionCluster(cluster *model.Cluster) error {
+	for utility, version := range p.UtilityVersions {
+		err := cluster.SetUtilityActualVersion(utility, version)
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-func (p *mockClusterProvisioner) UpgradeCluster(cluster *model.Cluster) error {
+func (p *mockClusterProvisioner) PreflightClusterUpgrade(cluster *model.Cluster) (model.ClusterUpgradeChecks, error) {
+	return p.PreflightChecks, nil
+}
+
+func (p *mockClusterProvisioner) UpgradeClusterControlPlane(cluster *model.Cluster) error {
 	return nil
 }
 
+func (p *mockClusterProvisioner) UpgradeClusterNodeGroup(cluster *model.Cluster, nodeGroup string) error {
+	if err := p.NodeGroupErrors[nodeGroup]; err != nil {
+		return err
+	}
+	p.UpgradedNodeGroups = append(p.UpgradedNodeGroups, nodeGroup)
+	return nil
+}
+
+func (p *mockClusterProvisioner) VerifyClusterUpgrade(cluster *model.Cluster, clusterInstallations []*model.ClusterInstallation) (model.ClusterUpgradeChecks, error) {
+	return nil, nil
+}
+
 func (p *mockClusterProvisioner) ResizeCluster(cluster *model.Cluster) error {
 	return nil
 }
@@ -335,3 +389,47 @@ func TestClusterSupervisorSupervise(t *testing.T) {
 		require.Equal(t, model.ClusterStateDeletionRequested, cluster.State)
 	})
 }
+
+func TestClusterSupervisorUtilityVersionChangeEvents(t *testing.T) {
+	grafanaClient, err := grafana.NewGrafanaClient("", "", []string{})
+	assert.Error(t, err)
+
+	newCluster := func(state string) *model.Cluster {
+		cluster := &model.Cluster{ID: model.NewID(), Provider: model.ProviderAWS, State: state}
+		cluster.SetUtilityActualVersion(model.NginxCanonicalName, &model.HelmUtilityVersion{Chart: "4.0.0", ValuesPath: "nginx.yaml"})
+		return cluster
+	}
+	provisionerOption := &mockClusterProvisionerOption{
+		mock: &mockClusterProvisioner{
+			UtilityVersions: map[string]*model.HelmUtilityVersion{
+				model.NginxCanonicalName: {Chart: "4.1.0", ValuesPath: "nginx.yaml"},
+			},
+		},
+	}
+
+	t.Run("provisioning changes a utility version", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		mockStore := &mockClusterStore{Cluster: newCluster(model.ClusterStateProvisioningRequested)}
+		mockEvents := &mockEventProducer{}
+
+		clusterSupervisor := supervisor.NewClusterSupervisor(mockStore, provisionerOption, mockEvents, "instanceID", grafanaClient, cloudMetrics, logger)
+		clusterSupervisor.Supervise(mockStore.Cluster)
+
+		assert.Equal(t, model.ClusterStateStable, mockStore.Cluster.State)
+		assert.Equal(t, []*model.ClusterUtilityVersionChangeEventData{
+			{Utility: model.NginxCanonicalName, OldVersion: "4.0.0", NewVersion: "4.1.0"},
+		}, mockEvents.clusterUtilityVersionChanges)
+	})
+
+	t.Run("resize keeps utility versions", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		mockStore := &mockClusterStore{Cluster: newCluster(model.ClusterStateResizeRequested)}
+		mockEvents := &mockEventProducer{}
+
+		clusterSupervisor := supervisor.NewClusterSupervisor(mockStore, provisionerOption, mockEvents, "instanceID", grafanaClient, cloudMetrics, logger)
+		clusterSupervisor.Supervise(mockStore.Cluster)
+
+		assert.Equal(t, model.ClusterStateStable, mockStore.Cluster.State)
+		assert.Empty(t, mockEvents.clusterUtilityVersionChanges)
+	})
+}
@@ -0,0 +1,158 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package supervisor
+
+import (
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// upgradeCluster runs the in-progress upgrade of the cluster from the phase
+// it last reached, recording the progress after every step so that a failed
+// upgrade can be resumed.
+func (s *ClusterSupervisor) upgradeCluster(cluster *model.Cluster, logger log.FieldLogger) string {
+	s.grafana.AddGrafanaClusterUpgradeAnnotation(cluster.ID, logger)
+
+	upgrade, err := s.store.GetLatestClusterUpgrade(cluster.ID)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get cluster upgrade")
+		return cluster.State
+	}
+	if upgrade == nil || upgrade.State != model.ClusterUpgradeStateInProgress {
+		upgrade = model.NewClusterUpgrade(cluster.ID, cluster.UpgradeVersion(), false)
+		err = s.store.CreateClusterUpgrade(upgrade)
+		if err != nil {
+			logger.WithError(err).Error("Failed to create cluster upgrade")
+			return cluster.State
+		}
+	}
+	logger = logger.WithField("upgrade", upgrade.ID)
+
+	err = s.runClusterUpgrade(cluster, upgrade, logger)
+	if err != nil {
+		logger.WithError(err).Errorf("Failed to upgrade cluster in phase %s", upgrade.Phase)
+		upgrade.Fail(err.Error())
+		err = s.store.UpdateClusterUpgrade(upgrade)
+		if err != nil {
+			logger.WithError(err).Error("Failed to record cluster upgrade failure")
+		}
+
+		logger.Info("Updating cluster store with latest cluster data")
+		err = s.store.UpdateCluster(cluster)
+		if err != nil {
+			logger.WithError(err).Error("Failed to save updated cluster metadata")
+			return model.ClusterStateRefreshMetadata
+		}
+		return model.ClusterStateUpgradeFailed
+	}
+
+	logger.Info("Finished upgrading cluster")
+	return s.refreshClusterMetadata(cluster, logger)
+}
+
+// runClusterUpgrade runs the remaining phases of the upgrade: the preflight
+// checks, the control plane upgrade, the roll of every nodegroup one at a
+// time and the verification of the cluster installations.
+func (s *ClusterSupervisor) runClusterUpgrade(cluster *model.Cluster, upgrade *model.ClusterUpgrade, logger log.FieldLogger) error {
+	provisioner := s.provisioner.GetClusterProvisioner(cluster.Provisioner)
+
+	for !upgrade.IsFinished() {
+		switch upgrade.Phase {
+		case model.ClusterUpgradePhasePreflight:
+			if upgrade.SkipPreflight {
+				logger.Warn("Skipping cluster upgrade preflight checks")
+			} else {
+				logger.Info("Running cluster upgrade preflight checks")
+				checks, err := provisioner.PreflightClusterUpgrade(cluster)
+				if err != nil {
+					return errors.Wrap(err, "failed to run preflight checks")
+				}
+				upgrade.Preflight = checks
+				if failures := checks.BlockingFailures(); len(failures) > 0 {
+					return errors.Errorf("preflight checks failed: %s", failures.Summary())
+				}
+			}
+			upgrade.Phase = model.ClusterUpgradePhaseControlPlane
+		case model.ClusterUpgradePhaseControlPlane:
+			logger.Info("Upgrading cluster control plane")
+			err := provisioner.UpgradeClusterControlPlane(cluster)
+			if err != nil {
+				return errors.Wrap(err, "failed to upgrade control plane")
+			}
+			upgrade.SetNodeGroups(cluster.UpgradeNodeGroups())
+			upgrade.Phase = model.ClusterUpgradePhaseNodeGroups
+		case model.ClusterUpgradePhaseNodeGroups:
+			for _, nodeGroup := range upgrade.NodeGroups {
+				if nodeGroup.State == model.ClusterUpgradeNodeGroupSucceeded {
+					continue
+				}
+				err := s.upgradeClusterNodeGroup(cluster, upgrade, nodeGroup, provisioner, logger)
+				if err != nil {
+					return errors.Wrapf(err, "failed to upgrade nodegroup %s", nodeGroup.Name)
+				}
+			}
+			upgrade.Phase = model.ClusterUpgradePhaseVerification
+		case model.ClusterUpgradePhaseVerification:
+			logger.Info("Verifying cluster upgrade")
+			clusterInstallations, err := s.store.GetClusterInstallations(&model.ClusterInstallationFilter{
+				ClusterID: cluster.ID,
+				Paging:    model.AllPagesNotDeleted(),
+			})
+			if err != nil {
+				return errors.Wrap(err, "failed to get cluster installations")
+			}
+			checks, err := provisioner.VerifyClusterUpgrade(cluster, clusterInstallations)
+			if err != nil {
+				return errors.Wrap(err, "failed to verify upgrade")
+			}
+			upgrade.Verification = checks
+			if failures := checks.BlockingFailures(); len(failures) > 0 {
+				return errors.Errorf("verification checks failed: %s", failures.Summary())
+			}
+			upgrade.Phase = model.ClusterUpgradePhaseComplete
+			upgrade.State = model.ClusterUpgradeStateSucceeded
+			upgrade.CompleteAt = model.GetMillis()
+		default:
+			return errors.Errorf("unknown cluster upgrade phase %q", upgrade.Phase)
+		}
+
+		err := s.store.UpdateClusterUpgrade(upgrade)
+		if err != nil {
+			return errors.Wrap(err, "failed to record cluster upgrade progress")
+		}
+	}
+
+	return nil
+}
+
+// upgradeClusterNodeGroup rolls a single nodegroup, recording its progress.
+func (s *ClusterSupervisor) upgradeClusterNodeGroup(cluster *model.Cluster, upgrade *model.ClusterUpgrade, nodeGroup *model.ClusterUpgradeNodeGroup, provisioner ClusterProvisioner, logger log.FieldLogger) error {
+	logger.WithField("nodegroup", nodeGroup.Name).Info("Upgrading cluster nodegroup")
+
+	nodeGroup.State = model.ClusterUpgradeNodeGroupInProgress
+	nodeGroup.StartAt = model.GetMillis()
+	nodeGroup.CompleteAt = 0
+	err := s.store.UpdateClusterUpgrade(upgrade)
+	if err != nil {
+		return errors.Wrap(err, "failed to record cluster upgrade progress")
+	}
+
+	err = provisioner.UpgradeClusterNodeGroup(cluster, nodeGroup.Name)
+	if err != nil {
+		nodeGroup.State = model.ClusterUpgradeNodeGroupFailed
+		nodeGroup.Message = err.Error()
+		return err
+	}
+
+	nodeGroup.State = model.ClusterUpgradeNodeGroupSucceeded
+	nodeGroup.CompleteAt = model.GetMillis()
+	err = s.store.UpdateClusterUpgrade(upgrade)
+	if err != nil {
+		return errors.Wrap(err, "failed to record cluster upgrade progress")
+	}
+
+	return nil
+}
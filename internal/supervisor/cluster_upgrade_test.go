@@ -0,0 +1,140 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package supervisor_test
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-cloud/internal/supervisor"
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/internal/tools/grafana"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClusterSupervisorUpgrade(t *testing.T) {
+	grafanaClient, _ := grafana.NewGrafanaClient("", "", []string{})
+
+	newCluster := func() *model.Cluster {
+		return &model.Cluster{
+			ID:          model.NewID(),
+			Provisioner: model.ProvisionerKops,
+			State:       model.ClusterStateUpgradeRequested,
+			ProvisionerMetadataKops: &model.KopsMetadata{
+				ChangeRequest: &model.KopsMetadataRequestedState{Version: "1.29.4"},
+				NodeInstanceGroups: model.KopsInstanceGroupsMetadata{
+					"nodes-b": {},
+					"nodes-a": {},
+				},
+			},
+		}
+	}
+
+	setup := func(t *testing.T, cluster *model.Cluster, provisioner *mockClusterProvisioner) (*supervisor.ClusterSupervisor, *mockClusterStore) {
+		mockStore := &mockClusterStore{Cluster: cluster}
+		return supervisor.NewClusterSupervisor(
+			mockStore,
+			&mockClusterProvisionerOption{mock: provisioner},
+			&mockEventProducer{},
+			"instanceID",
+			grafanaClient,
+			cloudMetrics,
+			testlib.MakeLogger(t),
+		), mockStore
+	}
+
+	t.Run("success", func(t *testing.T) {
+		cluster := newCluster()
+		provisioner := &mockClusterProvisioner{}
+		clusterSupervisor, mockStore := setup(t, cluster, provisioner)
+
+		clusterSupervisor.Supervise(cluster)
+
+		assert.Equal(t, model.ClusterStateStable, cluster.State)
+		require.Len(t, mockStore.ClusterUpgrades, 1)
+		upgrade := mockStore.ClusterUpgrades[0]
+		assert.Equal(t, "1.29.4", upgrade.Version)
+		assert.Equal(t, model.ClusterUpgradeStateSucceeded, upgrade.State)
+		assert.Equal(t, model.ClusterUpgradePhaseComplete, upgrade.Phase)
+		assert.NotZero(t, upgrade.CompleteAt)
+		assert.Equal(t, []string{"nodes-a", "nodes-b"}, provisioner.UpgradedNodeGroups)
+		for _, nodeGroup := range upgrade.NodeGroups {
+			assert.Equal(t, model.ClusterUpgradeNodeGroupSucceeded, nodeGroup.State)
+		}
+	})
+
+	t.Run("blocking preflight failure", func(t *testing.T) {
+		cluster := newCluster()
+		provisioner := &mockClusterProvisioner{
+			PreflightChecks: model.ClusterUpgradeChecks{
+				{Name: "pod-disruption-budgets", Blocking: true, Message: "app/pdb allows no disruption"},
+				{Name: "deprecated-apis", Blocking: false, Message: "old api"},
+			},
+		}
+		clusterSupervisor, mockStore := setup(t, cluster, provisioner)
+
+		clusterSupervisor.Supervise(cluster)
+
+		assert.Equal(t, model.ClusterStateUpgradeFailed, cluster.State)
+		require.Len(t, mockStore.ClusterUpgrades, 1)
+		upgrade := mockStore.ClusterUpgrades[0]
+		assert.Equal(t, model.ClusterUpgradeStateFailed, upgrade.State)
+		assert.Equal(t, model.ClusterUpgradePhasePreflight, upgrade.Phase)
+		assert.Contains(t, upgrade.Message, "app/pdb allows no disruption")
+		assert.Len(t, upgrade.Preflight, 2)
+		assert.Empty(t, provisioner.UpgradedNodeGroups)
+	})
+
+	t.Run("skip preflight", func(t *testing.T) {
+		cluster := newCluster()
+		provisioner := &mockClusterProvisioner{
+			PreflightChecks: model.ClusterUpgradeChecks{
+				{Name: "pod-disruption-budgets", Blocking: true},
+			},
+		}
+		clusterSupervisor, mockStore := setup(t, cluster, provisioner)
+		mockStore.ClusterUpgrades = []*model.ClusterUpgrade{model.NewClusterUpgrade(cluster.ID, "1.29.4", true)}
+
+		clusterSupervisor.Supervise(cluster)
+
+		assert.Equal(t, model.ClusterStateStable, cluster.State)
+		require.Len(t, mockStore.ClusterUpgrades, 1)
+		assert.Equal(t, model.ClusterUpgradeStateSucceeded, mockStore.ClusterUpgrades[0].State)
+		assert.Empty(t, mockStore.ClusterUpgrades[0].Preflight)
+	})
+
+	t.Run("nodegroup failure and resume", func(t *testing.T) {
+		cluster := newCluster()
+		provisioner := &mockClusterProvisioner{
+			NodeGroupErrors: map[string]error{"nodes-b": errors.New("drain timed out")},
+		}
+		clusterSupervisor, mockStore := setup(t, cluster, provisioner)
+
+		clusterSupervisor.Supervise(cluster)
+
+		assert.Equal(t, model.ClusterStateUpgradeFailed, cluster.State)
+		require.Len(t, mockStore.ClusterUpgrades, 1)
+		upgrade := mockStore.ClusterUpgrades[0]
+		assert.Equal(t, model.ClusterUpgradeStateFailed, upgrade.State)
+		assert.Equal(t, model.ClusterUpgradePhaseNodeGroups, upgrade.Phase)
+		assert.Contains(t, upgrade.Message, "drain timed out")
+		require.Len(t, upgrade.NodeGroups, 2)
+		assert.Equal(t, model.ClusterUpgradeNodeGroupSucceeded, upgrade.NodeGroups[0].State)
+		assert.Equal(t, model.ClusterUpgradeNodeGroupFailed, upgrade.NodeGroups[1].State)
+
+		require.NoError(t, upgrade.Resume())
+		provisioner.NodeGroupErrors = nil
+		cluster.State = model.ClusterStateUpgradeRequested
+
+		clusterSupervisor.Supervise(cluster)
+
+		assert.Equal(t, model.ClusterStateStable, cluster.State)
+		require.Len(t, mockStore.ClusterUpgrades, 1)
+		assert.Equal(t, model.ClusterUpgradeStateSucceeded, upgrade.State)
+		assert.Equal(t, []string{"nodes-a", "nodes-b"}, provisioner.UpgradedNodeGroups)
+	})
+}
@@ -0,0 +1,239 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package supervisor
+
+import (
+	"github.com/mattermost/mattermost-cloud/model"
+	log "github.com/sirupsen/logrus"
+)
+
+// clusterUtilityHealthStore abstracts the database operations required by the supervisor.
+type clusterUtilityHealthStore interface {
+	GetClusters(filter *model.ClusterFilter) ([]*model.Cluster, error)
+	GetCluster(clusterID string) (*model.Cluster, error)
+	UpdateCluster(cluster *model.Cluster) error
+	clusterLockStore
+}
+
+// clusterUtilityHealthProvisioner checks and remediates the health of
+// cluster utilities.
+type clusterUtilityHealthProvisioner interface {
+	CheckClusterUtilityHealth(cluster *model.Cluster) ([]*model.ClusterUtilityHealth, error)
+	RestartClusterUtility(cluster *model.Cluster, utility string) error
+}
+
+// ClusterUtilityHealthSupervisor periodically checks the health of the
+// utilities of stable clusters, records it on the clusters and optionally
+// remediates unhealthy utilities.
+type ClusterUtilityHealthSupervisor struct {
+	store          clusterUtilityHealthStore
+	provisioner    clusterUtilityHealthProvisioner
+	eventsProducer eventProducer
+	policy         model.UtilityRemediationPolicy
+	instanceID     string
+	logger         log.FieldLogger
+}
+
+// NewClusterUtilityHealthSupervisor creates a new ClusterUtilityHealthSupervisor.
+func NewClusterUtilityHealthSupervisor(
+	store clusterUtilityHealthStore,
+	provisioner clusterUtilityHealthProvisioner,
+	eventsProducer eventProducer,
+	policy model.UtilityRemediationPolicy,
+	instanceID string,
+	logger log.FieldLogger) *ClusterUtilityHealthSupervisor {
+	return &ClusterUtilityHealthSupervisor{
+		store:          store,
+		provisioner:    provisioner,
+		eventsProducer: eventsProducer,
+		policy:         policy,
+		instanceID:     instanceID,
+		logger:         logger.WithField("supervisor", "cluster-utility-health"),
+	}
+}
+
+// Shutdown performs graceful shutdown tasks for the supervisor.
+func (s *ClusterUtilityHealthSupervisor) Shutdown() {
+	s.logger.Debug("Shutting down cluster utility health supervisor")
+}
+
+// Do checks the utility health of all stable clusters.
+func (s *ClusterUtilityHealthSupervisor) Do() error {
+	clusters, err := s.store.GetClusters(&model.ClusterFilter{Paging: model.AllPagesNotDeleted()})
+	if err != nil {
+		s.logger.WithError(err).Warn("Failed to query clusters")
+		return nil
+	}
+
+	for _, cluster := range clusters {
+		if cluster.State != model.ClusterStateStable {
+			continue
+		}
+		s.Supervise(cluster)
+	}
+
+	return nil
+}
+
+// Supervise checks the utility health of the given cluster and remediates
+// unhealthy utilities according to the remediation policy.
+func (s *ClusterUtilityHealthSupervisor) Supervise(cluster *model.Cluster) {
+	logger := s.logger.WithFields(log.Fields{
+		"cluster": cluster.ID,
+	})
+
+	lock := newClusterLock(cluster.ID, s.instanceID, s.store, logger)
+	if !lock.TryLock() {
+		return
+	}
+	defer lock.Unlock()
+
+	// The cluster may have been picked up by the cluster supervisor since
+	// it was listed, in which case its utilities may be in flux.
+	cluster, err := s.store.GetCluster(cluster.ID)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get refreshed cluster")
+		return
+	}
+	if cluster == nil || cluster.State != model.ClusterStateStable {
+		logger.Debug("Cluster is no longer stable; skipping utility health check")
+		return
+	}
+
+	health, err := s.provisioner.CheckClusterUtilityHealth(cluster)
+	if err != nil {
+		logger.WithError(err).Warn("Failed to check cluster utility health")
+		return
+	}
+
+	previousHealth := map[string]*model.ClusterUtilityHealth{}
+	if cluster.UtilityMetadata != nil {
+		for utility, utilityHealth := range cluster.UtilityMetadata.Health {
+			previousHealth[utility] = utilityHealth
+		}
+	}
+
+	now := model.GetMillis()
+	checked := map[string]bool{}
+	upgrades := map[string]*model.HelmUtilityVersion{}
+	var becameUnhealthy []*model.ClusterUtilityHealth
+
+	for _, utilityHealth := range health {
+		checked[utilityHealth.Utility] = true
+		utilityLogger := logger.WithField("utility", utilityHealth.Utility)
+
+		if cluster.SetUtilityHealth(utilityHealth) {
+			utilityLogger.Warnf("Cluster utility became unhealthy: %s", utilityHealth.Message)
+			becameUnhealthy = append(becameUnhealthy, utilityHealth)
+		}
+
+		if cluster.APISecurityLock || !s.policy.RemediationDue(utilityHealth, now) {
+			continue
+		}
+
+		switch s.policy.Action {
+		case model.UtilityRemediationRestart:
+			utilityLogger.Info("Restarting unhealthy cluster utility")
+			err = s.provisioner.RestartClusterUtility(cluster, utilityHealth.Utility)
+			if err != nil {
+				utilityLogger.WithError(err).Error("Failed to restart cluster utility")
+			}
+		case model.UtilityRemediationHelmUpgrade:
+			version := remediationVersion(cluster, utilityHealth.Utility)
+			if version == nil {
+				utilityLogger.Warn("Unable to determine the utility version to reinstall")
+				continue
+			}
+			utilityLogger.Infof("Reinstalling unhealthy cluster utility version %s", version.Version())
+			upgrades[utilityHealth.Utility] = version
+		}
+		utilityHealth.RecordRemediation(now)
+	}
+
+	if cluster.UtilityMetadata != nil {
+		for utility := range cluster.UtilityMetadata.Health {
+			if !checked[utility] {
+				delete(cluster.UtilityMetadata.Health, utility)
+			}
+		}
+	}
+
+	// Health checks are frequent, so the cluster is only persisted when the
+	// outcome differs from the stored one. The stored CheckedAt is therefore
+	// the time of the last change rather than the last check.
+	if len(upgrades) == 0 && !utilityHealthChanged(previousHealth, cluster) {
+		logger.Debug("Cluster utility health unchanged")
+		return
+	}
+
+	oldState := cluster.State
+	if len(upgrades) > 0 {
+		cluster.SetUtilityDesiredVersions(upgrades)
+		cluster.State = model.ClusterStateProvisioningRequested
+	}
+
+	err = s.store.UpdateCluster(cluster)
+	if err != nil {
+		logger.WithError(err).Error("Failed to update cluster utility health")
+		return
+	}
+
+	for _, utilityHealth := range becameUnhealthy {
+		err = s.eventsProducer.ProduceClusterUtilityUnhealthyEvent(cluster, &model.ClusterUtilityUnhealthyEventData{
+			Utility:    utilityHealth.Utility,
+			HelmStatus: utilityHealth.HelmStatus,
+			Message:    utilityHealth.Message,
+		})
+		if err != nil {
+			logger.WithError(err).Error("Failed to create cluster utility unhealthy event")
+		}
+	}
+
+	if cluster.State != oldState {
+		err = s.eventsProducer.ProduceClusterStateChangeEvent(cluster, oldState)
+		if err != nil {
+			logger.WithError(err).Error("Failed to create cluster state change event")
+		}
+	}
+}
+
+// utilityHealthChanged returns true if the utility health stored on the
+// cluster differs from the previous health of its utilities.
+func utilityHealthChanged(previous map[string]*model.ClusterUtilityHealth, cluster *model.Cluster) bool {
+	var current map[string]*model.ClusterUtilityHealth
+	if cluster.UtilityMetadata != nil {
+		current = cluster.UtilityMetadata.Health
+	}
+	if len(previous) != len(current) {
+		return true
+	}
+	for utility, utilityHealth := range current {
+		if !utilityHealth.SameStatus(previous[utility]) {
+			return true
+		}
+	}
+	return false
+}
+
+// remediationVersion returns the version to reinstall an unhealthy utility
+// with, which is the version the utility is currently running.
+func remediationVersion(cluster *model.Cluster, utility string) *model.HelmUtilityVersion {
+	actualVersion := cluster.ActualUtilityVersion(utility)
+	if actualVersion == nil || actualVersion.Version() == "" || actualVersion.Version() == model.UnmanagedUtilityVersion {
+		return nil
+	}
+
+	valuesPath := actualVersion.Values()
+	if valuesPath == "" {
+		if defaultVersion, ok := model.DefaultUtilityVersions[utility]; ok && defaultVersion != nil {
+			valuesPath = defaultVersion.Values()
+		}
+	}
+	if valuesPath == "" {
+		return nil
+	}
+
+	return &model.HelmUtilityVersion{Chart: actualVersion.Version(), ValuesPath: valuesPath}
+}
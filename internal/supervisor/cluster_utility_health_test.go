@@ -0,0 +1,168 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package supervisor_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/internal/supervisor"
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockClusterUtilityHealthStore struct {
+	Cluster *model.Cluster
+
+	UpdateClusterCalls int
+}
+
+func (m *mockClusterUtilityHealthStore) GetClusters(filter *model.ClusterFilter) ([]*model.Cluster, error) {
+	return []*model.Cluster{m.Cluster}, nil
+}
+
+func (m *mockClusterUtilityHealthStore) GetCluster(clusterID string) (*model.Cluster, error) {
+	return m.Cluster, nil
+}
+
+func (m *mockClusterUtilityHealthStore) UpdateCluster(cluster *model.Cluster) error {
+	m.UpdateClusterCalls++
+	return nil
+}
+
+func (m *mockClusterUtilityHealthStore) LockCluster(clusterID, lockerID string) (bool, error) {
+	return true, nil
+}
+
+func (m *mockClusterUtilityHealthStore) UnlockCluster(clusterID, lockerID string, force bool) (bool, error) {
+	return true, nil
+}
+
+func (m *mockClusterUtilityHealthStore) LockClusterScheduling(clusterID, lockerID string) (bool, error) {
+	return true, nil
+}
+
+func (m *mockClusterUtilityHealthStore) UnlockClusterScheduling(clusterID, lockerID string, force bool) (bool, error) {
+	return true, nil
+}
+
+type mockClusterUtilityHealthProvisioner struct {
+	Health   []*model.ClusterUtilityHealth
+	Restarts []string
+}
+
+func (m *mockClusterUtilityHealthProvisioner) CheckClusterUtilityHealth(cluster *model.Cluster) ([]*model.ClusterUtilityHealth, error) {
+	// Return copies as the supervisor stores the results on the cluster.
+	var health []*model.ClusterUtilityHealth
+	for _, h := range m.Health {
+		copied := *h
+		copied.CheckedAt = model.GetMillis()
+		health = append(health, &copied)
+	}
+	return health, nil
+}
+
+func (m *mockClusterUtilityHealthProvisioner) RestartClusterUtility(cluster *model.Cluster, utility string) error {
+	m.Restarts = append(m.Restarts, utility)
+	return nil
+}
+
+func newMockClusterUtilityHealthStore() *mockClusterUtilityHealthStore {
+	cluster := &model.Cluster{ID: model.NewID(), State: model.ClusterStateStable}
+	cluster.SetUtilityActualVersion(model.NginxCanonicalName, &model.HelmUtilityVersion{Chart: "4.0.0", ValuesPath: "nginx.yaml"})
+	return &mockClusterUtilityHealthStore{Cluster: cluster}
+}
+
+func TestClusterUtilityHealthSupervisor(t *testing.T) {
+	unhealthy := []*model.ClusterUtilityHealth{
+		{Utility: model.NginxCanonicalName, HelmStatus: "failed", Message: "helm release nginx is failed"},
+	}
+	healthy := []*model.ClusterUtilityHealth{
+		{Utility: model.NginxCanonicalName, Healthy: true, HelmStatus: "deployed"},
+	}
+
+	t.Run("records health and emits event once", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		mockStore := newMockClusterUtilityHealthStore()
+		mockProvisioner := &mockClusterUtilityHealthProvisioner{Health: unhealthy}
+		mockEvents := &mockEventProducer{}
+		policy := model.UtilityRemediationPolicy{Action: model.UtilityRemediationNone}
+
+		healthSupervisor := supervisor.NewClusterUtilityHealthSupervisor(mockStore, mockProvisioner, mockEvents, policy, "instanceID", logger)
+		require.NoError(t, healthSupervisor.Do())
+		require.NoError(t, healthSupervisor.Do())
+
+		health := mockStore.Cluster.UtilityHealth(model.NginxCanonicalName)
+		require.NotNil(t, health)
+		assert.False(t, health.Healthy)
+		assert.NotZero(t, health.UnhealthySince)
+		assert.Equal(t, 1, mockStore.UpdateClusterCalls)
+		require.Len(t, mockEvents.clusterUtilityUnhealthy, 1)
+		assert.Equal(t, &model.ClusterUtilityUnhealthyEventData{
+			Utility:    model.NginxCanonicalName,
+			HelmStatus: "failed",
+			Message:    "helm release nginx is failed",
+		}, mockEvents.clusterUtilityUnhealthy[0])
+		assert.Empty(t, mockProvisioner.Restarts)
+
+		mockProvisioner.Health = healthy
+		require.NoError(t, healthSupervisor.Do())
+		health = mockStore.Cluster.UtilityHealth(model.NginxCanonicalName)
+		assert.True(t, health.Healthy)
+		assert.Zero(t, health.UnhealthySince)
+		assert.Equal(t, 2, mockStore.UpdateClusterCalls)
+
+		require.NoError(t, healthSupervisor.Do())
+		assert.Equal(t, 2, mockStore.UpdateClusterCalls)
+	})
+
+	t.Run("restart remediation with backoff", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		mockStore := newMockClusterUtilityHealthStore()
+		mockProvisioner := &mockClusterUtilityHealthProvisioner{Health: unhealthy}
+		policy := model.UtilityRemediationPolicy{Action: model.UtilityRemediationRestart, MaxBackoff: time.Hour}
+
+		healthSupervisor := supervisor.NewClusterUtilityHealthSupervisor(mockStore, mockProvisioner, &mockEventProducer{}, policy, "instanceID", logger)
+		require.NoError(t, healthSupervisor.Do())
+		assert.Equal(t, []string{model.NginxCanonicalName}, mockProvisioner.Restarts)
+		assert.Equal(t, 1, mockStore.Cluster.UtilityHealth(model.NginxCanonicalName).RemediationAttempts)
+
+		policy.InitialBackoff = time.Minute
+		healthSupervisor = supervisor.NewClusterUtilityHealthSupervisor(mockStore, mockProvisioner, &mockEventProducer{}, policy, "instanceID", logger)
+		require.NoError(t, healthSupervisor.Do())
+		assert.Len(t, mockProvisioner.Restarts, 1)
+		assert.Equal(t, model.ClusterStateStable, mockStore.Cluster.State)
+	})
+
+	t.Run("helm upgrade remediation", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		mockStore := newMockClusterUtilityHealthStore()
+		mockProvisioner := &mockClusterUtilityHealthProvisioner{Health: unhealthy}
+		mockEvents := &mockEventProducer{}
+		policy := model.UtilityRemediationPolicy{Action: model.UtilityRemediationHelmUpgrade, MaxBackoff: time.Hour}
+
+		healthSupervisor := supervisor.NewClusterUtilityHealthSupervisor(mockStore, mockProvisioner, mockEvents, policy, "instanceID", logger)
+		require.NoError(t, healthSupervisor.Do())
+
+		assert.Equal(t, model.ClusterStateProvisioningRequested, mockStore.Cluster.State)
+		assert.Equal(t, &model.HelmUtilityVersion{Chart: "4.0.0", ValuesPath: "nginx.yaml"}, mockStore.Cluster.DesiredUtilityVersion(model.NginxCanonicalName))
+		assert.Equal(t, []string{mockStore.Cluster.ID}, mockEvents.clusterListByEventOrder)
+		assert.Empty(t, mockProvisioner.Restarts)
+	})
+
+	t.Run("skips unstable clusters", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		mockStore := newMockClusterUtilityHealthStore()
+		mockStore.Cluster.State = model.ClusterStateUpgradeRequested
+		mockProvisioner := &mockClusterUtilityHealthProvisioner{Health: unhealthy}
+
+		healthSupervisor := supervisor.NewClusterUtilityHealthSupervisor(mockStore, mockProvisioner, &mockEventProducer{}, model.UtilityRemediationPolicy{}, "instanceID", logger)
+		require.NoError(t, healthSupervisor.Do())
+		assert.Zero(t, mockStore.UpdateClusterCalls)
+		assert.Nil(t, mockStore.Cluster.UtilityHealth(model.NginxCanonicalName))
+	})
+}
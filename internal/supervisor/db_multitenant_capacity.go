@@ -0,0 +1,79 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package supervisor
+
+import (
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/internal/common"
+	"github.com/mattermost/mattermost-cloud/model"
+	log "github.com/sirupsen/logrus"
+)
+
+// multitenantDatabaseCapacityStore abstracts the database operations required by the supervisor.
+type multitenantDatabaseCapacityStore interface {
+	GetMultitenantDatabases(filter *model.MultitenantDatabaseFilter) ([]*model.MultitenantDatabase, error)
+	GetLogicalDatabases(filter *model.LogicalDatabaseFilter) ([]*model.LogicalDatabase, error)
+	GetInstallations(filter *model.InstallationFilter, includeGroupConfig, includeGroupConfigOverrides bool) ([]*model.Installation, error)
+}
+
+// multitenantDatabaseCapacityMetrics records multitenant database capacity.
+type multitenantDatabaseCapacityMetrics interface {
+	ObserveMultitenantDatabaseCapacities(capacities []*model.MultitenantDatabaseCapacity)
+}
+
+// MultitenantDatabaseCapacitySupervisor periodically computes the capacity of
+// every multitenant database and exports it as metrics.
+type MultitenantDatabaseCapacitySupervisor struct {
+	store    multitenantDatabaseCapacityStore
+	limits   common.MultitenantDatabaseLimitProvider
+	metrics  multitenantDatabaseCapacityMetrics
+	lookback time.Duration
+	logger   log.FieldLogger
+}
+
+// NewMultitenantDatabaseCapacitySupervisor creates a new MultitenantDatabaseCapacitySupervisor.
+func NewMultitenantDatabaseCapacitySupervisor(
+	store multitenantDatabaseCapacityStore,
+	limits common.MultitenantDatabaseLimitProvider,
+	metrics multitenantDatabaseCapacityMetrics,
+	lookback time.Duration,
+	logger log.FieldLogger) *MultitenantDatabaseCapacitySupervisor {
+	return &MultitenantDatabaseCapacitySupervisor{
+		store:    store,
+		limits:   limits,
+		metrics:  metrics,
+		lookback: lookback,
+		logger:   logger.WithField("supervisor", "multitenant-database-capacity"),
+	}
+}
+
+// Shutdown performs graceful shutdown tasks for the supervisor.
+func (s *MultitenantDatabaseCapacitySupervisor) Shutdown() {
+	s.logger.Debug("Shutting down multitenant database capacity supervisor")
+}
+
+// Do computes the capacity of all multitenant databases and records it.
+func (s *MultitenantDatabaseCapacitySupervisor) Do() error {
+	capacities, err := common.GetMultitenantDatabaseCapacities(s.store, s.limits, &model.MultitenantDatabaseFilter{
+		Paging:                model.AllPagesNotDeleted(),
+		MaxInstallationsLimit: model.NoInstallationsLimit,
+	}, s.lookback)
+	if err != nil {
+		s.logger.WithError(err).Warn("Failed to compute multitenant database capacity")
+		return nil
+	}
+
+	s.metrics.ObserveMultitenantDatabaseCapacities(capacities)
+
+	for _, capacity := range capacities {
+		if capacity.Headroom <= 0 {
+			s.logger.WithField("multitenant-database", capacity.MultitenantDatabaseID).
+				Warnf("Multitenant database is at capacity with weight %.2f of %.2f", capacity.Weight, capacity.MaxWeight)
+		}
+	}
+
+	return nil
+}
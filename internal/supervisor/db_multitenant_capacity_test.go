@@ -0,0 +1,72 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package supervisor_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/internal/supervisor"
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockMultitenantDatabaseCapacityStore struct {
+	MultitenantDatabases []*model.MultitenantDatabase
+	Installations        []*model.Installation
+}
+
+func (m *mockMultitenantDatabaseCapacityStore) GetMultitenantDatabases(filter *model.MultitenantDatabaseFilter) ([]*model.MultitenantDatabase, error) {
+	return m.MultitenantDatabases, nil
+}
+
+func (m *mockMultitenantDatabaseCapacityStore) GetLogicalDatabases(filter *model.LogicalDatabaseFilter) ([]*model.LogicalDatabase, error) {
+	return nil, nil
+}
+
+func (m *mockMultitenantDatabaseCapacityStore) GetInstallations(filter *model.InstallationFilter, includeGroupConfig, includeGroupConfigOverrides bool) ([]*model.Installation, error) {
+	return m.Installations, nil
+}
+
+type mockMultitenantDatabaseLimits struct{}
+
+func (m *mockMultitenantDatabaseLimits) GetMultitenantDatabaseMaxInstallations(databaseType string) int {
+	return 10
+}
+
+type mockMultitenantDatabaseCapacityMetrics struct {
+	Capacities []*model.MultitenantDatabaseCapacity
+}
+
+func (m *mockMultitenantDatabaseCapacityMetrics) ObserveMultitenantDatabaseCapacities(capacities []*model.MultitenantDatabaseCapacity) {
+	m.Capacities = capacities
+}
+
+func TestMultitenantDatabaseCapacitySupervisor_Do(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	mockStore := &mockMultitenantDatabaseCapacityStore{
+		MultitenantDatabases: []*model.MultitenantDatabase{
+			{ID: "db1", DatabaseType: model.DatabaseEngineTypePostgres, Installations: model.MultitenantDatabaseInstallations{"a", "b"}},
+			{ID: "db2", DatabaseType: model.DatabaseEngineTypePostgres},
+		},
+		Installations: []*model.Installation{
+			{ID: "a", State: model.InstallationStateStable},
+			{ID: "b", State: model.InstallationStateHibernating},
+		},
+	}
+	mockMetrics := &mockMultitenantDatabaseCapacityMetrics{}
+
+	capacitySupervisor := supervisor.NewMultitenantDatabaseCapacitySupervisor(mockStore, &mockMultitenantDatabaseLimits{}, mockMetrics, 24*time.Hour, logger)
+	err := capacitySupervisor.Do()
+	require.NoError(t, err)
+
+	require.Len(t, mockMetrics.Capacities, 2)
+	assert.Equal(t, "db1", mockMetrics.Capacities[0].MultitenantDatabaseID)
+	assert.Equal(t, 1.75, mockMetrics.Capacities[0].Weight)
+	assert.Equal(t, 10.0, mockMetrics.Capacities[0].MaxWeight)
+	assert.Equal(t, 0.0, mockMetrics.Capacities[1].Weight)
+}
@@ -0,0 +1,325 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package supervisor
+
+import (
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/internal/common"
+	"github.com/mattermost/mattermost-cloud/internal/tools/aws"
+	"github.com/mattermost/mattermost-cloud/internal/webhook"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// multitenantDatabaseRebalanceStore abstracts the database operations required by the supervisor.
+type multitenantDatabaseRebalanceStore interface {
+	GetUnlockedMultitenantDatabaseRebalancesPendingWork() ([]*model.MultitenantDatabaseRebalance, error)
+	GetMultitenantDatabaseRebalance(id string) (*model.MultitenantDatabaseRebalance, error)
+	UpdateMultitenantDatabaseRebalance(rebalance *model.MultitenantDatabaseRebalance) error
+	multitenantDatabaseRebalanceLockStore
+
+	GetInstallation(installationID string, includeGroupConfig, includeGroupConfigOverrides bool) (*model.Installation, error)
+	installationLockStore
+
+	TriggerInstallationDBMigration(dbMigrationOp *model.InstallationDBMigrationOperation, installation *model.Installation) (*model.InstallationDBMigrationOperation, error)
+	GetInstallationDBMigrationOperation(id string) (*model.InstallationDBMigrationOperation, error)
+	GetInstallationDBMigrationOperations(filter *model.InstallationDBMigrationFilter) ([]*model.InstallationDBMigrationOperation, error)
+
+	GetMultitenantDatabase(multitenantdatabaseID string) (*model.MultitenantDatabase, error)
+	GetMultitenantDatabaseForInstallationID(installationID string) (*model.MultitenantDatabase, error)
+	GetInstallationsTotalDatabaseWeight(installationIDs []string) (float64, error)
+
+	GetWebhooks(filter *model.WebhookFilter) ([]*model.Webhook, error)
+}
+
+// errRebalanceMoveInvalid is returned when a rebalance move can no longer be
+// performed and should be skipped.
+var errRebalanceMoveInvalid = errors.New("rebalance move is no longer valid")
+
+// MultitenantDatabaseRebalanceSupervisor finds approved multitenant database
+// rebalances and executes their moves as throttled installation database
+// migrations.
+type MultitenantDatabaseRebalanceSupervisor struct {
+	store          multitenantDatabaseRebalanceStore
+	limits         common.MultitenantDatabaseLimitProvider
+	instanceID     string
+	environment    string
+	logger         log.FieldLogger
+	eventsProducer eventProducer
+}
+
+// NewMultitenantDatabaseRebalanceSupervisor creates a new MultitenantDatabaseRebalanceSupervisor.
+func NewMultitenantDatabaseRebalanceSupervisor(
+	store multitenantDatabaseRebalanceStore,
+	aws aws.AWS,
+	limits common.MultitenantDatabaseLimitProvider,
+	eventsProducer eventProducer,
+	instanceID string,
+	logger log.FieldLogger) *MultitenantDatabaseRebalanceSupervisor {
+	return &MultitenantDatabaseRebalanceSupervisor{
+		store:          store,
+		limits:         limits,
+		instanceID:     instanceID,
+		environment:    aws.GetCloudEnvironmentName(),
+		logger:         logger,
+		eventsProducer: eventsProducer,
+	}
+}
+
+// Shutdown performs graceful shutdown tasks for the supervisor.
+func (s *MultitenantDatabaseRebalanceSupervisor) Shutdown() {
+	s.logger.Debug("Shutting down multitenant database rebalance supervisor")
+}
+
+// Do looks for work to be done on any pending rebalances and attempts to schedule the required work.
+func (s *MultitenantDatabaseRebalanceSupervisor) Do() error {
+	rebalances, err := s.store.GetUnlockedMultitenantDatabaseRebalancesPendingWork()
+	if err != nil {
+		s.logger.WithError(err).Warn("Failed to query for pending work")
+		return nil
+	}
+
+	for _, rebalance := range rebalances {
+		s.Supervise(rebalance)
+	}
+
+	return nil
+}
+
+// Supervise schedules the required work on the given rebalance.
+func (s *MultitenantDatabaseRebalanceSupervisor) Supervise(rebalance *model.MultitenantDatabaseRebalance) {
+	logger := s.logger.WithFields(log.Fields{
+		"multitenantDatabaseRebalance": rebalance.ID,
+	})
+
+	lock := newMultitenantDatabaseRebalanceLock(rebalance.ID, s.instanceID, s.store, logger)
+	if !lock.TryLock() {
+		return
+	}
+	defer lock.Unlock()
+
+	// Before working on the rebalance, it is crucial that we ensure that it
+	// was not updated to a new state by another provisioning server.
+	originalState := rebalance.State
+	rebalance, err := s.store.GetMultitenantDatabaseRebalance(rebalance.ID)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get refreshed rebalance")
+		return
+	}
+	if rebalance.State != originalState {
+		logger.WithField("oldRebalanceState", originalState).
+			WithField("newRebalanceState", rebalance.State).
+			Warn("Another provisioner has worked on this rebalance; skipping...")
+		return
+	}
+
+	logger.Debugf("Supervising rebalance in state %s", rebalance.State)
+
+	oldState := rebalance.State
+	rebalance.State = s.transitionRebalance(rebalance, logger)
+	if rebalance.State != model.MultitenantDatabaseRebalanceStateRequested &&
+		rebalance.State != model.MultitenantDatabaseRebalanceStateInProgress {
+		rebalance.CompleteAt = model.GetMillis()
+	}
+
+	err = s.store.UpdateMultitenantDatabaseRebalance(rebalance)
+	if err != nil {
+		logger.WithError(err).Errorf("Failed to update rebalance with state %s", rebalance.State)
+		return
+	}
+
+	if rebalance.State == oldState {
+		return
+	}
+
+	webhookPayload := &model.WebhookPayload{
+		Type:      model.TypeMultitenantDatabaseRebalance,
+		ID:        rebalance.ID,
+		NewState:  string(rebalance.State),
+		OldState:  string(oldState),
+		Timestamp: time.Now().UnixNano(),
+		ExtraData: map[string]string{"VPC": rebalance.VpcID, "Environment": s.environment},
+	}
+	err = webhook.SendToAllWebhooks(s.store, webhookPayload, logger.WithField("webhookEvent", webhookPayload.NewState))
+	if err != nil {
+		logger.WithError(err).Error("Unable to process and send webhooks")
+	}
+
+	logger.Debugf("Transitioned rebalance from %s to %s", oldState, rebalance.State)
+}
+
+// transitionRebalance refreshes the moves of the given rebalance, starts new
+// migrations up to the concurrency limit and returns the resulting state.
+func (s *MultitenantDatabaseRebalanceSupervisor) transitionRebalance(rebalance *model.MultitenantDatabaseRebalance, logger log.FieldLogger) model.MultitenantDatabaseRebalanceState {
+	for _, move := range rebalance.Moves {
+		if move.State != model.MultitenantDatabaseRebalanceMoveInProgress {
+			continue
+		}
+		s.refreshMove(move, logger.WithField("installation", move.InstallationID))
+	}
+
+	inProgress := rebalance.Moves.CountInProgress()
+	for _, move := range rebalance.Moves {
+		if inProgress >= rebalance.MaxConcurrentMigrations {
+			break
+		}
+		if move.State != model.MultitenantDatabaseRebalanceMovePending {
+			continue
+		}
+
+		moveLogger := logger.WithField("installation", move.InstallationID)
+		err := s.startMove(move, moveLogger)
+		if errors.Is(err, errRebalanceMoveInvalid) {
+			moveLogger.WithError(err).Warn("Skipping rebalance move")
+			move.State = model.MultitenantDatabaseRebalanceMoveSkipped
+			move.Message = err.Error()
+			continue
+		}
+		if err != nil {
+			move.Attempts++
+			move.Message = err.Error()
+			if move.Attempts >= model.MaxMultitenantDatabaseRebalanceMoveAttempts {
+				moveLogger.WithError(err).Errorf("Failed to start rebalance move after %d attempts", move.Attempts)
+				move.State = model.MultitenantDatabaseRebalanceMoveFailed
+				continue
+			}
+			moveLogger.WithError(err).Warnf("Failed to start rebalance move (attempt %d)", move.Attempts)
+			continue
+		}
+		move.Message = ""
+		inProgress++
+	}
+
+	var failed bool
+	for _, move := range rebalance.Moves {
+		if !move.IsFinished() {
+			return model.MultitenantDatabaseRebalanceStateInProgress
+		}
+		if move.State == model.MultitenantDatabaseRebalanceMoveFailed {
+			failed = true
+		}
+	}
+	if failed {
+		return model.MultitenantDatabaseRebalanceStateFailed
+	}
+
+	return model.MultitenantDatabaseRebalanceStateSucceeded
+}
+
+func (s *MultitenantDatabaseRebalanceSupervisor) refreshMove(move *model.MultitenantDatabaseRebalanceMove, logger log.FieldLogger) {
+	dbMigration, err := s.store.GetInstallationDBMigrationOperation(move.DBMigrationID)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get installation db migration")
+		return
+	}
+	if dbMigration == nil {
+		move.State = model.MultitenantDatabaseRebalanceMoveFailed
+		move.Message = "installation db migration not found"
+		return
+	}
+
+	switch dbMigration.State {
+	case model.InstallationDBMigrationStateSucceeded,
+		model.InstallationDBMigrationStateCommitted:
+		logger.Info("Rebalance move finished successfully")
+		move.State = model.MultitenantDatabaseRebalanceMoveSucceeded
+		move.Message = ""
+	case model.InstallationDBMigrationStateFailed,
+		model.InstallationDBMigrationStateRollbackFinished,
+		model.InstallationDBMigrationStateDeleted:
+		logger.Warnf("Rebalance move finished with migration in state %s", dbMigration.State)
+		move.State = model.MultitenantDatabaseRebalanceMoveFailed
+		move.Message = string(dbMigration.State)
+	}
+}
+
+func (s *MultitenantDatabaseRebalanceSupervisor) startMove(move *model.MultitenantDatabaseRebalanceMove, logger log.FieldLogger) error {
+	installation, lock, err := getAndLockInstallation(s.store, move.InstallationID, s.instanceID, logger)
+	if err != nil {
+		return errors.Wrap(err, "failed to get and lock installation")
+	}
+	defer lock.Unlock()
+
+	if !model.IsRebalanceMovable(installation) {
+		return errors.Wrapf(errRebalanceMoveInvalid, "installation is in state %s with database %s", installation.State, installation.Database)
+	}
+
+	currentDB, err := s.store.GetMultitenantDatabaseForInstallationID(installation.ID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get current multitenant database")
+	}
+	if currentDB.ID != move.SourceDatabaseID {
+		return errors.Wrapf(errRebalanceMoveInvalid, "installation was moved to multitenant database %s", currentDB.ID)
+	}
+
+	destinationDB, err := s.store.GetMultitenantDatabase(move.DestinationDatabaseID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get destination multitenant database")
+	}
+	if destinationDB == nil || destinationDB.DeleteAt != 0 {
+		return errors.Wrap(errRebalanceMoveInvalid, "destination multitenant database no longer exists")
+	}
+	if destinationDB.VpcID != currentDB.VpcID {
+		return errors.Wrap(errRebalanceMoveInvalid, "databases VPCs do not match")
+	}
+
+	maxWeight := float64(s.limits.GetMultitenantDatabaseMaxInstallations(destinationDB.DatabaseType))
+	err = common.ValidateDBMigrationDestination(s.store, destinationDB, installation.ID, maxWeight)
+	if err != nil {
+		return errors.Wrap(errRebalanceMoveInvalid, err.Error())
+	}
+
+	uncommittedMigrations, err := s.store.GetInstallationDBMigrationOperations(&model.InstallationDBMigrationFilter{
+		Paging:         model.AllPagesNotDeleted(),
+		InstallationID: installation.ID,
+		States:         []model.InstallationDBMigrationOperationState{model.InstallationDBMigrationStateSucceeded},
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to query succeeded installation db migrations")
+	}
+	if len(uncommittedMigrations) > 0 {
+		return errors.Wrap(errRebalanceMoveInvalid, "installation has a succeeded db migration that is not committed")
+	}
+
+	dbMigration := &model.InstallationDBMigrationOperation{
+		InstallationID:         installation.ID,
+		SourceDatabase:         installation.Database,
+		DestinationDatabase:    model.InstallationDatabaseMultiTenantRDSPostgres,
+		SourceMultiTenant:      &model.MultiTenantDBMigrationData{DatabaseID: currentDB.ID},
+		DestinationMultiTenant: &model.MultiTenantDBMigrationData{DatabaseID: destinationDB.ID},
+	}
+
+	oldInstallationState := installation.State
+	dbMigration, err = s.store.TriggerInstallationDBMigration(dbMigration, installation)
+	if err != nil {
+		return errors.Wrap(err, "failed to trigger installation db migration")
+	}
+
+	move.State = model.MultitenantDatabaseRebalanceMoveInProgress
+	move.DBMigrationID = dbMigration.ID
+	logger.Infof("Started installation db migration %s to multitenant database %s", dbMigration.ID, destinationDB.ID)
+
+	webhookPayload := &model.WebhookPayload{
+		Type:      model.TypeInstallationDBMigration,
+		ID:        dbMigration.ID,
+		NewState:  string(dbMigration.State),
+		OldState:  "n/a",
+		Timestamp: time.Now().UnixNano(),
+		ExtraData: map[string]string{"Installation": dbMigration.InstallationID, "Environment": s.environment},
+	}
+	err = webhook.SendToAllWebhooks(s.store, webhookPayload, logger.WithField("webhookEvent", webhookPayload.NewState))
+	if err != nil {
+		logger.WithError(err).Error("Unable to process and send webhooks")
+	}
+
+	err = s.eventsProducer.ProduceInstallationStateChangeEvent(installation, oldInstallationState)
+	if err != nil {
+		logger.WithError(err).Error("Failed to create installation state change event")
+	}
+
+	return nil
+}
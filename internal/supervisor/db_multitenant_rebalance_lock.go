@@ -0,0 +1,47 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package supervisor
+
+import log "github.com/sirupsen/logrus"
+
+type multitenantDatabaseRebalanceLockStore interface {
+	LockMultitenantDatabaseRebalances(ids []string, lockerID string) (bool, error)
+	UnlockMultitenantDatabaseRebalances(ids []string, lockerID string, force bool) (bool, error)
+}
+
+type multitenantDatabaseRebalanceLock struct {
+	ids      []string
+	lockerID string
+	store    multitenantDatabaseRebalanceLockStore
+	logger   log.FieldLogger
+}
+
+func newMultitenantDatabaseRebalanceLock(id, lockerID string, store multitenantDatabaseRebalanceLockStore, logger log.FieldLogger) *multitenantDatabaseRebalanceLock {
+	return &multitenantDatabaseRebalanceLock{
+		ids:      []string{id},
+		lockerID: lockerID,
+		store:    store,
+		logger:   logger,
+	}
+}
+
+func (l *multitenantDatabaseRebalanceLock) TryLock() bool {
+	locked, err := l.store.LockMultitenantDatabaseRebalances(l.ids, l.lockerID)
+	if err != nil {
+		l.logger.WithError(err).Error("failed to lock multitenantDatabaseRebalances")
+		return false
+	}
+
+	return locked
+}
+
+func (l *multitenantDatabaseRebalanceLock) Unlock() {
+	unlocked, err := l.store.UnlockMultitenantDatabaseRebalances(l.ids, l.lockerID, false)
+	if err != nil {
+		l.logger.WithError(err).Error("failed to unlock multitenantDatabaseRebalances")
+	} else if !unlocked {
+		l.logger.Error("failed to release lock for multitenantDatabaseRebalances")
+	}
+}
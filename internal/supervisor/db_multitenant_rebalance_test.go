@@ -0,0 +1,255 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package supervisor_test
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-cloud/internal/supervisor"
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockMultitenantDatabaseRebalanceStore struct {
+	Rebalance           *model.MultitenantDatabaseRebalance
+	Installations       map[string]*model.Installation
+	Databases           map[string]*model.MultitenantDatabase
+	DBMigrations        map[string]*model.InstallationDBMigrationOperation
+	TriggeredMigrations []*model.InstallationDBMigrationOperation
+
+	UpdateRebalanceCalls int
+}
+
+func (m *mockMultitenantDatabaseRebalanceStore) GetUnlockedMultitenantDatabaseRebalancesPendingWork() ([]*model.MultitenantDatabaseRebalance, error) {
+	if m.Rebalance == nil {
+		return nil, nil
+	}
+	return []*model.MultitenantDatabaseRebalance{m.Rebalance}, nil
+}
+
+func (m *mockMultitenantDatabaseRebalanceStore) GetMultitenantDatabaseRebalance(id string) (*model.MultitenantDatabaseRebalance, error) {
+	return m.Rebalance, nil
+}
+
+func (m *mockMultitenantDatabaseRebalanceStore) UpdateMultitenantDatabaseRebalance(rebalance *model.MultitenantDatabaseRebalance) error {
+	m.UpdateRebalanceCalls++
+	return nil
+}
+
+func (m *mockMultitenantDatabaseRebalanceStore) LockMultitenantDatabaseRebalances(ids []string, lockerID string) (bool, error) {
+	return true, nil
+}
+
+func (m *mockMultitenantDatabaseRebalanceStore) UnlockMultitenantDatabaseRebalances(ids []string, lockerID string, force bool) (bool, error) {
+	return true, nil
+}
+
+func (m *mockMultitenantDatabaseRebalanceStore) GetInstallation(installationID string, includeGroupConfig, includeGroupConfigOverrides bool) (*model.Installation, error) {
+	return m.Installations[installationID], nil
+}
+
+func (m *mockMultitenantDatabaseRebalanceStore) LockInstallation(installationID, lockerID string) (bool, error) {
+	return true, nil
+}
+
+func (m *mockMultitenantDatabaseRebalanceStore) UnlockInstallation(installationID, lockerID string, force bool) (bool, error) {
+	return true, nil
+}
+
+func (m *mockMultitenantDatabaseRebalanceStore) TriggerInstallationDBMigration(dbMigrationOp *model.InstallationDBMigrationOperation, installation *model.Installation) (*model.InstallationDBMigrationOperation, error) {
+	dbMigrationOp.ID = model.NewID()
+	dbMigrationOp.State = model.InstallationDBMigrationStateRequested
+	installation.State = model.InstallationStateDBMigrationInProgress
+	m.TriggeredMigrations = append(m.TriggeredMigrations, dbMigrationOp)
+	return dbMigrationOp, nil
+}
+
+func (m *mockMultitenantDatabaseRebalanceStore) GetInstallationDBMigrationOperation(id string) (*model.InstallationDBMigrationOperation, error) {
+	return m.DBMigrations[id], nil
+}
+
+func (m *mockMultitenantDatabaseRebalanceStore) GetInstallationDBMigrationOperations(filter *model.InstallationDBMigrationFilter) ([]*model.InstallationDBMigrationOperation, error) {
+	return nil, nil
+}
+
+func (m *mockMultitenantDatabaseRebalanceStore) GetMultitenantDatabase(multitenantdatabaseID string) (*model.MultitenantDatabase, error) {
+	return m.Databases[multitenantdatabaseID], nil
+}
+
+func (m *mockMultitenantDatabaseRebalanceStore) GetMultitenantDatabaseForInstallationID(installationID string) (*model.MultitenantDatabase, error) {
+	for _, database := range m.Databases {
+		if database.Installations.Contains(installationID) {
+			return database, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockMultitenantDatabaseRebalanceStore) GetInstallationsTotalDatabaseWeight(installationIDs []string) (float64, error) {
+	return float64(len(installationIDs)), nil
+}
+
+func (m *mockMultitenantDatabaseRebalanceStore) GetWebhooks(filter *model.WebhookFilter) ([]*model.Webhook, error) {
+	return nil, nil
+}
+
+func newMockMultitenantDatabaseRebalanceStore(installationState string) *mockMultitenantDatabaseRebalanceStore {
+	return &mockMultitenantDatabaseRebalanceStore{
+		Installations: map[string]*model.Installation{
+			"a": {ID: "a", State: installationState, Database: model.InstallationDatabaseMultiTenantRDSPostgres},
+			"b": {ID: "b", State: installationState, Database: model.InstallationDatabaseMultiTenantRDSPostgres},
+		},
+		Databases: map[string]*model.MultitenantDatabase{
+			"db1": {ID: "db1", VpcID: "vpc1", Installations: model.MultitenantDatabaseInstallations{"a", "b"}},
+			"db2": {ID: "db2", VpcID: "vpc1"},
+		},
+		DBMigrations: map[string]*model.InstallationDBMigrationOperation{},
+		Rebalance: &model.MultitenantDatabaseRebalance{
+			ID:                      model.NewID(),
+			VpcID:                   "vpc1",
+			State:                   model.MultitenantDatabaseRebalanceStateRequested,
+			MaxConcurrentMigrations: 1,
+			Moves: model.MultitenantDatabaseRebalanceMoves{
+				{InstallationID: "a", SourceDatabaseID: "db1", DestinationDatabaseID: "db2", State: model.MultitenantDatabaseRebalanceMovePending},
+				{InstallationID: "b", SourceDatabaseID: "db1", DestinationDatabaseID: "db2", State: model.MultitenantDatabaseRebalanceMovePending},
+			},
+		},
+	}
+}
+
+func TestMultitenantDatabaseRebalanceSupervisor_Do(t *testing.T) {
+	t.Run("no rebalances pending work", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		mockStore := &mockMultitenantDatabaseRebalanceStore{}
+
+		rebalanceSupervisor := supervisor.NewMultitenantDatabaseRebalanceSupervisor(mockStore, &mockAWS{}, &mockMultitenantDatabaseLimits{}, &mockEventProducer{}, "instanceID", logger)
+		err := rebalanceSupervisor.Do()
+		require.NoError(t, err)
+
+		require.Equal(t, 0, mockStore.UpdateRebalanceCalls)
+	})
+}
+
+func TestMultitenantDatabaseRebalanceSupervisor_Supervise(t *testing.T) {
+	t.Run("start moves up to concurrency limit", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		mockStore := newMockMultitenantDatabaseRebalanceStore(model.InstallationStateHibernating)
+
+		rebalanceSupervisor := supervisor.NewMultitenantDatabaseRebalanceSupervisor(mockStore, &mockAWS{}, &mockMultitenantDatabaseLimits{}, &mockEventProducer{}, "instanceID", logger)
+		rebalanceSupervisor.Supervise(mockStore.Rebalance)
+
+		rebalance := mockStore.Rebalance
+		assert.Equal(t, model.MultitenantDatabaseRebalanceStateInProgress, rebalance.State)
+		assert.Equal(t, 1, mockStore.UpdateRebalanceCalls)
+		require.Len(t, mockStore.TriggeredMigrations, 1)
+		migration := mockStore.TriggeredMigrations[0]
+		assert.Equal(t, "a", migration.InstallationID)
+		assert.Equal(t, "db1", migration.SourceMultiTenant.DatabaseID)
+		assert.Equal(t, "db2", migration.DestinationMultiTenant.DatabaseID)
+		assert.Equal(t, model.MultitenantDatabaseRebalanceMoveInProgress, rebalance.Moves[0].State)
+		assert.Equal(t, migration.ID, rebalance.Moves[0].DBMigrationID)
+		assert.Equal(t, model.MultitenantDatabaseRebalanceMovePending, rebalance.Moves[1].State)
+	})
+
+	t.Run("skip installations that are not hibernating", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		mockStore := newMockMultitenantDatabaseRebalanceStore(model.InstallationStateStable)
+
+		rebalanceSupervisor := supervisor.NewMultitenantDatabaseRebalanceSupervisor(mockStore, &mockAWS{}, &mockMultitenantDatabaseLimits{}, &mockEventProducer{}, "instanceID", logger)
+		rebalanceSupervisor.Supervise(mockStore.Rebalance)
+
+		rebalance := mockStore.Rebalance
+		assert.Empty(t, mockStore.TriggeredMigrations)
+		assert.Equal(t, model.MultitenantDatabaseRebalanceMoveSkipped, rebalance.Moves[0].State)
+		assert.Equal(t, model.MultitenantDatabaseRebalanceMoveSkipped, rebalance.Moves[1].State)
+		assert.Equal(t, model.MultitenantDatabaseRebalanceStateSucceeded, rebalance.State)
+		assert.NotZero(t, rebalance.CompleteAt)
+	})
+
+	t.Run("skip moves to a full destination", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		mockStore := newMockMultitenantDatabaseRebalanceStore(model.InstallationStateHibernating)
+		mockStore.Databases["db2"].Installations = model.MultitenantDatabaseInstallations{"c", "d", "e", "f", "g", "h", "i", "j", "k", "l"}
+
+		rebalanceSupervisor := supervisor.NewMultitenantDatabaseRebalanceSupervisor(mockStore, &mockAWS{}, &mockMultitenantDatabaseLimits{}, &mockEventProducer{}, "instanceID", logger)
+		rebalanceSupervisor.Supervise(mockStore.Rebalance)
+
+		rebalance := mockStore.Rebalance
+		assert.Empty(t, mockStore.TriggeredMigrations)
+		assert.Equal(t, model.MultitenantDatabaseRebalanceMoveSkipped, rebalance.Moves[0].State)
+		assert.Equal(t, model.MultitenantDatabaseRebalanceMoveSkipped, rebalance.Moves[1].State)
+	})
+
+	t.Run("retry moves that fail to start", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		mockStore := newMockMultitenantDatabaseRebalanceStore(model.InstallationStateHibernating)
+		mockStore.Rebalance.Moves = mockStore.Rebalance.Moves[:1]
+		delete(mockStore.Installations, "a")
+
+		rebalanceSupervisor := supervisor.NewMultitenantDatabaseRebalanceSupervisor(mockStore, &mockAWS{}, &mockMultitenantDatabaseLimits{}, &mockEventProducer{}, "instanceID", logger)
+		rebalanceSupervisor.Supervise(mockStore.Rebalance)
+
+		move := mockStore.Rebalance.Moves[0]
+		assert.Empty(t, mockStore.TriggeredMigrations)
+		assert.Equal(t, model.MultitenantDatabaseRebalanceMovePending, move.State)
+		assert.Equal(t, 1, move.Attempts)
+		assert.Contains(t, move.Message, "could not find the installation")
+		assert.Equal(t, model.MultitenantDatabaseRebalanceStateInProgress, mockStore.Rebalance.State)
+
+		for i := 1; i < model.MaxMultitenantDatabaseRebalanceMoveAttempts; i++ {
+			rebalanceSupervisor.Supervise(mockStore.Rebalance)
+		}
+
+		assert.Equal(t, model.MultitenantDatabaseRebalanceMoveFailed, move.State)
+		assert.Equal(t, model.MaxMultitenantDatabaseRebalanceMoveAttempts, move.Attempts)
+		assert.Equal(t, model.MultitenantDatabaseRebalanceStateFailed, mockStore.Rebalance.State)
+		assert.NotZero(t, mockStore.Rebalance.CompleteAt)
+	})
+
+	t.Run("finish moves from migration state", func(t *testing.T) {
+		for _, testCase := range []struct {
+			description    string
+			migrationState model.InstallationDBMigrationOperationState
+			expectedState  model.MultitenantDatabaseRebalanceState
+		}{
+			{
+				description:    "when migration succeeded",
+				migrationState: model.InstallationDBMigrationStateSucceeded,
+				expectedState:  model.MultitenantDatabaseRebalanceStateSucceeded,
+			},
+			{
+				description:    "when migration failed",
+				migrationState: model.InstallationDBMigrationStateFailed,
+				expectedState:  model.MultitenantDatabaseRebalanceStateFailed,
+			},
+			{
+				description:    "when migration in progress",
+				migrationState: model.InstallationDBMigrationStateBackupInProgress,
+				expectedState:  model.MultitenantDatabaseRebalanceStateInProgress,
+			},
+		} {
+			t.Run(testCase.description, func(t *testing.T) {
+				logger := testlib.MakeLogger(t)
+				mockStore := newMockMultitenantDatabaseRebalanceStore(model.InstallationStateHibernating)
+				mockStore.Rebalance.State = model.MultitenantDatabaseRebalanceStateInProgress
+				mockStore.Rebalance.Moves = mockStore.Rebalance.Moves[:1]
+				mockStore.Rebalance.Moves[0].State = model.MultitenantDatabaseRebalanceMoveInProgress
+				mockStore.Rebalance.Moves[0].DBMigrationID = "migration"
+				mockStore.DBMigrations["migration"] = &model.InstallationDBMigrationOperation{
+					ID:    "migration",
+					State: testCase.migrationState,
+				}
+
+				rebalanceSupervisor := supervisor.NewMultitenantDatabaseRebalanceSupervisor(mockStore, &mockAWS{}, &mockMultitenantDatabaseLimits{}, &mockEventProducer{}, "instanceID", logger)
+				rebalanceSupervisor.Supervise(mockStore.Rebalance)
+
+				assert.Equal(t, testCase.expectedState, mockStore.Rebalance.State)
+				assert.Empty(t, mockStore.TriggeredMigrations)
+			})
+		}
+	})
+}
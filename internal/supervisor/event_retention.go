@@ -0,0 +1,274 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package supervisor
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// eventRetentionMaxBatchesPerRun limits the time spent by a single run
+	// of the supervisor on big backlogs.
+	eventRetentionMaxBatchesPerRun = 10
+
+	eventArchiveContentType = "application/gzip"
+)
+
+// eventRetentionStore abstracts the database operations required by the supervisor.
+type eventRetentionStore interface {
+	GetStateChangeEventsToPrune(olderThan int64, limit uint64) ([]*model.StateChangeEventData, error)
+	GetEventDeliveriesForEvents(eventIDs []string) ([]*model.EventDelivery, error)
+	DeleteStateChangeEvents(eventIDs []string) (*model.EventsPruneResult, error)
+	GetEventDeliveriesToPrune(olderThan int64, limit uint64) ([]*model.EventDelivery, error)
+	DeleteEventDeliveries(deliveryIDs []string) (int64, error)
+}
+
+// eventArchiver stores archived events.
+type eventArchiver interface {
+	S3PutObject(bucketName, path, contentType string, body []byte) error
+}
+
+// eventRetentionMetrics records pruned event rows.
+type eventRetentionMetrics interface {
+	ObserveEventsPruned(result *model.EventsPruneResult)
+}
+
+// EventRetentionConfig configures the EventRetentionSupervisor.
+type EventRetentionConfig struct {
+	// EventMaxAge is the age after which events, together with their
+	// state change data and deliveries, are pruned. Zero disables pruning.
+	EventMaxAge time.Duration
+	// EventDeliveryMaxAge is the time since the last delivery attempt
+	// after which delivered deliveries are pruned. Zero disables pruning.
+	EventDeliveryMaxAge time.Duration
+	// BatchSize is the number of rows pruned at once.
+	BatchSize uint64
+	// ArchiveBucket is the S3 bucket to which pruned rows are archived
+	// as gzip compressed NDJSON. Empty disables archival.
+	ArchiveBucket string
+}
+
+// Validate validates the EventRetentionConfig.
+func (c EventRetentionConfig) Validate() error {
+	if c.EventMaxAge < 0 || c.EventDeliveryMaxAge < 0 {
+		return errors.New("event retention max age cannot be negative")
+	}
+	if c.BatchSize == 0 {
+		return errors.New("event retention batch size must be greater than 0")
+	}
+
+	return nil
+}
+
+// EventRetentionSupervisor periodically prunes old events and event
+// deliveries, optionally archiving them first. Undelivered events, including
+// failed deliveries kept for replay, are never pruned.
+type EventRetentionSupervisor struct {
+	store    eventRetentionStore
+	archiver eventArchiver
+	metrics  eventRetentionMetrics
+	config   EventRetentionConfig
+	logger   log.FieldLogger
+}
+
+// NewEventRetentionSupervisor creates a new EventRetentionSupervisor.
+func NewEventRetentionSupervisor(
+	store eventRetentionStore,
+	archiver eventArchiver,
+	metrics eventRetentionMetrics,
+	config EventRetentionConfig,
+	logger log.FieldLogger) *EventRetentionSupervisor {
+	return &EventRetentionSupervisor{
+		store:    store,
+		archiver: archiver,
+		metrics:  metrics,
+		config:   config,
+		logger:   logger.WithField("supervisor", "event-retention"),
+	}
+}
+
+// Shutdown performs graceful shutdown tasks for the supervisor.
+func (s *EventRetentionSupervisor) Shutdown() {
+	s.logger.Debug("Shutting down event retention supervisor")
+}
+
+// Do prunes events and event deliveries exceeding their max age.
+func (s *EventRetentionSupervisor) Do() error {
+	result := &model.EventsPruneResult{}
+
+	if s.config.EventMaxAge > 0 {
+		err := s.pruneEvents(result)
+		if err != nil {
+			s.logger.WithError(err).Error("Failed to prune events")
+		}
+	}
+	if s.config.EventDeliveryMaxAge > 0 {
+		err := s.pruneEventDeliveries(result)
+		if err != nil {
+			s.logger.WithError(err).Error("Failed to prune event deliveries")
+		}
+	}
+
+	s.metrics.ObserveEventsPruned(result)
+	if !result.IsEmpty() {
+		s.logger.WithFields(log.Fields{
+			"events":              result.Events,
+			"state-change-events": result.StateChangeEvents,
+			"event-deliveries":    result.EventDeliveries,
+		}).Info("Pruned old events")
+	}
+
+	return nil
+}
+
+func (s *EventRetentionSupervisor) pruneEvents(result *model.EventsPruneResult) error {
+	olderThan := model.GetMillisAtTime(time.Now().Add(-s.config.EventMaxAge))
+
+	for i := 0; i < eventRetentionMaxBatchesPerRun; i++ {
+		events, err := s.store.GetStateChangeEventsToPrune(olderThan, s.config.BatchSize)
+		if err != nil {
+			return errors.Wrap(err, "failed to get events to prune")
+		}
+		if len(events) == 0 {
+			return nil
+		}
+
+		eventIDs := make([]string, 0, len(events))
+		for _, event := range events {
+			eventIDs = append(eventIDs, event.Event.ID)
+		}
+
+		if s.config.ArchiveBucket != "" {
+			err = s.archiveEvents(events)
+			if err != nil {
+				return errors.Wrap(err, "failed to archive events")
+			}
+		}
+
+		deleted, err := s.store.DeleteStateChangeEvents(eventIDs)
+		if err != nil {
+			return errors.Wrap(err, "failed to delete events")
+		}
+		result.Add(deleted)
+
+		if uint64(len(events)) < s.config.BatchSize {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+func (s *EventRetentionSupervisor) archiveEvents(events []*model.StateChangeEventData) error {
+	eventIDs := make([]string, 0, len(events))
+	for _, event := range events {
+		eventIDs = append(eventIDs, event.Event.ID)
+	}
+
+	deliveries, err := s.store.GetEventDeliveriesForEvents(eventIDs)
+	if err != nil {
+		return errors.Wrap(err, "failed to get event deliveries")
+	}
+	deliveriesByEvent := map[string][]*model.EventDelivery{}
+	for _, delivery := range deliveries {
+		deliveriesByEvent[delivery.EventID] = append(deliveriesByEvent[delivery.EventID], delivery)
+	}
+
+	records := make([]interface{}, 0, len(events))
+	for _, event := range events {
+		records = append(records, model.ArchivedStateChangeEvent{
+			StateChangeEventData: *event,
+			Deliveries:           deliveriesByEvent[event.Event.ID],
+		})
+	}
+
+	return s.archive("state-change-events", records)
+}
+
+func (s *EventRetentionSupervisor) pruneEventDeliveries(result *model.EventsPruneResult) error {
+	olderThan := model.GetMillisAtTime(time.Now().Add(-s.config.EventDeliveryMaxAge))
+
+	for i := 0; i < eventRetentionMaxBatchesPerRun; i++ {
+		deliveries, err := s.store.GetEventDeliveriesToPrune(olderThan, s.config.BatchSize)
+		if err != nil {
+			return errors.Wrap(err, "failed to get event deliveries to prune")
+		}
+		if len(deliveries) == 0 {
+			return nil
+		}
+
+		deliveryIDs := make([]string, 0, len(deliveries))
+		records := make([]interface{}, 0, len(deliveries))
+		for _, delivery := range deliveries {
+			deliveryIDs = append(deliveryIDs, delivery.ID)
+			records = append(records, delivery)
+		}
+
+		if s.config.ArchiveBucket != "" {
+			err = s.archive("event-deliveries", records)
+			if err != nil {
+				return errors.Wrap(err, "failed to archive event deliveries")
+			}
+		}
+
+		deleted, err := s.store.DeleteEventDeliveries(deliveryIDs)
+		if err != nil {
+			return errors.Wrap(err, "failed to delete event deliveries")
+		}
+		result.EventDeliveries += deleted
+
+		if uint64(len(deliveries)) < s.config.BatchSize {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// archive uploads the records to the archive bucket as gzip compressed NDJSON.
+func (s *EventRetentionSupervisor) archive(prefix string, records []interface{}) error {
+	body, err := compressNDJSON(records)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode archive")
+	}
+
+	now := time.Now().UTC()
+	key := fmt.Sprintf("%s/%s/%d-%s.ndjson.gz", prefix, now.Format("2006/01/02"), model.GetMillisAtTime(now), model.NewID())
+
+	err = s.archiver.S3PutObject(s.config.ArchiveBucket, key, eventArchiveContentType, body)
+	if err != nil {
+		return errors.Wrap(err, "failed to upload archive")
+	}
+	s.logger.WithField("key", key).Debugf("Archived %d %s records", len(records), prefix)
+
+	return nil
+}
+
+// compressNDJSON encodes the records as newline delimited JSON compressed with gzip.
+func compressNDJSON(records []interface{}) ([]byte, error) {
+	var buffer bytes.Buffer
+	writer := gzip.NewWriter(&buffer)
+	encoder := json.NewEncoder(writer)
+	for _, record := range records {
+		err := encoder.Encode(record)
+		if err != nil {
+			return nil, err
+		}
+	}
+	err := writer.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
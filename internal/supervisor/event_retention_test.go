@@ -0,0 +1,182 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package supervisor_test
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/internal/supervisor"
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockEventRetentionStore struct {
+	Events            []*model.StateChangeEventData
+	Deliveries        []*model.EventDelivery
+	DeletedEventIDs   []string
+	DeletedDeliveries []string
+}
+
+func (m *mockEventRetentionStore) GetStateChangeEventsToPrune(olderThan int64, limit uint64) ([]*model.StateChangeEventData, error) {
+	var events []*model.StateChangeEventData
+	for _, event := range m.Events {
+		if event.Event.Timestamp < olderThan && !contains(m.DeletedEventIDs, event.Event.ID) {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+func (m *mockEventRetentionStore) GetEventDeliveriesForEvents(eventIDs []string) ([]*model.EventDelivery, error) {
+	var deliveries []*model.EventDelivery
+	for _, delivery := range m.Deliveries {
+		if contains(eventIDs, delivery.EventID) {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	return deliveries, nil
+}
+
+func (m *mockEventRetentionStore) DeleteStateChangeEvents(eventIDs []string) (*model.EventsPruneResult, error) {
+	m.DeletedEventIDs = append(m.DeletedEventIDs, eventIDs...)
+	return &model.EventsPruneResult{
+		Events:            int64(len(eventIDs)),
+		StateChangeEvents: int64(len(eventIDs)),
+	}, nil
+}
+
+func (m *mockEventRetentionStore) GetEventDeliveriesToPrune(olderThan int64, limit uint64) ([]*model.EventDelivery, error) {
+	var deliveries []*model.EventDelivery
+	for _, delivery := range m.Deliveries {
+		if delivery.LastAttempt < olderThan && !contains(m.DeletedDeliveries, delivery.ID) {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	return deliveries, nil
+}
+
+func (m *mockEventRetentionStore) DeleteEventDeliveries(deliveryIDs []string) (int64, error) {
+	m.DeletedDeliveries = append(m.DeletedDeliveries, deliveryIDs...)
+	return int64(len(deliveryIDs)), nil
+}
+
+type mockEventArchiver struct {
+	Objects map[string][]byte
+	Err     error
+}
+
+func (m *mockEventArchiver) S3PutObject(bucketName, path, contentType string, body []byte) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	m.Objects[bucketName+"/"+path] = body
+	return nil
+}
+
+type mockEventRetentionMetrics struct {
+	Result model.EventsPruneResult
+}
+
+func (m *mockEventRetentionMetrics) ObserveEventsPruned(result *model.EventsPruneResult) {
+	m.Result.Add(result)
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+func TestEventRetentionSupervisor_Do(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	oldTimestamp := model.GetMillisAtTime(time.Now().Add(-48 * time.Hour))
+
+	newStore := func() *mockEventRetentionStore {
+		return &mockEventRetentionStore{
+			Events: []*model.StateChangeEventData{
+				{Event: model.Event{ID: "event1", Timestamp: oldTimestamp}},
+				{Event: model.Event{ID: "event2", Timestamp: model.GetMillis()}},
+			},
+			Deliveries: []*model.EventDelivery{
+				{ID: "delivery1", EventID: "event1", Status: model.EventDeliveryDelivered, LastAttempt: oldTimestamp},
+				{ID: "delivery2", EventID: "event2", Status: model.EventDeliveryDelivered, LastAttempt: model.GetMillis()},
+			},
+		}
+	}
+	config := supervisor.EventRetentionConfig{
+		EventMaxAge:         24 * time.Hour,
+		EventDeliveryMaxAge: 24 * time.Hour,
+		BatchSize:           100,
+	}
+
+	t.Run("prune without archive", func(t *testing.T) {
+		mockStore := newStore()
+		mockMetrics := &mockEventRetentionMetrics{}
+
+		retentionSupervisor := supervisor.NewEventRetentionSupervisor(mockStore, &mockEventArchiver{}, mockMetrics, config, logger)
+		err := retentionSupervisor.Do()
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"event1"}, mockStore.DeletedEventIDs)
+		assert.Equal(t, []string{"delivery1"}, mockStore.DeletedDeliveries)
+		assert.Equal(t, int64(1), mockMetrics.Result.Events)
+		assert.Equal(t, int64(1), mockMetrics.Result.EventDeliveries)
+	})
+
+	t.Run("archive before pruning", func(t *testing.T) {
+		mockStore := newStore()
+		mockArchiver := &mockEventArchiver{Objects: map[string][]byte{}}
+		archiveConfig := config
+		archiveConfig.ArchiveBucket = "archive"
+
+		retentionSupervisor := supervisor.NewEventRetentionSupervisor(mockStore, mockArchiver, &mockEventRetentionMetrics{}, archiveConfig, logger)
+		err := retentionSupervisor.Do()
+		require.NoError(t, err)
+		require.Len(t, mockArchiver.Objects, 2)
+
+		var archivedEvents []model.ArchivedStateChangeEvent
+		for key, body := range mockArchiver.Objects {
+			if !bytes.Contains([]byte(key), []byte("archive/state-change-events/")) {
+				continue
+			}
+			reader, err := gzip.NewReader(bytes.NewReader(body))
+			require.NoError(t, err)
+			scanner := bufio.NewScanner(reader)
+			for scanner.Scan() {
+				var archived model.ArchivedStateChangeEvent
+				require.NoError(t, json.Unmarshal(scanner.Bytes(), &archived))
+				archivedEvents = append(archivedEvents, archived)
+			}
+		}
+		require.Len(t, archivedEvents, 1)
+		assert.Equal(t, "event1", archivedEvents[0].Event.ID)
+		require.Len(t, archivedEvents[0].Deliveries, 1)
+		assert.Equal(t, "delivery1", archivedEvents[0].Deliveries[0].ID)
+	})
+
+	t.Run("do not prune when archive fails", func(t *testing.T) {
+		mockStore := newStore()
+		archiveConfig := config
+		archiveConfig.ArchiveBucket = "archive"
+
+		retentionSupervisor := supervisor.NewEventRetentionSupervisor(mockStore, &mockEventArchiver{Err: errors.New("failed")}, &mockEventRetentionMetrics{}, archiveConfig, logger)
+		err := retentionSupervisor.Do()
+		require.NoError(t, err)
+
+		assert.Empty(t, mockStore.DeletedEventIDs)
+		assert.Empty(t, mockStore.DeletedDeliveries)
+	})
+}
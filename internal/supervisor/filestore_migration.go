@@ -0,0 +1,583 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package supervisor
+
+import (
+	"sync"
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/internal/tools/aws"
+	"github.com/mattermost/mattermost-cloud/internal/webhook"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// installationFilestoreMigrationStore abstracts the database operations required by the supervisor.
+type installationFilestoreMigrationStore interface {
+	GetUnlockedInstallationFilestoreMigrationOperationsPendingWork() ([]*model.InstallationFilestoreMigrationOperation, error)
+	GetInstallationFilestoreMigrationOperation(id string) (*model.InstallationFilestoreMigrationOperation, error)
+	UpdateInstallationFilestoreMigrationOperationState(filestoreMigration *model.InstallationFilestoreMigrationOperation) error
+	UpdateInstallationFilestoreMigrationOperation(filestoreMigration *model.InstallationFilestoreMigrationOperation) error
+	DeleteInstallationFilestoreMigrationOperation(id string) error
+	installationFilestoreMigrationOperationLockStore
+
+	GetInstallation(installationID string, includeGroupConfig, includeGroupConfigOverrides bool) (*model.Installation, error)
+	UpdateInstallation(installation *model.Installation) error
+	installationLockStore
+
+	GetClusterInstallations(*model.ClusterInstallationFilter) ([]*model.ClusterInstallation, error)
+	GetCluster(id string) (*model.Cluster, error)
+
+	GetWebhooks(filter *model.WebhookFilter) ([]*model.Webhook, error)
+
+	model.InstallationDatabaseStoreInterface
+}
+
+type filestoreProvider interface {
+	GetFilestoreByType(installationID, filestoreType string) model.Filestore
+}
+
+// FilestoreMigrationCIProvisioner is the provisioner used to refresh cluster
+// installation secrets during filestore migrations.
+type FilestoreMigrationCIProvisioner interface {
+	ClusterInstallationProvisioner(version string) ClusterInstallationProvisioner
+}
+
+// FilestoreMigrationSupervisor finds pending work and effects the required changes.
+type FilestoreMigrationSupervisor struct {
+	store             installationFilestoreMigrationStore
+	aws               aws.AWS
+	filestoreProvider filestoreProvider
+	instanceID        string
+	keepFilestoreData bool
+	logger            log.FieldLogger
+	provisioner       FilestoreMigrationCIProvisioner
+	eventsProducer    eventProducer
+
+	// copies tracks the migrations whose objects are being copied in the
+	// background by this supervisor.
+	copies     map[string]struct{}
+	copiesLock sync.Mutex
+	copiesWait sync.WaitGroup
+	stop       chan struct{}
+	stopOnce   sync.Once
+}
+
+// NewInstallationFilestoreMigrationSupervisor creates a new FilestoreMigrationSupervisor.
+func NewInstallationFilestoreMigrationSupervisor(
+	store installationFilestoreMigrationStore,
+	aws aws.AWS,
+	filestoreProvider filestoreProvider,
+	instanceID string,
+	keepFilestoreData bool,
+	provisioner FilestoreMigrationCIProvisioner,
+	eventsProducer eventProducer,
+	logger log.FieldLogger) *FilestoreMigrationSupervisor {
+	return &FilestoreMigrationSupervisor{
+		store:             store,
+		aws:               aws,
+		filestoreProvider: filestoreProvider,
+		instanceID:        instanceID,
+		keepFilestoreData: keepFilestoreData,
+		logger:            logger,
+		provisioner:       provisioner,
+		eventsProducer:    eventsProducer,
+		copies:            make(map[string]struct{}),
+		stop:              make(chan struct{}),
+	}
+}
+
+// Shutdown performs graceful shutdown tasks for the supervisor.
+// Background copies stop after their current batch and resume from it on
+// the next supervisor run.
+func (s *FilestoreMigrationSupervisor) Shutdown() {
+	s.logger.Debug("Shutting down installation filestore migration supervisor")
+	s.stopOnce.Do(func() { close(s.stop) })
+	s.copiesWait.Wait()
+}
+
+// Do looks for work to be done on any pending filestore migrations and attempts to schedule the required work.
+func (s *FilestoreMigrationSupervisor) Do() error {
+	filestoreMigrations, err := s.store.GetUnlockedInstallationFilestoreMigrationOperationsPendingWork()
+	if err != nil {
+		s.logger.WithError(err).Warn("Failed to query for pending work")
+		return nil
+	}
+
+	for _, migration := range filestoreMigrations {
+		if migration.State == model.InstallationFilestoreMigrationStateCopyInProgress {
+			s.startCopy(migration)
+			continue
+		}
+		s.Supervise(migration)
+	}
+
+	return nil
+}
+
+// startCopy supervises a migration copying objects in the background, as
+// copying a whole filestore can take much longer than a supervisor run.
+// The migration stays locked until the copy stops.
+func (s *FilestoreMigrationSupervisor) startCopy(migration *model.InstallationFilestoreMigrationOperation) {
+	s.copiesLock.Lock()
+	defer s.copiesLock.Unlock()
+
+	if _, copying := s.copies[migration.ID]; copying {
+		return
+	}
+	select {
+	case <-s.stop:
+		return
+	default:
+	}
+
+	s.copies[migration.ID] = struct{}{}
+	s.copiesWait.Add(1)
+	go func() {
+		defer func() {
+			s.copiesLock.Lock()
+			delete(s.copies, migration.ID)
+			s.copiesLock.Unlock()
+			s.copiesWait.Done()
+		}()
+		s.Supervise(migration)
+	}()
+}
+
+// Supervise schedules the required work on the given filestore migration.
+func (s *FilestoreMigrationSupervisor) Supervise(migration *model.InstallationFilestoreMigrationOperation) {
+	logger := s.logger.WithFields(log.Fields{
+		"filestoreMigrationOperation": migration.ID,
+	})
+
+	lock := newInstallationFilestoreMigrationOperationLock(migration.ID, s.instanceID, s.store, logger)
+	if !lock.TryLock() {
+		return
+	}
+	defer lock.Unlock()
+
+	// Before working on the migration operation, it is crucial that we ensure that it
+	// was not updated to a new state by another provisioning server.
+	originalState := migration.State
+	migration, err := s.store.GetInstallationFilestoreMigrationOperation(migration.ID)
+	if err != nil {
+		logger.WithError(err).Errorf("Failed to get refreshed migration")
+		return
+	}
+	if migration.State != originalState {
+		logger.WithField("oldMigrationState", originalState).
+			WithField("newMigrationState", migration.State).
+			Warn("Another provisioner has worked on this migration; skipping...")
+		return
+	}
+
+	logger.Debugf("Supervising filestore migration in state %s", migration.State)
+
+	newState := s.transitionMigration(migration, s.instanceID, logger)
+
+	migration, err = s.store.GetInstallationFilestoreMigrationOperation(migration.ID)
+	if err != nil {
+		logger.WithError(err).Errorf("Failed to get migration and thus persist state %s", newState)
+		return
+	}
+
+	if migration.State == newState {
+		return
+	}
+
+	oldState := migration.State
+	migration.State = newState
+
+	err = s.store.UpdateInstallationFilestoreMigrationOperationState(migration)
+	if err != nil {
+		logger.WithError(err).Errorf("Failed to set migration state to %s", newState)
+		return
+	}
+
+	webhookPayload := &model.WebhookPayload{
+		Type:      model.TypeInstallationFilestoreMigration,
+		ID:        migration.ID,
+		NewState:  string(migration.State),
+		OldState:  string(oldState),
+		Timestamp: time.Now().UnixNano(),
+		ExtraData: map[string]string{"Installation": migration.InstallationID, "Environment": s.aws.GetCloudEnvironmentName()},
+	}
+	err = webhook.SendToAllWebhooks(s.store, webhookPayload, logger.WithField("webhookEvent", webhookPayload.NewState))
+	if err != nil {
+		logger.WithError(err).Error("Unable to process and send webhooks")
+	}
+
+	logger.Debugf("Transitioned filestore migration from %s to %s", oldState, migration.State)
+}
+
+// transitionMigration works with the given filestore migration to transition it to a final state.
+func (s *FilestoreMigrationSupervisor) transitionMigration(filestoreMigration *model.InstallationFilestoreMigrationOperation, instanceID string, logger log.FieldLogger) model.InstallationFilestoreMigrationOperationState {
+	switch filestoreMigration.State {
+	case model.InstallationFilestoreMigrationStateRequested:
+		return s.prepareDestination(filestoreMigration, instanceID, logger)
+	case model.InstallationFilestoreMigrationStateCopyInProgress:
+		return s.copyObjects(filestoreMigration, logger)
+	case model.InstallationFilestoreMigrationStateVerifying:
+		return s.verifyCopy(filestoreMigration, logger)
+	case model.InstallationFilestoreMigrationStateFilestoreSwitch:
+		return s.switchFilestore(filestoreMigration, instanceID, logger)
+	case model.InstallationFilestoreMigrationStateRefreshSecrets:
+		return s.refreshCredentials(filestoreMigration, instanceID, logger)
+	case model.InstallationFilestoreMigrationStateFinalizing:
+		return s.finalizeMigration(filestoreMigration, instanceID, logger)
+	case model.InstallationFilestoreMigrationStateFailing:
+		return s.failMigration(filestoreMigration, instanceID, logger)
+	case model.InstallationFilestoreMigrationStateCommitRequested:
+		return s.commitMigration(filestoreMigration, logger)
+	case model.InstallationFilestoreMigrationStateRollbackRequested:
+		return s.rollbackMigration(filestoreMigration, instanceID, logger)
+	case model.InstallationFilestoreMigrationStateDeletionRequested:
+		return s.cleanupMigration(filestoreMigration, logger)
+	default:
+		logger.Warnf("Found filestore migration pending work in unexpected state %s", filestoreMigration.State)
+		return filestoreMigration.State
+	}
+}
+
+func (s *FilestoreMigrationSupervisor) prepareDestination(filestoreMigration *model.InstallationFilestoreMigrationOperation, instanceID string, logger log.FieldLogger) model.InstallationFilestoreMigrationOperationState {
+	installation, lock, err := getAndLockInstallation(s.store, filestoreMigration.InstallationID, instanceID, logger)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get and lock installation")
+		return filestoreMigration.State
+	}
+	defer lock.Unlock()
+
+	if installation.Filestore != filestoreMigration.SourceFilestore {
+		logger.Errorf("Installation filestore %s does not match migration source filestore %s", installation.Filestore, filestoreMigration.SourceFilestore)
+		return model.InstallationFilestoreMigrationStateFailing
+	}
+
+	destination := s.filestoreProvider.GetFilestoreByType(installation.ID, filestoreMigration.DestinationFilestore)
+	err = destination.Provision(s.store, logger)
+	if err != nil {
+		logger.WithError(err).Error("Failed to provision destination filestore")
+		return filestoreMigration.State
+	}
+
+	sourceLocation, err := s.aws.GetS3FilestoreLocation(installation.ID, filestoreMigration.SourceFilestore, s.store)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get source filestore location")
+		return filestoreMigration.State
+	}
+
+	summary, err := s.aws.S3GetObjectsSummary(sourceLocation)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get source filestore objects summary")
+		return filestoreMigration.State
+	}
+
+	filestoreMigration.ObjectsTotal = summary.Objects
+	filestoreMigration.BytesTotal = summary.Bytes
+	filestoreMigration.ObjectsCopied = 0
+	filestoreMigration.BytesCopied = 0
+	filestoreMigration.ContinuationToken = ""
+	err = s.store.UpdateInstallationFilestoreMigrationOperation(filestoreMigration)
+	if err != nil {
+		logger.WithError(err).Error("Failed to set source filestore summary for filestore migration")
+		return filestoreMigration.State
+	}
+
+	logger.Infof("Copying %d objects (%d bytes) to %s filestore", summary.Objects, summary.Bytes, filestoreMigration.DestinationFilestore)
+
+	return model.InstallationFilestoreMigrationStateCopyInProgress
+}
+
+func (s *FilestoreMigrationSupervisor) copyObjects(filestoreMigration *model.InstallationFilestoreMigrationOperation, logger log.FieldLogger) model.InstallationFilestoreMigrationOperationState {
+	sourceLocation, destinationLocation, err := s.getLocations(filestoreMigration)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get filestore locations")
+		return filestoreMigration.State
+	}
+
+	for {
+		result, err := s.aws.S3CopyObjectsBatch(sourceLocation, destinationLocation, filestoreMigration.ContinuationToken, logger)
+		if err != nil {
+			logger.WithError(err).Error("Failed to copy filestore objects")
+			return filestoreMigration.State
+		}
+
+		filestoreMigration.ObjectsCopied += result.ObjectsCopied
+		filestoreMigration.BytesCopied += result.BytesCopied
+		filestoreMigration.ContinuationToken = result.ContinuationToken
+		err = s.store.UpdateInstallationFilestoreMigrationOperation(filestoreMigration)
+		if err != nil {
+			logger.WithError(err).Error("Failed to update filestore migration copy progress")
+			return filestoreMigration.State
+		}
+
+		if filestoreMigration.ContinuationToken == "" {
+			logger.Infof("Finished copying %d objects (%d bytes)", filestoreMigration.ObjectsCopied, filestoreMigration.BytesCopied)
+			return model.InstallationFilestoreMigrationStateVerifying
+		}
+
+		logger.Debugf("Filestore migration copy %.2f%% complete", filestoreMigration.CopyProgress())
+
+		select {
+		case <-s.stop:
+			logger.Infof("Filestore migration copy stopped at %.2f%%", filestoreMigration.CopyProgress())
+			return filestoreMigration.State
+		default:
+		}
+	}
+}
+
+func (s *FilestoreMigrationSupervisor) verifyCopy(filestoreMigration *model.InstallationFilestoreMigrationOperation, logger log.FieldLogger) model.InstallationFilestoreMigrationOperationState {
+	sourceLocation, destinationLocation, err := s.getLocations(filestoreMigration)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get filestore locations")
+		return filestoreMigration.State
+	}
+
+	comparison, err := s.aws.S3CompareObjects(sourceLocation, destinationLocation)
+	if err != nil {
+		logger.WithError(err).Error("Failed to compare source and destination filestore objects")
+		return filestoreMigration.State
+	}
+
+	if comparison.Mismatched > 0 {
+		logger.Errorf("Found %d objects missing or differing in destination filestore, including %v",
+			comparison.Mismatched, comparison.MismatchedKeys)
+		return model.InstallationFilestoreMigrationStateFailing
+	}
+
+	logger.Infof("Verified %d objects in destination filestore", comparison.Matched)
+
+	return model.InstallationFilestoreMigrationStateFilestoreSwitch
+}
+
+func (s *FilestoreMigrationSupervisor) switchFilestore(filestoreMigration *model.InstallationFilestoreMigrationOperation, instanceID string, logger log.FieldLogger) model.InstallationFilestoreMigrationOperationState {
+	installation, lock, err := getAndLockInstallation(s.store, filestoreMigration.InstallationID, instanceID, logger)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get and lock installation")
+		return filestoreMigration.State
+	}
+	defer lock.Unlock()
+
+	installation.Filestore = filestoreMigration.DestinationFilestore
+	err = s.store.UpdateInstallation(installation)
+	if err != nil {
+		logger.WithError(err).Error("Failed to switch filestore for installation")
+		return filestoreMigration.State
+	}
+
+	return model.InstallationFilestoreMigrationStateRefreshSecrets
+}
+
+func (s *FilestoreMigrationSupervisor) refreshCredentials(filestoreMigration *model.InstallationFilestoreMigrationOperation, instanceID string, logger log.FieldLogger) model.InstallationFilestoreMigrationOperationState {
+	installation, lock, err := getAndLockInstallation(s.store, filestoreMigration.InstallationID, instanceID, logger)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get and lock installation")
+		return filestoreMigration.State
+	}
+	defer lock.Unlock()
+
+	err = s.refreshSecrets(installation)
+	if err != nil {
+		logger.WithError(err).Error("Failed to refresh credentials for cluster installations")
+		return filestoreMigration.State
+	}
+
+	return model.InstallationFilestoreMigrationStateFinalizing
+}
+
+func (s *FilestoreMigrationSupervisor) finalizeMigration(filestoreMigration *model.InstallationFilestoreMigrationOperation, instanceID string, logger log.FieldLogger) model.InstallationFilestoreMigrationOperationState {
+	installation, lock, err := getAndLockInstallation(s.store, filestoreMigration.InstallationID, instanceID, logger)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get and lock installation")
+		return filestoreMigration.State
+	}
+	defer lock.Unlock()
+
+	oldState := installation.State
+
+	installation.State = model.InstallationStateHibernating
+	err = s.store.UpdateInstallation(installation)
+	if err != nil {
+		logger.WithError(err).Error("Failed to set installation back to hibernating after migration")
+		return filestoreMigration.State
+	}
+
+	err = s.eventsProducer.ProduceInstallationStateChangeEvent(installation, oldState)
+	if err != nil {
+		logger.WithError(err).Error("Failed to create installation state change event")
+	}
+
+	filestoreMigration.CompleteAt = model.GetMillis()
+	err = s.store.UpdateInstallationFilestoreMigrationOperation(filestoreMigration)
+	if err != nil {
+		logger.WithError(err).Error("Failed to set complete at for filestore migration")
+		return filestoreMigration.State
+	}
+
+	return model.InstallationFilestoreMigrationStateSucceeded
+}
+
+func (s *FilestoreMigrationSupervisor) failMigration(filestoreMigration *model.InstallationFilestoreMigrationOperation, instanceID string, logger log.FieldLogger) model.InstallationFilestoreMigrationOperationState {
+	installation, lock, err := getAndLockInstallation(s.store, filestoreMigration.InstallationID, instanceID, logger)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get and lock installation")
+		return filestoreMigration.State
+	}
+	defer lock.Unlock()
+
+	// The installation only uses the destination filestore once the migration
+	// has succeeded, so any objects copied so far can be safely removed.
+	if installation.Filestore != filestoreMigration.DestinationFilestore {
+		destination := s.filestoreProvider.GetFilestoreByType(installation.ID, filestoreMigration.DestinationFilestore)
+		err = destination.Teardown(false, s.store, logger)
+		if err != nil {
+			logger.WithError(err).Error("Failed to clean up destination filestore")
+			return filestoreMigration.State
+		}
+	}
+
+	oldState := installation.State
+
+	installation.State = model.InstallationStateFilestoreMigrationFailed
+	err = s.store.UpdateInstallation(installation)
+	if err != nil {
+		logger.WithError(err).Error("Failed to set installation state after failed migration")
+		return filestoreMigration.State
+	}
+
+	err = s.eventsProducer.ProduceInstallationStateChangeEvent(installation, oldState)
+	if err != nil {
+		logger.WithError(err).Error("Failed to create installation state change event")
+	}
+
+	return model.InstallationFilestoreMigrationStateFailed
+}
+
+func (s *FilestoreMigrationSupervisor) commitMigration(filestoreMigration *model.InstallationFilestoreMigrationOperation, logger log.FieldLogger) model.InstallationFilestoreMigrationOperationState {
+	source := s.filestoreProvider.GetFilestoreByType(filestoreMigration.InstallationID, filestoreMigration.SourceFilestore)
+	err := source.Teardown(false, s.store, logger)
+	if err != nil {
+		logger.WithError(err).Error("Failed to tear down source filestore")
+		return filestoreMigration.State
+	}
+
+	logger.Infof("Source %s filestore was cleaned up", filestoreMigration.SourceFilestore)
+
+	return model.InstallationFilestoreMigrationStateCommitted
+}
+
+func (s *FilestoreMigrationSupervisor) rollbackMigration(filestoreMigration *model.InstallationFilestoreMigrationOperation, instanceID string, logger log.FieldLogger) model.InstallationFilestoreMigrationOperationState {
+	installation, lock, err := getAndLockInstallation(s.store, filestoreMigration.InstallationID, instanceID, logger)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get and lock installation")
+		return filestoreMigration.State
+	}
+	defer lock.Unlock()
+
+	installation.Filestore = filestoreMigration.SourceFilestore
+	err = s.store.UpdateInstallation(installation)
+	if err != nil {
+		logger.WithError(err).Error("Failed to switch filestore for installation")
+		return filestoreMigration.State
+	}
+
+	err = s.refreshSecrets(installation)
+	if err != nil {
+		logger.WithError(err).Error("Failed to refresh secrets on cluster installations during rollback")
+		return filestoreMigration.State
+	}
+
+	destination := s.filestoreProvider.GetFilestoreByType(installation.ID, filestoreMigration.DestinationFilestore)
+	err = destination.Teardown(false, s.store, logger)
+	if err != nil {
+		logger.WithError(err).Error("Failed to clean up destination filestore")
+		return filestoreMigration.State
+	}
+
+	oldState := installation.State
+	installation.State = model.InstallationStateHibernating
+	err = s.store.UpdateInstallation(installation)
+	if err != nil {
+		logger.WithError(err).Error("Failed to set installation back to hibernating state")
+		return filestoreMigration.State
+	}
+
+	err = s.eventsProducer.ProduceInstallationStateChangeEvent(installation, oldState)
+	if err != nil {
+		logger.WithError(err).Error("Failed to create installation state change event")
+	}
+
+	return model.InstallationFilestoreMigrationStateRollbackFinished
+}
+
+// cleanupMigration tears down whichever of the source and destination
+// filestores is no longer used by the installation and marks the migration
+// as deleted.
+func (s *FilestoreMigrationSupervisor) cleanupMigration(filestoreMigration *model.InstallationFilestoreMigrationOperation, logger log.FieldLogger) model.InstallationFilestoreMigrationOperationState {
+	installation, err := s.store.GetInstallation(filestoreMigration.InstallationID, false, false)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get installation")
+		return filestoreMigration.State
+	}
+	if installation == nil {
+		logger.Error("Installation not found")
+		return filestoreMigration.State
+	}
+
+	unusedFilestore := filestoreMigration.SourceFilestore
+	if installation.Filestore == filestoreMigration.SourceFilestore {
+		unusedFilestore = filestoreMigration.DestinationFilestore
+	}
+
+	err = s.filestoreProvider.GetFilestoreByType(installation.ID, unusedFilestore).
+		Teardown(s.keepFilestoreData, s.store, logger)
+	if err != nil {
+		logger.WithError(err).Errorf("Failed to tear down unused %s filestore", unusedFilestore)
+		return filestoreMigration.State
+	}
+
+	err = s.store.DeleteInstallationFilestoreMigrationOperation(filestoreMigration.ID)
+	if err != nil {
+		logger.WithError(err).Error("Failed to mark migration operation as deleted")
+		return filestoreMigration.State
+	}
+
+	return model.InstallationFilestoreMigrationStateDeleted
+}
+
+func (s *FilestoreMigrationSupervisor) getLocations(filestoreMigration *model.InstallationFilestoreMigrationOperation) (*aws.S3FilestoreLocation, *aws.S3FilestoreLocation, error) {
+	sourceLocation, err := s.aws.GetS3FilestoreLocation(filestoreMigration.InstallationID, filestoreMigration.SourceFilestore, s.store)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to get source filestore location")
+	}
+	destinationLocation, err := s.aws.GetS3FilestoreLocation(filestoreMigration.InstallationID, filestoreMigration.DestinationFilestore, s.store)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to get destination filestore location")
+	}
+
+	return sourceLocation, destinationLocation, nil
+}
+
+func (s *FilestoreMigrationSupervisor) refreshSecrets(installation *model.Installation) error {
+	cis, err := s.store.GetClusterInstallations(&model.ClusterInstallationFilter{InstallationID: installation.ID, Paging: model.AllPagesNotDeleted()})
+	if err != nil {
+		return errors.Wrap(err, "failed to get cluster installations")
+	}
+
+	for _, ci := range cis {
+		cluster, err := s.store.GetCluster(ci.ClusterID)
+		if err != nil {
+			return errors.Wrap(err, "failed to get cluster")
+		}
+
+		err = s.provisioner.ClusterInstallationProvisioner(installation.CRVersion).
+			RefreshSecrets(cluster, installation, ci)
+		if err != nil {
+			return errors.Wrap(err, "failed to refresh credentials of cluster installation")
+		}
+	}
+	return nil
+}
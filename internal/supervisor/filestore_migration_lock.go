@@ -0,0 +1,56 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package supervisor
+
+import log "github.com/sirupsen/logrus"
+
+type installationFilestoreMigrationOperationLockStore interface {
+	LockInstallationFilestoreMigrationOperations(id []string, lockerID string) (bool, error)
+	UnlockInstallationFilestoreMigrationOperations(id []string, lockerID string, force bool) (bool, error)
+}
+
+type installationFilestoreMigrationOperationLock struct {
+	ids      []string
+	lockerID string
+	store    installationFilestoreMigrationOperationLockStore
+	logger   log.FieldLogger
+}
+
+func newInstallationFilestoreMigrationOperationLock(id, lockerID string, store installationFilestoreMigrationOperationLockStore, logger log.FieldLogger) *installationFilestoreMigrationOperationLock {
+	return &installationFilestoreMigrationOperationLock{
+		ids:      []string{id},
+		lockerID: lockerID,
+		store:    store,
+		logger:   logger,
+	}
+}
+
+func newInstallationFilestoreMigrationOperationLocks(ids []string, lockerID string, store installationFilestoreMigrationOperationLockStore, logger log.FieldLogger) *installationFilestoreMigrationOperationLock {
+	return &installationFilestoreMigrationOperationLock{
+		ids:      ids,
+		lockerID: lockerID,
+		store:    store,
+		logger:   logger,
+	}
+}
+
+func (l *installationFilestoreMigrationOperationLock) TryLock() bool {
+	locked, err := l.store.LockInstallationFilestoreMigrationOperations(l.ids, l.lockerID)
+	if err != nil {
+		l.logger.WithError(err).Error("failed to lock installationFilestoreMigrationOperations")
+		return false
+	}
+
+	return locked
+}
+
+func (l *installationFilestoreMigrationOperationLock) Unlock() {
+	unlocked, err := l.store.UnlockInstallationFilestoreMigrationOperations(l.ids, l.lockerID, false)
+	if err != nil {
+		l.logger.WithError(err).Error("failed to unlock installationFilestoreMigrationOperations")
+	} else if !unlocked {
+		l.logger.Error("failed to release lock for installationFilestoreMigrationOperations")
+	}
+}
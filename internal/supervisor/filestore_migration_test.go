@@ -0,0 +1,277 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package supervisor_test
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	awsMocks "github.com/mattermost/mattermost-cloud/internal/mocks/aws-tools"
+	"github.com/mattermost/mattermost-cloud/internal/supervisor"
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/internal/tools/aws"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockFilestoreMigrationStore struct {
+	FilestoreMigrationOperation *model.InstallationFilestoreMigrationOperation
+	MigrationPending            []*model.InstallationFilestoreMigrationOperation
+	Installation                *model.Installation
+
+	UpdateMigrationOperationCalls int
+
+	mockMultitenantDBStore
+}
+
+func (m *mockFilestoreMigrationStore) GetUnlockedInstallationFilestoreMigrationOperationsPendingWork() ([]*model.InstallationFilestoreMigrationOperation, error) {
+	return m.MigrationPending, nil
+}
+
+func (m *mockFilestoreMigrationStore) GetInstallationFilestoreMigrationOperation(id string) (*model.InstallationFilestoreMigrationOperation, error) {
+	operation := *m.FilestoreMigrationOperation
+	return &operation, nil
+}
+
+func (m *mockFilestoreMigrationStore) UpdateInstallationFilestoreMigrationOperationState(filestoreMigration *model.InstallationFilestoreMigrationOperation) error {
+	m.UpdateMigrationOperationCalls++
+	m.FilestoreMigrationOperation.State = filestoreMigration.State
+	return nil
+}
+
+func (m *mockFilestoreMigrationStore) UpdateInstallationFilestoreMigrationOperation(filestoreMigration *model.InstallationFilestoreMigrationOperation) error {
+	m.UpdateMigrationOperationCalls++
+	operation := *filestoreMigration
+	m.FilestoreMigrationOperation = &operation
+	return nil
+}
+
+func (m *mockFilestoreMigrationStore) DeleteInstallationFilestoreMigrationOperation(id string) error {
+	return nil
+}
+
+func (m *mockFilestoreMigrationStore) LockInstallationFilestoreMigrationOperations(id []string, lockerID string) (bool, error) {
+	return true, nil
+}
+
+func (m *mockFilestoreMigrationStore) UnlockInstallationFilestoreMigrationOperations(id []string, lockerID string, force bool) (bool, error) {
+	return true, nil
+}
+
+func (m *mockFilestoreMigrationStore) GetInstallation(installationID string, includeGroupConfig, includeGroupConfigOverrides bool) (*model.Installation, error) {
+	return m.Installation, nil
+}
+
+func (m *mockFilestoreMigrationStore) UpdateInstallation(installation *model.Installation) error {
+	m.Installation = installation
+	return nil
+}
+
+func (m *mockFilestoreMigrationStore) LockInstallation(installationID, lockerID string) (bool, error) {
+	return true, nil
+}
+
+func (m *mockFilestoreMigrationStore) UnlockInstallation(installationID, lockerID string, force bool) (bool, error) {
+	return true, nil
+}
+
+func (m *mockFilestoreMigrationStore) GetClusterInstallations(filter *model.ClusterInstallationFilter) ([]*model.ClusterInstallation, error) {
+	return nil, nil
+}
+
+func (m *mockFilestoreMigrationStore) GetCluster(id string) (*model.Cluster, error) {
+	panic("implement me")
+}
+
+func (m *mockFilestoreMigrationStore) GetWebhooks(filter *model.WebhookFilter) ([]*model.Webhook, error) {
+	return nil, nil
+}
+
+type mockFilestoreProvider struct{}
+
+func (m *mockFilestoreProvider) GetFilestoreByType(installationID, filestoreType string) model.Filestore {
+	panic("implement me")
+}
+
+func TestFilestoreMigrationSupervisor_Do(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	mockStore := &mockFilestoreMigrationStore{}
+
+	filestoreMigrationSupervisor := supervisor.NewInstallationFilestoreMigrationSupervisor(mockStore, &mockAWS{}, &mockFilestoreProvider{}, "instanceID", false, nil, nil, logger)
+	err := filestoreMigrationSupervisor.Do()
+	require.NoError(t, err)
+
+	require.Equal(t, 0, mockStore.UpdateMigrationOperationCalls)
+}
+
+func TestFilestoreMigrationSupervisor_Supervise(t *testing.T) {
+	installation := &model.Installation{
+		ID:        model.NewID(),
+		State:     model.InstallationStateFilestoreMigrationInProgress,
+		Filestore: model.InstallationFilestoreAwsS3,
+	}
+	source := &aws.S3FilestoreLocation{Bucket: "source"}
+	destination := &aws.S3FilestoreLocation{Bucket: "destination", Prefix: installation.ID + "/"}
+
+	setupLocations := func(awsMock *awsMocks.MockAWS) {
+		awsMock.EXPECT().GetS3FilestoreLocation(installation.ID, model.InstallationFilestoreAwsS3, gomock.Any()).Return(source, nil)
+		awsMock.EXPECT().GetS3FilestoreLocation(installation.ID, model.InstallationFilestoreBifrost, gomock.Any()).Return(destination, nil)
+		awsMock.EXPECT().GetCloudEnvironmentName().Return("test").AnyTimes()
+	}
+
+	newOperation := func(state model.InstallationFilestoreMigrationOperationState) *model.InstallationFilestoreMigrationOperation {
+		return &model.InstallationFilestoreMigrationOperation{
+			ID:                   model.NewID(),
+			InstallationID:       installation.ID,
+			State:                state,
+			SourceFilestore:      model.InstallationFilestoreAwsS3,
+			DestinationFilestore: model.InstallationFilestoreBifrost,
+			ObjectsTotal:         3,
+			BytesTotal:           30,
+		}
+	}
+
+	t.Run("copy objects in batches", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		ctrl := gomock.NewController(t)
+		awsMock := awsMocks.NewMockAWS(ctrl)
+		setupLocations(awsMock)
+
+		operation := newOperation(model.InstallationFilestoreMigrationStateCopyInProgress)
+		mockStore := &mockFilestoreMigrationStore{Installation: installation, FilestoreMigrationOperation: operation}
+
+		gomock.InOrder(
+			awsMock.EXPECT().S3CopyObjectsBatch(source, destination, "", gomock.Any()).
+				Return(&aws.S3CopyBatchResult{ObjectsCopied: 2, BytesCopied: 20, ContinuationToken: "next"}, nil),
+			awsMock.EXPECT().S3CopyObjectsBatch(source, destination, "next", gomock.Any()).
+				Return(&aws.S3CopyBatchResult{ObjectsCopied: 1, BytesCopied: 10}, nil),
+		)
+
+		filestoreMigrationSupervisor := supervisor.NewInstallationFilestoreMigrationSupervisor(mockStore, awsMock, &mockFilestoreProvider{}, "instanceID", false, nil, &mockEventProducer{}, logger)
+		filestoreMigrationSupervisor.Supervise(operation)
+
+		assert.Equal(t, model.InstallationFilestoreMigrationStateVerifying, mockStore.FilestoreMigrationOperation.State)
+		assert.Equal(t, int64(3), mockStore.FilestoreMigrationOperation.ObjectsCopied)
+		assert.Equal(t, int64(30), mockStore.FilestoreMigrationOperation.BytesCopied)
+		assert.Empty(t, mockStore.FilestoreMigrationOperation.ContinuationToken)
+	})
+
+	t.Run("copy objects in the background", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		ctrl := gomock.NewController(t)
+		awsMock := awsMocks.NewMockAWS(ctrl)
+		setupLocations(awsMock)
+
+		operation := newOperation(model.InstallationFilestoreMigrationStateCopyInProgress)
+		mockStore := &mockFilestoreMigrationStore{
+			Installation:                installation,
+			FilestoreMigrationOperation: operation,
+			MigrationPending:            []*model.InstallationFilestoreMigrationOperation{operation},
+		}
+
+		awsMock.EXPECT().S3CopyObjectsBatch(source, destination, "", gomock.Any()).
+			Return(&aws.S3CopyBatchResult{ObjectsCopied: 3, BytesCopied: 30}, nil)
+
+		filestoreMigrationSupervisor := supervisor.NewInstallationFilestoreMigrationSupervisor(mockStore, awsMock, &mockFilestoreProvider{}, "instanceID", false, nil, &mockEventProducer{}, logger)
+		err := filestoreMigrationSupervisor.Do()
+		require.NoError(t, err)
+
+		// Shutdown waits for background copies to stop and may be called
+		// more than once.
+		filestoreMigrationSupervisor.Shutdown()
+		filestoreMigrationSupervisor.Shutdown()
+
+		assert.Equal(t, model.InstallationFilestoreMigrationStateVerifying, mockStore.FilestoreMigrationOperation.State)
+		assert.Equal(t, int64(3), mockStore.FilestoreMigrationOperation.ObjectsCopied)
+	})
+
+	t.Run("copy objects fails", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		ctrl := gomock.NewController(t)
+		awsMock := awsMocks.NewMockAWS(ctrl)
+		setupLocations(awsMock)
+
+		operation := newOperation(model.InstallationFilestoreMigrationStateCopyInProgress)
+		mockStore := &mockFilestoreMigrationStore{Installation: installation, FilestoreMigrationOperation: operation}
+
+		awsMock.EXPECT().S3CopyObjectsBatch(source, destination, "", gomock.Any()).
+			Return(nil, assert.AnError)
+
+		filestoreMigrationSupervisor := supervisor.NewInstallationFilestoreMigrationSupervisor(mockStore, awsMock, &mockFilestoreProvider{}, "instanceID", false, nil, &mockEventProducer{}, logger)
+		filestoreMigrationSupervisor.Supervise(operation)
+
+		assert.Equal(t, model.InstallationFilestoreMigrationStateCopyInProgress, mockStore.FilestoreMigrationOperation.State)
+		assert.Equal(t, 0, mockStore.UpdateMigrationOperationCalls)
+	})
+
+	for _, testCase := range []struct {
+		description   string
+		comparison    *aws.S3ObjectsComparison
+		expectedState model.InstallationFilestoreMigrationOperationState
+	}{
+		{
+			description:   "verify copy succeeds",
+			comparison:    &aws.S3ObjectsComparison{Matched: 3},
+			expectedState: model.InstallationFilestoreMigrationStateFilestoreSwitch,
+		},
+		{
+			description:   "verify copy detects mismatched objects",
+			comparison:    &aws.S3ObjectsComparison{Matched: 2, Mismatched: 1, MismatchedKeys: []string{"data/file"}},
+			expectedState: model.InstallationFilestoreMigrationStateFailing,
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			logger := testlib.MakeLogger(t)
+			ctrl := gomock.NewController(t)
+			awsMock := awsMocks.NewMockAWS(ctrl)
+			setupLocations(awsMock)
+
+			operation := newOperation(model.InstallationFilestoreMigrationStateVerifying)
+			mockStore := &mockFilestoreMigrationStore{Installation: installation, FilestoreMigrationOperation: operation}
+
+			awsMock.EXPECT().S3CompareObjects(source, destination).Return(testCase.comparison, nil)
+
+			filestoreMigrationSupervisor := supervisor.NewInstallationFilestoreMigrationSupervisor(mockStore, awsMock, &mockFilestoreProvider{}, "instanceID", false, nil, &mockEventProducer{}, logger)
+			filestoreMigrationSupervisor.Supervise(operation)
+
+			assert.Equal(t, testCase.expectedState, mockStore.FilestoreMigrationOperation.State)
+		})
+	}
+
+	t.Run("verify copy fails", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		ctrl := gomock.NewController(t)
+		awsMock := awsMocks.NewMockAWS(ctrl)
+		setupLocations(awsMock)
+
+		operation := newOperation(model.InstallationFilestoreMigrationStateVerifying)
+		mockStore := &mockFilestoreMigrationStore{Installation: installation, FilestoreMigrationOperation: operation}
+
+		awsMock.EXPECT().S3CompareObjects(source, destination).Return(nil, assert.AnError)
+
+		filestoreMigrationSupervisor := supervisor.NewInstallationFilestoreMigrationSupervisor(mockStore, awsMock, &mockFilestoreProvider{}, "instanceID", false, nil, &mockEventProducer{}, logger)
+		filestoreMigrationSupervisor.Supervise(operation)
+
+		assert.Equal(t, model.InstallationFilestoreMigrationStateVerifying, mockStore.FilestoreMigrationOperation.State)
+	})
+
+	t.Run("switch filestore", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		ctrl := gomock.NewController(t)
+		awsMock := awsMocks.NewMockAWS(ctrl)
+		awsMock.EXPECT().GetCloudEnvironmentName().Return("test").AnyTimes()
+
+		operation := newOperation(model.InstallationFilestoreMigrationStateFilestoreSwitch)
+		switchInstallation := *installation
+		mockStore := &mockFilestoreMigrationStore{Installation: &switchInstallation, FilestoreMigrationOperation: operation}
+
+		filestoreMigrationSupervisor := supervisor.NewInstallationFilestoreMigrationSupervisor(mockStore, awsMock, &mockFilestoreProvider{}, "instanceID", false, nil, &mockEventProducer{}, logger)
+		filestoreMigrationSupervisor.Supervise(operation)
+
+		assert.Equal(t, model.InstallationFilestoreMigrationStateRefreshSecrets, mockStore.FilestoreMigrationOperation.State)
+		assert.Equal(t, model.InstallationFilestoreBifrost, mockStore.Installation.Filestore)
+	})
+}
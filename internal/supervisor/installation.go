@@ -44,6 +44,7 @@ type installationStore interface {
 
 	GetSingleTenantDatabaseConfigForInstallation(installationID string) (*model.SingleTenantDatabaseConfig, error)
 	GetAnnotationsForInstallation(installationID string) ([]*model.Annotation, error)
+	GetSizeProfileByName(name string) (*model.SizeProfile, error)
 
 	CreateClusterInstallation(clusterInstallation *model.ClusterInstallation) error
 	GetClusterInstallation(clusterInstallationID string) (*model.ClusterInstallation, error)
@@ -63,6 +64,10 @@ type installationStore interface {
 	UpdateInstallationDBMigrationOperationState(operation *model.InstallationDBMigrationOperation) error
 	installationDBMigrationOperationLockStore
 
+	GetInstallationFilestoreMigrationOperations(filter *model.InstallationFilestoreMigrationFilter) ([]*model.InstallationFilestoreMigrationOperation, error)
+	UpdateInstallationFilestoreMigrationOperationState(operation *model.InstallationFilestoreMigrationOperation) error
+	installationFilestoreMigrationOperationLockStore
+
 	GetInstallationDBRestorationOperations(filter *model.InstallationDBRestorationFilter) ([]*model.InstallationDBRestorationOperation, error)
 	UpdateInstallationDBRestorationOperationState(operation *model.InstallationDBRestorationOperation) error
 	installationDBRestorationLockStore
@@ -84,6 +89,8 @@ type eventProducer interface {
 	ProduceInstallationStateChangeEvent(installation *model.Installation, oldState string, extraDataFields ...events.DataField) error
 	ProduceClusterStateChangeEvent(cluster *model.Cluster, oldState string, extraDataFields ...events.DataField) error
 	ProduceClusterInstallationStateChangeEvent(clusterInstallation *model.ClusterInstallation, oldState string, extraDataFields ...events.DataField) error
+	ProduceClusterUtilityVersionChangeEvent(cluster *model.Cluster, data *model.ClusterUtilityVersionChangeEventData) error
+	ProduceClusterUtilityUnhealthyEvent(cluster *model.Cluster, data *model.ClusterUtilityUnhealthyEventData) error
 }
 
 // InstallationSupervisor finds installations pending work and effects the required changes.
@@ -489,7 +496,7 @@ func (s *InstallationSupervisor) prioritizeLowerUtilizedClusters(clusters []*mod
 			logger.WithError(err).Error("Failed to get cluster resources")
 			continue
 		}
-		size, err := model.GetInstallationSize(installation.Size)
+		size, _, err := model.ResolveInstallationSize(installation.Size, s.store)
 		if err != nil {
 			logger.WithError(err).Error("Invalid cluster installation size")
 			continue
@@ -577,7 +584,7 @@ func (s *InstallationSupervisor) createClusterInstallation(cluster *model.Cluste
 
 	// Begin final resource check.
 
-	size, err := model.GetInstallationSize(installation.Size)
+	size, _, err := model.ResolveInstallationSize(installation.Size, s.store)
 	if err != nil {
 		logger.WithError(err).Error("Invalid cluster installation size")
 		return nil
@@ -1300,7 +1307,12 @@ func (s *InstallationSupervisor) finalDeletionCleanup(installation *model.Instal
 		logger.WithError(err).Error("Failed to delete db restoration operations")
 		return model.InstallationStateDeletionFinalCleanup
 	}
-	if !migrationDeletionFinished || !restorationDeletionFinished {
+	filestoreMigrationDeletionFinished, err := s.deleteFilestoreMigrationOperations(installation, instanceID, logger)
+	if err != nil {
+		logger.WithError(err).Error("Failed to delete filestore migration operations")
+		return model.InstallationStateDeletionFinalCleanup
+	}
+	if !migrationDeletionFinished || !restorationDeletionFinished || !filestoreMigrationDeletionFinished {
 		logger.Info("Installation db restoration and migration deletion in progress")
 		return model.InstallationStateDeletionFinalCleanup
 	}
@@ -1560,6 +1572,65 @@ func (s *InstallationSupervisor) deleteMigrationOperations(installation *model.I
 	return true, nil
 }
 
+func (s *InstallationSupervisor) deleteFilestoreMigrationOperations(installation *model.Installation, instanceID string, logger log.FieldLogger) (bool, error) {
+	logger.Info("Deleting installation filestore migration operations")
+
+	migrationOperations, err := s.store.GetInstallationFilestoreMigrationOperations(&model.InstallationFilestoreMigrationFilter{
+		InstallationID: installation.ID,
+		Paging:         model.AllPagesNotDeleted(),
+	})
+	if err != nil {
+		return false, errors.Wrap(err, "failed to list filestore migration operations")
+	}
+
+	if len(migrationOperations) == 0 {
+		logger.Info("No existing filestore migration operations found for installation")
+		return true, nil
+	}
+
+	operationIDs := getInstallationFilestoreMigrationOperationIDs(migrationOperations)
+	migrationOperationsLocks := newInstallationFilestoreMigrationOperationLocks(operationIDs, instanceID, s.store, logger)
+
+	if !migrationOperationsLocks.TryLock() {
+		return false, errors.Errorf("Failed to lock %d installation filestore migrations", len(migrationOperations))
+	}
+	defer migrationOperationsLocks.Unlock()
+
+	// Fetch the same elements again, now that we have the locks.
+	migrationOperations, err = s.store.GetInstallationFilestoreMigrationOperations(&model.InstallationFilestoreMigrationFilter{
+		IDs:    operationIDs,
+		Paging: model.AllPagesNotDeleted(),
+	})
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to fetch %d installation filestore migration operations by ids", len(migrationOperations))
+	}
+
+	deleting := 0
+	for _, operation := range migrationOperations {
+		if operation.State == model.InstallationFilestoreMigrationStateDeleted {
+			continue
+		}
+		deleting++
+		if operation.State == model.InstallationFilestoreMigrationStateDeletionRequested {
+			continue
+		}
+
+		logger.Debugf("Deleting installation filestore migration operation %s in state %s", operation.ID, operation.State)
+		operation.State = model.InstallationFilestoreMigrationStateDeletionRequested
+		err = s.store.UpdateInstallationFilestoreMigrationOperationState(operation)
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to mark installation filestore migration %s for deletion", operation.ID)
+		}
+	}
+
+	if deleting > 0 {
+		logger.Infof("Installation filestore migrations deletion in progress, deleting operations %d", deleting)
+		return false, nil
+	}
+
+	return true, nil
+}
+
 func (s *InstallationSupervisor) finalCreationTasks(installation *model.Installation, logger log.FieldLogger) string {
 	logger.Info("Finished final creation tasks")
 
@@ -1671,6 +1742,14 @@ func getInstallationDBMigrationOperationIDs(operations []*model.InstallationDBMi
 	return ids
 }
 
+func getInstallationFilestoreMigrationOperationIDs(operations []*model.InstallationFilestoreMigrationOperation) []string {
+	ids := make([]string, 0, len(operations))
+	for _, op := range operations {
+		ids = append(ids, op.ID)
+	}
+	return ids
+}
+
 func getAnnotationsNames(annotations []*model.Annotation) []string {
 	names := make([]string, 0, len(annotations))
 	for _, ann := range annotations {
@@ -10,6 +10,7 @@ import (
 	"github.com/mattermost/mattermost-cloud/internal/tools/aws"
 	"github.com/mattermost/mattermost-cloud/internal/webhook"
 	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -55,6 +56,7 @@ type InstallationDBRestorationSupervisor struct {
 	environment    string
 	logger         log.FieldLogger
 	provisioner    RestoreProvisioner
+	aws            aws.AWS
 	eventsProducer eventProducer
 }
 
@@ -69,6 +71,7 @@ func NewInstallationDBRestorationSupervisor(
 	return &InstallationDBRestorationSupervisor{
 		store:          store,
 		provisioner:    provisioner,
+		aws:            aws,
 		eventsProducer: eventsProducer,
 		instanceID:     instanceID,
 		environment:    aws.GetCloudEnvironmentName(),
@@ -187,6 +190,10 @@ func (s *InstallationDBRestorationSupervisor) transitionRestoration(restoration
 }
 
 func (s *InstallationDBRestorationSupervisor) triggerRestoration(restoration *model.InstallationDBRestorationOperation, instanceID string, logger log.FieldLogger) model.InstallationDBRestorationState {
+	if restoration.IsPointInTime() {
+		return s.triggerPointInTimeRestoration(restoration, logger)
+	}
+
 	installation, lock, err := getAndLockInstallation(s.store, restoration.InstallationID, instanceID, logger)
 	if err != nil {
 		logger.WithError(err).Error("Failed to get and lock installation")
@@ -230,7 +237,27 @@ func (s *InstallationDBRestorationSupervisor) triggerRestoration(restoration *mo
 	return model.InstallationDBRestorationStateInProgress
 }
 
+func (s *InstallationDBRestorationSupervisor) triggerPointInTimeRestoration(restoration *model.InstallationDBRestorationOperation, logger log.FieldLogger) model.InstallationDBRestorationState {
+	restoreTime := time.UnixMilli(restoration.RestoreTime)
+
+	err := s.aws.RDSRestoreDBClusterToPointInTime(restoration.InstallationID, restoration.ID, restoreTime, logger)
+	if err != nil {
+		if errors.Is(err, aws.ErrRDSRestoreTimeOutOfRange) {
+			logger.WithError(err).Error("Installation database cannot be restored to requested time")
+			return model.InstallationDBRestorationStateFailing
+		}
+		logger.WithError(err).Error("Failed to trigger point-in-time restoration")
+		return restoration.State
+	}
+
+	return model.InstallationDBRestorationStateInProgress
+}
+
 func (s *InstallationDBRestorationSupervisor) checkRestorationStatus(restoration *model.InstallationDBRestorationOperation, instanceID string, logger log.FieldLogger) model.InstallationDBRestorationState {
+	if restoration.IsPointInTime() {
+		return s.checkPointInTimeRestorationStatus(restoration, logger)
+	}
+
 	backup, err := s.store.GetInstallationBackup(restoration.BackupID)
 	if err != nil {
 		logger.WithError(err).Error("Failed to get backup")
@@ -267,6 +294,27 @@ func (s *InstallationDBRestorationSupervisor) checkRestorationStatus(restoration
 	return model.InstallationDBRestorationStateFinalizing
 }
 
+func (s *InstallationDBRestorationSupervisor) checkPointInTimeRestorationStatus(restoration *model.InstallationDBRestorationOperation, logger log.FieldLogger) model.InstallationDBRestorationState {
+	done, err := s.aws.RDSCheckPointInTimeRestore(restoration.InstallationID, restoration.ID, logger)
+	if err != nil {
+		logger.WithError(err).Error("Failed to check point-in-time restoration status")
+		return restoration.State
+	}
+	if !done {
+		logger.Info("Database point-in-time restoration still in progress")
+		return restoration.State
+	}
+
+	restoration.CompleteAt = model.GetMillis()
+	err = s.store.UpdateInstallationDBRestorationOperation(restoration)
+	if err != nil {
+		logger.WithError(err).Error("Failed to update restoration")
+		return restoration.State
+	}
+
+	return model.InstallationDBRestorationStateFinalizing
+}
+
 func (s *InstallationDBRestorationSupervisor) finalizeRestoration(restoration *model.InstallationDBRestorationOperation, instanceID string, logger log.FieldLogger) model.InstallationDBRestorationState {
 	installation, lock, err := getAndLockInstallation(s.store, restoration.InstallationID, instanceID, logger)
 	if err != nil {
@@ -275,6 +323,14 @@ func (s *InstallationDBRestorationSupervisor) finalizeRestoration(restoration *m
 	}
 	defer lock.Unlock()
 
+	if restoration.IsPointInTime() {
+		err = s.aws.RDSCleanupPointInTimeRestore(restoration.InstallationID, restoration.ID, logger)
+		if err != nil {
+			logger.WithError(err).Error("Failed to cleanup replaced database cluster")
+			return restoration.State
+		}
+	}
+
 	oldState := installation.State
 	installation.State = restoration.TargetInstallationState
 	err = s.store.UpdateInstallation(installation)
@@ -299,6 +355,14 @@ func (s *InstallationDBRestorationSupervisor) failRestoration(restoration *model
 	}
 	defer lock.Unlock()
 
+	if restoration.IsPointInTime() {
+		err = s.aws.RDSCleanupPointInTimeRestore(restoration.InstallationID, restoration.ID, logger)
+		if err != nil {
+			logger.WithError(err).Error("Failed to cleanup restored database cluster")
+			return restoration.State
+		}
+	}
+
 	oldState := installation.State
 	installation.State = model.InstallationStateDBRestorationFailed
 	err = s.store.UpdateInstallation(installation)
@@ -316,27 +380,35 @@ func (s *InstallationDBRestorationSupervisor) failRestoration(restoration *model
 }
 
 func (s *InstallationDBRestorationSupervisor) cleanupRestoration(restoration *model.InstallationDBRestorationOperation, instanceID string, logger log.FieldLogger) model.InstallationDBRestorationState {
-	backup, err := s.store.GetInstallationBackup(restoration.BackupID)
-	if err != nil {
-		logger.WithError(err).Error("Failed to get backup")
-		return restoration.State
-	}
-	if backup != nil {
-		var cluster *model.Cluster
-		cluster, err = getClusterForClusterInstallation(s.store, restoration.ClusterInstallationID)
+	if restoration.IsPointInTime() {
+		err := s.aws.RDSCleanupPointInTimeRestore(restoration.InstallationID, restoration.ID, logger)
 		if err != nil {
-			logger.WithError(err).Error("Failed to get cluster for restoration")
+			logger.WithError(err).Error("Failed to cleanup point-in-time restoration database clusters")
 			return restoration.State
 		}
-
-		err = s.provisioner.CleanupRestoreJob(backup, cluster)
+	} else {
+		backup, err := s.store.GetInstallationBackup(restoration.BackupID)
 		if err != nil {
-			logger.WithError(err).Error("Failed to cleanup backup from cluster")
+			logger.WithError(err).Error("Failed to get backup")
 			return restoration.State
 		}
+		if backup != nil {
+			var cluster *model.Cluster
+			cluster, err = getClusterForClusterInstallation(s.store, restoration.ClusterInstallationID)
+			if err != nil {
+				logger.WithError(err).Error("Failed to get cluster for restoration")
+				return restoration.State
+			}
+
+			err = s.provisioner.CleanupRestoreJob(backup, cluster)
+			if err != nil {
+				logger.WithError(err).Error("Failed to cleanup backup from cluster")
+				return restoration.State
+			}
+		}
 	}
 
-	err = s.store.DeleteInstallationDBRestorationOperation(restoration.ID)
+	err := s.store.DeleteInstallationDBRestorationOperation(restoration.ID)
 	if err != nil {
 		logger.WithError(err).Error("Failed to mark restoration as deleted")
 		return restoration.State
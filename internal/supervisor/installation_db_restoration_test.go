@@ -7,11 +7,15 @@ package supervisor_test
 import (
 	"fmt"
 	"testing"
+	"time"
 
+	"github.com/golang/mock/gomock"
+	awsMocks "github.com/mattermost/mattermost-cloud/internal/mocks/aws-tools"
 	"github.com/mattermost/mattermost-cloud/internal/store"
 	"github.com/mattermost/mattermost-cloud/internal/supervisor"
 	"github.com/mattermost/mattermost-cloud/internal/testlib"
 	"github.com/mattermost/mattermost-cloud/internal/testutil"
+	"github.com/mattermost/mattermost-cloud/internal/tools/aws"
 	"github.com/mattermost/mattermost-cloud/model"
 	"github.com/pborman/uuid"
 	"github.com/pkg/errors"
@@ -176,6 +180,122 @@ func TestInstallationDBRestorationSupervisor_Do(t *testing.T) {
 	})
 }
 
+func TestInstallationDBRestorationSupervisor_PointInTime(t *testing.T) {
+	installation := &model.Installation{
+		ID:       model.NewID(),
+		State:    model.InstallationStateDBRestorationInProgress,
+		Database: model.InstallationDatabaseSingleTenantRDSPostgres,
+	}
+	restoreTime := model.GetMillis() - 60*60*1000
+
+	newRestorationSupervisor := func(t *testing.T, restorationOp *model.InstallationDBRestorationOperation) (*supervisor.InstallationDBRestorationSupervisor, *mockRestorationStore, *awsMocks.MockAWS) {
+		ctrl := gomock.NewController(t)
+		awsMock := awsMocks.NewMockAWS(ctrl)
+		awsMock.EXPECT().GetCloudEnvironmentName().Return("test")
+
+		mockStore := &mockRestorationStore{
+			Installation:                     installation,
+			InstallationRestorationOperation: restorationOp,
+		}
+
+		restorationSupervisor := supervisor.NewInstallationDBRestorationSupervisor(
+			mockStore,
+			awsMock,
+			&mockRestoreProvisioner{err: errors.New("backup restore should not be used")},
+			&mockEventProducer{},
+			"instanceID",
+			testlib.MakeLogger(t),
+		)
+
+		return restorationSupervisor, mockStore, awsMock
+	}
+
+	newRestorationOp := func(state model.InstallationDBRestorationState) *model.InstallationDBRestorationOperation {
+		return &model.InstallationDBRestorationOperation{
+			ID:             model.NewID(),
+			InstallationID: installation.ID,
+			Type:           model.InstallationDBRestorationTypePointInTime,
+			RestoreTime:    restoreTime,
+			State:          state,
+		}
+	}
+
+	t.Run("trigger restoration", func(t *testing.T) {
+		restorationOp := newRestorationOp(model.InstallationDBRestorationStateRequested)
+		restorationSupervisor, mockStore, awsMock := newRestorationSupervisor(t, restorationOp)
+
+		awsMock.EXPECT().
+			RDSRestoreDBClusterToPointInTime(installation.ID, restorationOp.ID, time.UnixMilli(restoreTime), gomock.Any()).
+			Return(nil)
+
+		restorationSupervisor.Supervise(restorationOp)
+		assert.Equal(t, model.InstallationDBRestorationStateInProgress, mockStore.InstallationRestorationOperation.State)
+	})
+
+	t.Run("trigger restoration outside of restorable window", func(t *testing.T) {
+		restorationOp := newRestorationOp(model.InstallationDBRestorationStateRequested)
+		restorationSupervisor, mockStore, awsMock := newRestorationSupervisor(t, restorationOp)
+
+		awsMock.EXPECT().
+			RDSRestoreDBClusterToPointInTime(installation.ID, restorationOp.ID, gomock.Any(), gomock.Any()).
+			Return(errors.Wrap(aws.ErrRDSRestoreTimeOutOfRange, "some context"))
+
+		restorationSupervisor.Supervise(restorationOp)
+		assert.Equal(t, model.InstallationDBRestorationStateFailing, mockStore.InstallationRestorationOperation.State)
+	})
+
+	t.Run("check restoration status", func(t *testing.T) {
+		for _, testCase := range []struct {
+			description   string
+			done          bool
+			err           error
+			expectedState model.InstallationDBRestorationState
+		}{
+			{
+				description:   "when swap finished",
+				done:          true,
+				expectedState: model.InstallationDBRestorationStateFinalizing,
+			},
+			{
+				description:   "when still in progress",
+				expectedState: model.InstallationDBRestorationStateInProgress,
+			},
+			{
+				description:   "when error",
+				err:           errors.New("some error"),
+				expectedState: model.InstallationDBRestorationStateInProgress,
+			},
+		} {
+			t.Run(testCase.description, func(t *testing.T) {
+				restorationOp := newRestorationOp(model.InstallationDBRestorationStateInProgress)
+				restorationSupervisor, mockStore, awsMock := newRestorationSupervisor(t, restorationOp)
+
+				awsMock.EXPECT().
+					RDSCheckPointInTimeRestore(installation.ID, restorationOp.ID, gomock.Any()).
+					Return(testCase.done, testCase.err)
+
+				restorationSupervisor.Supervise(restorationOp)
+				assert.Equal(t, testCase.expectedState, mockStore.InstallationRestorationOperation.State)
+				if testCase.done {
+					assert.True(t, mockStore.InstallationRestorationOperation.CompleteAt > 0)
+				}
+			})
+		}
+	})
+
+	t.Run("cleanup restoration", func(t *testing.T) {
+		restorationOp := newRestorationOp(model.InstallationDBRestorationStateDeletionRequested)
+		restorationSupervisor, mockStore, awsMock := newRestorationSupervisor(t, restorationOp)
+
+		awsMock.EXPECT().
+			RDSCleanupPointInTimeRestore(installation.ID, restorationOp.ID, gomock.Any()).
+			Return(nil)
+
+		restorationSupervisor.Supervise(restorationOp)
+		assert.Equal(t, model.InstallationDBRestorationStateDeleted, mockStore.InstallationRestorationOperation.State)
+	})
+}
+
 func TestInstallationDBRestorationSupervisor_Supervise(t *testing.T) {
 
 	t.Run("transition to restoration", func(t *testing.T) {
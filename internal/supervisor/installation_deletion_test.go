@@ -85,6 +85,14 @@ func (s *mockEventsProducer) ProduceClusterInstallationStateChangeEvent(clusterI
 	return nil
 }
 
+func (s *mockEventsProducer) ProduceClusterUtilityVersionChangeEvent(cluster *model.Cluster, data *model.ClusterUtilityVersionChangeEventData) error {
+	return nil
+}
+
+func (s *mockEventsProducer) ProduceClusterUtilityUnhealthyEvent(cluster *model.Cluster, data *model.ClusterUtilityUnhealthyEventData) error {
+	return nil
+}
+
 func TestInstallationDeletionSupervisor_Do(t *testing.T) {
 	t.Run("no installation deletion operations pending work", func(t *testing.T) {
 		logger := testlib.MakeLogger(t)
@@ -0,0 +1,193 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package supervisor
+
+import (
+	"sort"
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/internal/events"
+	"github.com/mattermost/mattermost-cloud/model"
+	log "github.com/sirupsen/logrus"
+)
+
+// installationRightSizingStore abstracts the database operations required by
+// the supervisor.
+type installationRightSizingStore interface {
+	GetInstallations(filter *model.InstallationFilter, includeGroupConfig, includeGroupConfigOverrides bool) ([]*model.Installation, error)
+	GetInstallation(installationID string, includeGroupConfig, includeGroupConfigOverrides bool) (*model.Installation, error)
+	GetInstallationUsages(filter *model.InstallationUsageFilter) ([]*model.InstallationUsage, error)
+	UpdateInstallation(installation *model.Installation) error
+	LockInstallation(installationID, lockerID string) (bool, error)
+	UnlockInstallation(installationID, lockerID string, force bool) (bool, error)
+}
+
+// installationRightSizingEventProducer produces the events of the size
+// changes applied by the supervisor.
+type installationRightSizingEventProducer interface {
+	ProduceInstallationStateChangeEvent(installation *model.Installation, oldState string, extraDataFields ...events.DataField) error
+	ProduceInstallationSpecUpdateEvent(installation *model.Installation, data *model.InstallationSpecUpdateEventData) error
+}
+
+// InstallationRightSizingConfig configures the InstallationRightSizingSupervisor.
+type InstallationRightSizingConfig struct {
+	Policy model.SizeRecommendationPolicy
+	// MinConfidence is the confidence required to apply a recommendation.
+	MinConfidence float64
+	// MaxPerRun limits the number of installations resized per run.
+	MaxPerRun int
+	// MaintenanceWindow is the time of day in which sizes are changed.
+	MaintenanceWindow model.MaintenanceWindow
+}
+
+// InstallationRightSizingSupervisor applies size recommendations to stable
+// installations during the maintenance window. Size changes go through the
+// regular installation update flow.
+type InstallationRightSizingSupervisor struct {
+	store          installationRightSizingStore
+	eventsProducer installationRightSizingEventProducer
+	config         InstallationRightSizingConfig
+	instanceID     string
+	logger         log.FieldLogger
+}
+
+// NewInstallationRightSizingSupervisor creates a new InstallationRightSizingSupervisor.
+func NewInstallationRightSizingSupervisor(
+	store installationRightSizingStore,
+	eventsProducer installationRightSizingEventProducer,
+	config InstallationRightSizingConfig,
+	instanceID string,
+	logger log.FieldLogger) *InstallationRightSizingSupervisor {
+	return &InstallationRightSizingSupervisor{
+		store:          store,
+		eventsProducer: eventsProducer,
+		config:         config,
+		instanceID:     instanceID,
+		logger:         logger.WithField("supervisor", "installation-right-sizing"),
+	}
+}
+
+// Shutdown performs graceful shutdown tasks for the supervisor.
+func (s *InstallationRightSizingSupervisor) Shutdown() {
+	s.logger.Debug("Shutting down installation right-sizing supervisor")
+}
+
+// Do applies the most confident size recommendations when in the
+// maintenance window.
+func (s *InstallationRightSizingSupervisor) Do() error {
+	now := time.Now()
+	if !s.config.MaintenanceWindow.Contains(now) {
+		return nil
+	}
+
+	installations, err := s.store.GetInstallations(&model.InstallationFilter{
+		Paging: model.AllPagesNotDeleted(),
+		State:  model.InstallationStateStable,
+	}, false, false)
+	if err != nil {
+		s.logger.WithError(err).Warn("Failed to query installations")
+		return nil
+	}
+
+	// Only the samples of the installations that can be resized are loaded.
+	var inScope []*model.Installation
+	var installationIDs []string
+	for _, installation := range installations {
+		if installation.APISecurityLock {
+			continue
+		}
+		inScope = append(inScope, installation)
+		installationIDs = append(installationIDs, installation.ID)
+	}
+	if len(inScope) == 0 {
+		return nil
+	}
+
+	samples, err := s.store.GetInstallationUsages(&model.InstallationUsageFilter{
+		InstallationIDs: installationIDs,
+		CreatedAfter:    model.GetMillisAtTime(now.Add(-s.config.Policy.Lookback)),
+	})
+	if err != nil {
+		s.logger.WithError(err).Warn("Failed to query installation usage")
+		return nil
+	}
+
+	recommendations, err := s.config.Policy.RecommendInstallationSizes(inScope, samples)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to recommend installation sizes")
+		return nil
+	}
+	sort.SliceStable(recommendations, func(i, j int) bool {
+		return recommendations[i].Confidence > recommendations[j].Confidence
+	})
+
+	var applied int
+	for _, recommendation := range recommendations {
+		if applied >= s.config.MaxPerRun || recommendation.Confidence < s.config.MinConfidence {
+			break
+		}
+		if s.Supervise(recommendation) {
+			applied++
+		}
+	}
+
+	return nil
+}
+
+// Supervise applies a size recommendation, returning true if the
+// installation was resized. The installation is skipped if it changed since
+// the recommendation was made.
+func (s *InstallationRightSizingSupervisor) Supervise(recommendation *model.InstallationSizeRecommendation) bool {
+	logger := s.logger.WithFields(log.Fields{
+		"installation": recommendation.InstallationID,
+		"size":         recommendation.RecommendedSize,
+	})
+
+	installationLock := newInstallationLock(recommendation.InstallationID, s.instanceID, s.store, logger)
+	if !installationLock.TryLock() {
+		return false
+	}
+	defer installationLock.Unlock()
+
+	installation, err := s.store.GetInstallation(recommendation.InstallationID, false, false)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get installation")
+		return false
+	}
+	if installation == nil || installation.State != model.InstallationStateStable || installation.Size != recommendation.CurrentSize {
+		logger.Debug("Installation changed since the recommendation was made")
+		return false
+	}
+	if installation.APISecurityLock {
+		logger.Debug("Installation is locked from API changes")
+		return false
+	}
+
+	oldState := installation.State
+	oldInstallation := installation.Clone()
+
+	installation.Size = recommendation.RecommendedSize
+	installation.State = model.InstallationStateUpdateRequested
+	err = s.store.UpdateInstallation(installation)
+	if err != nil {
+		logger.WithError(err).Error("Failed to update installation size")
+		return false
+	}
+
+	logger.Infof("Resized installation from %s to %s with a confidence of %.2f", recommendation.CurrentSize, recommendation.RecommendedSize, recommendation.Confidence)
+
+	err = s.eventsProducer.ProduceInstallationStateChangeEvent(installation, oldState)
+	if err != nil {
+		logger.WithError(err).Error("Failed to create installation state change event")
+	}
+	if data := model.NewInstallationSpecUpdateEventData(oldInstallation, installation); data != nil {
+		err = s.eventsProducer.ProduceInstallationSpecUpdateEvent(installation, data)
+		if err != nil {
+			logger.WithError(err).Error("Failed to create installation spec update event")
+		}
+	}
+
+	return true
+}
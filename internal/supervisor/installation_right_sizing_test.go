@@ -0,0 +1,184 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package supervisor_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/internal/events"
+	"github.com/mattermost/mattermost-cloud/internal/supervisor"
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockInstallationRightSizingStore struct {
+	Installations map[string]*model.Installation
+	Usages        []*model.InstallationUsage
+	UsageFilter   *model.InstallationUsageFilter
+	Updated       []*model.Installation
+	Locked        map[string]bool
+}
+
+func (m *mockInstallationRightSizingStore) GetInstallations(filter *model.InstallationFilter, includeGroupConfig, includeGroupConfigOverrides bool) ([]*model.Installation, error) {
+	var installations []*model.Installation
+	for _, installation := range m.Installations {
+		if installation.State == filter.State {
+			installations = append(installations, installation.Clone())
+		}
+	}
+	return installations, nil
+}
+
+func (m *mockInstallationRightSizingStore) GetInstallation(installationID string, includeGroupConfig, includeGroupConfigOverrides bool) (*model.Installation, error) {
+	installation, ok := m.Installations[installationID]
+	if !ok {
+		return nil, nil
+	}
+	return installation.Clone(), nil
+}
+
+func (m *mockInstallationRightSizingStore) GetInstallationUsages(filter *model.InstallationUsageFilter) ([]*model.InstallationUsage, error) {
+	m.UsageFilter = filter
+	var usages []*model.InstallationUsage
+	for _, usage := range m.Usages {
+		for _, id := range filter.InstallationIDs {
+			if usage.InstallationID == id {
+				usages = append(usages, usage)
+			}
+		}
+	}
+	return usages, nil
+}
+
+func (m *mockInstallationRightSizingStore) UpdateInstallation(installation *model.Installation) error {
+	m.Updated = append(m.Updated, installation)
+	return nil
+}
+
+func (m *mockInstallationRightSizingStore) LockInstallation(installationID, lockerID string) (bool, error) {
+	if m.Locked[installationID] {
+		return false, nil
+	}
+	return true, nil
+}
+
+func (m *mockInstallationRightSizingStore) UnlockInstallation(installationID, lockerID string, force bool) (bool, error) {
+	return true, nil
+}
+
+type mockInstallationRightSizingEventProducer struct {
+	StateChanges int
+	SpecUpdates  []*model.InstallationSpecUpdateEventData
+}
+
+func (m *mockInstallationRightSizingEventProducer) ProduceInstallationStateChangeEvent(installation *model.Installation, oldState string, extraDataFields ...events.DataField) error {
+	m.StateChanges++
+	return nil
+}
+
+func (m *mockInstallationRightSizingEventProducer) ProduceInstallationSpecUpdateEvent(installation *model.Installation, data *model.InstallationSpecUpdateEventData) error {
+	m.SpecUpdates = append(m.SpecUpdates, data)
+	return nil
+}
+
+func TestInstallationRightSizingSupervisor(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	lookback := 24 * time.Hour
+	now := model.GetMillis()
+
+	idleUsage := func(installationID string) []*model.InstallationUsage {
+		return []*model.InstallationUsage{
+			{InstallationID: installationID, MilliCPU: 10, MemoryBytes: 100 * 1024 * 1024, CreateAt: now - lookback.Milliseconds()},
+			{InstallationID: installationID, MilliCPU: 10, MemoryBytes: 100 * 1024 * 1024, CreateAt: now},
+		}
+	}
+
+	newStore := func() *mockInstallationRightSizingStore {
+		store := &mockInstallationRightSizingStore{
+			Installations: map[string]*model.Installation{
+				"id1": {ID: "id1", Size: "1000users", State: model.InstallationStateStable},
+				"id2": {ID: "id2", Size: "1000users", State: model.InstallationStateStable, APISecurityLock: true},
+				"id3": {ID: "id3", Size: "1000users", State: model.InstallationStateStable},
+			},
+			Locked: map[string]bool{"id3": true},
+		}
+		for id := range store.Installations {
+			store.Usages = append(store.Usages, idleUsage(id)...)
+		}
+		return store
+	}
+
+	config := supervisor.InstallationRightSizingConfig{
+		Policy: model.SizeRecommendationPolicy{
+			Candidates:        model.DefaultSizeRecommendationCandidates,
+			TargetUtilization: 0.8,
+			Lookback:          lookback,
+			MinSamples:        2,
+		},
+		MinConfidence: 0.5,
+		MaxPerRun:     5,
+	}
+
+	t.Run("resize idle installations", func(t *testing.T) {
+		store := newStore()
+		eventProducer := &mockInstallationRightSizingEventProducer{}
+		rightSizingSupervisor := supervisor.NewInstallationRightSizingSupervisor(store, eventProducer, config, "instanceID", logger)
+
+		err := rightSizingSupervisor.Do()
+		require.NoError(t, err)
+
+		require.Len(t, store.Updated, 1)
+		assert.Equal(t, "id1", store.Updated[0].ID)
+		assert.Equal(t, "cloud10users", store.Updated[0].Size)
+		assert.Equal(t, model.InstallationStateUpdateRequested, store.Updated[0].State)
+		assert.Equal(t, 1, eventProducer.StateChanges)
+		require.Len(t, eventProducer.SpecUpdates, 1)
+
+		// Installations locked from API changes are out of scope.
+		require.NotNil(t, store.UsageFilter)
+		assert.ElementsMatch(t, []string{"id1", "id3"}, store.UsageFilter.InstallationIDs)
+		assert.NotZero(t, store.UsageFilter.CreatedAfter)
+	})
+
+	t.Run("confidence too low", func(t *testing.T) {
+		store := newStore()
+		lowConfidenceConfig := config
+		lowConfidenceConfig.MinConfidence = 0.9
+		rightSizingSupervisor := supervisor.NewInstallationRightSizingSupervisor(store, &mockInstallationRightSizingEventProducer{}, lowConfidenceConfig, "instanceID", logger)
+
+		err := rightSizingSupervisor.Do()
+		require.NoError(t, err)
+		assert.Empty(t, store.Updated)
+	})
+
+	t.Run("outside maintenance window", func(t *testing.T) {
+		store := newStore()
+		windowConfig := config
+		hour := time.Now().UTC().Hour()
+		windowConfig.MaintenanceWindow = model.MaintenanceWindow{StartHour: (hour + 1) % 24, EndHour: (hour + 2) % 24}
+		rightSizingSupervisor := supervisor.NewInstallationRightSizingSupervisor(store, &mockInstallationRightSizingEventProducer{}, windowConfig, "instanceID", logger)
+
+		err := rightSizingSupervisor.Do()
+		require.NoError(t, err)
+		assert.Empty(t, store.Updated)
+		assert.Nil(t, store.UsageFilter)
+	})
+
+	t.Run("installation changed since recommendation", func(t *testing.T) {
+		store := newStore()
+		rightSizingSupervisor := supervisor.NewInstallationRightSizingSupervisor(store, &mockInstallationRightSizingEventProducer{}, config, "instanceID", logger)
+
+		applied := rightSizingSupervisor.Supervise(&model.InstallationSizeRecommendation{
+			InstallationID:  "id1",
+			CurrentSize:     "5000users",
+			RecommendedSize: "cloud10users",
+		})
+		assert.False(t, applied)
+		assert.Empty(t, store.Updated)
+	})
+}
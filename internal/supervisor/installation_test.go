@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"math/rand"
 	"testing"
+	"time"
 
 	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	eksTypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
@@ -191,6 +192,10 @@ func (s *mockInstallationStore) GetWebhooks(filter *model.WebhookFilter) ([]*mod
 	return nil, nil
 }
 
+func (s *mockInstallationStore) GetSizeProfileByName(name string) (*model.SizeProfile, error) {
+	return nil, nil
+}
+
 func (s *mockInstallationStore) GetAnnotationsForInstallation(installationID string) ([]*model.Annotation, error) {
 	return nil, nil
 }
@@ -227,6 +232,22 @@ func (s *mockInstallationStore) UnlockInstallationDBMigrationOperations(backupID
 	return true, nil
 }
 
+func (s *mockInstallationStore) GetInstallationFilestoreMigrationOperations(filter *model.InstallationFilestoreMigrationFilter) ([]*model.InstallationFilestoreMigrationOperation, error) {
+	return nil, nil
+}
+
+func (s *mockInstallationStore) UpdateInstallationFilestoreMigrationOperationState(operation *model.InstallationFilestoreMigrationOperation) error {
+	return nil
+}
+
+func (s *mockInstallationStore) LockInstallationFilestoreMigrationOperations(ids []string, lockerID string) (bool, error) {
+	return true, nil
+}
+
+func (s *mockInstallationStore) UnlockInstallationFilestoreMigrationOperations(ids []string, lockerID string, force bool) (bool, error) {
+	return true, nil
+}
+
 func (s *mockInstallationStore) GetInstallationDBRestorationOperations(filter *model.InstallationDBRestorationFilter) ([]*model.InstallationDBRestorationOperation, error) {
 	return nil, nil
 }
@@ -452,6 +473,10 @@ func (a *mockAWS) EnsureEKSNodeGroupMigrated(cluster *model.Cluster, ngPrefix st
 	return nil
 }
 
+func (a *mockAWS) EnsureEKSNodeGroupScaled(clusterName, nodeGroupName string, minCount, desiredCount, maxCount int64) error {
+	return nil
+}
+
 func (a *mockAWS) GetActiveEKSNodeGroup(clusterName, workerName string) (*eksTypes.Nodegroup, error) {
 	return nil, nil
 }
@@ -537,6 +562,10 @@ func (a *mockAWS) S3EnsureObjectDeleted(bucketName, path string) error {
 	return nil
 }
 
+func (a *mockAWS) S3PutObject(bucketName, path, contentType string, body []byte) error {
+	return nil
+}
+
 func (a *mockAWS) GetS3RegionURL() string {
 	return "s3.amazonaws.test.com"
 }
@@ -608,6 +637,9 @@ func (a *mockAWS) GenerateBifrostUtilitySecret(clusterID string, logger log.Fiel
 func (a *mockAWS) GetCIDRByVPCTag(vpcTagName string, logger log.FieldLogger) (string, error) {
 	return "", nil
 }
+func (a *mockAWS) GetCIDRByVPCID(vpcID string) (string, error) {
+	return "", nil
+}
 func (a *mockAWS) S3LargeCopy(srcBucketName, srcKey, destBucketName, destKey *string, logger log.FieldLogger) error {
 	return nil
 }
@@ -616,6 +648,42 @@ func (a *mockAWS) GetMultitenantBucketNameForInstallation(installationID string,
 	return "", nil
 }
 
+func (a *mockAWS) GetS3FilestoreLocation(installationID, filestoreType string, store model.InstallationDatabaseStoreInterface) (*aws.S3FilestoreLocation, error) {
+	return &aws.S3FilestoreLocation{}, nil
+}
+
+func (a *mockAWS) S3CopyObjectsBatch(source, destination *aws.S3FilestoreLocation, continuationToken string, logger log.FieldLogger) (*aws.S3CopyBatchResult, error) {
+	return &aws.S3CopyBatchResult{}, nil
+}
+
+func (a *mockAWS) S3GetObjectsSummary(location *aws.S3FilestoreLocation) (*aws.S3ObjectsSummary, error) {
+	return &aws.S3ObjectsSummary{}, nil
+}
+
+func (a *mockAWS) S3CompareObjects(source, destination *aws.S3FilestoreLocation) (*aws.S3ObjectsComparison, error) {
+	return &aws.S3ObjectsComparison{}, nil
+}
+
+func (a *mockAWS) GetInstallationDatabaseInstanceClasses(installationID string) ([]string, error) {
+	return nil, nil
+}
+
+func (a *mockAWS) GetMultitenantDatabaseInstanceClasses(rdsClusterID string) ([]string, error) {
+	return nil, nil
+}
+
+func (a *mockAWS) RDSRestoreDBClusterToPointInTime(installationID, restorationID string, restoreTime time.Time, logger log.FieldLogger) error {
+	return nil
+}
+
+func (a *mockAWS) RDSCheckPointInTimeRestore(installationID, restorationID string, logger log.FieldLogger) (bool, error) {
+	return true, nil
+}
+
+func (a *mockAWS) RDSCleanupPointInTimeRestore(installationID, restorationID string, logger log.FieldLogger) error {
+	return nil
+}
+
 func (a *mockAWS) SecretsManagerGetPGBouncerAuthUserPassword(vpcID string) (string, error) {
 	return "password", nil
 }
@@ -640,6 +708,8 @@ type mockEventProducer struct {
 	installationListByEventOrder        []string
 	clusterListByEventOrder             []string
 	clusterInstallationListByEventOrder []string
+	clusterUtilityVersionChanges        []*model.ClusterUtilityVersionChangeEventData
+	clusterUtilityUnhealthy             []*model.ClusterUtilityUnhealthyEventData
 }
 
 func (m *mockEventProducer) ProduceInstallationStateChangeEvent(installation *model.Installation, oldState string, extraDataFields ...events.DataField) error {
@@ -656,6 +726,16 @@ func (m *mockEventProducer) ProduceClusterInstallationStateChangeEvent(clusterIn
 	return nil
 }
 
+func (m *mockEventProducer) ProduceClusterUtilityVersionChangeEvent(cluster *model.Cluster, data *model.ClusterUtilityVersionChangeEventData) error {
+	m.clusterUtilityVersionChanges = append(m.clusterUtilityVersionChanges, data)
+	return nil
+}
+
+func (m *mockEventProducer) ProduceClusterUtilityUnhealthyEvent(cluster *model.Cluster, data *model.ClusterUtilityUnhealthyEventData) error {
+	m.clusterUtilityUnhealthy = append(m.clusterUtilityUnhealthy, data)
+	return nil
+}
+
 type mockCloudflareClient struct{}
 
 func (m *mockCloudflareClient) CreateDNSRecords(customerDNSName []string, dnsEndpoints []string, logger log.FieldLogger) error {
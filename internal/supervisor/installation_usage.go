@@ -0,0 +1,362 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package supervisor
+
+import (
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/internal/tools/aws"
+	"github.com/mattermost/mattermost-cloud/k8s"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// installationUsageStore abstracts the database operations required by the supervisor.
+type installationUsageStore interface {
+	GetInstallations(filter *model.InstallationFilter, includeGroupConfig, includeGroupConfigOverrides bool) ([]*model.Installation, error)
+	GetCluster(id string) (*model.Cluster, error)
+	GetInstallationUsages(filter *model.InstallationUsageFilter) ([]*model.InstallationUsage, error)
+	CreateInstallationUsage(usage *model.InstallationUsage) error
+	DeleteInstallationUsagesOlderThan(millis int64) (int64, error)
+	LockInstallation(installationID, lockerID string) (bool, error)
+	UnlockInstallation(installationID, lockerID string, force bool) (bool, error)
+
+	model.InstallationDatabaseStoreInterface
+}
+
+// installationUsageProvisioner measures the resources used in clusters.
+type installationUsageProvisioner interface {
+	GetNamespaceUsageGetter(cluster *model.Cluster) (k8s.NamespaceUsageGetter, error)
+}
+
+// installationUsageCloud measures the cloud resources attributed to
+// installations.
+type installationUsageCloud interface {
+	GetS3FilestoreLocation(installationID, filestoreType string, store model.InstallationDatabaseStoreInterface) (*aws.S3FilestoreLocation, error)
+	S3GetObjectsSummary(location *aws.S3FilestoreLocation) (*aws.S3ObjectsSummary, error)
+	GetInstallationDatabaseInstanceClasses(installationID string) ([]string, error)
+	GetMultitenantDatabaseInstanceClasses(rdsClusterID string) ([]string, error)
+}
+
+// InstallationUsageConfig configures the InstallationUsageSupervisor.
+type InstallationUsageConfig struct {
+	// Interval is the minimum time between two usage samples of an
+	// installation.
+	Interval time.Duration
+	// Retention is the age after which usage samples are pruned. Zero keeps
+	// samples forever.
+	Retention time.Duration
+	// FilestoreInterval is the minimum time between two measurements of the
+	// filestore of an installation. Measuring an S3 filestore lists all of
+	// its objects, so samples in between reuse the latest measurement. Zero
+	// measures the filestore on every sample.
+	FilestoreInterval time.Duration
+}
+
+// InstallationUsageSupervisor periodically samples the resources used by
+// every installation: the CPU, memory and volumes of their namespaces, the
+// size of their S3 filestore and their share of their database instances.
+// Samples are gated on the ones already stored, so several provisioner
+// instances can run the supervisor without duplicating samples.
+//
+// Sampling is not done by the cluster installation supervisor: it only
+// visits cluster installations with pending work, while usage is sampled for
+// every installation on the slow poll and summed over all of its cluster
+// installations. S3 filestores are measured by listing their objects rather
+// than with the CloudWatch BucketSizeBytes metric, which only reports whole
+// buckets and can't attribute the shared multitenant and bifrost buckets.
+type InstallationUsageSupervisor struct {
+	store       installationUsageStore
+	provisioner installationUsageProvisioner
+	cloud       installationUsageCloud
+	config      InstallationUsageConfig
+	instanceID  string
+	logger      log.FieldLogger
+}
+
+// NewInstallationUsageSupervisor creates a new InstallationUsageSupervisor.
+func NewInstallationUsageSupervisor(
+	store installationUsageStore,
+	provisioner installationUsageProvisioner,
+	cloud installationUsageCloud,
+	config InstallationUsageConfig,
+	instanceID string,
+	logger log.FieldLogger) *InstallationUsageSupervisor {
+	return &InstallationUsageSupervisor{
+		store:       store,
+		provisioner: provisioner,
+		cloud:       cloud,
+		config:      config,
+		instanceID:  instanceID,
+		logger:      logger.WithField("supervisor", "installation-usage"),
+	}
+}
+
+// Shutdown performs graceful shutdown tasks for the supervisor.
+func (s *InstallationUsageSupervisor) Shutdown() {
+	s.logger.Debug("Shutting down installation usage supervisor")
+}
+
+// Do samples the usage of the installations that were not sampled within the
+// interval and prunes expired samples.
+func (s *InstallationUsageSupervisor) Do() error {
+	installations, err := s.store.GetInstallations(&model.InstallationFilter{Paging: model.AllPagesNotDeleted()}, false, false)
+	if err != nil {
+		s.logger.WithError(err).Warn("Failed to query installations")
+		return nil
+	}
+
+	cache := newInstallationUsageCache()
+	for _, installation := range installations {
+		s.Supervise(installation, cache)
+	}
+
+	if s.config.Retention > 0 {
+		deleted, err := s.store.DeleteInstallationUsagesOlderThan(model.GetMillisAtTime(time.Now().Add(-s.config.Retention)))
+		if err != nil {
+			s.logger.WithError(err).Error("Failed to prune installation usage")
+			return nil
+		}
+		if deleted > 0 {
+			s.logger.Debugf("Pruned %d installation usage samples", deleted)
+		}
+	}
+
+	return nil
+}
+
+// installationUsageCache holds the resources shared by installations while
+// sampling them.
+type installationUsageCache struct {
+	clusters map[string]*model.Cluster
+	// namespaceUsage are the clients measuring namespace usage by cluster ID.
+	namespaceUsage map[string]k8s.NamespaceUsageGetter
+	// databases are the instance classes of multitenant databases by ID.
+	databases map[string][]string
+	// databaseWeights are the total weights of the installations of
+	// multitenant databases by ID.
+	databaseWeights map[string]float64
+}
+
+func newInstallationUsageCache() *installationUsageCache {
+	return &installationUsageCache{
+		clusters:        map[string]*model.Cluster{},
+		namespaceUsage:  map[string]k8s.NamespaceUsageGetter{},
+		databases:       map[string][]string{},
+		databaseWeights: map[string]float64{},
+	}
+}
+
+// Supervise records a usage sample of the given installation unless it was
+// already sampled within the interval. Resources shared by installations are
+// cached across installations.
+func (s *InstallationUsageSupervisor) Supervise(installation *model.Installation, cache *installationUsageCache) {
+	logger := s.logger.WithFields(log.Fields{
+		"installation": installation.ID,
+	})
+
+	sampled, err := s.sampledRecently(installation.ID)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get latest installation usage")
+		return
+	}
+	if sampled {
+		return
+	}
+
+	clusterInstallations, err := s.store.GetClusterInstallations(&model.ClusterInstallationFilter{
+		InstallationID: installation.ID,
+		Paging:         model.AllPagesNotDeleted(),
+	})
+	if err != nil {
+		logger.WithError(err).Error("Failed to get cluster installations")
+		return
+	}
+	if len(clusterInstallations) == 0 {
+		logger.Debug("Installation has no cluster installations to sample")
+		return
+	}
+
+	usage := &model.InstallationUsage{
+		InstallationID: installation.ID,
+		ClusterID:      clusterInstallations[0].ClusterID,
+	}
+
+	for _, clusterInstallation := range clusterInstallations {
+		cluster, ok := cache.clusters[clusterInstallation.ClusterID]
+		if !ok {
+			cluster, err = s.store.GetCluster(clusterInstallation.ClusterID)
+			if err != nil {
+				logger.WithError(err).Error("Failed to get cluster")
+				return
+			}
+			if cluster == nil {
+				logger.Warnf("Cluster %s not found", clusterInstallation.ClusterID)
+				return
+			}
+			cache.clusters[cluster.ID] = cluster
+		}
+
+		usageGetter, ok := cache.namespaceUsage[cluster.ID]
+		if !ok {
+			usageGetter, err = s.provisioner.GetNamespaceUsageGetter(cluster)
+			if err != nil {
+				logger.WithError(err).Warn("Failed to get cluster client")
+				return
+			}
+			cache.namespaceUsage[cluster.ID] = usageGetter
+		}
+
+		namespaceUsage, err := usageGetter.GetNamespaceUsage(clusterInstallation.Namespace)
+		if err != nil {
+			logger.WithError(err).Warn("Failed to get namespace usage")
+			return
+		}
+		usage.PodCount += namespaceUsage.PodCount
+		usage.MilliCPU += namespaceUsage.MilliCPU
+		usage.MemoryBytes += namespaceUsage.MemoryBytes
+		usage.VolumeBytes += namespaceUsage.VolumeBytes
+	}
+
+	switch installation.Filestore {
+	case model.InstallationFilestoreAwsS3, model.InstallationFilestoreMultiTenantAwsS3, model.InstallationFilestoreBifrost:
+		err = s.setFilestoreUsage(installation, usage)
+		if err != nil {
+			logger.WithError(err).Warn("Failed to get filestore usage")
+			return
+		}
+	}
+
+	switch {
+	case model.IsSingleTenantRDS(installation.Database):
+		classes, err := s.cloud.GetInstallationDatabaseInstanceClasses(installation.ID)
+		if err != nil {
+			logger.WithError(err).Warn("Failed to get database instances")
+			return
+		}
+		usage.DatabaseInstanceClasses = classes
+		usage.DatabaseShare = 1
+	case model.IsMultiTenantRDS(installation.Database):
+		err = s.setSharedDatabaseUsage(installation, usage, cache)
+		if err != nil {
+			logger.WithError(err).Warn("Failed to get multitenant database usage")
+			return
+		}
+	}
+
+	// Collecting the usage is slow, so the installation is only locked while
+	// checking again that no other instance recorded a sample meanwhile.
+	installationLock := newInstallationLock(installation.ID, s.instanceID, s.store, logger)
+	if !installationLock.TryLock() {
+		logger.Debug("Installation is locked, skipping usage sample")
+		return
+	}
+	defer installationLock.Unlock()
+
+	sampled, err = s.sampledRecently(installation.ID)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get latest installation usage")
+		return
+	}
+	if sampled {
+		return
+	}
+
+	err = s.store.CreateInstallationUsage(usage)
+	if err != nil {
+		logger.WithError(err).Error("Failed to record installation usage")
+		return
+	}
+}
+
+// setFilestoreUsage sets the size of the S3 filestore of the installation,
+// reusing the latest measurement if it was made within the filestore
+// interval.
+func (s *InstallationUsageSupervisor) setFilestoreUsage(installation *model.Installation, usage *model.InstallationUsage) error {
+	if s.config.FilestoreInterval > 0 {
+		measuredAfter := model.GetMillisAtTime(time.Now().Add(-s.config.FilestoreInterval))
+		usages, err := s.store.GetInstallationUsages(&model.InstallationUsageFilter{
+			InstallationID: installation.ID,
+			CreatedAfter:   measuredAfter,
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to get recent installation usage")
+		}
+		if len(usages) > 0 {
+			latest := usages[len(usages)-1]
+			if latest.FilestoreMeasureAt >= measuredAfter {
+				usage.FilestoreBytes = latest.FilestoreBytes
+				usage.FilestoreMeasureAt = latest.FilestoreMeasureAt
+				return nil
+			}
+		}
+	}
+
+	location, err := s.cloud.GetS3FilestoreLocation(installation.ID, installation.Filestore, s.store)
+	if err != nil {
+		return errors.Wrap(err, "failed to get filestore location")
+	}
+	summary, err := s.cloud.S3GetObjectsSummary(location)
+	if err != nil {
+		return errors.Wrap(err, "failed to list filestore objects")
+	}
+	usage.FilestoreBytes = summary.Bytes
+	usage.FilestoreMeasureAt = model.GetMillis()
+
+	return nil
+}
+
+// setSharedDatabaseUsage attributes to the installation the instances of its
+// multitenant database in proportion to its database weight.
+func (s *InstallationUsageSupervisor) setSharedDatabaseUsage(installation *model.Installation, usage *model.InstallationUsage, cache *installationUsageCache) error {
+	database, err := s.store.GetMultitenantDatabaseForInstallationID(installation.ID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get multitenant database")
+	}
+
+	classes, ok := cache.databases[database.ID]
+	if !ok {
+		classes, err = s.cloud.GetMultitenantDatabaseInstanceClasses(database.RdsClusterID)
+		if err != nil {
+			return errors.Wrap(err, "failed to get database instances")
+		}
+		cache.databases[database.ID] = classes
+	}
+
+	totalWeight, ok := cache.databaseWeights[database.ID]
+	if !ok {
+		totalWeight, err = s.store.GetInstallationsTotalDatabaseWeight(database.Installations)
+		if err != nil {
+			return errors.Wrap(err, "failed to get total database weight")
+		}
+		cache.databaseWeights[database.ID] = totalWeight
+	}
+
+	usage.DatabaseInstanceClasses = classes
+	if totalWeight > 0 {
+		usage.DatabaseShare = installation.GetDatabaseWeight() / totalWeight
+	}
+
+	return nil
+}
+
+// sampledRecently returns true if a usage sample of the installation was
+// recorded within the interval.
+func (s *InstallationUsageSupervisor) sampledRecently(installationID string) (bool, error) {
+	if s.config.Interval <= 0 {
+		return false, nil
+	}
+
+	usages, err := s.store.GetInstallationUsages(&model.InstallationUsageFilter{
+		InstallationID: installationID,
+		CreatedAfter:   model.GetMillisAtTime(time.Now().Add(-s.config.Interval)),
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return len(usages) > 0, nil
+}
@@ -0,0 +1,288 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package supervisor_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/internal/supervisor"
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/internal/tools/aws"
+	"github.com/mattermost/mattermost-cloud/k8s"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockInstallationUsageStore struct {
+	model.InstallationDatabaseStoreInterface
+
+	Installations        []*model.Installation
+	ClusterInstallations map[string][]*model.ClusterInstallation
+	Usages               []*model.InstallationUsage
+	PrunedBefore         int64
+	GetClusterCalls      int
+	LockedInstallations  map[string]bool
+	MultitenantDatabase  *model.MultitenantDatabase
+	DatabaseWeightCalls  int
+}
+
+func (m *mockInstallationUsageStore) GetMultitenantDatabaseForInstallationID(installationID string) (*model.MultitenantDatabase, error) {
+	return m.MultitenantDatabase, nil
+}
+
+func (m *mockInstallationUsageStore) GetInstallationsTotalDatabaseWeight(installationIDs []string) (float64, error) {
+	m.DatabaseWeightCalls++
+	return float64(len(installationIDs)) * model.DefaultDatabaseWeight, nil
+}
+
+func (m *mockInstallationUsageStore) GetInstallationUsages(filter *model.InstallationUsageFilter) ([]*model.InstallationUsage, error) {
+	usages := []*model.InstallationUsage{}
+	for _, usage := range m.Usages {
+		if usage.InstallationID == filter.InstallationID && usage.CreateAt >= filter.CreatedAfter {
+			usages = append(usages, usage)
+		}
+	}
+	return usages, nil
+}
+
+func (m *mockInstallationUsageStore) LockInstallation(installationID, lockerID string) (bool, error) {
+	return !m.LockedInstallations[installationID], nil
+}
+
+func (m *mockInstallationUsageStore) UnlockInstallation(installationID, lockerID string, force bool) (bool, error) {
+	return true, nil
+}
+
+func (m *mockInstallationUsageStore) GetInstallations(filter *model.InstallationFilter, includeGroupConfig, includeGroupConfigOverrides bool) ([]*model.Installation, error) {
+	return m.Installations, nil
+}
+
+func (m *mockInstallationUsageStore) GetClusterInstallations(filter *model.ClusterInstallationFilter) ([]*model.ClusterInstallation, error) {
+	return m.ClusterInstallations[filter.InstallationID], nil
+}
+
+func (m *mockInstallationUsageStore) GetCluster(id string) (*model.Cluster, error) {
+	m.GetClusterCalls++
+	return &model.Cluster{ID: id}, nil
+}
+
+func (m *mockInstallationUsageStore) CreateInstallationUsage(usage *model.InstallationUsage) error {
+	usage.CreateAt = model.GetMillis()
+	m.Usages = append(m.Usages, usage)
+	return nil
+}
+
+func (m *mockInstallationUsageStore) DeleteInstallationUsagesOlderThan(millis int64) (int64, error) {
+	m.PrunedBefore = millis
+	return 0, nil
+}
+
+type mockInstallationUsageProvisioner struct {
+	Usage        map[string]*k8s.NamespaceUsage
+	ClientsCalls int
+}
+
+func (m *mockInstallationUsageProvisioner) GetNamespaceUsageGetter(cluster *model.Cluster) (k8s.NamespaceUsageGetter, error) {
+	m.ClientsCalls++
+	return m, nil
+}
+
+func (m *mockInstallationUsageProvisioner) GetNamespaceUsage(namespace string) (*k8s.NamespaceUsage, error) {
+	usage, ok := m.Usage[namespace]
+	if !ok {
+		return nil, errors.New("metrics not available")
+	}
+	return usage, nil
+}
+
+type mockInstallationUsageCloud struct {
+	FilestoreBytes             map[string]int64
+	DatabaseClasses            map[string][]string
+	MultitenantDatabaseClasses map[string][]string
+	S3ListCalls                int
+}
+
+func (m *mockInstallationUsageCloud) GetS3FilestoreLocation(installationID, filestoreType string, store model.InstallationDatabaseStoreInterface) (*aws.S3FilestoreLocation, error) {
+	return &aws.S3FilestoreLocation{Bucket: "bucket", Prefix: installationID + "/"}, nil
+}
+
+func (m *mockInstallationUsageCloud) S3GetObjectsSummary(location *aws.S3FilestoreLocation) (*aws.S3ObjectsSummary, error) {
+	m.S3ListCalls++
+	return &aws.S3ObjectsSummary{Bytes: m.FilestoreBytes[location.Prefix]}, nil
+}
+
+func (m *mockInstallationUsageCloud) GetInstallationDatabaseInstanceClasses(installationID string) ([]string, error) {
+	return m.DatabaseClasses[installationID], nil
+}
+
+func (m *mockInstallationUsageCloud) GetMultitenantDatabaseInstanceClasses(rdsClusterID string) ([]string, error) {
+	return m.MultitenantDatabaseClasses[rdsClusterID], nil
+}
+
+func TestInstallationUsageSupervisor(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+
+	newStore := func() *mockInstallationUsageStore {
+		return &mockInstallationUsageStore{
+			Installations: []*model.Installation{
+				{ID: "single", Filestore: model.InstallationFilestoreAwsS3, Database: model.InstallationDatabaseSingleTenantRDSPostgres},
+				{ID: "multi", Filestore: model.InstallationFilestoreBifrost, Database: model.InstallationDatabaseMultiTenantRDSPostgres},
+				{ID: "operator", Filestore: model.InstallationFilestoreMinioOperator, Database: model.InstallationDatabaseMysqlOperator},
+				{ID: "pending"},
+			},
+			ClusterInstallations: map[string][]*model.ClusterInstallation{
+				"single":   {{ClusterID: "cluster1", Namespace: "single"}},
+				"multi":    {{ClusterID: "cluster1", Namespace: "multi"}},
+				"operator": {{ClusterID: "cluster2", Namespace: "operator"}},
+			},
+			MultitenantDatabase: &model.MultitenantDatabase{
+				ID:            "database1",
+				RdsClusterID:  "rds-cluster-multitenant",
+				Installations: model.MultitenantDatabaseInstallations{"multi", "other1", "other2", "other3"},
+			},
+		}
+	}
+	provisioner := &mockInstallationUsageProvisioner{
+		Usage: map[string]*k8s.NamespaceUsage{
+			"single":   {PodCount: 2, MilliCPU: 200, MemoryBytes: 1000, VolumeBytes: 0},
+			"multi":    {PodCount: 1, MilliCPU: 100, MemoryBytes: 500, VolumeBytes: 0},
+			"operator": {PodCount: 4, MilliCPU: 400, MemoryBytes: 2000, VolumeBytes: 3000},
+		},
+	}
+	cloud := &mockInstallationUsageCloud{
+		FilestoreBytes:             map[string]int64{"single/": 10, "multi/": 20},
+		DatabaseClasses:            map[string][]string{"single": {"db.r5.large"}},
+		MultitenantDatabaseClasses: map[string][]string{"rds-cluster-multitenant": {"db.r5.xlarge", "db.r5.xlarge"}},
+	}
+
+	t.Run("sample installations", func(t *testing.T) {
+		store := newStore()
+		provisioner.ClientsCalls = 0
+		usageSupervisor := supervisor.NewInstallationUsageSupervisor(store, provisioner, cloud, supervisor.InstallationUsageConfig{
+			Interval:  time.Hour,
+			Retention: 24 * time.Hour,
+		}, "instance-id", logger)
+
+		err := usageSupervisor.Do()
+		require.NoError(t, err)
+
+		require.Len(t, store.Usages, 3)
+		assert.Equal(t, &model.InstallationUsage{
+			InstallationID:          "single",
+			ClusterID:               "cluster1",
+			PodCount:                2,
+			MilliCPU:                200,
+			MemoryBytes:             1000,
+			FilestoreBytes:          10,
+			DatabaseInstanceClasses: model.DatabaseInstanceClasses{"db.r5.large"},
+			DatabaseShare:           1,
+			FilestoreMeasureAt:      store.Usages[0].FilestoreMeasureAt,
+			CreateAt:                store.Usages[0].CreateAt,
+		}, store.Usages[0])
+		assert.NotZero(t, store.Usages[0].FilestoreMeasureAt)
+		assert.Equal(t, &model.InstallationUsage{
+			InstallationID:          "multi",
+			ClusterID:               "cluster1",
+			PodCount:                1,
+			MilliCPU:                100,
+			MemoryBytes:             500,
+			FilestoreBytes:          20,
+			DatabaseInstanceClasses: model.DatabaseInstanceClasses{"db.r5.xlarge", "db.r5.xlarge"},
+			DatabaseShare:           0.25,
+			FilestoreMeasureAt:      store.Usages[1].FilestoreMeasureAt,
+			CreateAt:                store.Usages[1].CreateAt,
+		}, store.Usages[1])
+		assert.NotZero(t, store.Usages[1].FilestoreMeasureAt)
+		assert.Equal(t, &model.InstallationUsage{
+			InstallationID: "operator",
+			ClusterID:      "cluster2",
+			PodCount:       4,
+			MilliCPU:       400,
+			MemoryBytes:    2000,
+			VolumeBytes:    3000,
+			CreateAt:       store.Usages[2].CreateAt,
+		}, store.Usages[2])
+		assert.Equal(t, 2, store.GetClusterCalls)
+		assert.Equal(t, 2, provisioner.ClientsCalls)
+		assert.InDelta(t, model.GetMillisAtTime(time.Now().Add(-24*time.Hour)), store.PrunedBefore, float64(time.Minute.Milliseconds()))
+
+		// Installations are sampled at most once per interval.
+		err = usageSupervisor.Do()
+		require.NoError(t, err)
+		assert.Len(t, store.Usages, 3)
+	})
+
+	t.Run("sampled by another instance", func(t *testing.T) {
+		store := newStore()
+		store.Usages = []*model.InstallationUsage{
+			{InstallationID: "single", CreateAt: model.GetMillisAtTime(time.Now().Add(-30 * time.Minute))},
+			{InstallationID: "multi", CreateAt: model.GetMillisAtTime(time.Now().Add(-2 * time.Hour))},
+		}
+		usageSupervisor := supervisor.NewInstallationUsageSupervisor(store, provisioner, cloud, supervisor.InstallationUsageConfig{
+			Interval: time.Hour,
+		}, "instance-id", logger)
+
+		err := usageSupervisor.Do()
+		require.NoError(t, err)
+		require.Len(t, store.Usages, 4)
+		assert.Equal(t, "multi", store.Usages[2].InstallationID)
+		assert.Equal(t, "operator", store.Usages[3].InstallationID)
+	})
+
+	t.Run("filestore measured once per filestore interval", func(t *testing.T) {
+		store := newStore()
+		measureAt := model.GetMillisAtTime(time.Now().Add(-6 * time.Hour))
+		store.Usages = []*model.InstallationUsage{
+			{InstallationID: "single", FilestoreBytes: 5, FilestoreMeasureAt: measureAt, CreateAt: model.GetMillisAtTime(time.Now().Add(-2 * time.Hour))},
+			{InstallationID: "multi", FilestoreBytes: 5, FilestoreMeasureAt: model.GetMillisAtTime(time.Now().Add(-25 * time.Hour)), CreateAt: model.GetMillisAtTime(time.Now().Add(-2 * time.Hour))},
+		}
+		cloud.S3ListCalls = 0
+		usageSupervisor := supervisor.NewInstallationUsageSupervisor(store, provisioner, cloud, supervisor.InstallationUsageConfig{
+			Interval:          time.Hour,
+			FilestoreInterval: 24 * time.Hour,
+		}, "instance-id", logger)
+
+		err := usageSupervisor.Do()
+		require.NoError(t, err)
+		require.Len(t, store.Usages, 5)
+		assert.Equal(t, 1, cloud.S3ListCalls)
+
+		// The recent measurement of the single tenant filestore is reused.
+		assert.Equal(t, "single", store.Usages[2].InstallationID)
+		assert.Equal(t, int64(5), store.Usages[2].FilestoreBytes)
+		assert.Equal(t, measureAt, store.Usages[2].FilestoreMeasureAt)
+
+		// The stale measurement of the bifrost filestore is replaced.
+		assert.Equal(t, "multi", store.Usages[3].InstallationID)
+		assert.Equal(t, int64(20), store.Usages[3].FilestoreBytes)
+		assert.Greater(t, store.Usages[3].FilestoreMeasureAt, measureAt)
+	})
+
+	t.Run("installation locked", func(t *testing.T) {
+		store := newStore()
+		store.LockedInstallations = map[string]bool{"single": true}
+		usageSupervisor := supervisor.NewInstallationUsageSupervisor(store, provisioner, cloud, supervisor.InstallationUsageConfig{}, "instance-id", logger)
+
+		err := usageSupervisor.Do()
+		require.NoError(t, err)
+		require.Len(t, store.Usages, 2)
+		assert.Equal(t, "multi", store.Usages[0].InstallationID)
+	})
+
+	t.Run("metrics unavailable", func(t *testing.T) {
+		store := newStore()
+		store.ClusterInstallations["single"][0].Namespace = "missing"
+		usageSupervisor := supervisor.NewInstallationUsageSupervisor(store, provisioner, cloud, supervisor.InstallationUsageConfig{}, "instance-id", logger)
+
+		err := usageSupervisor.Do()
+		require.NoError(t, err)
+		require.Len(t, store.Usages, 2)
+		assert.Equal(t, "multi", store.Usages[0].InstallationID)
+		assert.Equal(t, int64(0), store.PrunedBefore)
+	})
+}
@@ -0,0 +1,173 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package supervisor
+
+import (
+	"sort"
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/k8s"
+	"github.com/mattermost/mattermost-cloud/model"
+	log "github.com/sirupsen/logrus"
+)
+
+// nodeGroupAutoscalingStore abstracts the database operations required by the supervisor.
+type nodeGroupAutoscalingStore interface {
+	GetClusters(filter *model.ClusterFilter) ([]*model.Cluster, error)
+	GetCluster(clusterID string) (*model.Cluster, error)
+	UpdateCluster(cluster *model.Cluster) error
+	clusterLockStore
+}
+
+// nodeGroupAutoscalingProvisioner inspects and scales cluster nodegroups.
+type nodeGroupAutoscalingProvisioner interface {
+	GetNodeGroupResources(cluster *model.Cluster, nodeGroup string) (*k8s.ClusterResources, error)
+	ScaleNodeGroup(cluster *model.Cluster, nodeGroup string, minCount, desiredCount, maxCount int64) error
+}
+
+// NodeGroupAutoscalingSupervisor periodically reconciles the autoscaling
+// policies of the nodegroups of stable EKS clusters against the resources
+// requested on their nodes.
+type NodeGroupAutoscalingSupervisor struct {
+	store       nodeGroupAutoscalingStore
+	provisioner nodeGroupAutoscalingProvisioner
+	instanceID  string
+	logger      log.FieldLogger
+}
+
+// NewNodeGroupAutoscalingSupervisor creates a new NodeGroupAutoscalingSupervisor.
+func NewNodeGroupAutoscalingSupervisor(
+	store nodeGroupAutoscalingStore,
+	provisioner nodeGroupAutoscalingProvisioner,
+	instanceID string,
+	logger log.FieldLogger) *NodeGroupAutoscalingSupervisor {
+	return &NodeGroupAutoscalingSupervisor{
+		store:       store,
+		provisioner: provisioner,
+		instanceID:  instanceID,
+		logger:      logger.WithField("supervisor", "nodegroup-autoscaling"),
+	}
+}
+
+// Shutdown performs graceful shutdown tasks for the supervisor.
+func (s *NodeGroupAutoscalingSupervisor) Shutdown() {
+	s.logger.Debug("Shutting down nodegroup autoscaling supervisor")
+}
+
+// Do reconciles the nodegroup autoscaling policies of all stable EKS clusters.
+func (s *NodeGroupAutoscalingSupervisor) Do() error {
+	clusters, err := s.store.GetClusters(&model.ClusterFilter{Paging: model.AllPagesNotDeleted()})
+	if err != nil {
+		s.logger.WithError(err).Warn("Failed to query clusters")
+		return nil
+	}
+
+	for _, cluster := range clusters {
+		if !hasNodeGroupAutoscaling(cluster) {
+			continue
+		}
+		s.Supervise(cluster)
+	}
+
+	return nil
+}
+
+// Supervise reconciles the autoscaling policies of the nodegroups of the
+// given cluster.
+func (s *NodeGroupAutoscalingSupervisor) Supervise(cluster *model.Cluster) {
+	logger := s.logger.WithFields(log.Fields{
+		"cluster": cluster.ID,
+	})
+
+	lock := newClusterLock(cluster.ID, s.instanceID, s.store, logger)
+	if !lock.TryLock() {
+		return
+	}
+	defer lock.Unlock()
+
+	// The nodegroups may have been changed by the cluster supervisor since the
+	// cluster was listed.
+	cluster, err := s.store.GetCluster(cluster.ID)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get refreshed cluster")
+		return
+	}
+	if cluster == nil || !hasNodeGroupAutoscaling(cluster) {
+		logger.Debug("Cluster no longer has nodegroup autoscaling to reconcile")
+		return
+	}
+
+	eksMetadata := cluster.ProvisionerMetadataEKS
+
+	var nodeGroups []string
+	for name, ng := range eksMetadata.NodeGroups {
+		if ng.AutoscalingPolicy != nil {
+			nodeGroups = append(nodeGroups, name)
+		}
+	}
+	sort.Strings(nodeGroups)
+
+	now := time.Now()
+	changed := false
+	for _, name := range nodeGroups {
+		ngLogger := logger.WithField("nodegroup", name)
+		ng := eksMetadata.NodeGroups[name]
+
+		resources, err := s.provisioner.GetNodeGroupResources(cluster, name)
+		if err != nil {
+			ngLogger.WithError(err).Warn("Failed to get nodegroup resources")
+			continue
+		}
+
+		status := ng.RecommendScaling(model.NodeGroupUsage{
+			NodeCount:        resources.WorkerNodeCount,
+			MilliTotalCPU:    resources.MilliTotalCPU,
+			MilliUsedCPU:     resources.MilliUsedCPU,
+			MilliTotalMemory: resources.MilliTotalMemory,
+			MilliUsedMemory:  resources.MilliUsedMemory,
+		}, now)
+
+		if cluster.APISecurityLock {
+			ngLogger.Debug("Cluster is locked; recording nodegroup scaling recommendation only")
+		} else {
+			err = s.provisioner.ScaleNodeGroup(cluster, name, status.MinCount, status.DesiredCount, ng.MaxCount)
+			if err != nil {
+				ngLogger.WithError(err).Error("Failed to scale nodegroup")
+				continue
+			}
+			if ng.AutoscalingStatus == nil || ng.AutoscalingStatus.DesiredCount != status.DesiredCount {
+				ngLogger.Infof("Scaled nodegroup to %d nodes: %s", status.DesiredCount, status.Reason)
+			}
+		}
+
+		if !status.SameScaling(ng.AutoscalingStatus) {
+			changed = true
+		}
+		ng.AutoscalingStatus = status
+		eksMetadata.NodeGroups[name] = ng
+	}
+
+	// Nodegroups are reconciled on every poll, so the cluster is only
+	// persisted when a scaling decision differs from the stored one. The
+	// stored LastReconciledAt is therefore the time of the last change.
+	if !changed {
+		logger.Debug("Nodegroup autoscaling status unchanged")
+		return
+	}
+
+	err = s.store.UpdateCluster(cluster)
+	if err != nil {
+		logger.WithError(err).Error("Failed to update nodegroup autoscaling status")
+	}
+}
+
+// hasNodeGroupAutoscaling returns whether the cluster is a stable EKS cluster
+// with nodegroup autoscaling policies.
+func hasNodeGroupAutoscaling(cluster *model.Cluster) bool {
+	return cluster.State == model.ClusterStateStable &&
+		cluster.Provisioner == model.ProvisionerEKS &&
+		cluster.ProvisionerMetadataEKS != nil &&
+		cluster.ProvisionerMetadataEKS.HasAutoscalingPolicies()
+}
@@ -0,0 +1,191 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package supervisor_test
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-cloud/internal/supervisor"
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/k8s"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockNodeGroupAutoscalingStore struct {
+	Cluster *model.Cluster
+
+	UpdateClusterCalls int
+}
+
+func (m *mockNodeGroupAutoscalingStore) GetClusters(filter *model.ClusterFilter) ([]*model.Cluster, error) {
+	return []*model.Cluster{m.Cluster}, nil
+}
+
+func (m *mockNodeGroupAutoscalingStore) GetCluster(clusterID string) (*model.Cluster, error) {
+	return m.Cluster, nil
+}
+
+func (m *mockNodeGroupAutoscalingStore) UpdateCluster(cluster *model.Cluster) error {
+	m.UpdateClusterCalls++
+	return nil
+}
+
+func (m *mockNodeGroupAutoscalingStore) LockCluster(clusterID, lockerID string) (bool, error) {
+	return true, nil
+}
+
+func (m *mockNodeGroupAutoscalingStore) UnlockCluster(clusterID, lockerID string, force bool) (bool, error) {
+	return true, nil
+}
+
+func (m *mockNodeGroupAutoscalingStore) LockClusterScheduling(clusterID, lockerID string) (bool, error) {
+	return true, nil
+}
+
+func (m *mockNodeGroupAutoscalingStore) UnlockClusterScheduling(clusterID, lockerID string, force bool) (bool, error) {
+	return true, nil
+}
+
+type nodeGroupScaling struct {
+	NodeGroup string
+	Min       int64
+	Desired   int64
+	Max       int64
+}
+
+type mockNodeGroupAutoscalingProvisioner struct {
+	Resources map[string]*k8s.ClusterResources
+	Scaled    []nodeGroupScaling
+}
+
+func (m *mockNodeGroupAutoscalingProvisioner) GetNodeGroupResources(cluster *model.Cluster, nodeGroup string) (*k8s.ClusterResources, error) {
+	resources, ok := m.Resources[nodeGroup]
+	if !ok {
+		return nil, errors.New("no nodes found")
+	}
+	return resources, nil
+}
+
+func (m *mockNodeGroupAutoscalingProvisioner) ScaleNodeGroup(cluster *model.Cluster, nodeGroup string, minCount, desiredCount, maxCount int64) error {
+	m.Scaled = append(m.Scaled, nodeGroupScaling{nodeGroup, minCount, desiredCount, maxCount})
+	return nil
+}
+
+func newMockNodeGroupAutoscalingStore() *mockNodeGroupAutoscalingStore {
+	return &mockNodeGroupAutoscalingStore{
+		Cluster: &model.Cluster{
+			ID:          model.NewID(),
+			State:       model.ClusterStateStable,
+			Provisioner: model.ProvisionerEKS,
+			ProvisionerMetadataEKS: &model.EKSMetadata{
+				Name: "test-cluster",
+				NodeGroups: map[string]model.NodeGroupMetadata{
+					model.NodeGroupWorker: {
+						Name:     "worker-abc",
+						MinCount: 2,
+						MaxCount: 10,
+						AutoscalingPolicy: &model.NodeGroupAutoscalingPolicy{
+							TargetCPUUtilization: 50,
+						},
+					},
+					"calls": {
+						Name:     "calls-abc",
+						MinCount: 1,
+						MaxCount: 1,
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestNodeGroupAutoscalingSupervisor(t *testing.T) {
+	resources := map[string]*k8s.ClusterResources{
+		model.NodeGroupWorker: {
+			WorkerNodeCount:  2,
+			MilliTotalCPU:    4000,
+			MilliUsedCPU:     3000,
+			MilliTotalMemory: 16000,
+			MilliUsedMemory:  4000,
+		},
+	}
+
+	t.Run("scales nodegroups with policies", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		mockStore := newMockNodeGroupAutoscalingStore()
+		mockProvisioner := &mockNodeGroupAutoscalingProvisioner{Resources: resources}
+
+		autoscalingSupervisor := supervisor.NewNodeGroupAutoscalingSupervisor(mockStore, mockProvisioner, "instanceID", logger)
+		require.NoError(t, autoscalingSupervisor.Do())
+
+		assert.Equal(t, []nodeGroupScaling{{model.NodeGroupWorker, 2, 3, 10}}, mockProvisioner.Scaled)
+		assert.Equal(t, 1, mockStore.UpdateClusterCalls)
+
+		status := mockStore.Cluster.ProvisionerMetadataEKS.NodeGroups[model.NodeGroupWorker].AutoscalingStatus
+		require.NotNil(t, status)
+		assert.Equal(t, int64(3), status.DesiredCount)
+		assert.Equal(t, 75, status.CPUUtilization)
+		assert.Nil(t, mockStore.Cluster.ProvisionerMetadataEKS.NodeGroups["calls"].AutoscalingStatus)
+
+		require.NoError(t, autoscalingSupervisor.Do())
+		assert.Equal(t, 1, mockStore.UpdateClusterCalls)
+
+		mockProvisioner.Resources = map[string]*k8s.ClusterResources{model.NodeGroupWorker: {
+			WorkerNodeCount:  3,
+			MilliTotalCPU:    6000,
+			MilliUsedCPU:     3500,
+			MilliTotalMemory: 24000,
+			MilliUsedMemory:  4000,
+		}}
+		require.NoError(t, autoscalingSupervisor.Do())
+		assert.Equal(t, 2, mockStore.UpdateClusterCalls)
+		status = mockStore.Cluster.ProvisionerMetadataEKS.NodeGroups[model.NodeGroupWorker].AutoscalingStatus
+		assert.Equal(t, int64(4), status.DesiredCount)
+	})
+
+	t.Run("only records recommendations for locked clusters", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		mockStore := newMockNodeGroupAutoscalingStore()
+		mockStore.Cluster.APISecurityLock = true
+		mockProvisioner := &mockNodeGroupAutoscalingProvisioner{Resources: resources}
+
+		autoscalingSupervisor := supervisor.NewNodeGroupAutoscalingSupervisor(mockStore, mockProvisioner, "instanceID", logger)
+		require.NoError(t, autoscalingSupervisor.Do())
+
+		assert.Empty(t, mockProvisioner.Scaled)
+		status := mockStore.Cluster.ProvisionerMetadataEKS.NodeGroups[model.NodeGroupWorker].AutoscalingStatus
+		require.NotNil(t, status)
+		assert.Equal(t, int64(3), status.DesiredCount)
+	})
+
+	t.Run("keeps previous status when resources are unavailable", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		mockStore := newMockNodeGroupAutoscalingStore()
+		mockProvisioner := &mockNodeGroupAutoscalingProvisioner{}
+
+		autoscalingSupervisor := supervisor.NewNodeGroupAutoscalingSupervisor(mockStore, mockProvisioner, "instanceID", logger)
+		require.NoError(t, autoscalingSupervisor.Do())
+
+		assert.Empty(t, mockProvisioner.Scaled)
+		assert.Nil(t, mockStore.Cluster.ProvisionerMetadataEKS.NodeGroups[model.NodeGroupWorker].AutoscalingStatus)
+		assert.Zero(t, mockStore.UpdateClusterCalls)
+	})
+
+	t.Run("skips clusters that are not stable", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		mockStore := newMockNodeGroupAutoscalingStore()
+		mockStore.Cluster.State = model.ClusterStateResizeRequested
+		mockProvisioner := &mockNodeGroupAutoscalingProvisioner{Resources: resources}
+
+		autoscalingSupervisor := supervisor.NewNodeGroupAutoscalingSupervisor(mockStore, mockProvisioner, "instanceID", logger)
+		require.NoError(t, autoscalingSupervisor.Do())
+
+		assert.Empty(t, mockProvisioner.Scaled)
+		assert.Zero(t, mockStore.UpdateClusterCalls)
+	})
+}
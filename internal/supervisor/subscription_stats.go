@@ -0,0 +1,55 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package supervisor
+
+import (
+	"github.com/mattermost/mattermost-cloud/model"
+	log "github.com/sirupsen/logrus"
+)
+
+// subscriptionStatsStore abstracts the database operations required by the supervisor.
+type subscriptionStatsStore interface {
+	GetSubscriptionsStats() ([]*model.SubscriptionStats, error)
+}
+
+// subscriptionStatsMetrics records subscription delivery backlog.
+type subscriptionStatsMetrics interface {
+	ObserveSubscriptionsStats(stats []*model.SubscriptionStats)
+}
+
+// SubscriptionStatsSupervisor periodically computes the event delivery
+// backlog of every subscription and exports it as metrics.
+type SubscriptionStatsSupervisor struct {
+	store   subscriptionStatsStore
+	metrics subscriptionStatsMetrics
+	logger  log.FieldLogger
+}
+
+// NewSubscriptionStatsSupervisor creates a new SubscriptionStatsSupervisor.
+func NewSubscriptionStatsSupervisor(store subscriptionStatsStore, metrics subscriptionStatsMetrics, logger log.FieldLogger) *SubscriptionStatsSupervisor {
+	return &SubscriptionStatsSupervisor{
+		store:   store,
+		metrics: metrics,
+		logger:  logger.WithField("supervisor", "subscription-stats"),
+	}
+}
+
+// Shutdown performs graceful shutdown tasks for the supervisor.
+func (s *SubscriptionStatsSupervisor) Shutdown() {
+	s.logger.Debug("Shutting down subscription stats supervisor")
+}
+
+// Do computes the delivery stats of all subscriptions and records them.
+func (s *SubscriptionStatsSupervisor) Do() error {
+	stats, err := s.store.GetSubscriptionsStats()
+	if err != nil {
+		s.logger.WithError(err).Warn("Failed to compute subscription stats")
+		return nil
+	}
+
+	s.metrics.ObserveSubscriptionsStats(stats)
+
+	return nil
+}
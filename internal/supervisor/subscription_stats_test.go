@@ -0,0 +1,64 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package supervisor_test
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-cloud/internal/supervisor"
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockSubscriptionStatsStore struct {
+	Stats []*model.SubscriptionStats
+	Err   error
+}
+
+func (m *mockSubscriptionStatsStore) GetSubscriptionsStats() ([]*model.SubscriptionStats, error) {
+	return m.Stats, m.Err
+}
+
+type mockSubscriptionStatsMetrics struct {
+	Stats    []*model.SubscriptionStats
+	Observed bool
+}
+
+func (m *mockSubscriptionStatsMetrics) ObserveSubscriptionsStats(stats []*model.SubscriptionStats) {
+	m.Stats = stats
+	m.Observed = true
+}
+
+func TestSubscriptionStatsSupervisor_Do(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+
+	t.Run("records stats", func(t *testing.T) {
+		mockStore := &mockSubscriptionStatsStore{
+			Stats: []*model.SubscriptionStats{
+				{SubscriptionID: "sub1", Backlog: 3, OldestUndeliveredEventAt: 100},
+				{SubscriptionID: "sub2"},
+			},
+		}
+		mockMetrics := &mockSubscriptionStatsMetrics{}
+
+		statsSupervisor := supervisor.NewSubscriptionStatsSupervisor(mockStore, mockMetrics, logger)
+		err := statsSupervisor.Do()
+		require.NoError(t, err)
+		assert.Equal(t, mockStore.Stats, mockMetrics.Stats)
+	})
+
+	t.Run("store error", func(t *testing.T) {
+		mockStore := &mockSubscriptionStatsStore{Err: errors.New("failure")}
+		mockMetrics := &mockSubscriptionStatsMetrics{}
+
+		statsSupervisor := supervisor.NewSubscriptionStatsSupervisor(mockStore, mockMetrics, logger)
+		err := statsSupervisor.Do()
+		require.NoError(t, err)
+		assert.False(t, mockMetrics.Observed)
+	})
+}
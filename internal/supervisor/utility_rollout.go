@@ -0,0 +1,370 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package supervisor
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/internal/tools/aws"
+	"github.com/mattermost/mattermost-cloud/internal/webhook"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// utilityRolloutStore abstracts the database operations required by the supervisor.
+type utilityRolloutStore interface {
+	GetUnlockedUtilityRolloutsPendingWork() ([]*model.UtilityRollout, error)
+	GetUtilityRollout(id string) (*model.UtilityRollout, error)
+	UpdateUtilityRollout(rollout *model.UtilityRollout) error
+	utilityRolloutLockStore
+
+	GetCluster(clusterID string) (*model.Cluster, error)
+	UpdateCluster(cluster *model.Cluster) error
+	clusterLockStore
+
+	GetWebhooks(filter *model.WebhookFilter) ([]*model.Webhook, error)
+}
+
+// utilityRolloutProvisioner checks the health of upgraded cluster utilities.
+type utilityRolloutProvisioner interface {
+	CheckClusterUtilityHealth(cluster *model.Cluster) ([]*model.ClusterUtilityHealth, error)
+}
+
+// errRolloutClusterBusy is returned when a rollout cluster cannot be
+// upgraded yet and should be retried on a later tick.
+var errRolloutClusterBusy = errors.New("cluster is busy")
+
+// errRolloutClusterInvalid is returned when a rollout cluster can no longer
+// be upgraded and should be skipped.
+var errRolloutClusterInvalid = errors.New("cluster cannot be upgraded by the rollout")
+
+// UtilityRolloutSupervisor finds pending utility rollouts and upgrades the
+// utility on their clusters a few clusters at a time.
+type UtilityRolloutSupervisor struct {
+	store          utilityRolloutStore
+	provisioner    utilityRolloutProvisioner
+	instanceID     string
+	environment    string
+	logger         log.FieldLogger
+	eventsProducer eventProducer
+}
+
+// NewUtilityRolloutSupervisor creates a new UtilityRolloutSupervisor.
+func NewUtilityRolloutSupervisor(
+	store utilityRolloutStore,
+	provisioner utilityRolloutProvisioner,
+	aws aws.AWS,
+	eventsProducer eventProducer,
+	instanceID string,
+	logger log.FieldLogger) *UtilityRolloutSupervisor {
+	return &UtilityRolloutSupervisor{
+		store:          store,
+		provisioner:    provisioner,
+		instanceID:     instanceID,
+		environment:    aws.GetCloudEnvironmentName(),
+		logger:         logger,
+		eventsProducer: eventsProducer,
+	}
+}
+
+// Shutdown performs graceful shutdown tasks for the supervisor.
+func (s *UtilityRolloutSupervisor) Shutdown() {
+	s.logger.Debug("Shutting down utility rollout supervisor")
+}
+
+// Do looks for work to be done on any pending rollouts and attempts to schedule the required work.
+func (s *UtilityRolloutSupervisor) Do() error {
+	rollouts, err := s.store.GetUnlockedUtilityRolloutsPendingWork()
+	if err != nil {
+		s.logger.WithError(err).Warn("Failed to query for pending work")
+		return nil
+	}
+
+	for _, rollout := range rollouts {
+		s.Supervise(rollout)
+	}
+
+	return nil
+}
+
+// Supervise schedules the required work on the given rollout.
+func (s *UtilityRolloutSupervisor) Supervise(rollout *model.UtilityRollout) {
+	logger := s.logger.WithFields(log.Fields{
+		"utilityRollout": rollout.ID,
+	})
+
+	lock := newUtilityRolloutLock(rollout.ID, s.instanceID, s.store, logger)
+	if !lock.TryLock() {
+		return
+	}
+	defer lock.Unlock()
+
+	// Before working on the rollout, it is crucial that we ensure that it
+	// was not updated to a new state by another provisioning server.
+	originalState := rollout.State
+	rollout, err := s.store.GetUtilityRollout(rollout.ID)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get refreshed rollout")
+		return
+	}
+	if rollout.State != originalState {
+		logger.WithField("oldRolloutState", originalState).
+			WithField("newRolloutState", rollout.State).
+			Warn("Another provisioner has worked on this rollout; skipping...")
+		return
+	}
+
+	logger.Debugf("Supervising rollout in state %s", rollout.State)
+
+	oldState := rollout.State
+	rollout.State = s.transitionRollout(rollout, logger)
+	if rollout.IsFinished() {
+		rollout.CompleteAt = model.GetMillis()
+	}
+
+	err = s.store.UpdateUtilityRollout(rollout)
+	if err != nil {
+		logger.WithError(err).Errorf("Failed to update rollout with state %s", rollout.State)
+		return
+	}
+
+	if rollout.State == oldState {
+		return
+	}
+
+	webhookPayload := &model.WebhookPayload{
+		Type:      model.TypeUtilityRollout,
+		ID:        rollout.ID,
+		NewState:  string(rollout.State),
+		OldState:  string(oldState),
+		Timestamp: time.Now().UnixNano(),
+		ExtraData: map[string]string{"Utility": rollout.Utility, "Version": rollout.Version, "Environment": s.environment},
+	}
+	err = webhook.SendToAllWebhooks(s.store, webhookPayload, logger.WithField("webhookEvent", webhookPayload.NewState))
+	if err != nil {
+		logger.WithError(err).Error("Unable to process and send webhooks")
+	}
+
+	logger.Debugf("Transitioned rollout from %s to %s", oldState, rollout.State)
+}
+
+// transitionRollout refreshes the clusters of the given rollout, starts new
+// cluster upgrades up to the concurrency limit and returns the resulting
+// state. Paused rollouts only track clusters that are already upgrading.
+func (s *UtilityRolloutSupervisor) transitionRollout(rollout *model.UtilityRollout, logger log.FieldLogger) model.UtilityRolloutState {
+	for _, rolloutCluster := range rollout.Clusters {
+		if rolloutCluster.State != model.UtilityRolloutClusterInProgress {
+			continue
+		}
+		s.refreshCluster(rollout, rolloutCluster, logger.WithField("cluster", rolloutCluster.ClusterID))
+	}
+
+	if rollout.Clusters.Count(model.UtilityRolloutClusterFailed) > 0 {
+		if rollout.State != model.UtilityRolloutStatePaused {
+			logger.Warn("A cluster failed to upgrade; pausing rollout")
+		}
+		return model.UtilityRolloutStatePaused
+	}
+
+	inProgress := rollout.Clusters.Count(model.UtilityRolloutClusterInProgress)
+	for _, rolloutCluster := range rollout.Clusters {
+		if inProgress >= rollout.MaxConcurrentClusters {
+			break
+		}
+		if rolloutCluster.State != model.UtilityRolloutClusterPending {
+			continue
+		}
+
+		clusterLogger := logger.WithField("cluster", rolloutCluster.ClusterID)
+		err := s.startCluster(rollout, rolloutCluster, clusterLogger)
+		if errors.Is(err, errRolloutClusterInvalid) {
+			clusterLogger.WithError(err).Warn("Skipping rollout cluster")
+			rolloutCluster.State = model.UtilityRolloutClusterSkipped
+			rolloutCluster.Message = err.Error()
+			continue
+		}
+		if errors.Is(err, errRolloutClusterBusy) {
+			clusterLogger.WithError(err).Debug("Rollout cluster is not ready to be upgraded")
+			continue
+		}
+		if err != nil {
+			clusterLogger.WithError(err).Error("Failed to start rollout cluster upgrade")
+			continue
+		}
+		inProgress++
+	}
+
+	for _, rolloutCluster := range rollout.Clusters {
+		if !rolloutCluster.IsFinished() {
+			return model.UtilityRolloutStateInProgress
+		}
+	}
+
+	return model.UtilityRolloutStateSucceeded
+}
+
+// refreshCluster checks whether the cluster finished provisioning, reports
+// the expected utility version and runs a healthy utility. Clusters with an
+// unhealthy utility are checked again until the cluster timeout expires.
+func (s *UtilityRolloutSupervisor) refreshCluster(rollout *model.UtilityRollout, rolloutCluster *model.UtilityRolloutCluster, logger log.FieldLogger) {
+	cluster, err := s.store.GetCluster(rolloutCluster.ClusterID)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get cluster")
+		return
+	}
+	if cluster == nil || cluster.State == model.ClusterStateDeleted {
+		rolloutCluster.State = model.UtilityRolloutClusterFailed
+		rolloutCluster.Message = "cluster not found"
+		return
+	}
+
+	switch cluster.State {
+	case model.ClusterStateStable:
+		actualVersion := cluster.ActualUtilityVersion(rollout.Utility)
+		if actualVersion != nil && actualVersion.Version() == rollout.Version {
+			var healthy bool
+			healthy, err = s.checkUtilityHealth(rollout, cluster, rolloutCluster)
+			if err != nil {
+				logger.WithError(err).Error("Failed to check utility health")
+				rolloutCluster.Message = "failed to check utility health"
+				break
+			}
+			if !healthy {
+				logger.Debugf("Cluster is running %s %s but the utility is not healthy yet", rollout.Utility, rollout.Version)
+				break
+			}
+			logger.Infof("Cluster is running a healthy %s %s", rollout.Utility, rollout.Version)
+			rolloutCluster.State = model.UtilityRolloutClusterSucceeded
+			rolloutCluster.Message = ""
+			return
+		}
+		logger.Warnf("Cluster finished provisioning without running %s %s", rollout.Utility, rollout.Version)
+		rolloutCluster.State = model.UtilityRolloutClusterFailed
+		rolloutCluster.Message = "cluster is not running the rollout version"
+		if actualVersion != nil {
+			rolloutCluster.Message = fmt.Sprintf("cluster is running version %s", actualVersion.Version())
+		}
+		return
+	case model.ClusterStateProvisioningFailed:
+		logger.Warn("Cluster failed to provision the new utility version")
+		rolloutCluster.State = model.UtilityRolloutClusterFailed
+		rolloutCluster.Message = string(cluster.State)
+		return
+	}
+
+	timeout := time.Duration(rollout.ClusterTimeoutSeconds) * time.Second
+	if model.GetMillis()-rolloutCluster.StartAt > timeout.Milliseconds() {
+		logger.Warnf("Cluster did not finish the upgrade within %s", timeout)
+		rolloutCluster.State = model.UtilityRolloutClusterFailed
+		if cluster.State == model.ClusterStateStable && rolloutCluster.Message != "" {
+			rolloutCluster.Message = fmt.Sprintf("timed out waiting for a healthy utility: %s", rolloutCluster.Message)
+			return
+		}
+		rolloutCluster.Message = fmt.Sprintf("timed out in state %s", cluster.State)
+	}
+}
+
+// checkUtilityHealth checks the workloads and Helm release of the rollout
+// utility on the cluster. The reason for an unhealthy utility is recorded on
+// the rollout cluster.
+func (s *UtilityRolloutSupervisor) checkUtilityHealth(rollout *model.UtilityRollout, cluster *model.Cluster, rolloutCluster *model.UtilityRolloutCluster) (bool, error) {
+	healthList, err := s.provisioner.CheckClusterUtilityHealth(cluster)
+	if err != nil {
+		return false, err
+	}
+
+	for _, health := range healthList {
+		if health.Utility != rollout.Utility {
+			continue
+		}
+		if health.Healthy {
+			return true, nil
+		}
+		rolloutCluster.Message = health.Message
+		if rolloutCluster.Message == "" {
+			rolloutCluster.Message = fmt.Sprintf("utility is unhealthy with helm status %s", health.HelmStatus)
+		}
+		return false, nil
+	}
+
+	rolloutCluster.Message = "no health was reported for the utility"
+	return false, nil
+}
+
+// startCluster sets the new desired utility version on the cluster and
+// requests provisioning.
+func (s *UtilityRolloutSupervisor) startCluster(rollout *model.UtilityRollout, rolloutCluster *model.UtilityRolloutCluster, logger log.FieldLogger) error {
+	lock := newClusterLock(rolloutCluster.ClusterID, s.instanceID, s.store, logger)
+	if !lock.TryLock() {
+		return errors.Wrap(errRolloutClusterBusy, "failed to lock cluster")
+	}
+	defer lock.Unlock()
+
+	cluster, err := s.store.GetCluster(rolloutCluster.ClusterID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get cluster")
+	}
+	if cluster == nil || cluster.State == model.ClusterStateDeleted || cluster.DeleteAt != 0 {
+		return errors.Wrap(errRolloutClusterInvalid, "cluster no longer exists")
+	}
+	if cluster.APISecurityLock {
+		return errors.Wrap(errRolloutClusterInvalid, "cluster API is locked")
+	}
+	if cluster.State != model.ClusterStateStable {
+		return errors.Wrapf(errRolloutClusterBusy, "cluster is in state %s", cluster.State)
+	}
+
+	registry, err := cluster.UtilityRegistry()
+	if err != nil {
+		return errors.Wrap(err, "failed to get cluster utility registry")
+	}
+	var registered bool
+	for _, definition := range registry {
+		if definition.Name == rollout.Utility {
+			registered = true
+			break
+		}
+	}
+	if !registered {
+		return errors.Wrapf(errRolloutClusterInvalid, "utility %s is not registered on the cluster", rollout.Utility)
+	}
+
+	valuesPath := rollout.ValuesPath
+	if valuesPath == "" {
+		if actualVersion := cluster.ActualUtilityVersion(rollout.Utility); actualVersion != nil {
+			valuesPath = actualVersion.Values()
+		}
+	}
+	if valuesPath == "" {
+		if defaultVersion, ok := model.DefaultUtilityVersions[rollout.Utility]; ok && defaultVersion != nil {
+			valuesPath = defaultVersion.Values()
+		}
+	}
+
+	cluster.SetUtilityDesiredVersions(map[string]*model.HelmUtilityVersion{
+		rollout.Utility: {Chart: rollout.Version, ValuesPath: valuesPath},
+	})
+	oldState := cluster.State
+	cluster.State = model.ClusterStateProvisioningRequested
+
+	err = s.store.UpdateCluster(cluster)
+	if err != nil {
+		return errors.Wrap(err, "failed to update cluster")
+	}
+
+	rolloutCluster.State = model.UtilityRolloutClusterInProgress
+	rolloutCluster.StartAt = model.GetMillis()
+	rolloutCluster.Message = ""
+	logger.Infof("Started upgrading %s to %s", rollout.Utility, rollout.Version)
+
+	err = s.eventsProducer.ProduceClusterStateChangeEvent(cluster, oldState)
+	if err != nil {
+		logger.WithError(err).Error("Failed to create cluster state change event")
+	}
+
+	return nil
+}
@@ -0,0 +1,47 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package supervisor
+
+import log "github.com/sirupsen/logrus"
+
+type utilityRolloutLockStore interface {
+	LockUtilityRollouts(ids []string, lockerID string) (bool, error)
+	UnlockUtilityRollouts(ids []string, lockerID string, force bool) (bool, error)
+}
+
+type utilityRolloutLock struct {
+	ids      []string
+	lockerID string
+	store    utilityRolloutLockStore
+	logger   log.FieldLogger
+}
+
+func newUtilityRolloutLock(id, lockerID string, store utilityRolloutLockStore, logger log.FieldLogger) *utilityRolloutLock {
+	return &utilityRolloutLock{
+		ids:      []string{id},
+		lockerID: lockerID,
+		store:    store,
+		logger:   logger,
+	}
+}
+
+func (l *utilityRolloutLock) TryLock() bool {
+	locked, err := l.store.LockUtilityRollouts(l.ids, l.lockerID)
+	if err != nil {
+		l.logger.WithError(err).Error("failed to lock utilityRollouts")
+		return false
+	}
+
+	return locked
+}
+
+func (l *utilityRolloutLock) Unlock() {
+	unlocked, err := l.store.UnlockUtilityRollouts(l.ids, l.lockerID, false)
+	if err != nil {
+		l.logger.WithError(err).Error("failed to unlock utilityRollouts")
+	} else if !unlocked {
+		l.logger.Error("failed to release lock for utilityRollouts")
+	}
+}
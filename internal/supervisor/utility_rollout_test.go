@@ -0,0 +1,269 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package supervisor_test
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-cloud/internal/supervisor"
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockUtilityRolloutStore struct {
+	Rollout  *model.UtilityRollout
+	Clusters map[string]*model.Cluster
+
+	UpdateRolloutCalls int
+	UpdateClusterCalls int
+}
+
+func (m *mockUtilityRolloutStore) GetUnlockedUtilityRolloutsPendingWork() ([]*model.UtilityRollout, error) {
+	if m.Rollout == nil {
+		return nil, nil
+	}
+	return []*model.UtilityRollout{m.Rollout}, nil
+}
+
+func (m *mockUtilityRolloutStore) GetUtilityRollout(id string) (*model.UtilityRollout, error) {
+	return m.Rollout, nil
+}
+
+func (m *mockUtilityRolloutStore) UpdateUtilityRollout(rollout *model.UtilityRollout) error {
+	m.UpdateRolloutCalls++
+	return nil
+}
+
+func (m *mockUtilityRolloutStore) LockUtilityRollouts(ids []string, lockerID string) (bool, error) {
+	return true, nil
+}
+
+func (m *mockUtilityRolloutStore) UnlockUtilityRollouts(ids []string, lockerID string, force bool) (bool, error) {
+	return true, nil
+}
+
+func (m *mockUtilityRolloutStore) GetCluster(clusterID string) (*model.Cluster, error) {
+	return m.Clusters[clusterID], nil
+}
+
+func (m *mockUtilityRolloutStore) UpdateCluster(cluster *model.Cluster) error {
+	m.UpdateClusterCalls++
+	return nil
+}
+
+func (m *mockUtilityRolloutStore) LockCluster(clusterID, lockerID string) (bool, error) {
+	return true, nil
+}
+
+func (m *mockUtilityRolloutStore) UnlockCluster(clusterID, lockerID string, force bool) (bool, error) {
+	return true, nil
+}
+
+func (m *mockUtilityRolloutStore) LockClusterScheduling(clusterID, lockerID string) (bool, error) {
+	return true, nil
+}
+
+func (m *mockUtilityRolloutStore) UnlockClusterScheduling(clusterID, lockerID string, force bool) (bool, error) {
+	return true, nil
+}
+
+func (m *mockUtilityRolloutStore) GetWebhooks(filter *model.WebhookFilter) ([]*model.Webhook, error) {
+	return nil, nil
+}
+
+func newMockUtilityRolloutStore(t *testing.T, clusterState string) *mockUtilityRolloutStore {
+	clusters := map[string]*model.Cluster{}
+	for _, id := range []string{"a", "b"} {
+		cluster := &model.Cluster{ID: id, State: clusterState}
+		err := cluster.SetUtilityActualVersion(model.NginxCanonicalName, &model.HelmUtilityVersion{Chart: "4.0.0", ValuesPath: "nginx_values.yaml"})
+		require.NoError(t, err)
+		clusters[id] = cluster
+	}
+
+	return &mockUtilityRolloutStore{
+		Clusters: clusters,
+		Rollout: &model.UtilityRollout{
+			ID:                    model.NewID(),
+			Utility:               model.NginxCanonicalName,
+			Version:               "4.1.0",
+			State:                 model.UtilityRolloutStateRequested,
+			MaxConcurrentClusters: 1,
+			ClusterTimeoutSeconds: model.DefaultUtilityRolloutClusterTimeoutSeconds,
+			Clusters: model.UtilityRolloutClusters{
+				{ClusterID: "a", State: model.UtilityRolloutClusterPending},
+				{ClusterID: "b", State: model.UtilityRolloutClusterPending},
+			},
+		},
+	}
+}
+
+func newMockUtilityRolloutProvisioner(healthy bool) *mockClusterUtilityHealthProvisioner {
+	health := &model.ClusterUtilityHealth{Utility: model.NginxCanonicalName, Healthy: healthy, HelmStatus: "deployed"}
+	if !healthy {
+		health.Message = "0/1 pods ready in deployment ingress-nginx-controller"
+	}
+	return &mockClusterUtilityHealthProvisioner{Health: []*model.ClusterUtilityHealth{health}}
+}
+
+func TestUtilityRolloutSupervisor_Do(t *testing.T) {
+	t.Run("no rollouts pending work", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		mockStore := &mockUtilityRolloutStore{}
+
+		rolloutSupervisor := supervisor.NewUtilityRolloutSupervisor(mockStore, newMockUtilityRolloutProvisioner(true), &mockAWS{}, &mockEventProducer{}, "instanceID", logger)
+		err := rolloutSupervisor.Do()
+		require.NoError(t, err)
+
+		require.Equal(t, 0, mockStore.UpdateRolloutCalls)
+	})
+}
+
+func TestUtilityRolloutSupervisor_Supervise(t *testing.T) {
+	t.Run("start clusters up to concurrency limit", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		mockStore := newMockUtilityRolloutStore(t, model.ClusterStateStable)
+
+		rolloutSupervisor := supervisor.NewUtilityRolloutSupervisor(mockStore, newMockUtilityRolloutProvisioner(true), &mockAWS{}, &mockEventProducer{}, "instanceID", logger)
+		rolloutSupervisor.Supervise(mockStore.Rollout)
+
+		rollout := mockStore.Rollout
+		assert.Equal(t, model.UtilityRolloutStateInProgress, rollout.State)
+		assert.Equal(t, 1, mockStore.UpdateRolloutCalls)
+		assert.Equal(t, 1, mockStore.UpdateClusterCalls)
+		assert.Equal(t, model.UtilityRolloutClusterInProgress, rollout.Clusters[0].State)
+		assert.NotZero(t, rollout.Clusters[0].StartAt)
+		assert.Equal(t, model.UtilityRolloutClusterPending, rollout.Clusters[1].State)
+
+		cluster := mockStore.Clusters["a"]
+		assert.Equal(t, model.ClusterStateProvisioningRequested, cluster.State)
+		assert.Equal(t, &model.HelmUtilityVersion{Chart: "4.1.0", ValuesPath: "nginx_values.yaml"}, cluster.DesiredUtilityVersion(model.NginxCanonicalName))
+	})
+
+	t.Run("busy clusters stay pending", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		mockStore := newMockUtilityRolloutStore(t, model.ClusterStateResizeRequested)
+
+		rolloutSupervisor := supervisor.NewUtilityRolloutSupervisor(mockStore, newMockUtilityRolloutProvisioner(true), &mockAWS{}, &mockEventProducer{}, "instanceID", logger)
+		rolloutSupervisor.Supervise(mockStore.Rollout)
+
+		rollout := mockStore.Rollout
+		assert.Equal(t, model.UtilityRolloutStateInProgress, rollout.State)
+		assert.Equal(t, 0, mockStore.UpdateClusterCalls)
+		assert.Equal(t, model.UtilityRolloutClusterPending, rollout.Clusters[0].State)
+		assert.Equal(t, model.UtilityRolloutClusterPending, rollout.Clusters[1].State)
+	})
+
+	t.Run("skip clusters without the utility", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		mockStore := newMockUtilityRolloutStore(t, model.ClusterStateStable)
+		mockStore.Rollout.Utility = "cert-manager"
+
+		rolloutSupervisor := supervisor.NewUtilityRolloutSupervisor(mockStore, newMockUtilityRolloutProvisioner(true), &mockAWS{}, &mockEventProducer{}, "instanceID", logger)
+		rolloutSupervisor.Supervise(mockStore.Rollout)
+
+		rollout := mockStore.Rollout
+		assert.Equal(t, model.UtilityRolloutClusterSkipped, rollout.Clusters[0].State)
+		assert.Equal(t, model.UtilityRolloutClusterSkipped, rollout.Clusters[1].State)
+		assert.Equal(t, model.UtilityRolloutStateSucceeded, rollout.State)
+		assert.NotZero(t, rollout.CompleteAt)
+	})
+
+	t.Run("finish clusters from cluster state", func(t *testing.T) {
+		for _, testCase := range []struct {
+			description   string
+			clusterState  string
+			actualVersion string
+			unhealthy     bool
+			startAt       int64
+			expectedState model.UtilityRolloutState
+		}{
+			{
+				description:   "when cluster runs the new version",
+				clusterState:  model.ClusterStateStable,
+				actualVersion: "4.1.0",
+				expectedState: model.UtilityRolloutStateSucceeded,
+			},
+			{
+				description:   "when the new version is not healthy yet",
+				clusterState:  model.ClusterStateStable,
+				actualVersion: "4.1.0",
+				unhealthy:     true,
+				expectedState: model.UtilityRolloutStateInProgress,
+			},
+			{
+				description:   "when the new version stays unhealthy",
+				clusterState:  model.ClusterStateStable,
+				actualVersion: "4.1.0",
+				unhealthy:     true,
+				startAt:       1,
+				expectedState: model.UtilityRolloutStatePaused,
+			},
+			{
+				description:   "when cluster runs another version",
+				clusterState:  model.ClusterStateStable,
+				actualVersion: "4.0.0",
+				expectedState: model.UtilityRolloutStatePaused,
+			},
+			{
+				description:   "when cluster failed to provision",
+				clusterState:  model.ClusterStateProvisioningFailed,
+				expectedState: model.UtilityRolloutStatePaused,
+			},
+			{
+				description:   "when cluster is provisioning",
+				clusterState:  model.ClusterStateProvisionInProgress,
+				expectedState: model.UtilityRolloutStateInProgress,
+			},
+			{
+				description:   "when cluster timed out",
+				clusterState:  model.ClusterStateProvisionInProgress,
+				startAt:       1,
+				expectedState: model.UtilityRolloutStatePaused,
+			},
+		} {
+			t.Run(testCase.description, func(t *testing.T) {
+				logger := testlib.MakeLogger(t)
+				mockStore := newMockUtilityRolloutStore(t, testCase.clusterState)
+				if testCase.actualVersion != "" {
+					err := mockStore.Clusters["a"].SetUtilityActualVersion(model.NginxCanonicalName, &model.HelmUtilityVersion{Chart: testCase.actualVersion})
+					require.NoError(t, err)
+				}
+				startAt := model.GetMillis()
+				if testCase.startAt != 0 {
+					startAt = testCase.startAt
+				}
+				mockStore.Rollout.State = model.UtilityRolloutStateInProgress
+				mockStore.Rollout.Clusters = mockStore.Rollout.Clusters[:1]
+				mockStore.Rollout.Clusters[0].State = model.UtilityRolloutClusterInProgress
+				mockStore.Rollout.Clusters[0].StartAt = startAt
+
+				rolloutSupervisor := supervisor.NewUtilityRolloutSupervisor(mockStore, newMockUtilityRolloutProvisioner(!testCase.unhealthy), &mockAWS{}, &mockEventProducer{}, "instanceID", logger)
+				rolloutSupervisor.Supervise(mockStore.Rollout)
+
+				assert.Equal(t, testCase.expectedState, mockStore.Rollout.State)
+				assert.Equal(t, 0, mockStore.UpdateClusterCalls)
+				if testCase.unhealthy {
+					assert.Contains(t, mockStore.Rollout.Clusters[0].Message, "0/1 pods ready")
+				}
+			})
+		}
+	})
+
+	t.Run("paused rollouts do not start clusters", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		mockStore := newMockUtilityRolloutStore(t, model.ClusterStateStable)
+		mockStore.Rollout.State = model.UtilityRolloutStatePaused
+		mockStore.Rollout.Clusters[0].State = model.UtilityRolloutClusterFailed
+
+		rolloutSupervisor := supervisor.NewUtilityRolloutSupervisor(mockStore, newMockUtilityRolloutProvisioner(true), &mockAWS{}, &mockEventProducer{}, "instanceID", logger)
+		rolloutSupervisor.Supervise(mockStore.Rollout)
+
+		assert.Equal(t, model.UtilityRolloutStatePaused, mockStore.Rollout.State)
+		assert.Equal(t, model.UtilityRolloutClusterPending, mockStore.Rollout.Clusters[1].State)
+		assert.Equal(t, 0, mockStore.UpdateClusterCalls)
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"context"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/acm"
@@ -56,13 +57,25 @@ type AWS interface {
 
 	S3EnsureBucketDeleted(bucketName string, logger log.FieldLogger) error
 	S3EnsureObjectDeleted(bucketName, path string) error
+	S3PutObject(bucketName, path, contentType string, body []byte) error
 	S3LargeCopy(srcBucketName, srcKey, destBucketName, destKey *string, logger log.FieldLogger) error
 	GetMultitenantBucketNameForInstallation(installationID string, store model.InstallationDatabaseStoreInterface) (string, error)
 	GetS3RegionURL() string
+	GetS3FilestoreLocation(installationID, filestoreType string, store model.InstallationDatabaseStoreInterface) (*S3FilestoreLocation, error)
+	S3CopyObjectsBatch(source, destination *S3FilestoreLocation, continuationToken string, logger log.FieldLogger) (*S3CopyBatchResult, error)
+	S3GetObjectsSummary(location *S3FilestoreLocation) (*S3ObjectsSummary, error)
+	S3CompareObjects(source, destination *S3FilestoreLocation) (*S3ObjectsComparison, error)
+	GetInstallationDatabaseInstanceClasses(installationID string) ([]string, error)
+	GetMultitenantDatabaseInstanceClasses(rdsClusterID string) ([]string, error)
+
+	RDSRestoreDBClusterToPointInTime(installationID, restorationID string, restoreTime time.Time, logger log.FieldLogger) error
+	RDSCheckPointInTimeRestore(installationID, restorationID string, logger log.FieldLogger) (bool, error)
+	RDSCleanupPointInTimeRestore(installationID, restorationID string, logger log.FieldLogger) error
 
 	GeneratePerseusUtilitySecret(clusterID string, logger log.FieldLogger) (*corev1.Secret, error)
 	GenerateBifrostUtilitySecret(clusterID string, logger log.FieldLogger) (*corev1.Secret, error)
 	GetCIDRByVPCTag(vpcTagName string, logger log.FieldLogger) (string, error)
+	GetCIDRByVPCID(vpcID string) (string, error)
 
 	FixSubnetTagsForVPC(vpc string, logger log.FieldLogger) error
 
@@ -76,6 +89,7 @@ type AWS interface {
 	EnsureEKSClusterUpdated(cluster *model.Cluster) (*eksTypes.Update, error)
 	EnsureEKSNodeGroup(cluster *model.Cluster, nodeGroupPrefix string) (*eksTypes.Nodegroup, error)
 	EnsureEKSNodeGroupMigrated(cluster *model.Cluster, nodeGroupPrefix string) error
+	EnsureEKSNodeGroupScaled(clusterName, nodeGroupName string, minCount, desiredCount, maxCount int64) error
 	GetActiveEKSCluster(clusterName string) (*eksTypes.Cluster, error)
 	GetActiveEKSNodeGroup(clusterName, nodeGroupName string) (*eksTypes.Nodegroup, error)
 	EnsureEKSNodeGroupDeleted(clusterName, nodeGroupName string) error
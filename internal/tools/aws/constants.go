@@ -247,6 +247,10 @@ const (
 	// tagging resources with an installation ID.
 	DefaultMattermostInstallationIDTagKey = "tag:InstallationId"
 
+	// DefaultRDSRestoredFromTagKey is the name used for tagging a DB cluster
+	// restored to a point in time with the DB cluster it was restored from.
+	DefaultRDSRestoredFromTagKey = "tag:RestoredFrom"
+
 	// DefaultMattermostDatabaseUsername is the default username used for
 	// connecting to a Mattermost database.
 	// Warning:
@@ -67,7 +67,12 @@ func (d *RDSDatabase) Teardown(store model.InstallationDatabaseStoreInterface, k
 	})
 	logger.Info("Tearing down RDS DB cluster")
 
-	err := d.client.secretsManagerEnsureRDSSecretDeleted(awsID, logger)
+	dbClusterID, err := d.client.rdsGetInstallationDBClusterID(awsID)
+	if err != nil {
+		return errors.Wrap(err, "unable to get RDS DB cluster ID")
+	}
+
+	err = d.client.secretsManagerEnsureRDSSecretDeleted(awsID, logger)
 	if err != nil {
 		return errors.Wrap(err, "unable to delete RDS secret")
 	}
@@ -77,7 +82,7 @@ func (d *RDSDatabase) Teardown(store model.InstallationDatabaseStoreInterface, k
 		return nil
 	}
 
-	err = d.client.rdsEnsureDBClusterDeleted(awsID, logger)
+	err = d.client.rdsEnsureDBClusterDeleted(dbClusterID, logger)
 	if err != nil {
 		return errors.Wrap(err, "unable to delete RDS DB cluster")
 	}
@@ -118,10 +123,15 @@ func (d *RDSDatabase) Snapshot(store model.InstallationDatabaseStoreInterface, l
 		"database-type":   d.databaseType,
 	})
 
-	_, err := d.client.Service().rds.CreateDBClusterSnapshot(
+	dbClusterID, err := d.client.rdsGetInstallationDBClusterID(awsID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get RDS DB cluster ID")
+	}
+
+	_, err = d.client.Service().rds.CreateDBClusterSnapshot(
 		context.TODO(),
 		&rds.CreateDBClusterSnapshotInput{
-			DBClusterIdentifier:         aws.String(awsID),
+			DBClusterIdentifier:         aws.String(dbClusterID),
 			DBClusterSnapshotIdentifier: aws.String(fmt.Sprintf("%s-snapshot-%v", awsID, time.Now().Nanosecond())),
 			Tags: []types.Tag{
 				{
@@ -157,7 +167,7 @@ func (d *RDSDatabase) GenerateDatabaseSecret(store model.InstallationDatabaseSto
 	dbClusters, err := d.client.Service().rds.DescribeDBClusters(
 		context.TODO(),
 		&rds.DescribeDBClustersInput{
-			DBClusterIdentifier: aws.String(awsID),
+			DBClusterIdentifier: aws.String(installationSecret.dbClusterIdentifier(awsID)),
 		})
 	if err != nil {
 		return nil, err
@@ -327,25 +337,34 @@ func (d *RDSDatabase) rdsDatabaseProvision(installationID string, logger log.Fie
 		return errors.Wrapf(err, "failed to convert database type to database engine")
 	}
 
-	tags, err := NewTags(ClusterIDTagKey, clusterID)
+	// The installation tag attributes the database resources, and so their
+	// cost, to the installation.
+	tags, err := NewTags(
+		ClusterIDTagKey, clusterID,
+		trimTagPrefix(DefaultMattermostInstallationIDTagKey), installationID,
+	)
 	if err != nil {
 		return errors.Wrap(err, "failed to generate AWS Tags")
 	}
 
-	err = d.client.rdsEnsureDBClusterCreated(awsID, *vpcs[0].VpcId, rdsSecret.MasterUsername, rdsSecret.MasterPassword, *keyMetadata.KeyId, d.databaseType, tags, logger)
+	// A point-in-time restoration replaces the DB cluster named after the
+	// installation.
+	dbClusterID := rdsSecret.dbClusterIdentifier(awsID)
+
+	err = d.client.rdsEnsureDBClusterCreated(dbClusterID, *vpcs[0].VpcId, rdsSecret.MasterUsername, rdsSecret.MasterPassword, *keyMetadata.KeyId, d.databaseType, tags, logger)
 	if err != nil {
 		return errors.Wrap(err, "failed to ensure DB cluster was created")
 	}
 
 	// Create primary
-	err = d.client.rdsEnsureDBClusterInstanceCreated(awsID, fmt.Sprintf("%s-master", awsID), dbEngine, dbConfig.PrimaryInstanceType, tags, logger)
+	err = d.client.rdsEnsureDBClusterInstanceCreated(dbClusterID, fmt.Sprintf("%s-master", dbClusterID), dbEngine, dbConfig.PrimaryInstanceType, tags, logger)
 	if err != nil {
 		return errors.Wrap(err, "failed to ensure DB primary instance was created")
 	}
 
 	// Create replicas
 	for i := 0; i < dbConfig.ReplicasCount; i++ {
-		err = d.client.rdsEnsureDBClusterInstanceCreated(awsID, fmt.Sprintf("%s-replica-%d", awsID, i), dbEngine, dbConfig.ReplicaInstanceType, tags, logger)
+		err = d.client.rdsEnsureDBClusterInstanceCreated(dbClusterID, fmt.Sprintf("%s-replica-%d", dbClusterID, i), dbEngine, dbConfig.ReplicaInstanceType, tags, logger)
 		if err != nil {
 			return errors.Wrap(err, "failed to ensure DB replica instance was created")
 		}
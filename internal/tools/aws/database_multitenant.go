@@ -470,7 +470,7 @@ func (d *RDSMultitenantDatabase) migrateInstallationToDB(store model.Installatio
 	return nil
 }
 
-// TODO: for now rollback will be supported only for multi-tenant postgres to multi-tenant postgres migration
+// TODO: for now rollback will be supported only for migrations between multi-tenant databases of the same type.
 // To support more DB types we will have to split this method to two.
 
 // RollbackMigration rollbacks Installation to the source database.
@@ -482,9 +482,8 @@ func (d *RDSMultitenantDatabase) RollbackMigration(store model.InstallationDatab
 		"database-type":            d.databaseType,
 	})
 
-	if dbMigration.SourceDatabase != model.InstallationDatabaseMultiTenantRDSPostgres ||
-		dbMigration.DestinationDatabase != model.InstallationDatabaseMultiTenantRDSPostgres {
-		return errors.New("db migration rollback is supported only for multitenant postgres database")
+	if !model.IsSupportedDBMigration(dbMigration.SourceDatabase, dbMigration.DestinationDatabase) {
+		return errors.New("db migration rollback is supported only for multitenant postgres and mysql databases")
 	}
 
 	unlockDest, err := lockMultitenantDatabase(dbMigration.DestinationMultiTenant.DatabaseID, d.instanceID, store, logger)
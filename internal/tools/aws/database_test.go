@@ -22,6 +22,7 @@ import (
 	gt "github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi"
 	gtTypes "github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi/types"
 	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	smTypes "github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
 	"github.com/golang/mock/gomock"
 	"github.com/mattermost/mattermost-cloud/internal/testlib"
 	"github.com/mattermost/mattermost-cloud/model"
@@ -215,6 +216,10 @@ func (a *AWSTestSuite) TestSnapshot() {
 			Return(testlib.NewLoggerEntry()).
 			Times(1),
 
+		a.Mocks.API.SecretsManager.EXPECT().
+			GetSecretValue(gomock.Any(), gomock.Any()).
+			Return(nil, &smTypes.ResourceNotFoundException{}),
+
 		a.Mocks.API.RDS.EXPECT().CreateDBClusterSnapshot(gomock.Any(), gomock.Any()).
 			Return(&rds.CreateDBClusterSnapshotOutput{}, nil).
 			Do(func(ctx context.Context, input *rds.CreateDBClusterSnapshotInput, optFns ...func(*rds.Options)) {
@@ -246,6 +251,10 @@ func (a *AWSTestSuite) TestSnapshotError() {
 			Return(testlib.NewLoggerEntry()).
 			Times(1),
 
+		a.Mocks.API.SecretsManager.EXPECT().
+			GetSecretValue(gomock.Any(), gomock.Any()).
+			Return(nil, &smTypes.ResourceNotFoundException{}),
+
 		a.Mocks.API.RDS.EXPECT().
 			CreateDBClusterSnapshot(gomock.Any(), gomock.Any()).
 			Return(nil, errors.New("database is not stable")).
@@ -251,6 +251,10 @@ func (a *Client) createEKSNodeGroup(cluster *model.Cluster, ngPrefix string) (*e
 		},
 	}
 
+	if ngChangeRequest.CapacityType != "" {
+		nodeGroupReq.CapacityType = eksTypes.CapacityTypes(ngChangeRequest.CapacityType)
+	}
+
 	if ngPrefix != model.NodeGroupWorker {
 		nodeGroupReq.Taints = []eksTypes.Taint{
 			{
@@ -362,6 +366,41 @@ func (c *Client) EnsureEKSNodeGroupMigrated(cluster *model.Cluster, ngPrefix str
 	return nil
 }
 
+// EnsureEKSNodeGroupScaled updates the scaling configuration of an existing
+// EKS NodeGroup without recreating it.
+func (c *Client) EnsureEKSNodeGroupScaled(clusterName, nodeGroupName string, minCount, desiredCount, maxCount int64) error {
+	nodeGroup, err := c.getEKSNodeGroup(clusterName, nodeGroupName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get EKS NodeGroup %s", nodeGroupName)
+	}
+	if nodeGroup == nil {
+		return errors.Errorf("EKS NodeGroup %s not found", nodeGroupName)
+	}
+
+	scalingConfig := nodeGroup.ScalingConfig
+	if scalingConfig != nil &&
+		ptr.ToInt32(scalingConfig.MinSize) == int32(minCount) &&
+		ptr.ToInt32(scalingConfig.DesiredSize) == int32(desiredCount) &&
+		ptr.ToInt32(scalingConfig.MaxSize) == int32(maxCount) {
+		return nil
+	}
+
+	_, err = c.Service().eks.UpdateNodegroupConfig(context.TODO(), &eks.UpdateNodegroupConfigInput{
+		ClusterName:   aws.String(clusterName),
+		NodegroupName: aws.String(nodeGroupName),
+		ScalingConfig: &eksTypes.NodegroupScalingConfig{
+			MinSize:     ptr.Int32(int32(minCount)),
+			DesiredSize: ptr.Int32(int32(desiredCount)),
+			MaxSize:     ptr.Int32(int32(maxCount)),
+		},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to update EKS NodeGroup %s scaling config", nodeGroupName)
+	}
+
+	return nil
+}
+
 // EnsureEKSClusterDeleted ensures EKS cluster is deleted.
 func (a *Client) EnsureEKSClusterDeleted(clusterName string) error {
 	ctx := context.TODO()
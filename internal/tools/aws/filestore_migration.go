@@ -0,0 +1,294 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package aws
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// s3CopyBatchSize is the number of objects copied in a single batch.
+	s3CopyBatchSize = 1000
+	// s3MaxCopyObjectSize is the largest object that can be copied with a
+	// single CopyObject request. Larger objects require a multipart copy.
+	s3MaxCopyObjectSize = 5 * 1024 * 1024 * 1024
+	// s3MaxReportedMismatches is the number of mismatched object keys kept
+	// when comparing filestores.
+	s3MaxReportedMismatches = 10
+)
+
+// S3FilestoreLocation is the bucket and key prefix under which the files of
+// an installation are stored.
+type S3FilestoreLocation struct {
+	Bucket string
+	Prefix string
+}
+
+// S3CopyBatchResult describes a batch of objects copied between filestores.
+type S3CopyBatchResult struct {
+	ObjectsCopied int64
+	BytesCopied   int64
+	// ContinuationToken is empty once all objects have been copied.
+	ContinuationToken string
+}
+
+// S3ObjectsSummary is the number and total size of objects in a filestore.
+type S3ObjectsSummary struct {
+	Objects int64
+	Bytes   int64
+}
+
+// S3ObjectsComparison is the result of comparing the objects stored in two
+// filestore locations.
+type S3ObjectsComparison struct {
+	// Matched is the number of objects with the same size and ETag in both
+	// locations.
+	Matched int64
+	// Mismatched is the number of objects missing from one of the locations
+	// or differing between them.
+	Mismatched int64
+	// MismatchedKeys holds the keys, relative to the location prefix, of the
+	// first mismatched objects.
+	MismatchedKeys []string
+}
+
+// GetS3FilestoreLocation returns the location of the files of an installation
+// stored in a filestore of the given type.
+func (a *Client) GetS3FilestoreLocation(installationID, filestoreType string, store model.InstallationDatabaseStoreInterface) (*S3FilestoreLocation, error) {
+	switch filestoreType {
+	case model.InstallationFilestoreAwsS3:
+		return &S3FilestoreLocation{Bucket: CloudID(installationID)}, nil
+	case model.InstallationFilestoreMultiTenantAwsS3, model.InstallationFilestoreBifrost:
+		bucketName, err := a.GetMultitenantBucketNameForInstallation(installationID, store)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to find multitenant bucket")
+		}
+		return &S3FilestoreLocation{Bucket: bucketName, Prefix: installationID + "/"}, nil
+	}
+
+	return nil, errors.Errorf("filestore type %s is not backed by S3", filestoreType)
+}
+
+// S3CopyObjectsBatch copies the next batch of objects from the source to the
+// destination location, starting from the given continuation token. Objects
+// are copied server-side and keep their key relative to the location prefix.
+func (a *Client) S3CopyObjectsBatch(source, destination *S3FilestoreLocation, continuationToken string, logger log.FieldLogger) (*S3CopyBatchResult, error) {
+	ctx := context.TODO()
+
+	input := &s3.ListObjectsV2Input{
+		Bucket:  aws.String(source.Bucket),
+		MaxKeys: s3CopyBatchSize,
+	}
+	if source.Prefix != "" {
+		input.Prefix = aws.String(source.Prefix)
+	}
+	if continuationToken != "" {
+		input.ContinuationToken = aws.String(continuationToken)
+	}
+
+	page, err := a.Service().s3.ListObjectsV2(ctx, input)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list objects in bucket %s", source.Bucket)
+	}
+
+	result := &S3CopyBatchResult{}
+	for _, object := range page.Contents {
+		sourceKey := aws.ToString(object.Key)
+		destinationKey := destination.Prefix + strings.TrimPrefix(sourceKey, source.Prefix)
+
+		if object.Size > s3MaxCopyObjectSize {
+			err = a.S3LargeCopy(&source.Bucket, &sourceKey, &destination.Bucket, &destinationKey, logger)
+		} else {
+			_, err = a.Service().s3.CopyObject(ctx, &s3.CopyObjectInput{
+				Bucket:     aws.String(destination.Bucket),
+				Key:        aws.String(destinationKey),
+				CopySource: aws.String(s3CopySource(source.Bucket, sourceKey)),
+			})
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to copy object %s", sourceKey)
+		}
+
+		result.ObjectsCopied++
+		result.BytesCopied += object.Size
+	}
+
+	if page.IsTruncated {
+		result.ContinuationToken = aws.ToString(page.NextContinuationToken)
+	}
+
+	logger.WithFields(log.Fields{
+		"s3-source-bucket":      source.Bucket,
+		"s3-destination-bucket": destination.Bucket,
+		"objects-copied":        result.ObjectsCopied,
+	}).Debug("Copied batch of S3 objects")
+
+	return result, nil
+}
+
+// S3GetObjectsSummary returns the number and total size of objects stored in
+// the given location.
+func (a *Client) S3GetObjectsSummary(location *S3FilestoreLocation) (*S3ObjectsSummary, error) {
+	ctx := context.TODO()
+
+	input := &s3.ListObjectsV2Input{
+		Bucket:  aws.String(location.Bucket),
+		MaxKeys: 1000, // The maximum number of objects we can retrieve on a single request
+	}
+	if location.Prefix != "" {
+		input.Prefix = aws.String(location.Prefix)
+	}
+
+	summary := &S3ObjectsSummary{}
+	paginator := s3.NewListObjectsV2Paginator(a.Service().s3, input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to list objects in bucket %s", location.Bucket)
+		}
+
+		for _, object := range page.Contents {
+			summary.Objects++
+			summary.Bytes += object.Size
+		}
+	}
+
+	return summary, nil
+}
+
+// S3CompareObjects compares every object stored in the source location with
+// the object stored under the same relative key in the destination location.
+// Objects match when their sizes are equal and, unless either was uploaded
+// in multiple parts, their ETags are equal.
+func (a *Client) S3CompareObjects(source, destination *S3FilestoreLocation) (*S3ObjectsComparison, error) {
+	sourceObjects := newS3ObjectLister(a, source)
+	destinationObjects := newS3ObjectLister(a, destination)
+
+	sourceObject, err := sourceObjects.next()
+	if err != nil {
+		return nil, err
+	}
+	destinationObject, err := destinationObjects.next()
+	if err != nil {
+		return nil, err
+	}
+
+	comparison := &S3ObjectsComparison{}
+	mismatch := func(key string) {
+		comparison.Mismatched++
+		if len(comparison.MismatchedKeys) < s3MaxReportedMismatches {
+			comparison.MismatchedKeys = append(comparison.MismatchedKeys, key)
+		}
+	}
+
+	// Both listings are sorted by key, so they can be walked side by side.
+	for sourceObject != nil || destinationObject != nil {
+		switch {
+		case destinationObject == nil || (sourceObject != nil && sourceObject.key < destinationObject.key):
+			mismatch(sourceObject.key)
+			sourceObject, err = sourceObjects.next()
+		case sourceObject == nil || destinationObject.key < sourceObject.key:
+			mismatch(destinationObject.key)
+			destinationObject, err = destinationObjects.next()
+		default:
+			if sourceObject.matches(destinationObject) {
+				comparison.Matched++
+			} else {
+				mismatch(sourceObject.key)
+			}
+			sourceObject, err = sourceObjects.next()
+			if err == nil {
+				destinationObject, err = destinationObjects.next()
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return comparison, nil
+}
+
+// s3ListedObject is an object listed in a filestore location.
+type s3ListedObject struct {
+	// key is relative to the location prefix.
+	key  string
+	size int64
+	eTag string
+}
+
+// matches returns true if both objects appear to have the same content.
+// The ETag of objects uploaded in multiple parts is not the MD5 digest of
+// their content and changes when they are copied, so only sizes are compared
+// for them.
+func (o *s3ListedObject) matches(other *s3ListedObject) bool {
+	if o.size != other.size {
+		return false
+	}
+	if strings.Contains(o.eTag, "-") || strings.Contains(other.eTag, "-") {
+		return true
+	}
+
+	return o.eTag == other.eTag
+}
+
+// s3ObjectLister lists the objects of a filestore location one at a time.
+type s3ObjectLister struct {
+	location  *S3FilestoreLocation
+	paginator *s3.ListObjectsV2Paginator
+	page      []types.Object
+}
+
+func newS3ObjectLister(a *Client, location *S3FilestoreLocation) *s3ObjectLister {
+	input := &s3.ListObjectsV2Input{
+		Bucket:  aws.String(location.Bucket),
+		MaxKeys: s3CopyBatchSize,
+	}
+	if location.Prefix != "" {
+		input.Prefix = aws.String(location.Prefix)
+	}
+
+	return &s3ObjectLister{
+		location:  location,
+		paginator: s3.NewListObjectsV2Paginator(a.Service().s3, input),
+	}
+}
+
+// next returns the next object, or nil once all objects have been listed.
+func (l *s3ObjectLister) next() (*s3ListedObject, error) {
+	for len(l.page) == 0 {
+		if !l.paginator.HasMorePages() {
+			return nil, nil
+		}
+		page, err := l.paginator.NextPage(context.TODO())
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to list objects in bucket %s", l.location.Bucket)
+		}
+		l.page = page.Contents
+	}
+
+	object := l.page[0]
+	l.page = l.page[1:]
+
+	return &s3ListedObject{
+		key:  strings.TrimPrefix(aws.ToString(object.Key), l.location.Prefix),
+		size: object.Size,
+		eTag: aws.ToString(object.ETag),
+	}, nil
+}
+
+// s3CopySource returns the URL-encoded copy source of an object.
+func s3CopySource(bucket, key string) string {
+	return (&url.URL{Path: bucket + "/" + key}).EscapedPath()
+}
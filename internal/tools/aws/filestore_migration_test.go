@@ -0,0 +1,159 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package aws
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/golang/mock/gomock"
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/model"
+)
+
+func (a *AWSTestSuite) TestGetS3FilestoreLocationSingleTenant() {
+	location, err := a.Mocks.AWS.GetS3FilestoreLocation(a.InstallationA.ID, model.InstallationFilestoreAwsS3, nil)
+	a.Assert().NoError(err)
+	a.Assert().Equal(&S3FilestoreLocation{Bucket: CloudID(a.InstallationA.ID)}, location)
+
+	_, err = a.Mocks.AWS.GetS3FilestoreLocation(a.InstallationA.ID, model.InstallationFilestoreMinioOperator, nil)
+	a.Assert().Error(err)
+}
+
+func (a *AWSTestSuite) TestS3CopyObjectsBatch() {
+	source := &S3FilestoreLocation{Bucket: "source"}
+	destination := &S3FilestoreLocation{Bucket: "destination", Prefix: "installation/"}
+
+	gomock.InOrder(
+		a.Mocks.API.S3.EXPECT().
+			ListObjectsV2(gomock.Any(), gomock.Any()).
+			Do(func(ctx context.Context, input *s3.ListObjectsV2Input, optFns ...func(*s3.Options)) {
+				a.Assert().Equal("source", *input.Bucket)
+				a.Assert().Nil(input.Prefix)
+				a.Assert().Equal("token", *input.ContinuationToken)
+			}).
+			Return(&s3.ListObjectsV2Output{
+				Contents: []types.Object{
+					{Key: aws.String("data/file 1.txt"), Size: 10},
+					{Key: aws.String("data/file2.txt"), Size: 20},
+				},
+				IsTruncated:           true,
+				NextContinuationToken: aws.String("next"),
+			}, nil),
+		a.Mocks.API.S3.EXPECT().
+			CopyObject(gomock.Any(), gomock.Any()).
+			Do(func(ctx context.Context, input *s3.CopyObjectInput, optFns ...func(*s3.Options)) {
+				a.Assert().Equal("destination", *input.Bucket)
+				a.Assert().Equal("installation/data/file 1.txt", *input.Key)
+				a.Assert().Equal("source/data/file%201.txt", *input.CopySource)
+			}).
+			Return(&s3.CopyObjectOutput{}, nil),
+		a.Mocks.API.S3.EXPECT().
+			CopyObject(gomock.Any(), gomock.Any()).
+			Return(&s3.CopyObjectOutput{}, nil),
+	)
+
+	result, err := a.Mocks.AWS.S3CopyObjectsBatch(source, destination, "token", testlib.NewLoggerEntry())
+	a.Assert().NoError(err)
+	a.Assert().Equal(&S3CopyBatchResult{ObjectsCopied: 2, BytesCopied: 30, ContinuationToken: "next"}, result)
+}
+
+func (a *AWSTestSuite) TestS3CopyObjectsBatchError() {
+	source := &S3FilestoreLocation{Bucket: "source"}
+	destination := &S3FilestoreLocation{Bucket: "destination", Prefix: "installation/"}
+
+	gomock.InOrder(
+		a.Mocks.API.S3.EXPECT().
+			ListObjectsV2(gomock.Any(), gomock.Any()).
+			Return(&s3.ListObjectsV2Output{
+				Contents: []types.Object{{Key: aws.String("file"), Size: 10}},
+			}, nil),
+		a.Mocks.API.S3.EXPECT().
+			CopyObject(gomock.Any(), gomock.Any()).
+			Return(nil, errors.New("copy failed")),
+	)
+
+	result, err := a.Mocks.AWS.S3CopyObjectsBatch(source, destination, "", testlib.NewLoggerEntry())
+	a.Assert().Error(err)
+	a.Assert().Nil(result)
+}
+
+func (a *AWSTestSuite) TestS3GetObjectsSummary() {
+	gomock.InOrder(
+		a.Mocks.API.S3.EXPECT().
+			ListObjectsV2(gomock.Any(), gomock.Any()).
+			Do(func(ctx context.Context, input *s3.ListObjectsV2Input, optFns ...func(*s3.Options)) {
+				a.Assert().Equal("installation/", *input.Prefix)
+			}).
+			Return(&s3.ListObjectsV2Output{
+				Contents:              []types.Object{{Size: 10}, {Size: 20}},
+				IsTruncated:           true,
+				NextContinuationToken: aws.String("next"),
+			}, nil),
+		a.Mocks.API.S3.EXPECT().
+			ListObjectsV2(gomock.Any(), gomock.Any()).
+			Return(&s3.ListObjectsV2Output{
+				Contents: []types.Object{{Size: 5}},
+			}, nil),
+	)
+
+	summary, err := a.Mocks.AWS.S3GetObjectsSummary(&S3FilestoreLocation{Bucket: "bucket", Prefix: "installation/"})
+	a.Assert().NoError(err)
+	a.Assert().Equal(&S3ObjectsSummary{Objects: 3, Bytes: 35}, summary)
+}
+
+func (a *AWSTestSuite) TestS3CompareObjects() {
+	source := &S3FilestoreLocation{Bucket: "source"}
+	destination := &S3FilestoreLocation{Bucket: "destination", Prefix: "installation/"}
+
+	a.Mocks.API.S3.EXPECT().
+		ListObjectsV2(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, input *s3.ListObjectsV2Input, optFns ...func(*s3.Options)) (*s3.ListObjectsV2Output, error) {
+			if *input.Bucket == "source" {
+				a.Assert().Nil(input.Prefix)
+				return &s3.ListObjectsV2Output{
+					Contents: []types.Object{
+						{Key: aws.String("a"), Size: 10, ETag: aws.String(`"1"`)},
+						{Key: aws.String("b"), Size: 10, ETag: aws.String(`"2"`)},
+						{Key: aws.String("c"), Size: 10, ETag: aws.String(`"3"`)},
+						{Key: aws.String("d"), Size: 10, ETag: aws.String(`"4-2"`)},
+						{Key: aws.String("e"), Size: 10, ETag: aws.String(`"5"`)},
+					},
+				}, nil
+			}
+			a.Assert().Equal("installation/", *input.Prefix)
+			return &s3.ListObjectsV2Output{
+				Contents: []types.Object{
+					{Key: aws.String("installation/a"), Size: 10, ETag: aws.String(`"1"`)},
+					{Key: aws.String("installation/b"), Size: 10, ETag: aws.String(`"changed"`)},
+					{Key: aws.String("installation/d"), Size: 10, ETag: aws.String(`"copied"`)},
+					{Key: aws.String("installation/e"), Size: 5, ETag: aws.String(`"5"`)},
+					{Key: aws.String("installation/f"), Size: 10, ETag: aws.String(`"6"`)},
+				},
+			}, nil
+		}).
+		Times(2)
+
+	comparison, err := a.Mocks.AWS.S3CompareObjects(source, destination)
+	a.Assert().NoError(err)
+	a.Assert().Equal(&S3ObjectsComparison{
+		Matched:        2,
+		Mismatched:     4,
+		MismatchedKeys: []string{"b", "c", "e", "f"},
+	}, comparison)
+}
+
+func (a *AWSTestSuite) TestS3CompareObjectsError() {
+	a.Mocks.API.S3.EXPECT().
+		ListObjectsV2(gomock.Any(), gomock.Any()).
+		Return(nil, errors.New("list failed"))
+
+	comparison, err := a.Mocks.AWS.S3CompareObjects(&S3FilestoreLocation{Bucket: "source"}, &S3FilestoreLocation{Bucket: "destination"})
+	a.Assert().Error(err)
+	a.Assert().Nil(comparison)
+}
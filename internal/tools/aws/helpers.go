@@ -74,6 +74,21 @@ func RDSMigrationInstanceID(installationID string) string {
 	return fmt.Sprintf("%s-migration", CloudID(installationID))
 }
 
+// RDSPointInTimeRestoreClusterID formats the name of the RDS database cluster
+// created by a point-in-time restoration.
+func RDSPointInTimeRestoreClusterID(installationID, restorationID string) string {
+	return fmt.Sprintf("%s-pitr-%s", CloudID(installationID), shortRestorationID(restorationID))
+}
+
+// shortRestorationID keeps RDS identifiers derived from the restoration
+// within the 63 character limit.
+func shortRestorationID(restorationID string) string {
+	if len(restorationID) > 10 {
+		return restorationID[:10]
+	}
+	return restorationID
+}
+
 // RDSMultitenantSecretName formats the name of a secret used in a multitenant RDS database.
 func RDSMultitenantSecretName(id string) string {
 	return fmt.Sprintf("rds-multitenant-%s", id)
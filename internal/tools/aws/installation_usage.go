@@ -0,0 +1,85 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package aws
+
+import (
+	"context"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	gt "github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi"
+	gtTypes "github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi/types"
+	"github.com/pkg/errors"
+)
+
+// GetInstallationDatabaseInstanceClasses returns the instance classes of the
+// RDS instances dedicated to the given installation. Instances are attributed
+// to the installation by their InstallationId tag. Databases created before
+// they were tagged are found by the ID of the installation's DB cluster.
+// Installations using shared databases have no dedicated instances.
+func (a *Client) GetInstallationDatabaseInstanceClasses(installationID string) ([]string, error) {
+	resources, err := a.resourceTaggingGetAllResources(gt.GetResourcesInput{
+		ResourceTypeFilters: []string{"rds:db"},
+		TagFilters: []gtTypes.TagFilter{
+			{
+				Key:    aws.String(trimTagPrefix(DefaultMattermostInstallationIDTagKey)),
+				Values: []string{installationID},
+			},
+		},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get RDS instances tagged with the installation ID")
+	}
+
+	var classes []string
+	for _, resource := range resources {
+		output, err := a.Service().rds.DescribeDBInstances(
+			context.TODO(),
+			&rds.DescribeDBInstancesInput{
+				DBInstanceIdentifier: resource.ResourceARN,
+			})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to describe RDS instance %s", aws.ToString(resource.ResourceARN))
+		}
+		for _, instance := range output.DBInstances {
+			classes = append(classes, aws.ToString(instance.DBInstanceClass))
+		}
+	}
+
+	if len(classes) == 0 {
+		dbClusterID, err := a.rdsGetInstallationDBClusterID(CloudID(installationID))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get DB cluster ID")
+		}
+		instances, err := a.rdsDescribeDBClusterInstances(dbClusterID)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to describe DB cluster instances")
+		}
+		for _, instance := range instances {
+			classes = append(classes, aws.ToString(instance.DBInstanceClass))
+		}
+	}
+	sort.Strings(classes)
+
+	return classes, nil
+}
+
+// GetMultitenantDatabaseInstanceClasses returns the instance classes of the
+// RDS instances of the given multitenant DB cluster.
+func (a *Client) GetMultitenantDatabaseInstanceClasses(rdsClusterID string) ([]string, error) {
+	instances, err := a.rdsDescribeDBClusterInstances(rdsClusterID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to describe DB cluster instances")
+	}
+
+	var classes []string
+	for _, instance := range instances {
+		classes = append(classes, aws.ToString(instance.DBInstanceClass))
+	}
+	sort.Strings(classes)
+
+	return classes, nil
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package aws
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdsTypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	gt "github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi"
+	gtTypes "github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi/types"
+	smTypes "github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+	"github.com/golang/mock/gomock"
+)
+
+func (a *AWSTestSuite) TestGetInstallationDatabaseInstanceClassesTagged() {
+	gomock.InOrder(
+		a.Mocks.API.ResourceGroupsTagging.EXPECT().
+			GetResources(gomock.Any(), gomock.Any()).
+			Do(func(ctx context.Context, input *gt.GetResourcesInput, optFns ...func(*gt.Options)) {
+				a.Assert().Equal([]string{"rds:db"}, input.ResourceTypeFilters)
+				a.Assert().Equal("InstallationId", *input.TagFilters[0].Key)
+				a.Assert().Equal([]string{a.InstallationA.ID}, input.TagFilters[0].Values)
+			}).
+			Return(&gt.GetResourcesOutput{
+				ResourceTagMappingList: []gtTypes.ResourceTagMapping{
+					{ResourceARN: aws.String("arn:master")},
+					{ResourceARN: aws.String("arn:replica")},
+				},
+			}, nil),
+		a.Mocks.API.RDS.EXPECT().
+			DescribeDBInstances(gomock.Any(), &rds.DescribeDBInstancesInput{DBInstanceIdentifier: aws.String("arn:master")}).
+			Return(&rds.DescribeDBInstancesOutput{
+				DBInstances: []rdsTypes.DBInstance{{DBInstanceClass: aws.String("db.r5.large")}},
+			}, nil),
+		a.Mocks.API.RDS.EXPECT().
+			DescribeDBInstances(gomock.Any(), &rds.DescribeDBInstancesInput{DBInstanceIdentifier: aws.String("arn:replica")}).
+			Return(&rds.DescribeDBInstancesOutput{
+				DBInstances: []rdsTypes.DBInstance{{DBInstanceClass: aws.String("db.r5.2xlarge")}},
+			}, nil),
+	)
+
+	classes, err := a.Mocks.AWS.GetInstallationDatabaseInstanceClasses(a.InstallationA.ID)
+	a.Assert().NoError(err)
+	a.Assert().Equal([]string{"db.r5.2xlarge", "db.r5.large"}, classes)
+}
+
+func (a *AWSTestSuite) TestGetInstallationDatabaseInstanceClassesUntagged() {
+	gomock.InOrder(
+		a.Mocks.API.ResourceGroupsTagging.EXPECT().
+			GetResources(gomock.Any(), gomock.Any()).
+			Return(&gt.GetResourcesOutput{}, nil),
+		a.Mocks.API.SecretsManager.EXPECT().
+			GetSecretValue(gomock.Any(), gomock.Any()).
+			Return(nil, &smTypes.ResourceNotFoundException{}),
+		a.Mocks.API.RDS.EXPECT().
+			DescribeDBInstances(gomock.Any(), gomock.Any()).
+			Do(func(ctx context.Context, input *rds.DescribeDBInstancesInput, optFns ...func(*rds.Options)) {
+				a.Assert().Equal("db-cluster-id", *input.Filters[0].Name)
+				a.Assert().Equal([]string{CloudID(a.InstallationA.ID)}, input.Filters[0].Values)
+			}).
+			Return(&rds.DescribeDBInstancesOutput{
+				DBInstances: []rdsTypes.DBInstance{{DBInstanceClass: aws.String("db.t3.medium")}},
+			}, nil),
+	)
+
+	classes, err := a.Mocks.AWS.GetInstallationDatabaseInstanceClasses(a.InstallationA.ID)
+	a.Assert().NoError(err)
+	a.Assert().Equal([]string{"db.t3.medium"}, classes)
+}
+
+func (a *AWSTestSuite) TestGetMultitenantDatabaseInstanceClasses() {
+	a.Mocks.API.RDS.EXPECT().
+		DescribeDBInstances(gomock.Any(), gomock.Any()).
+		Do(func(ctx context.Context, input *rds.DescribeDBInstancesInput, optFns ...func(*rds.Options)) {
+			a.Assert().Equal("db-cluster-id", *input.Filters[0].Name)
+			a.Assert().Equal([]string{"rds-cluster-multitenant"}, input.Filters[0].Values)
+		}).
+		Return(&rds.DescribeDBInstancesOutput{
+			DBInstances: []rdsTypes.DBInstance{
+				{DBInstanceClass: aws.String("db.r5.xlarge")},
+				{DBInstanceClass: aws.String("db.r5.large")},
+			},
+		}, nil)
+
+	classes, err := a.Mocks.AWS.GetMultitenantDatabaseInstanceClasses("rds-cluster-multitenant")
+	a.Assert().NoError(err)
+	a.Assert().Equal([]string{"db.r5.large", "db.r5.xlarge"}, classes)
+}
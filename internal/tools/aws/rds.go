@@ -8,6 +8,7 @@ import (
 	"context"
 	"fmt"
 	"math/rand"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -241,18 +242,16 @@ func (a *Client) rdsEnsureDBClusterDeleted(awsID string, logger log.FieldLogger)
 	if len(result.DBClusters) != 1 {
 		return fmt.Errorf("expected 1 DB cluster, but got %d", len(result.DBClusters))
 	}
+	if aws.ToString(result.DBClusters[0].Status) == rdsStatusDeleting {
+		logger.WithField("db-cluster-name", awsID).Debug("DBCluster is already being deleted")
+		return nil
+	}
 
 	for _, instance := range result.DBClusters[0].DBClusterMembers {
-		_, err = a.Service().rds.DeleteDBInstance(
-			ctx,
-			&rds.DeleteDBInstanceInput{
-				DBInstanceIdentifier: instance.DBInstanceIdentifier,
-				SkipFinalSnapshot:    true,
-			})
+		err = a.rdsDeleteDBClusterInstance(aws.ToString(instance.DBInstanceIdentifier), logger)
 		if err != nil {
 			return errors.Wrap(err, "unable to delete DB cluster instance")
 		}
-		logger.WithField("db-instance-name", *instance.DBInstanceIdentifier).Debug("DB instance deleted")
 	}
 
 	_, err = a.Service().rds.DeleteDBCluster(
@@ -296,3 +295,329 @@ func (a *Client) DeletePGBouncerLogicalDatabase(multitenantDatabase *model.Multi
 
 	return nil
 }
+
+// ErrRDSRestoreTimeOutOfRange is returned when a DB cluster cannot be restored
+// to the requested time because it is outside of its backup retention window.
+var ErrRDSRestoreTimeOutOfRange = errors.New("restore time is outside of the restorable window of the DB cluster")
+
+const (
+	rdsStatusAvailable = "available"
+	rdsStatusDeleting  = "deleting"
+)
+
+// RDSRestoreDBClusterToPointInTime starts restoration of the installation
+// DB cluster to the given time. The data is restored to a new DB cluster
+// which replaces the original one once RDSCheckPointInTimeRestore completes.
+func (a *Client) RDSRestoreDBClusterToPointInTime(installationID, restorationID string, restoreTime time.Time, logger log.FieldLogger) error {
+	awsID := CloudID(installationID)
+	restoredID := RDSPointInTimeRestoreClusterID(installationID, restorationID)
+
+	sourceID, err := a.rdsGetInstallationDBClusterID(awsID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get DB cluster ID")
+	}
+
+	logger = logger.WithFields(log.Fields{
+		"db-cluster-name":          sourceID,
+		"restored-db-cluster-name": restoredID,
+	})
+
+	restored, err := a.rdsDescribeDBCluster(restoredID)
+	if err != nil {
+		return errors.Wrap(err, "failed to describe restored DB cluster")
+	}
+	if restored != nil {
+		logger.Debug("AWS DB cluster already restored")
+		return nil
+	}
+
+	source, err := a.rdsDescribeDBCluster(sourceID)
+	if err != nil {
+		return errors.Wrap(err, "failed to describe DB cluster")
+	}
+	if source == nil {
+		return errors.Errorf("DB cluster %s not found", sourceID)
+	}
+
+	if source.EarliestRestorableTime == nil || source.LatestRestorableTime == nil ||
+		restoreTime.Before(*source.EarliestRestorableTime) || restoreTime.After(*source.LatestRestorableTime) {
+		return errors.Wrapf(ErrRDSRestoreTimeOutOfRange, "unable to restore DB cluster %s to %s", sourceID, restoreTime.UTC().Format(time.RFC3339))
+	}
+
+	var securityGroupIDs []string
+	for _, sg := range source.VpcSecurityGroups {
+		securityGroupIDs = append(securityGroupIDs, aws.ToString(sg.VpcSecurityGroupId))
+	}
+
+	// The source DB cluster is recorded on the restored one so that it can
+	// be deleted once the restored DB cluster replaces it.
+	restoredFromTagKey := trimTagPrefix(DefaultRDSRestoredFromTagKey)
+	tags := []rdsTypes.Tag{{Key: aws.String(restoredFromTagKey), Value: aws.String(sourceID)}}
+	for _, tag := range source.TagList {
+		if aws.ToString(tag.Key) != restoredFromTagKey {
+			tags = append(tags, tag)
+		}
+	}
+
+	_, err = a.Service().rds.RestoreDBClusterToPointInTime(
+		context.TODO(),
+		&rds.RestoreDBClusterToPointInTimeInput{
+			DBClusterIdentifier:       aws.String(restoredID),
+			SourceDBClusterIdentifier: aws.String(sourceID),
+			RestoreToTime:             aws.Time(restoreTime),
+			DBSubnetGroupName:         source.DBSubnetGroup,
+			VpcSecurityGroupIds:       securityGroupIDs,
+			KmsKeyId:                  source.KmsKeyId,
+			Tags:                      tags,
+		})
+	if err != nil {
+		return errors.Wrap(err, "failed to restore DB cluster to point in time")
+	}
+
+	logger.Info("AWS DB cluster point-in-time restoration started")
+
+	return nil
+}
+
+// RDSCheckPointInTimeRestore progresses the point-in-time restoration of the
+// installation DB cluster. Once the restored DB cluster is available, its
+// instances are created to mirror the original ones, and the installation RDS
+// secret is updated so that the database secret of the installation points to
+// the endpoint of the restored DB cluster. Returns true when the restored DB
+// cluster is in use.
+func (a *Client) RDSCheckPointInTimeRestore(installationID, restorationID string, logger log.FieldLogger) (bool, error) {
+	awsID := CloudID(installationID)
+	restoredID := RDSPointInTimeRestoreClusterID(installationID, restorationID)
+
+	rdsSecret, err := a.secretsManagerGetRDSSecret(RDSSecretName(awsID))
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get RDS secret")
+	}
+	sourceID := rdsSecret.dbClusterIdentifier(awsID)
+
+	logger = logger.WithFields(log.Fields{
+		"db-cluster-name":          sourceID,
+		"restored-db-cluster-name": restoredID,
+	})
+
+	if sourceID == restoredID {
+		return true, nil
+	}
+
+	restored, err := a.rdsDescribeDBCluster(restoredID)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to describe restored DB cluster")
+	}
+	if restored == nil {
+		return false, errors.Errorf("restored DB cluster %s not found", restoredID)
+	}
+	if aws.ToString(restored.Status) != rdsStatusAvailable {
+		logger.Debugf("Waiting for restored DB cluster to become available, status is %s", aws.ToString(restored.Status))
+		return false, nil
+	}
+
+	source, err := a.rdsDescribeDBCluster(sourceID)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to describe DB cluster")
+	}
+	if source == nil {
+		return false, errors.Errorf("DB cluster %s not found", sourceID)
+	}
+
+	ready, err := a.rdsEnsureRestoredDBClusterInstancesCreated(source, restored, logger)
+	if err != nil || !ready {
+		return false, err
+	}
+
+	rdsSecret.DBClusterID = restoredID
+	err = a.secretsManagerUpdateRDSSecret(awsID, rdsSecret, logger)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to switch RDS secret to restored DB cluster")
+	}
+
+	logger.Info("Installation database switched to restored DB cluster")
+
+	return true, nil
+}
+
+// RDSCleanupPointInTimeRestore removes the DB cluster which is no longer used
+// after point-in-time restoration. When the restored DB cluster replaced the
+// original one, the original DB cluster is deleted with a final snapshot.
+// Otherwise the restoration did not complete and the restored DB cluster is
+// deleted.
+func (a *Client) RDSCleanupPointInTimeRestore(installationID, restorationID string, logger log.FieldLogger) error {
+	restoredID := RDSPointInTimeRestoreClusterID(installationID, restorationID)
+
+	activeID, err := a.rdsGetInstallationDBClusterID(CloudID(installationID))
+	if err != nil {
+		return errors.Wrap(err, "failed to get DB cluster ID")
+	}
+
+	if activeID != restoredID {
+		err = a.rdsEnsureDBClusterDeleted(restoredID, logger)
+		if err != nil {
+			return errors.Wrap(err, "failed to delete restored DB cluster")
+		}
+		return nil
+	}
+
+	restored, err := a.rdsDescribeDBCluster(restoredID)
+	if err != nil {
+		return errors.Wrap(err, "failed to describe restored DB cluster")
+	}
+	if restored == nil {
+		return errors.Errorf("restored DB cluster %s not found", restoredID)
+	}
+
+	var replacedID string
+	for _, tag := range restored.TagList {
+		if aws.ToString(tag.Key) == trimTagPrefix(DefaultRDSRestoredFromTagKey) {
+			replacedID = aws.ToString(tag.Value)
+		}
+	}
+	if replacedID == "" {
+		logger.Warn("Restored DB cluster does not record the DB cluster it replaced; skipping cleanup")
+		return nil
+	}
+
+	replaced, err := a.rdsDescribeDBCluster(replacedID)
+	if err != nil {
+		return errors.Wrap(err, "failed to describe replaced DB cluster")
+	}
+	if replaced == nil {
+		return nil
+	}
+	// A previous cleanup already deleted the replaced DB cluster.
+	if aws.ToString(replaced.Status) == rdsStatusDeleting {
+		logger.WithField("db-cluster-name", replacedID).Debug("Replaced DB cluster is already being deleted")
+		return nil
+	}
+
+	for _, instance := range replaced.DBClusterMembers {
+		err = a.rdsDeleteDBClusterInstance(aws.ToString(instance.DBInstanceIdentifier), logger)
+		if err != nil {
+			return errors.Wrap(err, "unable to delete replaced DB cluster instance")
+		}
+	}
+
+	_, err = a.Service().rds.DeleteDBCluster(
+		context.TODO(),
+		&rds.DeleteDBClusterInput{
+			DBClusterIdentifier:       aws.String(replacedID),
+			FinalDBSnapshotIdentifier: aws.String(fmt.Sprintf("%s-final-%s", replacedID, shortRestorationID(restorationID))),
+		})
+	if err != nil {
+		return errors.Wrap(err, "unable to delete replaced DB cluster")
+	}
+
+	logger.WithField("db-cluster-name", replacedID).Info("Replaced DB cluster deleted with final snapshot")
+
+	return nil
+}
+
+// rdsDeleteDBClusterInstance deletes the given DB cluster instance. Instances
+// which are already deleted or being deleted are skipped.
+func (a *Client) rdsDeleteDBClusterInstance(instanceID string, logger log.FieldLogger) error {
+	_, err := a.Service().rds.DeleteDBInstance(
+		context.TODO(),
+		&rds.DeleteDBInstanceInput{
+			DBInstanceIdentifier: aws.String(instanceID),
+			SkipFinalSnapshot:    true,
+		})
+	if err != nil {
+		var notFoundErr *rdsTypes.DBInstanceNotFoundFault
+		var stateErr *rdsTypes.InvalidDBInstanceStateFault
+		if errors.As(err, &notFoundErr) || errors.As(err, &stateErr) {
+			logger.WithField("db-instance-name", instanceID).Debug("DB instance is already being deleted")
+			return nil
+		}
+		return err
+	}
+	logger.WithField("db-instance-name", instanceID).Debug("DB instance deleted")
+
+	return nil
+}
+
+// rdsDescribeDBCluster returns the DB cluster with given ID or nil if it does not exist.
+func (a *Client) rdsDescribeDBCluster(clusterID string) (*rdsTypes.DBCluster, error) {
+	result, err := a.Service().rds.DescribeDBClusters(
+		context.TODO(),
+		&rds.DescribeDBClustersInput{
+			DBClusterIdentifier: aws.String(clusterID),
+		})
+	if err != nil {
+		var awsErr *rdsTypes.DBClusterNotFoundFault
+		if errors.As(err, &awsErr) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if len(result.DBClusters) != 1 {
+		return nil, fmt.Errorf("expected 1 DB cluster, but got %d", len(result.DBClusters))
+	}
+
+	return &result.DBClusters[0], nil
+}
+
+func (a *Client) rdsDescribeDBClusterInstances(clusterID string) ([]rdsTypes.DBInstance, error) {
+	result, err := a.Service().rds.DescribeDBInstances(
+		context.TODO(),
+		&rds.DescribeDBInstancesInput{
+			Filters: []rdsTypes.Filter{
+				{
+					Name:   aws.String("db-cluster-id"),
+					Values: []string{clusterID},
+				},
+			},
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	return result.DBInstances, nil
+}
+
+// rdsEnsureRestoredDBClusterInstancesCreated creates instances of the restored
+// DB cluster matching the instances of the source DB cluster and returns true
+// once all of them are available.
+func (a *Client) rdsEnsureRestoredDBClusterInstancesCreated(source, restored *rdsTypes.DBCluster, logger log.FieldLogger) (bool, error) {
+	sourceID := aws.ToString(source.DBClusterIdentifier)
+	restoredID := aws.ToString(restored.DBClusterIdentifier)
+
+	sourceInstances, err := a.rdsDescribeDBClusterInstances(sourceID)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to describe DB cluster instances")
+	}
+
+	tags, err := NewTags()
+	if err != nil {
+		return false, err
+	}
+	for _, tag := range restored.TagList {
+		tags.Add(aws.ToString(tag.Key), aws.ToString(tag.Value))
+	}
+
+	for _, instance := range sourceInstances {
+		instanceName := restoredID + strings.TrimPrefix(aws.ToString(instance.DBInstanceIdentifier), sourceID)
+		err = a.rdsEnsureDBClusterInstanceCreated(restoredID, instanceName, aws.ToString(instance.Engine), aws.ToString(instance.DBInstanceClass), tags, logger)
+		if err != nil {
+			return false, errors.Wrap(err, "failed to ensure restored DB instance was created")
+		}
+	}
+
+	restoredInstances, err := a.rdsDescribeDBClusterInstances(restoredID)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to describe restored DB cluster instances")
+	}
+	if len(restoredInstances) < len(sourceInstances) {
+		return false, nil
+	}
+	for _, instance := range restoredInstances {
+		if aws.ToString(instance.DBInstanceStatus) != rdsStatusAvailable {
+			logger.Debugf("Waiting for restored DB instance %s to become available", aws.ToString(instance.DBInstanceIdentifier))
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
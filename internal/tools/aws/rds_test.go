@@ -6,14 +6,18 @@ package aws
 
 import (
 	"context"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	"github.com/aws/aws-sdk-go-v2/service/rds"
 	rdsTypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	smTypes "github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
 	"github.com/golang/mock/gomock"
 	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/model"
 	"github.com/pkg/errors"
 )
 
@@ -226,3 +230,245 @@ func (a *AWSTestSuite) TestRDSEnsureDBClusterInstanceCreateError() {
 	a.Assert().Error(err)
 	a.Assert().Equal(err.Error(), "instance creation failure")
 }
+
+func (a *AWSTestSuite) TestRDSRestoreDBClusterToPointInTime() {
+	restorationID := model.NewID()
+	restoreTime := time.Now().Add(-time.Hour)
+	awsID := CloudID(a.InstallationA.ID)
+	restoredID := RDSPointInTimeRestoreClusterID(a.InstallationA.ID, restorationID)
+
+	a.Mocks.Log.Logger.EXPECT().WithFields(gomock.Any()).Return(testlib.NewLoggerEntry())
+
+	gomock.InOrder(
+		a.Mocks.API.SecretsManager.EXPECT().
+			GetSecretValue(gomock.Any(), gomock.Any()).
+			Return(nil, &smTypes.ResourceNotFoundException{}),
+		a.Mocks.API.RDS.EXPECT().
+			DescribeDBClusters(gomock.Any(), &rds.DescribeDBClustersInput{DBClusterIdentifier: aws.String(restoredID)}).
+			Return(nil, &rdsTypes.DBClusterNotFoundFault{}),
+		a.Mocks.API.RDS.EXPECT().
+			DescribeDBClusters(gomock.Any(), &rds.DescribeDBClustersInput{DBClusterIdentifier: aws.String(awsID)}).
+			Return(&rds.DescribeDBClustersOutput{DBClusters: []rdsTypes.DBCluster{{
+				DBClusterIdentifier:    aws.String(awsID),
+				DBSubnetGroup:          aws.String("subnet-group"),
+				KmsKeyId:               aws.String("kms-key"),
+				VpcSecurityGroups:      []rdsTypes.VpcSecurityGroupMembership{{VpcSecurityGroupId: aws.String("sg-1")}},
+				EarliestRestorableTime: aws.Time(restoreTime.Add(-24 * time.Hour)),
+				LatestRestorableTime:   aws.Time(restoreTime.Add(time.Minute)),
+				TagList:                []rdsTypes.Tag{{Key: aws.String("InstallationId"), Value: aws.String(a.InstallationA.ID)}},
+			}}}, nil),
+		a.Mocks.API.RDS.EXPECT().
+			RestoreDBClusterToPointInTime(gomock.Any(), gomock.Any()).
+			Return(&rds.RestoreDBClusterToPointInTimeOutput{}, nil).
+			Do(func(ctx context.Context, input *rds.RestoreDBClusterToPointInTimeInput, optFns ...func(*rds.Options)) {
+				a.Assert().Equal(restoredID, *input.DBClusterIdentifier)
+				a.Assert().Equal(awsID, *input.SourceDBClusterIdentifier)
+				a.Assert().Equal(restoreTime, *input.RestoreToTime)
+				a.Assert().Equal("subnet-group", *input.DBSubnetGroupName)
+				a.Assert().Equal([]string{"sg-1"}, input.VpcSecurityGroupIds)
+				a.Assert().Equal([]rdsTypes.Tag{
+					{Key: aws.String("RestoredFrom"), Value: aws.String(awsID)},
+					{Key: aws.String("InstallationId"), Value: aws.String(a.InstallationA.ID)},
+				}, input.Tags)
+			}),
+	)
+
+	err := a.Mocks.AWS.RDSRestoreDBClusterToPointInTime(a.InstallationA.ID, restorationID, restoreTime, a.Mocks.Log.Logger)
+	a.Assert().NoError(err)
+}
+
+func (a *AWSTestSuite) TestRDSRestoreDBClusterToPointInTimeOutOfRange() {
+	restorationID := model.NewID()
+	restoreTime := time.Now().Add(-30 * 24 * time.Hour)
+
+	a.Mocks.Log.Logger.EXPECT().WithFields(gomock.Any()).Return(testlib.NewLoggerEntry())
+
+	gomock.InOrder(
+		a.Mocks.API.SecretsManager.EXPECT().
+			GetSecretValue(gomock.Any(), gomock.Any()).
+			Return(nil, &smTypes.ResourceNotFoundException{}),
+		a.Mocks.API.RDS.EXPECT().
+			DescribeDBClusters(gomock.Any(), gomock.Any()).
+			Return(nil, &rdsTypes.DBClusterNotFoundFault{}),
+		a.Mocks.API.RDS.EXPECT().
+			DescribeDBClusters(gomock.Any(), gomock.Any()).
+			Return(&rds.DescribeDBClustersOutput{DBClusters: []rdsTypes.DBCluster{{
+				EarliestRestorableTime: aws.Time(time.Now().Add(-7 * 24 * time.Hour)),
+				LatestRestorableTime:   aws.Time(time.Now()),
+			}}}, nil),
+	)
+
+	err := a.Mocks.AWS.RDSRestoreDBClusterToPointInTime(a.InstallationA.ID, restorationID, restoreTime, a.Mocks.Log.Logger)
+	a.Assert().Error(err)
+	a.Assert().True(errors.Is(err, ErrRDSRestoreTimeOutOfRange))
+}
+
+func (a *AWSTestSuite) TestRDSCheckPointInTimeRestore() {
+	restorationID := model.NewID()
+	awsID := CloudID(a.InstallationA.ID)
+	restoredID := RDSPointInTimeRestoreClusterID(a.InstallationA.ID, restorationID)
+	password := model.NewRandomPassword(model.DefaultPasswordLength)
+
+	a.Mocks.Log.Logger.EXPECT().WithFields(gomock.Any()).Return(testlib.NewLoggerEntry())
+
+	gomock.InOrder(
+		a.Mocks.API.SecretsManager.EXPECT().
+			GetSecretValue(gomock.Any(), gomock.Any()).
+			Return(&secretsmanager.GetSecretValueOutput{
+				SecretString: aws.String(`{"MasterUsername":"mmcloud","MasterPassword":"` + password + `"}`),
+			}, nil),
+		a.Mocks.API.RDS.EXPECT().
+			DescribeDBClusters(gomock.Any(), &rds.DescribeDBClustersInput{DBClusterIdentifier: aws.String(restoredID)}).
+			Return(&rds.DescribeDBClustersOutput{DBClusters: []rdsTypes.DBCluster{{
+				DBClusterIdentifier: aws.String(restoredID),
+				Status:              aws.String(rdsStatusAvailable),
+			}}}, nil),
+		a.Mocks.API.RDS.EXPECT().
+			DescribeDBClusters(gomock.Any(), &rds.DescribeDBClustersInput{DBClusterIdentifier: aws.String(awsID)}).
+			Return(&rds.DescribeDBClustersOutput{DBClusters: []rdsTypes.DBCluster{{
+				DBClusterIdentifier: aws.String(awsID),
+				Status:              aws.String(rdsStatusAvailable),
+			}}}, nil),
+		a.Mocks.API.RDS.EXPECT().
+			DescribeDBInstances(gomock.Any(), gomock.Any()).
+			Return(&rds.DescribeDBInstancesOutput{}, nil),
+		a.Mocks.API.RDS.EXPECT().
+			DescribeDBInstances(gomock.Any(), gomock.Any()).
+			Return(&rds.DescribeDBInstancesOutput{}, nil),
+		a.Mocks.API.SecretsManager.EXPECT().
+			UpdateSecret(gomock.Any(), gomock.Any()).
+			Return(&secretsmanager.UpdateSecretOutput{}, nil).
+			Do(func(ctx context.Context, input *secretsmanager.UpdateSecretInput, optFns ...func(*secretsmanager.Options)) {
+				a.Assert().Equal(RDSSecretName(awsID), *input.SecretId)
+				a.Assert().JSONEq(`{"MasterUsername":"mmcloud","MasterPassword":"`+password+`","DBClusterID":"`+restoredID+`"}`, *input.SecretString)
+			}),
+	)
+
+	done, err := a.Mocks.AWS.RDSCheckPointInTimeRestore(a.InstallationA.ID, restorationID, a.Mocks.Log.Logger)
+	a.Assert().NoError(err)
+	a.Assert().True(done)
+}
+
+func (a *AWSTestSuite) TestRDSCleanupPointInTimeRestore() {
+	restorationID := model.NewID()
+	awsID := CloudID(a.InstallationA.ID)
+	restoredID := RDSPointInTimeRestoreClusterID(a.InstallationA.ID, restorationID)
+	password := model.NewRandomPassword(model.DefaultPasswordLength)
+
+	gomock.InOrder(
+		a.Mocks.API.SecretsManager.EXPECT().
+			GetSecretValue(gomock.Any(), gomock.Any()).
+			Return(&secretsmanager.GetSecretValueOutput{
+				SecretString: aws.String(`{"MasterUsername":"mmcloud","MasterPassword":"` + password + `","DBClusterID":"` + restoredID + `"}`),
+			}, nil),
+		a.Mocks.API.RDS.EXPECT().
+			DescribeDBClusters(gomock.Any(), &rds.DescribeDBClustersInput{DBClusterIdentifier: aws.String(restoredID)}).
+			Return(&rds.DescribeDBClustersOutput{DBClusters: []rdsTypes.DBCluster{{
+				DBClusterIdentifier: aws.String(restoredID),
+				TagList:             []rdsTypes.Tag{{Key: aws.String("RestoredFrom"), Value: aws.String(awsID)}},
+			}}}, nil),
+		a.Mocks.API.RDS.EXPECT().
+			DescribeDBClusters(gomock.Any(), &rds.DescribeDBClustersInput{DBClusterIdentifier: aws.String(awsID)}).
+			Return(&rds.DescribeDBClustersOutput{DBClusters: []rdsTypes.DBCluster{{
+				DBClusterIdentifier: aws.String(awsID),
+				DBClusterMembers:    []rdsTypes.DBClusterMember{{DBInstanceIdentifier: aws.String(awsID + "-master")}},
+			}}}, nil),
+		a.Mocks.API.RDS.EXPECT().
+			DeleteDBInstance(gomock.Any(), gomock.Any()).
+			Return(&rds.DeleteDBInstanceOutput{}, nil),
+		a.Mocks.API.RDS.EXPECT().
+			DeleteDBCluster(gomock.Any(), gomock.Any()).
+			Return(&rds.DeleteDBClusterOutput{}, nil).
+			Do(func(ctx context.Context, input *rds.DeleteDBClusterInput, optFns ...func(*rds.Options)) {
+				a.Assert().Equal(awsID, *input.DBClusterIdentifier)
+				a.Assert().NotNil(input.FinalDBSnapshotIdentifier)
+			}),
+	)
+
+	err := a.Mocks.AWS.RDSCleanupPointInTimeRestore(a.InstallationA.ID, restorationID, testlib.NewLoggerEntry())
+	a.Assert().NoError(err)
+}
+
+func (a *AWSTestSuite) TestRDSCleanupPointInTimeRestoreAlreadyDeleting() {
+	restorationID := model.NewID()
+	awsID := CloudID(a.InstallationA.ID)
+	restoredID := RDSPointInTimeRestoreClusterID(a.InstallationA.ID, restorationID)
+	password := model.NewRandomPassword(model.DefaultPasswordLength)
+
+	gomock.InOrder(
+		a.Mocks.API.SecretsManager.EXPECT().
+			GetSecretValue(gomock.Any(), gomock.Any()).
+			Return(&secretsmanager.GetSecretValueOutput{
+				SecretString: aws.String(`{"MasterUsername":"mmcloud","MasterPassword":"` + password + `","DBClusterID":"` + restoredID + `"}`),
+			}, nil),
+		a.Mocks.API.RDS.EXPECT().
+			DescribeDBClusters(gomock.Any(), &rds.DescribeDBClustersInput{DBClusterIdentifier: aws.String(restoredID)}).
+			Return(&rds.DescribeDBClustersOutput{DBClusters: []rdsTypes.DBCluster{{
+				DBClusterIdentifier: aws.String(restoredID),
+				TagList:             []rdsTypes.Tag{{Key: aws.String("RestoredFrom"), Value: aws.String(awsID)}},
+			}}}, nil),
+		a.Mocks.API.RDS.EXPECT().
+			DescribeDBClusters(gomock.Any(), &rds.DescribeDBClustersInput{DBClusterIdentifier: aws.String(awsID)}).
+			Return(&rds.DescribeDBClustersOutput{DBClusters: []rdsTypes.DBCluster{{
+				DBClusterIdentifier: aws.String(awsID),
+				Status:              aws.String("deleting"),
+				DBClusterMembers:    []rdsTypes.DBClusterMember{{DBInstanceIdentifier: aws.String(awsID + "-master")}},
+			}}}, nil),
+	)
+	a.Mocks.API.RDS.EXPECT().DeleteDBInstance(gomock.Any(), gomock.Any()).Times(0)
+	a.Mocks.API.RDS.EXPECT().DeleteDBCluster(gomock.Any(), gomock.Any()).Times(0)
+
+	err := a.Mocks.AWS.RDSCleanupPointInTimeRestore(a.InstallationA.ID, restorationID, testlib.NewLoggerEntry())
+	a.Assert().NoError(err)
+}
+
+func (a *AWSTestSuite) TestRDSCleanupPointInTimeRestoreInstanceAlreadyDeleting() {
+	restorationID := model.NewID()
+	restoredID := RDSPointInTimeRestoreClusterID(a.InstallationA.ID, restorationID)
+
+	gomock.InOrder(
+		a.Mocks.API.SecretsManager.EXPECT().
+			GetSecretValue(gomock.Any(), gomock.Any()).
+			Return(nil, &smTypes.ResourceNotFoundException{}),
+		a.Mocks.API.RDS.EXPECT().
+			DescribeDBClusters(gomock.Any(), &rds.DescribeDBClustersInput{DBClusterIdentifier: aws.String(restoredID)}).
+			Return(&rds.DescribeDBClustersOutput{DBClusters: []rdsTypes.DBCluster{{
+				DBClusterIdentifier: aws.String(restoredID),
+				DBClusterMembers:    []rdsTypes.DBClusterMember{{DBInstanceIdentifier: aws.String(restoredID + "-master")}},
+			}}}, nil),
+		a.Mocks.API.RDS.EXPECT().
+			DeleteDBInstance(gomock.Any(), gomock.Any()).
+			Return(nil, &rdsTypes.InvalidDBInstanceStateFault{}),
+		a.Mocks.API.RDS.EXPECT().
+			DeleteDBCluster(gomock.Any(), gomock.Any()).
+			Return(&rds.DeleteDBClusterOutput{}, nil),
+	)
+
+	err := a.Mocks.AWS.RDSCleanupPointInTimeRestore(a.InstallationA.ID, restorationID, testlib.NewLoggerEntry())
+	a.Assert().NoError(err)
+}
+
+func (a *AWSTestSuite) TestRDSCleanupPointInTimeRestoreNotCompleted() {
+	restorationID := model.NewID()
+	restoredID := RDSPointInTimeRestoreClusterID(a.InstallationA.ID, restorationID)
+
+	gomock.InOrder(
+		a.Mocks.API.SecretsManager.EXPECT().
+			GetSecretValue(gomock.Any(), gomock.Any()).
+			Return(nil, &smTypes.ResourceNotFoundException{}),
+		a.Mocks.API.RDS.EXPECT().
+			DescribeDBClusters(gomock.Any(), &rds.DescribeDBClustersInput{DBClusterIdentifier: aws.String(restoredID)}).
+			Return(&rds.DescribeDBClustersOutput{DBClusters: []rdsTypes.DBCluster{{
+				DBClusterIdentifier: aws.String(restoredID),
+			}}}, nil),
+		a.Mocks.API.RDS.EXPECT().
+			DeleteDBCluster(gomock.Any(), gomock.Any()).
+			Return(&rds.DeleteDBClusterOutput{}, nil).
+			Do(func(ctx context.Context, input *rds.DeleteDBClusterInput, optFns ...func(*rds.Options)) {
+				a.Assert().Equal(restoredID, *input.DBClusterIdentifier)
+			}),
+	)
+
+	err := a.Mocks.AWS.RDSCleanupPointInTimeRestore(a.InstallationA.ID, restorationID, testlib.NewLoggerEntry())
+	a.Assert().NoError(err)
+}
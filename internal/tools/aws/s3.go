@@ -5,6 +5,7 @@
 package aws
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"math"
@@ -390,6 +391,23 @@ func (a *Client) S3LargeCopy(srcBucketName, srcBucketKey, destBucketName, destBu
 }
 
 // S3EnsureObjectDeleted is used to ensure that the file is deleted.
+// S3PutObject uploads the object with given content to the bucket.
+func (a *Client) S3PutObject(bucketName, path, contentType string, body []byte) error {
+	_, err := a.Service().s3.PutObject(
+		context.TODO(),
+		&s3.PutObjectInput{
+			Bucket:      aws.String(bucketName),
+			Key:         aws.String(path),
+			ContentType: aws.String(contentType),
+			Body:        bytes.NewReader(body),
+		})
+	if err != nil {
+		return errors.Wrap(err, "failed to put object")
+	}
+
+	return nil
+}
+
 func (a *Client) S3EnsureObjectDeleted(bucketName, path string) error {
 	_, err := a.Service().s3.DeleteObject(
 		context.TODO(),
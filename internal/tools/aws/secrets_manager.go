@@ -40,6 +40,19 @@ func (s *IAMAccessKey) Validate() error {
 type RDSSecret struct {
 	MasterUsername string
 	MasterPassword string
+	// DBClusterID is the identifier of the DB cluster holding the data of
+	// the installation once it was restored to a point in time. It is empty
+	// while the data is held by the DB cluster named after the installation.
+	DBClusterID string `json:",omitempty"`
+}
+
+// dbClusterIdentifier returns the identifier of the DB cluster holding the
+// data of the installation with the given AWS ID.
+func (s *RDSSecret) dbClusterIdentifier(awsID string) string {
+	if s.DBClusterID != "" {
+		return s.DBClusterID
+	}
+	return awsID
 }
 
 // Validate performs a basic sanity check on the RDS secret.
@@ -264,6 +277,44 @@ func (a *Client) secretsManagerGetRDSSecret(secretName string) (*RDSSecret, erro
 	return rdsSecret, nil
 }
 
+func (a *Client) secretsManagerUpdateRDSSecret(awsID string, rdsSecret *RDSSecret, logger log.FieldLogger) error {
+	secretName := RDSSecretName(awsID)
+
+	b, err := json.Marshal(rdsSecret)
+	if err != nil {
+		return errors.Wrap(err, "unable to marshal secrets manager payload")
+	}
+
+	_, err = a.Service().secretsManager.UpdateSecret(
+		context.TODO(),
+		&secretsmanager.UpdateSecretInput{
+			SecretId:     aws.String(secretName),
+			SecretString: aws.String(string(b)),
+		})
+	if err != nil {
+		return errors.Wrap(err, "unable to update secrets manager secret")
+	}
+
+	logger.WithField("secret-name", secretName).Debug("AWS RDS secret updated")
+
+	return nil
+}
+
+// rdsGetInstallationDBClusterID returns the identifier of the DB cluster
+// holding the data of the installation with the given AWS ID.
+func (a *Client) rdsGetInstallationDBClusterID(awsID string) (string, error) {
+	rdsSecret, err := a.secretsManagerGetRDSSecret(RDSSecretName(awsID))
+	if err != nil {
+		var awsErr *types.ResourceNotFoundException
+		if errors.As(err, &awsErr) {
+			return awsID, nil
+		}
+		return "", errors.Wrap(err, "failed to get RDS secret")
+	}
+
+	return rdsSecret.dbClusterIdentifier(awsID), nil
+}
+
 func (a *Client) secretsManagerEnsureIAMAccessKeySecretDeleted(awsID string, logger log.FieldLogger) error {
 	return a.secretsManagerEnsureSecretDeleted(IAMSecretName(awsID), false, logger)
 }
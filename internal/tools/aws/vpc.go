@@ -34,6 +34,16 @@ func (a *Client) EnsureVPCExists(vpcID string) error {
 	return err
 }
 
+// GetCIDRByVPCID fetches the VPC CIDR block by VPC ID.
+func (a *Client) GetCIDRByVPCID(vpcID string) (string, error) {
+	vpc, err := a.GetVPC(vpcID)
+	if err != nil {
+		return "", err
+	}
+
+	return *vpc.CidrBlock, nil
+}
+
 // GetCIDRByVPCTag fetches VPC CIDR block by 'Name' tag.
 func (a *Client) GetCIDRByVPCTag(vpcTagName string, logger log.FieldLogger) (string, error) {
 	ctx := context.TODO()
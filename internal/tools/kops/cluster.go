@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/mattermost/mattermost-cloud/model"
 	"github.com/pkg/errors"
@@ -123,6 +124,33 @@ func (c *Cmd) RollingUpdateCluster(name string) error {
 	return nil
 }
 
+// RollingUpdateInstanceGroups invokes kops rolling-update cluster limited to
+// the given instance groups. Non-zero intervals set the time to wait after
+// replacing each node and after draining each node.
+func (c *Cmd) RollingUpdateInstanceGroups(name string, instanceGroups []string, nodeInterval, postDrainDelay time.Duration) error {
+	args := []string{
+		"rolling-update",
+		"cluster",
+		arg("name", name),
+		arg("state", "s3://", c.s3StateStore),
+		arg("instance-group", strings.Join(instanceGroups, ",")),
+		"--yes",
+	}
+	if nodeInterval > 0 {
+		args = append(args, arg("node-interval", nodeInterval.String()))
+	}
+	if postDrainDelay > 0 {
+		args = append(args, arg("post-drain-delay", postDrainDelay.String()))
+	}
+
+	_, _, err := c.run(args...)
+	if err != nil {
+		return errors.Wrap(err, "failed to invoke kops rolling-update cluster")
+	}
+
+	return nil
+}
+
 func (c *Cmd) rollingUpdateCluster(name string, dryRun bool) ([]byte, []byte, error) {
 	args := []string{
 		"rolling-update",
@@ -98,6 +98,27 @@ type DBClusterUtilizationSettings struct {
 	MaxInstallationsRDSMySQL             int
 }
 
+// MaxInstallations returns the maximum number of installations supported by a
+// multitenant database of the given type.
+func (s DBClusterUtilizationSettings) MaxInstallations(databaseType string) int {
+	var limit, defaultLimit int
+	switch databaseType {
+	case model.DatabaseEngineTypeMySQL:
+		limit, defaultLimit = s.MaxInstallationsRDSMySQL, aws.DefaultRDSMultitenantDatabaseMySQLCountLimit
+	case model.DatabaseEngineTypePostgresProxy:
+		limit, defaultLimit = s.MaxInstallationsRDSPostgresPGBouncer, aws.DefaultRDSMultitenantPGBouncerDatabasePostgresCountLimit
+	case model.DatabaseEngineTypePostgresProxyPerseus:
+		limit, defaultLimit = s.MaxInstallationsPerseus, aws.DefaultRDSMultitenantPerseusDatabasePostgresCountLimit
+	default:
+		limit, defaultLimit = s.MaxInstallationsRDSPostgres, aws.DefaultRDSMultitenantDatabasePostgresCountLimit
+	}
+	if limit > 0 {
+		return limit
+	}
+
+	return defaultLimit
+}
+
 // NewResourceUtil returns a new instance of ResourceUtil.
 func NewResourceUtil(
 	instanceID string,
@@ -116,17 +137,22 @@ func NewResourceUtil(
 
 // GetFilestore returns the Filestore interface that matches the installation.
 func (r *ResourceUtil) GetFilestore(installation *model.Installation) model.Filestore {
-	switch installation.Filestore {
+	return r.GetFilestoreByType(installation.ID, installation.Filestore)
+}
+
+// GetFilestoreByType returns the Filestore interface that matches the installationID and filestore type.
+func (r *ResourceUtil) GetFilestoreByType(installationID, filestoreType string) model.Filestore {
+	switch filestoreType {
 	case model.InstallationFilestoreMinioOperator:
 		return model.NewMinioOperatorFilestore()
 	case model.InstallationFilestoreLocalEphemeral:
 		return model.NewLocalEphemeralFilestore()
 	case model.InstallationFilestoreAwsS3:
-		return aws.NewS3Filestore(installation.ID, r.awsClient, r.enableS3Versioning)
+		return aws.NewS3Filestore(installationID, r.awsClient, r.enableS3Versioning)
 	case model.InstallationFilestoreMultiTenantAwsS3:
-		return aws.NewS3MultitenantFilestore(installation.ID, r.awsClient)
+		return aws.NewS3MultitenantFilestore(installationID, r.awsClient)
 	case model.InstallationFilestoreBifrost:
-		return aws.NewBifrostFilestore(installation.ID, r.awsClient)
+		return aws.NewBifrostFilestore(installationID, r.awsClient)
 	}
 
 	// Warning: we should never get here as it would mean that we didn't match
@@ -193,6 +219,12 @@ func (r *ResourceUtil) GetDatabase(installationID, dbType string) model.Database
 	return model.NewMysqlOperatorDatabase()
 }
 
+// GetMultitenantDatabaseMaxInstallations returns the maximum number of
+// installations supported by a multitenant database of the given type.
+func (r *ResourceUtil) GetMultitenantDatabaseMaxInstallations(databaseType string) int {
+	return r.dbClusterUtilizationSettings.MaxInstallations(databaseType)
+}
+
 // EnsureSecretManagerSecretDeleted ensures a secret with the provided name is
 // marked for deletion in AWS Secrets Manager.
 func (r *ResourceUtil) EnsureSecretManagerSecretDeleted(secretName string, logger log.FieldLogger) error {
@@ -17,6 +17,17 @@ const (
 	allowMMExternalBeta = "external-mm-v1beta-allow"
 )
 
+// CreateOrUpdateNetworkPolicyV1 creates or updates a NetworkPolicy.
+func (kc *KubeClient) CreateOrUpdateNetworkPolicyV1(namespace string, networkPolicy *networkingv1.NetworkPolicy) (metav1.Object, error) {
+	return kc.createOrUpdateNetworkPolicyV1(namespace, networkPolicy)
+}
+
+// DeleteNetworkPolicyV1 deletes a NetworkPolicy by name.
+func (kc *KubeClient) DeleteNetworkPolicyV1(namespace, name string) error {
+	ctx := context.TODO()
+	return kc.Clientset.NetworkingV1().NetworkPolicies(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+}
+
 func (kc *KubeClient) createOrUpdateNetworkPolicyV1(namespace string, networkPolicy *networkingv1.NetworkPolicy) (metav1.Object, error) {
 	ctx := context.TODO()
 	_, err := kc.Clientset.NetworkingV1().NetworkPolicies(namespace).Get(ctx, networkPolicy.GetName(), metav1.GetOptions{})
@@ -9,6 +9,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 	networkingv1 "k8s.io/api/networking/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -29,4 +30,11 @@ func TestNetworkPolicyV1(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, networkPolicy.GetName(), result.GetName())
 	})
+	t.Run("delete NetworkPolicy", func(t *testing.T) {
+		err := testClient.DeleteNetworkPolicyV1(namespace, networkPolicy.GetName())
+		require.NoError(t, err)
+
+		err = testClient.DeleteNetworkPolicyV1(namespace, networkPolicy.GetName())
+		require.True(t, k8sErrors.IsNotFound(err))
+	})
 }
@@ -0,0 +1,46 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package k8s
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NodeReadiness describes the readiness and kubelet version of a node.
+type NodeReadiness struct {
+	Name           string
+	Ready          bool
+	KubeletVersion string
+}
+
+// GetNodeReadiness returns the readiness of all nodes of the cluster.
+func (kc *KubeClient) GetNodeReadiness() ([]NodeReadiness, error) {
+	ctx := context.TODO()
+	nodes, err := kc.Clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list nodes")
+	}
+
+	var readiness []NodeReadiness
+	for _, node := range nodes.Items {
+		nodeReadiness := NodeReadiness{
+			Name:           node.Name,
+			KubeletVersion: node.Status.NodeInfo.KubeletVersion,
+		}
+		for _, condition := range node.Status.Conditions {
+			if condition.Type == corev1.NodeReady {
+				nodeReadiness.Ready = condition.Status == corev1.ConditionTrue
+				break
+			}
+		}
+		readiness = append(readiness, nodeReadiness)
+	}
+
+	return readiness, nil
+}
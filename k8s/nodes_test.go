@@ -0,0 +1,48 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetNodeReadiness(t *testing.T) {
+	testClient := newTestKubeClient()
+
+	_, err := testClient.Clientset.CoreV1().Nodes().Create(context.TODO(), &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node1"},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{
+				{Type: corev1.NodeMemoryPressure, Status: corev1.ConditionFalse},
+				{Type: corev1.NodeReady, Status: corev1.ConditionTrue},
+			},
+			NodeInfo: corev1.NodeSystemInfo{KubeletVersion: "v1.29.4"},
+		},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+	_, err = testClient.Clientset.CoreV1().Nodes().Create(context.TODO(), &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node2"},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{
+				{Type: corev1.NodeReady, Status: corev1.ConditionUnknown},
+			},
+			NodeInfo: corev1.NodeSystemInfo{KubeletVersion: "v1.28.9"},
+		},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	readiness, err := testClient.GetNodeReadiness()
+	require.NoError(t, err)
+	assert.Equal(t, []NodeReadiness{
+		{Name: "node1", Ready: true, KubeletVersion: "v1.29.4"},
+		{Name: "node2", Ready: false, KubeletVersion: "v1.28.9"},
+	}, readiness)
+}
@@ -7,6 +7,8 @@ package k8s
 import (
 	"context"
 
+	"github.com/pkg/errors"
+
 	v1 "k8s.io/api/policy/v1"
 	v1beta1 "k8s.io/api/policy/v1beta1"
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
@@ -53,3 +55,23 @@ func (kc *KubeClient) createOrUpdatePodDisruptionBudgetV1(namespace string, podD
 	pdb.Spec = podDisruptionBudget.Spec
 	return kc.Clientset.PolicyV1().PodDisruptionBudgets(namespace).Update(ctx, pdb, metav1.UpdateOptions{})
 }
+
+// GetBlockingPodDisruptionBudgets returns the namespaced names of the
+// PodDisruptionBudgets which currently allow no disruption of the pods they
+// cover, and so would block the draining of nodes.
+func (kc *KubeClient) GetBlockingPodDisruptionBudgets() ([]string, error) {
+	ctx := context.TODO()
+	pdbs, err := kc.Clientset.PolicyV1().PodDisruptionBudgets(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list pod disruption budgets")
+	}
+
+	var blocking []string
+	for _, pdb := range pdbs.Items {
+		if pdb.Status.ExpectedPods > 0 && pdb.Status.DisruptionsAllowed == 0 {
+			blocking = append(blocking, pdb.Namespace+"/"+pdb.Name)
+		}
+	}
+
+	return blocking, nil
+}
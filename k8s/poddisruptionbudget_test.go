@@ -5,9 +5,12 @@
 package k8s
 
 import (
+	"context"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/policy/v1"
 	v1beta1 "k8s.io/api/policy/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -30,3 +33,26 @@ func TestPodDisruptionBudgetBetaV1(t *testing.T) {
 		require.Equal(t, podDisruptionBudget.GetName(), result.GetName())
 	})
 }
+
+func TestGetBlockingPodDisruptionBudgets(t *testing.T) {
+	testClient := newTestKubeClient()
+
+	_, err := testClient.Clientset.PolicyV1().PodDisruptionBudgets("app").Create(context.TODO(), &v1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{Name: "blocking"},
+		Status:     v1.PodDisruptionBudgetStatus{ExpectedPods: 1, DisruptionsAllowed: 0},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+	_, err = testClient.Clientset.PolicyV1().PodDisruptionBudgets("app").Create(context.TODO(), &v1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{Name: "allowing"},
+		Status:     v1.PodDisruptionBudgetStatus{ExpectedPods: 3, DisruptionsAllowed: 1},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+	_, err = testClient.Clientset.PolicyV1().PodDisruptionBudgets("idle").Create(context.TODO(), &v1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{Name: "no-pods"},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	blocking, err := testClient.GetBlockingPodDisruptionBudgets()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"app/blocking"}, blocking)
+}
@@ -0,0 +1,104 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NamespaceUsage is a snapshot of the resources actually used by the pods
+// and volumes of a namespace.
+type NamespaceUsage struct {
+	PodCount    int64
+	MilliCPU    int64
+	MemoryBytes int64
+	VolumeBytes int64
+}
+
+// NamespaceUsageGetter measures the resources used by the namespaces of a
+// cluster.
+type NamespaceUsageGetter interface {
+	GetNamespaceUsage(namespace string) (*NamespaceUsage, error)
+}
+
+// podMetricsList is the subset of the metrics.k8s.io PodMetricsList used to
+// compute namespace usage.
+type podMetricsList struct {
+	Items []struct {
+		Containers []struct {
+			Usage corev1.ResourceList `json:"usage"`
+		} `json:"containers"`
+	} `json:"items"`
+}
+
+// GetNamespaceUsage returns the CPU and memory used by the pods of the given
+// namespace, as reported by the metrics server, and the storage claimed by
+// its persistent volume claims.
+func (kc *KubeClient) GetNamespaceUsage(namespace string) (*NamespaceUsage, error) {
+	data, err := kc.Clientset.CoreV1().RESTClient().Get().
+		AbsPath(fmt.Sprintf("/apis/metrics.k8s.io/v1beta1/namespaces/%s/pods", namespace)).
+		DoRaw(context.TODO())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get pod metrics")
+	}
+
+	usage, err := parsePodMetrics(data)
+	if err != nil {
+		return nil, err
+	}
+
+	usage.VolumeBytes, err = kc.GetNamespaceVolumeBytes(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	return usage, nil
+}
+
+// GetNamespaceVolumeBytes returns the storage claimed by the persistent volume
+// claims of the given namespace. The capacity of bound claims is used when
+// known, as it can exceed the requested storage.
+func (kc *KubeClient) GetNamespaceVolumeBytes(namespace string) (int64, error) {
+	claims, err := kc.Clientset.CoreV1().PersistentVolumeClaims(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to list persistent volume claims")
+	}
+
+	var total int64
+	for _, claim := range claims.Items {
+		if capacity, ok := claim.Status.Capacity[corev1.ResourceStorage]; ok {
+			total += capacity.Value()
+			continue
+		}
+		total += claim.Spec.Resources.Requests.Storage().Value()
+	}
+
+	return total, nil
+}
+
+func parsePodMetrics(data []byte) (*NamespaceUsage, error) {
+	var metrics podMetricsList
+	err := json.Unmarshal(data, &metrics)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode pod metrics")
+	}
+
+	usage := &NamespaceUsage{}
+	for _, pod := range metrics.Items {
+		usage.PodCount++
+		for _, container := range pod.Containers {
+			usage.MilliCPU += container.Usage.Cpu().MilliValue()
+			usage.MemoryBytes += container.Usage.Memory().Value()
+		}
+	}
+
+	return usage, nil
+}
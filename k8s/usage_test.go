@@ -0,0 +1,73 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParsePodMetrics(t *testing.T) {
+	data := []byte(`{
+		"kind": "PodMetricsList",
+		"items": [
+			{"metadata": {"name": "mm-1"}, "containers": [
+				{"name": "mattermost", "usage": {"cpu": "250m", "memory": "512Mi"}},
+				{"name": "sidecar", "usage": {"cpu": "1500000n", "memory": "16Mi"}}
+			]},
+			{"metadata": {"name": "mm-2"}, "containers": [
+				{"name": "mattermost", "usage": {"cpu": "1", "memory": "1Gi"}}
+			]}
+		]
+	}`)
+
+	usage, err := parsePodMetrics(data)
+	require.NoError(t, err)
+	assert.Equal(t, &NamespaceUsage{
+		PodCount:    2,
+		MilliCPU:    1252,
+		MemoryBytes: (512 + 16 + 1024) * 1024 * 1024,
+	}, usage)
+
+	_, err = parsePodMetrics([]byte("not json"))
+	assert.Error(t, err)
+}
+
+func TestGetNamespaceVolumeBytes(t *testing.T) {
+	testClient := newTestKubeClient()
+	namespace := "installation"
+
+	_, err := testClient.Clientset.CoreV1().PersistentVolumeClaims(namespace).Create(context.TODO(), &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "bound"},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("1Gi")},
+			},
+		},
+		Status: corev1.PersistentVolumeClaimStatus{
+			Capacity: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("2Gi")},
+		},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+	_, err = testClient.Clientset.CoreV1().PersistentVolumeClaims(namespace).Create(context.TODO(), &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "pending"},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("500Mi")},
+			},
+		},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	volumeBytes, err := testClient.GetNamespaceVolumeBytes(namespace)
+	require.NoError(t, err)
+	assert.Equal(t, int64((2048+500)*1024*1024), volumeBytes)
+}
@@ -0,0 +1,125 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// WorkloadKindDeployment is the kind of deployment workloads.
+	WorkloadKindDeployment = "Deployment"
+	// WorkloadKindDaemonSet is the kind of daemonset workloads.
+	WorkloadKindDaemonSet = "DaemonSet"
+	// WorkloadKindStatefulSet is the kind of statefulset workloads.
+	WorkloadKindStatefulSet = "StatefulSet"
+
+	restartedAtAnnotation = "kubectl.kubernetes.io/restartedAt"
+)
+
+// WorkloadReadiness describes the number of ready pods of a workload.
+type WorkloadReadiness struct {
+	Kind    string
+	Name    string
+	Desired int32
+	Ready   int32
+}
+
+// IsReady returns true if all desired pods of the workload are ready.
+func (w WorkloadReadiness) IsReady() bool {
+	return w.Ready >= w.Desired
+}
+
+// GetWorkloadReadiness returns the readiness of the deployments, daemonsets
+// and statefulsets matching the label selector in the given namespace.
+func (kc *KubeClient) GetWorkloadReadiness(namespace, labelSelector string) ([]WorkloadReadiness, error) {
+	ctx := context.TODO()
+	listOptions := metav1.ListOptions{LabelSelector: labelSelector}
+	var workloads []WorkloadReadiness
+
+	deployments, err := kc.Clientset.AppsV1().Deployments(namespace).List(ctx, listOptions)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list deployments")
+	}
+	for _, deployment := range deployments.Items {
+		desired := int32(1)
+		if deployment.Spec.Replicas != nil {
+			desired = *deployment.Spec.Replicas
+		}
+		workloads = append(workloads, WorkloadReadiness{
+			Kind:    WorkloadKindDeployment,
+			Name:    deployment.Name,
+			Desired: desired,
+			Ready:   deployment.Status.AvailableReplicas,
+		})
+	}
+
+	daemonSets, err := kc.Clientset.AppsV1().DaemonSets(namespace).List(ctx, listOptions)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list daemonsets")
+	}
+	for _, daemonSet := range daemonSets.Items {
+		workloads = append(workloads, WorkloadReadiness{
+			Kind:    WorkloadKindDaemonSet,
+			Name:    daemonSet.Name,
+			Desired: daemonSet.Status.DesiredNumberScheduled,
+			Ready:   daemonSet.Status.NumberReady,
+		})
+	}
+
+	statefulSets, err := kc.Clientset.AppsV1().StatefulSets(namespace).List(ctx, listOptions)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list statefulsets")
+	}
+	for _, statefulSet := range statefulSets.Items {
+		desired := int32(1)
+		if statefulSet.Spec.Replicas != nil {
+			desired = *statefulSet.Spec.Replicas
+		}
+		workloads = append(workloads, WorkloadReadiness{
+			Kind:    WorkloadKindStatefulSet,
+			Name:    statefulSet.Name,
+			Desired: desired,
+			Ready:   statefulSet.Status.ReadyReplicas,
+		})
+	}
+
+	return workloads, nil
+}
+
+// RestartWorkloads triggers a rolling restart of the deployments, daemonsets
+// and statefulsets matching the label selector in the given namespace, the
+// same way as `kubectl rollout restart`.
+func (kc *KubeClient) RestartWorkloads(namespace, labelSelector string) error {
+	workloads, err := kc.GetWorkloadReadiness(namespace, labelSelector)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.TODO()
+	payload := []byte(fmt.Sprintf(`{"spec":{"template":{"metadata":{"annotations":{"%s":"%s"}}}}}`, restartedAtAnnotation, time.Now().Format(time.RFC3339)))
+
+	for _, workload := range workloads {
+		switch workload.Kind {
+		case WorkloadKindDeployment:
+			_, err = kc.Clientset.AppsV1().Deployments(namespace).Patch(ctx, workload.Name, types.StrategicMergePatchType, payload, metav1.PatchOptions{})
+		case WorkloadKindDaemonSet:
+			_, err = kc.Clientset.AppsV1().DaemonSets(namespace).Patch(ctx, workload.Name, types.StrategicMergePatchType, payload, metav1.PatchOptions{})
+		case WorkloadKindStatefulSet:
+			_, err = kc.Clientset.AppsV1().StatefulSets(namespace).Patch(ctx, workload.Name, types.StrategicMergePatchType, payload, metav1.PatchOptions{})
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to restart %s %s", workload.Kind, workload.Name)
+		}
+	}
+
+	return nil
+}
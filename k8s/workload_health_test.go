@@ -0,0 +1,66 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestWorkloadReadiness(t *testing.T) {
+	testClient := newTestKubeClient()
+	namespace := "nginx"
+	labels := map[string]string{"app.kubernetes.io/instance": "nginx"}
+	replicas := int32(2)
+
+	_, err := testClient.Clientset.AppsV1().Deployments(namespace).Create(context.TODO(), &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "controller", Labels: labels},
+		Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+		Status:     appsv1.DeploymentStatus{AvailableReplicas: 1},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+	_, err = testClient.Clientset.AppsV1().DaemonSets(namespace).Create(context.TODO(), &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Labels: labels},
+		Status:     appsv1.DaemonSetStatus{DesiredNumberScheduled: 3, NumberReady: 3},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+	_, err = testClient.Clientset.AppsV1().Deployments(namespace).Create(context.TODO(), &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "other", Labels: map[string]string{"app.kubernetes.io/instance": "other"}},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	t.Run("get readiness", func(t *testing.T) {
+		workloads, err := testClient.GetWorkloadReadiness(namespace, "app.kubernetes.io/instance=nginx")
+		require.NoError(t, err)
+		assert.Equal(t, []WorkloadReadiness{
+			{Kind: WorkloadKindDeployment, Name: "controller", Desired: 2, Ready: 1},
+			{Kind: WorkloadKindDaemonSet, Name: "agent", Desired: 3, Ready: 3},
+		}, workloads)
+		assert.False(t, workloads[0].IsReady())
+		assert.True(t, workloads[1].IsReady())
+	})
+
+	t.Run("restart", func(t *testing.T) {
+		err := testClient.RestartWorkloads(namespace, "app.kubernetes.io/instance=nginx")
+		require.NoError(t, err)
+
+		deployment, err := testClient.Clientset.AppsV1().Deployments(namespace).Get(context.TODO(), "controller", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Contains(t, deployment.Spec.Template.Annotations, restartedAtAnnotation)
+
+		daemonSet, err := testClient.Clientset.AppsV1().DaemonSets(namespace).Get(context.TODO(), "agent", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Contains(t, daemonSet.Spec.Template.Annotations, restartedAtAnnotation)
+
+		other, err := testClient.Clientset.AppsV1().Deployments(namespace).Get(context.TODO(), "other", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.NotContains(t, other.Spec.Template.Annotations, restartedAtAnnotation)
+	})
+}
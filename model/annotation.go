@@ -78,6 +78,15 @@ func GetAnnotationsIDs(annotations []*Annotation) []string {
 	return ids
 }
 
+// GetAnnotationsNames gets names of annotations.
+func GetAnnotationsNames(annotations []*Annotation) []string {
+	names := make([]string, 0, len(annotations))
+	for _, ann := range annotations {
+		names = append(names, ann.Name)
+	}
+	return names
+}
+
 // NewAddAnnotationsRequestFromReader will create a AddAnnotationsRequest from an
 // io.Reader with JSON data.
 func NewAddAnnotationsRequestFromReader(reader io.Reader) (*AddAnnotationsRequest, error) {
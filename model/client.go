@@ -175,6 +175,24 @@ func (c *Client) doPut(u string, request interface{}) (*http.Response, error) {
 	return c.httpClient.Do(req)
 }
 
+func (c *Client) doPatch(u string, request interface{}) (*http.Response, error) {
+	requestBytes, err := json.Marshal(request)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal request")
+	}
+
+	req, err := http.NewRequest(http.MethodPatch, u, bytes.NewReader(requestBytes))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create http request")
+	}
+	for k, v := range c.headers {
+		req.Header.Add(k, v)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	return c.httpClient.Do(req)
+}
+
 func (c *Client) doDelete(u string) (*http.Response, error) {
 	req, err := http.NewRequest(http.MethodDelete, u, nil)
 	if err != nil {
@@ -317,6 +335,173 @@ func (c *Client) GetClusterUtilities(clusterID string) (*UtilityMetadata, error)
 	}
 }
 
+// GetClusterUtilityRegistry returns the utility registry of the given cluster
+// with the desired and actual versions of the utilities.
+func (c *Client) GetClusterUtilityRegistry(clusterID string) ([]*ClusterUtility, error) {
+	resp, err := c.doGet(c.buildURL("/api/cluster/%s/utilities/registry", clusterID))
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return ClusterUtilitiesFromReader(resp.Body)
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// AddClusterUtility registers a new utility for the given cluster.
+func (c *Client) AddClusterUtility(clusterID string, request *AddClusterUtilityRequest) (*ClusterDTO, error) {
+	resp, err := c.doPost(c.buildURL("/api/cluster/%s/utilities", clusterID), request)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusAccepted:
+		return DTOFromReader[ClusterDTO](resp.Body)
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// RemoveClusterUtility removes a registered utility from the given cluster.
+func (c *Client) RemoveClusterUtility(clusterID, utilityName string) (*ClusterDTO, error) {
+	resp, err := c.doDelete(c.buildURL("/api/cluster/%s/utility/%s", clusterID, utilityName))
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusAccepted:
+		return DTOFromReader[ClusterDTO](resp.Body)
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// GetUtilityDrift fetches the utility version drift report of all clusters.
+func (c *Client) GetUtilityDrift(request *GetUtilityDriftRequest) (*UtilityDriftReport, error) {
+	u, err := url.Parse(c.buildURL("/api/clusters/utilities/drift"))
+	if err != nil {
+		return nil, err
+	}
+
+	request.ApplyToURL(u)
+
+	resp, err := c.doGet(u.String())
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return UtilityDriftReportFromReader(resp.Body)
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// CreateUtilityRollout requests the upgrade of a utility across the
+// selected clusters.
+func (c *Client) CreateUtilityRollout(request *UtilityRolloutRequest) (*UtilityRollout, error) {
+	resp, err := c.doPost(c.buildURL("/api/clusters/utilities/rollout"), request)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusAccepted:
+		return UtilityRolloutFromReader(resp.Body)
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// GetUtilityRollouts fetches the list of utility rollouts from the
+// configured provisioning server.
+func (c *Client) GetUtilityRollouts(request *GetUtilityRolloutsRequest) ([]*UtilityRollout, error) {
+	u, err := url.Parse(c.buildURL("/api/clusters/utilities/rollouts"))
+	if err != nil {
+		return nil, err
+	}
+
+	request.ApplyToURL(u)
+
+	resp, err := c.doGet(u.String())
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return UtilityRolloutsFromReader(resp.Body)
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// GetUtilityRollout fetches the utility rollout from the configured
+// provisioning server.
+func (c *Client) GetUtilityRollout(rolloutID string) (*UtilityRollout, error) {
+	resp, err := c.doGet(c.buildURL("/api/utility_rollout/%s", rolloutID))
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return UtilityRolloutFromReader(resp.Body)
+	case http.StatusNotFound:
+		return nil, nil
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// ResumeUtilityRollout retries the failed clusters of a paused utility
+// rollout.
+func (c *Client) ResumeUtilityRollout(rolloutID string) (*UtilityRollout, error) {
+	return c.updateUtilityRollout(rolloutID, "resume")
+}
+
+// CancelUtilityRollout cancels the upgrade of the clusters of a utility
+// rollout that were not upgraded yet.
+func (c *Client) CancelUtilityRollout(rolloutID string) (*UtilityRollout, error) {
+	return c.updateUtilityRollout(rolloutID, "cancel")
+}
+
+func (c *Client) updateUtilityRollout(rolloutID, action string) (*UtilityRollout, error) {
+	resp, err := c.doPost(c.buildURL("/api/utility_rollout/%s/%s", rolloutID, action), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusAccepted:
+		return UtilityRolloutFromReader(resp.Body)
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
 // UpdateCluster updates a cluster's configuration.
 func (c *Client) UpdateCluster(clusterID string, request *UpdateClusterRequest) (*ClusterDTO, error) {
 	resp, err := c.doPut(c.buildURL("/api/cluster/%s", clusterID), request)
@@ -351,6 +536,271 @@ func (c *Client) UpgradeCluster(clusterID string, request *PatchUpgradeClusterRe
 	}
 }
 
+// GetClusterUpgrades fetches the upgrades of a cluster, most recent first.
+func (c *Client) GetClusterUpgrades(clusterID string, request *GetClusterUpgradesRequest) ([]*ClusterUpgrade, error) {
+	u, err := url.Parse(c.buildURL("/api/cluster/%s/kubernetes/upgrades", clusterID))
+	if err != nil {
+		return nil, err
+	}
+
+	request.ApplyToURL(u)
+
+	resp, err := c.doGet(u.String())
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return ClusterUpgradesFromReader(resp.Body)
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// ResumeClusterUpgrade resumes the failed upgrade of a cluster from the
+// phase it failed in.
+func (c *Client) ResumeClusterUpgrade(clusterID string) (*ClusterUpgrade, error) {
+	resp, err := c.doPost(c.buildURL("/api/cluster/%s/kubernetes/upgrade/resume", clusterID), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusAccepted:
+		return ClusterUpgradeFromReader(resp.Body)
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// CreateClusterTemplate requests the creation of a cluster template from the
+// configured provisioning server.
+func (c *Client) CreateClusterTemplate(request *CreateClusterTemplateRequest) (*ClusterTemplate, error) {
+	resp, err := c.doPost(c.buildURL("/api/cluster_templates"), request)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return ClusterTemplateFromReader(resp.Body)
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// GetClusterTemplate fetches the cluster template with the given name from
+// the configured provisioning server.
+func (c *Client) GetClusterTemplate(name string) (*ClusterTemplate, error) {
+	resp, err := c.doGet(c.buildURL("/api/cluster_template/%s", name))
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return ClusterTemplateFromReader(resp.Body)
+
+	case http.StatusNotFound:
+		return nil, nil
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// GetClusterTemplates fetches the list of cluster templates from the
+// configured provisioning server.
+func (c *Client) GetClusterTemplates(request *GetClusterTemplatesRequest) ([]*ClusterTemplate, error) {
+	u, err := url.Parse(c.buildURL("/api/cluster_templates"))
+	if err != nil {
+		return nil, err
+	}
+
+	request.ApplyToURL(u)
+
+	resp, err := c.doGet(u.String())
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return ClusterTemplatesFromReader(resp.Body)
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// UpdateClusterTemplate updates the cluster template with the given name.
+func (c *Client) UpdateClusterTemplate(name string, request *PatchClusterTemplateRequest) (*ClusterTemplate, error) {
+	resp, err := c.doPut(c.buildURL("/api/cluster_template/%s", name), request)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return ClusterTemplateFromReader(resp.Body)
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// DeleteClusterTemplate deletes the cluster template with the given name.
+func (c *Client) DeleteClusterTemplate(name string) error {
+	resp, err := c.doDelete(c.buildURL("/api/cluster_template/%s", name))
+	if err != nil {
+		return err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusNoContent:
+		return nil
+
+	default:
+		return errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// GetClusterTemplateDivergence fetches how the clusters created from the
+// given cluster template differ from its current version.
+func (c *Client) GetClusterTemplateDivergence(name string, request *GetClusterTemplateDivergenceRequest) ([]*ClusterTemplateDivergence, error) {
+	u, err := url.Parse(c.buildURL("/api/cluster_template/%s/clusters", name))
+	if err != nil {
+		return nil, err
+	}
+
+	request.ApplyToURL(u)
+
+	resp, err := c.doGet(u.String())
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return ClusterTemplateDivergencesFromReader(resp.Body)
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// CreateSizeProfile requests the creation of an installation size profile
+// from the configured provisioning server.
+func (c *Client) CreateSizeProfile(request *CreateSizeProfileRequest) (*SizeProfile, error) {
+	resp, err := c.doPost(c.buildURL("/api/sizes"), request)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return SizeProfileFromReader(resp.Body)
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// GetSizeProfile fetches the installation size profile with the given name
+// from the configured provisioning server.
+func (c *Client) GetSizeProfile(name string) (*SizeProfile, error) {
+	resp, err := c.doGet(c.buildURL("/api/size/%s", name))
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return SizeProfileFromReader(resp.Body)
+
+	case http.StatusNotFound:
+		return nil, nil
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// GetSizeProfiles fetches the list of installation size profiles from the
+// configured provisioning server.
+func (c *Client) GetSizeProfiles(request *GetSizeProfilesRequest) ([]*SizeProfile, error) {
+	u, err := url.Parse(c.buildURL("/api/sizes"))
+	if err != nil {
+		return nil, err
+	}
+
+	request.ApplyToURL(u)
+
+	resp, err := c.doGet(u.String())
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return SizeProfilesFromReader(resp.Body)
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// UpdateSizeProfile updates the installation size profile with the given
+// name.
+func (c *Client) UpdateSizeProfile(name string, request *PatchSizeProfileRequest) (*SizeProfile, error) {
+	resp, err := c.doPut(c.buildURL("/api/size/%s", name), request)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return SizeProfileFromReader(resp.Body)
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// DeleteSizeProfile deletes the installation size profile with the given
+// name.
+func (c *Client) DeleteSizeProfile(name string) error {
+	resp, err := c.doDelete(c.buildURL("/api/size/%s", name))
+	if err != nil {
+		return err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusNoContent:
+		return nil
+
+	default:
+		return errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
 // ResizeCluster resizes a cluster with a new size value.
 func (c *Client) ResizeCluster(clusterID string, request *PatchClusterSizeRequest) (*ClusterDTO, error) {
 	resp, err := c.doPut(c.buildURL("/api/cluster/%s/size", clusterID), request)
@@ -401,6 +851,23 @@ func (c *Client) DeleteNodegroup(clusterID string, nodegroup string) (*ClusterDT
 	}
 }
 
+// PatchNodegroup changes the given nodegroup of a cluster in place.
+func (c *Client) PatchNodegroup(clusterID string, nodegroup string, request *PatchNodegroupRequest) (*ClusterDTO, error) {
+	resp, err := c.doPatch(c.buildURL("/api/cluster/%s/nodegroup/%s", clusterID, nodegroup), request)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusAccepted:
+		return DTOFromReader[ClusterDTO](resp.Body)
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
 // DeleteCluster deletes the given cluster and all resources contained therein.
 func (c *Client) DeleteCluster(clusterID string) error {
 	resp, err := c.doDelete(c.buildURL("/api/cluster/%s", clusterID))
@@ -690,9 +1157,90 @@ func (c *Client) WakeupInstallation(installationID string, request *PatchInstall
 	}
 }
 
-// GetInstallationsStatus fetches the status for all installations.
-func (c *Client) GetInstallationsStatus() (*InstallationsStatus, error) {
-	resp, err := c.doGet(c.buildURL("/api/installations/status"))
+// GetInstallationsStatus fetches the status for all installations.
+func (c *Client) GetInstallationsStatus() (*InstallationsStatus, error) {
+	resp, err := c.doGet(c.buildURL("/api/installations/status"))
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return InstallationsStatusFromReader(resp.Body)
+
+	case http.StatusNotFound:
+		return nil, nil
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// GetInstallationUsage fetches the resources used by the given installation
+// over a time window.
+func (c *Client) GetInstallationUsage(installationID string, request *GetInstallationUsageRequest) (*InstallationUsageSummary, error) {
+	u, err := url.Parse(c.buildURL("/api/installation/%s/usage", installationID))
+	if err != nil {
+		return nil, err
+	}
+
+	request.ApplyToURL(u)
+
+	resp, err := c.doGet(u.String())
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return InstallationUsageSummaryFromReader(resp.Body)
+
+	case http.StatusNotFound:
+		return nil, nil
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// GetUsageReport fetches the resources used by the installations of the fleet
+// over a time window grouped by owner or group.
+func (c *Client) GetUsageReport(request *GetUsageReportRequest) (*UsageReport, error) {
+	u, err := url.Parse(c.buildURL("/api/installations/usage"))
+	if err != nil {
+		return nil, err
+	}
+
+	request.ApplyToURL(u)
+
+	resp, err := c.doGet(u.String())
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return UsageReportFromReader(resp.Body)
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// GetInstallationSizeRecommendations fetches size recommendations for the
+// installations matching the request, based on their observed usage.
+func (c *Client) GetInstallationSizeRecommendations(request *GetInstallationSizeRecommendationsRequest) ([]*InstallationSizeRecommendation, error) {
+	u, err := url.Parse(c.buildURL("/api/installations/recommendations"))
+	if err != nil {
+		return nil, err
+	}
+
+	request.ApplyToURL(u)
+
+	resp, err := c.doGet(u.String())
 	if err != nil {
 		return nil, err
 	}
@@ -700,10 +1248,7 @@ func (c *Client) GetInstallationsStatus() (*InstallationsStatus, error) {
 
 	switch resp.StatusCode {
 	case http.StatusOK:
-		return InstallationsStatusFromReader(resp.Body)
-
-	case http.StatusNotFound:
-		return nil, nil
+		return InstallationSizeRecommendationsFromReader(resp.Body)
 
 	default:
 		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
@@ -830,6 +1375,25 @@ func (c *Client) RestoreInstallationDatabase(installationID, backupID string) (*
 	}
 }
 
+// RestoreInstallationDatabaseToPointInTime requests restoration of installation database to the given time in milliseconds.
+func (c *Client) RestoreInstallationDatabaseToPointInTime(installationID string, restoreTime int64) (*InstallationDBRestorationOperation, error) {
+	resp, err := c.doPost(c.buildURL("/api/installations/operations/database/restorations/point-in-time"),
+		InstallationDBPointInTimeRestorationRequest{InstallationID: installationID, RestoreTime: restoreTime},
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusAccepted:
+		return NewInstallationDBRestorationOperationFromReader(resp.Body)
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
 // GetInstallationDBRestorationOperations  fetches the list of installation db restoration operations from the configured provisioning server.
 func (c *Client) GetInstallationDBRestorationOperations(request *GetInstallationDBRestorationOperationsRequest) ([]*InstallationDBRestorationOperation, error) {
 	u, err := url.Parse(c.buildURL("/api/installations/operations/database/restorations"))
@@ -961,6 +1525,97 @@ func (c *Client) GetInstallationDBMigrationOperation(id string) (*InstallationDB
 	}
 }
 
+// MigrateInstallationFilestore requests installation filestore migration from the configured provisioning server.
+func (c *Client) MigrateInstallationFilestore(request *InstallationFilestoreMigrationRequest) (*InstallationFilestoreMigrationOperation, error) {
+	resp, err := c.doPost(c.buildURL("/api/installations/operations/filestore/migrations"), request)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusAccepted:
+		return NewFilestoreMigrationOperationFromReader(resp.Body)
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// CommitInstallationFilestoreMigration commits installation filestore migration from the configured provisioning server.
+func (c *Client) CommitInstallationFilestoreMigration(id string) (*InstallationFilestoreMigrationOperation, error) {
+	resp, err := c.doPost(c.buildURL("/api/installations/operations/filestore/migration/%s/commit", id), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusAccepted:
+		return NewFilestoreMigrationOperationFromReader(resp.Body)
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// RollbackInstallationFilestoreMigration triggers installation filestore migration rollback from the configured provisioning server.
+func (c *Client) RollbackInstallationFilestoreMigration(id string) (*InstallationFilestoreMigrationOperation, error) {
+	resp, err := c.doPost(c.buildURL("/api/installations/operations/filestore/migration/%s/rollback", id), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusAccepted:
+		return NewFilestoreMigrationOperationFromReader(resp.Body)
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// GetInstallationFilestoreMigrationOperations fetches the list of installation filestore migration operations from the configured provisioning server.
+func (c *Client) GetInstallationFilestoreMigrationOperations(request *GetInstallationFilestoreMigrationOperationsRequest) ([]*InstallationFilestoreMigrationOperation, error) {
+	u, err := url.Parse(c.buildURL("/api/installations/operations/filestore/migrations"))
+	if err != nil {
+		return nil, err
+	}
+	request.ApplyToURL(u)
+
+	resp, err := c.doGet(u.String())
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return NewFilestoreMigrationOperationsFromReader(resp.Body)
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// GetInstallationFilestoreMigrationOperation fetches the specified installation filestore migration operation from the configured provisioning server.
+func (c *Client) GetInstallationFilestoreMigrationOperation(id string) (*InstallationFilestoreMigrationOperation, error) {
+	resp, err := c.doGet(c.buildURL("/api/installations/operations/filestore/migration/%s", id))
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return NewFilestoreMigrationOperationFromReader(resp.Body)
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
 // AddInstallationAnnotations adds annotations to the given installation.
 func (c *Client) AddInstallationAnnotations(installationID string, annotationsRequest *AddAnnotationsRequest) (*InstallationDTO, error) {
 	resp, err := c.doPost(c.buildURL("/api/installation/%s/annotations", installationID), annotationsRequest)
@@ -1502,6 +2157,120 @@ func (c *Client) DeleteMultitenantDatabase(multitenantDatabaseID string, force b
 	}
 }
 
+// GetMultitenantDatabasesCapacity fetches the capacity and growth forecast of
+// multitenant databases.
+func (c *Client) GetMultitenantDatabasesCapacity(request *GetMultitenantDatabasesCapacityRequest) ([]*MultitenantDatabaseCapacity, error) {
+	u, err := url.Parse(c.buildURL("/api/databases/multitenant_databases/capacity"))
+	if err != nil {
+		return nil, err
+	}
+
+	request.ApplyToURL(u)
+
+	resp, err := c.doGet(u.String())
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return MultitenantDatabaseCapacitiesFromReader(resp.Body)
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// GetMultitenantDatabaseRebalancePlan fetches a dry-run rebalance plan for
+// the multitenant databases of a VPC.
+func (c *Client) GetMultitenantDatabaseRebalancePlan(request *MultitenantDatabaseRebalanceRequest) (*MultitenantDatabaseRebalancePlan, error) {
+	u, err := url.Parse(c.buildURL("/api/databases/multitenant_databases/rebalance"))
+	if err != nil {
+		return nil, err
+	}
+
+	request.ApplyToURL(u)
+
+	resp, err := c.doGet(u.String())
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return MultitenantDatabaseRebalancePlanFromReader(resp.Body)
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// RebalanceMultitenantDatabases approves and starts the execution of a
+// rebalance plan for the multitenant databases of a VPC.
+func (c *Client) RebalanceMultitenantDatabases(request *MultitenantDatabaseRebalanceRequest) (*MultitenantDatabaseRebalance, error) {
+	resp, err := c.doPost(c.buildURL("/api/databases/multitenant_databases/rebalance"), request)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusAccepted:
+		return MultitenantDatabaseRebalanceFromReader(resp.Body)
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// GetMultitenantDatabaseRebalances fetches the list of multitenant database
+// rebalances from the configured provisioning server.
+func (c *Client) GetMultitenantDatabaseRebalances(request *GetMultitenantDatabaseRebalancesRequest) ([]*MultitenantDatabaseRebalance, error) {
+	u, err := url.Parse(c.buildURL("/api/databases/multitenant_databases/rebalances"))
+	if err != nil {
+		return nil, err
+	}
+
+	request.ApplyToURL(u)
+
+	resp, err := c.doGet(u.String())
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return MultitenantDatabaseRebalancesFromReader(resp.Body)
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// GetMultitenantDatabaseRebalance fetches the multitenant database rebalance
+// from the configured provisioning server.
+func (c *Client) GetMultitenantDatabaseRebalance(rebalanceID string) (*MultitenantDatabaseRebalance, error) {
+	resp, err := c.doGet(c.buildURL("/api/databases/multitenant_database_rebalance/%s", rebalanceID))
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return MultitenantDatabaseRebalanceFromReader(resp.Body)
+
+	case http.StatusNotFound:
+		return nil, nil
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
 // GetLogicalDatabases fetches the list of logical databases from the configured provisioning server.
 func (c *Client) GetLogicalDatabases(request *GetLogicalDatabasesRequest) ([]*LogicalDatabase, error) {
 	u, err := url.Parse(c.buildURL("/api/databases/logical_databases"))
@@ -1948,6 +2717,93 @@ func (c *Client) DeleteSubscription(subID string) error {
 	}
 }
 
+// GetSubscriptionStats fetches the event delivery stats of the subscription.
+func (c *Client) GetSubscriptionStats(subID string) (*SubscriptionStats, error) {
+	resp, err := c.doGet(c.buildURL("/api/subscription/%s/stats", subID))
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return NewSubscriptionStatsFromReader(resp.Body)
+
+	case http.StatusNotFound:
+		return nil, nil
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// ListSubscriptionDeliveries requests list of event deliveries of the subscription.
+func (c *Client) ListSubscriptionDeliveries(subID string, request *ListSubscriptionDeliveriesRequest) ([]*StateChangeEventDeliveryData, error) {
+	u, err := url.Parse(c.buildURL("/api/subscription/%s/deliveries", subID))
+	if err != nil {
+		return nil, err
+	}
+
+	request.ApplyToURL(u)
+
+	resp, err := c.doGet(u.String())
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return NewStateChangeEventDeliveriesDataFromReader(resp.Body)
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// ReplaySubscriptionDeliveries schedules event deliveries of the subscription to be sent again.
+func (c *Client) ReplaySubscriptionDeliveries(subID string, request *ReplaySubscriptionDeliveriesRequest) (*ReplaySubscriptionDeliveriesResponse, error) {
+	resp, err := c.doPost(c.buildURL("/api/subscription/%s/replay", subID), request)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusAccepted:
+		return NewReplaySubscriptionDeliveriesResponseFromReader(resp.Body)
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// PauseSubscription stops event deliveries to the subscription.
+func (c *Client) PauseSubscription(subID string) (*Subscription, error) {
+	return c.updateSubscriptionPaused(subID, "pause")
+}
+
+// ResumeSubscription resumes event deliveries to the subscription.
+func (c *Client) ResumeSubscription(subID string) (*Subscription, error) {
+	return c.updateSubscriptionPaused(subID, "resume")
+}
+
+func (c *Client) updateSubscriptionPaused(subID, action string) (*Subscription, error) {
+	resp, err := c.doPost(c.buildURL("/api/subscription/%s/%s", subID, action), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return NewSubscriptionFromReader(resp.Body)
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
 func (c *Client) GetClusterInstallationStatus(clusterInstallationID string) (*ClusterInstallationStatus, error) {
 	resp, err := c.doGet(c.buildURL("/api/cluster_installation/%s/status", clusterInstallationID))
 	if err != nil {
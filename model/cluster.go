@@ -27,6 +27,8 @@ type Cluster struct {
 	Networking                  string
 	UtilityMetadata             *UtilityMetadata
 	PgBouncerConfig             *PgBouncerConfig
+	TemplateID                  string `json:"TemplateID,omitempty"`
+	TemplateVersion             int64  `json:"TemplateVersion,omitempty"`
 	AllowInstallations          bool
 	CreateAt                    int64
 	DeleteAt                    int64
@@ -128,6 +130,7 @@ func ClustersFromReader(reader io.Reader) ([]*Cluster, error) {
 type ClusterFilter struct {
 	Paging
 	Annotations *AnnotationsFilter
+	TemplateID  string
 }
 
 // AnnotationsFilter describes filter based on Annotations.
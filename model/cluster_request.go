@@ -49,6 +49,12 @@ type CreateClusterRequest struct {
 	ArgocdClusterRegister      map[string]string              `json:"argocd-register,omitempty"`
 	ExternalClusterSecretName  string                         `json:"external-cluster-secret-name,omitempty"`
 	PgBouncerConfig            *PgBouncerConfig               `json:"pgbouncer-config,omitempty"`
+	Template                   string                         `json:"template,omitempty"`
+	// TemplateOverrides are the names of the fields that override the
+	// template even when they are false or zero. Such values are omitted
+	// when the request is encoded, so they must be listed here to replace a
+	// value of the template.
+	TemplateOverrides []string `json:"template-overrides,omitempty"`
 }
 
 func (request *CreateClusterRequest) setUtilityDefaults(utilityName string) {
@@ -145,6 +151,9 @@ func (request *CreateClusterRequest) SetDefaults() {
 
 // Validate validates the values of a cluster create request.
 func (request *CreateClusterRequest) Validate() error {
+	if len(request.TemplateOverrides) != 0 && len(request.Template) == 0 {
+		return errors.New("template overrides require a template")
+	}
 	if request.Provider != ProviderAWS {
 		return errors.Errorf("unsupported provider %s", request.Provider)
 	}
@@ -205,6 +214,16 @@ func (request *CreateClusterRequest) Validate() error {
 				if ng.MaxCount != ng.MinCount {
 					return errors.Errorf("node min (%d) and max (%d) counts must match for node group %s", ng.MinCount, ng.MaxCount, name)
 				}
+				err := validateNodeGroupCapacityType(ng.CapacityType)
+				if err != nil {
+					return errors.Wrapf(err, "invalid node group %s", name)
+				}
+				if ng.AutoscalingPolicy != nil {
+					err = ng.AutoscalingPolicy.Validate(ng.MaxCount)
+					if err != nil {
+						return errors.Wrapf(err, "invalid autoscaling policy for node group %s", name)
+					}
+				}
 			}
 		}
 
@@ -253,10 +272,9 @@ func contains(s []string, str string) bool {
 // NewCreateClusterRequestFromReader will create a CreateClusterRequest from an
 // io.Reader with JSON data.
 func NewCreateClusterRequestFromReader(reader io.Reader) (*CreateClusterRequest, error) {
-	var createClusterRequest CreateClusterRequest
-	err := json.NewDecoder(reader).Decode(&createClusterRequest)
-	if err != nil && err != io.EOF {
-		return nil, errors.Wrap(err, "failed to decode create cluster request")
+	createClusterRequest, err := DecodeCreateClusterRequest(reader)
+	if err != nil {
+		return nil, err
 	}
 
 	createClusterRequest.SetDefaults()
@@ -265,6 +283,19 @@ func NewCreateClusterRequestFromReader(reader io.Reader) (*CreateClusterRequest,
 		return nil, errors.Wrap(err, "create cluster request failed validation")
 	}
 
+	return createClusterRequest, nil
+}
+
+// DecodeCreateClusterRequest decodes a CreateClusterRequest from an io.Reader
+// with JSON data without setting defaults, so that a template can be applied
+// to it first.
+func DecodeCreateClusterRequest(reader io.Reader) (*CreateClusterRequest, error) {
+	var createClusterRequest CreateClusterRequest
+	err := json.NewDecoder(reader).Decode(&createClusterRequest)
+	if err != nil && err != io.EOF {
+		return nil, errors.Wrap(err, "failed to decode create cluster request")
+	}
+
 	return &createClusterRequest, nil
 }
 
@@ -320,6 +351,22 @@ func (request *GetClustersRequest) ApplyToURL(u *url.URL) {
 	u.RawQuery = q.Encode()
 }
 
+// GetClusterUpgradesRequest describes the parameters to request a list of
+// upgrades of a cluster.
+type GetClusterUpgradesRequest struct {
+	Paging
+	State string
+}
+
+// ApplyToURL modifies the given url to include query string parameters for the request.
+func (request *GetClusterUpgradesRequest) ApplyToURL(u *url.URL) {
+	q := u.Query()
+	q.Add("state", request.State)
+	request.Paging.AddToQuery(q)
+
+	u.RawQuery = q.Encode()
+}
+
 // UpdateClusterRequest specifies the parameters available for updating a cluster.
 type UpdateClusterRequest struct {
 	Name               *string
@@ -343,6 +390,8 @@ type PatchUpgradeClusterRequest struct {
 	RotatorConfig  *RotatorConfig `json:"rotatorConfig,omitempty"`
 	MaxPodsPerNode *int64
 	KmsKeyId       *string `json:"kmsKeyId,omitempty"`
+	// SkipPreflight skips the checks run before upgrading the cluster.
+	SkipPreflight bool `json:"skipPreflight,omitempty"`
 }
 
 // Validate validates the values of a cluster upgrade request.
@@ -498,6 +547,16 @@ func (request *CreateNodegroupsRequest) Validate() error {
 		if meta.MaxCount < meta.MinCount {
 			return errors.Errorf("nodegroup %s max count (%d) can't be less than min count (%d)", ng, meta.MaxCount, meta.MinCount)
 		}
+		err := validateNodeGroupCapacityType(meta.CapacityType)
+		if err != nil {
+			return errors.Wrapf(err, "invalid nodegroup %s", ng)
+		}
+		if meta.AutoscalingPolicy != nil {
+			err = meta.AutoscalingPolicy.Validate(meta.MaxCount)
+			if err != nil {
+				return errors.Wrapf(err, "invalid autoscaling policy for nodegroup %s", ng)
+			}
+		}
 	}
 
 	for _, ng := range request.NodeGroupWithPublicSubnet {
@@ -537,3 +596,73 @@ func NewCreateNodegroupsRequestFromReader(reader io.Reader) (*CreateNodegroupsRe
 
 	return &createNodegroupsRequest, nil
 }
+
+// PatchNodegroupRequest specifies the parameters for changing a nodegroup of
+// a cluster in place. CapacityType switches the whole nodegroup between
+// ON_DEMAND and SPOT; mixing both requires one nodegroup per capacity type.
+// AutoscalingPolicy scales only this nodegroup. Keeping a base count of
+// on-demand nodes and scaling the rest on spot nodegroups is not supported.
+type PatchNodegroupRequest struct {
+	InstanceType            *string                     `json:"instance-type,omitempty"`
+	CapacityType            *string                     `json:"capacity-type,omitempty"`
+	MinCount                *int64                      `json:"min-count,omitempty"`
+	MaxCount                *int64                      `json:"max-count,omitempty"`
+	AutoscalingPolicy       *NodeGroupAutoscalingPolicy `json:"autoscaling-policy,omitempty"`
+	RemoveAutoscalingPolicy bool                        `json:"remove-autoscaling-policy,omitempty"`
+}
+
+// Validate validates the values of a nodegroup patch request.
+func (p *PatchNodegroupRequest) Validate() error {
+	if p.InstanceType != nil && len(*p.InstanceType) == 0 {
+		return errors.New("instance type cannot be a blank value")
+	}
+	if p.CapacityType != nil {
+		if len(*p.CapacityType) == 0 {
+			return errors.New("capacity type cannot be a blank value")
+		}
+		err := validateNodeGroupCapacityType(*p.CapacityType)
+		if err != nil {
+			return err
+		}
+	}
+	if p.MinCount != nil && *p.MinCount < 1 {
+		return errors.New("min count has to be 1 or greater")
+	}
+	if p.MinCount != nil && p.MaxCount != nil && *p.MaxCount < *p.MinCount {
+		return errors.Errorf("max count (%d) can't be less than min count (%d)", *p.MaxCount, *p.MinCount)
+	}
+	if p.AutoscalingPolicy != nil && p.RemoveAutoscalingPolicy {
+		return errors.New("cannot set and remove the autoscaling policy at the same time")
+	}
+	if p.InstanceType == nil && p.CapacityType == nil && p.MinCount == nil && p.MaxCount == nil &&
+		p.AutoscalingPolicy == nil && !p.RemoveAutoscalingPolicy {
+		return errors.New("nodegroup patch has no changes")
+	}
+
+	return nil
+}
+
+// NewPatchNodegroupRequestFromReader will create a PatchNodegroupRequest from an io.Reader with JSON data.
+func NewPatchNodegroupRequestFromReader(reader io.Reader) (*PatchNodegroupRequest, error) {
+	var patchNodegroupRequest PatchNodegroupRequest
+	err := json.NewDecoder(reader).Decode(&patchNodegroupRequest)
+	if err != nil && err != io.EOF {
+		return nil, errors.Wrap(err, "failed to decode patch nodegroup request")
+	}
+
+	err = patchNodegroupRequest.Validate()
+	if err != nil {
+		return nil, errors.Wrap(err, "patch nodegroup request failed validation")
+	}
+
+	return &patchNodegroupRequest, nil
+}
+
+func validateNodeGroupCapacityType(capacityType string) error {
+	switch capacityType {
+	case "", NodeGroupCapacityOnDemand, NodeGroupCapacitySpot:
+		return nil
+	}
+
+	return errors.Errorf("unsupported capacity type %s, must be %s or %s", capacityType, NodeGroupCapacityOnDemand, NodeGroupCapacitySpot)
+}
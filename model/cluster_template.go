@@ -0,0 +1,379 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	clusterTemplateNameMinLen = 3
+	clusterTemplateNameMaxLen = 64
+)
+
+var clusterTemplateNameRegex = regexp.MustCompile("^[a-z]+[a-z0-9_-]*$")
+
+// ClusterTemplate is a named set of cluster creation values that clusters
+// can be created from. The version of a template is incremented every time
+// its spec changes.
+type ClusterTemplate struct {
+	ID          string
+	Name        string
+	Description string
+	Version     int64
+	Spec        *ClusterTemplateSpec
+	CreateAt    int64
+	UpdateAt    int64
+	DeleteAt    int64
+}
+
+// ClusterTemplateSpec holds the cluster creation values of a template.
+type ClusterTemplateSpec CreateClusterRequest
+
+// ClusterTemplateFilter describes the parameters used to constrain a set of
+// cluster templates.
+type ClusterTemplateFilter struct {
+	Paging
+}
+
+// ClusterTemplateDivergence describes how a cluster created from a template
+// differs from the current version of the template.
+type ClusterTemplateDivergence struct {
+	ClusterID       string
+	ClusterName     string
+	State           string
+	TemplateVersion int64
+	CurrentVersion  int64
+	Differences     []string `json:"Differences,omitempty"`
+}
+
+// IsDeleted returns whether the cluster template was marked as deleted or not.
+func (t *ClusterTemplate) IsDeleted() bool {
+	return t.DeleteAt != 0
+}
+
+// Value implements the driver.Valuer interface for database storage
+func (s *ClusterTemplateSpec) Value() (driver.Value, error) {
+	if s == nil {
+		return nil, nil
+	}
+	return json.Marshal(s)
+}
+
+// Scan implements the sql.Scanner interface for database retrieval
+func (s *ClusterTemplateSpec) Scan(src interface{}) error {
+	if src == nil {
+		return nil
+	}
+
+	source, ok := src.([]byte)
+	if !ok {
+		return errors.New("could not assert type of ClusterTemplateSpec")
+	}
+
+	var spec ClusterTemplateSpec
+	err := json.Unmarshal(source, &spec)
+	if err != nil {
+		return err
+	}
+	*s = spec
+
+	return nil
+}
+
+// Validate validates the values of a cluster template spec by validating the
+// cluster create request it produces.
+func (s *ClusterTemplateSpec) Validate() error {
+	if len(s.Template) != 0 {
+		return errors.New("templates cannot reference other templates")
+	}
+	if len(s.TemplateOverrides) != 0 {
+		return errors.New("templates cannot have template overrides")
+	}
+
+	request := &CreateClusterRequest{}
+	err := request.ApplyTemplate(&ClusterTemplate{Spec: s}, nil)
+	if err != nil {
+		return err
+	}
+	request.SetDefaults()
+
+	return request.Validate()
+}
+
+func validateClusterTemplateName(name string) error {
+	if len(name) < clusterTemplateNameMinLen || len(name) > clusterTemplateNameMaxLen {
+		return errors.Errorf("template name must be between %d and %d characters long", clusterTemplateNameMinLen, clusterTemplateNameMaxLen)
+	}
+	if !clusterTemplateNameRegex.MatchString(name) {
+		return errors.New("template name must start with a letter and can contain only lowercase letters, numbers or '_', '-' characters")
+	}
+
+	return nil
+}
+
+// ApplyTemplate fills the values of the request that are not set with the
+// values of the given template. Nested values such as utility versions,
+// nodegroups and pgbouncer config are merged field by field, so a request
+// only needs to contain the values it overrides.
+//
+// The requestJSON is the JSON object the request was decoded from. When it
+// is provided, every field present in it overrides the template, including
+// false and zero values. Without it, only the non-zero values of the request
+// override the template. In both cases, the fields listed in the template
+// overrides of the request replace the template values with their zero
+// value when they are not set.
+func (request *CreateClusterRequest) ApplyTemplate(template *ClusterTemplate, requestJSON []byte) error {
+	if template.Spec == nil {
+		return nil
+	}
+
+	base, err := toJSONObject(template.Spec)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode template spec")
+	}
+
+	var overrides map[string]interface{}
+	if len(requestJSON) != 0 {
+		err = json.Unmarshal(requestJSON, &overrides)
+		if err != nil {
+			return errors.Wrap(err, "failed to decode create cluster request")
+		}
+	} else {
+		overrides, err = toJSONObject(request)
+		if err != nil {
+			return errors.Wrap(err, "failed to encode create cluster request")
+		}
+		removeZeroJSONValues(overrides)
+	}
+	if overrides == nil {
+		overrides = map[string]interface{}{}
+	}
+	for _, field := range request.TemplateOverrides {
+		if _, ok := overrides[field]; ok {
+			continue
+		}
+		if zero, ok := zeroJSONValue(base[field]); ok {
+			overrides[field] = zero
+		}
+	}
+
+	data, err := json.Marshal(mergeJSONObjects(base, overrides))
+	if err != nil {
+		return errors.Wrap(err, "failed to encode merged create cluster request")
+	}
+
+	var merged CreateClusterRequest
+	err = json.Unmarshal(data, &merged)
+	if err != nil {
+		return errors.Wrap(err, "failed to decode merged create cluster request")
+	}
+	merged.Template = request.Template
+	*request = merged
+
+	return nil
+}
+
+func toJSONObject(value interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	object := map[string]interface{}{}
+	err = json.Unmarshal(data, &object)
+	if err != nil {
+		return nil, err
+	}
+
+	return object, nil
+}
+
+// mergeJSONObjects returns the base object with the values present in the
+// overrides applied on top of it. Null values are treated as not present.
+func mergeJSONObjects(base, overrides map[string]interface{}) map[string]interface{} {
+	for key, value := range overrides {
+		if value == nil {
+			continue
+		}
+
+		overrideObject, isObject := value.(map[string]interface{})
+		baseObject, baseIsObject := base[key].(map[string]interface{})
+		if isObject && baseIsObject {
+			base[key] = mergeJSONObjects(baseObject, overrideObject)
+			continue
+		}
+
+		base[key] = value
+	}
+
+	return base
+}
+
+// removeZeroJSONValues removes the zero values from the given object and
+// its nested objects.
+func removeZeroJSONValues(object map[string]interface{}) {
+	for key, value := range object {
+		switch v := value.(type) {
+		case nil:
+			delete(object, key)
+		case string:
+			if v == "" {
+				delete(object, key)
+			}
+		case float64:
+			if v == 0 {
+				delete(object, key)
+			}
+		case bool:
+			if !v {
+				delete(object, key)
+			}
+		case map[string]interface{}:
+			removeZeroJSONValues(v)
+		}
+	}
+}
+
+// zeroJSONValue returns the zero value of the type of the given JSON value.
+// Null values and objects, which are merged field by field, are not
+// supported.
+func zeroJSONValue(value interface{}) (interface{}, bool) {
+	switch value.(type) {
+	case string:
+		return "", true
+	case float64:
+		return float64(0), true
+	case bool:
+		return false, true
+	case []interface{}:
+		return []interface{}{}, true
+	}
+
+	return nil, false
+}
+
+// Divergence compares the given cluster with the current version of the
+// template. It returns nil if the cluster was not created from the template.
+func (t *ClusterTemplate) Divergence(cluster *Cluster) *ClusterTemplateDivergence {
+	if cluster.TemplateID != t.ID {
+		return nil
+	}
+
+	divergence := &ClusterTemplateDivergence{
+		ClusterID:       cluster.ID,
+		ClusterName:     cluster.Name,
+		State:           cluster.State,
+		TemplateVersion: cluster.TemplateVersion,
+		CurrentVersion:  t.Version,
+	}
+
+	if cluster.TemplateVersion != t.Version {
+		divergence.Differences = append(divergence.Differences, fmt.Sprintf("created from template version %d", cluster.TemplateVersion))
+	}
+
+	spec := t.Spec
+	if spec == nil {
+		return divergence
+	}
+
+	addDifference := func(field, templateValue, clusterValue string) {
+		if len(templateValue) != 0 && templateValue != clusterValue {
+			divergence.Differences = append(divergence.Differences, fmt.Sprintf("%s is %q, template has %q", field, clusterValue, templateValue))
+		}
+	}
+
+	addDifference("provisioner", spec.Provisioner, cluster.Provisioner)
+	addDifference("networking", spec.Networking, cluster.Networking)
+
+	var version, ami string
+	switch cluster.Provisioner {
+	case ProvisionerKops:
+		if cluster.ProvisionerMetadataKops != nil {
+			version = cluster.ProvisionerMetadataKops.Version
+			ami = cluster.ProvisionerMetadataKops.AMI
+		}
+	case ProvisionerEKS:
+		if cluster.ProvisionerMetadataEKS != nil {
+			version = cluster.ProvisionerMetadataEKS.Version
+			ami = cluster.ProvisionerMetadataEKS.AMI
+		}
+	}
+	if spec.Version != "latest" {
+		addDifference("version", spec.Version, version)
+	}
+	addDifference("ami", spec.AMI, ami)
+
+	var utilities []string
+	for utility := range spec.DesiredUtilityVersions {
+		utilities = append(utilities, utility)
+	}
+	sort.Strings(utilities)
+
+	for _, utility := range utilities {
+		templateVersion := spec.DesiredUtilityVersions[utility]
+		if templateVersion == nil {
+			continue
+		}
+		var clusterVersion string
+		if actualVersion := cluster.ActualUtilityVersion(utility); actualVersion != nil {
+			clusterVersion = actualVersion.Version()
+		}
+		addDifference(fmt.Sprintf("utility %s", utility), templateVersion.Chart, clusterVersion)
+	}
+
+	return divergence
+}
+
+// IsDiverged returns whether the cluster differs from the template.
+func (d *ClusterTemplateDivergence) IsDiverged() bool {
+	return len(d.Differences) > 0
+}
+
+// ClusterTemplateFromReader decodes a json-encoded cluster template from the
+// given io.Reader.
+func ClusterTemplateFromReader(reader io.Reader) (*ClusterTemplate, error) {
+	template := ClusterTemplate{}
+	decoder := json.NewDecoder(reader)
+	err := decoder.Decode(&template)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return &template, nil
+}
+
+// ClusterTemplatesFromReader decodes a json-encoded list of cluster templates
+// from the given io.Reader.
+func ClusterTemplatesFromReader(reader io.Reader) ([]*ClusterTemplate, error) {
+	templates := []*ClusterTemplate{}
+	decoder := json.NewDecoder(reader)
+	err := decoder.Decode(&templates)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return templates, nil
+}
+
+// ClusterTemplateDivergencesFromReader decodes a json-encoded list of cluster
+// template divergences from the given io.Reader.
+func ClusterTemplateDivergencesFromReader(reader io.Reader) ([]*ClusterTemplateDivergence, error) {
+	divergences := []*ClusterTemplateDivergence{}
+	decoder := json.NewDecoder(reader)
+	err := decoder.Decode(&divergences)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return divergences, nil
+}
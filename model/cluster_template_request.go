@@ -0,0 +1,135 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+	"net/url"
+	"reflect"
+
+	"github.com/pkg/errors"
+)
+
+// CreateClusterTemplateRequest specifies the parameters for a new cluster
+// template.
+type CreateClusterTemplateRequest struct {
+	Name        string               `json:"name"`
+	Description string               `json:"description,omitempty"`
+	Spec        *ClusterTemplateSpec `json:"spec"`
+}
+
+// Validate validates the values of a cluster template create request.
+func (request *CreateClusterTemplateRequest) Validate() error {
+	err := validateClusterTemplateName(request.Name)
+	if err != nil {
+		return err
+	}
+	if request.Spec == nil {
+		return errors.New("template spec must be set")
+	}
+
+	return errors.Wrap(request.Spec.Validate(), "invalid template spec")
+}
+
+// NewCreateClusterTemplateRequestFromReader will create a
+// CreateClusterTemplateRequest from an io.Reader with JSON data.
+func NewCreateClusterTemplateRequestFromReader(reader io.Reader) (*CreateClusterTemplateRequest, error) {
+	var createRequest CreateClusterTemplateRequest
+	err := json.NewDecoder(reader).Decode(&createRequest)
+	if err != nil && err != io.EOF {
+		return nil, errors.Wrap(err, "failed to decode create cluster template request")
+	}
+
+	err = createRequest.Validate()
+	if err != nil {
+		return nil, errors.Wrap(err, "create cluster template request failed validation")
+	}
+
+	return &createRequest, nil
+}
+
+// PatchClusterTemplateRequest specifies the parameters for updating a cluster
+// template. The spec replaces the spec of the template as a whole.
+type PatchClusterTemplateRequest struct {
+	Description *string              `json:"description,omitempty"`
+	Spec        *ClusterTemplateSpec `json:"spec,omitempty"`
+}
+
+// Validate validates the values of a cluster template patch request.
+func (p *PatchClusterTemplateRequest) Validate() error {
+	if p.Description == nil && p.Spec == nil {
+		return errors.New("cluster template patch has no changes")
+	}
+	if p.Spec != nil {
+		return errors.Wrap(p.Spec.Validate(), "invalid template spec")
+	}
+
+	return nil
+}
+
+// Apply applies the patch to the given cluster template. The version of the
+// template is incremented when its spec changes.
+func (p *PatchClusterTemplateRequest) Apply(template *ClusterTemplate) bool {
+	var applied bool
+	if p.Description != nil && *p.Description != template.Description {
+		applied = true
+		template.Description = *p.Description
+	}
+	if p.Spec != nil && !reflect.DeepEqual(p.Spec, template.Spec) {
+		applied = true
+		template.Spec = p.Spec
+		template.Version++
+	}
+
+	return applied
+}
+
+// NewPatchClusterTemplateRequestFromReader will create a
+// PatchClusterTemplateRequest from an io.Reader with JSON data.
+func NewPatchClusterTemplateRequestFromReader(reader io.Reader) (*PatchClusterTemplateRequest, error) {
+	var patchRequest PatchClusterTemplateRequest
+	err := json.NewDecoder(reader).Decode(&patchRequest)
+	if err != nil && err != io.EOF {
+		return nil, errors.Wrap(err, "failed to decode patch cluster template request")
+	}
+
+	err = patchRequest.Validate()
+	if err != nil {
+		return nil, errors.Wrap(err, "patch cluster template request failed validation")
+	}
+
+	return &patchRequest, nil
+}
+
+// GetClusterTemplatesRequest describes the parameters to request a list of
+// cluster templates.
+type GetClusterTemplatesRequest struct {
+	Paging
+}
+
+// ApplyToURL modifies the given url to include query string parameters for the request.
+func (request *GetClusterTemplatesRequest) ApplyToURL(u *url.URL) {
+	q := u.Query()
+	request.Paging.AddToQuery(q)
+
+	u.RawQuery = q.Encode()
+}
+
+// GetClusterTemplateDivergenceRequest describes the parameters to request
+// the clusters created from a cluster template.
+type GetClusterTemplateDivergenceRequest struct {
+	OnlyDiverged bool
+}
+
+// ApplyToURL modifies the given url to include query string parameters for the request.
+func (request *GetClusterTemplateDivergenceRequest) ApplyToURL(u *url.URL) {
+	q := u.Query()
+	if request.OnlyDiverged {
+		q.Add("only_diverged", "true")
+	}
+
+	u.RawQuery = q.Encode()
+}
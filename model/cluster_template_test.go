@@ -0,0 +1,277 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateClusterRequestApplyTemplate(t *testing.T) {
+	template := &ClusterTemplate{
+		ID:      NewID(),
+		Name:    "standard",
+		Version: 2,
+		Spec: &ClusterTemplateSpec{
+			Provisioner:      ProvisionerEKS,
+			Version:          "1.28",
+			Zones:            []string{"us-east-1a", "us-east-1b"},
+			NodeInstanceType: "m6i.large",
+			NodeMinCount:     3,
+			Networking:       NetworkingCalico,
+			Annotations:      []string{"team-sre"},
+			DesiredUtilityVersions: map[string]*HelmUtilityVersion{
+				NginxCanonicalName: {Chart: "4.0.18", ValuesPath: "nginx.yaml"},
+			},
+			PgBouncerConfig:    &PgBouncerConfig{MinPoolSize: 5, DefaultPoolSize: 10},
+			AllowInstallations: true,
+		},
+	}
+
+	t.Run("empty request takes template values", func(t *testing.T) {
+		request := &CreateClusterRequest{Template: template.Name}
+		require.NoError(t, request.ApplyTemplate(template, nil))
+
+		assert.Equal(t, template.Name, request.Template)
+		assert.Equal(t, ProvisionerEKS, request.Provisioner)
+		assert.Equal(t, "1.28", request.Version)
+		assert.Equal(t, []string{"us-east-1a", "us-east-1b"}, request.Zones)
+		assert.Equal(t, int64(3), request.NodeMinCount)
+		assert.Equal(t, []string{"team-sre"}, request.Annotations)
+		assert.Equal(t, "4.0.18", request.DesiredUtilityVersions[NginxCanonicalName].Chart)
+		assert.Equal(t, int64(10), request.PgBouncerConfig.DefaultPoolSize)
+		assert.True(t, request.AllowInstallations)
+	})
+
+	t.Run("request values override template values", func(t *testing.T) {
+		request := &CreateClusterRequest{
+			Template:         template.Name,
+			NodeInstanceType: "m6i.xlarge",
+			Zones:            []string{"us-east-1c"},
+			DesiredUtilityVersions: map[string]*HelmUtilityVersion{
+				NginxCanonicalName: {Chart: "4.1.0"},
+			},
+			PgBouncerConfig: &PgBouncerConfig{MinPoolSize: 1},
+		}
+		require.NoError(t, request.ApplyTemplate(template, nil))
+
+		assert.Equal(t, "m6i.xlarge", request.NodeInstanceType)
+		assert.Equal(t, []string{"us-east-1c"}, request.Zones)
+		assert.Equal(t, "1.28", request.Version)
+		assert.Equal(t, "4.1.0", request.DesiredUtilityVersions[NginxCanonicalName].Chart)
+		assert.Equal(t, "nginx.yaml", request.DesiredUtilityVersions[NginxCanonicalName].ValuesPath)
+		assert.Equal(t, int64(1), request.PgBouncerConfig.MinPoolSize)
+		assert.Equal(t, int64(10), request.PgBouncerConfig.DefaultPoolSize)
+	})
+
+	t.Run("present request values override template values", func(t *testing.T) {
+		requestJSON := []byte(`{"allow-installations": false, "node-min-count": 0, "pgbouncer-config": {"MinPoolSize": 0}}`)
+		request, err := DecodeCreateClusterRequest(bytes.NewReader(requestJSON))
+		require.NoError(t, err)
+		require.NoError(t, request.ApplyTemplate(template, requestJSON))
+
+		assert.False(t, request.AllowInstallations)
+		assert.Equal(t, int64(0), request.NodeMinCount)
+		assert.Equal(t, int64(0), request.PgBouncerConfig.MinPoolSize)
+		assert.Equal(t, int64(10), request.PgBouncerConfig.DefaultPoolSize)
+		assert.Equal(t, "m6i.large", request.NodeInstanceType)
+	})
+
+	t.Run("null request values do not override template values", func(t *testing.T) {
+		requestJSON := []byte(`{"pgbouncer-config": null}`)
+		request, err := DecodeCreateClusterRequest(bytes.NewReader(requestJSON))
+		require.NoError(t, err)
+		require.NoError(t, request.ApplyTemplate(template, requestJSON))
+
+		assert.True(t, request.AllowInstallations)
+		assert.Equal(t, int64(5), request.PgBouncerConfig.MinPoolSize)
+	})
+
+	t.Run("template overrides replace template values with zero values", func(t *testing.T) {
+		request := &CreateClusterRequest{
+			Template:          template.Name,
+			TemplateOverrides: []string{"allow-installations", "annotations", "unknown"},
+		}
+		require.NoError(t, request.ApplyTemplate(template, nil))
+
+		assert.False(t, request.AllowInstallations)
+		assert.Empty(t, request.Annotations)
+		assert.Equal(t, int64(3), request.NodeMinCount)
+	})
+
+	t.Run("template overrides of encoded request", func(t *testing.T) {
+		requestJSON := []byte(`{"template": "standard", "template-overrides": ["allow-installations"]}`)
+		request, err := DecodeCreateClusterRequest(bytes.NewReader(requestJSON))
+		require.NoError(t, err)
+		require.NoError(t, request.ApplyTemplate(template, requestJSON))
+
+		assert.False(t, request.AllowInstallations)
+		assert.Equal(t, []string{"allow-installations"}, request.TemplateOverrides)
+	})
+
+	t.Run("template is not modified", func(t *testing.T) {
+		request := &CreateClusterRequest{NodeInstanceType: "m6i.xlarge"}
+		require.NoError(t, request.ApplyTemplate(template, nil))
+
+		assert.Equal(t, "m6i.large", template.Spec.NodeInstanceType)
+	})
+}
+
+func TestClusterTemplateSpecValidate(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		spec := &ClusterTemplateSpec{Provisioner: ProvisionerKops, NodeInstanceType: "m6i.large"}
+		assert.NoError(t, spec.Validate())
+	})
+
+	t.Run("references another template", func(t *testing.T) {
+		spec := &ClusterTemplateSpec{Template: "other"}
+		assert.Error(t, spec.Validate())
+	})
+
+	t.Run("has template overrides", func(t *testing.T) {
+		spec := &ClusterTemplateSpec{TemplateOverrides: []string{"allow-installations"}}
+		assert.Error(t, spec.Validate())
+	})
+
+	t.Run("invalid values", func(t *testing.T) {
+		spec := &ClusterTemplateSpec{Provider: "azure"}
+		assert.Error(t, spec.Validate())
+	})
+
+	t.Run("eks without role arns", func(t *testing.T) {
+		spec := &ClusterTemplateSpec{Provisioner: ProvisionerEKS}
+		assert.Error(t, spec.Validate())
+	})
+}
+
+func TestCreateClusterTemplateRequestValidate(t *testing.T) {
+	spec := &ClusterTemplateSpec{}
+
+	for _, testCase := range []struct {
+		name  string
+		valid bool
+	}{
+		{"standard", true},
+		{"eks-large_2", true},
+		{"ab", false},
+		{"Standard", false},
+		{"2standard", false},
+		{"standard template", false},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			request := &CreateClusterTemplateRequest{Name: testCase.name, Spec: spec}
+			if testCase.valid {
+				assert.NoError(t, request.Validate())
+			} else {
+				assert.Error(t, request.Validate())
+			}
+		})
+	}
+
+	t.Run("missing spec", func(t *testing.T) {
+		request := &CreateClusterTemplateRequest{Name: "standard"}
+		assert.Error(t, request.Validate())
+	})
+}
+
+func TestPatchClusterTemplateRequestApply(t *testing.T) {
+	newTemplate := func() *ClusterTemplate {
+		return &ClusterTemplate{
+			Description: "standard clusters",
+			Version:     1,
+			Spec:        &ClusterTemplateSpec{NodeInstanceType: "m6i.large"},
+		}
+	}
+
+	t.Run("description only", func(t *testing.T) {
+		template := newTemplate()
+		description := "updated"
+		patch := &PatchClusterTemplateRequest{Description: &description}
+		assert.True(t, patch.Apply(template))
+		assert.Equal(t, "updated", template.Description)
+		assert.Equal(t, int64(1), template.Version)
+	})
+
+	t.Run("unchanged spec", func(t *testing.T) {
+		template := newTemplate()
+		patch := &PatchClusterTemplateRequest{Spec: &ClusterTemplateSpec{NodeInstanceType: "m6i.large"}}
+		assert.False(t, patch.Apply(template))
+		assert.Equal(t, int64(1), template.Version)
+	})
+
+	t.Run("changed spec", func(t *testing.T) {
+		template := newTemplate()
+		patch := &PatchClusterTemplateRequest{Spec: &ClusterTemplateSpec{NodeInstanceType: "m6i.xlarge"}}
+		assert.True(t, patch.Apply(template))
+		assert.Equal(t, "m6i.xlarge", template.Spec.NodeInstanceType)
+		assert.Equal(t, int64(2), template.Version)
+	})
+
+	t.Run("no changes", func(t *testing.T) {
+		patch := &PatchClusterTemplateRequest{}
+		assert.Error(t, patch.Validate())
+	})
+}
+
+func TestClusterTemplateDivergence(t *testing.T) {
+	template := &ClusterTemplate{
+		ID:      NewID(),
+		Version: 2,
+		Spec: &ClusterTemplateSpec{
+			Provisioner: ProvisionerEKS,
+			Version:     "1.28",
+			DesiredUtilityVersions: map[string]*HelmUtilityVersion{
+				NginxCanonicalName: {Chart: "4.0.18"},
+			},
+		},
+	}
+
+	newCluster := func() *Cluster {
+		cluster := &Cluster{
+			ID:              NewID(),
+			Provisioner:     ProvisionerEKS,
+			TemplateID:      template.ID,
+			TemplateVersion: 2,
+			ProvisionerMetadataEKS: &EKSMetadata{
+				Version: "1.28",
+			},
+		}
+		require.NoError(t, cluster.SetUtilityActualVersion(NginxCanonicalName, &HelmUtilityVersion{Chart: "4.0.18"}))
+		return cluster
+	}
+
+	t.Run("other template", func(t *testing.T) {
+		cluster := newCluster()
+		cluster.TemplateID = NewID()
+		assert.Nil(t, template.Divergence(cluster))
+	})
+
+	t.Run("matching", func(t *testing.T) {
+		divergence := template.Divergence(newCluster())
+		require.NotNil(t, divergence)
+		assert.False(t, divergence.IsDiverged())
+	})
+
+	t.Run("older template version", func(t *testing.T) {
+		cluster := newCluster()
+		cluster.TemplateVersion = 1
+		divergence := template.Divergence(cluster)
+		require.NotNil(t, divergence)
+		assert.True(t, divergence.IsDiverged())
+		assert.Len(t, divergence.Differences, 1)
+	})
+
+	t.Run("changed cluster", func(t *testing.T) {
+		cluster := newCluster()
+		cluster.ProvisionerMetadataEKS.Version = "1.29"
+		require.NoError(t, cluster.SetUtilityActualVersion(NginxCanonicalName, &HelmUtilityVersion{Chart: "4.1.0"}))
+		divergence := template.Divergence(cluster)
+		require.NotNil(t, divergence)
+		assert.Len(t, divergence.Differences, 2)
+	})
+}
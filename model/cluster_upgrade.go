@@ -0,0 +1,329 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ClusterUpgradeState is the state of a cluster upgrade.
+type ClusterUpgradeState string
+
+const (
+	// ClusterUpgradeStateInProgress is an upgrade being worked on.
+	ClusterUpgradeStateInProgress ClusterUpgradeState = "in-progress"
+	// ClusterUpgradeStateFailed is an upgrade which failed and can be resumed.
+	ClusterUpgradeStateFailed ClusterUpgradeState = "failed"
+	// ClusterUpgradeStateSucceeded is an upgrade which completed.
+	ClusterUpgradeStateSucceeded ClusterUpgradeState = "succeeded"
+)
+
+// ClusterUpgradePhase is a step of a cluster upgrade. Phases are run in the
+// order they are declared.
+type ClusterUpgradePhase string
+
+const (
+	// ClusterUpgradePhasePreflight checks that the cluster can be upgraded.
+	ClusterUpgradePhasePreflight ClusterUpgradePhase = "preflight"
+	// ClusterUpgradePhaseControlPlane upgrades the control plane.
+	ClusterUpgradePhaseControlPlane ClusterUpgradePhase = "control-plane"
+	// ClusterUpgradePhaseNodeGroups rolls the worker nodegroups one at a time.
+	ClusterUpgradePhaseNodeGroups ClusterUpgradePhase = "nodegroups"
+	// ClusterUpgradePhaseVerification verifies the cluster installations
+	// after the upgrade.
+	ClusterUpgradePhaseVerification ClusterUpgradePhase = "verification"
+	// ClusterUpgradePhaseComplete is the phase of a finished upgrade.
+	ClusterUpgradePhaseComplete ClusterUpgradePhase = "complete"
+)
+
+// ClusterUpgradeNodeGroupState is the state of a nodegroup during an upgrade.
+type ClusterUpgradeNodeGroupState string
+
+const (
+	// ClusterUpgradeNodeGroupPending is a nodegroup waiting to be rolled.
+	ClusterUpgradeNodeGroupPending ClusterUpgradeNodeGroupState = "pending"
+	// ClusterUpgradeNodeGroupInProgress is a nodegroup being rolled.
+	ClusterUpgradeNodeGroupInProgress ClusterUpgradeNodeGroupState = "in-progress"
+	// ClusterUpgradeNodeGroupSucceeded is a nodegroup which was rolled.
+	ClusterUpgradeNodeGroupSucceeded ClusterUpgradeNodeGroupState = "succeeded"
+	// ClusterUpgradeNodeGroupFailed is a nodegroup which failed to roll.
+	ClusterUpgradeNodeGroupFailed ClusterUpgradeNodeGroupState = "failed"
+)
+
+// ClusterUpgrade is the progress record of a cluster upgrade.
+type ClusterUpgrade struct {
+	ID        string
+	ClusterID string
+	// Version is the kubernetes version the cluster is upgraded to. It is
+	// empty if the upgrade does not change the version.
+	Version       string
+	State         ClusterUpgradeState
+	Phase         ClusterUpgradePhase
+	SkipPreflight bool
+	Preflight     ClusterUpgradeChecks
+	NodeGroups    ClusterUpgradeNodeGroups
+	Verification  ClusterUpgradeChecks
+	// Message describes why the upgrade failed.
+	Message    string
+	CreateAt   int64
+	UpdateAt   int64
+	CompleteAt int64
+}
+
+// ClusterUpgradeCheck is the result of a preflight or verification check.
+type ClusterUpgradeCheck struct {
+	Name   string
+	Passed bool
+	// Blocking checks stop the upgrade when they don't pass.
+	Blocking bool
+	Message  string `json:"Message,omitempty"`
+}
+
+// ClusterUpgradeChecks is a list of upgrade check results.
+type ClusterUpgradeChecks []*ClusterUpgradeCheck
+
+// ClusterUpgradeNodeGroup is the progress of a nodegroup during an upgrade.
+type ClusterUpgradeNodeGroup struct {
+	Name       string
+	State      ClusterUpgradeNodeGroupState
+	StartAt    int64  `json:"StartAt,omitempty"`
+	CompleteAt int64  `json:"CompleteAt,omitempty"`
+	Message    string `json:"Message,omitempty"`
+}
+
+// ClusterUpgradeNodeGroups is the list of nodegroups rolled by an upgrade,
+// in rolling order.
+type ClusterUpgradeNodeGroups []*ClusterUpgradeNodeGroup
+
+// ClusterUpgradeFilter describes the parameters used to constrain a set of
+// cluster upgrades.
+type ClusterUpgradeFilter struct {
+	Paging
+	ClusterID string
+	States    []ClusterUpgradeState
+}
+
+// NewClusterUpgrade creates the progress record of an upgrade of the given
+// cluster, starting with the preflight checks.
+func NewClusterUpgrade(clusterID, version string, skipPreflight bool) *ClusterUpgrade {
+	return &ClusterUpgrade{
+		ClusterID:     clusterID,
+		Version:       version,
+		State:         ClusterUpgradeStateInProgress,
+		Phase:         ClusterUpgradePhasePreflight,
+		SkipPreflight: skipPreflight,
+	}
+}
+
+// IsFinished returns true if the upgrade succeeded.
+func (u *ClusterUpgrade) IsFinished() bool {
+	return u.State == ClusterUpgradeStateSucceeded
+}
+
+// Fail marks the upgrade as failed in its current phase.
+func (u *ClusterUpgrade) Fail(message string) {
+	u.State = ClusterUpgradeStateFailed
+	u.Message = message
+}
+
+// Resume marks a failed upgrade as in progress again. The upgrade resumes
+// from the phase it failed in.
+func (u *ClusterUpgrade) Resume() error {
+	if u.State != ClusterUpgradeStateFailed {
+		return errors.Errorf("upgrade in state %s cannot be resumed", u.State)
+	}
+	u.State = ClusterUpgradeStateInProgress
+	u.Message = ""
+	for _, nodeGroup := range u.NodeGroups {
+		if nodeGroup.State == ClusterUpgradeNodeGroupFailed {
+			nodeGroup.State = ClusterUpgradeNodeGroupPending
+			nodeGroup.Message = ""
+		}
+	}
+	return nil
+}
+
+// SetNodeGroups sets the nodegroups to roll, keeping the progress of
+// nodegroups already known to the upgrade.
+func (u *ClusterUpgrade) SetNodeGroups(names []string) {
+	existing := map[string]*ClusterUpgradeNodeGroup{}
+	for _, nodeGroup := range u.NodeGroups {
+		existing[nodeGroup.Name] = nodeGroup
+	}
+
+	nodeGroups := make(ClusterUpgradeNodeGroups, 0, len(names))
+	for _, name := range names {
+		if nodeGroup, ok := existing[name]; ok {
+			nodeGroups = append(nodeGroups, nodeGroup)
+			continue
+		}
+		nodeGroups = append(nodeGroups, &ClusterUpgradeNodeGroup{
+			Name:  name,
+			State: ClusterUpgradeNodeGroupPending,
+		})
+	}
+	u.NodeGroups = nodeGroups
+}
+
+// BlockingFailures returns the blocking checks which did not pass.
+func (c ClusterUpgradeChecks) BlockingFailures() ClusterUpgradeChecks {
+	var failures ClusterUpgradeChecks
+	for _, check := range c {
+		if check.Blocking && !check.Passed {
+			failures = append(failures, check)
+		}
+	}
+	return failures
+}
+
+// Summary describes the checks which did not pass.
+func (c ClusterUpgradeChecks) Summary() string {
+	var messages []string
+	for _, check := range c {
+		if !check.Passed {
+			messages = append(messages, check.Name+": "+check.Message)
+		}
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Value implements the driver.Valuer interface for database storage.
+func (c ClusterUpgradeChecks) Value() (driver.Value, error) {
+	if c == nil {
+		return nil, nil
+	}
+	return json.Marshal(c)
+}
+
+// Scan implements the sql.Scanner interface for database retrieval.
+func (c *ClusterUpgradeChecks) Scan(src interface{}) error {
+	if src == nil {
+		return nil
+	}
+
+	source, ok := src.([]byte)
+	if !ok {
+		return errors.New("could not assert type of ClusterUpgradeChecks")
+	}
+
+	var checks ClusterUpgradeChecks
+	err := json.Unmarshal(source, &checks)
+	if err != nil {
+		return err
+	}
+	*c = checks
+
+	return nil
+}
+
+// Value implements the driver.Valuer interface for database storage.
+func (n ClusterUpgradeNodeGroups) Value() (driver.Value, error) {
+	if n == nil {
+		return nil, nil
+	}
+	return json.Marshal(n)
+}
+
+// Scan implements the sql.Scanner interface for database retrieval.
+func (n *ClusterUpgradeNodeGroups) Scan(src interface{}) error {
+	if src == nil {
+		return nil
+	}
+
+	source, ok := src.([]byte)
+	if !ok {
+		return errors.New("could not assert type of ClusterUpgradeNodeGroups")
+	}
+
+	var nodeGroups ClusterUpgradeNodeGroups
+	err := json.Unmarshal(source, &nodeGroups)
+	if err != nil {
+		return err
+	}
+	*n = nodeGroups
+
+	return nil
+}
+
+// UpgradeNodeGroups returns the worker nodegroups of the cluster in the
+// order they are rolled during an upgrade.
+func (c *Cluster) UpgradeNodeGroups() []string {
+	var names []string
+	switch c.Provisioner {
+	case ProvisionerKops:
+		if c.ProvisionerMetadataKops == nil {
+			return nil
+		}
+		for name := range c.ProvisionerMetadataKops.NodeInstanceGroups {
+			names = append(names, name)
+		}
+		for name := range c.ProvisionerMetadataKops.CustomInstanceGroups {
+			names = append(names, name)
+		}
+	case ProvisionerEKS:
+		if c.ProvisionerMetadataEKS == nil {
+			return nil
+		}
+		nodeGroups := c.ProvisionerMetadataEKS.NodeGroups
+		if c.ProvisionerMetadataEKS.ChangeRequest != nil && len(c.ProvisionerMetadataEKS.ChangeRequest.NodeGroups) > 0 {
+			nodeGroups = c.ProvisionerMetadataEKS.ChangeRequest.NodeGroups
+		}
+		for name := range nodeGroups {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// UpgradeVersion returns the kubernetes version requested by the pending
+// upgrade of the cluster, or an empty string if the version is unchanged.
+func (c *Cluster) UpgradeVersion() string {
+	switch c.Provisioner {
+	case ProvisionerKops:
+		if c.ProvisionerMetadataKops != nil && c.ProvisionerMetadataKops.ChangeRequest != nil {
+			return c.ProvisionerMetadataKops.ChangeRequest.Version
+		}
+	case ProvisionerEKS:
+		if c.ProvisionerMetadataEKS != nil && c.ProvisionerMetadataEKS.ChangeRequest != nil {
+			return c.ProvisionerMetadataEKS.ChangeRequest.Version
+		}
+	}
+
+	return ""
+}
+
+// ClusterUpgradeFromReader decodes a json-encoded cluster upgrade from the
+// given io.Reader.
+func ClusterUpgradeFromReader(reader io.Reader) (*ClusterUpgrade, error) {
+	upgrade := ClusterUpgrade{}
+	decoder := json.NewDecoder(reader)
+	err := decoder.Decode(&upgrade)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return &upgrade, nil
+}
+
+// ClusterUpgradesFromReader decodes a json-encoded list of cluster upgrades
+// from the given io.Reader.
+func ClusterUpgradesFromReader(reader io.Reader) ([]*ClusterUpgrade, error) {
+	upgrades := []*ClusterUpgrade{}
+	decoder := json.NewDecoder(reader)
+	err := decoder.Decode(&upgrades)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return upgrades, nil
+}
@@ -0,0 +1,120 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClusterUpgradeResume(t *testing.T) {
+	upgrade := NewClusterUpgrade("cluster1", "1.29.4", false)
+	assert.Equal(t, ClusterUpgradeStateInProgress, upgrade.State)
+	assert.Equal(t, ClusterUpgradePhasePreflight, upgrade.Phase)
+	assert.Error(t, upgrade.Resume())
+
+	upgrade.Phase = ClusterUpgradePhaseNodeGroups
+	upgrade.SetNodeGroups([]string{"nodes-a", "nodes-b"})
+	upgrade.NodeGroups[0].State = ClusterUpgradeNodeGroupSucceeded
+	upgrade.NodeGroups[1].State = ClusterUpgradeNodeGroupFailed
+	upgrade.NodeGroups[1].Message = "drain timed out"
+	upgrade.Fail("failed to upgrade nodegroup nodes-b")
+	assert.Equal(t, ClusterUpgradeStateFailed, upgrade.State)
+	assert.False(t, upgrade.IsFinished())
+
+	require.NoError(t, upgrade.Resume())
+	assert.Equal(t, ClusterUpgradeStateInProgress, upgrade.State)
+	assert.Equal(t, ClusterUpgradePhaseNodeGroups, upgrade.Phase)
+	assert.Empty(t, upgrade.Message)
+	assert.Equal(t, ClusterUpgradeNodeGroupSucceeded, upgrade.NodeGroups[0].State)
+	assert.Equal(t, ClusterUpgradeNodeGroupPending, upgrade.NodeGroups[1].State)
+	assert.Empty(t, upgrade.NodeGroups[1].Message)
+}
+
+func TestClusterUpgradeSetNodeGroups(t *testing.T) {
+	upgrade := NewClusterUpgrade("cluster1", "", false)
+	upgrade.SetNodeGroups([]string{"nodes-a", "nodes-b"})
+	upgrade.NodeGroups[0].State = ClusterUpgradeNodeGroupSucceeded
+
+	upgrade.SetNodeGroups([]string{"nodes-a", "nodes-c"})
+	require.Len(t, upgrade.NodeGroups, 2)
+	assert.Equal(t, "nodes-a", upgrade.NodeGroups[0].Name)
+	assert.Equal(t, ClusterUpgradeNodeGroupSucceeded, upgrade.NodeGroups[0].State)
+	assert.Equal(t, "nodes-c", upgrade.NodeGroups[1].Name)
+	assert.Equal(t, ClusterUpgradeNodeGroupPending, upgrade.NodeGroups[1].State)
+}
+
+func TestClusterUpgradeChecks(t *testing.T) {
+	checks := ClusterUpgradeChecks{
+		{Name: "deprecated-apis", Passed: false, Blocking: false, Message: "old api"},
+		{Name: "pod-disruption-budgets", Passed: false, Blocking: true, Message: "app/pdb allows no disruption"},
+		{Name: "surge-capacity", Passed: true, Blocking: true},
+	}
+
+	failures := checks.BlockingFailures()
+	require.Len(t, failures, 1)
+	assert.Equal(t, "pod-disruption-budgets", failures[0].Name)
+	assert.Equal(t, "pod-disruption-budgets: app/pdb allows no disruption", failures.Summary())
+	assert.Equal(t, "deprecated-apis: old api; pod-disruption-budgets: app/pdb allows no disruption", checks.Summary())
+
+	value, err := checks.Value()
+	require.NoError(t, err)
+	var scanned ClusterUpgradeChecks
+	require.NoError(t, scanned.Scan(value))
+	assert.Equal(t, checks, scanned)
+
+	var empty ClusterUpgradeChecks
+	value, err = empty.Value()
+	require.NoError(t, err)
+	assert.Nil(t, value)
+}
+
+func TestClusterUpgradeNodeGroups(t *testing.T) {
+	t.Run("kops", func(t *testing.T) {
+		cluster := &Cluster{
+			Provisioner: ProvisionerKops,
+			ProvisionerMetadataKops: &KopsMetadata{
+				ChangeRequest:        &KopsMetadataRequestedState{Version: "1.29.4"},
+				MasterInstanceGroups: KopsInstanceGroupsMetadata{"master-us-east-1a": {}},
+				NodeInstanceGroups:   KopsInstanceGroupsMetadata{"nodes-us-east-1b": {}, "nodes-us-east-1a": {}},
+				CustomInstanceGroups: KopsInstanceGroupsMetadata{"arm": {}},
+			},
+		}
+		assert.Equal(t, []string{"arm", "nodes-us-east-1a", "nodes-us-east-1b"}, cluster.UpgradeNodeGroups())
+		assert.Equal(t, "1.29.4", cluster.UpgradeVersion())
+	})
+
+	t.Run("eks", func(t *testing.T) {
+		cluster := &Cluster{
+			Provisioner: ProvisionerEKS,
+			ProvisionerMetadataEKS: &EKSMetadata{
+				NodeGroups: map[string]NodeGroupMetadata{"nodes": {}, "calls": {}},
+			},
+		}
+		assert.Equal(t, []string{"calls", "nodes"}, cluster.UpgradeNodeGroups())
+		assert.Empty(t, cluster.UpgradeVersion())
+	})
+}
+
+func TestClusterUpgradesFromReader(t *testing.T) {
+	upgrades, err := ClusterUpgradesFromReader(bytes.NewReader([]byte(
+		`[{"ID":"id1","ClusterID":"cluster1","State":"failed","Phase":"nodegroups","NodeGroups":[{"Name":"nodes-a","State":"failed"}]}]`,
+	)))
+	require.NoError(t, err)
+	assert.Equal(t, []*ClusterUpgrade{{
+		ID:         "id1",
+		ClusterID:  "cluster1",
+		State:      ClusterUpgradeStateFailed,
+		Phase:      ClusterUpgradePhaseNodeGroups,
+		NodeGroups: ClusterUpgradeNodeGroups{{Name: "nodes-a", State: ClusterUpgradeNodeGroupFailed}},
+	}}, upgrades)
+
+	upgrade, err := ClusterUpgradeFromReader(bytes.NewReader([]byte("")))
+	require.NoError(t, err)
+	assert.Equal(t, &ClusterUpgrade{}, upgrade)
+}
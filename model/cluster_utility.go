@@ -172,6 +172,9 @@ type UtilityGroupVersions struct {
 	MetricsServer       *HelmUtilityVersion
 	Velero              *HelmUtilityVersion
 	Cloudprober         *HelmUtilityVersion
+	// Custom holds the versions of the utilities registered for the
+	// cluster, keyed by utility name.
+	Custom map[string]*HelmUtilityVersion `json:",omitempty"`
 }
 
 type UtilityArgocdClusterRegister struct {
@@ -183,7 +186,7 @@ type UtilityArgocdClusterRegister struct {
 // canonical names for each utility as the keys and the members of the
 // struct making up the values
 func (h *UtilityGroupVersions) AsMap() map[string]*HelmUtilityVersion {
-	return map[string]*HelmUtilityVersion{
+	versions := map[string]*HelmUtilityVersion{
 		PrometheusOperatorCanonicalName:  h.PrometheusOperator,
 		ThanosCanonicalName:              h.Thanos,
 		NginxCanonicalName:               h.Nginx,
@@ -198,6 +201,23 @@ func (h *UtilityGroupVersions) AsMap() map[string]*HelmUtilityVersion {
 		VeleroCanonicalName:              h.Velero,
 		CloudproberCanonicalName:         h.Cloudprober,
 	}
+	for utility, version := range h.Custom {
+		versions[utility] = version
+	}
+	return versions
+}
+
+// setCustomVersion sets the version of a utility registered for the
+// cluster. A nil version removes the utility's version.
+func (h *UtilityGroupVersions) setCustomVersion(utility string, version *HelmUtilityVersion) {
+	if version == nil {
+		delete(h.Custom, utility)
+		return
+	}
+	if h.Custom == nil {
+		h.Custom = map[string]*HelmUtilityVersion{}
+	}
+	h.Custom[utility] = version
 }
 
 // UtilityMetadata is a container struct for any metadata related to
@@ -207,6 +227,37 @@ type UtilityMetadata struct {
 	ActualVersions        UtilityGroupVersions
 	ManagedByArgocd       bool
 	ArgocdClusterRegister UtilityArgocdClusterRegister
+	// Utilities are the utilities registered for the cluster in addition
+	// to the builtin ones.
+	Utilities []*UtilityDefinition `json:",omitempty"`
+	// RemovedUtilities are utilities removed from the registry which are
+	// yet to be uninstalled from the cluster.
+	RemovedUtilities []*UtilityDefinition `json:",omitempty"`
+	// Health is the result of the last health check of each utility.
+	Health map[string]*ClusterUtilityHealth `json:",omitempty"`
+}
+
+// isCustomUtility returns true if the utility is registered for the
+// cluster or is pending removal.
+func (m *UtilityMetadata) isCustomUtility(utility string) bool {
+	if BuiltinUtility(utility) != nil {
+		return false
+	}
+	for _, definition := range append(m.Utilities, m.RemovedUtilities...) {
+		if definition.Name == utility {
+			return true
+		}
+	}
+	return false
+}
+
+// setUtilityVersion sets the version of a builtin or registered utility.
+func (m *UtilityMetadata) setUtilityVersion(versions *UtilityGroupVersions, utility string, version *HelmUtilityVersion) {
+	if m.isCustomUtility(utility) {
+		versions.setCustomVersion(utility, version)
+		return
+	}
+	setUtilityVersion(versions, utility, version)
 }
 
 // NewUtilityMetadata creates an instance of UtilityMetadata given the raw
@@ -236,8 +287,8 @@ func (c *Cluster) SetUtilityActualVersion(utility string, version *HelmUtilityVe
 		metadata = c.UtilityMetadata
 	}
 
-	setUtilityVersion(&metadata.ActualVersions, utility, version)
-	setUtilityVersion(&metadata.DesiredVersions, utility, nil)
+	metadata.setUtilityVersion(&metadata.ActualVersions, utility, version)
+	metadata.setUtilityVersion(&metadata.DesiredVersions, utility, nil)
 
 	c.UtilityMetadata = metadata
 	return nil
@@ -254,7 +305,7 @@ func (c *Cluster) SetUtilityDesiredVersions(desiredVersions map[string]*HelmUtil
 		desiredVersions = map[string]*HelmUtilityVersion{}
 	}
 	for utility, version := range desiredVersions {
-		setUtilityVersion(&c.UtilityMetadata.DesiredVersions, utility, version)
+		c.UtilityMetadata.setUtilityVersion(&c.UtilityMetadata.DesiredVersions, utility, version)
 	}
 }
 
@@ -302,6 +353,18 @@ func getUtilityVersion(versions UtilityGroupVersions, utility string) *HelmUtili
 	return nil
 }
 
+// ChartVersions returns chart versions of the utilities keyed by their
+// canonical names. Utilities without a version are omitted.
+func (h *UtilityGroupVersions) ChartVersions() map[string]string {
+	versions := map[string]string{}
+	for utility, version := range h.AsMap() {
+		if version != nil {
+			versions[utility] = version.Chart
+		}
+	}
+	return versions
+}
+
 // setUtilityVersion will assign the version in desiredVersion to the
 // utility whose name's string representation matches one of the known
 // utilities with a version field in utilityVersion struct in the
@@ -0,0 +1,177 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// UtilityRemediationAction is the action taken to remediate an unhealthy
+// cluster utility.
+type UtilityRemediationAction string
+
+const (
+	// UtilityRemediationNone disables the remediation of unhealthy utilities.
+	UtilityRemediationNone UtilityRemediationAction = "none"
+	// UtilityRemediationHelmUpgrade re-runs the Helm upgrade of the utility
+	// by reprovisioning the cluster.
+	UtilityRemediationHelmUpgrade UtilityRemediationAction = "helm-upgrade"
+	// UtilityRemediationRestart restarts the pods of the utility.
+	UtilityRemediationRestart UtilityRemediationAction = "restart"
+)
+
+// ClusterUtilityHealth is the result of the last health check of a utility
+// on a cluster.
+type ClusterUtilityHealth struct {
+	Utility string
+	Healthy bool
+	// HelmStatus is the status of the Helm release of the utility.
+	HelmStatus string                   `json:",omitempty"`
+	Workloads  []*UtilityWorkloadHealth `json:",omitempty"`
+	Message    string                   `json:",omitempty"`
+	CheckedAt  int64
+	// UnhealthySince is the time the utility was first seen unhealthy.
+	UnhealthySince      int64 `json:",omitempty"`
+	RemediationAttempts int   `json:",omitempty"`
+	LastRemediationAt   int64 `json:",omitempty"`
+}
+
+// UtilityWorkloadHealth is the readiness of a deployment, daemonset or
+// statefulset of a utility.
+type UtilityWorkloadHealth struct {
+	Kind    string
+	Name    string
+	Desired int32
+	Ready   int32
+}
+
+// RecordRemediation records a remediation attempt at the given time.
+func (h *ClusterUtilityHealth) RecordRemediation(now int64) {
+	h.RemediationAttempts++
+	h.LastRemediationAt = now
+}
+
+// SameStatus returns true if both health check results describe the same
+// state of the utility, ignoring the time they were checked at.
+func (h *ClusterUtilityHealth) SameStatus(other *ClusterUtilityHealth) bool {
+	if h == nil || other == nil {
+		return h == other
+	}
+	current, previous := *h, *other
+	current.CheckedAt, previous.CheckedAt = 0, 0
+	return reflect.DeepEqual(current, previous)
+}
+
+// SetUtilityHealth stores the result of a health check of a utility on the
+// cluster, preserving the remediation state while the utility stays
+// unhealthy. It returns true if the utility became unhealthy.
+func (c *Cluster) SetUtilityHealth(health *ClusterUtilityHealth) bool {
+	if c.UtilityMetadata == nil {
+		c.UtilityMetadata = &UtilityMetadata{}
+	}
+	if c.UtilityMetadata.Health == nil {
+		c.UtilityMetadata.Health = map[string]*ClusterUtilityHealth{}
+	}
+
+	previous := c.UtilityMetadata.Health[health.Utility]
+	c.UtilityMetadata.Health[health.Utility] = health
+
+	if health.Healthy {
+		health.UnhealthySince = 0
+		health.RemediationAttempts = 0
+		health.LastRemediationAt = 0
+		return false
+	}
+
+	if previous == nil || previous.Healthy {
+		health.UnhealthySince = health.CheckedAt
+		return true
+	}
+
+	health.UnhealthySince = previous.UnhealthySince
+	health.RemediationAttempts = previous.RemediationAttempts
+	health.LastRemediationAt = previous.LastRemediationAt
+	return false
+}
+
+// UtilityHealth returns the result of the last health check of a utility
+// on the cluster, or nil if it was never checked.
+func (c *Cluster) UtilityHealth(utility string) *ClusterUtilityHealth {
+	if c.UtilityMetadata == nil {
+		return nil
+	}
+	return c.UtilityMetadata.Health[utility]
+}
+
+// UtilityRemediationPolicy configures the remediation of unhealthy cluster
+// utilities.
+type UtilityRemediationPolicy struct {
+	Action UtilityRemediationAction
+	// InitialBackoff is the time a utility must stay unhealthy before the
+	// first remediation attempt. It doubles after every attempt.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// MaxAttempts is the maximum number of remediation attempts while a
+	// utility stays unhealthy. Zero means no limit.
+	MaxAttempts int
+}
+
+// Validate validates the remediation policy.
+func (p *UtilityRemediationPolicy) Validate() error {
+	switch p.Action {
+	case UtilityRemediationNone, UtilityRemediationHelmUpgrade, UtilityRemediationRestart:
+	default:
+		return errors.Errorf("unsupported utility remediation action %q", p.Action)
+	}
+	if p.InitialBackoff < 0 {
+		return errors.New("initial backoff must not be negative")
+	}
+	if p.MaxBackoff < p.InitialBackoff {
+		return errors.New("max backoff must not be lower than initial backoff")
+	}
+	if p.MaxAttempts < 0 {
+		return errors.New("max attempts must not be negative")
+	}
+	return nil
+}
+
+// Enabled returns true if unhealthy utilities are remediated.
+func (p *UtilityRemediationPolicy) Enabled() bool {
+	return p.Action != "" && p.Action != UtilityRemediationNone
+}
+
+// Backoff returns the time to wait before the remediation attempt following
+// the given number of attempts.
+func (p *UtilityRemediationPolicy) Backoff(attempts int) time.Duration {
+	backoff := p.InitialBackoff
+	for i := 0; i < attempts; i++ {
+		backoff *= 2
+		if backoff >= p.MaxBackoff {
+			return p.MaxBackoff
+		}
+	}
+	return backoff
+}
+
+// RemediationDue returns true if the unhealthy utility should be remediated
+// at the given time in milliseconds.
+func (p *UtilityRemediationPolicy) RemediationDue(health *ClusterUtilityHealth, now int64) bool {
+	if !p.Enabled() || health == nil || health.Healthy {
+		return false
+	}
+	if p.MaxAttempts > 0 && health.RemediationAttempts >= p.MaxAttempts {
+		return false
+	}
+
+	since := health.UnhealthySince
+	if health.RemediationAttempts > 0 {
+		since = health.LastRemediationAt
+	}
+
+	return now-since >= p.Backoff(health.RemediationAttempts).Milliseconds()
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetUtilityHealth(t *testing.T) {
+	cluster := &Cluster{}
+	assert.Nil(t, cluster.UtilityHealth(NginxCanonicalName))
+
+	assert.False(t, cluster.SetUtilityHealth(&ClusterUtilityHealth{Utility: NginxCanonicalName, Healthy: true, CheckedAt: 100}))
+	assert.True(t, cluster.UtilityHealth(NginxCanonicalName).Healthy)
+
+	assert.True(t, cluster.SetUtilityHealth(&ClusterUtilityHealth{Utility: NginxCanonicalName, CheckedAt: 200}))
+	health := cluster.UtilityHealth(NginxCanonicalName)
+	assert.Equal(t, int64(200), health.UnhealthySince)
+	health.RecordRemediation(250)
+
+	assert.False(t, cluster.SetUtilityHealth(&ClusterUtilityHealth{Utility: NginxCanonicalName, CheckedAt: 300}))
+	health = cluster.UtilityHealth(NginxCanonicalName)
+	assert.Equal(t, int64(200), health.UnhealthySince)
+	assert.Equal(t, 1, health.RemediationAttempts)
+	assert.Equal(t, int64(250), health.LastRemediationAt)
+
+	assert.False(t, cluster.SetUtilityHealth(&ClusterUtilityHealth{Utility: NginxCanonicalName, Healthy: true, CheckedAt: 400}))
+	health = cluster.UtilityHealth(NginxCanonicalName)
+	assert.Zero(t, health.UnhealthySince)
+	assert.Zero(t, health.RemediationAttempts)
+}
+
+func TestClusterUtilityHealthSameStatus(t *testing.T) {
+	health := &ClusterUtilityHealth{Utility: NginxCanonicalName, HelmStatus: "failed", CheckedAt: 100, UnhealthySince: 100}
+
+	assert.True(t, health.SameStatus(&ClusterUtilityHealth{Utility: NginxCanonicalName, HelmStatus: "failed", CheckedAt: 200, UnhealthySince: 100}))
+	assert.False(t, health.SameStatus(&ClusterUtilityHealth{Utility: NginxCanonicalName, HelmStatus: "deployed", CheckedAt: 100, UnhealthySince: 100}))
+	assert.False(t, health.SameStatus(nil))
+	assert.True(t, (*ClusterUtilityHealth)(nil).SameStatus(nil))
+}
+
+func TestUtilityRemediationPolicy(t *testing.T) {
+	t.Run("validate", func(t *testing.T) {
+		require.NoError(t, (&UtilityRemediationPolicy{Action: UtilityRemediationNone}).Validate())
+		require.NoError(t, (&UtilityRemediationPolicy{Action: UtilityRemediationRestart, InitialBackoff: time.Minute, MaxBackoff: time.Hour}).Validate())
+		require.Error(t, (&UtilityRemediationPolicy{Action: "reboot"}).Validate())
+		require.Error(t, (&UtilityRemediationPolicy{Action: UtilityRemediationRestart, InitialBackoff: time.Hour, MaxBackoff: time.Minute}).Validate())
+		require.Error(t, (&UtilityRemediationPolicy{Action: UtilityRemediationRestart, MaxAttempts: -1}).Validate())
+	})
+
+	t.Run("backoff", func(t *testing.T) {
+		policy := &UtilityRemediationPolicy{InitialBackoff: time.Minute, MaxBackoff: 5 * time.Minute}
+		assert.Equal(t, time.Minute, policy.Backoff(0))
+		assert.Equal(t, 2*time.Minute, policy.Backoff(1))
+		assert.Equal(t, 4*time.Minute, policy.Backoff(2))
+		assert.Equal(t, 5*time.Minute, policy.Backoff(3))
+	})
+
+	t.Run("remediation due", func(t *testing.T) {
+		minute := time.Minute.Milliseconds()
+		policy := &UtilityRemediationPolicy{Action: UtilityRemediationRestart, InitialBackoff: time.Minute, MaxBackoff: time.Hour, MaxAttempts: 2}
+		health := &ClusterUtilityHealth{UnhealthySince: 0}
+
+		assert.False(t, policy.RemediationDue(health, minute-1))
+		assert.True(t, policy.RemediationDue(health, minute))
+
+		health.RecordRemediation(minute)
+		assert.False(t, policy.RemediationDue(health, 2*minute))
+		assert.True(t, policy.RemediationDue(health, 3*minute))
+
+		health.RecordRemediation(3 * minute)
+		assert.False(t, policy.RemediationDue(health, time.Hour.Milliseconds()))
+
+		assert.False(t, policy.RemediationDue(&ClusterUtilityHealth{Healthy: true}, time.Hour.Milliseconds()))
+		assert.False(t, (&UtilityRemediationPolicy{Action: UtilityRemediationNone}).RemediationDue(&ClusterUtilityHealth{}, time.Hour.Milliseconds()))
+	})
+}
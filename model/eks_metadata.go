@@ -34,12 +34,15 @@ type EKSMetadata struct {
 // NodeGroupMetadata is the metadata of an instance group.
 type NodeGroupMetadata struct {
 	Name              string
-	Type              string `json:"Type,omitempty"`
-	InstanceType      string `json:"InstanceType,omitempty"`
-	MinCount          int64  `json:"MinCount,omitempty"`
-	MaxCount          int64  `json:"MaxCount,omitempty"`
-	WithPublicSubnet  bool   `json:"WithPublicSubnet,omitempty"`
-	WithSecurityGroup bool   `json:"WithSecurityGroup,omitempty"`
+	Type              string                      `json:"Type,omitempty"`
+	InstanceType      string                      `json:"InstanceType,omitempty"`
+	CapacityType      string                      `json:"CapacityType,omitempty"`
+	MinCount          int64                       `json:"MinCount,omitempty"`
+	MaxCount          int64                       `json:"MaxCount,omitempty"`
+	WithPublicSubnet  bool                        `json:"WithPublicSubnet,omitempty"`
+	WithSecurityGroup bool                        `json:"WithSecurityGroup,omitempty"`
+	AutoscalingPolicy *NodeGroupAutoscalingPolicy `json:"AutoscalingPolicy,omitempty"`
+	AutoscalingStatus *NodeGroupAutoscalingStatus `json:"AutoscalingStatus,omitempty"`
 }
 
 // EKSMetadataRequestedState is the requested state for eks metadata.
@@ -62,6 +65,9 @@ func (ng *NodeGroupMetadata) CopyMissingFieldsFrom(other NodeGroupMetadata) {
 	if ng.InstanceType == "" {
 		ng.InstanceType = other.InstanceType
 	}
+	if ng.CapacityType == "" {
+		ng.CapacityType = other.CapacityType
+	}
 	if ng.MinCount == 0 {
 		ng.MinCount = other.MinCount
 	}
@@ -116,10 +122,12 @@ func (em *EKSMetadata) ApplyClusterCreateRequest(createRequest *CreateClusterReq
 			Name:              fmt.Sprintf("%s-%s", name, NewNodeGroupSuffix()),
 			Type:              name,
 			InstanceType:      ng.InstanceType,
+			CapacityType:      ng.CapacityType,
 			MinCount:          ng.MinCount,
 			MaxCount:          ng.MaxCount,
 			WithPublicSubnet:  ng.WithPublicSubnet,
 			WithSecurityGroup: ng.WithSecurityGroup,
+			AutoscalingPolicy: ng.AutoscalingPolicy,
 		}
 	}
 
@@ -274,7 +282,7 @@ func (em *EKSMetadata) ValidateChangeRequest() error {
 	}
 
 	for _, ng := range changeRequest.NodeGroups {
-		if len(ng.InstanceType) != 0 || ng.MinCount != 0 || ng.MaxCount != 0 {
+		if len(ng.InstanceType) != 0 || len(ng.CapacityType) != 0 || ng.MinCount != 0 || ng.MaxCount != 0 {
 			changeAllowed = true
 			break
 		}
@@ -343,10 +351,12 @@ func (em *EKSMetadata) ApplyNodegroupsCreateRequest(request *CreateNodegroupsReq
 			Name:              fmt.Sprintf("%s-%s", name, NewNodeGroupSuffix()),
 			Type:              name,
 			InstanceType:      ng.InstanceType,
+			CapacityType:      ng.CapacityType,
 			MinCount:          ng.MinCount,
 			MaxCount:          ng.MaxCount,
 			WithPublicSubnet:  ng.WithPublicSubnet,
 			WithSecurityGroup: ng.WithSecurityGroup,
+			AutoscalingPolicy: ng.AutoscalingPolicy,
 		}
 	}
 
@@ -4,6 +4,8 @@
 
 package model
 
+import "encoding/json"
+
 // EventType represents the Provisioners' event type.
 type EventType string
 
@@ -23,6 +25,8 @@ type Event struct {
 // EventExtraData represents extra data of an Event.
 type EventExtraData struct {
 	Fields map[string]string `json:"fields"`
+	// Data is a typed payload of events other than resource state change.
+	Data json.RawMessage `json:"data,omitempty"`
 }
 
 // EventDeliveryStatus represents status of EventDelivery
@@ -36,6 +40,7 @@ const (
 	// EventDeliveryRetrying indicates that delivery failed but will be retired.
 	EventDeliveryRetrying EventDeliveryStatus = "retrying"
 	// EventDeliveryFailed indicates that delivery failed and will not be retried.
+	// Failed deliveries are kept as dead letters and can be replayed.
 	EventDeliveryFailed EventDeliveryStatus = "failed"
 )
 
@@ -47,6 +52,25 @@ type EventDelivery struct {
 	Attempts       int
 	EventID        string
 	SubscriptionID string
+	// ReplayedAt is the time the delivery was last scheduled for replay.
+	// The failure threshold of replayed delivery is counted from that time.
+	ReplayedAt int64
+}
+
+// FailureThresholdStart returns the time from which the failure threshold
+// of the delivery is counted.
+func (d EventDelivery) FailureThresholdStart(eventTimestamp int64) int64 {
+	if d.ReplayedAt > eventTimestamp {
+		return d.ReplayedAt
+	}
+	return eventTimestamp
+}
+
+// EventDeliveryFilter is a filter for event delivery queries.
+type EventDeliveryFilter struct {
+	Paging
+	SubscriptionID string
+	Status         EventDeliveryStatus
 }
 
 // EventDeliveryForEvent finds first EventDelivery for a particular eventID in collection.
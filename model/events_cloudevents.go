@@ -0,0 +1,97 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// SubscriptionPayloadFormat is the format in which events are delivered to a subscription.
+type SubscriptionPayloadFormat string
+
+const (
+	// SubscriptionPayloadFormatProvisioner delivers StateChangeEventPayload as JSON body.
+	SubscriptionPayloadFormatProvisioner SubscriptionPayloadFormat = ""
+	// SubscriptionPayloadFormatCloudEventsStructured delivers events as
+	// CloudEvents 1.0 in structured HTTP content mode.
+	SubscriptionPayloadFormatCloudEventsStructured SubscriptionPayloadFormat = "cloudevents-structured"
+	// SubscriptionPayloadFormatCloudEventsBinary delivers events as
+	// CloudEvents 1.0 in binary HTTP content mode.
+	SubscriptionPayloadFormatCloudEventsBinary SubscriptionPayloadFormat = "cloudevents-binary"
+)
+
+// Validate validates the SubscriptionPayloadFormat.
+func (f SubscriptionPayloadFormat) Validate() error {
+	switch f {
+	case SubscriptionPayloadFormatProvisioner,
+		SubscriptionPayloadFormatCloudEventsStructured,
+		SubscriptionPayloadFormatCloudEventsBinary:
+		return nil
+	default:
+		return errors.Errorf("unsupported payload format %q", f)
+	}
+}
+
+const (
+	// CloudEventsSpecVersion is the version of CloudEvents specification
+	// the provisioner events conform to.
+	CloudEventsSpecVersion = "1.0"
+	// CloudEventsContentType is the content type of structured mode CloudEvents.
+	CloudEventsContentType = "application/cloudevents+json"
+
+	cloudEventsTypePrefix   = "com.mattermost.provisioner"
+	cloudEventsSourcePrefix = "/provisioner"
+	cloudEventsHeaderPrefix = "ce-"
+)
+
+// CloudEvent is a CloudEvents 1.0 envelope of the provisioner event.
+type CloudEvent struct {
+	SpecVersion     string                  `json:"specversion"`
+	ID              string                  `json:"id"`
+	Source          string                  `json:"source"`
+	Type            string                  `json:"type"`
+	Subject         string                  `json:"subject,omitempty"`
+	Time            string                  `json:"time,omitempty"`
+	DataContentType string                  `json:"datacontenttype,omitempty"`
+	Data            StateChangeEventPayload `json:"data"`
+}
+
+// ToCloudEvent converts StateChangeEventData to CloudEvent.
+// The event type and source are derived from the resource type, so that
+// consumers can route events without inspecting the data.
+func (e *StateChangeEventData) ToCloudEvent() CloudEvent {
+	return CloudEvent{
+		SpecVersion:     CloudEventsSpecVersion,
+		ID:              e.Event.ID,
+		Source:          fmt.Sprintf("%s/%s", cloudEventsSourcePrefix, e.StateChange.ResourceType),
+		Type:            fmt.Sprintf("%s.%s.%s", cloudEventsTypePrefix, e.StateChange.ResourceType, e.Event.EventType),
+		Subject:         e.StateChange.ResourceID,
+		Time:            TimeFromMillis(e.Event.Timestamp).UTC().Format(time.RFC3339Nano),
+		DataContentType: "application/json",
+		Data:            e.ToEventPayload(),
+	}
+}
+
+// BinaryModeHeaders returns the HTTP headers carrying event attributes in
+// the CloudEvents binary content mode.
+func (c CloudEvent) BinaryModeHeaders() map[string]string {
+	headers := map[string]string{
+		cloudEventsHeaderPrefix + "specversion": c.SpecVersion,
+		cloudEventsHeaderPrefix + "id":          c.ID,
+		cloudEventsHeaderPrefix + "source":      c.Source,
+		cloudEventsHeaderPrefix + "type":        c.Type,
+	}
+	if c.Subject != "" {
+		headers[cloudEventsHeaderPrefix+"subject"] = c.Subject
+	}
+	if c.Time != "" {
+		headers[cloudEventsHeaderPrefix+"time"] = c.Time
+	}
+
+	return headers
+}
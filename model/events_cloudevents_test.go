@@ -0,0 +1,53 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStateChangeEventData_ToCloudEvent(t *testing.T) {
+	eventData := StateChangeEventData{
+		Event: Event{
+			ID:        "event1",
+			EventType: ResourceStateChangeEventType,
+			Timestamp: 1600000000123,
+			ExtraData: EventExtraData{Fields: map[string]string{"key": "value"}},
+		},
+		StateChange: StateChangeEvent{
+			ResourceID:   "installation1",
+			ResourceType: TypeInstallation,
+			OldState:     InstallationStateCreationRequested,
+			NewState:     InstallationStateStable,
+		},
+	}
+
+	cloudEvent := eventData.ToCloudEvent()
+	assert.Equal(t, "1.0", cloudEvent.SpecVersion)
+	assert.Equal(t, "event1", cloudEvent.ID)
+	assert.Equal(t, "/provisioner/installation", cloudEvent.Source)
+	assert.Equal(t, "com.mattermost.provisioner.installation.resourceStateChange", cloudEvent.Type)
+	assert.Equal(t, "installation1", cloudEvent.Subject)
+	assert.Equal(t, "2020-09-13T12:26:40.123Z", cloudEvent.Time)
+	assert.Equal(t, eventData.ToEventPayload(), cloudEvent.Data)
+
+	assert.Equal(t, map[string]string{
+		"ce-specversion": "1.0",
+		"ce-id":          "event1",
+		"ce-source":      "/provisioner/installation",
+		"ce-type":        "com.mattermost.provisioner.installation.resourceStateChange",
+		"ce-subject":     "installation1",
+		"ce-time":        "2020-09-13T12:26:40.123Z",
+	}, cloudEvent.BinaryModeHeaders())
+}
+
+func TestSubscriptionPayloadFormat_Validate(t *testing.T) {
+	assert.NoError(t, SubscriptionPayloadFormatProvisioner.Validate())
+	assert.NoError(t, SubscriptionPayloadFormatCloudEventsStructured.Validate())
+	assert.NoError(t, SubscriptionPayloadFormatCloudEventsBinary.Validate())
+	assert.Error(t, SubscriptionPayloadFormat("xml").Validate())
+}
@@ -0,0 +1,51 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+// EventPendingDeliveryStatuses are statuses of event deliveries that were
+// not yet completed.
+var EventPendingDeliveryStatuses = []EventDeliveryStatus{EventDeliveryNotAttempted, EventDeliveryRetrying}
+
+// EventPrunableDeliveryStatuses are statuses of event deliveries that may be
+// pruned. Failed deliveries are dead letters kept for replay, so only
+// delivered events and deliveries are ever pruned.
+var EventPrunableDeliveryStatuses = []EventDeliveryStatus{EventDeliveryDelivered}
+
+// IsPrunable returns true if the delivery status is one of
+// EventPrunableDeliveryStatuses.
+func (s EventDeliveryStatus) IsPrunable() bool {
+	for _, status := range EventPrunableDeliveryStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// ArchivedStateChangeEvent is a single record of the state change event
+// archive, containing the event together with its deliveries.
+type ArchivedStateChangeEvent struct {
+	StateChangeEventData
+	Deliveries []*EventDelivery
+}
+
+// EventsPruneResult contains the number of rows pruned from the event tables.
+type EventsPruneResult struct {
+	Events            int64
+	StateChangeEvents int64
+	EventDeliveries   int64
+}
+
+// Add adds the counts of other result to the result.
+func (r *EventsPruneResult) Add(other *EventsPruneResult) {
+	r.Events += other.Events
+	r.StateChangeEvents += other.StateChangeEvents
+	r.EventDeliveries += other.EventDeliveries
+}
+
+// IsEmpty returns true if no rows were pruned.
+func (r *EventsPruneResult) IsEmpty() bool {
+	return r.Events == 0 && r.StateChangeEvents == 0 && r.EventDeliveries == 0
+}
@@ -0,0 +1,18 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventDeliveryStatusIsPrunable(t *testing.T) {
+	assert.True(t, EventDeliveryDelivered.IsPrunable())
+	assert.False(t, EventDeliveryNotAttempted.IsPrunable())
+	assert.False(t, EventDeliveryRetrying.IsPrunable())
+	assert.False(t, EventDeliveryFailed.IsPrunable())
+}
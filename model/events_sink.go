@@ -0,0 +1,157 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// SubscriptionSinkType specifies the backend to which subscription events are
+// delivered. The subscription URL is interpreted according to the sink type.
+type SubscriptionSinkType string
+
+const (
+	// SubscriptionSinkHTTP delivers events with HTTP POST requests.
+	// The URL is the endpoint of the consumer.
+	SubscriptionSinkHTTP SubscriptionSinkType = "http"
+	// SubscriptionSinkNATS publishes events to NATS subject.
+	// The URL has the form nats://host:port/subject. Adding ?jetstream=true
+	// publishes to JetStream and waits for the acknowledgement.
+	SubscriptionSinkNATS SubscriptionSinkType = "nats"
+	// SubscriptionSinkKafka produces events to Kafka topic.
+	// The URL has the form kafka://broker1:9092,broker2:9092/topic.
+	SubscriptionSinkKafka SubscriptionSinkType = "kafka"
+	// SubscriptionSinkSQS sends events to AWS SQS queue.
+	// The URL is the queue URL.
+	SubscriptionSinkSQS SubscriptionSinkType = "sqs"
+	// SubscriptionSinkSNS publishes events to AWS SNS topic.
+	// The URL is the topic ARN.
+	SubscriptionSinkSNS SubscriptionSinkType = "sns"
+	// SubscriptionSinkFile appends events to a local file, one per line.
+	// The URL is either file:///path/to/file or stdout. Intended for development,
+	// it is only available when enabled on the server and the file must be
+	// within the directory configured there.
+	SubscriptionSinkFile SubscriptionSinkType = "file"
+
+	// SubscriptionSinkFileStdout is the file sink URL writing events to stdout.
+	SubscriptionSinkFileStdout = "stdout"
+)
+
+// SubscriptionSinkTypes lists all supported sink types.
+var SubscriptionSinkTypes = []SubscriptionSinkType{
+	SubscriptionSinkHTTP,
+	SubscriptionSinkNATS,
+	SubscriptionSinkKafka,
+	SubscriptionSinkSQS,
+	SubscriptionSinkSNS,
+	SubscriptionSinkFile,
+}
+
+// Validate validates the SubscriptionSinkType.
+func (t SubscriptionSinkType) Validate() error {
+	for _, sinkType := range SubscriptionSinkTypes {
+		if t == sinkType {
+			return nil
+		}
+	}
+
+	return errors.Errorf("unsupported sink type %q", t)
+}
+
+// ValidateURL checks if the subscription URL is valid for the sink type.
+func (t SubscriptionSinkType) ValidateURL(rawURL string) error {
+	switch t {
+	case SubscriptionSinkNATS:
+		_, _, err := ParseNATSSinkURL(rawURL)
+		return err
+	case SubscriptionSinkKafka:
+		_, _, err := ParseKafkaSinkURL(rawURL)
+		return err
+	case SubscriptionSinkSQS:
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			return errors.Wrap(err, "failed to parse SQS queue URL")
+		}
+		if u.Host == "" || strings.Trim(u.Path, "/") == "" {
+			return errors.New("SQS queue URL must contain host and queue path")
+		}
+		return nil
+	case SubscriptionSinkSNS:
+		if !strings.HasPrefix(rawURL, "arn:") || !strings.Contains(rawURL, ":sns:") {
+			return errors.New("SNS sink URL must be a topic ARN")
+		}
+		return nil
+	case SubscriptionSinkFile:
+		_, err := ParseFileSinkURL(rawURL)
+		return err
+	default:
+		_, err := url.Parse(rawURL)
+		if err != nil {
+			return errors.Wrap(err, "failed to parse subscription URL")
+		}
+		return nil
+	}
+}
+
+// ParseNATSSinkURL returns NATS server URL and subject from the sink URL.
+func ParseNATSSinkURL(rawURL string) (string, string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", "", errors.Wrap(err, "failed to parse NATS sink URL")
+	}
+	if u.Scheme != "nats" && u.Scheme != "tls" {
+		return "", "", errors.Errorf("NATS sink URL scheme must be nats or tls, got %q", u.Scheme)
+	}
+	subject := strings.Trim(u.Path, "/")
+	if u.Host == "" || subject == "" {
+		return "", "", errors.New("NATS sink URL must contain host and subject")
+	}
+
+	server := url.URL{Scheme: u.Scheme, Host: u.Host, User: u.User}
+	return server.String(), subject, nil
+}
+
+// ParseKafkaSinkURL returns Kafka brokers and topic from the sink URL.
+func ParseKafkaSinkURL(rawURL string) ([]string, string, error) {
+	rest, found := strings.CutPrefix(rawURL, "kafka://")
+	if !found {
+		return nil, "", errors.New("Kafka sink URL must start with kafka://")
+	}
+	hosts, topic, _ := strings.Cut(rest, "/")
+	topic = strings.Trim(topic, "/")
+	if hosts == "" || topic == "" {
+		return nil, "", errors.New("Kafka sink URL must contain brokers and topic")
+	}
+
+	var brokers []string
+	for _, broker := range strings.Split(hosts, ",") {
+		if broker == "" {
+			return nil, "", errors.New("Kafka sink URL contains empty broker")
+		}
+		brokers = append(brokers, broker)
+	}
+
+	return brokers, topic, nil
+}
+
+// ParseFileSinkURL returns the path of the file sink, or stdout.
+func ParseFileSinkURL(rawURL string) (string, error) {
+	if rawURL == SubscriptionSinkFileStdout {
+		return rawURL, nil
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse file sink URL")
+	}
+	if u.Scheme != "file" || u.Host != "" || !path.IsAbs(u.Path) {
+		return "", errors.Errorf("file sink URL must be %s or file:///absolute/path", SubscriptionSinkFileStdout)
+	}
+
+	return u.Path, nil
+}
@@ -41,7 +41,10 @@ type StateChangeEventPayload struct {
 	ResourceType ResourceType `json:"resourceType"`
 	NewState     string       `json:"newState"`
 	OldState     string       `json:"oldState"`
+	EventType    EventType    `json:"eventType,omitempty"`
 	ExtraData    map[string]string
+	// Data contains typed payload for events other than resource state change.
+	Data json.RawMessage `json:"data,omitempty"`
 }
 
 // ToEventPayload converts StateChangeEventData to StateChangeEventPayload.
@@ -53,7 +56,9 @@ func (e *StateChangeEventData) ToEventPayload() StateChangeEventPayload {
 		ResourceType: e.StateChange.ResourceType,
 		NewState:     e.StateChange.NewState,
 		OldState:     e.StateChange.OldState,
+		EventType:    e.Event.EventType,
 		ExtraData:    e.Event.ExtraData.Fields,
+		Data:         e.Event.ExtraData.Data,
 	}
 }
 
@@ -96,6 +101,19 @@ func NewStateChangeEventsDataFromReader(reader io.Reader) ([]*StateChangeEventDa
 	return data, nil
 }
 
+// NewStateChangeEventDeliveriesDataFromReader will create an array of
+// StateChangeEventDeliveryData from an io.Reader with JSON data.
+func NewStateChangeEventDeliveriesDataFromReader(reader io.Reader) ([]*StateChangeEventDeliveryData, error) {
+	data := []*StateChangeEventDeliveryData{}
+	decoder := json.NewDecoder(reader)
+	err := decoder.Decode(&data)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return data, nil
+}
+
 // StateChangeEventFilter is a filter for state change event queries.
 type StateChangeEventFilter struct {
 	Paging
@@ -5,7 +5,9 @@
 package model
 
 import (
+	"database/sql/driver"
 	"encoding/json"
+	"fmt"
 	"io"
 	"time"
 
@@ -40,6 +42,133 @@ type Subscription struct {
 	LockAcquiredBy   *string
 	LockAcquiredAt   int64
 	Headers          Headers
+	// Paused subscriptions keep accumulating event deliveries, but they
+	// are not sent until the subscription is resumed.
+	Paused bool
+	// PayloadFormat is the format in which events are delivered.
+	PayloadFormat SubscriptionPayloadFormat
+	// SinkType is the backend to which events are delivered. It determines
+	// how the URL is interpreted.
+	SinkType SubscriptionSinkType
+	// Filter narrows down the events delivered to the subscription.
+	// Subscription without filter receives all events of its EventType.
+	Filter *SubscriptionFilter `json:",omitempty"`
+}
+
+// SubscriptionFilter describes which events are delivered to a subscription.
+// Empty fields do not constrain the events.
+type SubscriptionFilter struct {
+	// ResourceTypes limits events to the resources of given types.
+	ResourceTypes []ResourceType `json:",omitempty"`
+	// NewStates limits events to transitions into one of given states.
+	NewStates []string `json:",omitempty"`
+	// OldStates limits events to transitions from one of given states.
+	OldStates []string `json:",omitempty"`
+	// OwnerID limits events to the resources owned by given owner.
+	OwnerID string `json:",omitempty"`
+	// GroupID limits events to the resources belonging to given group.
+	GroupID string `json:",omitempty"`
+	// Annotation limits events to the resources with given annotation.
+	Annotation string `json:",omitempty"`
+}
+
+// EventResourceMetadata contains the resource properties used to evaluate
+// subscription filters, which are not part of the event itself.
+type EventResourceMetadata struct {
+	OwnerID     string
+	GroupID     string
+	Annotations []string
+}
+
+// IsEmpty returns true if the filter does not constrain any events.
+func (f *SubscriptionFilter) IsEmpty() bool {
+	return f == nil || (len(f.ResourceTypes) == 0 &&
+		len(f.NewStates) == 0 &&
+		len(f.OldStates) == 0 &&
+		!f.RequiresResourceMetadata())
+}
+
+// RequiresResourceMetadata returns true if the filter cannot be evaluated
+// without EventResourceMetadata.
+func (f *SubscriptionFilter) RequiresResourceMetadata() bool {
+	return f != nil && (f.OwnerID != "" || f.GroupID != "" || f.Annotation != "")
+}
+
+// Matches returns true if the state change event passes the filter.
+// Metadata is only required when RequiresResourceMetadata returns true.
+func (f *SubscriptionFilter) Matches(event StateChangeEvent, metadata *EventResourceMetadata) bool {
+	if f.IsEmpty() {
+		return true
+	}
+	if len(f.ResourceTypes) > 0 && !resourceTypeIn(event.ResourceType, f.ResourceTypes) {
+		return false
+	}
+	if len(f.NewStates) > 0 && !contains(f.NewStates, event.NewState) {
+		return false
+	}
+	if len(f.OldStates) > 0 && !contains(f.OldStates, event.OldState) {
+		return false
+	}
+	if !f.RequiresResourceMetadata() {
+		return true
+	}
+	if metadata == nil {
+		return false
+	}
+	if f.OwnerID != "" && f.OwnerID != metadata.OwnerID {
+		return false
+	}
+	if f.GroupID != "" && f.GroupID != metadata.GroupID {
+		return false
+	}
+	if f.Annotation != "" && !contains(metadata.Annotations, f.Annotation) {
+		return false
+	}
+
+	return true
+}
+
+// Validate validates the subscription filter.
+func (f *SubscriptionFilter) Validate() error {
+	if f == nil {
+		return nil
+	}
+	for _, resourceType := range f.ResourceTypes {
+		if resourceType == "" {
+			return errors.New("filter resource type cannot be empty")
+		}
+	}
+
+	return nil
+}
+
+// Value implements driver.Valuer to store the filter as JSON.
+func (f *SubscriptionFilter) Value() (driver.Value, error) {
+	if f == nil {
+		return nil, nil
+	}
+	return json.Marshal(f)
+}
+
+// Scan implements sql.Scanner to load the filter stored as JSON.
+func (f *SubscriptionFilter) Scan(databaseValue interface{}) error {
+	switch value := databaseValue.(type) {
+	case []byte:
+		return json.Unmarshal(value, f)
+	case nil:
+		return nil
+	default:
+		return fmt.Errorf("cannot scan type %t into SubscriptionFilter", databaseValue)
+	}
+}
+
+func resourceTypeIn(resourceType ResourceType, resourceTypes []ResourceType) bool {
+	for _, t := range resourceTypes {
+		if t == resourceType {
+			return true
+		}
+	}
+	return false
 }
 
 // IsDeleted returns true if subscription is deleted.
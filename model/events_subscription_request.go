@@ -21,13 +21,25 @@ type CreateSubscriptionRequest struct {
 	EventType        EventType
 	FailureThreshold time.Duration
 	Headers          Headers
+	Filter           *SubscriptionFilter
+	PayloadFormat    SubscriptionPayloadFormat
+	// SinkType defaults to SubscriptionSinkHTTP.
+	SinkType SubscriptionSinkType
 }
 
 // ToSubscription validates request and converts it to subscription
 func (r CreateSubscriptionRequest) ToSubscription() (Subscription, error) {
-	_, err := url.Parse(r.URL)
+	sinkType := r.SinkType
+	if sinkType == "" {
+		sinkType = SubscriptionSinkHTTP
+	}
+	err := sinkType.Validate()
+	if err != nil {
+		return Subscription{}, errors.Wrap(err, "invalid subscription sink")
+	}
+	err = sinkType.ValidateURL(r.URL)
 	if err != nil {
-		return Subscription{}, errors.Wrap(err, "failed to parse subscription URL")
+		return Subscription{}, errors.Wrap(err, "invalid subscription URL")
 	}
 	if r.EventType == "" {
 		return Subscription{}, errors.New("event type is required when registering subscription")
@@ -38,6 +50,18 @@ func (r CreateSubscriptionRequest) ToSubscription() (Subscription, error) {
 	if r.FailureThreshold < 0 || r.FailureThreshold > 72*time.Hour {
 		return Subscription{}, errors.New("failure threshold need to be between 0 and 72 hours")
 	}
+	err = r.Filter.Validate()
+	if err != nil {
+		return Subscription{}, errors.Wrap(err, "invalid subscription filter")
+	}
+	filter := r.Filter
+	if filter.IsEmpty() {
+		filter = nil
+	}
+	err = r.PayloadFormat.Validate()
+	if err != nil {
+		return Subscription{}, errors.Wrap(err, "invalid subscription payload format")
+	}
 
 	return Subscription{
 		Name:                  r.Name,
@@ -48,6 +72,9 @@ func (r CreateSubscriptionRequest) ToSubscription() (Subscription, error) {
 		LastDeliveryAttemptAt: 0,
 		FailureThreshold:      r.FailureThreshold,
 		Headers:               r.Headers,
+		Filter:                filter,
+		PayloadFormat:         r.PayloadFormat,
+		SinkType:              sinkType,
 	}, nil
 }
 
@@ -80,3 +107,81 @@ func (request *ListSubscriptionsRequest) ApplyToURL(u *url.URL) {
 
 	u.RawQuery = q.Encode()
 }
+
+// ListSubscriptionDeliveriesRequest represents a request data for querying
+// event deliveries of a subscription.
+type ListSubscriptionDeliveriesRequest struct {
+	Paging
+	Status EventDeliveryStatus
+}
+
+// ApplyToURL modifies the given url to include query string parameters for the request.
+func (request *ListSubscriptionDeliveriesRequest) ApplyToURL(u *url.URL) {
+	q := u.Query()
+	q.Add("status", string(request.Status))
+	request.Paging.AddToQuery(q)
+
+	u.RawQuery = q.Encode()
+}
+
+// ReplaySubscriptionDeliveriesRequest represents a request to replay event
+// deliveries of a subscription. Either EventIDs or time range bounded by
+// From and To has to be specified.
+type ReplaySubscriptionDeliveriesRequest struct {
+	EventIDs []string `json:",omitempty"`
+	// From and To are inclusive bounds of event timestamps in milliseconds.
+	From int64 `json:",omitempty"`
+	To   int64 `json:",omitempty"`
+}
+
+// Validate validates the ReplaySubscriptionDeliveriesRequest.
+func (request *ReplaySubscriptionDeliveriesRequest) Validate() error {
+	if len(request.EventIDs) > 0 {
+		if request.From != 0 || request.To != 0 {
+			return errors.New("event IDs and time range cannot be specified together")
+		}
+		return nil
+	}
+	if request.To == 0 {
+		return errors.New("either event IDs or time range must be specified")
+	}
+	if request.From < 0 || request.From > request.To {
+		return errors.Errorf("invalid time range from %d to %d", request.From, request.To)
+	}
+
+	return nil
+}
+
+// NewReplaySubscriptionDeliveriesRequestFromReader will create a
+// ReplaySubscriptionDeliveriesRequest from an io.Reader with JSON data.
+func NewReplaySubscriptionDeliveriesRequestFromReader(reader io.Reader) (*ReplaySubscriptionDeliveriesRequest, error) {
+	var request ReplaySubscriptionDeliveriesRequest
+	err := json.NewDecoder(reader).Decode(&request)
+	if err != nil && err != io.EOF {
+		return nil, errors.Wrap(err, "failed to decode replay subscription deliveries request")
+	}
+
+	err = request.Validate()
+	if err != nil {
+		return nil, errors.Wrap(err, "replay subscription deliveries request failed validation")
+	}
+
+	return &request, nil
+}
+
+// ReplaySubscriptionDeliveriesResponse is a response to replay event deliveries request.
+type ReplaySubscriptionDeliveriesResponse struct {
+	Replayed int64
+}
+
+// NewReplaySubscriptionDeliveriesResponseFromReader will create a
+// ReplaySubscriptionDeliveriesResponse from an io.Reader with JSON data.
+func NewReplaySubscriptionDeliveriesResponseFromReader(reader io.Reader) (*ReplaySubscriptionDeliveriesResponse, error) {
+	var response ReplaySubscriptionDeliveriesResponse
+	err := json.NewDecoder(reader).Decode(&response)
+	if err != nil && err != io.EOF {
+		return nil, errors.Wrap(err, "failed to decode replay subscription deliveries response")
+	}
+
+	return &response, nil
+}
@@ -40,4 +40,45 @@ func TestNewCreateSubscriptionRequestFromReader(t *testing.T) {
 			FailureThreshold: 100,
 		}, createSubscriptionRequest)
 	})
+
+	t.Run("valid with filter", func(t *testing.T) {
+		createSubscriptionRequest, err := NewCreateSubscriptionRequestFromReader(bytes.NewReader([]byte(
+			`{"name":"test","url":"http://test", "ownerID":"owner","filter":{"resourceTypes":["installation"],"ownerID":"customer"}}`,
+		)))
+		require.NoError(t, err)
+		require.Equal(t, &CreateSubscriptionRequest{
+			Name:    "test",
+			URL:     "http://test",
+			OwnerID: "owner",
+			Filter: &SubscriptionFilter{
+				ResourceTypes: []ResourceType{TypeInstallation},
+				OwnerID:       "customer",
+			},
+		}, createSubscriptionRequest)
+	})
+}
+
+func TestReplaySubscriptionDeliveriesRequest_Validate(t *testing.T) {
+	for _, testCase := range []struct {
+		description string
+		request     ReplaySubscriptionDeliveriesRequest
+		valid       bool
+	}{
+		{"empty", ReplaySubscriptionDeliveriesRequest{}, false},
+		{"event IDs", ReplaySubscriptionDeliveriesRequest{EventIDs: []string{"event1"}}, true},
+		{"time range", ReplaySubscriptionDeliveriesRequest{From: 100, To: 200}, true},
+		{"time range from zero", ReplaySubscriptionDeliveriesRequest{To: 200}, true},
+		{"inverted time range", ReplaySubscriptionDeliveriesRequest{From: 200, To: 100}, false},
+		{"negative from", ReplaySubscriptionDeliveriesRequest{From: -1, To: 100}, false},
+		{"event IDs and time range", ReplaySubscriptionDeliveriesRequest{EventIDs: []string{"event1"}, From: 100, To: 200}, false},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			err := testCase.request.Validate()
+			if testCase.valid {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
 }
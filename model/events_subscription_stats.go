@@ -0,0 +1,81 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// SubscriptionStats summarizes event deliveries of a subscription.
+type SubscriptionStats struct {
+	SubscriptionID        string
+	LastDeliveryStatus    SubscriptionDeliveryStatus
+	LastDeliveryAttemptAt int64
+	Paused                bool
+	// Backlog is the number of events awaiting delivery, including the
+	// events which delivery is being retried.
+	Backlog   int64
+	Retrying  int64
+	Delivered int64
+	Failed    int64
+	// OldestUndeliveredEventAt is the timestamp of the oldest event
+	// awaiting delivery or 0 if there is no backlog.
+	OldestUndeliveredEventAt int64
+}
+
+// NewSubscriptionStats creates empty SubscriptionStats of the subscription.
+func NewSubscriptionStats(sub *Subscription) *SubscriptionStats {
+	return &SubscriptionStats{
+		SubscriptionID:        sub.ID,
+		LastDeliveryStatus:    sub.LastDeliveryStatus,
+		LastDeliveryAttemptAt: sub.LastDeliveryAttemptAt,
+		Paused:                sub.Paused,
+	}
+}
+
+// AddDeliveries adds count of event deliveries with given status to the
+// stats. The oldestEventAt is the timestamp of the oldest event among them.
+func (s *SubscriptionStats) AddDeliveries(status EventDeliveryStatus, count, oldestEventAt int64) {
+	switch status {
+	case EventDeliveryDelivered:
+		s.Delivered += count
+		return
+	case EventDeliveryFailed:
+		s.Failed += count
+		return
+	case EventDeliveryRetrying:
+		s.Retrying += count
+	}
+
+	s.Backlog += count
+	if count > 0 && (s.OldestUndeliveredEventAt == 0 || oldestEventAt < s.OldestUndeliveredEventAt) {
+		s.OldestUndeliveredEventAt = oldestEventAt
+	}
+}
+
+// OldestUndeliveredEventAge returns the age of the oldest event awaiting
+// delivery at the given time in milliseconds.
+func (s *SubscriptionStats) OldestUndeliveredEventAge(now int64) time.Duration {
+	if s.OldestUndeliveredEventAt == 0 || now < s.OldestUndeliveredEventAt {
+		return 0
+	}
+	return time.Duration(now-s.OldestUndeliveredEventAt) * time.Millisecond
+}
+
+// NewSubscriptionStatsFromReader will create SubscriptionStats from an
+// io.Reader with JSON data.
+func NewSubscriptionStatsFromReader(reader io.Reader) (*SubscriptionStats, error) {
+	var stats SubscriptionStats
+	err := json.NewDecoder(reader).Decode(&stats)
+	if err != nil && err != io.EOF {
+		return nil, errors.Wrap(err, "failed to decode SubscriptionStats")
+	}
+
+	return &stats, nil
+}
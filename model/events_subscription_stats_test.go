@@ -0,0 +1,76 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscriptionStats(t *testing.T) {
+	stats := NewSubscriptionStats(&Subscription{
+		ID:                    "sub",
+		LastDeliveryStatus:    SubscriptionDeliveryFailed,
+		LastDeliveryAttemptAt: 100,
+		Paused:                true,
+	})
+	assert.Equal(t, int64(0), stats.Backlog)
+	assert.Equal(t, time.Duration(0), stats.OldestUndeliveredEventAge(1000))
+
+	stats.AddDeliveries(EventDeliveryDelivered, 5, 10)
+	stats.AddDeliveries(EventDeliveryFailed, 2, 20)
+	stats.AddDeliveries(EventDeliveryRetrying, 3, 400)
+	stats.AddDeliveries(EventDeliveryNotAttempted, 4, 300)
+
+	assert.Equal(t, &SubscriptionStats{
+		SubscriptionID:           "sub",
+		LastDeliveryStatus:       SubscriptionDeliveryFailed,
+		LastDeliveryAttemptAt:    100,
+		Paused:                   true,
+		Backlog:                  7,
+		Retrying:                 3,
+		Delivered:                5,
+		Failed:                   2,
+		OldestUndeliveredEventAt: 300,
+	}, stats)
+	assert.Equal(t, 700*time.Millisecond, stats.OldestUndeliveredEventAge(1000))
+	assert.Equal(t, time.Duration(0), stats.OldestUndeliveredEventAge(200))
+}
+
+func TestNewSubscriptionStatsFromReader(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		stats, err := NewSubscriptionStatsFromReader(bytes.NewReader([]byte(
+			"",
+		)))
+		require.NoError(t, err)
+		require.Equal(t, &SubscriptionStats{}, stats)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		stats, err := NewSubscriptionStatsFromReader(bytes.NewReader([]byte(
+			"{test",
+		)))
+		require.Error(t, err)
+		require.Nil(t, stats)
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		stats, err := NewSubscriptionStatsFromReader(bytes.NewReader([]byte(
+			`{"SubscriptionID":"abcd", "Backlog":3, "Retrying":1, "Delivered":10, "OldestUndeliveredEventAt":100}`,
+		)))
+		require.NoError(t, err)
+		require.Equal(t, &SubscriptionStats{
+			SubscriptionID:           "abcd",
+			Backlog:                  3,
+			Retrying:                 1,
+			Delivered:                10,
+			OldestUndeliveredEventAt: 100,
+		}, stats)
+	})
+}
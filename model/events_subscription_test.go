@@ -8,6 +8,7 @@ import (
 	"bytes"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -76,3 +77,71 @@ func TestNewSubscriptionsFromReader(t *testing.T) {
 		}, subscriptions)
 	})
 }
+
+func TestSubscriptionFilter_Matches(t *testing.T) {
+	event := StateChangeEvent{
+		ResourceType: TypeInstallation,
+		ResourceID:   "installation",
+		OldState:     InstallationStateCreationRequested,
+		NewState:     InstallationStateStable,
+	}
+	metadata := &EventResourceMetadata{
+		OwnerID:     "owner",
+		GroupID:     "group",
+		Annotations: []string{"multi-tenant"},
+	}
+
+	for _, testCase := range []struct {
+		description string
+		filter      *SubscriptionFilter
+		metadata    *EventResourceMetadata
+		matches     bool
+	}{
+		{"nil filter", nil, nil, true},
+		{"empty filter", &SubscriptionFilter{}, nil, true},
+		{"resource type matches", &SubscriptionFilter{ResourceTypes: []ResourceType{TypeCluster, TypeInstallation}}, nil, true},
+		{"resource type does not match", &SubscriptionFilter{ResourceTypes: []ResourceType{TypeCluster}}, nil, false},
+		{"new state matches", &SubscriptionFilter{NewStates: []string{InstallationStateStable}}, nil, true},
+		{"new state does not match", &SubscriptionFilter{NewStates: []string{InstallationStateDeleted}}, nil, false},
+		{"old state does not match", &SubscriptionFilter{OldStates: []string{InstallationStateStable}}, nil, false},
+		{"owner matches", &SubscriptionFilter{OwnerID: "owner"}, metadata, true},
+		{"owner does not match", &SubscriptionFilter{OwnerID: "other"}, metadata, false},
+		{"group does not match", &SubscriptionFilter{GroupID: "other"}, metadata, false},
+		{"annotation matches", &SubscriptionFilter{Annotation: "multi-tenant"}, metadata, true},
+		{"annotation does not match", &SubscriptionFilter{Annotation: "other"}, metadata, false},
+		{"missing metadata", &SubscriptionFilter{OwnerID: "owner"}, nil, false},
+		{"all fields match", &SubscriptionFilter{
+			ResourceTypes: []ResourceType{TypeInstallation},
+			NewStates:     []string{InstallationStateStable},
+			OldStates:     []string{InstallationStateCreationRequested},
+			OwnerID:       "owner",
+			GroupID:       "group",
+			Annotation:    "multi-tenant",
+		}, metadata, true},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			assert.Equal(t, testCase.matches, testCase.filter.Matches(event, testCase.metadata))
+		})
+	}
+}
+
+func TestSubscriptionFilter_ValueScan(t *testing.T) {
+	filter := &SubscriptionFilter{
+		ResourceTypes: []ResourceType{TypeInstallation},
+		NewStates:     []string{InstallationStateStable},
+		OwnerID:       "owner",
+	}
+
+	value, err := filter.Value()
+	require.NoError(t, err)
+
+	scanned := &SubscriptionFilter{}
+	err = scanned.Scan(value)
+	require.NoError(t, err)
+	assert.Equal(t, filter, scanned)
+
+	var nilFilter *SubscriptionFilter
+	value, err = nilFilter.Value()
+	require.NoError(t, err)
+	assert.Nil(t, value)
+}
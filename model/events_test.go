@@ -53,3 +53,9 @@ func TestEventDeliveryForEvent(t *testing.T) {
 	}
 
 }
+
+func TestEventDeliveryFailureThresholdStart(t *testing.T) {
+	assert.Equal(t, int64(100), EventDelivery{}.FailureThresholdStart(100))
+	assert.Equal(t, int64(100), EventDelivery{ReplayedAt: 50}.FailureThresholdStart(100))
+	assert.Equal(t, int64(200), EventDelivery{ReplayedAt: 200}.FailureThresholdStart(100))
+}
@@ -0,0 +1,225 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// InstallationSpecUpdateEventType is event type representing update of
+	// installation specification such as version, image, size or env vars.
+	InstallationSpecUpdateEventType EventType = "installationSpecUpdate"
+	// AnnotationChangeEventType is event type representing annotations being
+	// added to or removed from a resource.
+	AnnotationChangeEventType EventType = "annotationChange"
+	// DNSRecordChangeEventType is event type representing DNS records being
+	// added to or removed from an installation.
+	DNSRecordChangeEventType EventType = "dnsRecordChange"
+	// GroupConfigChangeEventType is event type representing update of group
+	// configuration.
+	GroupConfigChangeEventType EventType = "groupConfigChange"
+	// ClusterUtilityVersionChangeEventType is event type representing change
+	// of a cluster utility version.
+	ClusterUtilityVersionChangeEventType EventType = "clusterUtilityVersionChange"
+	// ClusterUtilityUnhealthyEventType is event type representing a cluster
+	// utility becoming unhealthy.
+	ClusterUtilityUnhealthyEventType EventType = "clusterUtilityUnhealthy"
+	// LockChangeEventType is event type representing API security lock or
+	// unlock of a resource.
+	LockChangeEventType EventType = "lockChange"
+)
+
+// ValueChange represents old and new value of a changed field.
+type ValueChange struct {
+	Old string `json:"old"`
+	New string `json:"new"`
+}
+
+// InstallationSpecUpdateEventData is a payload of InstallationSpecUpdateEventType event.
+// Values of environment variables and license are not included as they may
+// contain secrets, only the fact that they changed is reported.
+type InstallationSpecUpdateEventData struct {
+	Changes        map[string]ValueChange `json:"changes,omitempty"`
+	ChangedEnvVars []string               `json:"changedEnvVars,omitempty"`
+	LicenseChanged bool                   `json:"licenseChanged,omitempty"`
+}
+
+// AnnotationChangeEventData is a payload of AnnotationChangeEventType event.
+type AnnotationChangeEventData struct {
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+}
+
+// DNSRecordChangeEventData is a payload of DNSRecordChangeEventType event.
+type DNSRecordChangeEventData struct {
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+}
+
+// GroupConfigChangeEventData is a payload of GroupConfigChangeEventType event.
+type GroupConfigChangeEventData struct {
+	Changes        map[string]ValueChange `json:"changes,omitempty"`
+	ChangedEnvVars []string               `json:"changedEnvVars,omitempty"`
+	SequenceBumped bool                   `json:"sequenceBumped,omitempty"`
+}
+
+// ClusterUtilityVersionChangeEventData is a payload of ClusterUtilityVersionChangeEventType event.
+type ClusterUtilityVersionChangeEventData struct {
+	Utility    string `json:"utility"`
+	OldVersion string `json:"oldVersion"`
+	NewVersion string `json:"newVersion"`
+}
+
+// ClusterUtilityUnhealthyEventData is a payload of ClusterUtilityUnhealthyEventType event.
+type ClusterUtilityUnhealthyEventData struct {
+	Utility    string `json:"utility"`
+	HelmStatus string `json:"helmStatus"`
+	Message    string `json:"message"`
+}
+
+// LockChangeEventData is a payload of LockChangeEventType event.
+type LockChangeEventData struct {
+	Locked bool `json:"locked"`
+}
+
+// NewInstallationSpecUpdateEventData compares two versions of installation
+// and returns the description of the changes. Returns nil if none of the
+// tracked fields changed.
+func NewInstallationSpecUpdateEventData(old, new *Installation) *InstallationSpecUpdateEventData {
+	changes := map[string]ValueChange{}
+	addValueChange(changes, "OwnerID", old.OwnerID, new.OwnerID)
+	addValueChange(changes, "Version", old.Version, new.Version)
+	addValueChange(changes, "Image", old.Image, new.Image)
+	addValueChange(changes, "Size", old.Size, new.Size)
+
+	data := &InstallationSpecUpdateEventData{
+		ChangedEnvVars: changedEnvVarNames(
+			mergeEnvVarMaps(old.MattermostEnv, old.PriorityEnv),
+			mergeEnvVarMaps(new.MattermostEnv, new.PriorityEnv),
+		),
+		LicenseChanged: old.License != new.License,
+	}
+	if len(changes) > 0 {
+		data.Changes = changes
+	}
+
+	if data.Changes == nil && len(data.ChangedEnvVars) == 0 && !data.LicenseChanged {
+		return nil
+	}
+
+	return data
+}
+
+// NewGroupConfigChangeEventData compares two versions of group and returns
+// the description of the changes. Returns nil if nothing changed.
+func NewGroupConfigChangeEventData(old, new *Group) *GroupConfigChangeEventData {
+	changes := map[string]ValueChange{}
+	addValueChange(changes, "Name", old.Name, new.Name)
+	addValueChange(changes, "Description", old.Description, new.Description)
+	addValueChange(changes, "Version", old.Version, new.Version)
+	addValueChange(changes, "Image", old.Image, new.Image)
+	addValueChange(changes, "MaxRolling", strconv.FormatInt(old.MaxRolling, 10), strconv.FormatInt(new.MaxRolling, 10))
+	if !reflect.DeepEqual(old.Scheduling, new.Scheduling) {
+		changes["Scheduling"] = ValueChange{Old: schedulingString(old.Scheduling), New: schedulingString(new.Scheduling)}
+	}
+
+	data := &GroupConfigChangeEventData{
+		ChangedEnvVars: changedEnvVarNames(old.MattermostEnv, new.MattermostEnv),
+		SequenceBumped: old.Sequence != new.Sequence,
+	}
+	if len(changes) > 0 {
+		data.Changes = changes
+	}
+
+	if data.Changes == nil && len(data.ChangedEnvVars) == 0 && !data.SequenceBumped {
+		return nil
+	}
+
+	return data
+}
+
+// NewClusterUtilityVersionChangeEventsData compares utility chart versions
+// keyed by utility name and returns a change description for each utility
+// which version changed, sorted by utility name.
+func NewClusterUtilityVersionChangeEventsData(old, new map[string]string) []*ClusterUtilityVersionChangeEventData {
+	var changes []*ClusterUtilityVersionChangeEventData
+	for utility, newVersion := range new {
+		if old[utility] != newVersion {
+			changes = append(changes, &ClusterUtilityVersionChangeEventData{
+				Utility:    utility,
+				OldVersion: old[utility],
+				NewVersion: newVersion,
+			})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Utility < changes[j].Utility
+	})
+
+	return changes
+}
+
+// MarshalEventData marshals typed event payload so that it can be stored
+// with the event.
+func MarshalEventData(data interface{}) (json.RawMessage, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal event data")
+	}
+
+	return raw, nil
+}
+
+func addValueChange(changes map[string]ValueChange, field, old, new string) {
+	if old != new {
+		changes[field] = ValueChange{Old: old, New: new}
+	}
+}
+
+func mergeEnvVarMaps(maps ...EnvVarMap) EnvVarMap {
+	merged := EnvVarMap{}
+	for _, m := range maps {
+		for k, v := range m {
+			merged[k] = v
+		}
+	}
+
+	return merged
+}
+
+// changedEnvVarNames returns sorted names of env vars that were added,
+// removed or modified.
+func changedEnvVarNames(old, new EnvVarMap) []string {
+	var names []string
+	for name, oldVar := range old {
+		newVar, ok := new[name]
+		if !ok || !reflect.DeepEqual(oldVar, newVar) {
+			names = append(names, name)
+		}
+	}
+	for name := range new {
+		if _, ok := old[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func schedulingString(s *Scheduling) string {
+	if s == nil {
+		return ""
+	}
+	data, _ := json.Marshal(s)
+
+	return string(data)
+}
@@ -0,0 +1,104 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewInstallationSpecUpdateEventData(t *testing.T) {
+	installation := &Installation{
+		OwnerID: "owner",
+		Version: "7.0.0",
+		Image:   "mattermost/mattermost-enterprise-edition",
+		Size:    "100users",
+		License: "license",
+		MattermostEnv: EnvVarMap{
+			"KEY1": {Value: "value1"},
+			"KEY2": {Value: "value2"},
+		},
+	}
+
+	t.Run("no changes", func(t *testing.T) {
+		assert.Nil(t, NewInstallationSpecUpdateEventData(installation, installation.Clone()))
+	})
+
+	t.Run("changes", func(t *testing.T) {
+		updated := installation.Clone()
+		updated.Version = "7.1.0"
+		updated.Size = "1000users"
+		updated.License = "new-license"
+		updated.MattermostEnv = EnvVarMap{
+			"KEY1": {Value: "changed"},
+			"KEY3": {Value: "value3"},
+		}
+		updated.PriorityEnv = EnvVarMap{
+			"KEY4": {Value: "value4"},
+		}
+
+		data := NewInstallationSpecUpdateEventData(installation, updated)
+		assert.Equal(t, &InstallationSpecUpdateEventData{
+			Changes: map[string]ValueChange{
+				"Version": {Old: "7.0.0", New: "7.1.0"},
+				"Size":    {Old: "100users", New: "1000users"},
+			},
+			ChangedEnvVars: []string{"KEY1", "KEY2", "KEY3", "KEY4"},
+			LicenseChanged: true,
+		}, data)
+	})
+}
+
+func TestNewGroupConfigChangeEventData(t *testing.T) {
+	group := &Group{
+		Name:       "group",
+		Version:    "7.0.0",
+		MaxRolling: 5,
+		Sequence:   1,
+	}
+
+	t.Run("no changes", func(t *testing.T) {
+		assert.Nil(t, NewGroupConfigChangeEventData(group, group.Clone()))
+	})
+
+	t.Run("changes", func(t *testing.T) {
+		updated := group.Clone()
+		updated.Version = "7.1.0"
+		updated.MaxRolling = 10
+		updated.Sequence = 2
+		updated.MattermostEnv = EnvVarMap{"KEY1": {Value: "value1"}}
+
+		data := NewGroupConfigChangeEventData(group, updated)
+		assert.Equal(t, &GroupConfigChangeEventData{
+			Changes: map[string]ValueChange{
+				"Version":    {Old: "7.0.0", New: "7.1.0"},
+				"MaxRolling": {Old: "5", New: "10"},
+			},
+			ChangedEnvVars: []string{"KEY1"},
+			SequenceBumped: true,
+		}, data)
+	})
+}
+
+func TestNewClusterUtilityVersionChangeEventsData(t *testing.T) {
+	old := map[string]string{
+		NginxCanonicalName:  "4.0.0",
+		ThanosCanonicalName: "1.0.0",
+	}
+	new := map[string]string{
+		NginxCanonicalName:     "4.1.0",
+		ThanosCanonicalName:    "1.0.0",
+		VeleroCanonicalName:    "2.0.0",
+		FluentbitCanonicalName: "0.20.0",
+	}
+
+	assert.Empty(t, NewClusterUtilityVersionChangeEventsData(old, old))
+	assert.Equal(t, []*ClusterUtilityVersionChangeEventData{
+		{Utility: FluentbitCanonicalName, OldVersion: "", NewVersion: "0.20.0"},
+		{Utility: NginxCanonicalName, OldVersion: "4.0.0", NewVersion: "4.1.0"},
+		{Utility: VeleroCanonicalName, OldVersion: "", NewVersion: "2.0.0"},
+	}, NewClusterUtilityVersionChangeEventsData(old, new))
+}
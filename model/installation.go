@@ -60,9 +60,10 @@ type Installation struct {
 	DeletionLocked             bool
 	LockAcquiredBy             *string
 	LockAcquiredAt             int64
-	GroupOverrides             map[string]string  `json:"GroupOverrides,omitempty"`
-	PodProbeOverrides          *PodProbeOverrides `json:"PodProbeOverrides,omitempty"`
-	Scheduling                 *Scheduling        `json:"Scheduling,omitempty"`
+	GroupOverrides             map[string]string        `json:"GroupOverrides,omitempty"`
+	PodProbeOverrides          *PodProbeOverrides       `json:"PodProbeOverrides,omitempty"`
+	Scheduling                 *Scheduling              `json:"Scheduling,omitempty"`
+	NetworkPolicyExceptions    *NetworkPolicyExceptions `json:"NetworkPolicyExceptions,omitempty"`
 
 	// configconfigMergedWithGroup is set when the installation configuration
 	// has been overridden with group configuration. This value can then be
@@ -154,6 +155,7 @@ type InstallationFilter struct {
 	State           string
 	DNS             string
 	Name            string
+	Size            string
 	DeletionLocked  *bool
 }
 
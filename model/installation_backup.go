@@ -127,9 +127,8 @@ func EnsureInstallationReadyForBackup(installation *Installation) error {
 func EnsureBackupRestoreCompatible(installation *Installation) error {
 	var errs []string
 
-	if installation.Database != InstallationDatabaseMultiTenantRDSPostgres &&
-		installation.Database != InstallationDatabaseSingleTenantRDSPostgres {
-		errs = append(errs, fmt.Sprintf("invalid installation database, backup-restore is supported only for Postgres database, the database type is %q", installation.Database))
+	if BackupRestoreDatabaseEngine(installation.Database) == "" {
+		errs = append(errs, fmt.Sprintf("invalid installation database, backup-restore is supported only for RDS Postgres and MySQL databases, the database type is %q", installation.Database))
 	}
 
 	if installation.Filestore == InstallationFilestoreMinioOperator {
@@ -143,6 +142,20 @@ func EnsureBackupRestoreCompatible(installation *Installation) error {
 	return nil
 }
 
+// BackupRestoreDatabaseEngine returns the database engine that the
+// backup-restore job has to use for the given installation database type or
+// an empty string if the database type does not support backup-restore.
+func BackupRestoreDatabaseEngine(databaseType string) string {
+	switch databaseType {
+	case InstallationDatabaseSingleTenantRDSPostgres, InstallationDatabaseMultiTenantRDSPostgres:
+		return DatabaseEngineTypePostgres
+	case InstallationDatabaseSingleTenantRDSMySQL, InstallationDatabaseMultiTenantRDSMySQL:
+		return DatabaseEngineTypeMySQL
+	}
+
+	return ""
+}
+
 // ValidTransitionState returns whether an installation backup can be transitioned into
 // the new state or not based on its current state.
 func (b *InstallationBackup) ValidTransitionState(newState InstallationBackupState) bool {
@@ -85,11 +85,18 @@ func TestEnsureBackupRestoreCompatible(t *testing.T) {
 			},
 		},
 		{
-			description: "invalid db",
+			description: "valid mysql installation",
 			installation: &Installation{
 				Database:  InstallationDatabaseMultiTenantRDSMySQL,
 				Filestore: InstallationFilestoreBifrost,
 			},
+		},
+		{
+			description: "invalid db",
+			installation: &Installation{
+				Database:  InstallationDatabaseMysqlOperator,
+				Filestore: InstallationFilestoreBifrost,
+			},
 			errorContains: "invalid installation database",
 		},
 		{
@@ -141,7 +148,7 @@ func TestEnsureInstallationReadyForBackup(t *testing.T) {
 			description: "invalid db",
 			installation: &Installation{
 				State:     InstallationStateHibernating,
-				Database:  InstallationDatabaseMultiTenantRDSMySQL,
+				Database:  InstallationDatabaseMysqlOperator,
 				Filestore: InstallationFilestoreBifrost,
 			},
 			errorContains: "invalid installation database",
@@ -158,3 +165,12 @@ func TestEnsureInstallationReadyForBackup(t *testing.T) {
 		})
 	}
 }
+
+func TestBackupRestoreDatabaseEngine(t *testing.T) {
+	assert.Equal(t, DatabaseEngineTypePostgres, BackupRestoreDatabaseEngine(InstallationDatabaseSingleTenantRDSPostgres))
+	assert.Equal(t, DatabaseEngineTypePostgres, BackupRestoreDatabaseEngine(InstallationDatabaseMultiTenantRDSPostgres))
+	assert.Equal(t, DatabaseEngineTypeMySQL, BackupRestoreDatabaseEngine(InstallationDatabaseSingleTenantRDSMySQL))
+	assert.Equal(t, DatabaseEngineTypeMySQL, BackupRestoreDatabaseEngine(InstallationDatabaseMultiTenantRDSMySQL))
+	assert.Empty(t, BackupRestoreDatabaseEngine(InstallationDatabaseMysqlOperator))
+	assert.Empty(t, BackupRestoreDatabaseEngine(InstallationDatabasePerseus))
+}
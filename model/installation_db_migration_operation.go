@@ -99,6 +99,24 @@ type InstallationDBMigrationFilter struct {
 	States         []InstallationDBMigrationOperationState
 }
 
+// validDBMigrations maps source database types to the database types they
+// can be migrated to. Data is moved with the backup-restore job, so only
+// migrations between databases of the same engine are supported.
+var validDBMigrations = map[string][]string{
+	InstallationDatabaseMultiTenantRDSPostgres: {
+		InstallationDatabaseMultiTenantRDSPostgres,
+	},
+	InstallationDatabaseMultiTenantRDSMySQL: {
+		InstallationDatabaseMultiTenantRDSMySQL,
+	},
+}
+
+// IsSupportedDBMigration returns true if installation database can be
+// migrated from the source database type to the destination database type.
+func IsSupportedDBMigration(source, destination string) bool {
+	return contains(validDBMigrations[source], destination)
+}
+
 // NewDBMigrationOperationFromReader will create a InstallationDBMigrationOperation from an
 // io.Reader with JSON data.
 func NewDBMigrationOperationFromReader(reader io.Reader) (*InstallationDBMigrationOperation, error) {
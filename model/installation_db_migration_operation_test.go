@@ -112,3 +112,11 @@ func TestInstallationDBMigrationOperation_ValidTransitionState(t *testing.T) {
 		})
 	}
 }
+
+func TestIsSupportedDBMigration(t *testing.T) {
+	assert.True(t, IsSupportedDBMigration(InstallationDatabaseMultiTenantRDSPostgres, InstallationDatabaseMultiTenantRDSPostgres))
+	assert.True(t, IsSupportedDBMigration(InstallationDatabaseMultiTenantRDSMySQL, InstallationDatabaseMultiTenantRDSMySQL))
+	assert.False(t, IsSupportedDBMigration(InstallationDatabaseMultiTenantRDSMySQL, InstallationDatabaseMultiTenantRDSPostgres))
+	assert.False(t, IsSupportedDBMigration(InstallationDatabaseSingleTenantRDSMySQL, InstallationDatabaseMultiTenantRDSMySQL))
+	assert.False(t, IsSupportedDBMigration(InstallationDatabaseMultiTenantRDSPostgres, InstallationDatabaseMysqlOperator))
+}
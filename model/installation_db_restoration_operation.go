@@ -16,8 +16,15 @@ type InstallationDBRestorationOperation struct {
 	ID             string
 	InstallationID string
 	BackupID       string
-	RequestAt      int64
-	State          InstallationDBRestorationState
+	// Type is the kind of restoration. Backup restorations restore data from
+	// BackupID, point-in-time restorations restore the database cluster to
+	// RestoreTime.
+	Type InstallationDBRestorationType
+	// RestoreTime is the time in milliseconds to which the database is
+	// restored by a point-in-time restoration.
+	RestoreTime int64
+	RequestAt   int64
+	State       InstallationDBRestorationState
 	// TargetInstallationState is an installation State to which installation
 	// will be transitioned when the restoration finishes successfully.
 	TargetInstallationState string
@@ -28,6 +35,16 @@ type InstallationDBRestorationOperation struct {
 	LockAcquiredAt          int64
 }
 
+// InstallationDBRestorationType represents the type of db restoration operation.
+type InstallationDBRestorationType string
+
+const (
+	// InstallationDBRestorationTypeBackup is a restoration of an installation backup.
+	InstallationDBRestorationTypeBackup InstallationDBRestorationType = "backup"
+	// InstallationDBRestorationTypePointInTime is a restoration of the installation database cluster to a point in time.
+	InstallationDBRestorationTypePointInTime InstallationDBRestorationType = "point-in-time"
+)
+
 // InstallationDBRestorationState represents the state of db restoration operation.
 type InstallationDBRestorationState string
 
@@ -87,7 +104,39 @@ func EnsureInstallationReadyForDBRestoration(installation *Installation, backup
 		return errors.Errorf("invalid installation state, only hibernated installations can be restored, state is %q", installation.State)
 	}
 
-	return EnsureBackupRestoreCompatible(installation)
+	err := EnsureBackupRestoreCompatible(installation)
+	if err != nil {
+		return err
+	}
+
+	if BackupRestoreDatabaseEngine(backup.BackedUpDatabaseType) != BackupRestoreDatabaseEngine(installation.Database) {
+		return errors.Errorf("backup of %q database cannot be restored to %q database", backup.BackedUpDatabaseType, installation.Database)
+	}
+
+	return nil
+}
+
+// EnsureInstallationReadyForPointInTimeRestoration ensures that installation database can be restored to the given time.
+func EnsureInstallationReadyForPointInTimeRestoration(installation *Installation, restoreTime int64) error {
+	if installation.Database != InstallationDatabaseSingleTenantRDSPostgres {
+		return errors.Errorf("point-in-time restoration is supported only for %s databases, the database is %q", InstallationDatabaseSingleTenantRDSPostgres, installation.Database)
+	}
+	if installation.State != InstallationStateHibernating {
+		return errors.Errorf("invalid installation state, only hibernated installations can be restored, state is %q", installation.State)
+	}
+	if restoreTime <= installation.CreateAt {
+		return errors.New("restore time must be after the installation was created")
+	}
+	if restoreTime >= GetMillis() {
+		return errors.New("restore time must be in the past")
+	}
+
+	return nil
+}
+
+// IsPointInTime returns true if the restoration restores the database to a point in time.
+func (o *InstallationDBRestorationOperation) IsPointInTime() bool {
+	return o.Type == InstallationDBRestorationTypePointInTime
 }
 
 // DetermineAfterRestorationState returns installation state that should be set after successful restoration.
@@ -102,10 +102,40 @@ func TestEnsureInstallationReadyForDBRestoration(t *testing.T) {
 				Filestore: InstallationFilestoreBifrost,
 			},
 			backup: &InstallationBackup{
-				InstallationID: "abcd",
-				State:          InstallationBackupStateBackupSucceeded,
+				InstallationID:       "abcd",
+				BackedUpDatabaseType: InstallationDatabaseMultiTenantRDSPostgres,
+				State:                InstallationBackupStateBackupSucceeded,
+			},
+		},
+		{
+			description: "valid mysql installation and backup",
+			installation: &Installation{
+				ID:        "abcd",
+				State:     InstallationStateHibernating,
+				Database:  InstallationDatabaseSingleTenantRDSMySQL,
+				Filestore: InstallationFilestoreAwsS3,
+			},
+			backup: &InstallationBackup{
+				InstallationID:       "abcd",
+				BackedUpDatabaseType: InstallationDatabaseMultiTenantRDSMySQL,
+				State:                InstallationBackupStateBackupSucceeded,
 			},
 		},
+		{
+			description: "backup database engine not matching installation",
+			installation: &Installation{
+				ID:        "abcd",
+				State:     InstallationStateHibernating,
+				Database:  InstallationDatabaseMultiTenantRDSMySQL,
+				Filestore: InstallationFilestoreBifrost,
+			},
+			backup: &InstallationBackup{
+				InstallationID:       "abcd",
+				BackedUpDatabaseType: InstallationDatabaseMultiTenantRDSPostgres,
+				State:                InstallationBackupStateBackupSucceeded,
+			},
+			errorContains: "cannot be restored",
+		},
 		{
 			description: "backup failed",
 			installation: &Installation{
@@ -168,7 +198,7 @@ func TestEnsureInstallationReadyForDBRestoration(t *testing.T) {
 			installation: &Installation{
 				ID:        "abcd",
 				State:     InstallationStateHibernating,
-				Database:  InstallationDatabaseMultiTenantRDSMySQL,
+				Database:  InstallationDatabaseMysqlOperator,
 				Filestore: InstallationFilestoreBifrost,
 			},
 			backup: &InstallationBackup{
@@ -190,6 +220,62 @@ func TestEnsureInstallationReadyForDBRestoration(t *testing.T) {
 	}
 }
 
+func TestEnsureInstallationReadyForPointInTimeRestoration(t *testing.T) {
+	now := GetMillis()
+
+	for _, testCase := range []struct {
+		description  string
+		installation *Installation
+		restoreTime  int64
+		isError      bool
+	}{
+		{
+			description:  "valid postgres installation",
+			installation: &Installation{State: InstallationStateHibernating, Database: InstallationDatabaseSingleTenantRDSPostgres, CreateAt: now - 10000},
+			restoreTime:  now - 5000,
+		},
+		{
+			description:  "single tenant mysql database",
+			installation: &Installation{State: InstallationStateHibernating, Database: InstallationDatabaseSingleTenantRDSMySQL, CreateAt: now - 10000},
+			restoreTime:  now - 5000,
+			isError:      true,
+		},
+		{
+			description:  "multi tenant database",
+			installation: &Installation{State: InstallationStateHibernating, Database: InstallationDatabaseMultiTenantRDSPostgres, CreateAt: now - 10000},
+			restoreTime:  now - 5000,
+			isError:      true,
+		},
+		{
+			description:  "installation not hibernated",
+			installation: &Installation{State: InstallationStateStable, Database: InstallationDatabaseSingleTenantRDSPostgres, CreateAt: now - 10000},
+			restoreTime:  now - 5000,
+			isError:      true,
+		},
+		{
+			description:  "restore time before installation creation",
+			installation: &Installation{State: InstallationStateHibernating, Database: InstallationDatabaseSingleTenantRDSPostgres, CreateAt: now - 10000},
+			restoreTime:  now - 20000,
+			isError:      true,
+		},
+		{
+			description:  "restore time in the future",
+			installation: &Installation{State: InstallationStateHibernating, Database: InstallationDatabaseSingleTenantRDSPostgres, CreateAt: now - 10000},
+			restoreTime:  now + 60000,
+			isError:      true,
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			err := EnsureInstallationReadyForPointInTimeRestoration(testCase.installation, testCase.restoreTime)
+			if testCase.isError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
 func TestDetermineAfterRestorationState(t *testing.T) {
 
 	for _, testCase := range []struct {
@@ -30,6 +30,38 @@ func NewInstallationDBRestorationRequestFromReader(reader io.Reader) (*Installat
 	return &restoreRequest, nil
 }
 
+// InstallationDBPointInTimeRestorationRequest represents request for restoration
+// of installation database to a point in time.
+type InstallationDBPointInTimeRestorationRequest struct {
+	InstallationID string
+	// RestoreTime is the time in milliseconds to which the database is restored.
+	RestoreTime int64
+}
+
+// NewInstallationDBPointInTimeRestorationRequestFromReader will create a InstallationDBPointInTimeRestorationRequest from an
+// io.Reader with JSON data.
+func NewInstallationDBPointInTimeRestorationRequestFromReader(reader io.Reader) (*InstallationDBPointInTimeRestorationRequest, error) {
+	var restoreRequest InstallationDBPointInTimeRestorationRequest
+	err := json.NewDecoder(reader).Decode(&restoreRequest)
+	if err != nil && err != io.EOF {
+		return nil, errors.Wrap(err, "failed to decode installation db point-in-time restore request")
+	}
+
+	return &restoreRequest, nil
+}
+
+// Validate validates the values of the point-in-time restoration request.
+func (request *InstallationDBPointInTimeRestorationRequest) Validate() error {
+	if request.InstallationID == "" {
+		return errors.New("installation ID must not be empty")
+	}
+	if request.RestoreTime <= 0 {
+		return errors.New("restore time must be set")
+	}
+
+	return nil
+}
+
 // GetInstallationDBRestorationOperationsRequest describes the parameters to request
 // a list of installation restoration operations.
 type GetInstallationDBRestorationOperationsRequest struct {
@@ -39,6 +39,30 @@ func TestNewInstallationDBRestorationRequestFromReader(t *testing.T) {
 	})
 }
 
+func TestNewInstallationDBPointInTimeRestorationRequestFromReader(t *testing.T) {
+	t.Run("invalid", func(t *testing.T) {
+		restorationRequest, err := NewInstallationDBPointInTimeRestorationRequestFromReader(bytes.NewReader([]byte(
+			"{test",
+		)))
+		require.Error(t, err)
+		require.Nil(t, restorationRequest)
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		restorationRequest, err := NewInstallationDBPointInTimeRestorationRequestFromReader(bytes.NewReader([]byte(
+			`{"InstallationID": "installation", "RestoreTime": 1000}`,
+		)))
+		require.NoError(t, err)
+		require.Equal(t, &InstallationDBPointInTimeRestorationRequest{InstallationID: "installation", RestoreTime: 1000}, restorationRequest)
+		assert.NoError(t, restorationRequest.Validate())
+	})
+
+	t.Run("missing restore time", func(t *testing.T) {
+		restorationRequest := &InstallationDBPointInTimeRestorationRequest{InstallationID: "installation"}
+		assert.Error(t, restorationRequest.Validate())
+	})
+}
+
 func TestGetInstallationDBRestorationOperationsRequest_ApplyToURL(t *testing.T) {
 	req := &GetInstallationDBRestorationOperationsRequest{
 		InstallationID:        "my-installation",
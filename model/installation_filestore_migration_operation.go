@@ -0,0 +1,186 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// InstallationFilestoreMigrationOperation contains information about installation's filestore migration operation.
+type InstallationFilestoreMigrationOperation struct {
+	ID             string
+	InstallationID string
+	RequestAt      int64
+	State          InstallationFilestoreMigrationOperationState
+	// SourceFilestore is the filestore type the installation is migrated from.
+	SourceFilestore string
+	// DestinationFilestore is the filestore type the installation is migrated to.
+	DestinationFilestore string
+	// ObjectsTotal and BytesTotal describe the content of the source filestore
+	// at the time the copy was started.
+	ObjectsTotal int64
+	BytesTotal   int64
+	// ObjectsCopied and BytesCopied track the progress of the copy.
+	ObjectsCopied int64
+	BytesCopied   int64
+	// ContinuationToken is the position from which the copy will resume.
+	ContinuationToken string `json:"-"`
+	CompleteAt        int64
+	DeleteAt          int64
+	LockAcquiredBy    *string
+	LockAcquiredAt    int64
+}
+
+// InstallationFilestoreMigrationOperationState represents the state of filestore migration operation.
+type InstallationFilestoreMigrationOperationState string
+
+const (
+	// InstallationFilestoreMigrationStateRequested is requested filestore migration operation.
+	InstallationFilestoreMigrationStateRequested InstallationFilestoreMigrationOperationState = "installation-filestore-migration-requested"
+	// InstallationFilestoreMigrationStateCopyInProgress is filestore migration operation that is copying objects to the destination filestore.
+	InstallationFilestoreMigrationStateCopyInProgress InstallationFilestoreMigrationOperationState = "installation-filestore-migration-copy-in-progress"
+	// InstallationFilestoreMigrationStateVerifying is filestore migration operation that is verifying the copied objects.
+	InstallationFilestoreMigrationStateVerifying InstallationFilestoreMigrationOperationState = "installation-filestore-migration-verifying"
+	// InstallationFilestoreMigrationStateFilestoreSwitch is filestore migration operation that is switching to new filestore.
+	InstallationFilestoreMigrationStateFilestoreSwitch InstallationFilestoreMigrationOperationState = "installation-filestore-migration-filestore-switch"
+	// InstallationFilestoreMigrationStateRefreshSecrets is filestore migration operation that is refreshing secrets.
+	InstallationFilestoreMigrationStateRefreshSecrets InstallationFilestoreMigrationOperationState = "installation-filestore-migration-refresh-secrets"
+	// InstallationFilestoreMigrationStateFinalizing is filestore migration operation that is finalizing the migration.
+	InstallationFilestoreMigrationStateFinalizing InstallationFilestoreMigrationOperationState = "installation-filestore-migration-finalizing"
+	// InstallationFilestoreMigrationStateFailing is filestore migration operation that is failing.
+	InstallationFilestoreMigrationStateFailing InstallationFilestoreMigrationOperationState = "installation-filestore-migration-failing"
+	// InstallationFilestoreMigrationStateSucceeded is filestore migration operation that finished with success.
+	InstallationFilestoreMigrationStateSucceeded InstallationFilestoreMigrationOperationState = "installation-filestore-migration-succeeded"
+	// InstallationFilestoreMigrationStateFailed is filestore migration operation that failed.
+	InstallationFilestoreMigrationStateFailed InstallationFilestoreMigrationOperationState = "installation-filestore-migration-failed"
+	// InstallationFilestoreMigrationStateCommitRequested is filestore migration scheduled for commit and cleanup of the source filestore.
+	InstallationFilestoreMigrationStateCommitRequested InstallationFilestoreMigrationOperationState = "installation-filestore-migration-commit-requested"
+	// InstallationFilestoreMigrationStateCommitted is filestore migration that has been committed and can no longer be rolled back.
+	InstallationFilestoreMigrationStateCommitted InstallationFilestoreMigrationOperationState = "installation-filestore-migration-committed"
+	// InstallationFilestoreMigrationStateRollbackRequested is filestore migration scheduled for rollback.
+	InstallationFilestoreMigrationStateRollbackRequested InstallationFilestoreMigrationOperationState = "installation-filestore-migration-rollback-requested"
+	// InstallationFilestoreMigrationStateRollbackFinished is filestore migration that was successfully rolled back.
+	InstallationFilestoreMigrationStateRollbackFinished InstallationFilestoreMigrationOperationState = "installation-filestore-migration-rollback-finished"
+	// InstallationFilestoreMigrationStateDeletionRequested is filestore migration scheduled for deletion.
+	InstallationFilestoreMigrationStateDeletionRequested InstallationFilestoreMigrationOperationState = "installation-filestore-migration-deletion-requested"
+	// InstallationFilestoreMigrationStateDeleted is filestore migration that has been deleted.
+	InstallationFilestoreMigrationStateDeleted InstallationFilestoreMigrationOperationState = "installation-filestore-migration-deleted"
+)
+
+// AllInstallationFilestoreMigrationOperationsStatesPendingWork is a list of all filestore migration operations states
+// that the supervisor will attempt to transition towards stable on the next "tick".
+var AllInstallationFilestoreMigrationOperationsStatesPendingWork = []InstallationFilestoreMigrationOperationState{
+	InstallationFilestoreMigrationStateRequested,
+	InstallationFilestoreMigrationStateCopyInProgress,
+	InstallationFilestoreMigrationStateVerifying,
+	InstallationFilestoreMigrationStateFilestoreSwitch,
+	InstallationFilestoreMigrationStateRefreshSecrets,
+	InstallationFilestoreMigrationStateFinalizing,
+	InstallationFilestoreMigrationStateFailing,
+	InstallationFilestoreMigrationStateCommitRequested,
+	InstallationFilestoreMigrationStateRollbackRequested,
+	InstallationFilestoreMigrationStateDeletionRequested,
+}
+
+// InstallationFilestoreMigrationFilter describes the parameters used to constrain a set of installation filestore migration operations.
+type InstallationFilestoreMigrationFilter struct {
+	Paging
+	IDs            []string
+	InstallationID string
+	States         []InstallationFilestoreMigrationOperationState
+}
+
+// validFilestoreMigrations maps source filestore types to the filestore types
+// they can be migrated to.
+// Migrations from or to the aws-multitenant-s3 filestore are not supported:
+//   - it shares its IAM user with the aws-s3 filestore of the same
+//     installation, so tearing down either one breaks the other.
+//   - it stores files in the same bucket and prefix as the bifrost filestore,
+//     so there is nothing to copy and tearing down the source would delete
+//     the files of the destination.
+var validFilestoreMigrations = map[string][]string{
+	InstallationFilestoreAwsS3: {
+		InstallationFilestoreBifrost,
+	},
+	InstallationFilestoreBifrost: {
+		InstallationFilestoreAwsS3,
+	},
+}
+
+// IsSupportedFilestoreMigration returns true if installation data can be
+// migrated from the source filestore type to the destination filestore type.
+func IsSupportedFilestoreMigration(source, destination string) bool {
+	return contains(validFilestoreMigrations[source], destination)
+}
+
+// CopyProgress returns the percentage of the source objects that have been copied.
+func (o *InstallationFilestoreMigrationOperation) CopyProgress() float64 {
+	if o.ObjectsTotal == 0 {
+		if o.State == InstallationFilestoreMigrationStateRequested {
+			return 0
+		}
+		return 100
+	}
+
+	return float64(o.ObjectsCopied) / float64(o.ObjectsTotal) * 100
+}
+
+// NewFilestoreMigrationOperationFromReader will create a InstallationFilestoreMigrationOperation from an
+// io.Reader with JSON data.
+func NewFilestoreMigrationOperationFromReader(reader io.Reader) (*InstallationFilestoreMigrationOperation, error) {
+	var filestoreMigrationOperation InstallationFilestoreMigrationOperation
+	err := json.NewDecoder(reader).Decode(&filestoreMigrationOperation)
+	if err != nil && err != io.EOF {
+		return nil, errors.Wrap(err, "failed to decode InstallationFilestoreMigrationOperation")
+	}
+
+	return &filestoreMigrationOperation, nil
+}
+
+// NewFilestoreMigrationOperationsFromReader will create a slice of FilestoreMigrationOperations from an
+// io.Reader with JSON data.
+func NewFilestoreMigrationOperationsFromReader(reader io.Reader) ([]*InstallationFilestoreMigrationOperation, error) {
+	filestoreMigrationOperations := []*InstallationFilestoreMigrationOperation{}
+	err := json.NewDecoder(reader).Decode(&filestoreMigrationOperations)
+	if err != nil && err != io.EOF {
+		return nil, errors.Wrap(err, "failed to decode FilestoreMigrationOperations")
+	}
+
+	return filestoreMigrationOperations, nil
+}
+
+// ValidTransitionState returns whether a filestore migration can be transitioned into
+// the new state or not based on its current state.
+func (o InstallationFilestoreMigrationOperation) ValidTransitionState(newState InstallationFilestoreMigrationOperationState) bool {
+	validStates, found := validInstallationFilestoreMigrationOperationTransitions[newState]
+	if !found {
+		return false
+	}
+
+	return filestoreMigrationOperationStateIn(o.State, validStates)
+}
+
+var (
+	validInstallationFilestoreMigrationOperationTransitions = map[InstallationFilestoreMigrationOperationState][]InstallationFilestoreMigrationOperationState{
+		InstallationFilestoreMigrationStateCommitRequested: {
+			InstallationFilestoreMigrationStateSucceeded,
+		},
+		InstallationFilestoreMigrationStateRollbackRequested: {
+			InstallationFilestoreMigrationStateSucceeded,
+		},
+	}
+)
+
+func filestoreMigrationOperationStateIn(state InstallationFilestoreMigrationOperationState, states []InstallationFilestoreMigrationOperationState) bool {
+	for _, s := range states {
+		if s == state {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,155 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewFilestoreMigrationOperationFromReader(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		filestoreMigrationOperation, err := NewFilestoreMigrationOperationFromReader(bytes.NewReader([]byte(
+			"",
+		)))
+		require.NoError(t, err)
+		require.Equal(t, &InstallationFilestoreMigrationOperation{}, filestoreMigrationOperation)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		filestoreMigrationOperation, err := NewFilestoreMigrationOperationFromReader(bytes.NewReader([]byte(
+			"{test",
+		)))
+		require.Error(t, err)
+		require.Nil(t, filestoreMigrationOperation)
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		filestoreMigrationOperation, err := NewFilestoreMigrationOperationFromReader(bytes.NewReader([]byte(
+			`{"ID":"id", "InstallationID": "installation", "RequestAt": 10, "State": "installation-filestore-migration-requested", "SourceFilestore": "aws-s3", "DestinationFilestore": "bifrost"}`,
+		)))
+		require.NoError(t, err)
+		require.Equal(t, &InstallationFilestoreMigrationOperation{
+			ID:                   "id",
+			InstallationID:       "installation",
+			RequestAt:            10,
+			State:                InstallationFilestoreMigrationStateRequested,
+			SourceFilestore:      InstallationFilestoreAwsS3,
+			DestinationFilestore: InstallationFilestoreBifrost,
+		}, filestoreMigrationOperation)
+	})
+}
+
+func TestNewFilestoreMigrationOperationsFromReader(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		filestoreMigrationOperations, err := NewFilestoreMigrationOperationsFromReader(bytes.NewReader([]byte(
+			"",
+		)))
+		require.NoError(t, err)
+		require.Equal(t, []*InstallationFilestoreMigrationOperation{}, filestoreMigrationOperations)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		filestoreMigrationOperations, err := NewFilestoreMigrationOperationsFromReader(bytes.NewReader([]byte(
+			"{test",
+		)))
+		require.Error(t, err)
+		require.Nil(t, filestoreMigrationOperations)
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		filestoreMigrationOperations, err := NewFilestoreMigrationOperationsFromReader(bytes.NewReader([]byte(
+			`[
+	{"ID":"id", "InstallationID": "installation", "RequestAt": 10, "State": "installation-filestore-migration-requested"},
+	{"ID":"id2", "InstallationID": "installation2", "RequestAt": 20, "State": "installation-filestore-migration-copy-in-progress"}
+]`,
+		)))
+		require.NoError(t, err)
+		require.Equal(t, []*InstallationFilestoreMigrationOperation{
+			{
+				ID:             "id",
+				InstallationID: "installation",
+				RequestAt:      10,
+				State:          InstallationFilestoreMigrationStateRequested,
+			},
+			{
+				ID:             "id2",
+				InstallationID: "installation2",
+				RequestAt:      20,
+				State:          InstallationFilestoreMigrationStateCopyInProgress,
+			},
+		}, filestoreMigrationOperations)
+	})
+}
+
+func TestInstallationFilestoreMigrationOperation_ValidTransitionState(t *testing.T) {
+	for _, testCase := range []struct {
+		oldState InstallationFilestoreMigrationOperationState
+		newState InstallationFilestoreMigrationOperationState
+		isValid  bool
+	}{
+		{
+			oldState: InstallationFilestoreMigrationStateSucceeded,
+			newState: InstallationFilestoreMigrationStateCommitRequested,
+			isValid:  true,
+		},
+		{
+			oldState: InstallationFilestoreMigrationStateSucceeded,
+			newState: InstallationFilestoreMigrationStateRollbackRequested,
+			isValid:  true,
+		},
+		{
+			oldState: InstallationFilestoreMigrationStateCopyInProgress,
+			newState: InstallationFilestoreMigrationStateRollbackRequested,
+			isValid:  false,
+		},
+		{
+			oldState: InstallationFilestoreMigrationStateCommitted,
+			newState: InstallationFilestoreMigrationStateRollbackRequested,
+			isValid:  false,
+		},
+	} {
+		t.Run(string(testCase.oldState)+" to "+string(testCase.newState), func(t *testing.T) {
+			filestoreMigration := &InstallationFilestoreMigrationOperation{State: testCase.oldState}
+
+			isValid := filestoreMigration.ValidTransitionState(testCase.newState)
+			assert.Equal(t, testCase.isValid, isValid)
+		})
+	}
+}
+
+func TestInstallationFilestoreMigrationOperation_CopyProgress(t *testing.T) {
+	t.Run("not started", func(t *testing.T) {
+		operation := &InstallationFilestoreMigrationOperation{State: InstallationFilestoreMigrationStateRequested}
+		assert.Equal(t, float64(0), operation.CopyProgress())
+	})
+
+	t.Run("empty filestore", func(t *testing.T) {
+		operation := &InstallationFilestoreMigrationOperation{State: InstallationFilestoreMigrationStateVerifying}
+		assert.Equal(t, float64(100), operation.CopyProgress())
+	})
+
+	t.Run("in progress", func(t *testing.T) {
+		operation := &InstallationFilestoreMigrationOperation{
+			State:         InstallationFilestoreMigrationStateCopyInProgress,
+			ObjectsTotal:  200,
+			ObjectsCopied: 50,
+		}
+		assert.Equal(t, float64(25), operation.CopyProgress())
+	})
+}
+
+func TestIsSupportedFilestoreMigration(t *testing.T) {
+	assert.True(t, IsSupportedFilestoreMigration(InstallationFilestoreAwsS3, InstallationFilestoreBifrost))
+	assert.False(t, IsSupportedFilestoreMigration(InstallationFilestoreAwsS3, InstallationFilestoreMultiTenantAwsS3))
+	assert.True(t, IsSupportedFilestoreMigration(InstallationFilestoreBifrost, InstallationFilestoreAwsS3))
+	assert.False(t, IsSupportedFilestoreMigration(InstallationFilestoreBifrost, InstallationFilestoreMultiTenantAwsS3))
+	assert.False(t, IsSupportedFilestoreMigration(InstallationFilestoreMultiTenantAwsS3, InstallationFilestoreBifrost))
+	assert.False(t, IsSupportedFilestoreMigration(InstallationFilestoreMultiTenantAwsS3, InstallationFilestoreAwsS3))
+	assert.False(t, IsSupportedFilestoreMigration(InstallationFilestoreMinioOperator, InstallationFilestoreBifrost))
+}
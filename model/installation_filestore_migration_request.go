@@ -0,0 +1,62 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+	"net/url"
+
+	"github.com/pkg/errors"
+)
+
+// InstallationFilestoreMigrationRequest represent request for installation filestore migration.
+type InstallationFilestoreMigrationRequest struct {
+	InstallationID string
+
+	DestinationFilestore string
+}
+
+// NewInstallationFilestoreMigrationRequestFromReader will create a InstallationFilestoreMigrationRequest from an
+// io.Reader with JSON data.
+func NewInstallationFilestoreMigrationRequestFromReader(reader io.Reader) (*InstallationFilestoreMigrationRequest, error) {
+	var installationFilestoreMigrationRequest InstallationFilestoreMigrationRequest
+	err := json.NewDecoder(reader).Decode(&installationFilestoreMigrationRequest)
+	if err != nil && err != io.EOF {
+		return nil, errors.Wrap(err, "failed to decode InstallationFilestoreMigrationRequest")
+	}
+
+	return &installationFilestoreMigrationRequest, nil
+}
+
+// Validate validates the values of an installation filestore migration request.
+func (request *InstallationFilestoreMigrationRequest) Validate() error {
+	if request.InstallationID == "" {
+		return errors.New("installation ID must not be empty")
+	}
+	if !IsSupportedFilestore(request.DestinationFilestore) {
+		return errors.Errorf("unsupported destination filestore %s", request.DestinationFilestore)
+	}
+
+	return nil
+}
+
+// GetInstallationFilestoreMigrationOperationsRequest describes the parameters to request
+// a list of installation filestore migration operations.
+type GetInstallationFilestoreMigrationOperationsRequest struct {
+	Paging
+	InstallationID string
+	State          string
+}
+
+// ApplyToURL modifies the given url to include query string parameters for the request.
+func (request *GetInstallationFilestoreMigrationOperationsRequest) ApplyToURL(u *url.URL) {
+	q := u.Query()
+	q.Add("installation", request.InstallationID)
+	q.Add("state", request.State)
+	request.Paging.AddToQuery(q)
+
+	u.RawQuery = q.Encode()
+}
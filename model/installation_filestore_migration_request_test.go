@@ -0,0 +1,58 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewInstallationFilestoreMigrationRequestFromReader(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		installationFilestoreMigrationRequest, err := NewInstallationFilestoreMigrationRequestFromReader(bytes.NewReader([]byte(
+			"",
+		)))
+		require.NoError(t, err)
+		require.Equal(t, &InstallationFilestoreMigrationRequest{}, installationFilestoreMigrationRequest)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		installationFilestoreMigrationRequest, err := NewInstallationFilestoreMigrationRequestFromReader(bytes.NewReader([]byte(
+			"{test",
+		)))
+		require.Error(t, err)
+		require.Nil(t, installationFilestoreMigrationRequest)
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		installationFilestoreMigrationRequest, err := NewInstallationFilestoreMigrationRequestFromReader(bytes.NewReader([]byte(
+			`{"InstallationID": "installation", "DestinationFilestore":"bifrost"}`,
+		)))
+		require.NoError(t, err)
+		require.Equal(t, &InstallationFilestoreMigrationRequest{
+			InstallationID:       "installation",
+			DestinationFilestore: InstallationFilestoreBifrost,
+		}, installationFilestoreMigrationRequest)
+	})
+}
+
+func TestInstallationFilestoreMigrationRequest_Validate(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		request := &InstallationFilestoreMigrationRequest{InstallationID: "installation", DestinationFilestore: InstallationFilestoreBifrost}
+		require.NoError(t, request.Validate())
+	})
+
+	t.Run("missing installation", func(t *testing.T) {
+		request := &InstallationFilestoreMigrationRequest{DestinationFilestore: InstallationFilestoreBifrost}
+		require.Error(t, request.Validate())
+	})
+
+	t.Run("unknown filestore", func(t *testing.T) {
+		request := &InstallationFilestoreMigrationRequest{InstallationID: "installation", DestinationFilestore: "unknown"}
+		require.Error(t, request.Validate())
+	})
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"io"
+	"net"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// NetworkPolicyExceptions is the traffic allowed to and from the pods of an
+// installation on top of the isolation NetworkPolicies managed by the
+// provisioner.
+type NetworkPolicyExceptions struct {
+	// IngressNamespaces are additional namespaces allowed to reach the
+	// installation.
+	IngressNamespaces []string `json:"IngressNamespaces,omitempty"`
+	// Egress are additional destinations the installation is allowed to
+	// reach.
+	Egress []NetworkPolicyEgressException `json:"Egress,omitempty"`
+}
+
+// NetworkPolicyEgressException allows the pods of an installation to reach a
+// CIDR block on a TCP port, or on every port when Port is 0.
+type NetworkPolicyEgressException struct {
+	CIDR string
+	Port int32 `json:"Port,omitempty"`
+}
+
+// Validate validates the values of network policy exceptions.
+func (e *NetworkPolicyExceptions) Validate() error {
+	if e == nil {
+		return nil
+	}
+	for _, namespace := range e.IngressNamespaces {
+		if errs := validation.IsDNS1123Label(namespace); len(errs) > 0 {
+			return errors.Errorf("invalid ingress namespace %q: %s", namespace, errs[0])
+		}
+	}
+	for _, egress := range e.Egress {
+		_, _, err := net.ParseCIDR(egress.CIDR)
+		if err != nil {
+			return errors.Wrapf(err, "invalid egress CIDR %q", egress.CIDR)
+		}
+		if egress.Port < 0 || egress.Port > 65535 {
+			return errors.Errorf("invalid egress port %d", egress.Port)
+		}
+	}
+
+	return nil
+}
+
+// IsEmpty returns true if no exception is defined.
+func (e *NetworkPolicyExceptions) IsEmpty() bool {
+	return e == nil || (len(e.IngressNamespaces) == 0 && len(e.Egress) == 0)
+}
+
+// NetworkPolicyExceptionsFromReader decodes json-encoded network policy
+// exceptions from the given io.Reader.
+func NetworkPolicyExceptionsFromReader(reader io.Reader) (*NetworkPolicyExceptions, error) {
+	exceptions := &NetworkPolicyExceptions{}
+	decoder := json.NewDecoder(reader)
+	err := decoder.Decode(exceptions)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return exceptions, nil
+}
+
+// Value implements the driver.Valuer interface for database storage
+func (e *NetworkPolicyExceptions) Value() (driver.Value, error) {
+	if e == nil {
+		return nil, nil
+	}
+	return json.Marshal(e)
+}
+
+// Scan implements the sql.Scanner interface for database retrieval
+func (e *NetworkPolicyExceptions) Scan(src interface{}) error {
+	if src == nil {
+		return nil
+	}
+
+	source, ok := src.([]byte)
+	if !ok {
+		return errors.New("could not assert type of NetworkPolicyExceptions")
+	}
+
+	var exceptions NetworkPolicyExceptions
+	err := json.Unmarshal(source, &exceptions)
+	if err != nil {
+		return err
+	}
+	*e = exceptions
+	return nil
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNetworkPolicyExceptionsValidate(t *testing.T) {
+	var exceptions *NetworkPolicyExceptions
+	require.NoError(t, exceptions.Validate())
+
+	exceptions = &NetworkPolicyExceptions{
+		IngressNamespaces: []string{"monitoring"},
+		Egress:            []NetworkPolicyEgressException{{CIDR: "10.0.0.0/8", Port: 443}, {CIDR: "192.168.0.1/32"}},
+	}
+	require.NoError(t, exceptions.Validate())
+
+	for name, invalid := range map[string]*NetworkPolicyExceptions{
+		"invalid namespace": {IngressNamespaces: []string{"Monitoring_NS"}},
+		"invalid CIDR":      {Egress: []NetworkPolicyEgressException{{CIDR: "10.0.0.0"}}},
+		"negative port":     {Egress: []NetworkPolicyEgressException{{CIDR: "10.0.0.0/8", Port: -1}}},
+		"port too high":     {Egress: []NetworkPolicyEgressException{{CIDR: "10.0.0.0/8", Port: 65536}}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Error(t, invalid.Validate())
+		})
+	}
+}
+
+func TestNetworkPolicyExceptionsIsEmpty(t *testing.T) {
+	var exceptions *NetworkPolicyExceptions
+	assert.True(t, exceptions.IsEmpty())
+	assert.True(t, (&NetworkPolicyExceptions{}).IsEmpty())
+	assert.False(t, (&NetworkPolicyExceptions{IngressNamespaces: []string{"monitoring"}}).IsEmpty())
+	assert.False(t, (&NetworkPolicyExceptions{Egress: []NetworkPolicyEgressException{{CIDR: "10.0.0.0/8"}}}).IsEmpty())
+}
+
+func TestNetworkPolicyExceptionsDatabase(t *testing.T) {
+	exceptions := &NetworkPolicyExceptions{
+		IngressNamespaces: []string{"monitoring"},
+		Egress:            []NetworkPolicyEgressException{{CIDR: "10.0.0.0/8", Port: 443}},
+	}
+
+	value, err := exceptions.Value()
+	require.NoError(t, err)
+
+	scanned := &NetworkPolicyExceptions{}
+	require.NoError(t, scanned.Scan(value))
+	assert.Equal(t, exceptions, scanned)
+
+	value, err = (*NetworkPolicyExceptions)(nil).Value()
+	require.NoError(t, err)
+	assert.Nil(t, value)
+
+	assert.Error(t, scanned.Scan("invalid"))
+}
+
+func TestNetworkPolicyExceptionsFromReader(t *testing.T) {
+	exceptions, err := NetworkPolicyExceptionsFromReader(bytes.NewReader([]byte(
+		`{"IngressNamespaces":["monitoring"],"Egress":[{"CIDR":"10.0.0.0/8","Port":443}]}`,
+	)))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"monitoring"}, exceptions.IngressNamespaces)
+	require.Len(t, exceptions.Egress, 1)
+	assert.Equal(t, int32(443), exceptions.Egress[0].Port)
+
+	exceptions, err = NetworkPolicyExceptionsFromReader(bytes.NewReader(nil))
+	require.NoError(t, err)
+	assert.True(t, exceptions.IsEmpty())
+
+	_, err = NetworkPolicyExceptionsFromReader(bytes.NewReader([]byte("{")))
+	assert.Error(t, err)
+}
@@ -65,6 +65,9 @@ type CreateInstallationRequest struct {
 	ExternalDatabaseConfig ExternalDatabaseRequest
 	PodProbeOverrides      *PodProbeOverrides
 	Command                *Commmand
+	// NetworkPolicyExceptions is the traffic allowed on top of the isolation
+	// NetworkPolicies of the installation.
+	NetworkPolicyExceptions *NetworkPolicyExceptions
 }
 
 // https://man7.org/linux/man-pages/man7/hostname.7.html
@@ -123,7 +126,7 @@ func (request *CreateInstallationRequest) Validate() error {
 		return err
 	}
 
-	_, err = GetInstallationSize(request.Size)
+	err = ValidateInstallationSize(request.Size)
 	if err != nil {
 		return errors.Wrap(err, "invalid Installation size")
 	}
@@ -148,6 +151,10 @@ func (request *CreateInstallationRequest) Validate() error {
 	if err != nil {
 		return errors.Wrap(err, "invalid priority env var settings")
 	}
+	err = request.NetworkPolicyExceptions.Validate()
+	if err != nil {
+		return errors.Wrap(err, "invalid network policy exceptions")
+	}
 
 	if requireAnnotatedInstallations {
 		if len(request.Annotations) == 0 {
@@ -354,6 +361,9 @@ type PatchInstallationRequest struct {
 	MattermostEnv     EnvVarMap
 	PodProbeOverrides *PodProbeOverrides
 	Command           *Commmand
+	// NetworkPolicyExceptions replaces the network policy exceptions of the
+	// installation. An empty value removes them.
+	NetworkPolicyExceptions *NetworkPolicyExceptions
 }
 
 // Validate validates the values of a installation patch request.
@@ -368,11 +378,15 @@ func (p *PatchInstallationRequest) Validate() error {
 		return errors.New("provided ip ranges update value was blank")
 	}
 	if p.Size != nil {
-		_, err := GetInstallationSize(*p.Size)
+		err := ValidateInstallationSize(*p.Size)
 		if err != nil {
 			return errors.Wrap(err, "invalid size")
 		}
 	}
+	err := p.NetworkPolicyExceptions.Validate()
+	if err != nil {
+		return errors.Wrap(err, "invalid network policy exceptions")
+	}
 	// EnvVarMap validation is skipped as all configurations of this now imply
 	// a specific patch action should be taken.
 
@@ -443,6 +457,14 @@ func (p *PatchInstallationRequest) Apply(installation *Installation) bool {
 		applied = true
 	}
 
+	if p.NetworkPolicyExceptions != nil {
+		installation.NetworkPolicyExceptions = p.NetworkPolicyExceptions
+		if p.NetworkPolicyExceptions.IsEmpty() {
+			installation.NetworkPolicyExceptions = nil
+		}
+		applied = true
+	}
+
 	return applied
 }
 
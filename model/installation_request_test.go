@@ -95,7 +95,7 @@ func TestCreateInstallationRequestValid(t *testing.T) {
 			&model.CreateInstallationRequest{
 				OwnerID: "owner1",
 				DNS:     "domain4321.com",
-				Size:    "jumbo",
+				Size:    "jumbo_size",
 			},
 		},
 		{
@@ -978,6 +978,35 @@ func TestPatchInstallationRequestApply(t *testing.T) {
 				},
 			},
 		},
+		// NetworkPolicyExceptions scenarios
+		{
+			"network policy exceptions only",
+			true,
+			&model.PatchInstallationRequest{
+				NetworkPolicyExceptions: &model.NetworkPolicyExceptions{
+					IngressNamespaces: []string{"monitoring"},
+				},
+			},
+			&model.Installation{},
+			&model.Installation{
+				NetworkPolicyExceptions: &model.NetworkPolicyExceptions{
+					IngressNamespaces: []string{"monitoring"},
+				},
+			},
+		},
+		{
+			"network policy exceptions removal",
+			true,
+			&model.PatchInstallationRequest{
+				NetworkPolicyExceptions: &model.NetworkPolicyExceptions{},
+			},
+			&model.Installation{
+				NetworkPolicyExceptions: &model.NetworkPolicyExceptions{
+					IngressNamespaces: []string{"monitoring"},
+				},
+			},
+			&model.Installation{},
+		},
 		// Command scenarios
 		{
 			"command only",
@@ -0,0 +1,285 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// SizeRecommendationUpsize recommends a larger size for an installation
+	// whose peak usage exceeds its requested resources.
+	SizeRecommendationUpsize = "upsize"
+	// SizeRecommendationDownsize recommends a smaller size for an
+	// installation using a small fraction of its requested resources.
+	SizeRecommendationDownsize = "downsize"
+)
+
+// DefaultSizeRecommendationCandidates is the default list of sizes
+// recommended to installations.
+var DefaultSizeRecommendationCandidates = []string{
+	"cloud10users",
+	"cloud100users",
+	"1000users",
+	"5000users",
+	"10000users",
+	"25000users",
+}
+
+// SizeRecommendationPolicy configures how installation sizes are recommended
+// from their observed usage.
+type SizeRecommendationPolicy struct {
+	// Candidates are the sizes that can be recommended.
+	Candidates []string
+	// TargetUtilization is the fraction of the requested CPU and memory that
+	// the peak usage of an installation should use.
+	TargetUtilization float64
+	// Lookback is the usage history used to recommend sizes.
+	Lookback time.Duration
+	// MinSamples is the number of usage samples required to recommend a
+	// size.
+	MinSamples int64
+}
+
+// Validate validates the values of a size recommendation policy.
+func (p *SizeRecommendationPolicy) Validate() error {
+	if len(p.Candidates) == 0 {
+		return errors.New("at least one candidate size must be provided")
+	}
+	for _, size := range p.Candidates {
+		_, err := GetInstallationSize(size)
+		if err != nil {
+			return errors.Wrapf(err, "invalid candidate size %s", size)
+		}
+	}
+	if p.TargetUtilization <= 0 || p.TargetUtilization > 1 {
+		return errors.New("target utilization must be greater than 0 and at most 1")
+	}
+	if p.Lookback <= 0 {
+		return errors.New("lookback must be greater than 0")
+	}
+	if p.MinSamples < 1 {
+		return errors.New("min samples must be at least 1")
+	}
+
+	return nil
+}
+
+// InstallationSizeRecommendation is a recommended size change of an
+// installation.
+type InstallationSizeRecommendation struct {
+	InstallationID  string
+	Name            string
+	OwnerID         string
+	CurrentSize     string
+	RecommendedSize string
+	Action          string
+	// Confidence ranges from 0 to 1. It grows with the share of the lookback
+	// window covered by usage samples and with the distance between the peak
+	// usage and the target utilization of the current size.
+	Confidence             float64
+	Reason                 string
+	PeakMilliCPU           int64
+	PeakMemoryBytes        int64
+	CurrentMilliCPU        int64
+	CurrentMemoryBytes     int64
+	RecommendedMilliCPU    int64
+	RecommendedMemoryBytes int64
+}
+
+type sizeRequests struct {
+	Size        string
+	MilliCPU    int64
+	MemoryBytes int64
+}
+
+func (r *sizeRequests) fits(milliCPU, memoryBytes int64) bool {
+	return r.MilliCPU >= milliCPU && r.MemoryBytes >= memoryBytes
+}
+
+// installationSizeRequests returns the CPU and memory requested by the pods
+// of an installation of the given size. Operator managed databases and
+// filestores run in the namespace of the installation, so they are included.
+func installationSizeRequests(installation *Installation, size string) (*sizeRequests, error) {
+	resources, err := GetInstallationSize(size)
+	if err != nil {
+		return nil, err
+	}
+
+	requests := &sizeRequests{Size: size}
+	add := func(replicas int32, cpu, memory int64) {
+		requests.MilliCPU += int64(replicas) * cpu
+		requests.MemoryBytes += int64(replicas) * memory
+	}
+	add(resources.App.Replicas, resources.App.Resources.Requests.Cpu().MilliValue(), resources.App.Resources.Requests.Memory().Value())
+	if installation.Database == InstallationDatabaseMysqlOperator {
+		add(resources.Database.Replicas, resources.Database.Resources.Requests.Cpu().MilliValue(), resources.Database.Resources.Requests.Memory().Value())
+	}
+	if installation.Filestore == InstallationFilestoreMinioOperator {
+		add(resources.Minio.Replicas, resources.Minio.Resources.Requests.Cpu().MilliValue(), resources.Minio.Resources.Requests.Memory().Value())
+	}
+
+	return requests, nil
+}
+
+// Recommend compares the configured size of an installation with its
+// observed usage and recommends the smallest candidate size fitting the peak
+// usage at the target utilization. Nil is returned when there is not enough
+// usage data or no better size.
+func (p *SizeRecommendationPolicy) Recommend(installation *Installation, summary *InstallationUsageSummary) (*InstallationSizeRecommendation, error) {
+	if summary.Samples < p.MinSamples {
+		return nil, nil
+	}
+
+	// Installations using size profiles, or sizes that can no longer be
+	// resolved, are left alone.
+	current, err := installationSizeRequests(installation, installation.Size)
+	if err != nil || current.MilliCPU == 0 || current.MemoryBytes == 0 {
+		return nil, nil
+	}
+
+	var candidates []*sizeRequests
+	for _, size := range p.Candidates {
+		candidate, err := installationSizeRequests(installation, size)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get requests of size %s", size)
+		}
+		candidates = append(candidates, candidate)
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].MilliCPU != candidates[j].MilliCPU {
+			return candidates[i].MilliCPU < candidates[j].MilliCPU
+		}
+		return candidates[i].MemoryBytes < candidates[j].MemoryBytes
+	})
+
+	requiredCPU := int64(math.Ceil(float64(summary.MaxMilliCPU) / p.TargetUtilization))
+	requiredMemory := int64(math.Ceil(float64(summary.MaxMemoryBytes) / p.TargetUtilization))
+
+	best := candidates[len(candidates)-1]
+	for _, candidate := range candidates {
+		if candidate.fits(requiredCPU, requiredMemory) {
+			best = candidate
+			break
+		}
+	}
+	if best.Size == installation.Size {
+		return nil, nil
+	}
+
+	var action string
+	if current.fits(requiredCPU, requiredMemory) {
+		if best.MilliCPU > current.MilliCPU || best.MemoryBytes > current.MemoryBytes ||
+			(best.MilliCPU == current.MilliCPU && best.MemoryBytes == current.MemoryBytes) {
+			return nil, nil
+		}
+		action = SizeRecommendationDownsize
+	} else {
+		if !best.fits(requiredCPU, requiredMemory) && !best.fits(current.MilliCPU+1, current.MemoryBytes) && !best.fits(current.MilliCPU, current.MemoryBytes+1) {
+			// No candidate is larger than the current size.
+			return nil, nil
+		}
+		action = SizeRecommendationUpsize
+	}
+
+	utilization := math.Max(
+		float64(summary.MaxMilliCPU)/float64(current.MilliCPU),
+		float64(summary.MaxMemoryBytes)/float64(current.MemoryBytes),
+	)
+	clarity := math.Min(1, math.Abs(1-utilization/p.TargetUtilization))
+	coverage := math.Min(1, float64(summary.To-summary.From)/float64(p.Lookback.Milliseconds()))
+
+	return &InstallationSizeRecommendation{
+		InstallationID:         installation.ID,
+		Name:                   installation.Name,
+		OwnerID:                installation.OwnerID,
+		CurrentSize:            installation.Size,
+		RecommendedSize:        best.Size,
+		Action:                 action,
+		Confidence:             math.Round(coverage*clarity*100) / 100,
+		Reason:                 fmt.Sprintf("peak usage is %.0f%% of the resources requested by size %s with a target of %.0f%%", utilization*100, installation.Size, p.TargetUtilization*100),
+		PeakMilliCPU:           summary.MaxMilliCPU,
+		PeakMemoryBytes:        summary.MaxMemoryBytes,
+		CurrentMilliCPU:        current.MilliCPU,
+		CurrentMemoryBytes:     current.MemoryBytes,
+		RecommendedMilliCPU:    best.MilliCPU,
+		RecommendedMemoryBytes: best.MemoryBytes,
+	}, nil
+}
+
+// RecommendInstallationSizes recommends sizes for the given installations
+// from their usage samples. Installations without a recommendation are
+// omitted.
+func (p *SizeRecommendationPolicy) RecommendInstallationSizes(installations []*Installation, samples []*InstallationUsage) ([]*InstallationSizeRecommendation, error) {
+	samplesByInstallation := map[string][]*InstallationUsage{}
+	for _, sample := range samples {
+		samplesByInstallation[sample.InstallationID] = append(samplesByInstallation[sample.InstallationID], sample)
+	}
+
+	recommendations := []*InstallationSizeRecommendation{}
+	for _, installation := range installations {
+		summary := SummarizeInstallationUsage(installation.ID, samplesByInstallation[installation.ID])
+		recommendation, err := p.Recommend(installation, summary)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to recommend size of installation %s", installation.ID)
+		}
+		if recommendation != nil {
+			recommendations = append(recommendations, recommendation)
+		}
+	}
+
+	return recommendations, nil
+}
+
+// MaintenanceWindow is a daily time window, in UTC hours, in which
+// disruptive changes may be applied automatically. The window wraps around
+// midnight when it ends before it starts and is always open when it starts
+// and ends at the same hour.
+type MaintenanceWindow struct {
+	StartHour int
+	EndHour   int
+}
+
+// Validate validates the values of a maintenance window.
+func (w *MaintenanceWindow) Validate() error {
+	if w.StartHour < 0 || w.StartHour > 23 || w.EndHour < 0 || w.EndHour > 23 {
+		return errors.New("maintenance window hours must be between 0 and 23")
+	}
+
+	return nil
+}
+
+// Contains returns true if the given time is in the maintenance window.
+func (w *MaintenanceWindow) Contains(t time.Time) bool {
+	hour := t.UTC().Hour()
+	switch {
+	case w.StartHour == w.EndHour:
+		return true
+	case w.StartHour < w.EndHour:
+		return hour >= w.StartHour && hour < w.EndHour
+	default:
+		return hour >= w.StartHour || hour < w.EndHour
+	}
+}
+
+// InstallationSizeRecommendationsFromReader decodes a json-encoded list of
+// installation size recommendations from the given io.Reader.
+func InstallationSizeRecommendationsFromReader(reader io.Reader) ([]*InstallationSizeRecommendation, error) {
+	recommendations := []*InstallationSizeRecommendation{}
+	decoder := json.NewDecoder(reader)
+	err := decoder.Decode(&recommendations)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return recommendations, nil
+}
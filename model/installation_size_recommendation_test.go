@@ -0,0 +1,191 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"bytes"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testMiB = 1024 * 1024
+
+func newTestSizeRecommendationPolicy() *SizeRecommendationPolicy {
+	return &SizeRecommendationPolicy{
+		Candidates:        DefaultSizeRecommendationCandidates,
+		TargetUtilization: 0.8,
+		Lookback:          24 * time.Hour,
+		MinSamples:        2,
+	}
+}
+
+func TestSizeRecommendationPolicyValidate(t *testing.T) {
+	require.NoError(t, newTestSizeRecommendationPolicy().Validate())
+
+	for name, mutate := range map[string]func(p *SizeRecommendationPolicy){
+		"no candidates":      func(p *SizeRecommendationPolicy) { p.Candidates = nil },
+		"invalid candidate":  func(p *SizeRecommendationPolicy) { p.Candidates = []string{"unknown"} },
+		"no target":          func(p *SizeRecommendationPolicy) { p.TargetUtilization = 0 },
+		"target above 1":     func(p *SizeRecommendationPolicy) { p.TargetUtilization = 1.5 },
+		"no lookback":        func(p *SizeRecommendationPolicy) { p.Lookback = 0 },
+		"no minimum samples": func(p *SizeRecommendationPolicy) { p.MinSamples = 0 },
+	} {
+		t.Run(name, func(t *testing.T) {
+			policy := newTestSizeRecommendationPolicy()
+			mutate(policy)
+			assert.Error(t, policy.Validate())
+		})
+	}
+}
+
+func TestSizeRecommendationPolicyRecommend(t *testing.T) {
+	policy := newTestSizeRecommendationPolicy()
+	day := policy.Lookback.Milliseconds()
+
+	summary := func(samples, span, milliCPU, memoryBytes int64) *InstallationUsageSummary {
+		return &InstallationUsageSummary{
+			Samples:        samples,
+			From:           1000,
+			To:             1000 + span,
+			MaxMilliCPU:    milliCPU,
+			MaxMemoryBytes: memoryBytes,
+		}
+	}
+	installation := func(size string) *Installation {
+		return &Installation{ID: "id1", Name: "test", OwnerID: "owner1", Size: size, Database: InstallationDatabaseMultiTenantRDSPostgres}
+	}
+
+	t.Run("downsize", func(t *testing.T) {
+		recommendation, err := policy.Recommend(installation("1000users"), summary(24, day, 10, 100*testMiB))
+		require.NoError(t, err)
+		require.NotNil(t, recommendation)
+		assert.Equal(t, "id1", recommendation.InstallationID)
+		assert.Equal(t, "owner1", recommendation.OwnerID)
+		assert.Equal(t, "1000users", recommendation.CurrentSize)
+		assert.Equal(t, "cloud10users", recommendation.RecommendedSize)
+		assert.Equal(t, SizeRecommendationDownsize, recommendation.Action)
+		assert.Equal(t, 0.76, recommendation.Confidence)
+		assert.Equal(t, int64(300), recommendation.CurrentMilliCPU)
+		assert.Equal(t, int64(20), recommendation.RecommendedMilliCPU)
+	})
+
+	t.Run("upsize with partial history", func(t *testing.T) {
+		recommendation, err := policy.Recommend(installation("cloud10users"), summary(24, day/2, 200, 400*testMiB))
+		require.NoError(t, err)
+		require.NotNil(t, recommendation)
+		assert.Equal(t, "1000users", recommendation.RecommendedSize)
+		assert.Equal(t, SizeRecommendationUpsize, recommendation.Action)
+		assert.Equal(t, 0.5, recommendation.Confidence)
+	})
+
+	t.Run("operator database resources are included", func(t *testing.T) {
+		operatorInstallation := installation("cloud10users")
+		operatorInstallation.Database = InstallationDatabaseMysqlOperator
+		operatorInstallation.Filestore = InstallationFilestoreMinioOperator
+
+		recommendation, err := policy.Recommend(operatorInstallation, summary(24, day, 200, 400*testMiB))
+		require.NoError(t, err)
+		assert.Nil(t, recommendation)
+	})
+
+	t.Run("current size fits", func(t *testing.T) {
+		recommendation, err := policy.Recommend(installation("1000users"), summary(24, day, 200, 400*testMiB))
+		require.NoError(t, err)
+		assert.Nil(t, recommendation)
+	})
+
+	t.Run("largest size already in use", func(t *testing.T) {
+		recommendation, err := policy.Recommend(installation("25000users"), summary(24, day, 100000, 100*testGiB))
+		require.NoError(t, err)
+		assert.Nil(t, recommendation)
+	})
+
+	t.Run("not enough samples", func(t *testing.T) {
+		recommendation, err := policy.Recommend(installation("1000users"), summary(1, day, 10, 100*testMiB))
+		require.NoError(t, err)
+		assert.Nil(t, recommendation)
+	})
+
+	t.Run("unknown current size", func(t *testing.T) {
+		recommendation, err := policy.Recommend(installation("unknown"), summary(24, day, 10, 100*testMiB))
+		require.NoError(t, err)
+		assert.Nil(t, recommendation)
+	})
+}
+
+func TestRecommendInstallationSizes(t *testing.T) {
+	policy := newTestSizeRecommendationPolicy()
+	installations := []*Installation{
+		{ID: "id1", Size: "1000users"},
+		{ID: "id2", Size: "1000users"},
+	}
+	samples := []*InstallationUsage{
+		{InstallationID: "id1", MilliCPU: 10, MemoryBytes: 100 * testMiB, CreateAt: 1000},
+		{InstallationID: "id2", MilliCPU: 200, MemoryBytes: 400 * testMiB, CreateAt: 1000},
+		{InstallationID: "id1", MilliCPU: 10, MemoryBytes: 100 * testMiB, CreateAt: 1000 + policy.Lookback.Milliseconds()},
+		{InstallationID: "id2", MilliCPU: 200, MemoryBytes: 400 * testMiB, CreateAt: 1000 + policy.Lookback.Milliseconds()},
+	}
+
+	recommendations, err := policy.RecommendInstallationSizes(installations, samples)
+	require.NoError(t, err)
+	require.Len(t, recommendations, 1)
+	assert.Equal(t, "id1", recommendations[0].InstallationID)
+	assert.Equal(t, "cloud10users", recommendations[0].RecommendedSize)
+}
+
+func TestMaintenanceWindowContains(t *testing.T) {
+	at := func(hour int) time.Time {
+		return time.Date(2024, 1, 1, hour, 30, 0, 0, time.UTC)
+	}
+
+	window := MaintenanceWindow{StartHour: 2, EndHour: 5}
+	assert.False(t, window.Contains(at(1)))
+	assert.True(t, window.Contains(at(2)))
+	assert.True(t, window.Contains(at(4)))
+	assert.False(t, window.Contains(at(5)))
+
+	window = MaintenanceWindow{StartHour: 22, EndHour: 2}
+	assert.True(t, window.Contains(at(23)))
+	assert.True(t, window.Contains(at(1)))
+	assert.False(t, window.Contains(at(12)))
+
+	window = MaintenanceWindow{}
+	assert.True(t, window.Contains(at(12)))
+
+	assert.Error(t, (&MaintenanceWindow{StartHour: 24}).Validate())
+}
+
+func TestGetInstallationSizeRecommendationsRequestApplyToURL(t *testing.T) {
+	u, err := url.Parse("http://localhost/api/installations/recommendations")
+	require.NoError(t, err)
+
+	request := &GetInstallationSizeRecommendationsRequest{
+		Paging:        AllPagesNotDeleted(),
+		OwnerID:       "owner1",
+		MinConfidence: 0.75,
+	}
+	request.ApplyToURL(u)
+	assert.Equal(t, "owner1", u.Query().Get("owner"))
+	assert.Equal(t, "0.75", u.Query().Get("min_confidence"))
+	assert.Equal(t, "-1", u.Query().Get("per_page"))
+}
+
+func TestInstallationSizeRecommendationsFromReader(t *testing.T) {
+	recommendations, err := InstallationSizeRecommendationsFromReader(bytes.NewReader([]byte(
+		`[{"InstallationID":"id1","RecommendedSize":"cloud10users","Confidence":0.9}]`,
+	)))
+	require.NoError(t, err)
+	require.Len(t, recommendations, 1)
+	assert.Equal(t, "cloud10users", recommendations[0].RecommendedSize)
+	assert.Equal(t, 0.9, recommendations[0].Confidence)
+
+	recommendations, err = InstallationSizeRecommendationsFromReader(bytes.NewReader(nil))
+	require.NoError(t, err)
+	assert.Empty(t, recommendations)
+}
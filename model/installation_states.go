@@ -86,6 +86,12 @@ const (
 	InstallationStateDBMigrationFailed = "db-migration-failed"
 	// InstallationStateDNSMigrationHibernating is an hibernated installation that is being migrated to different cluster.
 	InstallationStateDNSMigrationHibernating = "dns-migration-hibernated"
+	// InstallationStateFilestoreMigrationInProgress is an installation that is being migrated to different filestore.
+	InstallationStateFilestoreMigrationInProgress = "filestore-migration-in-progress"
+	// InstallationStateFilestoreMigrationRollbackInProgress is an installation that is being migrated back to original filestore.
+	InstallationStateFilestoreMigrationRollbackInProgress = "filestore-migration-rollback-in-progress"
+	// InstallationStateFilestoreMigrationFailed is an installation for which filestore migration failed.
+	InstallationStateFilestoreMigrationFailed = "filestore-migration-failed"
 )
 
 const (
@@ -129,6 +135,9 @@ var AllInstallationStates = []string{
 	InstallationStateDBRestorationFailed,
 	InstallationStateDBMigrationFailed,
 	InstallationStateDNSMigrationHibernating,
+	InstallationStateFilestoreMigrationInProgress,
+	InstallationStateFilestoreMigrationRollbackInProgress,
+	InstallationStateFilestoreMigrationFailed,
 }
 
 // AllInstallationStatesPendingWork is a list of all installation states that
@@ -262,6 +271,9 @@ var (
 		InstallationStateDNSMigrationHibernating: {
 			InstallationStateHibernating,
 		},
+		InstallationStateFilestoreMigrationInProgress: {
+			InstallationStateHibernating,
+		},
 	}
 )
 
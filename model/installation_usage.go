@@ -0,0 +1,341 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"io"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// UsageReportGroupByOwner groups the usage report by installation owner.
+	UsageReportGroupByOwner = "owner"
+	// UsageReportGroupByGroup groups the usage report by installation group.
+	UsageReportGroupByGroup = "group"
+
+	// hoursPerMonth is the average number of hours in a month used by cloud
+	// providers for monthly pricing.
+	hoursPerMonth = 730
+	bytesPerGiB   = 1024 * 1024 * 1024
+)
+
+// InstallationUsage is a sample of the resources actually used by an
+// installation at a point in time.
+type InstallationUsage struct {
+	ID             string
+	InstallationID string
+	ClusterID      string
+	PodCount       int64
+	MilliCPU       int64
+	MemoryBytes    int64
+	VolumeBytes    int64
+	FilestoreBytes int64
+	// FilestoreMeasureAt is the time FilestoreBytes was measured. Filestores
+	// are measured less often than the other resources, so it may predate
+	// CreateAt.
+	FilestoreMeasureAt      int64
+	DatabaseInstanceClasses DatabaseInstanceClasses
+	// DatabaseShare is the fraction of the database instances attributed to
+	// the installation: 1 for dedicated instances, the installation's share
+	// of the weight of a multitenant database otherwise.
+	DatabaseShare float64
+	CreateAt      int64
+}
+
+// DatabaseInstanceClasses is the list of the classes of the database
+// instances used by an installation.
+type DatabaseInstanceClasses []string
+
+// Value implements the driver.Valuer interface for database storage
+func (c DatabaseInstanceClasses) Value() (driver.Value, error) {
+	if c == nil {
+		return nil, nil
+	}
+	return json.Marshal(c)
+}
+
+// Scan implements the sql.Scanner interface for database retrieval
+func (c *DatabaseInstanceClasses) Scan(src interface{}) error {
+	if src == nil {
+		return nil
+	}
+
+	source, ok := src.([]byte)
+	if !ok {
+		return errors.New("could not assert type of DatabaseInstanceClasses")
+	}
+
+	var classes DatabaseInstanceClasses
+	err := json.Unmarshal(source, &classes)
+	if err != nil {
+		return err
+	}
+	*c = classes
+
+	return nil
+}
+
+// InstallationUsageFilter describes the parameters used to constrain a set of
+// installation usage samples.
+type InstallationUsageFilter struct {
+	InstallationID  string
+	InstallationIDs []string
+	// CreatedAfter and CreatedBefore bound the time window of the samples in
+	// milliseconds. Zero values leave the window open.
+	CreatedAfter  int64
+	CreatedBefore int64
+}
+
+// InstallationUsageSummary aggregates the usage samples of an installation
+// over a time window.
+type InstallationUsageSummary struct {
+	InstallationID          string
+	OwnerID                 string
+	GroupID                 string
+	From                    int64
+	To                      int64
+	Samples                 int64
+	AvgMilliCPU             int64
+	MaxMilliCPU             int64
+	AvgMemoryBytes          int64
+	MaxMemoryBytes          int64
+	VolumeBytes             int64
+	FilestoreBytes          int64
+	DatabaseInstanceClasses DatabaseInstanceClasses
+	DatabaseShare           float64
+	Cost                    *UsageCostEstimate   `json:",omitempty"`
+	History                 []*InstallationUsage `json:",omitempty"`
+}
+
+// SummarizeInstallationUsage aggregates the usage samples of an installation.
+// CPU and memory are averaged over the samples, while storage reports the
+// peak usage and databases the instances and share of the latest sample.
+func SummarizeInstallationUsage(installationID string, samples []*InstallationUsage) *InstallationUsageSummary {
+	summary := &InstallationUsageSummary{InstallationID: installationID}
+	if len(samples) == 0 {
+		return summary
+	}
+
+	var totalCPU, totalMemory int64
+	var latest *InstallationUsage
+	for _, sample := range samples {
+		summary.Samples++
+		totalCPU += sample.MilliCPU
+		totalMemory += sample.MemoryBytes
+		summary.MaxMilliCPU = maxInt64(summary.MaxMilliCPU, sample.MilliCPU)
+		summary.MaxMemoryBytes = maxInt64(summary.MaxMemoryBytes, sample.MemoryBytes)
+		summary.VolumeBytes = maxInt64(summary.VolumeBytes, sample.VolumeBytes)
+		summary.FilestoreBytes = maxInt64(summary.FilestoreBytes, sample.FilestoreBytes)
+
+		if summary.From == 0 || sample.CreateAt < summary.From {
+			summary.From = sample.CreateAt
+		}
+		if latest == nil || sample.CreateAt >= latest.CreateAt {
+			latest = sample
+		}
+	}
+	summary.To = latest.CreateAt
+	summary.AvgMilliCPU = totalCPU / summary.Samples
+	summary.AvgMemoryBytes = totalMemory / summary.Samples
+	summary.DatabaseInstanceClasses = latest.DatabaseInstanceClasses
+	summary.DatabaseShare = latest.DatabaseShare
+
+	return summary
+}
+
+func maxInt64(a, b int64) int64 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// UsagePriceTable is the unit prices used to estimate the cost of the
+// resources used by installations.
+type UsagePriceTable struct {
+	Currency string
+	// CPUCoreHour is the price of one CPU core used for an hour.
+	CPUCoreHour float64
+	// MemoryGiBHour is the price of one GiB of memory used for an hour.
+	MemoryGiBHour float64
+	// VolumeGiBMonth is the price of one GiB of persistent volume storage
+	// claimed for a month.
+	VolumeGiBMonth float64
+	// FilestoreGiBMonth is the price of one GiB of filestore storage used for
+	// a month.
+	FilestoreGiBMonth float64
+	// DatabaseInstanceHour is the price of a database instance running for an
+	// hour by instance class. Instances of unlisted classes are not priced.
+	DatabaseInstanceHour map[string]float64
+}
+
+// Validate validates the values of a usage price table.
+func (p *UsagePriceTable) Validate() error {
+	if p.CPUCoreHour < 0 || p.MemoryGiBHour < 0 || p.VolumeGiBMonth < 0 || p.FilestoreGiBMonth < 0 {
+		return errors.New("prices must not be negative")
+	}
+	for class, price := range p.DatabaseInstanceHour {
+		if price < 0 {
+			return errors.Errorf("price of database instance class %s must not be negative", class)
+		}
+	}
+
+	return nil
+}
+
+// UsageCostEstimate is the estimated monthly cost of the resources used by
+// one or more installations.
+type UsageCostEstimate struct {
+	Currency  string
+	CPU       float64
+	Memory    float64
+	Volume    float64
+	Filestore float64
+	Database  float64
+	Total     float64
+}
+
+// Add adds the given estimate to this one.
+func (e *UsageCostEstimate) Add(other *UsageCostEstimate) {
+	e.CPU += other.CPU
+	e.Memory += other.Memory
+	e.Volume += other.Volume
+	e.Filestore += other.Filestore
+	e.Database += other.Database
+	e.Total += other.Total
+}
+
+// EstimateMonthlyCost estimates the monthly cost of an installation if it
+// kept using the resources of the given summary for a whole month. Only the
+// installation's share of the database instances is priced.
+func (p *UsagePriceTable) EstimateMonthlyCost(summary *InstallationUsageSummary) *UsageCostEstimate {
+	estimate := &UsageCostEstimate{
+		Currency:  p.Currency,
+		CPU:       float64(summary.AvgMilliCPU) / 1000 * p.CPUCoreHour * hoursPerMonth,
+		Memory:    float64(summary.AvgMemoryBytes) / bytesPerGiB * p.MemoryGiBHour * hoursPerMonth,
+		Volume:    float64(summary.VolumeBytes) / bytesPerGiB * p.VolumeGiBMonth,
+		Filestore: float64(summary.FilestoreBytes) / bytesPerGiB * p.FilestoreGiBMonth,
+	}
+	for _, class := range summary.DatabaseInstanceClasses {
+		estimate.Database += p.DatabaseInstanceHour[class] * hoursPerMonth * summary.DatabaseShare
+	}
+	estimate.Total = estimate.CPU + estimate.Memory + estimate.Volume + estimate.Filestore + estimate.Database
+
+	return estimate
+}
+
+// UsageReport aggregates the usage of the installations of the fleet by owner
+// or group.
+type UsageReport struct {
+	GroupBy string
+	From    int64
+	To      int64
+	Entries []*UsageReportEntry
+}
+
+// UsageReportEntry is the usage of the installations of one owner or group.
+// Installations without a group are reported under an empty key. Database
+// instances shared with other installations are counted by their share.
+type UsageReportEntry struct {
+	Key               string
+	Installations     int64
+	AvgMilliCPU       int64
+	AvgMemoryBytes    int64
+	VolumeBytes       int64
+	FilestoreBytes    int64
+	DatabaseInstances float64
+	Cost              *UsageCostEstimate `json:",omitempty"`
+}
+
+// IsValidUsageReportGroupBy returns true if the usage report can be grouped
+// by the given value.
+func IsValidUsageReportGroupBy(groupBy string) bool {
+	return groupBy == UsageReportGroupByOwner || groupBy == UsageReportGroupByGroup
+}
+
+// NewUsageReport groups the given installation usage summaries by owner or
+// group. Entries are sorted by key.
+func NewUsageReport(groupBy string, from, to int64, summaries []*InstallationUsageSummary) (*UsageReport, error) {
+	if !IsValidUsageReportGroupBy(groupBy) {
+		return nil, errors.Errorf("unsupported usage report grouping %s", groupBy)
+	}
+
+	report := &UsageReport{GroupBy: groupBy, From: from, To: to, Entries: []*UsageReportEntry{}}
+	entries := map[string]*UsageReportEntry{}
+	for _, summary := range summaries {
+		key := summary.OwnerID
+		if groupBy == UsageReportGroupByGroup {
+			key = summary.GroupID
+		}
+
+		entry, ok := entries[key]
+		if !ok {
+			entry = &UsageReportEntry{Key: key}
+			entries[key] = entry
+			report.Entries = append(report.Entries, entry)
+		}
+		entry.Installations++
+		entry.AvgMilliCPU += summary.AvgMilliCPU
+		entry.AvgMemoryBytes += summary.AvgMemoryBytes
+		entry.VolumeBytes += summary.VolumeBytes
+		entry.FilestoreBytes += summary.FilestoreBytes
+		entry.DatabaseInstances += float64(len(summary.DatabaseInstanceClasses)) * summary.DatabaseShare
+		if summary.Cost != nil {
+			if entry.Cost == nil {
+				entry.Cost = &UsageCostEstimate{Currency: summary.Cost.Currency}
+			}
+			entry.Cost.Add(summary.Cost)
+		}
+	}
+
+	sort.Slice(report.Entries, func(i, j int) bool {
+		return report.Entries[i].Key < report.Entries[j].Key
+	})
+
+	return report, nil
+}
+
+// UsagePriceTableFromReader decodes a json-encoded usage price table from the
+// given io.Reader.
+func UsagePriceTableFromReader(reader io.Reader) (*UsagePriceTable, error) {
+	priceTable := UsagePriceTable{}
+	decoder := json.NewDecoder(reader)
+	err := decoder.Decode(&priceTable)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return &priceTable, nil
+}
+
+// InstallationUsageSummaryFromReader decodes a json-encoded installation
+// usage summary from the given io.Reader.
+func InstallationUsageSummaryFromReader(reader io.Reader) (*InstallationUsageSummary, error) {
+	summary := InstallationUsageSummary{}
+	decoder := json.NewDecoder(reader)
+	err := decoder.Decode(&summary)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return &summary, nil
+}
+
+// UsageReportFromReader decodes a json-encoded usage report from the given
+// io.Reader.
+func UsageReportFromReader(reader io.Reader) (*UsageReport, error) {
+	report := UsageReport{}
+	decoder := json.NewDecoder(reader)
+	err := decoder.Decode(&report)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return &report, nil
+}
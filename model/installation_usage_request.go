@@ -0,0 +1,83 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"net/url"
+	"strconv"
+)
+
+// GetInstallationUsageRequest describes the parameters to request the usage
+// of an installation.
+type GetInstallationUsageRequest struct {
+	// From and To bound the time window of the usage in milliseconds. The
+	// last day is used by default.
+	From           int64
+	To             int64
+	IncludeHistory bool
+}
+
+// ApplyToURL modifies the given url to include query string parameters for the request.
+func (request *GetInstallationUsageRequest) ApplyToURL(u *url.URL) {
+	q := u.Query()
+	applyUsageWindowToQuery(q, request.From, request.To)
+	if request.IncludeHistory {
+		q.Add("include_history", "true")
+	}
+
+	u.RawQuery = q.Encode()
+}
+
+// GetUsageReportRequest describes the parameters to request the usage report
+// of the fleet.
+type GetUsageReportRequest struct {
+	// GroupBy is either owner or group.
+	GroupBy string
+	From    int64
+	To      int64
+}
+
+// ApplyToURL modifies the given url to include query string parameters for the request.
+func (request *GetUsageReportRequest) ApplyToURL(u *url.URL) {
+	q := u.Query()
+	if request.GroupBy != "" {
+		q.Add("group_by", request.GroupBy)
+	}
+	applyUsageWindowToQuery(q, request.From, request.To)
+
+	u.RawQuery = q.Encode()
+}
+
+func applyUsageWindowToQuery(q url.Values, from, to int64) {
+	if from != 0 {
+		q.Add("from", strconv.FormatInt(from, 10))
+	}
+	if to != 0 {
+		q.Add("to", strconv.FormatInt(to, 10))
+	}
+}
+
+// GetInstallationSizeRecommendationsRequest describes the parameters to
+// request installation size recommendations.
+type GetInstallationSizeRecommendationsRequest struct {
+	Paging
+	OwnerID string
+	GroupID string
+	// MinConfidence omits recommendations with a lower confidence.
+	MinConfidence float64
+}
+
+// ApplyToURL modifies the given url to include query string parameters for the request.
+func (request *GetInstallationSizeRecommendationsRequest) ApplyToURL(u *url.URL) {
+	q := u.Query()
+	q.Add("owner", request.OwnerID)
+	q.Add("group", request.GroupID)
+	if request.MinConfidence > 0 {
+		q.Add("min_confidence", strconv.FormatFloat(request.MinConfidence, 'f', -1, 64))
+	}
+	request.Paging.AddToQuery(q)
+
+	u.RawQuery = q.Encode()
+}
@@ -0,0 +1,169 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"bytes"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testGiB = 1024 * 1024 * 1024
+
+func TestSummarizeInstallationUsage(t *testing.T) {
+	t.Run("no samples", func(t *testing.T) {
+		summary := SummarizeInstallationUsage("id1", nil)
+		assert.Equal(t, &InstallationUsageSummary{InstallationID: "id1"}, summary)
+	})
+
+	t.Run("samples", func(t *testing.T) {
+		samples := []*InstallationUsage{
+			{MilliCPU: 300, MemoryBytes: 2 * testGiB, VolumeBytes: 10, FilestoreBytes: 100, DatabaseInstanceClasses: DatabaseInstanceClasses{"db.t3.medium"}, DatabaseShare: 1, CreateAt: 2000},
+			{MilliCPU: 100, MemoryBytes: 1 * testGiB, VolumeBytes: 20, FilestoreBytes: 50, CreateAt: 1000},
+			{MilliCPU: 200, MemoryBytes: 3 * testGiB, VolumeBytes: 20, FilestoreBytes: 150, DatabaseInstanceClasses: DatabaseInstanceClasses{"db.r5.large", "db.r5.large"}, DatabaseShare: 0.1, CreateAt: 3000},
+		}
+
+		summary := SummarizeInstallationUsage("id1", samples)
+		assert.Equal(t, &InstallationUsageSummary{
+			InstallationID:          "id1",
+			From:                    1000,
+			To:                      3000,
+			Samples:                 3,
+			AvgMilliCPU:             200,
+			MaxMilliCPU:             300,
+			AvgMemoryBytes:          2 * testGiB,
+			MaxMemoryBytes:          3 * testGiB,
+			VolumeBytes:             20,
+			FilestoreBytes:          150,
+			DatabaseInstanceClasses: DatabaseInstanceClasses{"db.r5.large", "db.r5.large"},
+			DatabaseShare:           0.1,
+		}, summary)
+	})
+}
+
+func TestUsagePriceTableValidate(t *testing.T) {
+	assert.NoError(t, (&UsagePriceTable{}).Validate())
+	assert.NoError(t, (&UsagePriceTable{CPUCoreHour: 0.04, DatabaseInstanceHour: map[string]float64{"db.r5.large": 0.29}}).Validate())
+	assert.Error(t, (&UsagePriceTable{MemoryGiBHour: -1}).Validate())
+	assert.Error(t, (&UsagePriceTable{DatabaseInstanceHour: map[string]float64{"db.r5.large": -0.29}}).Validate())
+}
+
+func TestUsagePriceTableEstimateMonthlyCost(t *testing.T) {
+	priceTable := &UsagePriceTable{
+		Currency:             "USD",
+		CPUCoreHour:          0.1,
+		MemoryGiBHour:        0.01,
+		VolumeGiBMonth:       0.1,
+		FilestoreGiBMonth:    0.02,
+		DatabaseInstanceHour: map[string]float64{"db.r5.large": 0.2},
+	}
+
+	t.Run("dedicated database", func(t *testing.T) {
+		estimate := priceTable.EstimateMonthlyCost(&InstallationUsageSummary{
+			AvgMilliCPU:             500,
+			AvgMemoryBytes:          2 * testGiB,
+			VolumeBytes:             10 * testGiB,
+			FilestoreBytes:          50 * testGiB,
+			DatabaseInstanceClasses: DatabaseInstanceClasses{"db.r5.large", "db.unknown"},
+			DatabaseShare:           1,
+		})
+		assert.Equal(t, "USD", estimate.Currency)
+		assert.InDelta(t, 36.5, estimate.CPU, 0.001)
+		assert.InDelta(t, 14.6, estimate.Memory, 0.001)
+		assert.InDelta(t, 1, estimate.Volume, 0.001)
+		assert.InDelta(t, 1, estimate.Filestore, 0.001)
+		assert.InDelta(t, 146, estimate.Database, 0.001)
+		assert.InDelta(t, 199.1, estimate.Total, 0.001)
+	})
+
+	t.Run("shared database", func(t *testing.T) {
+		estimate := priceTable.EstimateMonthlyCost(&InstallationUsageSummary{
+			DatabaseInstanceClasses: DatabaseInstanceClasses{"db.r5.large", "db.r5.large"},
+			DatabaseShare:           0.05,
+		})
+		assert.InDelta(t, 14.6, estimate.Database, 0.001)
+		assert.InDelta(t, 14.6, estimate.Total, 0.001)
+	})
+}
+
+func TestNewUsageReport(t *testing.T) {
+	summaries := []*InstallationUsageSummary{
+		{InstallationID: "i1", OwnerID: "owner2", GroupID: "group1", AvgMilliCPU: 100, AvgMemoryBytes: 10, VolumeBytes: 1, FilestoreBytes: 5, Cost: &UsageCostEstimate{Currency: "USD", CPU: 1, Total: 1}},
+		{InstallationID: "i2", OwnerID: "owner1", AvgMilliCPU: 200, AvgMemoryBytes: 20, DatabaseInstanceClasses: DatabaseInstanceClasses{"db.r5.large"}, DatabaseShare: 1, Cost: &UsageCostEstimate{Currency: "USD", Database: 2, Total: 2}},
+		{InstallationID: "i4", OwnerID: "owner1", DatabaseInstanceClasses: DatabaseInstanceClasses{"db.r5.large", "db.r5.large"}, DatabaseShare: 0.25},
+		{InstallationID: "i3", OwnerID: "owner2", GroupID: "group1", AvgMilliCPU: 300, AvgMemoryBytes: 30, VolumeBytes: 2, FilestoreBytes: 5, Cost: &UsageCostEstimate{Currency: "USD", CPU: 3, Total: 3}},
+	}
+
+	t.Run("by owner", func(t *testing.T) {
+		report, err := NewUsageReport(UsageReportGroupByOwner, 1, 2, summaries)
+		require.NoError(t, err)
+		assert.Equal(t, &UsageReport{
+			GroupBy: UsageReportGroupByOwner,
+			From:    1,
+			To:      2,
+			Entries: []*UsageReportEntry{
+				{Key: "owner1", Installations: 2, AvgMilliCPU: 200, AvgMemoryBytes: 20, DatabaseInstances: 1.5, Cost: &UsageCostEstimate{Currency: "USD", Database: 2, Total: 2}},
+				{Key: "owner2", Installations: 2, AvgMilliCPU: 400, AvgMemoryBytes: 40, VolumeBytes: 3, FilestoreBytes: 10, Cost: &UsageCostEstimate{Currency: "USD", CPU: 4, Total: 4}},
+			},
+		}, report)
+	})
+
+	t.Run("by group", func(t *testing.T) {
+		report, err := NewUsageReport(UsageReportGroupByGroup, 1, 2, summaries)
+		require.NoError(t, err)
+		require.Len(t, report.Entries, 2)
+		assert.Equal(t, "", report.Entries[0].Key)
+		assert.Equal(t, int64(2), report.Entries[0].Installations)
+		assert.Equal(t, "group1", report.Entries[1].Key)
+		assert.Equal(t, int64(2), report.Entries[1].Installations)
+	})
+
+	t.Run("invalid grouping", func(t *testing.T) {
+		_, err := NewUsageReport("cluster", 1, 2, summaries)
+		assert.Error(t, err)
+	})
+}
+
+func TestDatabaseInstanceClassesValueScan(t *testing.T) {
+	classes := DatabaseInstanceClasses{"db.r5.large"}
+	value, err := classes.Value()
+	require.NoError(t, err)
+
+	var scanned DatabaseInstanceClasses
+	require.NoError(t, scanned.Scan(value))
+	assert.Equal(t, classes, scanned)
+
+	value, err = DatabaseInstanceClasses(nil).Value()
+	require.NoError(t, err)
+	assert.Nil(t, value)
+}
+
+func TestUsagePriceTableFromReader(t *testing.T) {
+	priceTable, err := UsagePriceTableFromReader(bytes.NewReader([]byte(
+		`{"Currency":"USD","CPUCoreHour":0.04,"DatabaseInstanceHour":{"db.r5.large":0.29}}`,
+	)))
+	require.NoError(t, err)
+	assert.Equal(t, &UsagePriceTable{
+		Currency:             "USD",
+		CPUCoreHour:          0.04,
+		DatabaseInstanceHour: map[string]float64{"db.r5.large": 0.29},
+	}, priceTable)
+
+	_, err = UsagePriceTableFromReader(bytes.NewReader([]byte("{")))
+	assert.Error(t, err)
+}
+
+func TestGetUsageRequestsApplyToURL(t *testing.T) {
+	u, _ := url.Parse("http://localhost/api/installation/id/usage")
+	(&GetInstallationUsageRequest{From: 10, IncludeHistory: true}).ApplyToURL(u)
+	assert.Equal(t, "from=10&include_history=true", u.RawQuery)
+
+	u, _ = url.Parse("http://localhost/api/installations/usage")
+	(&GetUsageReportRequest{GroupBy: UsageReportGroupByGroup, From: 10, To: 20}).ApplyToURL(u)
+	assert.Equal(t, "from=10&group_by=group&to=20", u.RawQuery)
+}
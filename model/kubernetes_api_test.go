@@ -0,0 +1,34 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindRemovedKubernetesAPIs(t *testing.T) {
+	resources := []KubernetesManifestResource{
+		{APIVersion: "apps/v1", Kind: "Deployment", Name: "app", Source: "release a"},
+		{APIVersion: "policy/v1beta1", Kind: "PodDisruptionBudget", Name: "pdb", Source: "release a"},
+		{APIVersion: "flowcontrol.apiserver.k8s.io/v1beta3", Kind: "FlowSchema", Name: "flow", Source: "release b"},
+	}
+
+	assert.Empty(t, FindRemovedKubernetesAPIs(resources, "1.24.17"))
+	assert.Empty(t, FindRemovedKubernetesAPIs(resources, ""))
+	assert.Equal(t, []string{
+		"release a PodDisruptionBudget/pdb uses policy/v1beta1 removed in 1.25, use policy/v1",
+	}, FindRemovedKubernetesAPIs(resources, "1.25.0"))
+	assert.Len(t, FindRemovedKubernetesAPIs(resources, "v1.32"), 2)
+	assert.Len(t, FindRemovedKubernetesAPIs(resources, "latest"), 2)
+}
+
+func TestCompareKubernetesMinorVersions(t *testing.T) {
+	assert.Zero(t, compareKubernetesMinorVersions("1.29", "1.29.4"))
+	assert.Negative(t, compareKubernetesMinorVersions("1.9", "1.29"))
+	assert.Positive(t, compareKubernetesMinorVersions("v1.30.1", "1.29"))
+	assert.Positive(t, compareKubernetesMinorVersions("2.0", "1.29"))
+}
@@ -49,6 +49,12 @@ func ParseProvisionerSize(size string) (v1alpha1.ClusterInstallationSize, error)
 		return v1alpha1.ClusterInstallationSize{}, errors.Errorf("unrecognized installation size %q", parts[0])
 	}
 
+	return applySizeReplicas(resources, parts)
+}
+
+// applySizeReplicas overrides the app replicas of the size with the optional
+// replicas segment of the size name.
+func applySizeReplicas(resources v1alpha1.ClusterInstallationSize, parts []string) (v1alpha1.ClusterInstallationSize, error) {
 	if len(parts) == 1 {
 		return resources, nil
 	}
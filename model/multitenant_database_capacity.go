@@ -0,0 +1,149 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// DefaultCapacityLookbackDays is the default number of days of installation
+// creation history used to compute multitenant database growth rates.
+const DefaultCapacityLookbackDays = 30
+
+// MultitenantDatabaseCapacity reports how full a multitenant database is and
+// when it is expected to run out of room for new installations.
+type MultitenantDatabaseCapacity struct {
+	MultitenantDatabaseID string
+	RdsClusterID          string
+	VpcID                 string
+	DatabaseType          string
+	State                 string
+	Installations         int
+	Weight                float64
+	MaxWeight             float64
+	Headroom              float64
+	// Utilization is the ratio of Weight to MaxWeight.
+	Utilization float64
+	// LogicalDatabases, MaxInstallationsPerLogicalDatabase and
+	// LogicalDatabaseFill only apply to proxy databases.
+	LogicalDatabases                   int     `json:"LogicalDatabases,omitempty"`
+	MaxInstallationsPerLogicalDatabase int64   `json:"MaxInstallationsPerLogicalDatabase,omitempty"`
+	LogicalDatabaseFill                float64 `json:"LogicalDatabaseFill,omitempty"`
+	// WeightGrowthPerDay is the weight of installations created per day over
+	// the lookback window.
+	WeightGrowthPerDay float64
+	// ProjectedExhaustionAt is the projected time in millis at which the
+	// database will reach MaxWeight. It is 0 when the database is not growing.
+	ProjectedExhaustionAt int64 `json:"ProjectedExhaustionAt,omitempty"`
+}
+
+// IsProxyDatabaseType returns true if the multitenant database type places
+// installations in logical databases.
+func IsProxyDatabaseType(databaseType string) bool {
+	return databaseType == DatabaseEngineTypePostgresProxy ||
+		databaseType == DatabaseEngineTypePostgresProxyPerseus
+}
+
+// NewMultitenantDatabaseCapacity computes the capacity of a multitenant
+// database from the installations it contains. Installations created within
+// the lookback window before now are used to compute the growth rate.
+func NewMultitenantDatabaseCapacity(database *MultitenantDatabase, installations []*Installation, logicalDatabases int, maxWeight float64, lookback time.Duration, now int64) *MultitenantDatabaseCapacity {
+	capacity := &MultitenantDatabaseCapacity{
+		MultitenantDatabaseID: database.ID,
+		RdsClusterID:          database.RdsClusterID,
+		VpcID:                 database.VpcID,
+		DatabaseType:          database.DatabaseType,
+		State:                 database.State,
+		Installations:         database.Installations.Count(),
+		MaxWeight:             maxWeight,
+	}
+
+	lookbackStart := now - lookback.Milliseconds()
+	var recentWeight float64
+	for _, installation := range installations {
+		weight := installation.GetDatabaseWeight()
+		capacity.Weight += weight
+		if installation.CreateAt >= lookbackStart {
+			recentWeight += weight
+		}
+	}
+
+	capacity.Headroom = maxWeight - capacity.Weight
+	if maxWeight > 0 {
+		capacity.Utilization = capacity.Weight / maxWeight
+	}
+
+	if IsProxyDatabaseType(database.DatabaseType) {
+		capacity.LogicalDatabases = logicalDatabases
+		capacity.MaxInstallationsPerLogicalDatabase = database.MaxInstallationsPerLogicalDatabase
+		logicalCapacity := int64(logicalDatabases) * database.MaxInstallationsPerLogicalDatabase
+		if logicalCapacity > 0 {
+			capacity.LogicalDatabaseFill = float64(capacity.Installations) / float64(logicalCapacity)
+		}
+	}
+
+	lookbackDays := lookback.Hours() / 24
+	if lookbackDays > 0 {
+		capacity.WeightGrowthPerDay = recentWeight / lookbackDays
+	}
+
+	switch {
+	case capacity.Headroom <= 0:
+		capacity.ProjectedExhaustionAt = now
+	case capacity.WeightGrowthPerDay > 0:
+		daysLeft := capacity.Headroom / capacity.WeightGrowthPerDay
+		capacity.ProjectedExhaustionAt = now + int64(daysLeft*float64(24*time.Hour/time.Millisecond))
+	}
+
+	return capacity
+}
+
+// ProjectedExhaustionDateString returns a standardized date string for the
+// projected exhaustion of the multitenant database.
+func (c *MultitenantDatabaseCapacity) ProjectedExhaustionDateString() string {
+	if c.ProjectedExhaustionAt == 0 {
+		return "n/a"
+	}
+
+	return DateStringFromMillis(c.ProjectedExhaustionAt)
+}
+
+// GetMultitenantDatabasesCapacityRequest describes the parameters to request
+// the capacity of multitenant databases.
+type GetMultitenantDatabasesCapacityRequest struct {
+	VpcID        string
+	DatabaseType string
+	LookbackDays int
+}
+
+// ApplyToURL modifies the given url to include query string parameters for the request.
+func (request *GetMultitenantDatabasesCapacityRequest) ApplyToURL(u *url.URL) {
+	q := u.Query()
+	q.Add("vpc_id", request.VpcID)
+	q.Add("database_type", request.DatabaseType)
+	if request.LookbackDays > 0 {
+		q.Add("lookback_days", strconv.Itoa(request.LookbackDays))
+	}
+
+	u.RawQuery = q.Encode()
+}
+
+// MultitenantDatabaseCapacitiesFromReader decodes a json-encoded list of
+// multitenant database capacities from the given io.Reader.
+func MultitenantDatabaseCapacitiesFromReader(reader io.Reader) ([]*MultitenantDatabaseCapacity, error) {
+	capacities := []*MultitenantDatabaseCapacity{}
+	decoder := json.NewDecoder(reader)
+
+	err := decoder.Decode(&capacities)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return capacities, nil
+}
@@ -0,0 +1,87 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewMultitenantDatabaseCapacity(t *testing.T) {
+	day := int64(24 * time.Hour / time.Millisecond)
+	now := int64(100 * day)
+
+	t.Run("no installations", func(t *testing.T) {
+		database := &MultitenantDatabase{ID: "db1", DatabaseType: DatabaseEngineTypePostgres}
+
+		capacity := NewMultitenantDatabaseCapacity(database, nil, 0, 10, 10*24*time.Hour, now)
+		assert.Equal(t, 0.0, capacity.Weight)
+		assert.Equal(t, 10.0, capacity.Headroom)
+		assert.Equal(t, 0.0, capacity.Utilization)
+		assert.Equal(t, 0.0, capacity.WeightGrowthPerDay)
+		assert.Equal(t, int64(0), capacity.ProjectedExhaustionAt)
+		assert.Equal(t, "n/a", capacity.ProjectedExhaustionDateString())
+	})
+
+	t.Run("growing database", func(t *testing.T) {
+		database := &MultitenantDatabase{
+			ID:            "db1",
+			DatabaseType:  DatabaseEngineTypePostgres,
+			Installations: MultitenantDatabaseInstallations{"a", "b", "c", "d"},
+		}
+		installations := []*Installation{
+			{ID: "a", State: InstallationStateStable, CreateAt: now - 50*day},
+			{ID: "b", State: InstallationStateHibernating, CreateAt: now - 50*day},
+			{ID: "c", State: InstallationStateStable, CreateAt: now - 5*day},
+			{ID: "d", State: InstallationStateStable, CreateAt: now - day},
+		}
+
+		capacity := NewMultitenantDatabaseCapacity(database, installations, 0, 10, 10*24*time.Hour, now)
+		assert.Equal(t, 4, capacity.Installations)
+		assert.Equal(t, 3.75, capacity.Weight)
+		assert.Equal(t, 6.25, capacity.Headroom)
+		assert.Equal(t, 0.375, capacity.Utilization)
+		assert.Equal(t, 0.2, capacity.WeightGrowthPerDay)
+		assert.InDelta(t, now+31*day+day/4, capacity.ProjectedExhaustionAt, 1)
+		assert.Zero(t, capacity.LogicalDatabases)
+	})
+
+	t.Run("full database", func(t *testing.T) {
+		database := &MultitenantDatabase{
+			ID:            "db1",
+			DatabaseType:  DatabaseEngineTypePostgres,
+			Installations: MultitenantDatabaseInstallations{"a", "b"},
+		}
+		installations := []*Installation{
+			{ID: "a", State: InstallationStateStable},
+			{ID: "b", State: InstallationStateStable},
+		}
+
+		capacity := NewMultitenantDatabaseCapacity(database, installations, 0, 2, 10*24*time.Hour, now)
+		assert.Equal(t, 0.0, capacity.Headroom)
+		assert.Equal(t, now, capacity.ProjectedExhaustionAt)
+	})
+
+	t.Run("proxy database logical database fill", func(t *testing.T) {
+		database := &MultitenantDatabase{
+			ID:                                 "db1",
+			DatabaseType:                       DatabaseEngineTypePostgresProxy,
+			Installations:                      MultitenantDatabaseInstallations{"a", "b", "c"},
+			MaxInstallationsPerLogicalDatabase: 2,
+		}
+		installations := []*Installation{
+			{ID: "a", State: InstallationStateStable},
+			{ID: "b", State: InstallationStateStable},
+			{ID: "c", State: InstallationStateStable},
+		}
+
+		capacity := NewMultitenantDatabaseCapacity(database, installations, 2, 100, 10*24*time.Hour, now)
+		assert.Equal(t, 2, capacity.LogicalDatabases)
+		assert.Equal(t, int64(2), capacity.MaxInstallationsPerLogicalDatabase)
+		assert.Equal(t, 0.75, capacity.LogicalDatabaseFill)
+	})
+}
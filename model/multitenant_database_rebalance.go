@@ -0,0 +1,343 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"io"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// MultitenantDatabaseRebalance is an approved multitenant database rebalance
+// plan that is executed as a series of installation database migrations.
+type MultitenantDatabaseRebalance struct {
+	ID                      string
+	VpcID                   string
+	DatabaseType            string
+	State                   MultitenantDatabaseRebalanceState
+	MaxConcurrentMigrations int
+	Moves                   MultitenantDatabaseRebalanceMoves
+	RequestAt               int64
+	CompleteAt              int64
+	DeleteAt                int64
+	LockAcquiredBy          *string
+	LockAcquiredAt          int64
+}
+
+// MultitenantDatabaseRebalanceState represents the state of a multitenant
+// database rebalance.
+type MultitenantDatabaseRebalanceState string
+
+const (
+	// MultitenantDatabaseRebalanceStateRequested is a rebalance that was
+	// approved and is waiting to be executed.
+	MultitenantDatabaseRebalanceStateRequested MultitenantDatabaseRebalanceState = "multitenant-database-rebalance-requested"
+	// MultitenantDatabaseRebalanceStateInProgress is a rebalance with
+	// migrations that are still being triggered or are still running.
+	MultitenantDatabaseRebalanceStateInProgress MultitenantDatabaseRebalanceState = "multitenant-database-rebalance-in-progress"
+	// MultitenantDatabaseRebalanceStateSucceeded is a rebalance where every
+	// move completed or was skipped.
+	MultitenantDatabaseRebalanceStateSucceeded MultitenantDatabaseRebalanceState = "multitenant-database-rebalance-succeeded"
+	// MultitenantDatabaseRebalanceStateFailed is a rebalance where at least
+	// one migration failed.
+	MultitenantDatabaseRebalanceStateFailed MultitenantDatabaseRebalanceState = "multitenant-database-rebalance-failed"
+)
+
+// AllMultitenantDatabaseRebalanceStatesPendingWork is a list of all
+// multitenant database rebalance states that the supervisor will attempt to
+// transition towards completion on the next "tick".
+var AllMultitenantDatabaseRebalanceStatesPendingWork = []MultitenantDatabaseRebalanceState{
+	MultitenantDatabaseRebalanceStateRequested,
+	MultitenantDatabaseRebalanceStateInProgress,
+}
+
+// MaxMultitenantDatabaseRebalanceMoveAttempts is the number of times the
+// supervisor tries to start a move before failing it.
+const MaxMultitenantDatabaseRebalanceMoveAttempts = 10
+
+// MultitenantDatabaseRebalanceMoveState represents the state of a single move
+// of a rebalance.
+type MultitenantDatabaseRebalanceMoveState string
+
+const (
+	// MultitenantDatabaseRebalanceMovePending is a move that has not been
+	// started yet.
+	MultitenantDatabaseRebalanceMovePending MultitenantDatabaseRebalanceMoveState = "pending"
+	// MultitenantDatabaseRebalanceMoveInProgress is a move with a running
+	// installation database migration.
+	MultitenantDatabaseRebalanceMoveInProgress MultitenantDatabaseRebalanceMoveState = "in-progress"
+	// MultitenantDatabaseRebalanceMoveSucceeded is a move whose migration
+	// succeeded.
+	MultitenantDatabaseRebalanceMoveSucceeded MultitenantDatabaseRebalanceMoveState = "succeeded"
+	// MultitenantDatabaseRebalanceMoveFailed is a move whose migration failed.
+	MultitenantDatabaseRebalanceMoveFailed MultitenantDatabaseRebalanceMoveState = "failed"
+	// MultitenantDatabaseRebalanceMoveSkipped is a move that was no longer
+	// valid when it was about to be started.
+	MultitenantDatabaseRebalanceMoveSkipped MultitenantDatabaseRebalanceMoveState = "skipped"
+)
+
+// MultitenantDatabaseRebalanceMove describes a single installation database
+// migration proposed by a rebalance plan.
+type MultitenantDatabaseRebalanceMove struct {
+	InstallationID        string
+	SourceDatabaseID      string
+	DestinationDatabaseID string
+	Weight                float64
+	State                 MultitenantDatabaseRebalanceMoveState `json:"State,omitempty"`
+	DBMigrationID         string                                `json:"DBMigrationID,omitempty"`
+	Message               string                                `json:"Message,omitempty"`
+	Attempts              int                                   `json:"Attempts,omitempty"`
+}
+
+// IsFinished returns true if the move will not be worked on anymore.
+func (m *MultitenantDatabaseRebalanceMove) IsFinished() bool {
+	switch m.State {
+	case MultitenantDatabaseRebalanceMoveSucceeded,
+		MultitenantDatabaseRebalanceMoveFailed,
+		MultitenantDatabaseRebalanceMoveSkipped:
+		return true
+	}
+
+	return false
+}
+
+// MultitenantDatabaseRebalanceMoves is the list of moves of a rebalance.
+type MultitenantDatabaseRebalanceMoves []*MultitenantDatabaseRebalanceMove
+
+// Value implements the driver.Valuer interface for database storage
+func (m MultitenantDatabaseRebalanceMoves) Value() (driver.Value, error) {
+	if m == nil {
+		return nil, nil
+	}
+	return json.Marshal(m)
+}
+
+// Scan implements the sql.Scanner interface for database retrieval
+func (m *MultitenantDatabaseRebalanceMoves) Scan(src interface{}) error {
+	if src == nil {
+		return nil
+	}
+
+	source, ok := src.([]byte)
+	if !ok {
+		return errors.New("could not assert type of MultitenantDatabaseRebalanceMoves")
+	}
+
+	var moves MultitenantDatabaseRebalanceMoves
+	err := json.Unmarshal(source, &moves)
+	if err != nil {
+		return err
+	}
+	*m = moves
+
+	return nil
+}
+
+// CountInProgress returns the number of moves with a running migration.
+func (m MultitenantDatabaseRebalanceMoves) CountInProgress() int {
+	var count int
+	for _, move := range m {
+		if move.State == MultitenantDatabaseRebalanceMoveInProgress {
+			count++
+		}
+	}
+
+	return count
+}
+
+// MultitenantDatabaseWeight describes the weight of a single multitenant
+// database before and after a rebalance plan is applied.
+type MultitenantDatabaseWeight struct {
+	MultitenantDatabaseID string
+	RdsClusterID          string
+	Installations         int
+	Weight                float64
+	ProjectedWeight       float64
+}
+
+// MultitenantDatabaseRebalancePlan is the proposed set of migrations that
+// evens out the weight of multitenant databases in a VPC.
+type MultitenantDatabaseRebalancePlan struct {
+	VpcID         string
+	DatabaseType  string
+	MaxWeight     float64
+	AverageWeight float64
+	Databases     []*MultitenantDatabaseWeight
+	Moves         MultitenantDatabaseRebalanceMoves
+}
+
+// MultitenantDatabaseRebalanceFilter describes the parameters used to
+// constrain a set of multitenant database rebalances.
+type MultitenantDatabaseRebalanceFilter struct {
+	Paging
+	VpcID  string
+	States []MultitenantDatabaseRebalanceState
+}
+
+// IsRebalanceMovable returns true if the installation database can be moved
+// by the multitenant database rebalancer.
+// Database migrations are only performed on hibernated installations.
+func IsRebalanceMovable(installation *Installation) bool {
+	return installation.Database == InstallationDatabaseMultiTenantRDSPostgres &&
+		installation.State == InstallationStateHibernating
+}
+
+// NewMultitenantDatabaseRebalancePlan computes the weight distribution of the
+// provided multitenant databases and greedily proposes installation moves from
+// the heaviest to the lightest database for as long as every move reduces the
+// gap between them. When the heaviest database has no installation that can
+// move to the lightest one, the next heaviest and lightest databases are
+// tried. Installations must contain every installation assigned to the
+// provided databases.
+func NewMultitenantDatabaseRebalancePlan(vpcID, databaseType string, databases []*MultitenantDatabase, installations map[string]*Installation, maxWeight float64, maxMoves int) *MultitenantDatabaseRebalancePlan {
+	plan := &MultitenantDatabaseRebalancePlan{
+		VpcID:        vpcID,
+		DatabaseType: databaseType,
+		MaxWeight:    maxWeight,
+		Databases:    []*MultitenantDatabaseWeight{},
+		Moves:        MultitenantDatabaseRebalanceMoves{},
+	}
+	if len(databases) == 0 {
+		return plan
+	}
+
+	var totalWeight float64
+	databasesByID := make(map[string]*MultitenantDatabase, len(databases))
+	movable := make(map[string][]*Installation, len(databases))
+	for _, database := range databases {
+		databasesByID[database.ID] = database
+		weight := &MultitenantDatabaseWeight{
+			MultitenantDatabaseID: database.ID,
+			RdsClusterID:          database.RdsClusterID,
+			Installations:         database.Installations.Count(),
+		}
+		for _, installationID := range database.Installations {
+			installation, ok := installations[installationID]
+			if !ok {
+				continue
+			}
+			weight.Weight += installation.GetDatabaseWeight()
+			if IsRebalanceMovable(installation) {
+				movable[database.ID] = append(movable[database.ID], installation)
+			}
+		}
+		weight.ProjectedWeight = weight.Weight
+		totalWeight += weight.Weight
+		plan.Databases = append(plan.Databases, weight)
+	}
+	plan.AverageWeight = totalWeight / float64(len(databases))
+
+	for _, candidates := range movable {
+		sort.Slice(candidates, func(i, j int) bool {
+			return candidates[i].ID < candidates[j].ID
+		})
+	}
+
+	for maxMoves <= 0 || len(plan.Moves) < maxMoves {
+		sortMultitenantDatabaseWeights(plan.Databases)
+		move := selectRebalanceMove(plan.Databases, databasesByID, movable, maxWeight)
+		if move == nil {
+			break
+		}
+		plan.Moves = append(plan.Moves, move)
+	}
+
+	sortMultitenantDatabaseWeights(plan.Databases)
+
+	return plan
+}
+
+// selectRebalanceMove returns the move of an installation from the heaviest
+// database with a movable installation to the lightest database that can
+// receive it, or nil if no move reduces the gap between two databases. The
+// weights must be sorted from the lightest to the heaviest and are updated
+// with the selected move.
+func selectRebalanceMove(weights []*MultitenantDatabaseWeight, databasesByID map[string]*MultitenantDatabase, movable map[string][]*Installation, maxWeight float64) *MultitenantDatabaseRebalanceMove {
+	for i := len(weights) - 1; i > 0; i-- {
+		source := weights[i]
+		candidates := movable[source.MultitenantDatabaseID]
+		for _, destination := range weights[:i] {
+			destinationDB := databasesByID[destination.MultitenantDatabaseID]
+			for j, candidate := range candidates {
+				weight := candidate.GetDatabaseWeight()
+				if source.ProjectedWeight-destination.ProjectedWeight <= weight {
+					continue
+				}
+				if destination.ProjectedWeight+weight >= maxWeight {
+					continue
+				}
+				if destinationDB.MigratedInstallations.Contains(candidate.ID) {
+					continue
+				}
+
+				movable[source.MultitenantDatabaseID] = append(candidates[:j:j], candidates[j+1:]...)
+				source.ProjectedWeight -= weight
+				destination.ProjectedWeight += weight
+				return &MultitenantDatabaseRebalanceMove{
+					InstallationID:        candidate.ID,
+					SourceDatabaseID:      source.MultitenantDatabaseID,
+					DestinationDatabaseID: destination.MultitenantDatabaseID,
+					Weight:                weight,
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func sortMultitenantDatabaseWeights(weights []*MultitenantDatabaseWeight) {
+	sort.SliceStable(weights, func(i, j int) bool {
+		if weights[i].ProjectedWeight == weights[j].ProjectedWeight {
+			return weights[i].MultitenantDatabaseID < weights[j].MultitenantDatabaseID
+		}
+		return weights[i].ProjectedWeight < weights[j].ProjectedWeight
+	})
+}
+
+// MultitenantDatabaseRebalanceFromReader decodes a json-encoded multitenant
+// database rebalance from the given io.Reader.
+func MultitenantDatabaseRebalanceFromReader(reader io.Reader) (*MultitenantDatabaseRebalance, error) {
+	rebalance := &MultitenantDatabaseRebalance{}
+	decoder := json.NewDecoder(reader)
+
+	err := decoder.Decode(&rebalance)
+	if err != nil && err != io.EOF {
+		return nil, errors.Wrap(err, "failed to decode multitenant database rebalance")
+	}
+
+	return rebalance, nil
+}
+
+// MultitenantDatabaseRebalancesFromReader decodes a json-encoded list of
+// multitenant database rebalances from the given io.Reader.
+func MultitenantDatabaseRebalancesFromReader(reader io.Reader) ([]*MultitenantDatabaseRebalance, error) {
+	rebalances := []*MultitenantDatabaseRebalance{}
+	decoder := json.NewDecoder(reader)
+
+	err := decoder.Decode(&rebalances)
+	if err != nil && err != io.EOF {
+		return nil, errors.Wrap(err, "failed to decode multitenant database rebalances")
+	}
+
+	return rebalances, nil
+}
+
+// MultitenantDatabaseRebalancePlanFromReader decodes a json-encoded
+// multitenant database rebalance plan from the given io.Reader.
+func MultitenantDatabaseRebalancePlanFromReader(reader io.Reader) (*MultitenantDatabaseRebalancePlan, error) {
+	plan := &MultitenantDatabaseRebalancePlan{}
+	decoder := json.NewDecoder(reader)
+
+	err := decoder.Decode(&plan)
+	if err != nil && err != io.EOF {
+		return nil, errors.Wrap(err, "failed to decode multitenant database rebalance plan")
+	}
+
+	return plan, nil
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+	"net/url"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultMaxConcurrentRebalanceMigrations is the default number of database
+// migrations a rebalance runs at the same time.
+const DefaultMaxConcurrentRebalanceMigrations = 1
+
+// MultitenantDatabaseRebalanceRequest describes the parameters used to plan
+// or execute a multitenant database rebalance.
+type MultitenantDatabaseRebalanceRequest struct {
+	VpcID                   string
+	DatabaseType            string
+	MaxMoves                int
+	MaxConcurrentMigrations int
+}
+
+// SetDefaults sets the default values for a rebalance request.
+func (request *MultitenantDatabaseRebalanceRequest) SetDefaults() {
+	if request.DatabaseType == "" {
+		request.DatabaseType = DatabaseEngineTypePostgres
+	}
+	if request.MaxConcurrentMigrations == 0 {
+		request.MaxConcurrentMigrations = DefaultMaxConcurrentRebalanceMigrations
+	}
+}
+
+// Validate validates the values of a rebalance request.
+func (request *MultitenantDatabaseRebalanceRequest) Validate() error {
+	if len(request.VpcID) == 0 {
+		return errors.New("VpcID must be set")
+	}
+	if request.DatabaseType != DatabaseEngineTypePostgres {
+		return errors.Errorf("rebalancing is only supported for %s multitenant databases", DatabaseEngineTypePostgres)
+	}
+	if request.MaxMoves < 0 {
+		return errors.New("MaxMoves must be 0 (unlimited) or greater")
+	}
+	if request.MaxConcurrentMigrations < 1 {
+		return errors.New("MaxConcurrentMigrations must be 1 or greater")
+	}
+
+	return nil
+}
+
+// ApplyToURL modifies the given url to include query string parameters for the request.
+func (request *MultitenantDatabaseRebalanceRequest) ApplyToURL(u *url.URL) {
+	q := u.Query()
+	q.Add("vpc_id", request.VpcID)
+	q.Add("database_type", request.DatabaseType)
+	q.Add("max_moves", strconv.Itoa(request.MaxMoves))
+
+	u.RawQuery = q.Encode()
+}
+
+// NewMultitenantDatabaseRebalanceRequestFromReader will create a
+// MultitenantDatabaseRebalanceRequest from an io.Reader with JSON data.
+func NewMultitenantDatabaseRebalanceRequestFromReader(reader io.Reader) (*MultitenantDatabaseRebalanceRequest, error) {
+	var rebalanceRequest MultitenantDatabaseRebalanceRequest
+	err := json.NewDecoder(reader).Decode(&rebalanceRequest)
+	if err != nil && err != io.EOF {
+		return nil, errors.Wrap(err, "failed to decode multitenant database rebalance request")
+	}
+
+	rebalanceRequest.SetDefaults()
+	err = rebalanceRequest.Validate()
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid multitenant database rebalance request")
+	}
+
+	return &rebalanceRequest, nil
+}
+
+// GetMultitenantDatabaseRebalancesRequest describes the parameters to request
+// a list of multitenant database rebalances.
+type GetMultitenantDatabaseRebalancesRequest struct {
+	Paging
+	VpcID string
+	State string
+}
+
+// ApplyToURL modifies the given url to include query string parameters for the request.
+func (request *GetMultitenantDatabaseRebalancesRequest) ApplyToURL(u *url.URL) {
+	q := u.Query()
+	q.Add("vpc_id", request.VpcID)
+	q.Add("state", request.State)
+	request.Paging.AddToQuery(q)
+
+	u.RawQuery = q.Encode()
+}
@@ -0,0 +1,54 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMultitenantDatabaseRebalanceRequestFromReader(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		request, err := NewMultitenantDatabaseRebalanceRequestFromReader(bytes.NewReader([]byte("")))
+		require.Error(t, err)
+		require.Nil(t, request)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		request, err := NewMultitenantDatabaseRebalanceRequestFromReader(bytes.NewReader([]byte("{test")))
+		require.Error(t, err)
+		require.Nil(t, request)
+	})
+
+	t.Run("defaults", func(t *testing.T) {
+		request, err := NewMultitenantDatabaseRebalanceRequestFromReader(bytes.NewReader([]byte(
+			`{"VpcID":"vpc1"}`,
+		)))
+		require.NoError(t, err)
+		require.Equal(t, &MultitenantDatabaseRebalanceRequest{
+			VpcID:                   "vpc1",
+			DatabaseType:            DatabaseEngineTypePostgres,
+			MaxConcurrentMigrations: DefaultMaxConcurrentRebalanceMigrations,
+		}, request)
+	})
+
+	t.Run("unsupported database type", func(t *testing.T) {
+		request, err := NewMultitenantDatabaseRebalanceRequestFromReader(bytes.NewReader([]byte(
+			`{"VpcID":"vpc1", "DatabaseType":"mysql"}`,
+		)))
+		require.Error(t, err)
+		require.Nil(t, request)
+	})
+
+	t.Run("negative max moves", func(t *testing.T) {
+		request, err := NewMultitenantDatabaseRebalanceRequestFromReader(bytes.NewReader([]byte(
+			`{"VpcID":"vpc1", "MaxMoves":-1}`,
+		)))
+		require.Error(t, err)
+		require.Nil(t, request)
+	})
+}
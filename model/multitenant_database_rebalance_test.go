@@ -0,0 +1,179 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMultitenantDatabaseRebalancePlan(t *testing.T) {
+	newInstallations := func(state string, ids ...string) map[string]*Installation {
+		installations := map[string]*Installation{}
+		for _, id := range ids {
+			installations[id] = &Installation{
+				ID:       id,
+				State:    state,
+				Database: InstallationDatabaseMultiTenantRDSPostgres,
+			}
+		}
+		return installations
+	}
+
+	t.Run("no databases", func(t *testing.T) {
+		plan := NewMultitenantDatabaseRebalancePlan("vpc1", DatabaseEngineTypePostgres, nil, nil, 10, 0)
+		assert.Empty(t, plan.Databases)
+		assert.Empty(t, plan.Moves)
+	})
+
+	t.Run("balance hibernating installations", func(t *testing.T) {
+		databases := []*MultitenantDatabase{
+			{ID: "db1", Installations: MultitenantDatabaseInstallations{"a", "b", "c", "d"}},
+			{ID: "db2"},
+		}
+		installations := newInstallations(InstallationStateHibernating, "a", "b", "c", "d")
+
+		plan := NewMultitenantDatabaseRebalancePlan("vpc1", DatabaseEngineTypePostgres, databases, installations, 10, 0)
+		require.Len(t, plan.Moves, 2)
+		assert.Equal(t, "a", plan.Moves[0].InstallationID)
+		assert.Equal(t, "db1", plan.Moves[0].SourceDatabaseID)
+		assert.Equal(t, "db2", plan.Moves[0].DestinationDatabaseID)
+		assert.Equal(t, HibernatingDatabaseWeight, plan.Moves[0].Weight)
+		assert.Equal(t, "b", plan.Moves[1].InstallationID)
+		assert.Equal(t, 1.5, plan.AverageWeight)
+		for _, database := range plan.Databases {
+			assert.Equal(t, 1.5, database.ProjectedWeight)
+		}
+	})
+
+	t.Run("max moves", func(t *testing.T) {
+		databases := []*MultitenantDatabase{
+			{ID: "db1", Installations: MultitenantDatabaseInstallations{"a", "b", "c", "d"}},
+			{ID: "db2"},
+		}
+		installations := newInstallations(InstallationStateHibernating, "a", "b", "c", "d")
+
+		plan := NewMultitenantDatabaseRebalancePlan("vpc1", DatabaseEngineTypePostgres, databases, installations, 10, 1)
+		require.Len(t, plan.Moves, 1)
+	})
+
+	t.Run("stable installations are not moved", func(t *testing.T) {
+		databases := []*MultitenantDatabase{
+			{ID: "db1", Installations: MultitenantDatabaseInstallations{"a", "b", "c", "d"}},
+			{ID: "db2"},
+		}
+		installations := newInstallations(InstallationStateStable, "a", "b", "c", "d")
+
+		plan := NewMultitenantDatabaseRebalancePlan("vpc1", DatabaseEngineTypePostgres, databases, installations, 10, 0)
+		assert.Empty(t, plan.Moves)
+		assert.Equal(t, 2.0, plan.AverageWeight)
+	})
+
+	t.Run("previously migrated installations are not moved back", func(t *testing.T) {
+		databases := []*MultitenantDatabase{
+			{ID: "db1", Installations: MultitenantDatabaseInstallations{"a", "b"}},
+			{ID: "db2", MigratedInstallations: MultitenantDatabaseInstallations{"a", "b"}},
+		}
+		installations := newInstallations(InstallationStateHibernating, "a", "b")
+
+		plan := NewMultitenantDatabaseRebalancePlan("vpc1", DatabaseEngineTypePostgres, databases, installations, 10, 0)
+		assert.Empty(t, plan.Moves)
+	})
+
+	t.Run("heaviest database without movable installations", func(t *testing.T) {
+		databases := []*MultitenantDatabase{
+			{ID: "db1", Installations: MultitenantDatabaseInstallations{"a", "b", "c", "d"}},
+			{ID: "db2", Installations: MultitenantDatabaseInstallations{"e", "f", "g", "h"}},
+			{ID: "db3"},
+		}
+		installations := newInstallations(InstallationStateStable, "a", "b", "c", "d")
+		for id, installation := range newInstallations(InstallationStateHibernating, "e", "f", "g", "h") {
+			installations[id] = installation
+		}
+
+		plan := NewMultitenantDatabaseRebalancePlan("vpc1", DatabaseEngineTypePostgres, databases, installations, 10, 0)
+		require.Len(t, plan.Moves, 2)
+		for _, move := range plan.Moves {
+			assert.Equal(t, "db2", move.SourceDatabaseID)
+			assert.Equal(t, "db3", move.DestinationDatabaseID)
+		}
+	})
+
+	t.Run("lightest database cannot receive installations", func(t *testing.T) {
+		databases := []*MultitenantDatabase{
+			{ID: "db1", Installations: MultitenantDatabaseInstallations{"a", "b", "c", "d"}},
+			{ID: "db2", MigratedInstallations: MultitenantDatabaseInstallations{"a", "b", "c", "d"}},
+			{ID: "db3", Installations: MultitenantDatabaseInstallations{"e"}},
+		}
+		installations := newInstallations(InstallationStateHibernating, "a", "b", "c", "d")
+		installations["e"] = &Installation{ID: "e", State: InstallationStateStable, Database: InstallationDatabaseMultiTenantRDSPostgres}
+
+		plan := NewMultitenantDatabaseRebalancePlan("vpc1", DatabaseEngineTypePostgres, databases, installations, 10, 0)
+		require.Len(t, plan.Moves, 1)
+		assert.Equal(t, "a", plan.Moves[0].InstallationID)
+		assert.Equal(t, "db1", plan.Moves[0].SourceDatabaseID)
+		assert.Equal(t, "db3", plan.Moves[0].DestinationDatabaseID)
+	})
+
+	t.Run("destination max weight", func(t *testing.T) {
+		databases := []*MultitenantDatabase{
+			{ID: "db1", Installations: MultitenantDatabaseInstallations{"a", "b", "c", "d"}},
+			{ID: "db2"},
+		}
+		installations := newInstallations(InstallationStateHibernating, "a", "b", "c", "d")
+
+		plan := NewMultitenantDatabaseRebalancePlan("vpc1", DatabaseEngineTypePostgres, databases, installations, 1, 0)
+		require.Len(t, plan.Moves, 1)
+	})
+}
+
+func TestMultitenantDatabaseRebalanceMoves(t *testing.T) {
+	moves := MultitenantDatabaseRebalanceMoves{
+		{InstallationID: "a", State: MultitenantDatabaseRebalanceMoveInProgress},
+		{InstallationID: "b", State: MultitenantDatabaseRebalanceMovePending},
+		{InstallationID: "c", State: MultitenantDatabaseRebalanceMoveSucceeded},
+	}
+	assert.Equal(t, 1, moves.CountInProgress())
+	assert.False(t, moves[0].IsFinished())
+	assert.False(t, moves[1].IsFinished())
+	assert.True(t, moves[2].IsFinished())
+
+	value, err := moves.Value()
+	require.NoError(t, err)
+
+	var scanned MultitenantDatabaseRebalanceMoves
+	err = scanned.Scan(value)
+	require.NoError(t, err)
+	assert.Equal(t, moves, scanned)
+}
+
+func TestMultitenantDatabaseRebalanceFromReader(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		rebalance, err := MultitenantDatabaseRebalanceFromReader(bytes.NewReader([]byte("")))
+		require.NoError(t, err)
+		require.Equal(t, &MultitenantDatabaseRebalance{}, rebalance)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		rebalance, err := MultitenantDatabaseRebalanceFromReader(bytes.NewReader([]byte("{test")))
+		require.Error(t, err)
+		require.Nil(t, rebalance)
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		rebalance, err := MultitenantDatabaseRebalanceFromReader(bytes.NewReader([]byte(
+			`{"ID":"id", "VpcID":"vpc1", "State":"multitenant-database-rebalance-requested"}`,
+		)))
+		require.NoError(t, err)
+		require.Equal(t, &MultitenantDatabaseRebalance{
+			ID:    "id",
+			VpcID: "vpc1",
+			State: MultitenantDatabaseRebalanceStateRequested,
+		}, rebalance)
+	})
+}
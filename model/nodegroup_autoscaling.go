@@ -0,0 +1,376 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// An EKS managed nodegroup is backed by a single capacity type. A mix of spot
+// and on-demand capacity is expressed as separate nodegroups, one per capacity
+// type, each with its own autoscaling policy. The policies are reconciled
+// independently: there is no policy that splits capacity between spot and
+// on-demand nodegroups, such as an on-demand base with spot overflow.
+const (
+	// NodeGroupCapacityOnDemand is the capacity type of nodegroups backed by
+	// on-demand instances.
+	NodeGroupCapacityOnDemand = "ON_DEMAND"
+	// NodeGroupCapacitySpot is the capacity type of nodegroups backed by spot
+	// instances.
+	NodeGroupCapacitySpot = "SPOT"
+)
+
+var scheduleDays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// NodeGroupAutoscalingPolicy describes how the size of an EKS nodegroup should
+// follow the resources requested by the pods scheduled on it. The nodegroup
+// never scales outside of its MinCount and MaxCount. A policy only applies to
+// its own nodegroup and does not take other nodegroups into account.
+type NodeGroupAutoscalingPolicy struct {
+	// TargetCPUUtilization is the percentage of the allocatable CPU of the
+	// nodegroup that pod requests should use.
+	TargetCPUUtilization int `json:"TargetCPUUtilization,omitempty"`
+	// TargetMemoryUtilization is the percentage of the allocatable memory of
+	// the nodegroup that pod requests should use.
+	TargetMemoryUtilization int `json:"TargetMemoryUtilization,omitempty"`
+	// Schedules raise the minimum size of the nodegroup during recurring
+	// windows, such as business hours.
+	Schedules []NodeGroupScaleSchedule `json:"Schedules,omitempty"`
+}
+
+// NodeGroupScaleSchedule is a recurring window during which a nodegroup keeps
+// at least MinCount nodes. A window with a StartHour later than its EndHour
+// spans midnight.
+type NodeGroupScaleSchedule struct {
+	Name      string
+	Days      []string `json:"Days,omitempty"`
+	StartHour int
+	EndHour   int
+	Timezone  string `json:"Timezone,omitempty"`
+	MinCount  int64
+}
+
+// NodeGroupAutoscalingStatus records the outcome of the last reconciliation of
+// a nodegroup autoscaling policy. It is only persisted when the scaling
+// decision changes.
+type NodeGroupAutoscalingStatus struct {
+	NodeCount         int64
+	MinCount          int64
+	DesiredCount      int64
+	CPUUtilization    int
+	MemoryUtilization int
+	Reason            string
+	LastReconciledAt  int64
+}
+
+// SameScaling returns true if both statuses describe the same scaling decision,
+// ignoring the observed usage and the time they were reconciled at.
+func (s *NodeGroupAutoscalingStatus) SameScaling(other *NodeGroupAutoscalingStatus) bool {
+	if s == nil || other == nil {
+		return s == other
+	}
+	return s.MinCount == other.MinCount &&
+		s.DesiredCount == other.DesiredCount &&
+		s.Reason == other.Reason
+}
+
+// NodeGroupUsage is a snapshot of the resources of the nodes of a nodegroup.
+type NodeGroupUsage struct {
+	NodeCount        int64
+	MilliTotalCPU    int64
+	MilliUsedCPU     int64
+	MilliTotalMemory int64
+	MilliUsedMemory  int64
+}
+
+// Validate validates the values of a nodegroup autoscaling policy.
+func (p *NodeGroupAutoscalingPolicy) Validate(maxCount int64) error {
+	if p.TargetCPUUtilization < 0 || p.TargetCPUUtilization > 100 {
+		return errors.New("target CPU utilization must be between 1 and 100")
+	}
+	if p.TargetMemoryUtilization < 0 || p.TargetMemoryUtilization > 100 {
+		return errors.New("target memory utilization must be between 1 and 100")
+	}
+	if p.TargetCPUUtilization == 0 && p.TargetMemoryUtilization == 0 && len(p.Schedules) == 0 {
+		return errors.New("autoscaling policy must set a utilization target or a schedule")
+	}
+
+	names := map[string]bool{}
+	for _, schedule := range p.Schedules {
+		if names[schedule.Name] {
+			return errors.Errorf("schedule name %q is used more than once", schedule.Name)
+		}
+		names[schedule.Name] = true
+
+		err := schedule.Validate()
+		if err != nil {
+			return errors.Wrapf(err, "invalid schedule %q", schedule.Name)
+		}
+		if maxCount != 0 && schedule.MinCount > maxCount {
+			return errors.Errorf("schedule %q min count (%d) can't be greater than the nodegroup max count (%d)", schedule.Name, schedule.MinCount, maxCount)
+		}
+	}
+
+	return nil
+}
+
+// Validate validates the values of a nodegroup scale schedule.
+func (s *NodeGroupScaleSchedule) Validate() error {
+	if len(s.Name) == 0 {
+		return errors.New("name cannot be blank")
+	}
+	if s.StartHour < 0 || s.StartHour > 23 {
+		return errors.New("start hour must be between 0 and 23")
+	}
+	if s.EndHour < 1 || s.EndHour > 24 {
+		return errors.New("end hour must be between 1 and 24")
+	}
+	if s.StartHour == s.EndHour {
+		return errors.New("start hour and end hour cannot be equal")
+	}
+	if s.MinCount < 1 {
+		return errors.New("min count has to be 1 or greater")
+	}
+	for _, day := range s.Days {
+		if _, ok := scheduleDays[strings.ToLower(day)]; !ok {
+			return errors.Errorf("invalid day %q", day)
+		}
+	}
+	if _, err := time.LoadLocation(s.Timezone); err != nil {
+		return errors.Wrapf(err, "invalid timezone %q", s.Timezone)
+	}
+
+	return nil
+}
+
+// Active returns whether the schedule window includes the given time.
+func (s *NodeGroupScaleSchedule) Active(now time.Time) bool {
+	location, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return false
+	}
+	now = now.In(location)
+	hour := now.Hour()
+
+	if s.StartHour < s.EndHour {
+		return s.onDay(now.Weekday()) && hour >= s.StartHour && hour < s.EndHour
+	}
+
+	// The window spans midnight, so the early hours belong to the window that
+	// started on the previous day.
+	if hour >= s.StartHour {
+		return s.onDay(now.Weekday())
+	}
+	if hour < s.EndHour {
+		return s.onDay((now.Weekday() + 6) % 7)
+	}
+
+	return false
+}
+
+func (s *NodeGroupScaleSchedule) onDay(weekday time.Weekday) bool {
+	if len(s.Days) == 0 {
+		return true
+	}
+	for _, day := range s.Days {
+		if scheduleDays[strings.ToLower(day)] == weekday {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ActiveSchedule returns the active schedule with the highest min count, or
+// nil if no schedule is active.
+func (p *NodeGroupAutoscalingPolicy) ActiveSchedule(now time.Time) *NodeGroupScaleSchedule {
+	var active *NodeGroupScaleSchedule
+	for i := range p.Schedules {
+		schedule := &p.Schedules[i]
+		if !schedule.Active(now) {
+			continue
+		}
+		if active == nil || schedule.MinCount > active.MinCount {
+			active = schedule
+		}
+	}
+
+	return active
+}
+
+// RecommendScaling calculates the scaling of the nodegroup that satisfies its
+// autoscaling policy given the current usage of its nodes. It returns nil if
+// the nodegroup has no autoscaling policy.
+func (ng *NodeGroupMetadata) RecommendScaling(usage NodeGroupUsage, now time.Time) *NodeGroupAutoscalingStatus {
+	policy := ng.AutoscalingPolicy
+	if policy == nil {
+		return nil
+	}
+
+	status := &NodeGroupAutoscalingStatus{
+		NodeCount:         usage.NodeCount,
+		MinCount:          ng.MinCount,
+		CPUUtilization:    utilizationPercent(usage.MilliUsedCPU, usage.MilliTotalCPU),
+		MemoryUtilization: utilizationPercent(usage.MilliUsedMemory, usage.MilliTotalMemory),
+		Reason:            "nodegroup min count",
+		LastReconciledAt:  now.UnixNano() / int64(time.Millisecond),
+	}
+
+	if schedule := policy.ActiveSchedule(now); schedule != nil && schedule.MinCount > status.MinCount {
+		status.MinCount = schedule.MinCount
+		status.Reason = fmt.Sprintf("schedule %s", schedule.Name)
+	}
+	if ng.MaxCount != 0 && status.MinCount > ng.MaxCount {
+		status.MinCount = ng.MaxCount
+	}
+	status.DesiredCount = status.MinCount
+
+	if usage.NodeCount > 0 {
+		cpuNodes := nodesForTarget(usage.MilliUsedCPU, usage.MilliTotalCPU/usage.NodeCount, policy.TargetCPUUtilization)
+		if cpuNodes > status.DesiredCount {
+			status.DesiredCount = cpuNodes
+			status.Reason = fmt.Sprintf("CPU requests at %d%% of allocatable, target %d%%", status.CPUUtilization, policy.TargetCPUUtilization)
+		}
+		memoryNodes := nodesForTarget(usage.MilliUsedMemory, usage.MilliTotalMemory/usage.NodeCount, policy.TargetMemoryUtilization)
+		if memoryNodes > status.DesiredCount {
+			status.DesiredCount = memoryNodes
+			status.Reason = fmt.Sprintf("memory requests at %d%% of allocatable, target %d%%", status.MemoryUtilization, policy.TargetMemoryUtilization)
+		}
+	}
+
+	if ng.MaxCount != 0 && status.DesiredCount > ng.MaxCount {
+		status.DesiredCount = ng.MaxCount
+		status.Reason = fmt.Sprintf("%s, capped at nodegroup max count", status.Reason)
+	}
+
+	return status
+}
+
+// nodesForTarget returns the number of nodes with the given allocatable amount
+// of a resource needed to keep the used amount at the target percentage.
+func nodesForTarget(used, allocatablePerNode int64, target int) int64 {
+	if target <= 0 || allocatablePerNode <= 0 {
+		return 0
+	}
+
+	capacity := allocatablePerNode * int64(target)
+	return (used*100 + capacity - 1) / capacity
+}
+
+func utilizationPercent(used, total int64) int {
+	if total <= 0 {
+		return 0
+	}
+
+	return int(used * 100 / total)
+}
+
+// HasAutoscalingPolicies returns whether any of the nodegroups has an
+// autoscaling policy.
+func (em *EKSMetadata) HasAutoscalingPolicies() bool {
+	for _, ng := range em.NodeGroups {
+		if ng.AutoscalingPolicy != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ValidateNodegroupPatch ensures that the nodegroup patch can be applied to
+// the given nodegroup.
+func (em *EKSMetadata) ValidateNodegroupPatch(nodegroup string, patch *PatchNodegroupRequest) error {
+	ng, found := em.NodeGroups[nodegroup]
+	if !found {
+		return errors.Errorf("nodegroup %s not found to patch", nodegroup)
+	}
+
+	minCount := ng.MinCount
+	if patch.MinCount != nil {
+		minCount = *patch.MinCount
+	}
+	maxCount := ng.MaxCount
+	if patch.MaxCount != nil {
+		maxCount = *patch.MaxCount
+	}
+	if minCount > maxCount {
+		return errors.Errorf("patch would set min count (%d) higher than max count (%d)", minCount, maxCount)
+	}
+
+	policy := ng.AutoscalingPolicy
+	if patch.AutoscalingPolicy != nil {
+		policy = patch.AutoscalingPolicy
+	}
+	if policy != nil && !patch.RemoveAutoscalingPolicy {
+		err := policy.Validate(maxCount)
+		if err != nil {
+			return errors.Wrap(err, "invalid autoscaling policy")
+		}
+	}
+
+	return nil
+}
+
+// ApplyNodegroupPatch applies the patch to the given nodegroup. Autoscaling
+// policy changes take effect immediately while changes to the nodegroup
+// itself are set in the change request. It returns whether the nodegroup
+// needs to be resized.
+func (em *EKSMetadata) ApplyNodegroupPatch(nodegroup string, patch *PatchNodegroupRequest) bool {
+	ng := em.NodeGroups[nodegroup]
+
+	if patch.RemoveAutoscalingPolicy {
+		ng.AutoscalingPolicy = nil
+		ng.AutoscalingStatus = nil
+	} else if patch.AutoscalingPolicy != nil {
+		ng.AutoscalingPolicy = patch.AutoscalingPolicy
+	}
+	em.NodeGroups[nodegroup] = ng
+
+	// Nodegroups are only recreated when the instances backing them change;
+	// scaling changes are applied to the existing nodegroup.
+	ngChangeRequest := NodeGroupMetadata{Name: ng.Name}
+
+	var applied bool
+	if patch.InstanceType != nil && *patch.InstanceType != ng.InstanceType {
+		applied = true
+		ngChangeRequest.Name = fmt.Sprintf("%s-%s", nodegroup, NewNodeGroupSuffix())
+		ngChangeRequest.InstanceType = *patch.InstanceType
+	}
+	if patch.CapacityType != nil && *patch.CapacityType != ng.CapacityType {
+		applied = true
+		ngChangeRequest.Name = fmt.Sprintf("%s-%s", nodegroup, NewNodeGroupSuffix())
+		ngChangeRequest.CapacityType = *patch.CapacityType
+	}
+	if patch.MinCount != nil && *patch.MinCount != ng.MinCount {
+		applied = true
+		ngChangeRequest.MinCount = *patch.MinCount
+	}
+	if patch.MaxCount != nil && *patch.MaxCount != ng.MaxCount {
+		applied = true
+		ngChangeRequest.MaxCount = *patch.MaxCount
+	}
+
+	if applied {
+		em.ChangeRequest = &EKSMetadataRequestedState{
+			NodeGroups: map[string]NodeGroupMetadata{
+				nodegroup: ngChangeRequest,
+			},
+		}
+	}
+
+	return applied
+}
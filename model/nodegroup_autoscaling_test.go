@@ -0,0 +1,222 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodeGroupScaleScheduleActive(t *testing.T) {
+	businessHours := NodeGroupScaleSchedule{
+		Name:      "business-hours",
+		Days:      []string{"mon", "tue", "wed", "thu", "fri"},
+		StartHour: 8,
+		EndHour:   18,
+		Timezone:  "America/New_York",
+		MinCount:  4,
+	}
+	overnight := NodeGroupScaleSchedule{
+		Name:      "batch",
+		Days:      []string{"fri"},
+		StartHour: 22,
+		EndHour:   4,
+		MinCount:  3,
+	}
+
+	testCases := []struct {
+		schedule NodeGroupScaleSchedule
+		time     time.Time
+		active   bool
+	}{
+		// Monday 14:00 UTC is 10:00 in New York.
+		{businessHours, time.Date(2024, 3, 18, 14, 0, 0, 0, time.UTC), true},
+		// Monday 23:00 UTC is 19:00 in New York.
+		{businessHours, time.Date(2024, 3, 18, 23, 0, 0, 0, time.UTC), false},
+		// Saturday 14:00 UTC.
+		{businessHours, time.Date(2024, 3, 23, 14, 0, 0, 0, time.UTC), false},
+		// Friday 23:00 UTC.
+		{overnight, time.Date(2024, 3, 22, 23, 0, 0, 0, time.UTC), true},
+		// Saturday 02:00 UTC belongs to the Friday window.
+		{overnight, time.Date(2024, 3, 23, 2, 0, 0, 0, time.UTC), true},
+		// Friday 02:00 UTC belongs to a Thursday window.
+		{overnight, time.Date(2024, 3, 22, 2, 0, 0, 0, time.UTC), false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.schedule.Name+" "+tc.time.String(), func(t *testing.T) {
+			assert.Equal(t, tc.active, tc.schedule.Active(tc.time))
+		})
+	}
+}
+
+func TestNodeGroupAutoscalingPolicyValidate(t *testing.T) {
+	valid := NodeGroupScaleSchedule{Name: "day", StartHour: 8, EndHour: 18, MinCount: 2}
+
+	assert.NoError(t, (&NodeGroupAutoscalingPolicy{TargetCPUUtilization: 70}).Validate(5))
+	assert.NoError(t, (&NodeGroupAutoscalingPolicy{Schedules: []NodeGroupScaleSchedule{valid}}).Validate(5))
+
+	assert.Error(t, (&NodeGroupAutoscalingPolicy{}).Validate(5))
+	assert.Error(t, (&NodeGroupAutoscalingPolicy{TargetCPUUtilization: 120}).Validate(5))
+	assert.Error(t, (&NodeGroupAutoscalingPolicy{Schedules: []NodeGroupScaleSchedule{valid, valid}}).Validate(5))
+	assert.Error(t, (&NodeGroupAutoscalingPolicy{Schedules: []NodeGroupScaleSchedule{valid}}).Validate(1))
+
+	invalid := valid
+	invalid.Days = []string{"someday"}
+	assert.Error(t, (&NodeGroupAutoscalingPolicy{Schedules: []NodeGroupScaleSchedule{invalid}}).Validate(5))
+
+	invalid = valid
+	invalid.Timezone = "Mars/Olympus_Mons"
+	assert.Error(t, (&NodeGroupAutoscalingPolicy{Schedules: []NodeGroupScaleSchedule{invalid}}).Validate(5))
+
+	invalid = valid
+	invalid.EndHour = invalid.StartHour
+	assert.Error(t, (&NodeGroupAutoscalingPolicy{Schedules: []NodeGroupScaleSchedule{invalid}}).Validate(5))
+}
+
+func TestNodeGroupRecommendScaling(t *testing.T) {
+	monday := time.Date(2024, 3, 18, 10, 0, 0, 0, time.UTC)
+	sunday := time.Date(2024, 3, 17, 10, 0, 0, 0, time.UTC)
+
+	ng := NodeGroupMetadata{
+		MinCount: 2,
+		MaxCount: 6,
+		AutoscalingPolicy: &NodeGroupAutoscalingPolicy{
+			TargetCPUUtilization:    50,
+			TargetMemoryUtilization: 80,
+			Schedules: []NodeGroupScaleSchedule{
+				{Name: "weekdays", Days: []string{"mon", "tue", "wed", "thu", "fri"}, StartHour: 8, EndHour: 18, MinCount: 4},
+			},
+		},
+	}
+
+	t.Run("no policy", func(t *testing.T) {
+		assert.Nil(t, (&NodeGroupMetadata{MinCount: 2}).RecommendScaling(NodeGroupUsage{}, monday))
+	})
+
+	t.Run("idle nodegroup keeps the minimum", func(t *testing.T) {
+		status := ng.RecommendScaling(NodeGroupUsage{NodeCount: 2, MilliTotalCPU: 4000, MilliUsedCPU: 100, MilliTotalMemory: 8000, MilliUsedMemory: 100}, sunday)
+		require.NotNil(t, status)
+		assert.Equal(t, int64(2), status.MinCount)
+		assert.Equal(t, int64(2), status.DesiredCount)
+		assert.Equal(t, "nodegroup min count", status.Reason)
+	})
+
+	t.Run("schedule raises the floor", func(t *testing.T) {
+		status := ng.RecommendScaling(NodeGroupUsage{NodeCount: 2, MilliTotalCPU: 4000, MilliUsedCPU: 100, MilliTotalMemory: 8000, MilliUsedMemory: 100}, monday)
+		assert.Equal(t, int64(4), status.MinCount)
+		assert.Equal(t, int64(4), status.DesiredCount)
+		assert.Equal(t, "schedule weekdays", status.Reason)
+	})
+
+	t.Run("cpu target", func(t *testing.T) {
+		// 3 of 4 cores requested with 2 cores per node needs 3 nodes at 50%.
+		status := ng.RecommendScaling(NodeGroupUsage{NodeCount: 2, MilliTotalCPU: 4000, MilliUsedCPU: 3000, MilliTotalMemory: 8000, MilliUsedMemory: 100}, sunday)
+		assert.Equal(t, int64(3), status.DesiredCount)
+		assert.Equal(t, 75, status.CPUUtilization)
+		assert.Contains(t, status.Reason, "CPU requests")
+	})
+
+	t.Run("memory target", func(t *testing.T) {
+		// 18 of 20 units of memory with 4 per node needs 6 nodes at 80%.
+		status := ng.RecommendScaling(NodeGroupUsage{NodeCount: 5, MilliTotalCPU: 10000, MilliUsedCPU: 1000, MilliTotalMemory: 20000, MilliUsedMemory: 18000}, sunday)
+		assert.Equal(t, int64(6), status.DesiredCount)
+		assert.Contains(t, status.Reason, "memory requests")
+	})
+
+	t.Run("capped at max count", func(t *testing.T) {
+		status := ng.RecommendScaling(NodeGroupUsage{NodeCount: 6, MilliTotalCPU: 12000, MilliUsedCPU: 12000, MilliTotalMemory: 8000, MilliUsedMemory: 100}, sunday)
+		assert.Equal(t, int64(6), status.DesiredCount)
+		assert.Contains(t, status.Reason, "capped at nodegroup max count")
+	})
+}
+
+func TestNodeGroupAutoscalingStatusSameScaling(t *testing.T) {
+	status := &NodeGroupAutoscalingStatus{NodeCount: 2, MinCount: 2, DesiredCount: 3, CPUUtilization: 75, Reason: "CPU requests", LastReconciledAt: 100}
+
+	assert.True(t, status.SameScaling(&NodeGroupAutoscalingStatus{NodeCount: 3, MinCount: 2, DesiredCount: 3, CPUUtilization: 50, Reason: "CPU requests", LastReconciledAt: 200}))
+	assert.False(t, status.SameScaling(&NodeGroupAutoscalingStatus{MinCount: 2, DesiredCount: 4, Reason: "CPU requests"}))
+	assert.False(t, status.SameScaling(&NodeGroupAutoscalingStatus{MinCount: 2, DesiredCount: 3, Reason: "schedule business-hours"}))
+	assert.False(t, status.SameScaling(nil))
+	assert.True(t, (*NodeGroupAutoscalingStatus)(nil).SameScaling(nil))
+}
+
+func TestEKSMetadataNodegroupPatch(t *testing.T) {
+	newMetadata := func() *EKSMetadata {
+		return &EKSMetadata{
+			NodeGroups: map[string]NodeGroupMetadata{
+				NodeGroupWorker: {Name: "worker-abc", InstanceType: "m5.large", MinCount: 2, MaxCount: 4},
+			},
+		}
+	}
+	int64Ptr := func(i int64) *int64 { return &i }
+	stringPtr := func(s string) *string { return &s }
+
+	t.Run("unknown nodegroup", func(t *testing.T) {
+		err := newMetadata().ValidateNodegroupPatch("calls", &PatchNodegroupRequest{MinCount: int64Ptr(1)})
+		assert.Error(t, err)
+	})
+
+	t.Run("min above max", func(t *testing.T) {
+		err := newMetadata().ValidateNodegroupPatch(NodeGroupWorker, &PatchNodegroupRequest{MinCount: int64Ptr(5)})
+		assert.Error(t, err)
+	})
+
+	t.Run("schedule above max", func(t *testing.T) {
+		err := newMetadata().ValidateNodegroupPatch(NodeGroupWorker, &PatchNodegroupRequest{
+			AutoscalingPolicy: &NodeGroupAutoscalingPolicy{
+				Schedules: []NodeGroupScaleSchedule{{Name: "day", StartHour: 8, EndHour: 18, MinCount: 5}},
+			},
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("scaling keeps the nodegroup", func(t *testing.T) {
+		em := newMetadata()
+		patch := &PatchNodegroupRequest{MaxCount: int64Ptr(8)}
+		require.NoError(t, em.ValidateNodegroupPatch(NodeGroupWorker, patch))
+		assert.True(t, em.ApplyNodegroupPatch(NodeGroupWorker, patch))
+		require.NotNil(t, em.ChangeRequest)
+		assert.Equal(t, NodeGroupMetadata{Name: "worker-abc", MaxCount: 8}, em.ChangeRequest.NodeGroups[NodeGroupWorker])
+		require.NoError(t, em.ValidateChangeRequest())
+	})
+
+	t.Run("capacity type recreates the nodegroup", func(t *testing.T) {
+		em := newMetadata()
+		assert.True(t, em.ApplyNodegroupPatch(NodeGroupWorker, &PatchNodegroupRequest{CapacityType: stringPtr(NodeGroupCapacitySpot)}))
+		ngChangeRequest := em.ChangeRequest.NodeGroups[NodeGroupWorker]
+		assert.NotEqual(t, "worker-abc", ngChangeRequest.Name)
+		assert.Equal(t, NodeGroupCapacitySpot, ngChangeRequest.CapacityType)
+	})
+
+	t.Run("policy only", func(t *testing.T) {
+		em := newMetadata()
+		policy := &NodeGroupAutoscalingPolicy{TargetCPUUtilization: 60}
+		assert.False(t, em.ApplyNodegroupPatch(NodeGroupWorker, &PatchNodegroupRequest{AutoscalingPolicy: policy}))
+		assert.Nil(t, em.ChangeRequest)
+		assert.Equal(t, policy, em.NodeGroups[NodeGroupWorker].AutoscalingPolicy)
+		assert.True(t, em.HasAutoscalingPolicies())
+
+		assert.False(t, em.ApplyNodegroupPatch(NodeGroupWorker, &PatchNodegroupRequest{RemoveAutoscalingPolicy: true}))
+		assert.Nil(t, em.NodeGroups[NodeGroupWorker].AutoscalingPolicy)
+		assert.False(t, em.HasAutoscalingPolicies())
+	})
+}
+
+func TestPatchNodegroupRequestValidate(t *testing.T) {
+	int64Ptr := func(i int64) *int64 { return &i }
+	stringPtr := func(s string) *string { return &s }
+
+	assert.Error(t, (&PatchNodegroupRequest{}).Validate())
+	assert.Error(t, (&PatchNodegroupRequest{MinCount: int64Ptr(0)}).Validate())
+	assert.Error(t, (&PatchNodegroupRequest{MinCount: int64Ptr(3), MaxCount: int64Ptr(2)}).Validate())
+	assert.Error(t, (&PatchNodegroupRequest{CapacityType: stringPtr("RESERVED")}).Validate())
+	assert.Error(t, (&PatchNodegroupRequest{AutoscalingPolicy: &NodeGroupAutoscalingPolicy{}, RemoveAutoscalingPolicy: true}).Validate())
+	assert.NoError(t, (&PatchNodegroupRequest{CapacityType: stringPtr(NodeGroupCapacitySpot)}).Validate())
+	assert.NoError(t, (&PatchNodegroupRequest{RemoveAutoscalingPolicy: true}).Validate())
+}
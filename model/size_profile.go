@@ -0,0 +1,271 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/mattermost/mattermost-operator/apis/mattermost/v1alpha1"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	sizeProfileNameMinLen = 3
+	sizeProfileNameMaxLen = 64
+)
+
+var sizeProfileNameRegex = regexp.MustCompile("^[a-zA-Z][a-zA-Z0-9]*$")
+
+// SizeProfileGetter looks up size profiles by name. It returns nil if no
+// such profile exists.
+type SizeProfileGetter interface {
+	GetSizeProfileByName(name string) (*SizeProfile, error)
+}
+
+// ResolveInstallationSize returns Installation size based on its name. Sizes
+// which are neither Operator nor provisioner sizes are looked up as size
+// profiles, in which case the profile is returned too.
+func ResolveInstallationSize(size string, sizeProfiles SizeProfileGetter) (v1alpha1.ClusterInstallationSize, *SizeProfile, error) {
+	resources, err := GetInstallationSize(size)
+	if err == nil {
+		return resources, nil, nil
+	}
+
+	name := SizeProfileName(size)
+	if sizeProfiles == nil || validateSizeProfileName(name) != nil {
+		return v1alpha1.ClusterInstallationSize{}, nil, err
+	}
+	profile, lookupErr := sizeProfiles.GetSizeProfileByName(name)
+	if lookupErr != nil {
+		return v1alpha1.ClusterInstallationSize{}, nil, errors.Wrapf(lookupErr, "failed to look up size profile %q", name)
+	}
+	if profile == nil || profile.Spec == nil {
+		return v1alpha1.ClusterInstallationSize{}, nil, err
+	}
+
+	resources, err = profile.InstallationSize(size)
+	if err != nil {
+		return v1alpha1.ClusterInstallationSize{}, nil, err
+	}
+
+	return resources, profile, nil
+}
+
+// ValidateInstallationSize checks that the size is either an Operator or
+// provisioner size, or a well-formed reference to a size profile. Whether
+// the size profile exists is checked with ResolveInstallationSize.
+func ValidateInstallationSize(size string) error {
+	_, err := GetInstallationSize(size)
+	if err == nil {
+		return nil
+	}
+
+	parts := strings.Split(size, "-")
+	if validateSizeProfileName(parts[0]) != nil {
+		return err
+	}
+	_, err = applySizeReplicas(v1alpha1.ClusterInstallationSize{}, parts)
+
+	return err
+}
+
+// SizeProfile is a named installation size managed by the provisioner.
+// Installations reference it by name in the same way as built-in sizes,
+// optionally overriding the app replicas with a [NAME]-[REPLICAS] suffix.
+type SizeProfile struct {
+	ID          string
+	Name        string
+	Description string
+	Spec        *SizeProfileSpec
+	CreateAt    int64
+	UpdateAt    int64
+	DeleteAt    int64
+}
+
+// SizeProfileSpec describes the replicas and resources of the components of
+// an installation. The database and filestore values only apply to
+// installations using operator-managed databases and filestores.
+type SizeProfileSpec struct {
+	App       SizeProfileComponent
+	JobServer *SizeProfileJobServer `json:"JobServer,omitempty"`
+	Database  SizeProfileComponent
+	Filestore SizeProfileComponent
+}
+
+// SizeProfileComponent describes the replicas and resources of a single
+// installation component.
+type SizeProfileComponent struct {
+	Replicas  int32
+	Resources corev1.ResourceRequirements
+}
+
+// SizeProfileJobServer describes how scheduled jobs are run for an
+// installation.
+type SizeProfileJobServer struct {
+	Dedicated bool
+}
+
+// SizeProfileFilter describes the parameters used to constrain a set of size
+// profiles.
+type SizeProfileFilter struct {
+	Paging
+}
+
+// IsDeleted returns whether the size profile was marked as deleted or not.
+func (p *SizeProfile) IsDeleted() bool {
+	return p.DeleteAt != 0
+}
+
+// Value implements the driver.Valuer interface for database storage
+func (s *SizeProfileSpec) Value() (driver.Value, error) {
+	if s == nil {
+		return nil, nil
+	}
+	return json.Marshal(s)
+}
+
+// Scan implements the sql.Scanner interface for database retrieval
+func (s *SizeProfileSpec) Scan(src interface{}) error {
+	if src == nil {
+		return nil
+	}
+
+	source, ok := src.([]byte)
+	if !ok {
+		return errors.New("could not assert type of SizeProfileSpec")
+	}
+
+	var spec SizeProfileSpec
+	err := json.Unmarshal(source, &spec)
+	if err != nil {
+		return err
+	}
+	*s = spec
+
+	return nil
+}
+
+// Validate validates the values of a size profile spec.
+func (s *SizeProfileSpec) Validate() error {
+	if s.App.Replicas < 1 {
+		return errors.New("app replicas must be at least 1")
+	}
+	if s.App.Resources.Requests.Cpu().IsZero() || s.App.Resources.Requests.Memory().IsZero() {
+		return errors.New("app cpu and memory requests must be set")
+	}
+
+	for name, component := range map[string]SizeProfileComponent{
+		"app":       s.App,
+		"database":  s.Database,
+		"filestore": s.Filestore,
+	} {
+		err := component.validate()
+		if err != nil {
+			return errors.Wrapf(err, "invalid %s size", name)
+		}
+	}
+
+	return nil
+}
+
+func (c SizeProfileComponent) validate() error {
+	if c.Replicas < 0 {
+		return errors.New("replicas cannot be negative")
+	}
+
+	for resourceName, request := range c.Resources.Requests {
+		if request.Sign() < 0 {
+			return errors.Errorf("%s request cannot be negative", resourceName)
+		}
+		limit, ok := c.Resources.Limits[resourceName]
+		if ok && limit.Cmp(request) < 0 {
+			return errors.Errorf("%s limit cannot be lower than request", resourceName)
+		}
+	}
+
+	return nil
+}
+
+// ClusterInstallationSize converts the spec to the size definition used by
+// the Mattermost Operator and the installation scheduler.
+func (s *SizeProfileSpec) ClusterInstallationSize() v1alpha1.ClusterInstallationSize {
+	return v1alpha1.ClusterInstallationSize{
+		App: v1alpha1.ComponentSize{
+			Replicas:  s.App.Replicas,
+			Resources: *s.App.Resources.DeepCopy(),
+		},
+		Minio: v1alpha1.ComponentSize{
+			Replicas:  s.Filestore.Replicas,
+			Resources: *s.Filestore.Resources.DeepCopy(),
+		},
+		Database: v1alpha1.ComponentSize{
+			Replicas:  s.Database.Replicas,
+			Resources: *s.Database.Resources.DeepCopy(),
+		},
+	}
+}
+
+// InstallationSize returns the size of installations using the profile, with
+// the optional replicas segment of the size name applied.
+func (p *SizeProfile) InstallationSize(size string) (v1alpha1.ClusterInstallationSize, error) {
+	return applySizeReplicas(p.Spec.ClusterInstallationSize(), strings.Split(size, "-"))
+}
+
+// DedicatedJobServer returns whether installations of the size run scheduled
+// jobs on a dedicated server.
+func (s *SizeProfileSpec) DedicatedJobServer() bool {
+	return s.JobServer != nil && s.JobServer.Dedicated
+}
+
+func validateSizeProfileName(name string) error {
+	if len(name) < sizeProfileNameMinLen || len(name) > sizeProfileNameMaxLen {
+		return errors.Errorf("size profile name must be between %d and %d characters long", sizeProfileNameMinLen, sizeProfileNameMaxLen)
+	}
+	if !sizeProfileNameRegex.MatchString(name) {
+		return errors.New("size profile name must start with a letter and can contain only letters and numbers")
+	}
+	if _, err := v1alpha1.GetClusterSize(name); err == nil || strings.HasPrefix(name, ProvisionerSizePrefix) {
+		return errors.Errorf("size profile name %q is reserved for built-in sizes", name)
+	}
+
+	return nil
+}
+
+// SizeProfileName returns the name of the size without the optional replicas
+// segment.
+func SizeProfileName(size string) string {
+	return strings.SplitN(size, "-", 2)[0]
+}
+
+// SizeProfileFromReader decodes a json-encoded size profile from the given
+// io.Reader.
+func SizeProfileFromReader(reader io.Reader) (*SizeProfile, error) {
+	profile := SizeProfile{}
+	decoder := json.NewDecoder(reader)
+	err := decoder.Decode(&profile)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return &profile, nil
+}
+
+// SizeProfilesFromReader decodes a json-encoded list of size profiles from
+// the given io.Reader.
+func SizeProfilesFromReader(reader io.Reader) ([]*SizeProfile, error) {
+	profiles := []*SizeProfile{}
+	decoder := json.NewDecoder(reader)
+	err := decoder.Decode(&profiles)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return profiles, nil
+}
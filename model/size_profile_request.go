@@ -0,0 +1,115 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+	"net/url"
+
+	"github.com/pkg/errors"
+)
+
+// CreateSizeProfileRequest specifies the parameters for a new size profile.
+type CreateSizeProfileRequest struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description,omitempty"`
+	Spec        *SizeProfileSpec `json:"spec"`
+}
+
+// Validate validates the values of a size profile create request.
+func (request *CreateSizeProfileRequest) Validate() error {
+	err := validateSizeProfileName(request.Name)
+	if err != nil {
+		return err
+	}
+	if request.Spec == nil {
+		return errors.New("size profile spec must be set")
+	}
+
+	return errors.Wrap(request.Spec.Validate(), "invalid size profile spec")
+}
+
+// NewCreateSizeProfileRequestFromReader will create a CreateSizeProfileRequest
+// from an io.Reader with JSON data.
+func NewCreateSizeProfileRequestFromReader(reader io.Reader) (*CreateSizeProfileRequest, error) {
+	var createRequest CreateSizeProfileRequest
+	err := json.NewDecoder(reader).Decode(&createRequest)
+	if err != nil && err != io.EOF {
+		return nil, errors.Wrap(err, "failed to decode create size profile request")
+	}
+
+	err = createRequest.Validate()
+	if err != nil {
+		return nil, errors.Wrap(err, "create size profile request failed validation")
+	}
+
+	return &createRequest, nil
+}
+
+// PatchSizeProfileRequest specifies the parameters for updating a size
+// profile. The spec replaces the spec of the profile as a whole.
+type PatchSizeProfileRequest struct {
+	Description *string          `json:"description,omitempty"`
+	Spec        *SizeProfileSpec `json:"spec,omitempty"`
+}
+
+// Validate validates the values of a size profile patch request.
+func (p *PatchSizeProfileRequest) Validate() error {
+	if p.Description == nil && p.Spec == nil {
+		return errors.New("size profile patch has no changes")
+	}
+	if p.Spec != nil {
+		return errors.Wrap(p.Spec.Validate(), "invalid size profile spec")
+	}
+
+	return nil
+}
+
+// Apply applies the patch to the given size profile.
+func (p *PatchSizeProfileRequest) Apply(profile *SizeProfile) bool {
+	var applied bool
+	if p.Description != nil && *p.Description != profile.Description {
+		applied = true
+		profile.Description = *p.Description
+	}
+	if p.Spec != nil {
+		applied = true
+		profile.Spec = p.Spec
+	}
+
+	return applied
+}
+
+// NewPatchSizeProfileRequestFromReader will create a PatchSizeProfileRequest
+// from an io.Reader with JSON data.
+func NewPatchSizeProfileRequestFromReader(reader io.Reader) (*PatchSizeProfileRequest, error) {
+	var patchRequest PatchSizeProfileRequest
+	err := json.NewDecoder(reader).Decode(&patchRequest)
+	if err != nil && err != io.EOF {
+		return nil, errors.Wrap(err, "failed to decode patch size profile request")
+	}
+
+	err = patchRequest.Validate()
+	if err != nil {
+		return nil, errors.Wrap(err, "patch size profile request failed validation")
+	}
+
+	return &patchRequest, nil
+}
+
+// GetSizeProfilesRequest describes the parameters to request a list of size
+// profiles.
+type GetSizeProfilesRequest struct {
+	Paging
+}
+
+// ApplyToURL modifies the given url to include query string parameters for the request.
+func (request *GetSizeProfilesRequest) ApplyToURL(u *url.URL) {
+	q := u.Query()
+	request.Paging.AddToQuery(q)
+
+	u.RawQuery = q.Encode()
+}
@@ -0,0 +1,170 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func testSizeProfileSpec() *SizeProfileSpec {
+	return &SizeProfileSpec{
+		App: SizeProfileComponent{
+			Replicas: 3,
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("500m"),
+					corev1.ResourceMemory: resource.MustParse("2Gi"),
+				},
+				Limits: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("2000m"),
+					corev1.ResourceMemory: resource.MustParse("4Gi"),
+				},
+			},
+		},
+		JobServer: &SizeProfileJobServer{Dedicated: true},
+		Database: SizeProfileComponent{
+			Replicas: 2,
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("250m"),
+					corev1.ResourceMemory: resource.MustParse("1Gi"),
+				},
+			},
+		},
+	}
+}
+
+func TestSizeProfileSpecValidate(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		assert.NoError(t, testSizeProfileSpec().Validate())
+	})
+
+	t.Run("no app replicas", func(t *testing.T) {
+		spec := testSizeProfileSpec()
+		spec.App.Replicas = 0
+		assert.Error(t, spec.Validate())
+	})
+
+	t.Run("no app requests", func(t *testing.T) {
+		spec := testSizeProfileSpec()
+		delete(spec.App.Resources.Requests, corev1.ResourceMemory)
+		assert.Error(t, spec.Validate())
+	})
+
+	t.Run("limit lower than request", func(t *testing.T) {
+		spec := testSizeProfileSpec()
+		spec.App.Resources.Limits[corev1.ResourceCPU] = resource.MustParse("100m")
+		assert.Error(t, spec.Validate())
+	})
+
+	t.Run("negative database replicas", func(t *testing.T) {
+		spec := testSizeProfileSpec()
+		spec.Database.Replicas = -1
+		assert.Error(t, spec.Validate())
+	})
+}
+
+func TestCreateSizeProfileRequestValidate(t *testing.T) {
+	for _, testCase := range []struct {
+		name  string
+		valid bool
+	}{
+		{"largeHA", true},
+		{"size50k", true},
+		{"xl", false},
+		{"large-ha", false},
+		{"1000users", false},
+		{"miniHA", false},
+		{"provisionerL", false},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			request := &CreateSizeProfileRequest{Name: testCase.name, Spec: testSizeProfileSpec()}
+			if testCase.valid {
+				assert.NoError(t, request.Validate())
+			} else {
+				assert.Error(t, request.Validate())
+			}
+		})
+	}
+
+	t.Run("missing spec", func(t *testing.T) {
+		request := &CreateSizeProfileRequest{Name: "largeHA"}
+		assert.Error(t, request.Validate())
+	})
+}
+
+type testSizeProfileGetter map[string]*SizeProfile
+
+func (g testSizeProfileGetter) GetSizeProfileByName(name string) (*SizeProfile, error) {
+	if name == "broken" {
+		return nil, errors.New("database unavailable")
+	}
+	return g[name], nil
+}
+
+func TestResolveInstallationSize(t *testing.T) {
+	profile := &SizeProfile{Name: "largeHA", Spec: testSizeProfileSpec()}
+	sizeProfiles := testSizeProfileGetter{profile.Name: profile}
+
+	t.Run("size profile", func(t *testing.T) {
+		size, resolvedProfile, err := ResolveInstallationSize("largeHA", sizeProfiles)
+		require.NoError(t, err)
+		assert.Equal(t, profile, resolvedProfile)
+		assert.Equal(t, int32(3), size.App.Replicas)
+		assert.Equal(t, int32(2), size.Database.Replicas)
+		assert.Equal(t, int64(1500), size.CalculateCPUMilliRequirement(false, false))
+		assert.Equal(t, int64(2000), size.CalculateCPUMilliRequirement(true, false))
+	})
+
+	t.Run("size profile with replicas", func(t *testing.T) {
+		size, _, err := ResolveInstallationSize("largeHA-5", sizeProfiles)
+		require.NoError(t, err)
+		assert.Equal(t, int32(5), size.App.Replicas)
+		assert.Equal(t, int32(3), profile.Spec.App.Replicas)
+	})
+
+	t.Run("built-in sizes", func(t *testing.T) {
+		_, resolvedProfile, err := ResolveInstallationSize("1000users", sizeProfiles)
+		assert.NoError(t, err)
+		assert.Nil(t, resolvedProfile)
+		_, _, err = ResolveInstallationSize("provisionerXL-2", nil)
+		assert.NoError(t, err)
+	})
+
+	t.Run("unknown size", func(t *testing.T) {
+		_, _, err := ResolveInstallationSize("smallHA", sizeProfiles)
+		assert.ErrorContains(t, err, "unrecognized installation size")
+	})
+
+	t.Run("no size profiles", func(t *testing.T) {
+		_, _, err := ResolveInstallationSize("largeHA", nil)
+		assert.ErrorContains(t, err, "unrecognized installation size")
+	})
+
+	t.Run("lookup error", func(t *testing.T) {
+		_, _, err := ResolveInstallationSize("broken", sizeProfiles)
+		assert.ErrorContains(t, err, "database unavailable")
+	})
+
+	t.Run("size profiles are not resolved by GetInstallationSize", func(t *testing.T) {
+		_, err := GetInstallationSize("largeHA")
+		assert.Error(t, err)
+	})
+}
+
+func TestValidateInstallationSize(t *testing.T) {
+	for _, size := range []string{"1000users", "provisionerXL-2", "largeHA", "largeHA-5"} {
+		assert.NoError(t, ValidateInstallationSize(size), size)
+	}
+	for _, size := range []string{"", "large_HA", "largeHA-x", "largeHA-1-2", "provisionerXL-x", "1000users-2"} {
+		assert.Error(t, ValidateInstallationSize(size), size)
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+)
+
+// UtilityDriftReport compares the desired, actual and default versions of
+// the utilities across clusters.
+type UtilityDriftReport struct {
+	Utilities []*UtilityDrift
+}
+
+// UtilityDrift describes the versions of a single utility across clusters.
+type UtilityDrift struct {
+	Utility        string
+	DefaultVersion string `json:"DefaultVersion,omitempty"`
+	// ActualVersions counts the clusters running each actual version.
+	ActualVersions map[string]int
+	// DriftedClusters is the number of clusters running a version other
+	// than the default version.
+	DriftedClusters int
+	// PendingClusters is the number of clusters with a desired version
+	// that is not running yet.
+	PendingClusters int
+	Clusters        []*ClusterUtilityDrift
+}
+
+// ClusterUtilityDrift describes the versions of a utility on a cluster.
+type ClusterUtilityDrift struct {
+	ClusterID      string
+	DesiredVersion string `json:"DesiredVersion,omitempty"`
+	ActualVersion  string `json:"ActualVersion,omitempty"`
+	// Drifted is true if the cluster runs a version other than the
+	// default version.
+	Drifted bool
+	// Pending is true if the desired version is not running yet.
+	Pending bool
+}
+
+// NewUtilityDriftReport builds the utility drift report of the given
+// clusters. The report is limited to a single utility if one is provided,
+// and to clusters with drifted or pending versions if onlyDrifted is set.
+func NewUtilityDriftReport(clusters []*Cluster, utility string, onlyDrifted bool) (*UtilityDriftReport, error) {
+	drifts := map[string]*UtilityDrift{}
+	var names []string
+
+	for _, cluster := range clusters {
+		registry, err := cluster.UtilityRegistry()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, definition := range registry {
+			if utility != "" && definition.Name != utility {
+				continue
+			}
+
+			clusterDrift := newClusterUtilityDrift(cluster, definition.Name)
+			if onlyDrifted && !clusterDrift.Drifted && !clusterDrift.Pending {
+				continue
+			}
+
+			drift, ok := drifts[definition.Name]
+			if !ok {
+				drift = &UtilityDrift{
+					Utility:        definition.Name,
+					DefaultVersion: defaultUtilityChartVersion(definition.Name),
+					ActualVersions: map[string]int{},
+					Clusters:       []*ClusterUtilityDrift{},
+				}
+				drifts[definition.Name] = drift
+				names = append(names, definition.Name)
+			}
+
+			drift.Clusters = append(drift.Clusters, clusterDrift)
+			if clusterDrift.ActualVersion != "" {
+				drift.ActualVersions[clusterDrift.ActualVersion]++
+			}
+			if clusterDrift.Drifted {
+				drift.DriftedClusters++
+			}
+			if clusterDrift.Pending {
+				drift.PendingClusters++
+			}
+		}
+	}
+
+	sort.Strings(names)
+	report := &UtilityDriftReport{Utilities: make([]*UtilityDrift, 0, len(names))}
+	for _, name := range names {
+		report.Utilities = append(report.Utilities, drifts[name])
+	}
+
+	return report, nil
+}
+
+func newClusterUtilityDrift(cluster *Cluster, utility string) *ClusterUtilityDrift {
+	drift := &ClusterUtilityDrift{ClusterID: cluster.ID}
+	if desired := cluster.DesiredUtilityVersion(utility); desired != nil {
+		drift.DesiredVersion = desired.Version()
+	}
+	if actual := cluster.ActualUtilityVersion(utility); actual != nil {
+		drift.ActualVersion = actual.Version()
+	}
+
+	defaultVersion := defaultUtilityChartVersion(utility)
+	drift.Drifted = defaultVersion != "" &&
+		drift.ActualVersion != "" &&
+		drift.ActualVersion != UnmanagedUtilityVersion &&
+		drift.ActualVersion != defaultVersion
+	drift.Pending = drift.DesiredVersion != "" && drift.DesiredVersion != drift.ActualVersion
+
+	return drift
+}
+
+// defaultUtilityChartVersion returns the default chart version of a utility
+// or an empty string for utilities without a default version.
+func defaultUtilityChartVersion(utility string) string {
+	version, ok := DefaultUtilityVersions[utility]
+	if !ok || version == nil {
+		return ""
+	}
+	return version.Version()
+}
+
+// UtilityDriftReportFromReader decodes a json-encoded utility drift report
+// from the given io.Reader.
+func UtilityDriftReportFromReader(reader io.Reader) (*UtilityDriftReport, error) {
+	report := UtilityDriftReport{}
+	decoder := json.NewDecoder(reader)
+	err := decoder.Decode(&report)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return &report, nil
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestDriftCluster(t *testing.T, id string, actual, desired string) *Cluster {
+	cluster := &Cluster{ID: id}
+	if actual != "" {
+		err := cluster.SetUtilityActualVersion(NginxCanonicalName, &HelmUtilityVersion{Chart: actual, ValuesPath: "nginx_values.yaml"})
+		require.NoError(t, err)
+	}
+	if desired != "" {
+		cluster.SetUtilityDesiredVersions(map[string]*HelmUtilityVersion{NginxCanonicalName: {Chart: desired, ValuesPath: "nginx_values.yaml"}})
+	}
+	return cluster
+}
+
+func TestNewUtilityDriftReport(t *testing.T) {
+	defaultVersion := DefaultUtilityVersions[NginxCanonicalName].Version()
+	clusters := []*Cluster{
+		newTestDriftCluster(t, "a", defaultVersion, ""),
+		newTestDriftCluster(t, "b", "4.0.0", ""),
+		newTestDriftCluster(t, "c", defaultVersion, "4.12.0"),
+		newTestDriftCluster(t, "d", UnmanagedUtilityVersion, ""),
+	}
+
+	t.Run("single utility", func(t *testing.T) {
+		report, err := NewUtilityDriftReport(clusters, NginxCanonicalName, false)
+		require.NoError(t, err)
+		require.Len(t, report.Utilities, 1)
+
+		drift := report.Utilities[0]
+		assert.Equal(t, NginxCanonicalName, drift.Utility)
+		assert.Equal(t, defaultVersion, drift.DefaultVersion)
+		assert.Equal(t, map[string]int{defaultVersion: 2, "4.0.0": 1, UnmanagedUtilityVersion: 1}, drift.ActualVersions)
+		assert.Equal(t, 1, drift.DriftedClusters)
+		assert.Equal(t, 1, drift.PendingClusters)
+		require.Len(t, drift.Clusters, 4)
+		assert.Equal(t, &ClusterUtilityDrift{ClusterID: "b", ActualVersion: "4.0.0", Drifted: true}, drift.Clusters[1])
+		assert.Equal(t, &ClusterUtilityDrift{ClusterID: "c", DesiredVersion: "4.12.0", ActualVersion: defaultVersion, Pending: true}, drift.Clusters[2])
+		assert.False(t, drift.Clusters[3].Drifted)
+	})
+
+	t.Run("only drifted", func(t *testing.T) {
+		report, err := NewUtilityDriftReport(clusters, NginxCanonicalName, true)
+		require.NoError(t, err)
+		require.Len(t, report.Utilities, 1)
+
+		var clusterIDs []string
+		for _, cluster := range report.Utilities[0].Clusters {
+			clusterIDs = append(clusterIDs, cluster.ClusterID)
+		}
+		assert.Equal(t, []string{"b", "c"}, clusterIDs)
+	})
+
+	t.Run("all utilities", func(t *testing.T) {
+		report, err := NewUtilityDriftReport(clusters, "", false)
+		require.NoError(t, err)
+		require.Len(t, report.Utilities, len(BuiltinUtilities))
+		for i := 1; i < len(report.Utilities); i++ {
+			assert.Less(t, report.Utilities[i-1].Utility, report.Utilities[i].Utility)
+		}
+	})
+
+	t.Run("unknown utility", func(t *testing.T) {
+		report, err := NewUtilityDriftReport(clusters, "cert-manager", false)
+		require.NoError(t, err)
+		assert.Empty(t, report.Utilities)
+	})
+}
+
+func TestUtilityDriftReportFromReader(t *testing.T) {
+	report := &UtilityDriftReport{
+		Utilities: []*UtilityDrift{
+			{Utility: NginxCanonicalName, ActualVersions: map[string]int{"4.0.0": 1}, Clusters: []*ClusterUtilityDrift{{ClusterID: "a", ActualVersion: "4.0.0"}}},
+		},
+	}
+	data, err := json.Marshal(report)
+	require.NoError(t, err)
+
+	result, err := UtilityDriftReportFromReader(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, report, result)
+}
@@ -0,0 +1,386 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+	"regexp"
+
+	"github.com/pkg/errors"
+)
+
+// utilityNameMaxLen is the maximum length of Helm release names.
+const utilityNameMaxLen = 53
+
+var utilityNameRegex = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// ErrUtilityNotFound is returned when a utility is not in the registry of
+// a cluster.
+var ErrUtilityNotFound = errors.New("utility not found")
+
+// UtilityDefinition describes a cluster utility deployed with a Helm chart.
+type UtilityDefinition struct {
+	// Name is the canonical name of the utility.
+	Name string
+	// Repo is the name of the Helm repository holding the chart.
+	Repo string
+	// RepoURL is the URL of the Helm repository.
+	RepoURL string
+	// Chart is the name of the chart in the repository.
+	Chart string
+	// Release is the name of the Helm release.
+	Release string
+	// Namespace is the namespace the utility is deployed to.
+	Namespace string
+	// DependsOn lists the utilities which must be deployed before this one.
+	DependsOn []string `json:",omitempty"`
+	// Builtin is true for utilities managed by the provisioner itself, which
+	// cannot be removed from the registry.
+	Builtin bool `json:",omitempty"`
+}
+
+// ChartReference returns the chart reference used by Helm to install the
+// utility.
+func (d *UtilityDefinition) ChartReference() string {
+	return d.Repo + "/" + d.Chart
+}
+
+// BuiltinUtilities is the registry of utilities deployed to every cluster,
+// in deployment order.
+var BuiltinUtilities = []*UtilityDefinition{
+	{Name: PgbouncerCanonicalName, Repo: "chartmuseum", RepoURL: "https://chartmuseum.internal.core.cloud.mattermost.com", Chart: "pgbouncer", Release: "pgbouncer", Namespace: "pgbouncer", Builtin: true},
+	{Name: NginxCanonicalName, Repo: "ingress-nginx", RepoURL: "https://kubernetes.github.io/ingress-nginx", Chart: "ingress-nginx", Release: "nginx", Namespace: "nginx", Builtin: true},
+	{Name: NginxInternalCanonicalName, Repo: "ingress-nginx", RepoURL: "https://kubernetes.github.io/ingress-nginx", Chart: "ingress-nginx", Release: "nginx-internal", Namespace: "nginx-internal", Builtin: true},
+	{Name: PrometheusOperatorCanonicalName, Repo: "prometheus-community", RepoURL: "https://prometheus-community.github.io/helm-charts", Chart: "kube-prometheus-stack", Release: "prometheus-operator", Namespace: "prometheus", Builtin: true},
+	{Name: ThanosCanonicalName, Repo: "bitnami", RepoURL: "https://charts.bitnami.com/bitnami", Chart: "thanos", Release: "thanos", Namespace: "prometheus", DependsOn: []string{PrometheusOperatorCanonicalName}, Builtin: true},
+	{Name: FluentbitCanonicalName, Repo: "fluent", RepoURL: "https://fluent.github.io/helm-charts", Chart: "fluent-bit", Release: "fluent-bit", Namespace: "fluent-bit", Builtin: true},
+	{Name: TeleportCanonicalName, Repo: "chartmuseum", RepoURL: "https://chartmuseum.internal.core.cloud.mattermost.com", Chart: "teleport-kube-agent", Release: "teleport-kube-agent", Namespace: "teleport", Builtin: true},
+	{Name: PromtailCanonicalName, Repo: "grafana", RepoURL: "https://grafana.github.io/helm-charts", Chart: "promtail", Release: "promtail", Namespace: "promtail", Builtin: true},
+	{Name: NodeProblemDetectorCanonicalName, Repo: "deliveryhero", RepoURL: "https://charts.deliveryhero.io/", Chart: "node-problem-detector", Release: "node-problem-detector", Namespace: "node-problem-detector", Builtin: true},
+	{Name: RtcdCanonicalName, Repo: "mattermost", RepoURL: "https://helm.mattermost.com", Chart: "mattermost-rtcd", Release: "mattermost-rtcd", Namespace: "mattermost-rtcd", Builtin: true},
+	{Name: MetricsServerCanonicalName, Repo: "metrics-server", RepoURL: "https://kubernetes-sigs.github.io/metrics-server/", Chart: "metrics-server", Release: "metrics-server", Namespace: "kube-system", Builtin: true},
+	{Name: VeleroCanonicalName, Repo: "vmware-tanzu", RepoURL: "https://vmware-tanzu.github.io/helm-charts/", Chart: "velero", Release: "velero", Namespace: "velero", Builtin: true},
+	{Name: CloudproberCanonicalName, Repo: "chartmuseum", RepoURL: "https://chartmuseum.internal.core.cloud.mattermost.com", Chart: "cloudprober", Release: "cloudprober", Namespace: "cloudprober", Builtin: true},
+}
+
+// BuiltinUtility returns the registry entry of a builtin utility, or nil if
+// there is no builtin utility with the given name.
+func BuiltinUtility(name string) *UtilityDefinition {
+	for _, definition := range BuiltinUtilities {
+		if definition.Name == name {
+			return definition
+		}
+	}
+	return nil
+}
+
+// SortUtilityDefinitions orders utility definitions so that every utility
+// comes after its dependencies. Otherwise, the given order is kept.
+func SortUtilityDefinitions(definitions []*UtilityDefinition) ([]*UtilityDefinition, error) {
+	byName := make(map[string]*UtilityDefinition, len(definitions))
+	for _, definition := range definitions {
+		if _, ok := byName[definition.Name]; ok {
+			return nil, errors.Errorf("utility %s is defined more than once", definition.Name)
+		}
+		byName[definition.Name] = definition
+	}
+
+	sorted := make([]*UtilityDefinition, 0, len(definitions))
+	done := make(map[string]bool, len(definitions))
+	visiting := make(map[string]bool)
+
+	var visit func(definition *UtilityDefinition) error
+	visit = func(definition *UtilityDefinition) error {
+		if done[definition.Name] {
+			return nil
+		}
+		if visiting[definition.Name] {
+			return errors.Errorf("utility %s has a circular dependency", definition.Name)
+		}
+		visiting[definition.Name] = true
+
+		for _, dependency := range definition.DependsOn {
+			dependencyDefinition, ok := byName[dependency]
+			if !ok {
+				return errors.Errorf("utility %s depends on unknown utility %s", definition.Name, dependency)
+			}
+			err := visit(dependencyDefinition)
+			if err != nil {
+				return err
+			}
+		}
+
+		visiting[definition.Name] = false
+		done[definition.Name] = true
+		sorted = append(sorted, definition)
+
+		return nil
+	}
+
+	for _, definition := range definitions {
+		err := visit(definition)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return sorted, nil
+}
+
+// HelmRepos returns the Helm repository URLs of the utility definitions
+// keyed by repository name.
+func HelmRepos(definitions []*UtilityDefinition) map[string]string {
+	repos := map[string]string{}
+	for _, definition := range definitions {
+		if definition.RepoURL != "" {
+			repos[definition.Repo] = definition.RepoURL
+		}
+	}
+	return repos
+}
+
+// UtilityRegistry returns the builtin and registered utilities of the
+// cluster in deployment order.
+func (c *Cluster) UtilityRegistry() ([]*UtilityDefinition, error) {
+	definitions := append([]*UtilityDefinition{}, BuiltinUtilities...)
+	if c.UtilityMetadata != nil {
+		definitions = append(definitions, c.UtilityMetadata.Utilities...)
+	}
+
+	return SortUtilityDefinitions(definitions)
+}
+
+// AddUtility registers a new utility for the cluster and sets its desired
+// version. The utility is deployed the next time the cluster is
+// provisioned.
+func (c *Cluster) AddUtility(request *AddClusterUtilityRequest) error {
+	err := request.Validate()
+	if err != nil {
+		return err
+	}
+
+	registry, err := c.UtilityRegistry()
+	if err != nil {
+		return errors.Wrap(err, "failed to get utility registry")
+	}
+	repos := HelmRepos(registry)
+
+	definition := request.Definition()
+	if url, ok := repos[definition.Repo]; ok {
+		if definition.RepoURL == "" {
+			definition.RepoURL = url
+		} else if definition.RepoURL != url {
+			return errors.Errorf("helm repo %s is already registered with URL %s", definition.Repo, url)
+		}
+	}
+	if definition.RepoURL == "" {
+		return errors.Errorf("helm repo %s is unknown, a repo URL must be provided", definition.Repo)
+	}
+
+	_, err = SortUtilityDefinitions(append(registry, definition))
+	if err != nil {
+		return err
+	}
+
+	if c.UtilityMetadata == nil {
+		c.UtilityMetadata = &UtilityMetadata{}
+	}
+	c.UtilityMetadata.Utilities = append(c.UtilityMetadata.Utilities, definition)
+	c.UtilityMetadata.RemovedUtilities = removeUtilityDefinition(c.UtilityMetadata.RemovedUtilities, definition.Name)
+	c.UtilityMetadata.DesiredVersions.setCustomVersion(definition.Name, &HelmUtilityVersion{
+		Chart:      request.Version,
+		ValuesPath: request.ValuesPath,
+	})
+
+	return nil
+}
+
+// RemoveUtility removes a registered utility from the cluster. The utility
+// is uninstalled the next time the cluster is provisioned.
+func (c *Cluster) RemoveUtility(name string) error {
+	if BuiltinUtility(name) != nil {
+		return errors.Errorf("builtin utility %s cannot be removed", name)
+	}
+
+	definition := c.registeredUtility(name)
+	if definition == nil {
+		return ErrUtilityNotFound
+	}
+
+	for _, other := range c.UtilityMetadata.Utilities {
+		for _, dependency := range other.DependsOn {
+			if dependency == name {
+				return errors.Errorf("utility %s is required by utility %s", name, other.Name)
+			}
+		}
+	}
+
+	c.UtilityMetadata.Utilities = removeUtilityDefinition(c.UtilityMetadata.Utilities, name)
+	c.UtilityMetadata.RemovedUtilities = append(c.UtilityMetadata.RemovedUtilities, definition)
+	c.UtilityMetadata.DesiredVersions.setCustomVersion(name, nil)
+
+	return nil
+}
+
+// SetUtilityUninstalled clears a removed utility from the cluster once its
+// Helm release has been uninstalled.
+func (c *Cluster) SetUtilityUninstalled(name string) {
+	if c.UtilityMetadata == nil {
+		return
+	}
+
+	c.UtilityMetadata.RemovedUtilities = removeUtilityDefinition(c.UtilityMetadata.RemovedUtilities, name)
+	c.UtilityMetadata.ActualVersions.setCustomVersion(name, nil)
+	delete(c.UtilityMetadata.Health, name)
+}
+
+// registeredUtility returns the definition of a utility registered for the
+// cluster, excluding the builtin utilities.
+func (c *Cluster) registeredUtility(name string) *UtilityDefinition {
+	if c.UtilityMetadata == nil {
+		return nil
+	}
+	for _, definition := range c.UtilityMetadata.Utilities {
+		if definition.Name == name {
+			return definition
+		}
+	}
+	return nil
+}
+
+func removeUtilityDefinition(definitions []*UtilityDefinition, name string) []*UtilityDefinition {
+	var remaining []*UtilityDefinition
+	for _, definition := range definitions {
+		if definition.Name != name {
+			remaining = append(remaining, definition)
+		}
+	}
+	return remaining
+}
+
+// ClusterUtility is a registry entry of a cluster together with the desired
+// and actual versions of the utility.
+type ClusterUtility struct {
+	*UtilityDefinition
+	DesiredVersion *HelmUtilityVersion
+	ActualVersion  *HelmUtilityVersion
+	Health         *ClusterUtilityHealth `json:",omitempty"`
+}
+
+// ClusterUtilities returns the utility registry of the cluster with the
+// versions of the utilities.
+func (c *Cluster) ClusterUtilities() ([]*ClusterUtility, error) {
+	registry, err := c.UtilityRegistry()
+	if err != nil {
+		return nil, err
+	}
+
+	utilities := make([]*ClusterUtility, 0, len(registry))
+	for _, definition := range registry {
+		utilities = append(utilities, &ClusterUtility{
+			UtilityDefinition: definition,
+			DesiredVersion:    c.DesiredUtilityVersion(definition.Name),
+			ActualVersion:     c.ActualUtilityVersion(definition.Name),
+			Health:            c.UtilityHealth(definition.Name),
+		})
+	}
+
+	return utilities, nil
+}
+
+// ClusterUtilitiesFromReader decodes a json-encoded list of cluster
+// utilities from the given io.Reader.
+func ClusterUtilitiesFromReader(reader io.Reader) ([]*ClusterUtility, error) {
+	utilities := []*ClusterUtility{}
+	decoder := json.NewDecoder(reader)
+	err := decoder.Decode(&utilities)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return utilities, nil
+}
+
+// AddClusterUtilityRequest specifies the parameters of a utility added to
+// the registry of a cluster.
+type AddClusterUtilityRequest struct {
+	Name       string   `json:"name"`
+	Repo       string   `json:"repo"`
+	RepoURL    string   `json:"repoURL,omitempty"`
+	Chart      string   `json:"chart"`
+	Release    string   `json:"release,omitempty"`
+	Namespace  string   `json:"namespace"`
+	Version    string   `json:"version"`
+	ValuesPath string   `json:"valuesPath"`
+	DependsOn  []string `json:"dependsOn,omitempty"`
+}
+
+// Validate validates the values of an add cluster utility request.
+func (request *AddClusterUtilityRequest) Validate() error {
+	if len(request.Name) > utilityNameMaxLen || !utilityNameRegex.MatchString(request.Name) {
+		return errors.Errorf("utility name %q is invalid: must be a lowercase DNS label of at most %d characters", request.Name, utilityNameMaxLen)
+	}
+	if request.Release != "" && (len(request.Release) > utilityNameMaxLen || !utilityNameRegex.MatchString(request.Release)) {
+		return errors.Errorf("release name %q is invalid: must be a lowercase DNS label of at most %d characters", request.Release, utilityNameMaxLen)
+	}
+	if request.Repo == "" {
+		return errors.New("helm repo cannot be empty")
+	}
+	if request.Chart == "" {
+		return errors.New("chart cannot be empty")
+	}
+	if !utilityNameRegex.MatchString(request.Namespace) {
+		return errors.Errorf("namespace %q is invalid", request.Namespace)
+	}
+	if request.Version == "" || request.Version == UnmanagedUtilityVersion {
+		return errors.New("chart version must be provided")
+	}
+	if request.ValuesPath == "" {
+		return errors.New("values path cannot be empty")
+	}
+	for _, dependency := range request.DependsOn {
+		if dependency == request.Name {
+			return errors.Errorf("utility %s cannot depend on itself", request.Name)
+		}
+	}
+
+	return nil
+}
+
+// Definition returns the registry entry of the requested utility.
+func (request *AddClusterUtilityRequest) Definition() *UtilityDefinition {
+	release := request.Release
+	if release == "" {
+		release = request.Name
+	}
+
+	return &UtilityDefinition{
+		Name:      request.Name,
+		Repo:      request.Repo,
+		RepoURL:   request.RepoURL,
+		Chart:     request.Chart,
+		Release:   release,
+		Namespace: request.Namespace,
+		DependsOn: request.DependsOn,
+	}
+}
+
+// NewAddClusterUtilityRequestFromReader will create an AddClusterUtilityRequest
+// from an io.Reader with JSON data.
+func NewAddClusterUtilityRequestFromReader(reader io.Reader) (*AddClusterUtilityRequest, error) {
+	var addClusterUtilityRequest AddClusterUtilityRequest
+	err := json.NewDecoder(reader).Decode(&addClusterUtilityRequest)
+	if err != nil && err != io.EOF {
+		return nil, errors.Wrap(err, "failed to decode add cluster utility request")
+	}
+
+	err = addClusterUtilityRequest.Validate()
+	if err != nil {
+		return nil, errors.Wrap(err, "add cluster utility request failed validation")
+	}
+
+	return &addClusterUtilityRequest, nil
+}
@@ -0,0 +1,223 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func utilityNames(definitions []*UtilityDefinition) []string {
+	names := make([]string, 0, len(definitions))
+	for _, definition := range definitions {
+		names = append(names, definition.Name)
+	}
+	return names
+}
+
+func newTestAddClusterUtilityRequest() *AddClusterUtilityRequest {
+	return &AddClusterUtilityRequest{
+		Name:       "cert-manager",
+		Repo:       "jetstack",
+		RepoURL:    "https://charts.jetstack.io",
+		Chart:      "cert-manager",
+		Namespace:  "cert-manager",
+		Version:    "1.14.4",
+		ValuesPath: "cert_manager_values.yaml",
+	}
+}
+
+func TestBuiltinUtilities(t *testing.T) {
+	sorted, err := SortUtilityDefinitions(BuiltinUtilities)
+	require.NoError(t, err)
+	assert.Equal(t, utilityNames(BuiltinUtilities), utilityNames(sorted))
+
+	for _, definition := range BuiltinUtilities {
+		assert.Contains(t, DefaultUtilityVersions, definition.Name)
+		assert.True(t, definition.Builtin)
+	}
+	assert.Len(t, BuiltinUtilities, len(DefaultUtilityVersions))
+	assert.Equal(t, "prometheus-community/kube-prometheus-stack", BuiltinUtility(PrometheusOperatorCanonicalName).ChartReference())
+	assert.Nil(t, BuiltinUtility("cert-manager"))
+}
+
+func TestSortUtilityDefinitions(t *testing.T) {
+	t.Run("dependencies first", func(t *testing.T) {
+		sorted, err := SortUtilityDefinitions([]*UtilityDefinition{
+			{Name: "a", DependsOn: []string{"c"}},
+			{Name: "b"},
+			{Name: "c", DependsOn: []string{"b"}},
+			{Name: "d"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"b", "c", "a", "d"}, utilityNames(sorted))
+	})
+
+	t.Run("unknown dependency", func(t *testing.T) {
+		_, err := SortUtilityDefinitions([]*UtilityDefinition{{Name: "a", DependsOn: []string{"b"}}})
+		assert.Error(t, err)
+	})
+
+	t.Run("circular dependency", func(t *testing.T) {
+		_, err := SortUtilityDefinitions([]*UtilityDefinition{
+			{Name: "a", DependsOn: []string{"b"}},
+			{Name: "b", DependsOn: []string{"a"}},
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("duplicate", func(t *testing.T) {
+		_, err := SortUtilityDefinitions([]*UtilityDefinition{{Name: "a"}, {Name: "a"}})
+		assert.Error(t, err)
+	})
+}
+
+func TestAddClusterUtilityRequestValid(t *testing.T) {
+	for _, testCase := range []struct {
+		description string
+		modify      func(request *AddClusterUtilityRequest)
+		expectError bool
+	}{
+		{"valid", func(request *AddClusterUtilityRequest) {}, false},
+		{"invalid name", func(request *AddClusterUtilityRequest) { request.Name = "Cert_Manager" }, true},
+		{"invalid release", func(request *AddClusterUtilityRequest) { request.Release = "-release" }, true},
+		{"no repo", func(request *AddClusterUtilityRequest) { request.Repo = "" }, true},
+		{"no chart", func(request *AddClusterUtilityRequest) { request.Chart = "" }, true},
+		{"no namespace", func(request *AddClusterUtilityRequest) { request.Namespace = "" }, true},
+		{"no version", func(request *AddClusterUtilityRequest) { request.Version = "" }, true},
+		{"unmanaged version", func(request *AddClusterUtilityRequest) { request.Version = UnmanagedUtilityVersion }, true},
+		{"no values path", func(request *AddClusterUtilityRequest) { request.ValuesPath = "" }, true},
+		{"depends on itself", func(request *AddClusterUtilityRequest) { request.DependsOn = []string{"cert-manager"} }, true},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			request := newTestAddClusterUtilityRequest()
+			testCase.modify(request)
+
+			if testCase.expectError {
+				assert.Error(t, request.Validate())
+			} else {
+				assert.NoError(t, request.Validate())
+			}
+		})
+	}
+}
+
+func TestNewAddClusterUtilityRequestFromReader(t *testing.T) {
+	data, err := json.Marshal(newTestAddClusterUtilityRequest())
+	require.NoError(t, err)
+
+	request, err := NewAddClusterUtilityRequestFromReader(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, newTestAddClusterUtilityRequest(), request)
+
+	_, err = NewAddClusterUtilityRequestFromReader(bytes.NewReader([]byte(`{"name": "cert-manager"}`)))
+	assert.Error(t, err)
+}
+
+func TestClusterUtilityRegistry(t *testing.T) {
+	t.Run("add utility", func(t *testing.T) {
+		cluster := &Cluster{}
+
+		err := cluster.AddUtility(newTestAddClusterUtilityRequest())
+		require.NoError(t, err)
+
+		request := newTestAddClusterUtilityRequest()
+		request.Name = "issuers"
+		request.Chart = "issuers"
+		request.RepoURL = ""
+		request.DependsOn = []string{"cert-manager"}
+		err = cluster.AddUtility(request)
+		require.NoError(t, err)
+
+		registry, err := cluster.UtilityRegistry()
+		require.NoError(t, err)
+		assert.Equal(t, append(utilityNames(BuiltinUtilities), "cert-manager", "issuers"), utilityNames(registry))
+		assert.Equal(t, "https://charts.jetstack.io", registry[len(registry)-1].RepoURL)
+		assert.Equal(t, "issuers", registry[len(registry)-1].Release)
+		assert.Equal(t, "https://charts.jetstack.io", HelmRepos(registry)["jetstack"])
+
+		assert.Equal(t, &HelmUtilityVersion{Chart: "1.14.4", ValuesPath: "cert_manager_values.yaml"}, cluster.DesiredUtilityVersion("cert-manager"))
+
+		err = cluster.SetUtilityActualVersion("cert-manager", &HelmUtilityVersion{Chart: "1.14.4", ValuesPath: "cert_manager_values.yaml"})
+		require.NoError(t, err)
+		assert.Nil(t, cluster.DesiredUtilityVersion("cert-manager"))
+		assert.Equal(t, "1.14.4", cluster.UtilityMetadata.ActualVersions.ChartVersions()["cert-manager"])
+
+		cluster.SetUtilityDesiredVersions(map[string]*HelmUtilityVersion{"cert-manager": {Chart: "1.15.0", ValuesPath: "cert_manager_values.yaml"}})
+		assert.Equal(t, "1.15.0", cluster.DesiredUtilityVersion("cert-manager").Version())
+
+		utilities, err := cluster.ClusterUtilities()
+		require.NoError(t, err)
+		require.Len(t, utilities, len(BuiltinUtilities)+2)
+		assert.Equal(t, "1.14.4", utilities[len(BuiltinUtilities)].ActualVersion.Version())
+	})
+
+	t.Run("add invalid utility", func(t *testing.T) {
+		for _, testCase := range []struct {
+			description string
+			modify      func(request *AddClusterUtilityRequest)
+		}{
+			{"builtin name", func(request *AddClusterUtilityRequest) { request.Name = NginxCanonicalName }},
+			{"unknown dependency", func(request *AddClusterUtilityRequest) { request.DependsOn = []string{"external-dns"} }},
+			{"unknown repo", func(request *AddClusterUtilityRequest) { request.RepoURL = "" }},
+			{"conflicting repo", func(request *AddClusterUtilityRequest) { request.Repo = "bitnami" }},
+		} {
+			t.Run(testCase.description, func(t *testing.T) {
+				cluster := &Cluster{}
+				request := newTestAddClusterUtilityRequest()
+				testCase.modify(request)
+
+				assert.Error(t, cluster.AddUtility(request))
+				assert.Nil(t, cluster.UtilityMetadata)
+			})
+		}
+	})
+
+	t.Run("remove utility", func(t *testing.T) {
+		cluster := &Cluster{}
+		err := cluster.AddUtility(newTestAddClusterUtilityRequest())
+		require.NoError(t, err)
+		request := newTestAddClusterUtilityRequest()
+		request.Name = "issuers"
+		request.DependsOn = []string{"cert-manager"}
+		err = cluster.AddUtility(request)
+		require.NoError(t, err)
+		err = cluster.SetUtilityActualVersion("issuers", &HelmUtilityVersion{Chart: "1.14.4", ValuesPath: "cert_manager_values.yaml"})
+		require.NoError(t, err)
+
+		assert.Error(t, cluster.RemoveUtility(NginxCanonicalName))
+		assert.ErrorIs(t, cluster.RemoveUtility("external-dns"), ErrUtilityNotFound)
+		assert.Error(t, cluster.RemoveUtility("cert-manager"))
+
+		err = cluster.RemoveUtility("issuers")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"issuers"}, utilityNames(cluster.UtilityMetadata.RemovedUtilities))
+		assert.NotNil(t, cluster.ActualUtilityVersion("issuers"))
+
+		cluster.SetUtilityUninstalled("issuers")
+		assert.Empty(t, cluster.UtilityMetadata.RemovedUtilities)
+		assert.Nil(t, cluster.ActualUtilityVersion("issuers"))
+
+		err = cluster.RemoveUtility("cert-manager")
+		require.NoError(t, err)
+		assert.Nil(t, cluster.DesiredUtilityVersion("cert-manager"))
+
+		// Adding the utility again cancels the pending removal.
+		err = cluster.AddUtility(newTestAddClusterUtilityRequest())
+		require.NoError(t, err)
+		assert.Empty(t, cluster.UtilityMetadata.RemovedUtilities)
+	})
+
+	t.Run("unknown utility versions are ignored", func(t *testing.T) {
+		cluster := &Cluster{}
+		cluster.SetUtilityDesiredVersions(map[string]*HelmUtilityVersion{"cert-manager": {Chart: "1.14.4"}})
+		assert.Nil(t, cluster.DesiredUtilityVersion("cert-manager"))
+		assert.Empty(t, cluster.UtilityMetadata.DesiredVersions.Custom)
+	})
+}
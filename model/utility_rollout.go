@@ -0,0 +1,185 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// UtilityRollout upgrades a single utility across a set of clusters by
+// provisioning a limited number of clusters at a time.
+type UtilityRollout struct {
+	ID                    string
+	Utility               string
+	Version               string
+	ValuesPath            string
+	State                 UtilityRolloutState
+	MaxConcurrentClusters int
+	ClusterTimeoutSeconds int64
+	Clusters              UtilityRolloutClusters
+	RequestAt             int64
+	CompleteAt            int64
+	DeleteAt              int64
+	LockAcquiredBy        *string
+	LockAcquiredAt        int64
+}
+
+// UtilityRolloutState represents the state of a utility rollout.
+type UtilityRolloutState string
+
+const (
+	// UtilityRolloutStateRequested is a rollout waiting to be started.
+	UtilityRolloutStateRequested UtilityRolloutState = "utility-rollout-requested"
+	// UtilityRolloutStateInProgress is a rollout with clusters still being
+	// upgraded or waiting to be upgraded.
+	UtilityRolloutStateInProgress UtilityRolloutState = "utility-rollout-in-progress"
+	// UtilityRolloutStatePaused is a rollout that stopped upgrading new
+	// clusters after a cluster failed to upgrade.
+	UtilityRolloutStatePaused UtilityRolloutState = "utility-rollout-paused"
+	// UtilityRolloutStateSucceeded is a rollout where every cluster was
+	// upgraded or skipped.
+	UtilityRolloutStateSucceeded UtilityRolloutState = "utility-rollout-succeeded"
+	// UtilityRolloutStateCancelled is a rollout that was cancelled before
+	// every cluster was upgraded.
+	UtilityRolloutStateCancelled UtilityRolloutState = "utility-rollout-cancelled"
+)
+
+// AllUtilityRolloutStatesPendingWork is a list of all utility rollout states
+// that the supervisor will attempt to transition towards completion on the
+// next "tick". Paused rollouts are supervised to track clusters which were
+// still being upgraded when the rollout was paused.
+var AllUtilityRolloutStatesPendingWork = []UtilityRolloutState{
+	UtilityRolloutStateRequested,
+	UtilityRolloutStateInProgress,
+	UtilityRolloutStatePaused,
+}
+
+// IsFinished returns true if the rollout will not be worked on anymore.
+func (r *UtilityRollout) IsFinished() bool {
+	return r.State == UtilityRolloutStateSucceeded || r.State == UtilityRolloutStateCancelled
+}
+
+// UtilityRolloutClusterState represents the state of a single cluster of a
+// utility rollout.
+type UtilityRolloutClusterState string
+
+const (
+	// UtilityRolloutClusterPending is a cluster that was not upgraded yet.
+	UtilityRolloutClusterPending UtilityRolloutClusterState = "pending"
+	// UtilityRolloutClusterInProgress is a cluster being provisioned with
+	// the new utility version.
+	UtilityRolloutClusterInProgress UtilityRolloutClusterState = "in-progress"
+	// UtilityRolloutClusterSucceeded is a cluster that runs the new utility
+	// version.
+	UtilityRolloutClusterSucceeded UtilityRolloutClusterState = "succeeded"
+	// UtilityRolloutClusterFailed is a cluster that failed to upgrade.
+	UtilityRolloutClusterFailed UtilityRolloutClusterState = "failed"
+	// UtilityRolloutClusterSkipped is a cluster that cannot be upgraded by
+	// the rollout.
+	UtilityRolloutClusterSkipped UtilityRolloutClusterState = "skipped"
+)
+
+// UtilityRolloutCluster describes the progress of a single cluster of a
+// utility rollout.
+type UtilityRolloutCluster struct {
+	ClusterID string
+	State     UtilityRolloutClusterState
+	StartAt   int64  `json:"StartAt,omitempty"`
+	Message   string `json:"Message,omitempty"`
+}
+
+// IsFinished returns true if the cluster will not be worked on anymore.
+func (c *UtilityRolloutCluster) IsFinished() bool {
+	switch c.State {
+	case UtilityRolloutClusterSucceeded,
+		UtilityRolloutClusterFailed,
+		UtilityRolloutClusterSkipped:
+		return true
+	}
+
+	return false
+}
+
+// UtilityRolloutClusters is the list of clusters of a utility rollout.
+type UtilityRolloutClusters []*UtilityRolloutCluster
+
+// Value implements the driver.Valuer interface for database storage
+func (c UtilityRolloutClusters) Value() (driver.Value, error) {
+	if c == nil {
+		return nil, nil
+	}
+	return json.Marshal(c)
+}
+
+// Scan implements the sql.Scanner interface for database retrieval
+func (c *UtilityRolloutClusters) Scan(src interface{}) error {
+	if src == nil {
+		return nil
+	}
+
+	source, ok := src.([]byte)
+	if !ok {
+		return errors.New("could not assert type of UtilityRolloutClusters")
+	}
+
+	var clusters UtilityRolloutClusters
+	err := json.Unmarshal(source, &clusters)
+	if err != nil {
+		return err
+	}
+	*c = clusters
+
+	return nil
+}
+
+// Count returns the number of clusters in the given state.
+func (c UtilityRolloutClusters) Count(state UtilityRolloutClusterState) int {
+	var count int
+	for _, cluster := range c {
+		if cluster.State == state {
+			count++
+		}
+	}
+
+	return count
+}
+
+// UtilityRolloutFilter describes the parameters used to constrain a set of
+// utility rollouts.
+type UtilityRolloutFilter struct {
+	Paging
+	Utility string
+	States  []UtilityRolloutState
+}
+
+// UtilityRolloutFromReader decodes a json-encoded utility rollout from the
+// given io.Reader.
+func UtilityRolloutFromReader(reader io.Reader) (*UtilityRollout, error) {
+	rollout := UtilityRollout{}
+	decoder := json.NewDecoder(reader)
+	err := decoder.Decode(&rollout)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return &rollout, nil
+}
+
+// UtilityRolloutsFromReader decodes a json-encoded list of utility rollouts
+// from the given io.Reader.
+func UtilityRolloutsFromReader(reader io.Reader) ([]*UtilityRollout, error) {
+	rollouts := []*UtilityRollout{}
+	decoder := json.NewDecoder(reader)
+	err := decoder.Decode(&rollouts)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return rollouts, nil
+}
@@ -0,0 +1,125 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+	"net/url"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultUtilityRolloutMaxConcurrentClusters is the default number of
+	// clusters a utility rollout upgrades at the same time.
+	DefaultUtilityRolloutMaxConcurrentClusters = 1
+	// DefaultUtilityRolloutClusterTimeoutSeconds is the default time a
+	// cluster has to finish provisioning the new utility version.
+	DefaultUtilityRolloutClusterTimeoutSeconds = 60 * 60
+)
+
+// UtilityRolloutRequest specifies the parameters of a new utility rollout.
+// The rollout targets the given clusters, or every cluster with all of the
+// given annotations.
+type UtilityRolloutRequest struct {
+	Utility               string   `json:"utility"`
+	Version               string   `json:"version"`
+	ValuesPath            string   `json:"valuesPath,omitempty"`
+	ClusterIDs            []string `json:"clusterIDs,omitempty"`
+	Annotations           []string `json:"annotations,omitempty"`
+	MaxConcurrentClusters int      `json:"maxConcurrentClusters,omitempty"`
+	ClusterTimeoutSeconds int64    `json:"clusterTimeoutSeconds,omitempty"`
+}
+
+// SetDefaults sets the default values for a utility rollout request.
+func (request *UtilityRolloutRequest) SetDefaults() {
+	if request.MaxConcurrentClusters == 0 {
+		request.MaxConcurrentClusters = DefaultUtilityRolloutMaxConcurrentClusters
+	}
+	if request.ClusterTimeoutSeconds == 0 {
+		request.ClusterTimeoutSeconds = DefaultUtilityRolloutClusterTimeoutSeconds
+	}
+}
+
+// Validate validates the values of a utility rollout request.
+func (request *UtilityRolloutRequest) Validate() error {
+	if request.Utility == "" {
+		return errors.New("utility must be set")
+	}
+	if request.Version == "" {
+		return errors.New("version must be set")
+	}
+	if request.Version == UnmanagedUtilityVersion {
+		return errors.New("utilities cannot be rolled out as unmanaged")
+	}
+	if len(request.ClusterIDs) == 0 && len(request.Annotations) == 0 {
+		return errors.New("clusters must be selected by ID or annotations")
+	}
+	if len(request.ClusterIDs) > 0 && len(request.Annotations) > 0 {
+		return errors.New("clusters cannot be selected by both ID and annotations")
+	}
+	if request.MaxConcurrentClusters < 1 {
+		return errors.New("max concurrent clusters must be 1 or greater")
+	}
+	if request.ClusterTimeoutSeconds < 1 {
+		return errors.New("cluster timeout must be 1 second or greater")
+	}
+
+	return nil
+}
+
+// NewUtilityRolloutRequestFromReader will create a UtilityRolloutRequest from
+// an io.Reader with JSON data.
+func NewUtilityRolloutRequestFromReader(reader io.Reader) (*UtilityRolloutRequest, error) {
+	var rolloutRequest UtilityRolloutRequest
+	err := json.NewDecoder(reader).Decode(&rolloutRequest)
+	if err != nil && err != io.EOF {
+		return nil, errors.Wrap(err, "failed to decode utility rollout request")
+	}
+
+	rolloutRequest.SetDefaults()
+	err = rolloutRequest.Validate()
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid utility rollout request")
+	}
+
+	return &rolloutRequest, nil
+}
+
+// GetUtilityRolloutsRequest describes the parameters to request a list of
+// utility rollouts.
+type GetUtilityRolloutsRequest struct {
+	Paging
+	Utility string
+	State   string
+}
+
+// ApplyToURL modifies the given url to include query string parameters for the request.
+func (request *GetUtilityRolloutsRequest) ApplyToURL(u *url.URL) {
+	q := u.Query()
+	q.Add("utility", request.Utility)
+	q.Add("state", request.State)
+	request.Paging.AddToQuery(q)
+
+	u.RawQuery = q.Encode()
+}
+
+// GetUtilityDriftRequest describes the parameters to request the utility
+// drift report of the clusters.
+type GetUtilityDriftRequest struct {
+	Utility     string
+	OnlyDrifted bool
+}
+
+// ApplyToURL modifies the given url to include query string parameters for the request.
+func (request *GetUtilityDriftRequest) ApplyToURL(u *url.URL) {
+	q := u.Query()
+	q.Add("utility", request.Utility)
+	q.Add("only_drifted", strconv.FormatBool(request.OnlyDrifted))
+
+	u.RawQuery = q.Encode()
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewUtilityRolloutRequestFromReader(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		request, err := NewUtilityRolloutRequestFromReader(bytes.NewReader([]byte("")))
+		require.Error(t, err)
+		require.Nil(t, request)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		request, err := NewUtilityRolloutRequestFromReader(bytes.NewReader([]byte("{test")))
+		require.Error(t, err)
+		require.Nil(t, request)
+	})
+
+	t.Run("defaults", func(t *testing.T) {
+		request, err := NewUtilityRolloutRequestFromReader(bytes.NewReader([]byte(
+			`{"utility":"nginx", "version":"4.1.0", "annotations":["multi-tenant"]}`,
+		)))
+		require.NoError(t, err)
+		require.Equal(t, &UtilityRolloutRequest{
+			Utility:               NginxCanonicalName,
+			Version:               "4.1.0",
+			Annotations:           []string{"multi-tenant"},
+			MaxConcurrentClusters: DefaultUtilityRolloutMaxConcurrentClusters,
+			ClusterTimeoutSeconds: DefaultUtilityRolloutClusterTimeoutSeconds,
+		}, request)
+	})
+
+	t.Run("no clusters selected", func(t *testing.T) {
+		request, err := NewUtilityRolloutRequestFromReader(bytes.NewReader([]byte(
+			`{"utility":"nginx", "version":"4.1.0"}`,
+		)))
+		require.Error(t, err)
+		require.Nil(t, request)
+	})
+
+	t.Run("clusters selected by ID and annotations", func(t *testing.T) {
+		request, err := NewUtilityRolloutRequestFromReader(bytes.NewReader([]byte(
+			`{"utility":"nginx", "version":"4.1.0", "clusterIDs":["cluster1"], "annotations":["multi-tenant"]}`,
+		)))
+		require.Error(t, err)
+		require.Nil(t, request)
+	})
+
+	t.Run("unmanaged version", func(t *testing.T) {
+		request, err := NewUtilityRolloutRequestFromReader(bytes.NewReader([]byte(
+			`{"utility":"nginx", "version":"unmanaged", "clusterIDs":["cluster1"]}`,
+		)))
+		require.Error(t, err)
+		require.Nil(t, request)
+	})
+
+	t.Run("negative max concurrent clusters", func(t *testing.T) {
+		request, err := NewUtilityRolloutRequestFromReader(bytes.NewReader([]byte(
+			`{"utility":"nginx", "version":"4.1.0", "clusterIDs":["cluster1"], "maxConcurrentClusters":-1}`,
+		)))
+		require.Error(t, err)
+		require.Nil(t, request)
+	})
+}
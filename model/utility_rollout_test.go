@@ -0,0 +1,79 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUtilityRolloutClusters(t *testing.T) {
+	clusters := UtilityRolloutClusters{
+		{ClusterID: "a", State: UtilityRolloutClusterSucceeded, StartAt: 100},
+		{ClusterID: "b", State: UtilityRolloutClusterInProgress, StartAt: 200},
+		{ClusterID: "c", State: UtilityRolloutClusterFailed, Message: "provisioning-failed"},
+		{ClusterID: "d", State: UtilityRolloutClusterPending},
+	}
+
+	assert.Equal(t, 1, clusters.Count(UtilityRolloutClusterInProgress))
+	assert.Equal(t, 0, clusters.Count(UtilityRolloutClusterSkipped))
+	assert.True(t, clusters[0].IsFinished())
+	assert.False(t, clusters[1].IsFinished())
+	assert.True(t, clusters[2].IsFinished())
+	assert.False(t, clusters[3].IsFinished())
+
+	t.Run("database serialization", func(t *testing.T) {
+		value, err := clusters.Value()
+		require.NoError(t, err)
+
+		var scanned UtilityRolloutClusters
+		err = scanned.Scan(value)
+		require.NoError(t, err)
+		assert.Equal(t, clusters, scanned)
+
+		err = scanned.Scan("invalid")
+		assert.Error(t, err)
+
+		value, err = UtilityRolloutClusters(nil).Value()
+		require.NoError(t, err)
+		assert.Nil(t, value)
+	})
+}
+
+func TestUtilityRolloutIsFinished(t *testing.T) {
+	for state, finished := range map[UtilityRolloutState]bool{
+		UtilityRolloutStateRequested:  false,
+		UtilityRolloutStateInProgress: false,
+		UtilityRolloutStatePaused:     false,
+		UtilityRolloutStateSucceeded:  true,
+		UtilityRolloutStateCancelled:  true,
+	} {
+		rollout := &UtilityRollout{State: state}
+		assert.Equal(t, finished, rollout.IsFinished(), state)
+	}
+}
+
+func TestUtilityRolloutsFromReader(t *testing.T) {
+	rollouts := []*UtilityRollout{
+		{ID: "rollout1", Utility: NginxCanonicalName, Version: "4.1.0", State: UtilityRolloutStatePaused, Clusters: UtilityRolloutClusters{{ClusterID: "a", State: UtilityRolloutClusterFailed}}},
+	}
+	data, err := json.Marshal(rollouts)
+	require.NoError(t, err)
+
+	result, err := UtilityRolloutsFromReader(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, rollouts, result)
+
+	rollout, err := UtilityRolloutFromReader(bytes.NewReader([]byte("")))
+	require.NoError(t, err)
+	assert.Equal(t, &UtilityRollout{}, rollout)
+
+	_, err = UtilityRolloutFromReader(bytes.NewReader([]byte("{test")))
+	assert.Error(t, err)
+}
@@ -20,12 +20,20 @@ const (
 	// TypeClusterInstallation is the string value that represents a cluster
 	// installation.
 	TypeClusterInstallation ResourceType = "cluster_installation"
+	// TypeGroup is the string value that represents a group.
+	TypeGroup ResourceType = "group"
 	// TypeInstallationBackup is the string value that represents an installation backup.
 	TypeInstallationBackup ResourceType = "installation_backup"
 	// TypeInstallationDBRestoration is the string value that represents an installation db restoration operation.
 	TypeInstallationDBRestoration ResourceType = "installation_db_restoration_operation"
 	// TypeInstallationDBMigration is the string value that represents an installation db migration operation.
 	TypeInstallationDBMigration ResourceType = "installation_db_migration_operation"
+	// TypeInstallationFilestoreMigration is the string value that represents an installation filestore migration operation.
+	TypeInstallationFilestoreMigration ResourceType = "installation_filestore_migration_operation"
+	// TypeMultitenantDatabaseRebalance is the string value that represents a multitenant database rebalance.
+	TypeMultitenantDatabaseRebalance ResourceType = "multitenant_database_rebalance"
+	// TypeUtilityRollout is the string value that represents a utility rollout.
+	TypeUtilityRollout ResourceType = "utility_rollout"
 )
 
 // String converts ResourceType to string.